@@ -0,0 +1,916 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: analytics/v1/analytics.proto
+
+package analyticsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Segment ABC-сегмент товара
+type Segment int32
+
+const (
+	Segment_SEGMENT_UNSPECIFIED Segment = 0
+	Segment_SEGMENT_A           Segment = 1
+	Segment_SEGMENT_B           Segment = 2
+	Segment_SEGMENT_C           Segment = 3
+)
+
+// Enum value maps for Segment.
+var (
+	Segment_name = map[int32]string{
+		0: "SEGMENT_UNSPECIFIED",
+		1: "SEGMENT_A",
+		2: "SEGMENT_B",
+		3: "SEGMENT_C",
+	}
+	Segment_value = map[string]int32{
+		"SEGMENT_UNSPECIFIED": 0,
+		"SEGMENT_A":           1,
+		"SEGMENT_B":           2,
+		"SEGMENT_C":           3,
+	}
+)
+
+func (x Segment) Enum() *Segment {
+	p := new(Segment)
+	*p = x
+	return p
+}
+
+func (x Segment) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Segment) Descriptor() protoreflect.EnumDescriptor {
+	return file_analytics_v1_analytics_proto_enumTypes[0].Descriptor()
+}
+
+func (Segment) Type() protoreflect.EnumType {
+	return &file_analytics_v1_analytics_proto_enumTypes[0]
+}
+
+func (x Segment) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Segment.Descriptor instead.
+func (Segment) EnumDescriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{0}
+}
+
+type GetProductRecommendationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID товаров текущей корзины
+	BasketProductIds []string `protobuf:"bytes,1,rep,name=basket_product_ids,json=basketProductIds,proto3" json:"basket_product_ids,omitempty"`
+	// Максимальное количество рекомендаций; 0 — значение сервера по умолчанию
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductRecommendationsRequest) Reset() {
+	*x = GetProductRecommendationsRequest{}
+	mi := &file_analytics_v1_analytics_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRecommendationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRecommendationsRequest) ProtoMessage() {}
+
+func (x *GetProductRecommendationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_v1_analytics_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRecommendationsRequest.ProtoReflect.Descriptor instead.
+func (*GetProductRecommendationsRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetProductRecommendationsRequest) GetBasketProductIds() []string {
+	if x != nil {
+		return x.BasketProductIds
+	}
+	return nil
+}
+
+func (x *GetProductRecommendationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetProductRecommendationsResponse struct {
+	state           protoimpl.MessageState   `protogen:"open.v1"`
+	Recommendations []*ProductRecommendation `protobuf:"bytes,1,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetProductRecommendationsResponse) Reset() {
+	*x = GetProductRecommendationsResponse{}
+	mi := &file_analytics_v1_analytics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRecommendationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRecommendationsResponse) ProtoMessage() {}
+
+func (x *GetProductRecommendationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_v1_analytics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRecommendationsResponse.ProtoReflect.Descriptor instead.
+func (*GetProductRecommendationsResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetProductRecommendationsResponse) GetRecommendations() []*ProductRecommendation {
+	if x != nil {
+		return x.Recommendations
+	}
+	return nil
+}
+
+// ProductRecommendation рекомендованный к корзине товар
+type ProductRecommendation struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Category  string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Price     float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	// Уверенность правила, по которому сделана рекомендация
+	Score         float64 `protobuf:"fixed64,5,opt,name=score,proto3" json:"score,omitempty"`
+	Lift          float64 `protobuf:"fixed64,6,opt,name=lift,proto3" json:"lift,omitempty"`
+	Support       float64 `protobuf:"fixed64,7,opt,name=support,proto3" json:"support,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductRecommendation) Reset() {
+	*x = ProductRecommendation{}
+	mi := &file_analytics_v1_analytics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductRecommendation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductRecommendation) ProtoMessage() {}
+
+func (x *ProductRecommendation) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_v1_analytics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductRecommendation.ProtoReflect.Descriptor instead.
+func (*ProductRecommendation) Descriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProductRecommendation) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductRecommendation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductRecommendation) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ProductRecommendation) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ProductRecommendation) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ProductRecommendation) GetLift() float64 {
+	if x != nil {
+		return x.Lift
+	}
+	return 0
+}
+
+func (x *ProductRecommendation) GetSupport() float64 {
+	if x != nil {
+		return x.Support
+	}
+	return 0
+}
+
+type GetProductSegmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductSegmentRequest) Reset() {
+	*x = GetProductSegmentRequest{}
+	mi := &file_analytics_v1_analytics_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductSegmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductSegmentRequest) ProtoMessage() {}
+
+func (x *GetProductSegmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_v1_analytics_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductSegmentRequest.ProtoReflect.Descriptor instead.
+func (*GetProductSegmentRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetProductSegmentRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+// ProductSegment ABC-сегмент товара по результатам последнего анализа
+type ProductSegment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Segment       Segment                `protobuf:"varint,2,opt,name=segment,proto3,enum=analytics.v1.Segment" json:"segment,omitempty"`
+	Score         float64                `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	AnalysisDate  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=analysis_date,json=analysisDate,proto3" json:"analysis_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSegment) Reset() {
+	*x = ProductSegment{}
+	mi := &file_analytics_v1_analytics_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSegment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSegment) ProtoMessage() {}
+
+func (x *ProductSegment) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_v1_analytics_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSegment.ProtoReflect.Descriptor instead.
+func (*ProductSegment) Descriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ProductSegment) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductSegment) GetSegment() Segment {
+	if x != nil {
+		return x.Segment
+	}
+	return Segment_SEGMENT_UNSPECIFIED
+}
+
+func (x *ProductSegment) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ProductSegment) GetAnalysisDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AnalysisDate
+	}
+	return nil
+}
+
+// ProductSegmentResult ответ на один запрос пакетного поиска сегментов
+type ProductSegmentResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// false, если товар не сегментирован; segment тогда не заполнен
+	Found         bool            `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Segment       *ProductSegment `protobuf:"bytes,3,opt,name=segment,proto3" json:"segment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSegmentResult) Reset() {
+	*x = ProductSegmentResult{}
+	mi := &file_analytics_v1_analytics_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSegmentResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSegmentResult) ProtoMessage() {}
+
+func (x *ProductSegmentResult) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_v1_analytics_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSegmentResult.ProtoReflect.Descriptor instead.
+func (*ProductSegmentResult) Descriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProductSegmentResult) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductSegmentResult) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *ProductSegmentResult) GetSegment() *ProductSegment {
+	if x != nil {
+		return x.Segment
+	}
+	return nil
+}
+
+type GetDiscountRecommendationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDiscountRecommendationRequest) Reset() {
+	*x = GetDiscountRecommendationRequest{}
+	mi := &file_analytics_v1_analytics_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDiscountRecommendationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDiscountRecommendationRequest) ProtoMessage() {}
+
+func (x *GetDiscountRecommendationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_v1_analytics_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDiscountRecommendationRequest.ProtoReflect.Descriptor instead.
+func (*GetDiscountRecommendationRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetDiscountRecommendationRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+// DiscountRecommendation рекомендация по оптимальной скидке
+type DiscountRecommendation struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Category  string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	// Пустое значение — рекомендация на весь день
+	Daypart string `protobuf:"bytes,3,opt,name=daypart,proto3" json:"daypart,omitempty"`
+	// Скидка в процентах
+	OptimalDiscount       float64                `protobuf:"fixed64,4,opt,name=optimal_discount,json=optimalDiscount,proto3" json:"optimal_discount,omitempty"`
+	LiftFactor            float64                `protobuf:"fixed64,5,opt,name=lift_factor,json=liftFactor,proto3" json:"lift_factor,omitempty"`
+	AbcSegment            Segment                `protobuf:"varint,6,opt,name=abc_segment,json=abcSegment,proto3,enum=analytics.v1.Segment" json:"abc_segment,omitempty"`
+	Confidence            float64                `protobuf:"fixed64,7,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	LifecycleStage        string                 `protobuf:"bytes,8,opt,name=lifecycle_stage,json=lifecycleStage,proto3" json:"lifecycle_stage,omitempty"`
+	AdjustmentReason      string                 `protobuf:"bytes,9,opt,name=adjustment_reason,json=adjustmentReason,proto3" json:"adjustment_reason,omitempty"`
+	NetIncrementalRevenue float64                `protobuf:"fixed64,10,opt,name=net_incremental_revenue,json=netIncrementalRevenue,proto3" json:"net_incremental_revenue,omitempty"`
+	AnalysisDate          *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=analysis_date,json=analysisDate,proto3" json:"analysis_date,omitempty"`
+	PeriodStart           *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=period_start,json=periodStart,proto3" json:"period_start,omitempty"`
+	PeriodEnd             *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=period_end,json=periodEnd,proto3" json:"period_end,omitempty"`
+	RunId                 string                 `protobuf:"bytes,14,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *DiscountRecommendation) Reset() {
+	*x = DiscountRecommendation{}
+	mi := &file_analytics_v1_analytics_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscountRecommendation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscountRecommendation) ProtoMessage() {}
+
+func (x *DiscountRecommendation) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_v1_analytics_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscountRecommendation.ProtoReflect.Descriptor instead.
+func (*DiscountRecommendation) Descriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DiscountRecommendation) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *DiscountRecommendation) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *DiscountRecommendation) GetDaypart() string {
+	if x != nil {
+		return x.Daypart
+	}
+	return ""
+}
+
+func (x *DiscountRecommendation) GetOptimalDiscount() float64 {
+	if x != nil {
+		return x.OptimalDiscount
+	}
+	return 0
+}
+
+func (x *DiscountRecommendation) GetLiftFactor() float64 {
+	if x != nil {
+		return x.LiftFactor
+	}
+	return 0
+}
+
+func (x *DiscountRecommendation) GetAbcSegment() Segment {
+	if x != nil {
+		return x.AbcSegment
+	}
+	return Segment_SEGMENT_UNSPECIFIED
+}
+
+func (x *DiscountRecommendation) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *DiscountRecommendation) GetLifecycleStage() string {
+	if x != nil {
+		return x.LifecycleStage
+	}
+	return ""
+}
+
+func (x *DiscountRecommendation) GetAdjustmentReason() string {
+	if x != nil {
+		return x.AdjustmentReason
+	}
+	return ""
+}
+
+func (x *DiscountRecommendation) GetNetIncrementalRevenue() float64 {
+	if x != nil {
+		return x.NetIncrementalRevenue
+	}
+	return 0
+}
+
+func (x *DiscountRecommendation) GetAnalysisDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AnalysisDate
+	}
+	return nil
+}
+
+func (x *DiscountRecommendation) GetPeriodStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PeriodStart
+	}
+	return nil
+}
+
+func (x *DiscountRecommendation) GetPeriodEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PeriodEnd
+	}
+	return nil
+}
+
+func (x *DiscountRecommendation) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+// DiscountRecommendationResult ответ на один запрос пакетного поиска рекомендаций
+type DiscountRecommendationResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// false, если рекомендации нет; recommendation тогда не заполнена
+	Found          bool                    `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Recommendation *DiscountRecommendation `protobuf:"bytes,3,opt,name=recommendation,proto3" json:"recommendation,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DiscountRecommendationResult) Reset() {
+	*x = DiscountRecommendationResult{}
+	mi := &file_analytics_v1_analytics_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscountRecommendationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscountRecommendationResult) ProtoMessage() {}
+
+func (x *DiscountRecommendationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_v1_analytics_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscountRecommendationResult.ProtoReflect.Descriptor instead.
+func (*DiscountRecommendationResult) Descriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DiscountRecommendationResult) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *DiscountRecommendationResult) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *DiscountRecommendationResult) GetRecommendation() *DiscountRecommendation {
+	if x != nil {
+		return x.Recommendation
+	}
+	return nil
+}
+
+type ListDiscountRecommendationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Категория товаров; пустое значение — все категории
+	Category string `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	// Максимальное количество рекомендаций без фильтра по категории; 0 — значение сервера по умолчанию
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDiscountRecommendationsRequest) Reset() {
+	*x = ListDiscountRecommendationsRequest{}
+	mi := &file_analytics_v1_analytics_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDiscountRecommendationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDiscountRecommendationsRequest) ProtoMessage() {}
+
+func (x *ListDiscountRecommendationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_v1_analytics_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDiscountRecommendationsRequest.ProtoReflect.Descriptor instead.
+func (*ListDiscountRecommendationsRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_v1_analytics_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListDiscountRecommendationsRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ListDiscountRecommendationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+var File_analytics_v1_analytics_proto protoreflect.FileDescriptor
+
+const file_analytics_v1_analytics_proto_rawDesc = "" +
+	"\n" +
+	"\x1canalytics/v1/analytics.proto\x12\fanalytics.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"f\n" +
+	" GetProductRecommendationsRequest\x12,\n" +
+	"\x12basket_product_ids\x18\x01 \x03(\tR\x10basketProductIds\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"r\n" +
+	"!GetProductRecommendationsResponse\x12M\n" +
+	"\x0frecommendations\x18\x01 \x03(\v2#.analytics.v1.ProductRecommendationR\x0frecommendations\"\xc0\x01\n" +
+	"\x15ProductRecommendation\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x01R\x05score\x12\x12\n" +
+	"\x04lift\x18\x06 \x01(\x01R\x04lift\x12\x18\n" +
+	"\asupport\x18\a \x01(\x01R\asupport\"9\n" +
+	"\x18GetProductSegmentRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\xb7\x01\n" +
+	"\x0eProductSegment\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12/\n" +
+	"\asegment\x18\x02 \x01(\x0e2\x15.analytics.v1.SegmentR\asegment\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score\x12?\n" +
+	"\ranalysis_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fanalysisDate\"\x83\x01\n" +
+	"\x14ProductSegmentResult\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x126\n" +
+	"\asegment\x18\x03 \x01(\v2\x1c.analytics.v1.ProductSegmentR\asegment\"A\n" +
+	" GetDiscountRecommendationRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\xf1\x04\n" +
+	"\x16DiscountRecommendation\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12\x18\n" +
+	"\adaypart\x18\x03 \x01(\tR\adaypart\x12)\n" +
+	"\x10optimal_discount\x18\x04 \x01(\x01R\x0foptimalDiscount\x12\x1f\n" +
+	"\vlift_factor\x18\x05 \x01(\x01R\n" +
+	"liftFactor\x126\n" +
+	"\vabc_segment\x18\x06 \x01(\x0e2\x15.analytics.v1.SegmentR\n" +
+	"abcSegment\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\a \x01(\x01R\n" +
+	"confidence\x12'\n" +
+	"\x0flifecycle_stage\x18\b \x01(\tR\x0elifecycleStage\x12+\n" +
+	"\x11adjustment_reason\x18\t \x01(\tR\x10adjustmentReason\x126\n" +
+	"\x17net_incremental_revenue\x18\n" +
+	" \x01(\x01R\x15netIncrementalRevenue\x12?\n" +
+	"\ranalysis_date\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\fanalysisDate\x12=\n" +
+	"\fperiod_start\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vperiodStart\x129\n" +
+	"\n" +
+	"period_end\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tperiodEnd\x12\x15\n" +
+	"\x06run_id\x18\x0e \x01(\tR\x05runId\"\xa1\x01\n" +
+	"\x1cDiscountRecommendationResult\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x12L\n" +
+	"\x0erecommendation\x18\x03 \x01(\v2$.analytics.v1.DiscountRecommendationR\x0erecommendation\"V\n" +
+	"\"ListDiscountRecommendationsRequest\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit*O\n" +
+	"\aSegment\x12\x17\n" +
+	"\x13SEGMENT_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tSEGMENT_A\x10\x01\x12\r\n" +
+	"\tSEGMENT_B\x10\x02\x12\r\n" +
+	"\tSEGMENT_C\x10\x032\xc6\x05\n" +
+	"\x10AnalyticsService\x12|\n" +
+	"\x19GetProductRecommendations\x12..analytics.v1.GetProductRecommendationsRequest\x1a/.analytics.v1.GetProductRecommendationsResponse\x12Y\n" +
+	"\x11GetProductSegment\x12&.analytics.v1.GetProductSegmentRequest\x1a\x1c.analytics.v1.ProductSegment\x12i\n" +
+	"\x17BatchGetProductSegments\x12&.analytics.v1.GetProductSegmentRequest\x1a\".analytics.v1.ProductSegmentResult(\x010\x01\x12q\n" +
+	"\x19GetDiscountRecommendation\x12..analytics.v1.GetDiscountRecommendationRequest\x1a$.analytics.v1.DiscountRecommendation\x12\x81\x01\n" +
+	"\x1fBatchGetDiscountRecommendations\x12..analytics.v1.GetDiscountRecommendationRequest\x1a*.analytics.v1.DiscountRecommendationResult(\x010\x01\x12w\n" +
+	"\x1bListDiscountRecommendations\x120.analytics.v1.ListDiscountRecommendationsRequest\x1a$.analytics.v1.DiscountRecommendation0\x01BI\n" +
+	"\x15com.kava.analytics.v1P\x01Z.analitics-service/api/analytics/v1;analyticsv1b\x06proto3"
+
+var (
+	file_analytics_v1_analytics_proto_rawDescOnce sync.Once
+	file_analytics_v1_analytics_proto_rawDescData []byte
+)
+
+func file_analytics_v1_analytics_proto_rawDescGZIP() []byte {
+	file_analytics_v1_analytics_proto_rawDescOnce.Do(func() {
+		file_analytics_v1_analytics_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_analytics_v1_analytics_proto_rawDesc), len(file_analytics_v1_analytics_proto_rawDesc)))
+	})
+	return file_analytics_v1_analytics_proto_rawDescData
+}
+
+var file_analytics_v1_analytics_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_analytics_v1_analytics_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_analytics_v1_analytics_proto_goTypes = []any{
+	(Segment)(0),                               // 0: analytics.v1.Segment
+	(*GetProductRecommendationsRequest)(nil),   // 1: analytics.v1.GetProductRecommendationsRequest
+	(*GetProductRecommendationsResponse)(nil),  // 2: analytics.v1.GetProductRecommendationsResponse
+	(*ProductRecommendation)(nil),              // 3: analytics.v1.ProductRecommendation
+	(*GetProductSegmentRequest)(nil),           // 4: analytics.v1.GetProductSegmentRequest
+	(*ProductSegment)(nil),                     // 5: analytics.v1.ProductSegment
+	(*ProductSegmentResult)(nil),               // 6: analytics.v1.ProductSegmentResult
+	(*GetDiscountRecommendationRequest)(nil),   // 7: analytics.v1.GetDiscountRecommendationRequest
+	(*DiscountRecommendation)(nil),             // 8: analytics.v1.DiscountRecommendation
+	(*DiscountRecommendationResult)(nil),       // 9: analytics.v1.DiscountRecommendationResult
+	(*ListDiscountRecommendationsRequest)(nil), // 10: analytics.v1.ListDiscountRecommendationsRequest
+	(*timestamppb.Timestamp)(nil),              // 11: google.protobuf.Timestamp
+}
+var file_analytics_v1_analytics_proto_depIdxs = []int32{
+	3,  // 0: analytics.v1.GetProductRecommendationsResponse.recommendations:type_name -> analytics.v1.ProductRecommendation
+	0,  // 1: analytics.v1.ProductSegment.segment:type_name -> analytics.v1.Segment
+	11, // 2: analytics.v1.ProductSegment.analysis_date:type_name -> google.protobuf.Timestamp
+	5,  // 3: analytics.v1.ProductSegmentResult.segment:type_name -> analytics.v1.ProductSegment
+	0,  // 4: analytics.v1.DiscountRecommendation.abc_segment:type_name -> analytics.v1.Segment
+	11, // 5: analytics.v1.DiscountRecommendation.analysis_date:type_name -> google.protobuf.Timestamp
+	11, // 6: analytics.v1.DiscountRecommendation.period_start:type_name -> google.protobuf.Timestamp
+	11, // 7: analytics.v1.DiscountRecommendation.period_end:type_name -> google.protobuf.Timestamp
+	8,  // 8: analytics.v1.DiscountRecommendationResult.recommendation:type_name -> analytics.v1.DiscountRecommendation
+	1,  // 9: analytics.v1.AnalyticsService.GetProductRecommendations:input_type -> analytics.v1.GetProductRecommendationsRequest
+	4,  // 10: analytics.v1.AnalyticsService.GetProductSegment:input_type -> analytics.v1.GetProductSegmentRequest
+	4,  // 11: analytics.v1.AnalyticsService.BatchGetProductSegments:input_type -> analytics.v1.GetProductSegmentRequest
+	7,  // 12: analytics.v1.AnalyticsService.GetDiscountRecommendation:input_type -> analytics.v1.GetDiscountRecommendationRequest
+	7,  // 13: analytics.v1.AnalyticsService.BatchGetDiscountRecommendations:input_type -> analytics.v1.GetDiscountRecommendationRequest
+	10, // 14: analytics.v1.AnalyticsService.ListDiscountRecommendations:input_type -> analytics.v1.ListDiscountRecommendationsRequest
+	2,  // 15: analytics.v1.AnalyticsService.GetProductRecommendations:output_type -> analytics.v1.GetProductRecommendationsResponse
+	5,  // 16: analytics.v1.AnalyticsService.GetProductSegment:output_type -> analytics.v1.ProductSegment
+	6,  // 17: analytics.v1.AnalyticsService.BatchGetProductSegments:output_type -> analytics.v1.ProductSegmentResult
+	8,  // 18: analytics.v1.AnalyticsService.GetDiscountRecommendation:output_type -> analytics.v1.DiscountRecommendation
+	9,  // 19: analytics.v1.AnalyticsService.BatchGetDiscountRecommendations:output_type -> analytics.v1.DiscountRecommendationResult
+	8,  // 20: analytics.v1.AnalyticsService.ListDiscountRecommendations:output_type -> analytics.v1.DiscountRecommendation
+	15, // [15:21] is the sub-list for method output_type
+	9,  // [9:15] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_analytics_v1_analytics_proto_init() }
+func file_analytics_v1_analytics_proto_init() {
+	if File_analytics_v1_analytics_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_analytics_v1_analytics_proto_rawDesc), len(file_analytics_v1_analytics_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_analytics_v1_analytics_proto_goTypes,
+		DependencyIndexes: file_analytics_v1_analytics_proto_depIdxs,
+		EnumInfos:         file_analytics_v1_analytics_proto_enumTypes,
+		MessageInfos:      file_analytics_v1_analytics_proto_msgTypes,
+	}.Build()
+	File_analytics_v1_analytics_proto = out.File
+	file_analytics_v1_analytics_proto_goTypes = nil
+	file_analytics_v1_analytics_proto_depIdxs = nil
+}
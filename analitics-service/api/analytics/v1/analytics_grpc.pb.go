@@ -0,0 +1,329 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: analytics/v1/analytics.proto
+
+package analyticsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AnalyticsService_GetProductRecommendations_FullMethodName       = "/analytics.v1.AnalyticsService/GetProductRecommendations"
+	AnalyticsService_GetProductSegment_FullMethodName               = "/analytics.v1.AnalyticsService/GetProductSegment"
+	AnalyticsService_BatchGetProductSegments_FullMethodName         = "/analytics.v1.AnalyticsService/BatchGetProductSegments"
+	AnalyticsService_GetDiscountRecommendation_FullMethodName       = "/analytics.v1.AnalyticsService/GetDiscountRecommendation"
+	AnalyticsService_BatchGetDiscountRecommendations_FullMethodName = "/analytics.v1.AnalyticsService/BatchGetDiscountRecommendations"
+	AnalyticsService_ListDiscountRecommendations_FullMethodName     = "/analytics.v1.AnalyticsService/ListDiscountRecommendations"
+)
+
+// AnalyticsServiceClient is the client API for AnalyticsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// # AnalyticsService отдает результаты аналитики сервису меню с низкой задержкой
+//
+// Вызовы без дедлайна клиента ограничиваются таймаутом сервера; более длинные дедлайны
+// клиента сокращаются до него. Состояние сервиса доступно через grpc.health.v1.Health
+type AnalyticsServiceClient interface {
+	// GetProductRecommendations возвращает товары, которые стоит предложить к текущей корзине,
+	// по сохраненным ассоциативным правилам
+	GetProductRecommendations(ctx context.Context, in *GetProductRecommendationsRequest, opts ...grpc.CallOption) (*GetProductRecommendationsResponse, error)
+	// GetProductSegment возвращает ABC-сегмент товара; NOT_FOUND, если товар не сегментирован
+	GetProductSegment(ctx context.Context, in *GetProductSegmentRequest, opts ...grpc.CallOption) (*ProductSegment, error)
+	// BatchGetProductSegments возвращает сегменты для потока товаров, по ответу на каждый запрос в том же порядке
+	BatchGetProductSegments(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GetProductSegmentRequest, ProductSegmentResult], error)
+	// GetDiscountRecommendation возвращает текущую рекомендацию по скидке для товара;
+	// NOT_FOUND, если рекомендации нет
+	GetDiscountRecommendation(ctx context.Context, in *GetDiscountRecommendationRequest, opts ...grpc.CallOption) (*DiscountRecommendation, error)
+	// BatchGetDiscountRecommendations возвращает рекомендации для потока товаров, по ответу на каждый запрос в том же порядке
+	BatchGetDiscountRecommendations(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GetDiscountRecommendationRequest, DiscountRecommendationResult], error)
+	// ListDiscountRecommendations передает потоком последние рекомендации по скидкам, при необходимости по категории
+	ListDiscountRecommendations(ctx context.Context, in *ListDiscountRecommendationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DiscountRecommendation], error)
+}
+
+type analyticsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAnalyticsServiceClient(cc grpc.ClientConnInterface) AnalyticsServiceClient {
+	return &analyticsServiceClient{cc}
+}
+
+func (c *analyticsServiceClient) GetProductRecommendations(ctx context.Context, in *GetProductRecommendationsRequest, opts ...grpc.CallOption) (*GetProductRecommendationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProductRecommendationsResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetProductRecommendations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetProductSegment(ctx context.Context, in *GetProductSegmentRequest, opts ...grpc.CallOption) (*ProductSegment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductSegment)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetProductSegment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) BatchGetProductSegments(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GetProductSegmentRequest, ProductSegmentResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AnalyticsService_ServiceDesc.Streams[0], AnalyticsService_BatchGetProductSegments_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetProductSegmentRequest, ProductSegmentResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyticsService_BatchGetProductSegmentsClient = grpc.BidiStreamingClient[GetProductSegmentRequest, ProductSegmentResult]
+
+func (c *analyticsServiceClient) GetDiscountRecommendation(ctx context.Context, in *GetDiscountRecommendationRequest, opts ...grpc.CallOption) (*DiscountRecommendation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiscountRecommendation)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetDiscountRecommendation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) BatchGetDiscountRecommendations(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GetDiscountRecommendationRequest, DiscountRecommendationResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AnalyticsService_ServiceDesc.Streams[1], AnalyticsService_BatchGetDiscountRecommendations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetDiscountRecommendationRequest, DiscountRecommendationResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyticsService_BatchGetDiscountRecommendationsClient = grpc.BidiStreamingClient[GetDiscountRecommendationRequest, DiscountRecommendationResult]
+
+func (c *analyticsServiceClient) ListDiscountRecommendations(ctx context.Context, in *ListDiscountRecommendationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DiscountRecommendation], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AnalyticsService_ServiceDesc.Streams[2], AnalyticsService_ListDiscountRecommendations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListDiscountRecommendationsRequest, DiscountRecommendation]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyticsService_ListDiscountRecommendationsClient = grpc.ServerStreamingClient[DiscountRecommendation]
+
+// AnalyticsServiceServer is the server API for AnalyticsService service.
+// All implementations must embed UnimplementedAnalyticsServiceServer
+// for forward compatibility.
+//
+// # AnalyticsService отдает результаты аналитики сервису меню с низкой задержкой
+//
+// Вызовы без дедлайна клиента ограничиваются таймаутом сервера; более длинные дедлайны
+// клиента сокращаются до него. Состояние сервиса доступно через grpc.health.v1.Health
+type AnalyticsServiceServer interface {
+	// GetProductRecommendations возвращает товары, которые стоит предложить к текущей корзине,
+	// по сохраненным ассоциативным правилам
+	GetProductRecommendations(context.Context, *GetProductRecommendationsRequest) (*GetProductRecommendationsResponse, error)
+	// GetProductSegment возвращает ABC-сегмент товара; NOT_FOUND, если товар не сегментирован
+	GetProductSegment(context.Context, *GetProductSegmentRequest) (*ProductSegment, error)
+	// BatchGetProductSegments возвращает сегменты для потока товаров, по ответу на каждый запрос в том же порядке
+	BatchGetProductSegments(grpc.BidiStreamingServer[GetProductSegmentRequest, ProductSegmentResult]) error
+	// GetDiscountRecommendation возвращает текущую рекомендацию по скидке для товара;
+	// NOT_FOUND, если рекомендации нет
+	GetDiscountRecommendation(context.Context, *GetDiscountRecommendationRequest) (*DiscountRecommendation, error)
+	// BatchGetDiscountRecommendations возвращает рекомендации для потока товаров, по ответу на каждый запрос в том же порядке
+	BatchGetDiscountRecommendations(grpc.BidiStreamingServer[GetDiscountRecommendationRequest, DiscountRecommendationResult]) error
+	// ListDiscountRecommendations передает потоком последние рекомендации по скидкам, при необходимости по категории
+	ListDiscountRecommendations(*ListDiscountRecommendationsRequest, grpc.ServerStreamingServer[DiscountRecommendation]) error
+	mustEmbedUnimplementedAnalyticsServiceServer()
+}
+
+// UnimplementedAnalyticsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAnalyticsServiceServer struct{}
+
+func (UnimplementedAnalyticsServiceServer) GetProductRecommendations(context.Context, *GetProductRecommendationsRequest) (*GetProductRecommendationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductRecommendations not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetProductSegment(context.Context, *GetProductSegmentRequest) (*ProductSegment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductSegment not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) BatchGetProductSegments(grpc.BidiStreamingServer[GetProductSegmentRequest, ProductSegmentResult]) error {
+	return status.Errorf(codes.Unimplemented, "method BatchGetProductSegments not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetDiscountRecommendation(context.Context, *GetDiscountRecommendationRequest) (*DiscountRecommendation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDiscountRecommendation not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) BatchGetDiscountRecommendations(grpc.BidiStreamingServer[GetDiscountRecommendationRequest, DiscountRecommendationResult]) error {
+	return status.Errorf(codes.Unimplemented, "method BatchGetDiscountRecommendations not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) ListDiscountRecommendations(*ListDiscountRecommendationsRequest, grpc.ServerStreamingServer[DiscountRecommendation]) error {
+	return status.Errorf(codes.Unimplemented, "method ListDiscountRecommendations not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) mustEmbedUnimplementedAnalyticsServiceServer() {}
+func (UnimplementedAnalyticsServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeAnalyticsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AnalyticsServiceServer will
+// result in compilation errors.
+type UnsafeAnalyticsServiceServer interface {
+	mustEmbedUnimplementedAnalyticsServiceServer()
+}
+
+func RegisterAnalyticsServiceServer(s grpc.ServiceRegistrar, srv AnalyticsServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAnalyticsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AnalyticsService_ServiceDesc, srv)
+}
+
+func _AnalyticsService_GetProductRecommendations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRecommendationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetProductRecommendations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetProductRecommendations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetProductRecommendations(ctx, req.(*GetProductRecommendationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetProductSegment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductSegmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetProductSegment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetProductSegment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetProductSegment(ctx, req.(*GetProductSegmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_BatchGetProductSegments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AnalyticsServiceServer).BatchGetProductSegments(&grpc.GenericServerStream[GetProductSegmentRequest, ProductSegmentResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyticsService_BatchGetProductSegmentsServer = grpc.BidiStreamingServer[GetProductSegmentRequest, ProductSegmentResult]
+
+func _AnalyticsService_GetDiscountRecommendation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDiscountRecommendationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetDiscountRecommendation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetDiscountRecommendation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetDiscountRecommendation(ctx, req.(*GetDiscountRecommendationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_BatchGetDiscountRecommendations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AnalyticsServiceServer).BatchGetDiscountRecommendations(&grpc.GenericServerStream[GetDiscountRecommendationRequest, DiscountRecommendationResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyticsService_BatchGetDiscountRecommendationsServer = grpc.BidiStreamingServer[GetDiscountRecommendationRequest, DiscountRecommendationResult]
+
+func _AnalyticsService_ListDiscountRecommendations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDiscountRecommendationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AnalyticsServiceServer).ListDiscountRecommendations(m, &grpc.GenericServerStream[ListDiscountRecommendationsRequest, DiscountRecommendation]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyticsService_ListDiscountRecommendationsServer = grpc.ServerStreamingServer[DiscountRecommendation]
+
+// AnalyticsService_ServiceDesc is the grpc.ServiceDesc for AnalyticsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AnalyticsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "analytics.v1.AnalyticsService",
+	HandlerType: (*AnalyticsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetProductRecommendations",
+			Handler:    _AnalyticsService_GetProductRecommendations_Handler,
+		},
+		{
+			MethodName: "GetProductSegment",
+			Handler:    _AnalyticsService_GetProductSegment_Handler,
+		},
+		{
+			MethodName: "GetDiscountRecommendation",
+			Handler:    _AnalyticsService_GetDiscountRecommendation_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchGetProductSegments",
+			Handler:       _AnalyticsService_BatchGetProductSegments_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "BatchGetDiscountRecommendations",
+			Handler:       _AnalyticsService_BatchGetDiscountRecommendations_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ListDiscountRecommendations",
+			Handler:       _AnalyticsService_ListDiscountRecommendations_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "analytics/v1/analytics.proto",
+}
@@ -0,0 +1,4 @@
+// Package analyticsv1 содержит gRPC-контракт аналитического сервиса и сгенерированный по нему код
+package analyticsv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative analytics/v1/analytics.proto
@@ -0,0 +1,126 @@
+// cmd/datagen/main.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"analitics-service/internal/datagen"
+)
+
+// Генератор синтетических данных кофейни для локальной разработки
+//
+//	go run ./cmd/datagen -out testdata/seed -format csv
+//	go run ./cmd/datagen -seed 42 -days 365 -analytics-dsn "$ANALYTICS_DSN" -users-dsn "$USERS_DSN" -reset
+//
+// Одинаковые параметры и зерно дают одинаковый набор данных
+func main() {
+	defaults := datagen.DefaultConfig()
+
+	seed := flag.Int64("seed", defaults.Seed, "зерно генератора случайных чисел")
+	start := flag.String("start", defaults.Start.Format("2006-01-02"), "первый день периода YYYY-MM-DD")
+	days := flag.Int("days", defaults.Days, "длина периода в днях")
+	timezone := flag.String("tz", "Europe/Moscow", "часовой пояс кофейни")
+	customers := flag.Int("customers", defaults.Customers, "количество клиентов")
+	daily := flag.Float64("daily", defaults.DailyTransactions, "среднее количество чеков в день")
+	basket := flag.Float64("basket", defaults.MeanBasketSize, "среднее количество позиций в чеке")
+	churn := flag.Float64("churn", defaults.ChurnRate, "доля клиентов, уходящих в течение периода")
+	discounts := flag.Int("discounts", defaults.DiscountCampaigns, "количество акций со скидкой")
+	coupons := flag.Int("coupons", defaults.CouponCampaigns, "количество купонных кампаний")
+	couponRate := flag.Float64("coupon-rate", defaults.CouponRedemptionRate, "вероятность использования купона в чеке во время кампании")
+	out := flag.String("out", "", "каталог для файлов набора данных")
+	formatName := flag.String("format", "json", "формат файлов: json, csv")
+	analyticsDSN := flag.String("analytics-dsn", "", "строка подключения к базе аналитического сервиса")
+	usersDSN := flag.String("users-dsn", "", "строка подключения к базе пользовательского сервиса")
+	reset := flag.Bool("reset", false, "очистить таблицы перед загрузкой")
+	flag.Parse()
+
+	if *out == "" && *analyticsDSN == "" && *usersDSN == "" {
+		log.Println("Укажите -out или строку подключения к базе")
+		flag.Usage()
+		os.Exit(2)
+	}
+	format, err := datagen.ParseFileFormat(*formatName)
+	if err != nil {
+		log.Fatalf("Некорректный формат: %v", err)
+	}
+	location, err := time.LoadLocation(*timezone)
+	if err != nil {
+		log.Fatalf("Некорректный часовой пояс: %v", err)
+	}
+	startDate, err := time.ParseInLocation("2006-01-02", *start, location)
+	if err != nil {
+		log.Fatalf("Некорректная дата начала: %v", err)
+	}
+
+	cfg := defaults
+	cfg.Seed = *seed
+	cfg.Start = startDate
+	cfg.Days = *days
+	cfg.Location = location
+	cfg.Customers = *customers
+	cfg.DailyTransactions = *daily
+	cfg.MeanBasketSize = *basket
+	cfg.ChurnRate = *churn
+	cfg.DiscountCampaigns = *discounts
+	cfg.CouponCampaigns = *coupons
+	cfg.CouponRedemptionRate = *couponRate
+
+	dataset, err := datagen.Generate(cfg)
+	if err != nil {
+		log.Fatalf("Ошибка генерации: %v", err)
+	}
+	log.Printf("Сгенерировано: %d клиентов, %d товаров, %d акций, %d чеков, %d продаж",
+		len(dataset.Users), len(dataset.Products), len(dataset.Promotions), len(dataset.Transactions), len(dataset.Sales))
+	for _, pattern := range dataset.Patterns {
+		stats := dataset.MeasurePattern(pattern)
+		log.Printf("Ассоциация %v -> %s: support=%.4f confidence=%.3f lift=%.2f",
+			pattern.Antecedent, pattern.Consequent, stats.Support, stats.Confidence, stats.Lift)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if *out != "" {
+		if err := datagen.WriteFiles(*out, format, dataset); err != nil {
+			log.Fatalf("Ошибка записи файлов: %v", err)
+		}
+		log.Printf("Файлы записаны в %s", *out)
+	}
+	if *analyticsDSN != "" {
+		if err := loadDatabase(ctx, *analyticsDSN, func(db *sql.DB) error {
+			return datagen.LoadAnalytics(ctx, db, dataset, *reset)
+		}); err != nil {
+			log.Fatalf("Ошибка загрузки в базу аналитического сервиса: %v", err)
+		}
+		log.Println("Данные загружены в базу аналитического сервиса")
+	}
+	if *usersDSN != "" {
+		if err := loadDatabase(ctx, *usersDSN, func(db *sql.DB) error {
+			return datagen.LoadUsers(ctx, db, dataset, *reset)
+		}); err != nil {
+			log.Fatalf("Ошибка загрузки в базу пользовательского сервиса: %v", err)
+		}
+		log.Println("Данные загружены в базу пользовательского сервиса")
+	}
+}
+
+// loadDatabase открывает соединение с базой dsn и выполняет загрузку
+func loadDatabase(ctx context.Context, dsn string, load func(db *sql.DB) error) error {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.PingContext(ctx); err != nil {
+		return err
+	}
+	return load(db)
+}
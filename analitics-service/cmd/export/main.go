@@ -0,0 +1,105 @@
+// cmd/export/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"analitics-service/pkg/export"
+)
+
+// Утилита выгрузки аналитических результатов в файл через API сервиса
+//
+//	go run ./cmd/export -dataset rules -format parquet -out rules.parquet
+//	go run ./cmd/export -dataset retention -period monthly -from 2024-01-01 -to 2024-07-01 -format xlsx
+//
+// Файл передается потоком, поэтому память утилиты не зависит от размера выгрузки
+func main() {
+	apiURL := flag.String("api", "http://localhost:8080", "адрес аналитического сервиса")
+	dataset := flag.String("dataset", "", "набор данных: abc, rules, recommendations, retention, forecasts")
+	formatName := flag.String("format", "csv", "формат файла: csv, xlsx, parquet")
+	out := flag.String("out", "", "файл результата; по умолчанию <dataset>.<format>, - для stdout")
+	from := flag.String("from", "", "начало периода YYYY-MM-DD")
+	to := flag.String("to", "", "конец периода YYYY-MM-DD")
+	period := flag.String("period", "", "гранулярность удержания: daily, weekly, monthly")
+	level := flag.String("level", "", "уровень прогнозов: product, category")
+	limit := flag.Int("limit", 0, "количество последних рекомендаций")
+	flag.Parse()
+
+	if *dataset == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		log.Fatalf("Некорректный формат: %v", err)
+	}
+
+	params := url.Values{"format": {string(format)}}
+	for name, value := range map[string]string{"from": *from, "to": *to, "period": *period, "level": *level} {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+	if *limit > 0 {
+		params.Set("limit", fmt.Sprint(*limit))
+	}
+	requestURL := fmt.Sprintf("%s/api/v1/exports/%s?%s", *apiURL, url.PathEscape(*dataset), params.Encode())
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	path := *out
+	if path == "" {
+		path = *dataset + format.Extension()
+	}
+	if err := download(ctx, requestURL, path); err != nil {
+		log.Fatalf("Ошибка выгрузки: %v", err)
+	}
+	if path != "-" {
+		log.Printf("Выгрузка сохранена в %s", path)
+	}
+}
+
+// download сохраняет ответ API в файл path; при ошибке частично записанный файл удаляется
+func download(ctx context.Context, requestURL, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("service responded %s: %s", resp.Status, body)
+	}
+
+	if path == "-" {
+		_, err = io.Copy(os.Stdout, resp.Body)
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
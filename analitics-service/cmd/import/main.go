@@ -0,0 +1,128 @@
+// cmd/import/main.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	_ "github.com/lib/pq" // Postgres driver
+
+	"analitics-service/config"
+	"analitics-service/internal/importer"
+)
+
+// Утилита импорта исторических выгрузок кассовых систем в базу аналитического сервиса
+//
+//	go run ./cmd/import -mapping pos_transactions.yaml -input receipts_2019_2024.csv
+//	go run ./cmd/import -mapping products.yaml -input products.jsonl -dry-run
+//
+// Отклоненные записи с причиной попадают в отчет <input>.rejected.csv; повторный запуск
+// пропускает уже загруженные сущности, поэтому прерванный импорт можно просто перезапустить
+func main() {
+	mappingPath := flag.String("mapping", "", "YAML-файл сопоставления колонок")
+	input := flag.String("input", "", "файл CSV или JSON Lines, - для stdin")
+	configPath := flag.String("config", "config/config.yaml", "конфигурация сервиса с подключением к базе")
+	dsn := flag.String("dsn", "", "строка подключения к базе; по умолчанию из конфигурации")
+	reportPath := flag.String("report", "", "отчет об отклоненных записях; по умолчанию <input>.rejected.csv")
+	batchSize := flag.Int("batch", 0, "количество сущностей в пачке; по умолчанию из сопоставления")
+	dryRun := flag.Bool("dry-run", false, "только проверить файл, не записывая в базу")
+	flag.Parse()
+
+	if *mappingPath == "" || *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	mapping, err := importer.LoadMapping(*mappingPath)
+	if err != nil {
+		log.Fatalf("Ошибка загрузки сопоставления: %v", err)
+	}
+	if *batchSize > 0 {
+		mapping.BatchSize = *batchSize
+	}
+
+	source, closeSource, err := openInput(*input)
+	if err != nil {
+		log.Fatalf("Не удалось открыть файл импорта: %v", err)
+	}
+	defer closeSource()
+
+	if *reportPath == "" {
+		*reportPath = "rejected.csv"
+		if *input != "-" {
+			*reportPath = *input + ".rejected.csv"
+		}
+	}
+	reportFile, err := os.Create(*reportPath)
+	if err != nil {
+		log.Fatalf("Не удалось создать отчет: %v", err)
+	}
+	report, err := importer.NewReportWriter(reportFile)
+	if err != nil {
+		log.Fatalf("Не удалось создать отчет: %v", err)
+	}
+
+	var db *sql.DB
+	if !*dryRun {
+		if *dsn == "" {
+			cfg, err := config.LoadConfig(*configPath)
+			if err != nil {
+				log.Fatalf("Ошибка загрузки конфигурации: %v", err)
+			}
+			*dsn = cfg.Database.DSN
+		}
+		db, err = sql.Open("postgres", *dsn)
+		if err != nil {
+			log.Fatalf("Ошибка подключения к базе: %v", err)
+		}
+		defer db.Close()
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	imp := importer.New(db, mapping, importer.Options{
+		Rejected: report.Write,
+		Progress: func(result importer.Result) {
+			log.Printf("Записей: %d, загружено: %d, дубликатов: %d, отклонено: %d",
+				result.Records, result.Imported, result.Duplicates, result.Rejected)
+		},
+	})
+	result, importErr := imp.Import(ctx, source, mapping.FormatFor(*input))
+
+	if err := report.Close(); err != nil {
+		log.Printf("Ошибка записи отчета: %v", err)
+	}
+	if err := reportFile.Close(); err != nil {
+		log.Printf("Ошибка записи отчета: %v", err)
+	}
+	if importErr != nil {
+		if result != nil {
+			log.Printf("Импорт прерван после %d записей, загружено %d %s", result.Records, result.Imported, mapping.Entity)
+		}
+		log.Fatalf("Ошибка импорта: %v", importErr)
+	}
+
+	log.Printf("Импорт %s завершен: записей %d, прошло проверку %d, загружено %d, дубликатов %d, отклонено %d",
+		mapping.Entity, result.Records, result.Valid, result.Imported, result.Duplicates, result.Rejected)
+	if result.Rejected > 0 {
+		log.Printf("Отклоненные записи: %s", *reportPath)
+	}
+}
+
+// openInput открывает файл импорта или stdin
+func openInput(path string) (io.Reader, func(), error) {
+	if path == "-" {
+		return os.Stdin, func() {}, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, func() { file.Close() }, nil
+}
@@ -4,17 +4,30 @@ package main
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	_ "github.com/lib/pq" // Postgres driver
 
 	"analitics-service/config"
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/internal/infrastructure/outbox"
+	"analitics-service/internal/infrastructure/postgres"
+	"analitics-service/internal/infrastructure/scheduler"
 	"analitics-service/internal/infrastructure/services"
+	grpcapi "analitics-service/internal/interfaces/grpc"
+	httpapi "analitics-service/internal/interfaces/http"
+	"analitics-service/internal/interfaces/http/handlers"
+	"analitics-service/internal/interfaces/kafka"
+	"analitics-service/internal/interfaces/notifier"
 	"analitics-service/pkg/logger"
 )
 
@@ -47,18 +60,171 @@ func main() {
 	}
 	logg.Info(ctx, "Successfully connected to database")
 
+	// Создание таблиц результатов анализа, если их еще нет
+	if _, err := db.ExecContext(ctx, postgres.AnalyticsSchema); err != nil {
+		logg.Error(ctx, "Failed to apply analytics schema", "error", err)
+		log.Fatalf("Failed to apply analytics schema: %v", err)
+	}
+
 	// Настройка пула соединений
 	db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
 	db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
 	db.SetConnMaxLifetime(time.Duration(cfg.Database.ConnMaxLifetimeMinutes) * time.Minute)
 
+	// Инициализация репозиториев
+	transactor := postgres.NewTransactor(db)
+	productRepo := postgres.NewProductRepository(db)
+	salesRepo := postgres.NewSalesRepository(db)
+	transactionRepo := postgres.NewTransactionRepository(db)
+	costRepo := postgres.NewProductCostRepository(db)
+	profitMarginRepo := postgres.NewProfitMarginRepository(db)
+	promotionRepo := postgres.NewPromotionRepository(db)
+	abcSegmentRepo := postgres.NewABCSegmentRepository(db)
+	abcAnalysisRepo := postgres.NewABCAnalysisRepository(db)
+	ruleRepo := postgres.NewAssociationRuleRepository(db)
+	recommendationRepo := postgres.NewDiscountRecommendationRepository(db)
+	forecastRepo := postgres.NewForecastRepository(db)
+	pricingRepo := postgres.NewPricingRepository(db)
+	anomalyRepo := postgres.NewAnomalyRepository(db)
+	retentionRepo := postgres.NewRetentionMetricsRepository(db)
+	bundleRepo := postgres.NewBundleRepository(db)
+	lifecycleRepo := postgres.NewProductLifecycleRepository(db)
+	runRepo := postgres.NewAnalysisRunRepository(db)
+	quarantineRepo := postgres.NewQuarantineRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	jobLockRepo := postgres.NewJobLockRepository(db)
+	jobRunRepo := postgres.NewJobRunRepository(db)
+	abTestRepo := postgres.NewABTestRepository(db)
+	experimentRepo := postgres.NewExperimentRepository(db)
+	exposureRepo := postgres.NewExposureRepository(db)
+	banditRepo := postgres.NewBanditRepository(db)
+	upliftRepo := postgres.NewUpliftModelRepository(db)
+
+	dataQualityConfig, err := newDataQualityConfig(cfg.DataQuality)
+	if err != nil {
+		log.Fatalf("Некорректная конфигурация data_quality: %v", err)
+	}
+	basketKPIConfig, err := newBasketKPIConfig(cfg.Dayparts, cfg.BasketKPIs)
+	if err != nil {
+		log.Fatalf("Некорректная конфигурация basket_kpis: %v", err)
+	}
+	daypartOptions, err := newDaypartAnalysisOptions(cfg.Dayparts)
+	if err != nil {
+		log.Fatalf("Некорректная конфигурация dayparts: %v", err)
+	}
+
 	// Инициализация сервисов
 	aprioriService := services.NewAprioriService(logg)
+	abcService := services.NewABCAnalysisService(productRepo, salesRepo, abcSegmentRepo, profitMarginRepo, costRepo)
+	cannibalizationService := services.NewCannibalizationService(salesRepo, productRepo, ruleRepo, logg)
+	regressionService := services.NewRegressionService(transactionRepo, productRepo, abTestRepo, abcSegmentRepo, lifecycleRepo,
+		cannibalizationService)
+	eventService := services.NewAnalyticsEventService(transactor, outboxRepo, runRepo, abcSegmentRepo, ruleRepo, recommendationRepo, logg)
+	dataQualityService := services.NewDataQualityService(logg)
+	analysisRunService := services.NewAnalysisRunService(
+		abcService, aprioriService, regressionService,
+		productRepo, salesRepo, profitMarginRepo, costRepo, transactionRepo, lifecycleRepo, abcAnalysisRepo, ruleRepo,
+		recommendationRepo, abcSegmentRepo, runRepo, quarantineRepo,
+		transactor, eventService, dataQualityService, dataQualityConfig, logg,
+	)
+	forecastService := services.NewForecastService(salesRepo, productRepo, forecastRepo, logg)
+	pricingService := services.NewPricingService(productRepo, salesRepo, abcSegmentRepo, pricingRepo, logg)
+	anomalyService := services.NewAnomalyService(salesRepo, anomalyRepo, newAnomalyNotifier(cfg.Alerts, logg), logg)
+	retentionService := services.NewRetentionService(transactionRepo, retentionRepo, logg)
+	couponService := services.NewCouponAnalyticsService(transactionRepo, productRepo, promotionRepo, costRepo, logg)
+	bundleService := services.NewBundleService(transactionRepo, productRepo, bundleRepo, logg)
+	lifecycleService := services.NewLifecycleService(productRepo, salesRepo, abcSegmentRepo, lifecycleRepo, logg)
+	exportService := services.NewExportService(abcSegmentRepo, ruleRepo, recommendationRepo, forecastRepo, retentionService, logg)
+	marginService := services.NewMarginService(costRepo, productRepo, salesRepo, transactor, logg)
+	abTestService := services.NewABTestEvaluationService(abTestRepo, logg)
+	experimentService := services.NewExperimentService(experimentRepo, exposureRepo, salesRepo, abTestRepo, logg)
+	banditService := services.NewBanditService(banditRepo, logg)
+	upliftService := services.NewUpliftService(upliftRepo, experimentRepo, exposureRepo, transactionRepo, logg)
+	promotionImpactService := services.NewPromotionImpactService(promotionRepo, salesRepo, productRepo, costRepo, logg)
+	daypartService := services.NewDaypartService(transactionRepo, salesRepo, abcSegmentRepo, aprioriService, logg)
+	basketKPIService := services.NewCachedBasketKPIService(
+		services.NewBasketKPIService(transactionRepo, basketKPIConfig, logg),
+		time.Duration(cfg.BasketKPIs.CacheTTLSeconds)*time.Second,
+		cfg.BasketKPIs.CacheMaxEntries,
+		logg,
+	)
+	recommendationQueryService := services.NewRecommendationQueryService(productRepo, ruleRepo, abcSegmentRepo, recommendationRepo,
+		aprioriService, cfg.Apriori.MaxRecommendations, logg)
 	logg.Info(ctx, "Services initialized successfully")
 
+	// Идентификатор реплики — владелец блокировок планировщика и ретранслятора событий
+	instanceID, err := resolveInstanceID(cfg.Scheduler.InstanceID)
+	if err != nil {
+		log.Fatalf("Failed to resolve instance id: %v", err)
+	}
+
+	// Инициализация планировщика задач; задачи доступны для ручного запуска через API,
+	// а по расписанию запускаются, если включен scheduler.enabled
+	jobScheduler, err := newScheduler(cfg.Scheduler, instanceID, analysisRunService, retentionService, jobLockRepo, jobRunRepo, logg)
+	if err != nil {
+		logg.Error(ctx, "Failed to create job scheduler", "error", err)
+		log.Fatalf("Failed to create job scheduler: %v", err)
+	}
+
+	// Фоновые задачи работают до получения сигнала остановки
+	runCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	var background sync.WaitGroup
+
+	if cfg.Scheduler.Enabled {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			jobScheduler.Start(runCtx)
+		}()
+	}
+
+	// Ретранслятор публикует события из outbox в Kafka
+	var producer kafka.MessageProducer
+	if cfg.Events.Enabled {
+		producer = kafka.NewProducer(cfg.Events.Brokers, cfg.Events.Topic)
+		relay, err := outbox.NewRelay(outboxRepo, jobLockRepo, producer, outbox.RelayOptions{
+			InstanceID: instanceID,
+			Interval:   time.Duration(cfg.Events.RelayIntervalSeconds) * time.Second,
+			BatchSize:  cfg.Events.RelayBatchSize,
+			LockTTL:    time.Duration(cfg.Events.LockTTLSeconds) * time.Second,
+			Retention:  time.Duration(cfg.Events.RetentionHours) * time.Hour,
+		}, logg)
+		if err != nil {
+			logg.Error(ctx, "Failed to create outbox relay", "error", err)
+			log.Fatalf("Failed to create outbox relay: %v", err)
+		}
+
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			relay.Start(runCtx)
+		}()
+	}
+
 	// Инициализация HTTP роутера
-	// TODO: Добавить HTTP обработчики
-	router := http.NewServeMux()
+	router := httpapi.SetupRouter(
+		handlers.NewForecastHandler(forecastService, logg),
+		handlers.NewPricingHandler(pricingService, entities.DefaultPricingConfig(), logg),
+		handlers.NewAnomalyHandler(anomalyService, entities.DefaultAnomalyDetectionConfig(), logg),
+		handlers.NewRetentionHandler(retentionService, logg),
+		handlers.NewCouponHandler(couponService, entities.DefaultCouponAnalyticsConfig(), logg),
+		handlers.NewBundleHandler(bundleService, entities.DefaultBundleOptions(), logg),
+		handlers.NewLifecycleHandler(lifecycleService, entities.DefaultLifecycleConfig(), logg),
+		handlers.NewJobHandler(jobScheduler, logg),
+		handlers.NewAnalysisRunHandler(analysisRunService, logg),
+		handlers.NewExportHandler(exportService, logg),
+		handlers.NewEventHandler(eventService, logg),
+		handlers.NewMarginHandler(marginService, logg),
+		handlers.NewBasketKPIHandler(basketKPIService, logg),
+		handlers.NewABTestHandler(abTestService, entities.DefaultABTestAnalysisOptions(), logg),
+		handlers.NewExperimentHandler(experimentService, logg),
+		handlers.NewBanditHandler(banditService, logg),
+		handlers.NewUpliftHandler(upliftService, entities.DefaultUpliftModelConfig(), logg),
+		handlers.NewCannibalizationHandler(cannibalizationService, logg),
+		handlers.NewPromotionHandler(promotionImpactService, logg),
+		handlers.NewDaypartHandler(daypartService, daypartOptions, logg),
+	)
 	logg.Info(ctx, "HTTP router setup completed")
 
 	// Запуск HTTP сервера
@@ -83,10 +249,31 @@ func main() {
 		}
 	}()
 
+	// Запуск gRPC сервера на отдельном адресе
+	grpcAddr := cfg.GRPC.Address
+	if grpcAddr == "" {
+		grpcAddr = ":9090"
+	}
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		logg.Error(ctx, "Failed to listen gRPC address", "address", grpcAddr, "error", err)
+		log.Fatalf("Failed to listen gRPC address %s: %v", grpcAddr, err)
+	}
+	grpcSrv := grpcapi.NewServer(recommendationQueryService, grpcapi.Options{
+		UnaryTimeout:  time.Duration(cfg.GRPC.UnaryTimeoutMs) * time.Millisecond,
+		StreamTimeout: time.Duration(cfg.GRPC.StreamTimeoutSeconds) * time.Second,
+		MaxBatchSize:  cfg.GRPC.MaxBatchSize,
+	}, logg)
+
+	go func() {
+		if err := grpcSrv.Serve(grpcListener); err != nil {
+			logg.Error(ctx, "Failed to start gRPC server", "error", err)
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
 	// Graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	<-runCtx.Done()
 
 	logg.Info(ctx, "Shutting down server...")
 
@@ -100,5 +287,152 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Останавливаем gRPC сервер
+	grpcSrv.Shutdown(shutdownCtx)
+
+	// Дожидаемся фоновых задач: запуски по расписанию и ретранслятор останавливаются отменой runCtx
+	waitBackground(shutdownCtx, &background, logg)
+
+	// Продюсер закрывается после остановки ретранслятора, чтобы не прервать отправку пачки
+	if producer != nil {
+		if err := producer.Close(); err != nil {
+			logg.Error(shutdownCtx, "Failed to close Kafka producer", "error", err)
+		}
+	}
+
 	logg.Info(shutdownCtx, "Server exited properly")
 }
+
+// waitBackground ждет завершения фоновых задач, но не дольше ctx
+func waitBackground(ctx context.Context, background *sync.WaitGroup, logg logger.Logger) {
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logg.Warn(ctx, "Background tasks did not finish before shutdown timeout")
+	}
+}
+
+// newDataQualityConfig переводит секцию data_quality в правила очистки; без правил применяются правила по умолчанию
+func newDataQualityConfig(cfg config.DataQualityConfig) (entities.DataQualityConfig, error) {
+	dataQuality := entities.DefaultDataQualityConfig()
+	if len(cfg.Rules) > 0 {
+		dataQuality.Rules = make(map[entities.DataQualityRule]entities.DataQualityAction, len(cfg.Rules))
+		for rule, action := range cfg.Rules {
+			dataQuality.Rules[entities.DataQualityRule(rule)] = entities.DataQualityAction(action)
+		}
+	}
+	if cfg.TotalTolerance > 0 {
+		dataQuality.TotalTolerance = cfg.TotalTolerance
+	}
+	if cfg.OutlierThreshold > 0 {
+		dataQuality.OutlierThreshold = cfg.OutlierThreshold
+	}
+	if cfg.OutlierMinSamples > 0 {
+		dataQuality.OutlierMinSamples = cfg.OutlierMinSamples
+	}
+	return dataQuality, dataQuality.Validate()
+}
+
+// newBasketKPIConfig собирает параметры KPI корзины из секций dayparts и basket_kpis
+func newBasketKPIConfig(dayparts config.DaypartsConfig, cfg config.BasketKPIsConfig) (entities.BasketKPIConfig, error) {
+	kpi := entities.DefaultBasketKPIConfig()
+	if len(dayparts.Parts) > 0 {
+		kpi.Dayparts = configDayparts(dayparts)
+	}
+	if dayparts.Timezone != "" {
+		kpi.Timezone = dayparts.Timezone
+	}
+	if cfg.LoyalMinPurchases > 0 {
+		kpi.LoyalMinPurchases = cfg.LoyalMinPurchases
+	}
+	if cfg.SegmentLookbackDays > 0 {
+		kpi.SegmentLookbackDays = cfg.SegmentLookbackDays
+	}
+	return kpi, kpi.Validate()
+}
+
+// newDaypartAnalysisOptions собирает параметры анализа частей дня из секции dayparts
+func newDaypartAnalysisOptions(dayparts config.DaypartsConfig) (entities.DaypartAnalysisOptions, error) {
+	options := entities.DefaultDaypartAnalysisOptions()
+	if len(dayparts.Parts) > 0 {
+		options.Dayparts = configDayparts(dayparts)
+	}
+	if dayparts.Timezone != "" {
+		options.Timezone = dayparts.Timezone
+	}
+	return options, options.Validate()
+}
+
+// configDayparts преобразует части дня из конфигурации в доменные
+func configDayparts(dayparts config.DaypartsConfig) []entities.Daypart {
+	parts := make([]entities.Daypart, 0, len(dayparts.Parts))
+	for _, part := range dayparts.Parts {
+		parts = append(parts, entities.Daypart{Name: part.Name, StartHour: part.StartHour, EndHour: part.EndHour})
+	}
+	return parts
+}
+
+// newAnomalyNotifier пишет алерты в лог и, если задан вебхук, отправляет их на него
+func newAnomalyNotifier(cfg config.AlertsConfig, logg logger.Logger) services.AnomalyNotifier {
+	logNotifier := notifier.NewLogNotifier(logg)
+	if cfg.WebhookURL == "" {
+		return logNotifier
+	}
+	timeout := time.Duration(cfg.WebhookTimeoutSeconds) * time.Second
+	return services.NewMultiNotifier(logNotifier, notifier.NewWebhookNotifier(cfg.WebhookURL, timeout))
+}
+
+// newScheduler регистрирует встроенные задачи и создает планировщик с расписаниями из секции scheduler
+func newScheduler(
+	cfg config.SchedulerConfig,
+	instanceID string,
+	analysisRunService services.AnalysisRunService,
+	retentionService services.RetentionService,
+	lockRepo repositories.JobLockRepository,
+	runRepo repositories.JobRunRepository,
+	logg logger.Logger,
+) (*scheduler.Scheduler, error) {
+	registry := scheduler.NewRegistry()
+	for _, job := range []scheduler.Job{
+		scheduler.NewABCAnalysisJob(analysisRunService),
+		scheduler.NewAprioriJob(analysisRunService),
+		scheduler.NewRetentionJob(retentionService),
+		scheduler.NewDiscountRecommendationsJob(analysisRunService),
+	} {
+		if err := registry.Register(job); err != nil {
+			return nil, err
+		}
+	}
+
+	schedules := make([]scheduler.JobSchedule, 0, len(cfg.Jobs))
+	for _, job := range cfg.Jobs {
+		schedules = append(schedules, scheduler.JobSchedule{
+			Name:    job.Name,
+			Spec:    job.Schedule,
+			Enabled: job.Enabled,
+			Timeout: time.Duration(job.TimeoutMinutes) * time.Minute,
+			Params:  scheduler.Params(job.Params),
+		})
+	}
+
+	return scheduler.NewScheduler(registry, schedules, lockRepo, runRepo, instanceID,
+		time.Duration(cfg.LockTTLMinutes)*time.Minute, logg)
+}
+
+// resolveInstanceID возвращает идентификатор реплики из конфигурации или имя хоста
+func resolveInstanceID(configured string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("failed to get host name: %w", err)
+	}
+	return hostname, nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"os"
 
 	"github.com/joho/godotenv"
@@ -27,6 +28,19 @@ func LoadConfig(path string) (*Config, error) {
 
 	// Expand environment variables in the DSN string from the YAML file.
 	cfg.Database.DSN = os.ExpandEnv(cfg.Database.DSN)
+	cfg.Alerts.WebhookURL = os.ExpandEnv(cfg.Alerts.WebhookURL)
+	cfg.Scheduler.InstanceID = os.ExpandEnv(cfg.Scheduler.InstanceID)
+	// Brokers that expand to an empty string (unset variable) are dropped.
+	brokers := cfg.Events.Brokers[:0]
+	for _, broker := range cfg.Events.Brokers {
+		if broker = os.ExpandEnv(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	cfg.Events.Brokers = brokers
+	if cfg.Events.Enabled && len(cfg.Events.Brokers) == 0 {
+		return nil, errors.New("events are enabled but no Kafka brokers are configured")
+	}
 	return &cfg, nil
 }
 
@@ -37,6 +51,13 @@ type Config struct {
 	Database    DatabaseConfig    `yaml:"database"`
 	Apriori     AprioriConfig     `yaml:"apriori"`
 	ABCAnalysis ABCAnalysisConfig `yaml:"abc_analysis"`
+	Dayparts    DaypartsConfig    `yaml:"dayparts"`
+	Alerts      AlertsConfig      `yaml:"alerts"`
+	Scheduler   SchedulerConfig   `yaml:"scheduler"`
+	GRPC        GRPCConfig        `yaml:"grpc"`
+	Events      EventsConfig      `yaml:"events"`
+	DataQuality DataQualityConfig `yaml:"data_quality"`
+	BasketKPIs  BasketKPIsConfig  `yaml:"basket_kpis"`
 }
 
 // ServerConfig holds the server-related settings.
@@ -48,6 +69,15 @@ type ServerConfig struct {
 	IdleTimeoutSeconds  int    `yaml:"idle_timeout_seconds"`
 }
 
+// GRPCConfig holds the gRPC server settings.
+// Timeouts cap call duration: calls without a client deadline or with a longer one are cut to them.
+type GRPCConfig struct {
+	Address              string `yaml:"address"`
+	UnaryTimeoutMs       int    `yaml:"unary_timeout_ms"`
+	StreamTimeoutSeconds int    `yaml:"stream_timeout_seconds"`
+	MaxBatchSize         int    `yaml:"max_batch_size"`
+}
+
 // LoggerConfig holds logger settings.
 type LoggerConfig struct {
 	Level  string `yaml:"level"`
@@ -74,3 +104,74 @@ type ABCAnalysisConfig struct {
 	AThreshold float64 `yaml:"a_threshold"`
 	BThreshold float64 `yaml:"b_threshold"`
 }
+
+// DaypartsConfig holds settings for daypart sales analysis.
+type DaypartsConfig struct {
+	Timezone string          `yaml:"timezone"`
+	Parts    []DaypartConfig `yaml:"parts"`
+}
+
+// DaypartConfig describes a named part of the day as an hour interval [start, end).
+type DaypartConfig struct {
+	Name      string `yaml:"name"`
+	StartHour int    `yaml:"start_hour"`
+	EndHour   int    `yaml:"end_hour"`
+}
+
+// AlertsConfig holds anomaly alert delivery settings.
+// An empty webhook URL means alerts are only written to the log.
+type AlertsConfig struct {
+	WebhookURL            string `yaml:"webhook_url"`
+	WebhookTimeoutSeconds int    `yaml:"webhook_timeout_seconds"`
+}
+
+// SchedulerConfig holds settings for scheduled analytics jobs.
+// InstanceID identifies the replica holding a job lock; when empty the host name is used.
+type SchedulerConfig struct {
+	Enabled        bool        `yaml:"enabled"`
+	InstanceID     string      `yaml:"instance_id"`
+	LockTTLMinutes int         `yaml:"lock_ttl_minutes"`
+	Jobs           []JobConfig `yaml:"jobs"`
+}
+
+// JobConfig describes the cron schedule and parameters of a registered job.
+// An empty schedule means the job can only be triggered manually through the API.
+type JobConfig struct {
+	Name           string            `yaml:"name"`
+	Schedule       string            `yaml:"schedule"`
+	Enabled        bool              `yaml:"enabled"`
+	TimeoutMinutes int               `yaml:"timeout_minutes"`
+	Params         map[string]string `yaml:"params"`
+}
+
+// EventsConfig holds analytics event publishing settings.
+// Events are written to the outbox table together with the data they describe and relayed to Kafka
+// by a single replica holding the relay lock. A zero retention keeps published events forever.
+type EventsConfig struct {
+	Enabled              bool     `yaml:"enabled"`
+	Brokers              []string `yaml:"brokers"`
+	Topic                string   `yaml:"topic"`
+	RelayIntervalSeconds int      `yaml:"relay_interval_seconds"`
+	RelayBatchSize       int      `yaml:"relay_batch_size"`
+	LockTTLSeconds       int      `yaml:"lock_ttl_seconds"`
+	RetentionHours       int      `yaml:"retention_hours"`
+}
+
+// DataQualityConfig holds data-quality rules applied to analysis inputs.
+// Rules maps a rule name to its action: quarantine, repair or flag; rules missing from the map are not applied.
+type DataQualityConfig struct {
+	Rules             map[string]string `yaml:"rules"`
+	TotalTolerance    float64           `yaml:"total_tolerance"`
+	OutlierThreshold  float64           `yaml:"outlier_threshold"`
+	OutlierMinSamples int               `yaml:"outlier_min_samples"`
+}
+
+// BasketKPIsConfig holds settings for the basket KPI dashboard.
+// Period boundaries and dayparts come from the dayparts section. A customer is loyal once their purchases
+// within the lookback window, including the current one, reach loyal_min_purchases. A zero cache TTL disables caching.
+type BasketKPIsConfig struct {
+	LoyalMinPurchases   int `yaml:"loyal_min_purchases"`
+	SegmentLookbackDays int `yaml:"segment_lookback_days"`
+	CacheTTLSeconds     int `yaml:"cache_ttl_seconds"`
+	CacheMaxEntries     int `yaml:"cache_max_entries"`
+}
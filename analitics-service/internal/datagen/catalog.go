@@ -0,0 +1,115 @@
+// internal/datagen/catalog.go
+package datagen
+
+import (
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// catalogItem описывает товар меню и его спрос
+type catalogItem struct {
+	id          string
+	name        string
+	category    string
+	subCategory string
+	price       float64
+	cost        float64
+	popularity  float64            // Относительный спрос на товар
+	dayparts    map[string]float64 // Множитель спроса по частям дня; отсутствующие части дня — 1
+}
+
+// Категории меню
+const (
+	categoryCoffee     = "coffee"
+	categoryTea        = "tea"
+	categoryBakery     = "bakery"
+	categorySandwiches = "sandwiches"
+	categoryDesserts   = "desserts"
+	categoryDrinks     = "drinks"
+)
+
+// Спрос по частям дня для типичных групп товаров
+var (
+	morningDemand = map[string]float64{"breakfast": 2.0, "lunch": 0.8, "afternoon": 0.7, "evening": 0.4}
+	lunchDemand   = map[string]float64{"breakfast": 0.4, "lunch": 2.5, "afternoon": 0.8, "evening": 0.6}
+	sweetDemand   = map[string]float64{"breakfast": 0.5, "lunch": 0.8, "afternoon": 2.0, "evening": 1.4}
+	eveningDemand = map[string]float64{"breakfast": 0.3, "lunch": 0.7, "afternoon": 1.2, "evening": 1.8}
+)
+
+// catalog меню кофейни; ID товаров стабильны и используются в заложенных ассоциациях
+var catalog = []catalogItem{
+	{"prod-espresso", "Espresso", categoryCoffee, "black", 2.20, 0.45, 0.8, morningDemand},
+	{"prod-americano", "Americano", categoryCoffee, "black", 2.60, 0.50, 1.6, morningDemand},
+	{"prod-cappuccino", "Cappuccino", categoryCoffee, "milk", 3.40, 0.80, 2.2, morningDemand},
+	{"prod-latte", "Latte", categoryCoffee, "milk", 3.60, 0.85, 2.4, nil},
+	{"prod-flat-white", "Flat White", categoryCoffee, "milk", 3.70, 0.90, 1.3, morningDemand},
+	{"prod-raf", "Raf Coffee", categoryCoffee, "milk", 4.10, 1.00, 0.9, sweetDemand},
+	{"prod-cold-brew", "Cold Brew", categoryCoffee, "cold", 3.90, 0.70, 0.8, eveningDemand},
+	{"prod-black-tea", "Black Tea", categoryTea, "classic", 2.30, 0.30, 0.7, nil},
+	{"prod-green-tea", "Green Tea", categoryTea, "classic", 2.30, 0.30, 0.6, sweetDemand},
+	{"prod-matcha-latte", "Matcha Latte", categoryTea, "latte", 4.20, 1.10, 0.9, sweetDemand},
+	{"prod-croissant", "Croissant", categoryBakery, "viennoiserie", 2.80, 0.70, 1.5, morningDemand},
+	{"prod-pain-au-chocolat", "Pain au Chocolat", categoryBakery, "viennoiserie", 3.10, 0.80, 0.9, morningDemand},
+	{"prod-cinnamon-roll", "Cinnamon Roll", categoryBakery, "sweet", 3.30, 0.75, 0.8, sweetDemand},
+	{"prod-blueberry-muffin", "Blueberry Muffin", categoryBakery, "sweet", 2.90, 0.65, 0.7, sweetDemand},
+	{"prod-ham-cheese-sandwich", "Ham & Cheese Sandwich", categorySandwiches, "hot", 5.90, 2.10, 1.0, lunchDemand},
+	{"prod-chicken-wrap", "Chicken Wrap", categorySandwiches, "hot", 6.40, 2.40, 0.9, lunchDemand},
+	{"prod-avocado-toast", "Avocado Toast", categorySandwiches, "toast", 6.80, 2.50, 0.7, morningDemand},
+	{"prod-caprese-panini", "Caprese Panini", categorySandwiches, "hot", 6.20, 2.20, 0.6, lunchDemand},
+	{"prod-cheesecake", "Cheesecake", categoryDesserts, "cake", 4.50, 1.40, 0.8, sweetDemand},
+	{"prod-tiramisu", "Tiramisu", categoryDesserts, "cake", 4.80, 1.50, 0.6, eveningDemand},
+	{"prod-chocolate-cookie", "Chocolate Cookie", categoryDesserts, "cookie", 1.90, 0.35, 1.1, sweetDemand},
+	{"prod-macaron-set", "Macaron Set", categoryDesserts, "cookie", 5.50, 1.80, 0.3, eveningDemand},
+	{"prod-orange-juice", "Fresh Orange Juice", categoryDrinks, "juice", 3.80, 1.20, 0.7, morningDemand},
+	{"prod-lemonade", "Homemade Lemonade", categoryDrinks, "cold", 3.50, 0.60, 0.6, eveningDemand},
+	{"prod-still-water", "Still Water", categoryDrinks, "water", 1.50, 0.30, 0.5, lunchDemand},
+}
+
+// catalogIndex возвращает позицию товара в каталоге по ID
+func catalogIndex(productID string) (int, bool) {
+	for i := range catalog {
+		if catalog[i].id == productID {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// demand возвращает спрос на товар в указанной части дня
+func (c *catalogItem) demand(daypart string) float64 {
+	if multiplier, ok := c.dayparts[daypart]; ok {
+		return c.popularity * multiplier
+	}
+	return c.popularity
+}
+
+// product преобразует позицию каталога в товар; createdAt — дата заведения товара в меню
+func (c *catalogItem) product(createdAt time.Time) entities.Product {
+	return entities.Product{
+		BaseEntity: entities.BaseEntity{
+			ID:        c.id,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		},
+		Name:        c.name,
+		Category:    c.category,
+		CategoryID:  "cat-" + c.category,
+		SubCategory: c.subCategory,
+		Price:       c.price,
+		Cost:        c.cost,
+		IsActive:    true,
+	}
+}
+
+// item преобразует позицию каталога в позицию чека
+func (c *catalogItem) item() entities.Item {
+	return entities.Item{
+		ProductID:  c.id,
+		Name:       c.name,
+		CategoryID: "cat-" + c.category,
+		Category:   c.category,
+		Price:      c.price,
+		Quantity:   1,
+	}
+}
@@ -0,0 +1,108 @@
+// internal/datagen/config.go
+package datagen
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// Config содержит параметры генерации синтетических данных кофейни
+// При одинаковых Config генератор выдает одинаковый набор данных
+type Config struct {
+	Seed     int64          // Зерно генератора случайных чисел
+	Start    time.Time      // Первый день периода; время суток отбрасывается
+	Days     int            // Длина периода в днях
+	Location *time.Location // Часовой пояс кофейни; nil — UTC
+
+	Customers         int     // Количество клиентов
+	DailyTransactions float64 // Среднее количество чеков в день при всех активных клиентах
+	MeanBasketSize    float64 // Среднее количество позиций в чеке без учета заложенных ассоциаций
+	ChurnRate         float64 // Доля клиентов, которые перестают приходить в течение периода
+
+	DiscountCampaigns    int     // Количество акций со скидкой на категорию или товары
+	CouponCampaigns      int     // Количество купонных кампаний
+	CouponRedemptionRate float64 // Вероятность использования купона в чеке во время кампании
+
+	Dayparts []entities.Daypart // Части дня, по которым распределяются визиты и спрос на товары
+	Patterns []Pattern          // Ассоциации, закладываемые в корзины
+}
+
+// DefaultConfig возвращает параметры небольшой кофейни за 180 дней, заканчивающихся вчера
+func DefaultConfig() Config {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return Config{
+		Seed:                 1,
+		Start:                today.AddDate(0, 0, -180),
+		Days:                 180,
+		Location:             time.UTC,
+		Customers:            2000,
+		DailyTransactions:    300,
+		MeanBasketSize:       1.8,
+		ChurnRate:            0.25,
+		DiscountCampaigns:    6,
+		CouponCampaigns:      3,
+		CouponRedemptionRate: 0.08,
+		Dayparts: []entities.Daypart{
+			{Name: "breakfast", StartHour: 6, EndHour: 11},
+			{Name: "lunch", StartHour: 11, EndHour: 14},
+			{Name: "afternoon", StartHour: 14, EndHour: 17},
+			{Name: "evening", StartHour: 17, EndHour: 22},
+		},
+		Patterns: DefaultPatterns(),
+	}
+}
+
+// Validate проверяет корректность параметров генерации
+func (c *Config) Validate() error {
+	if c.Start.IsZero() {
+		return errors.New("start date is required")
+	}
+
+	if c.Days <= 0 {
+		return fmt.Errorf("days must be positive, got %d", c.Days)
+	}
+
+	if c.Customers <= 0 {
+		return fmt.Errorf("customers must be positive, got %d", c.Customers)
+	}
+
+	if c.DailyTransactions <= 0 {
+		return fmt.Errorf("daily transactions must be positive, got %f", c.DailyTransactions)
+	}
+
+	if c.MeanBasketSize < 1 {
+		return fmt.Errorf("mean basket size must be at least 1, got %f", c.MeanBasketSize)
+	}
+
+	if c.ChurnRate < 0 || c.ChurnRate > 1 {
+		return fmt.Errorf("churn rate must be between 0 and 1, got %f", c.ChurnRate)
+	}
+
+	if c.DiscountCampaigns < 0 || c.CouponCampaigns < 0 {
+		return errors.New("campaign counts cannot be negative")
+	}
+
+	if c.CouponRedemptionRate < 0 || c.CouponRedemptionRate > 1 {
+		return fmt.Errorf("coupon redemption rate must be between 0 and 1, got %f", c.CouponRedemptionRate)
+	}
+
+	if len(c.Dayparts) == 0 {
+		return errors.New("at least one daypart is required")
+	}
+	for i := range c.Dayparts {
+		if err := c.Dayparts[i].Validate(); err != nil {
+			return fmt.Errorf("invalid daypart: %w", err)
+		}
+	}
+
+	for i := range c.Patterns {
+		if err := c.Patterns[i].validate(); err != nil {
+			return fmt.Errorf("invalid pattern %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// internal/datagen/dataset.go
+package datagen
+
+import (
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// User представляет клиента в формате пользовательского сервиса
+// ChurnedAt не сохраняется в базу: это истинная дата ухода клиента для проверки моделей оттока
+type User struct {
+	ID               string     `json:"id"`
+	Email            string     `json:"email"`
+	Phone            string     `json:"phone"`
+	Age              int        `json:"age"`
+	Gender           string     `json:"gender"`
+	City             string     `json:"city"`
+	RegistrationDate time.Time  `json:"registration_date"`
+	LastActivity     time.Time  `json:"last_activity"`
+	ChurnedAt        *time.Time `json:"churned_at,omitempty"`
+}
+
+// Dataset содержит сгенерированные данные обоих сервисов
+// Чеки и продажи упорядочены по времени; ID клиентов и чеков — UUID, общие для обоих сервисов
+type Dataset struct {
+	Users        []User                 `json:"users"`
+	Products     []entities.Product     `json:"products"`
+	Promotions   []entities.Promotion   `json:"promotions"`
+	Transactions []entities.Transaction `json:"transactions"`
+	Sales        []entities.Sale        `json:"sales"`
+	Patterns     []Pattern              `json:"patterns"`
+}
+
+// MeasurePattern возвращает наблюдаемые поддержку, достоверность и лифт ассоциации в чеках набора
+func (d *Dataset) MeasurePattern(pattern Pattern) PatternStats {
+	if len(d.Transactions) == 0 {
+		return PatternStats{}
+	}
+
+	var withAntecedent, withConsequent, withBoth int
+	for i := range d.Transactions {
+		products := make(map[string]bool, len(d.Transactions[i].Items))
+		for _, item := range d.Transactions[i].Items {
+			products[item.ProductID] = true
+		}
+
+		hasAntecedent := true
+		for _, productID := range pattern.Antecedent {
+			if !products[productID] {
+				hasAntecedent = false
+				break
+			}
+		}
+		hasConsequent := products[pattern.Consequent]
+
+		if hasAntecedent {
+			withAntecedent++
+		}
+		if hasConsequent {
+			withConsequent++
+		}
+		if hasAntecedent && hasConsequent {
+			withBoth++
+		}
+	}
+
+	total := float64(len(d.Transactions))
+	stats := PatternStats{Support: float64(withBoth) / total}
+	if withAntecedent > 0 {
+		stats.Confidence = float64(withBoth) / float64(withAntecedent)
+	}
+	if withConsequent > 0 {
+		stats.Lift = stats.Confidence / (float64(withConsequent) / total)
+	}
+	return stats
+}
+
+// UserTransaction представляет чек в формате пользовательского сервиса
+type UserTransaction struct {
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	Amount          float64   `json:"amount"`
+	Timestamp       time.Time `json:"timestamp"`
+	Category        string    `json:"category"`
+	DiscountApplied bool      `json:"discount_applied"`
+}
+
+// UserTransactions возвращает чеки в формате пользовательского сервиса
+// Категорией чека считается категория самой дорогой позиции
+func (d *Dataset) UserTransactions() []UserTransaction {
+	transactions := make([]UserTransaction, 0, len(d.Transactions))
+	for _, t := range d.Transactions {
+		category := ""
+		maxAmount := -1.0
+		discountApplied := t.DiscountUsed
+		for _, item := range t.Items {
+			if amount := item.Price * float64(item.Quantity); amount > maxAmount {
+				maxAmount = amount
+				category = item.Category
+			}
+			if item.DiscountPct > 0 {
+				discountApplied = true
+			}
+		}
+
+		transactions = append(transactions, UserTransaction{
+			ID:              t.ID,
+			UserID:          t.CustomerID,
+			Amount:          t.TotalAmount,
+			Timestamp:       t.Date,
+			Category:        category,
+			DiscountApplied: discountApplied,
+		})
+	}
+	return transactions
+}
@@ -0,0 +1,118 @@
+// internal/datagen/files.go
+package datagen
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"analitics-service/pkg/export"
+)
+
+// FileFormat определяет формат файлов набора данных
+type FileFormat string
+
+const (
+	FileFormatJSON FileFormat = "json"
+	FileFormatCSV  FileFormat = "csv"
+)
+
+// ParseFileFormat проверяет и возвращает формат файлов набора данных
+func ParseFileFormat(raw string) (FileFormat, error) {
+	switch format := FileFormat(raw); format {
+	case FileFormatJSON, FileFormatCSV:
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported file format %q, expected json or csv", raw)
+}
+
+// WriteFiles записывает набор данных в каталог dir, по файлу на таблицу
+// JSON-файлы содержат массивы сущностей, позиции чеков вложены в чеки; в CSV позиции вынесены
+// в transaction_items.csv, а списки записываются через точку с запятой.
+// Таблицы пользовательского сервиса записываются в users и user_transactions,
+// заложенные ассоциации — в patterns.json в обоих форматах
+func WriteFiles(dir string, format FileFormat, dataset *Dataset) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if format == FileFormatJSON {
+		files := []struct {
+			name  string
+			value interface{}
+		}{
+			{"products", dataset.Products},
+			{"promotions", dataset.Promotions},
+			{"transactions", dataset.Transactions},
+			{"sales", dataset.Sales},
+			{"users", dataset.Users},
+			{"user_transactions", dataset.UserTransactions()},
+		}
+		for _, file := range files {
+			if err := writeJSONFile(filepath.Join(dir, file.name+".json"), file.value); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, t := range append(analyticsTables(dataset), userTables(dataset)...) {
+			if err := writeCSVFile(filepath.Join(dir, t.file+".csv"), t); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writeJSONFile(filepath.Join(dir, "patterns.json"), dataset.Patterns)
+}
+
+// writeJSONFile записывает значение в JSON-файл
+func writeJSONFile(path string, value interface{}) error {
+	return writeFile(path, func(w *bufio.Writer) error {
+		return json.NewEncoder(w).Encode(value)
+	})
+}
+
+// writeCSVFile записывает таблицу в CSV-файл
+func writeCSVFile(path string, t table) error {
+	return writeFile(path, func(w *bufio.Writer) error {
+		writer, err := export.NewWriter(export.FormatCSV, w, t.columns)
+		if err != nil {
+			return err
+		}
+
+		err = t.rows(func(values []interface{}) error {
+			for i, value := range values {
+				if list, ok := value.([]string); ok {
+					values[i] = strings.Join(list, ";")
+				}
+			}
+			return writer.WriteRow(values)
+		})
+		if err != nil {
+			return err
+		}
+		return writer.Close()
+	})
+}
+
+// writeFile создает файл и записывает его содержимое через write; при ошибке файл удаляется
+func writeFile(path string, write func(w *bufio.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	w := bufio.NewWriter(file)
+	err = write(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
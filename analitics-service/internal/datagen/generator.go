@@ -0,0 +1,527 @@
+// internal/datagen/generator.go
+package datagen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/pkg/stats"
+)
+
+// Поведение клиентов, не вынесенное в Config
+const (
+	existingCustomerShare = 0.6  // Доля клиентов, зарегистрированных до начала периода
+	preferredDaypartShare = 0.7  // Вероятность прийти в предпочитаемую часть дня
+	minLifetimeDays       = 14   // Минимальное время между первым днем клиента в периоде и уходом
+	churnFadeDays         = 30   // За сколько дней до ухода клиент начинает приходить реже
+	repeatItemShare       = 0.1  // Вероятность взять две штуки товара
+	promotionDemandBoost  = 20.0 // Скидка в процентах, удваивающая спрос на товар
+)
+
+// weekdayTraffic множитель посещаемости по дням недели, начиная с воскресенья
+var weekdayTraffic = [7]float64{0.85, 0.9, 0.95, 1.0, 1.0, 1.1, 1.2}
+
+// daypartTraffic посещаемость за час в типичных частях дня; для остальных частей дня — 1
+var daypartTraffic = map[string]float64{"breakfast": 1.6, "lunch": 1.8, "afternoon": 0.9, "evening": 0.8}
+
+// cities города клиентов и их доли
+var cities = []struct {
+	name   string
+	weight float64
+}{
+	{"Moscow", 0.8},
+	{"Khimki", 0.06},
+	{"Mytishchi", 0.05},
+	{"Krasnogorsk", 0.05},
+	{"Balashikha", 0.04},
+}
+
+// customer содержит скрытые параметры поведения клиента
+type customer struct {
+	user           *User
+	rate           float64   // Среднее количество визитов в день
+	daypart        int       // Предпочитаемая часть дня
+	couponAffinity float64   // Множитель вероятности использовать купон
+	churnedAt      time.Time // Дата ухода; нулевое время — клиент не уходит
+}
+
+// generator строит набор данных по параметрам; все случайные величины берутся из одного rng,
+// поэтому результат определяется зерном
+type generator struct {
+	cfg            Config
+	rng            *rand.Rand
+	start          time.Time
+	end            time.Time
+	daypartWeights []float64
+	discounts      []entities.Promotion
+	coupons        []entities.Promotion
+}
+
+// Generate создает синтетический набор данных кофейни
+// Клиенты приходят с индивидуальной частотой, зависящей от дня недели и сезона, часть из них уходит,
+// состав чеков зависит от части дня, скидок и заложенных ассоциаций
+func Generate(cfg Config) (*Dataset, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid generator config: %w", err)
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+
+	start := cfg.Start.In(cfg.Location)
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, cfg.Location)
+	g := &generator{
+		cfg:   cfg,
+		rng:   rand.New(rand.NewSource(cfg.Seed)),
+		start: start,
+		end:   start.AddDate(0, 0, cfg.Days),
+	}
+	for _, daypart := range cfg.Dayparts {
+		g.daypartWeights = append(g.daypartWeights, float64(daypartHours(daypart))*trafficPerHour(daypart.Name))
+	}
+
+	dataset := &Dataset{
+		Products: g.products(),
+		Patterns: cfg.Patterns,
+	}
+	g.discounts = g.discountCampaigns()
+	g.coupons = g.couponCampaigns()
+	dataset.Promotions = append(append(dataset.Promotions, g.discounts...), g.coupons...)
+
+	customers := g.customers()
+	for day := g.start; day.Before(g.end); day = day.AddDate(0, 0, 1) {
+		transactions := g.day(day, customers)
+		for _, transaction := range transactions {
+			dataset.Sales = append(dataset.Sales, g.sales(transaction)...)
+		}
+		dataset.Transactions = append(dataset.Transactions, transactions...)
+	}
+
+	dataset.Users = make([]User, 0, len(customers))
+	for _, c := range customers {
+		dataset.Users = append(dataset.Users, *c.user)
+	}
+	return dataset, nil
+}
+
+// products возвращает меню кофейни; все товары заведены за год до начала периода
+func (g *generator) products() []entities.Product {
+	createdAt := g.start.AddDate(-1, 0, 0)
+	products := make([]entities.Product, 0, len(catalog))
+	for i := range catalog {
+		products = append(products, catalog[i].product(createdAt))
+	}
+	return products
+}
+
+// discountCampaigns создает акции со скидкой в процентах на категорию или несколько товаров
+func (g *generator) discountCampaigns() []entities.Promotion {
+	promotions := make([]entities.Promotion, 0, g.cfg.DiscountCampaigns)
+	for i := 0; i < g.cfg.DiscountCampaigns; i++ {
+		startDate, endDate := g.campaignPeriod(7, 14)
+		promotion := entities.Promotion{
+			BaseEntity:    entities.BaseEntity{ID: g.uuid(), CreatedAt: startDate.AddDate(0, 0, -7), UpdatedAt: startDate.AddDate(0, 0, -7)},
+			Mechanic:      entities.MechanicPercentOff,
+			DiscountValue: float64(10 + 5*g.rng.Intn(5)),
+			Channels:      []string{"in_store", "app"},
+			StartDate:     startDate,
+			EndDate:       endDate,
+		}
+
+		if g.rng.Float64() < 0.5 {
+			category := catalog[g.rng.Intn(len(catalog))].category
+			promotion.Categories = []string{category}
+			promotion.Name = fmt.Sprintf("%s week -%.0f%%", category, promotion.DiscountValue)
+		} else {
+			for _, index := range g.rng.Perm(len(catalog))[:1+g.rng.Intn(2)] {
+				promotion.ProductIDs = append(promotion.ProductIDs, catalog[index].id)
+			}
+			promotion.Name = fmt.Sprintf("%s -%.0f%%", catalog[mustCatalogIndex(promotion.ProductIDs[0])].name, promotion.DiscountValue)
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions
+}
+
+// couponCampaigns создает купонные кампании на все меню
+func (g *generator) couponCampaigns() []entities.Promotion {
+	categories := make([]string, 0)
+	seen := make(map[string]bool)
+	for i := range catalog {
+		if !seen[catalog[i].category] {
+			seen[catalog[i].category] = true
+			categories = append(categories, catalog[i].category)
+		}
+	}
+
+	promotions := make([]entities.Promotion, 0, g.cfg.CouponCampaigns)
+	for i := 0; i < g.cfg.CouponCampaigns; i++ {
+		startDate, endDate := g.campaignPeriod(14, 30)
+		code := g.couponCode()
+		promotions = append(promotions, entities.Promotion{
+			BaseEntity:    entities.BaseEntity{ID: g.uuid(), CreatedAt: startDate.AddDate(0, 0, -7), UpdatedAt: startDate.AddDate(0, 0, -7)},
+			Name:          "Coupon " + code,
+			Categories:    categories,
+			Mechanic:      entities.MechanicCouponBased,
+			DiscountValue: float64(10 + 5*g.rng.Intn(3)),
+			CouponCode:    code,
+			Channels:      []string{"in_store", "app"},
+			StartDate:     startDate,
+			EndDate:       endDate,
+		})
+	}
+	return promotions
+}
+
+// campaignPeriod возвращает период кампании длиной от minDays до maxDays дней внутри периода генерации
+func (g *generator) campaignPeriod(minDays, maxDays int) (time.Time, time.Time) {
+	length := minDays + g.rng.Intn(maxDays-minDays+1)
+	if length > g.cfg.Days {
+		length = g.cfg.Days
+	}
+	startDate := g.start.AddDate(0, 0, g.rng.Intn(g.cfg.Days-length+1))
+	return startDate, startDate.AddDate(0, 0, length)
+}
+
+// customers создает клиентов со случайными частотой визитов, предпочитаемой частью дня и датой ухода
+func (g *generator) customers() []*customer {
+	// Частоты визитов распределены по гамма-закону со средним DailyTransactions/Customers:
+	// немного постоянных гостей и много редких
+	meanRate := g.cfg.DailyTransactions / float64(g.cfg.Customers)
+	period := g.end.Sub(g.start)
+
+	customers := make([]*customer, 0, g.cfg.Customers)
+	for i := 0; i < g.cfg.Customers; i++ {
+		var registered time.Time
+		if g.rng.Float64() < existingCustomerShare {
+			registered = g.start.Add(-time.Duration(g.rng.Float64() * float64(365*24*time.Hour)))
+		} else {
+			registered = g.start.Add(time.Duration(g.rng.Float64() * float64(period)))
+		}
+		registered = registered.Truncate(time.Second)
+
+		c := &customer{
+			user:           g.user(i, registered),
+			rate:           meanRate * stats.SampleGamma(g.rng, 2) / 2,
+			daypart:        g.weightedIndex(g.daypartWeights),
+			couponAffinity: stats.SampleGamma(g.rng, 0.5) / 0.5,
+		}
+
+		if g.rng.Float64() < g.cfg.ChurnRate {
+			from := registered
+			if from.Before(g.start) {
+				from = g.start
+			}
+			minLifetime := time.Duration(minLifetimeDays) * 24 * time.Hour
+			if remaining := g.end.Sub(from) - minLifetime; remaining > 0 {
+				c.churnedAt = from.Add(minLifetime + time.Duration(g.rng.Float64()*float64(remaining))).Truncate(time.Second)
+				churnedAt := c.churnedAt
+				c.user.ChurnedAt = &churnedAt
+			}
+		}
+		customers = append(customers, c)
+	}
+	return customers
+}
+
+// user создает профиль клиента для пользовательского сервиса
+func (g *generator) user(index int, registered time.Time) *User {
+	age := int(math.Round(32 + 10*g.rng.NormFloat64()))
+	if age < 18 {
+		age = 18
+	}
+	if age > 75 {
+		age = 75
+	}
+
+	gender := "female"
+	if g.rng.Float64() < 0.48 {
+		gender = "male"
+	}
+
+	weights := make([]float64, len(cities))
+	for i, city := range cities {
+		weights[i] = city.weight
+	}
+
+	return &User{
+		ID:               g.uuid(),
+		Email:            fmt.Sprintf("customer%05d@example.com", index+1),
+		Phone:            fmt.Sprintf("+7 9%02d %03d-%02d-%02d", g.rng.Intn(100), g.rng.Intn(1000), g.rng.Intn(100), g.rng.Intn(100)),
+		Age:              age,
+		Gender:           gender,
+		City:             cities[g.weightedIndex(weights)].name,
+		RegistrationDate: registered,
+		LastActivity:     registered,
+	}
+}
+
+// day генерирует чеки дня, упорядоченные по времени
+func (g *generator) day(day time.Time, customers []*customer) []entities.Transaction {
+	traffic := weekdayTraffic[day.Weekday()] * seasonality(day)
+	dayEnd := day.AddDate(0, 0, 1)
+
+	var transactions []entities.Transaction
+	for _, c := range customers {
+		if !c.user.RegistrationDate.Before(dayEnd) {
+			continue
+		}
+		if !c.churnedAt.IsZero() && !c.churnedAt.After(day) {
+			continue
+		}
+
+		rate := c.rate * traffic
+		if !c.churnedAt.IsZero() {
+			// Перед уходом клиент приходит все реже
+			if daysLeft := c.churnedAt.Sub(day).Hours() / 24; daysLeft < churnFadeDays {
+				rate *= math.Max(daysLeft/churnFadeDays, 0.1)
+			}
+		}
+
+		for visits := poisson(g.rng, rate); visits > 0; visits-- {
+			date := g.visitTime(day, c)
+			if date.Before(c.user.RegistrationDate) || (!c.churnedAt.IsZero() && !date.Before(c.churnedAt)) {
+				continue
+			}
+			transactions = append(transactions, g.transaction(c, date))
+			if date.After(c.user.LastActivity) {
+				c.user.LastActivity = date
+			}
+		}
+	}
+
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].Date.Before(transactions[j].Date)
+	})
+	return transactions
+}
+
+// visitTime выбирает время визита клиента в течение дня
+func (g *generator) visitTime(day time.Time, c *customer) time.Time {
+	index := c.daypart
+	if g.rng.Float64() >= preferredDaypartShare {
+		index = g.weightedIndex(g.daypartWeights)
+	}
+
+	daypart := g.cfg.Dayparts[index]
+	hour := (daypart.StartHour + g.rng.Intn(daypartHours(daypart))) % 24
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(g.rng.Intn(3600))*time.Second)
+}
+
+// transaction составляет чек клиента: товары по спросу части дня и скидкам, заложенные ассоциации и купон
+func (g *generator) transaction(c *customer, date time.Time) entities.Transaction {
+	daypart, _ := entities.DaypartAt(g.cfg.Dayparts, date.Hour())
+	discounts := g.productDiscounts(date)
+
+	quantities := make(map[int]int)
+	var order []int
+	add := func(index int) {
+		if quantities[index] == 0 {
+			order = append(order, index)
+		}
+		quantities[index]++
+	}
+
+	for _, pattern := range g.cfg.Patterns {
+		if g.rng.Float64() < pattern.Frequency {
+			for _, productID := range pattern.Antecedent {
+				if index := mustCatalogIndex(productID); quantities[index] == 0 {
+					add(index)
+				}
+			}
+		}
+	}
+
+	weights := make([]float64, len(catalog))
+	for i := range catalog {
+		weights[i] = catalog[i].demand(daypart) * (1 + discounts[i]/promotionDemandBoost)
+	}
+	for size := 1 + poisson(g.rng, g.cfg.MeanBasketSize-1); len(order) < size; {
+		add(g.weightedIndex(weights))
+	}
+
+	for _, pattern := range g.cfg.Patterns {
+		if containsAll(quantities, pattern.Antecedent) && g.rng.Float64() < pattern.Confidence {
+			if index := mustCatalogIndex(pattern.Consequent); quantities[index] == 0 {
+				add(index)
+			}
+		}
+	}
+
+	transaction := entities.Transaction{
+		BaseEntity: entities.BaseEntity{ID: g.uuid(), CreatedAt: date, UpdatedAt: date},
+		CustomerID: c.user.ID,
+		Date:       date,
+	}
+
+	couponDiscount := 0.0
+	if coupon := g.activeCoupon(date); coupon != nil && g.rng.Float64() < math.Min(g.cfg.CouponRedemptionRate*c.couponAffinity, 1) {
+		transaction.DiscountUsed = true
+		transaction.CouponCode = coupon.CouponCode
+		couponDiscount = coupon.DiscountValue
+	}
+
+	for _, index := range order {
+		item := catalog[index].item()
+		item.Quantity = quantities[index]
+		if item.Quantity == 1 && g.rng.Float64() < repeatItemShare {
+			item.Quantity = 2
+		}
+		item.DiscountPct = round2(100 * (1 - (1-discounts[index]/100)*(1-couponDiscount/100)))
+
+		transaction.Items = append(transaction.Items, item)
+		transaction.TotalAmount += round2(item.Price * float64(item.Quantity) * (1 - item.DiscountPct/100))
+	}
+	transaction.TotalAmount = round2(transaction.TotalAmount)
+	return transaction
+}
+
+// sales разворачивает чек в продажи отдельных товаров
+func (g *generator) sales(transaction entities.Transaction) []entities.Sale {
+	sales := make([]entities.Sale, 0, len(transaction.Items))
+	for _, item := range transaction.Items {
+		sales = append(sales, entities.Sale{
+			BaseEntity:    entities.BaseEntity{ID: g.uuid(), CreatedAt: transaction.Date, UpdatedAt: transaction.Date},
+			ProductID:     item.ProductID,
+			Quantity:      item.Quantity,
+			Price:         item.Price,
+			DiscountRate:  item.DiscountPct,
+			PurchaseDate:  transaction.Date,
+			CustomerID:    transaction.CustomerID,
+			TransactionID: transaction.ID,
+		})
+	}
+	return sales
+}
+
+// productDiscounts возвращает наибольшую скидку акций на каждый товар каталога в момент date
+func (g *generator) productDiscounts(date time.Time) []float64 {
+	discounts := make([]float64, len(catalog))
+	for i := range g.discounts {
+		promotion := &g.discounts[i]
+		if date.Before(promotion.StartDate) || !date.Before(promotion.EndDate) {
+			continue
+		}
+		for index := range catalog {
+			if promotion.Covers(entities.Product{BaseEntity: entities.BaseEntity{ID: catalog[index].id}, Category: catalog[index].category}) {
+				discounts[index] = math.Max(discounts[index], promotion.DiscountValue)
+			}
+		}
+	}
+	return discounts
+}
+
+// activeCoupon возвращает купонную кампанию, действующую в момент date, или nil
+func (g *generator) activeCoupon(date time.Time) *entities.Promotion {
+	var active []*entities.Promotion
+	for i := range g.coupons {
+		if !date.Before(g.coupons[i].StartDate) && date.Before(g.coupons[i].EndDate) {
+			active = append(active, &g.coupons[i])
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	return active[g.rng.Intn(len(active))]
+}
+
+// weightedIndex выбирает индекс с вероятностью, пропорциональной весу
+func (g *generator) weightedIndex(weights []float64) int {
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+
+	target := g.rng.Float64() * total
+	for i, weight := range weights {
+		if target < weight {
+			return i
+		}
+		target -= weight
+	}
+	return len(weights) - 1
+}
+
+// uuid возвращает случайный UUID версии 4, определяемый зерном генератора
+func (g *generator) uuid() string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], g.rng.Uint64())
+	binary.BigEndian.PutUint64(b[8:], g.rng.Uint64())
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// couponCode возвращает код купона из заглавных латинских букв и цифр
+func (g *generator) couponCode() string {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	code := make([]byte, 8)
+	for i := range code {
+		code[i] = alphabet[g.rng.Intn(len(alphabet))]
+	}
+	return string(code)
+}
+
+// seasonality возвращает сезонный множитель посещаемости: зимой кофе пьют чаще, чем летом
+func seasonality(day time.Time) float64 {
+	return 1 + 0.15*math.Cos(2*math.Pi*float64(day.YearDay()-15)/365)
+}
+
+// daypartHours возвращает количество часов в части дня
+func daypartHours(daypart entities.Daypart) int {
+	if daypart.StartHour < daypart.EndHour {
+		return daypart.EndHour - daypart.StartHour
+	}
+	return 24 - daypart.StartHour + daypart.EndHour
+}
+
+// trafficPerHour возвращает относительную посещаемость за час в части дня
+func trafficPerHour(daypart string) float64 {
+	if traffic, ok := daypartTraffic[daypart]; ok {
+		return traffic
+	}
+	return 1
+}
+
+// poisson генерирует случайную величину из распределения Пуассона методом Кнута
+func poisson(rng *rand.Rand, lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+
+	limit := math.Exp(-lambda)
+	k := 0
+	for p := rng.Float64(); p > limit; p *= rng.Float64() {
+		k++
+	}
+	return k
+}
+
+// containsAll проверяет, что в корзине есть все указанные товары
+func containsAll(quantities map[int]int, productIDs []string) bool {
+	for _, productID := range productIDs {
+		if quantities[mustCatalogIndex(productID)] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// mustCatalogIndex возвращает позицию товара в каталоге; ID проверены в Config.Validate
+func mustCatalogIndex(productID string) int {
+	index, ok := catalogIndex(productID)
+	if !ok {
+		panic("datagen: unknown product " + productID)
+	}
+	return index
+}
+
+// round2 округляет сумму до копеек
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
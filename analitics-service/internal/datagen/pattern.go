@@ -0,0 +1,63 @@
+// internal/datagen/pattern.go
+package datagen
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Pattern описывает ассоциацию «условие → следствие», которую генератор закладывает в корзины
+// Наблюдаемая достоверность правила не ниже Confidence: следствие попадает в чек с условием
+// и само по себе, поэтому тесты алгоритмов проверяют ее через Dataset.MeasurePattern
+type Pattern struct {
+	Antecedent []string `json:"antecedent"` // ID товаров условия
+	Consequent string   `json:"consequent"` // ID товара следствия
+	Frequency  float64  `json:"frequency"`  // Доля чеков, в которые добавляется условие
+	Confidence float64  `json:"confidence"` // Вероятность добавить следствие в чек с условием
+}
+
+// PatternStats содержит наблюдаемые метрики ассоциации в сгенерированных чеках
+type PatternStats struct {
+	Support    float64 `json:"support"`    // Доля чеков с условием и следствием
+	Confidence float64 `json:"confidence"` // Доля чеков с условием, содержащих следствие
+	Lift       float64 `json:"lift"`       // Отношение достоверности к доле чеков со следствием
+}
+
+// DefaultPatterns возвращает ассоциации, закладываемые по умолчанию
+func DefaultPatterns() []Pattern {
+	return []Pattern{
+		{Antecedent: []string{"prod-cappuccino"}, Consequent: "prod-croissant", Frequency: 0.06, Confidence: 0.6},
+		{Antecedent: []string{"prod-matcha-latte"}, Consequent: "prod-cheesecake", Frequency: 0.03, Confidence: 0.5},
+		{Antecedent: []string{"prod-flat-white", "prod-avocado-toast"}, Consequent: "prod-orange-juice", Frequency: 0.02, Confidence: 0.55},
+	}
+}
+
+// validate проверяет, что ассоциация ссылается на товары каталога и ее вероятности корректны
+func (p *Pattern) validate() error {
+	if len(p.Antecedent) == 0 {
+		return errors.New("pattern antecedent is required")
+	}
+
+	if _, ok := catalogIndex(p.Consequent); !ok {
+		return fmt.Errorf("unknown product %q", p.Consequent)
+	}
+
+	for _, productID := range p.Antecedent {
+		if _, ok := catalogIndex(productID); !ok {
+			return fmt.Errorf("unknown product %q", productID)
+		}
+		if productID == p.Consequent {
+			return fmt.Errorf("consequent %s cannot be part of the antecedent", p.Consequent)
+		}
+	}
+
+	if p.Frequency < 0 || p.Frequency > 1 {
+		return fmt.Errorf("frequency must be between 0 and 1, got %f", p.Frequency)
+	}
+
+	if p.Confidence < 0 || p.Confidence > 1 {
+		return fmt.Errorf("confidence must be between 0 and 1, got %f", p.Confidence)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// internal/datagen/pattern_test.go
+package datagen_test
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"analitics-service/internal/datagen"
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+const (
+	// Пороги ниже поддержки и достоверности самой редкой заложенной ассоциации
+	testMinSupport    = 0.005
+	testMinConfidence = 0.3
+
+	// Относительное расхождение лифта правила с лифтом, посчитанным по тем же чекам
+	testLiftTolerance = 0.01
+	// Лифт, начиная с которого ассоциация считается заложенной, а не случайной
+	testMinPlantedLift = 1.5
+)
+
+// TestAprioriFindsPlantedPatterns проверяет, что Apriori находит ассоциации, заложенные генератором,
+// с лифтом, наблюдаемым в сгенерированных чеках
+func TestAprioriFindsPlantedPatterns(t *testing.T) {
+	cfg := datagen.DefaultConfig()
+	cfg.Seed = 42
+	cfg.Start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg.Days = 60
+
+	dataset, err := datagen.Generate(cfg)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	apriori := services.NewAprioriService(logger.NewLogger("ERROR"))
+	rules, err := apriori.AnalyzeTransactions(context.Background(), dataset.Transactions, testMinSupport, testMinConfidence)
+	if err != nil {
+		t.Fatalf("AnalyzeTransactions() error = %v", err)
+	}
+
+	for _, pattern := range dataset.Patterns {
+		name := strings.Join(pattern.Antecedent, "+") + " -> " + pattern.Consequent
+		t.Run(name, func(t *testing.T) {
+			expected := dataset.MeasurePattern(pattern)
+			if expected.Lift < testMinPlantedLift {
+				t.Fatalf("planted lift = %.3f, want at least %.1f", expected.Lift, testMinPlantedLift)
+			}
+
+			rule, ok := findRule(rules, pattern)
+			if !ok {
+				t.Fatalf("rule not found among %d rules", len(rules))
+			}
+
+			if math.Abs(rule.Lift-expected.Lift) > testLiftTolerance*expected.Lift {
+				t.Errorf("lift = %.3f, want %.3f", rule.Lift, expected.Lift)
+			}
+			if math.Abs(rule.Confidence-expected.Confidence) > testLiftTolerance*expected.Confidence {
+				t.Errorf("confidence = %.3f, want %.3f", rule.Confidence, expected.Confidence)
+			}
+		})
+	}
+}
+
+// findRule возвращает правило, условие и следствие которого совпадают с ассоциацией
+func findRule(rules []entities.AssociationRule, pattern datagen.Pattern) (entities.AssociationRule, bool) {
+	antecedent := append([]string(nil), pattern.Antecedent...)
+	sort.Strings(antecedent)
+
+	for _, rule := range rules {
+		if len(rule.Consequent) != 1 || rule.Consequent[0].ProductID != pattern.Consequent {
+			continue
+		}
+		if strings.Join(productIDs(rule.Antecedent), ",") == strings.Join(antecedent, ",") {
+			return rule, true
+		}
+	}
+	return entities.AssociationRule{}, false
+}
+
+// productIDs возвращает отсортированные ID товаров
+func productIDs(items []entities.Item) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,109 @@
+// internal/datagen/postgres.go
+package datagen
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"analitics-service/internal/infrastructure/postgres"
+)
+
+// userSchema описывает таблицы пользовательского сервиса, которые читают его репозитории
+const userSchema = `
+CREATE TABLE IF NOT EXISTS public.users (
+	id                UUID PRIMARY KEY,
+	email             TEXT NOT NULL UNIQUE,
+	phone             TEXT NOT NULL DEFAULT '',
+	age               INTEGER NOT NULL DEFAULT 0,
+	gender            TEXT NOT NULL DEFAULT '',
+	city              TEXT NOT NULL DEFAULT '',
+	registration_date TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	last_activity     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS public.transactions (
+	id               UUID PRIMARY KEY,
+	user_id          UUID NOT NULL REFERENCES public.users (id) ON DELETE CASCADE,
+	amount           NUMERIC(12, 2) NOT NULL,
+	timestamp        TIMESTAMPTZ NOT NULL,
+	category         TEXT NOT NULL DEFAULT '',
+	discount_applied BOOLEAN NOT NULL DEFAULT FALSE
+);
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id ON public.transactions (user_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_transactions_timestamp ON public.transactions (timestamp);
+`
+
+// LoadAnalytics создает недостающие таблицы аналитического сервиса и загружает в них товары, акции,
+// чеки и продажи одной транзакцией. При reset таблицы предварительно очищаются,
+// иначе повторная загрузка того же набора завершится ошибкой уникальности
+func LoadAnalytics(ctx context.Context, db *sql.DB, dataset *Dataset, reset bool) error {
+	return load(ctx, db, postgres.SourceSchema, analyticsTables(dataset), reset)
+}
+
+// LoadUsers создает недостающие таблицы пользовательского сервиса и загружает в них клиентов и их чеки
+func LoadUsers(ctx context.Context, db *sql.DB, dataset *Dataset, reset bool) error {
+	return load(ctx, db, userSchema, userTables(dataset), reset)
+}
+
+// load загружает таблицы через COPY в одной транзакции
+func load(ctx context.Context, db *sql.DB, schema string, tables []table, reset bool) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	if reset {
+		names := make([]string, len(tables))
+		for i, t := range tables {
+			names[i] = "public." + t.name
+		}
+		if _, err := tx.ExecContext(ctx, "TRUNCATE "+strings.Join(names, ", ")+" CASCADE"); err != nil {
+			return fmt.Errorf("failed to truncate tables: %w", err)
+		}
+	}
+
+	for _, t := range tables {
+		if err := copyTable(ctx, tx, t); err != nil {
+			return fmt.Errorf("failed to load %s: %w", t.name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// copyTable записывает строки таблицы через COPY FROM STDIN
+func copyTable(ctx context.Context, tx *sql.Tx, t table) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyInSchema("public", t.name, t.columnNames()...))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	err = t.rows(func(values []interface{}) error {
+		for i, value := range values {
+			if list, ok := value.([]string); ok {
+				values[i] = pq.Array(list)
+			}
+		}
+		_, err := stmt.ExecContext(ctx, values...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx)
+	return err
+}
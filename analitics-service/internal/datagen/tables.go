@@ -0,0 +1,220 @@
+// internal/datagen/tables.go
+package datagen
+
+import (
+	"analitics-service/pkg/export"
+)
+
+// table описывает таблицу набора данных, общую для выгрузки в CSV и загрузки в Postgres
+// Значения строк передаются в порядке колонок; списки передаются как []string
+type table struct {
+	name    string // Таблица в базе сервиса
+	file    string // Имя файла без расширения
+	columns []export.Column
+	rows    func(write func(values []interface{}) error) error
+}
+
+// columnNames возвращает имена колонок таблицы
+func (t *table) columnNames() []string {
+	names := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+// analyticsTables возвращает таблицы аналитического сервиса в порядке загрузки
+func analyticsTables(d *Dataset) []table {
+	return []table{
+		{
+			name: "products",
+			file: "products",
+			columns: []export.Column{
+				{Name: "id", Type: export.String},
+				{Name: "name", Type: export.String},
+				{Name: "category", Type: export.String},
+				{Name: "category_id", Type: export.String},
+				{Name: "sub_category", Type: export.String},
+				{Name: "price", Type: export.Float},
+				{Name: "cost", Type: export.Float},
+				{Name: "description", Type: export.String},
+				{Name: "image_url", Type: export.String},
+				{Name: "is_active", Type: export.Bool},
+				{Name: "created_at", Type: export.Time},
+				{Name: "updated_at", Type: export.Time},
+			},
+			rows: func(write func([]interface{}) error) error {
+				for _, p := range d.Products {
+					if err := write([]interface{}{
+						p.ID, p.Name, p.Category, p.CategoryID, p.SubCategory, p.Price, p.Cost,
+						p.Description, p.ImageURL, p.IsActive, p.CreatedAt, p.UpdatedAt,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "promotions",
+			file: "promotions",
+			columns: []export.Column{
+				{Name: "id", Type: export.String},
+				{Name: "name", Type: export.String},
+				{Name: "description", Type: export.String},
+				{Name: "product_ids", Type: export.String},
+				{Name: "categories", Type: export.String},
+				{Name: "mechanic", Type: export.String},
+				{Name: "discount_value", Type: export.Float},
+				{Name: "buy_quantity", Type: export.Int},
+				{Name: "get_quantity", Type: export.Int},
+				{Name: "coupon_code", Type: export.String},
+				{Name: "channels", Type: export.String},
+				{Name: "start_date", Type: export.Time},
+				{Name: "end_date", Type: export.Time},
+				{Name: "created_at", Type: export.Time},
+				{Name: "updated_at", Type: export.Time},
+			},
+			rows: func(write func([]interface{}) error) error {
+				for _, p := range d.Promotions {
+					if err := write([]interface{}{
+						p.ID, p.Name, p.Description, p.ProductIDs, p.Categories, string(p.Mechanic), p.DiscountValue,
+						p.BuyQuantity, p.GetQuantity, p.CouponCode, p.Channels, p.StartDate, p.EndDate, p.CreatedAt, p.UpdatedAt,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "transactions",
+			file: "transactions",
+			columns: []export.Column{
+				{Name: "id", Type: export.String},
+				{Name: "customer_id", Type: export.String},
+				{Name: "date", Type: export.Time},
+				{Name: "total_amount", Type: export.Float},
+				{Name: "discount_used", Type: export.Bool},
+				{Name: "coupon_code", Type: export.String},
+				{Name: "created_at", Type: export.Time},
+				{Name: "updated_at", Type: export.Time},
+			},
+			rows: func(write func([]interface{}) error) error {
+				for _, t := range d.Transactions {
+					if err := write([]interface{}{
+						t.ID, t.CustomerID, t.Date, t.TotalAmount, t.DiscountUsed, t.CouponCode, t.CreatedAt, t.UpdatedAt,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "transaction_items",
+			file: "transaction_items",
+			columns: []export.Column{
+				{Name: "transaction_id", Type: export.String},
+				{Name: "product_id", Type: export.String},
+				{Name: "name", Type: export.String},
+				{Name: "category_id", Type: export.String},
+				{Name: "category", Type: export.String},
+				{Name: "price", Type: export.Float},
+				{Name: "quantity", Type: export.Int},
+				{Name: "discount_pct", Type: export.Float},
+			},
+			rows: func(write func([]interface{}) error) error {
+				for _, t := range d.Transactions {
+					for _, item := range t.Items {
+						if err := write([]interface{}{
+							t.ID, item.ProductID, item.Name, item.CategoryID, item.Category, item.Price, item.Quantity, item.DiscountPct,
+						}); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "sales",
+			file: "sales",
+			columns: []export.Column{
+				{Name: "id", Type: export.String},
+				{Name: "product_id", Type: export.String},
+				{Name: "quantity", Type: export.Int},
+				{Name: "price", Type: export.Float},
+				{Name: "discount_rate", Type: export.Float},
+				{Name: "purchase_date", Type: export.Time},
+				{Name: "customer_id", Type: export.String},
+				{Name: "transaction_id", Type: export.String},
+				{Name: "created_at", Type: export.Time},
+				{Name: "updated_at", Type: export.Time},
+			},
+			rows: func(write func([]interface{}) error) error {
+				for _, s := range d.Sales {
+					if err := write([]interface{}{
+						s.ID, s.ProductID, s.Quantity, s.Price, s.DiscountRate, s.PurchaseDate,
+						s.CustomerID, s.TransactionID, s.CreatedAt, s.UpdatedAt,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// userTables возвращает таблицы пользовательского сервиса в порядке загрузки
+func userTables(d *Dataset) []table {
+	return []table{
+		{
+			name: "users",
+			file: "users",
+			columns: []export.Column{
+				{Name: "id", Type: export.String},
+				{Name: "email", Type: export.String},
+				{Name: "phone", Type: export.String},
+				{Name: "age", Type: export.Int},
+				{Name: "gender", Type: export.String},
+				{Name: "city", Type: export.String},
+				{Name: "registration_date", Type: export.Time},
+				{Name: "last_activity", Type: export.Time},
+			},
+			rows: func(write func([]interface{}) error) error {
+				for _, u := range d.Users {
+					if err := write([]interface{}{
+						u.ID, u.Email, u.Phone, u.Age, u.Gender, u.City, u.RegistrationDate, u.LastActivity,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "transactions",
+			file: "user_transactions",
+			columns: []export.Column{
+				{Name: "id", Type: export.String},
+				{Name: "user_id", Type: export.String},
+				{Name: "amount", Type: export.Float},
+				{Name: "timestamp", Type: export.Time},
+				{Name: "category", Type: export.String},
+				{Name: "discount_applied", Type: export.Bool},
+			},
+			rows: func(write func([]interface{}) error) error {
+				for _, t := range d.UserTransactions() {
+					if err := write([]interface{}{
+						t.ID, t.UserID, t.Amount, t.Timestamp, t.Category, t.DiscountApplied,
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	}
+}
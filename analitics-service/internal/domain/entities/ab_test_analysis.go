@@ -0,0 +1,19 @@
+// internal/domain/entities/ab_test_analysis.go
+package entities
+
+import (
+	"time"
+)
+
+// ABTestAnalysis содержит регрессию лифта A/B тестов со скидкой на размер скидки, базовую цену и длительность теста
+type ABTestAnalysis struct {
+	TestsAnalyzed     int       `json:"tests_analyzed"`
+	DiscountCoeff     float64   `json:"discount_coeff"`
+	BasePriceCoeff    float64   `json:"base_price_coeff"`
+	DurationCoeff     float64   `json:"duration_coeff"`
+	InterceptCoeff    float64   `json:"intercept_coeff"`
+	RSquared          float64   `json:"r_squared"`
+	OptimalDiscount   float64   `json:"optimal_discount"` // Скидка, максимизирующая прогнозируемую выручку (от 0 до 1)
+	AnalysisTimestamp time.Time `json:"analysis_timestamp"`
+	Recommendations   []string  `json:"recommendations"`
+}
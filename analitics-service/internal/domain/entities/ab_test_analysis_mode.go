@@ -0,0 +1,10 @@
+// internal/domain/entities/ab_test_analysis_mode.go
+package entities
+
+// ABTestAnalysisMode определяет способ оценки A/B теста
+type ABTestAnalysisMode string
+
+const (
+	AnalysisModeFrequentist ABTestAnalysisMode = "frequentist"
+	AnalysisModeBayesian    ABTestAnalysisMode = "bayesian"
+)
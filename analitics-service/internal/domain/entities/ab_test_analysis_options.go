@@ -0,0 +1,17 @@
+// internal/domain/entities/ab_test_analysis_options.go
+package entities
+
+// ABTestAnalysisOptions содержит параметры оценки A/B теста для обоих режимов
+// Режим выбирается по полю AnalysisMode теста
+type ABTestAnalysisOptions struct {
+	Frequentist ABTestEvaluationConfig   `json:"frequentist"`
+	Bayesian    BayesianEvaluationConfig `json:"bayesian"`
+}
+
+// DefaultABTestAnalysisOptions возвращает параметры оценки по умолчанию
+func DefaultABTestAnalysisOptions() ABTestAnalysisOptions {
+	return ABTestAnalysisOptions{
+		Frequentist: DefaultABTestEvaluationConfig(),
+		Bayesian:    DefaultBayesianEvaluationConfig(),
+	}
+}
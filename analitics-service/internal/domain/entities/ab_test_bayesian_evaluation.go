@@ -0,0 +1,24 @@
+// internal/domain/entities/ab_test_bayesian_evaluation.go
+package entities
+
+import "time"
+
+// ABTestBayesianEvaluation содержит результат байесовской оценки A/B теста
+type ABTestBayesianEvaluation struct {
+	TestID      string                   `json:"test_id"`
+	EvaluatedAt time.Time                `json:"evaluated_at"`
+	Config      BayesianEvaluationConfig `json:"config"`
+	Metrics     []BayesianMetricResult   `json:"metrics"`
+	Decision    ABTestDecision           `json:"decision"`
+	StopTest    bool                     `json:"stop_test"`
+}
+
+// MetricResult возвращает результат оценки указанной метрики
+func (e *ABTestBayesianEvaluation) MetricResult(metric ABTestMetric) (BayesianMetricResult, bool) {
+	for _, m := range e.Metrics {
+		if m.Metric == metric {
+			return m, true
+		}
+	}
+	return BayesianMetricResult{}, false
+}
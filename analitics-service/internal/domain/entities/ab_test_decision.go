@@ -0,0 +1,11 @@
+// internal/domain/entities/ab_test_decision.go
+package entities
+
+// ABTestDecision представляет решение по A/B тесту на основе правила остановки
+type ABTestDecision string
+
+const (
+	DecisionShipTest    ABTestDecision = "ship_test"
+	DecisionKeepControl ABTestDecision = "keep_control"
+	DecisionContinue    ABTestDecision = "continue"
+)
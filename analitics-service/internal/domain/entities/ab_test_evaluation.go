@@ -0,0 +1,23 @@
+// internal/domain/entities/ab_test_evaluation.go
+package entities
+
+import "time"
+
+// ABTestEvaluation содержит подробный результат частотной оценки A/B теста
+type ABTestEvaluation struct {
+	TestID      string                 `json:"test_id"`
+	EvaluatedAt time.Time              `json:"evaluated_at"`
+	Config      ABTestEvaluationConfig `json:"config"`
+	Metrics     []MetricTestResult     `json:"metrics"`
+	SampleRatio SampleRatioCheck       `json:"sample_ratio"`
+}
+
+// MetricResult возвращает результат проверки указанной метрики
+func (e *ABTestEvaluation) MetricResult(metric ABTestMetric) (MetricTestResult, bool) {
+	for _, m := range e.Metrics {
+		if m.Metric == metric {
+			return m, true
+		}
+	}
+	return MetricTestResult{}, false
+}
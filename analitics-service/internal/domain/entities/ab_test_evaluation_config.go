@@ -0,0 +1,59 @@
+// internal/domain/entities/ab_test_evaluation_config.go
+package entities
+
+import (
+	"fmt"
+)
+
+// ABTestEvaluationConfig содержит параметры частотной оценки A/B теста
+type ABTestEvaluationConfig struct {
+	Alpha               float64      `json:"alpha"`                // Уровень значимости
+	TargetPower         float64      `json:"target_power"`         // Целевая мощность для расчета MDE
+	PrimaryMetric       ABTestMetric `json:"primary_metric"`       // Метрика, определяющая итог теста
+	ExpectedTestShare   float64      `json:"expected_test_share"`  // Ожидаемая доля тестовой группы
+	SRMAlpha            float64      `json:"srm_alpha"`            // Уровень значимости проверки SRM
+	BootstrapIterations int          `json:"bootstrap_iterations"` // 0 отключает бутстреп
+	Seed                int64        `json:"seed"`
+}
+
+// DefaultABTestEvaluationConfig возвращает параметры оценки по умолчанию
+func DefaultABTestEvaluationConfig() ABTestEvaluationConfig {
+	return ABTestEvaluationConfig{
+		Alpha:               0.05,
+		TargetPower:         0.8,
+		PrimaryMetric:       MetricConversion,
+		ExpectedTestShare:   0.5,
+		SRMAlpha:            0.001,
+		BootstrapIterations: 2000,
+		Seed:                1,
+	}
+}
+
+// Validate проверяет корректность данных в структуре ABTestEvaluationConfig
+func (c *ABTestEvaluationConfig) Validate() error {
+	if c.Alpha <= 0 || c.Alpha >= 1 {
+		return fmt.Errorf("alpha must be between 0 and 1, got %f", c.Alpha)
+	}
+
+	if c.TargetPower <= 0 || c.TargetPower >= 1 {
+		return fmt.Errorf("target power must be between 0 and 1, got %f", c.TargetPower)
+	}
+
+	if !isValidABTestMetric(c.PrimaryMetric) {
+		return fmt.Errorf("invalid primary metric: %s", c.PrimaryMetric)
+	}
+
+	if c.ExpectedTestShare <= 0 || c.ExpectedTestShare >= 1 {
+		return fmt.Errorf("expected test share must be between 0 and 1, got %f", c.ExpectedTestShare)
+	}
+
+	if c.SRMAlpha <= 0 || c.SRMAlpha >= 1 {
+		return fmt.Errorf("SRM alpha must be between 0 and 1, got %f", c.SRMAlpha)
+	}
+
+	if c.BootstrapIterations < 0 {
+		return fmt.Errorf("bootstrap iterations cannot be negative, got %d", c.BootstrapIterations)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+// internal/domain/entities/ab_test_metric.go
+package entities
+
+// ABTestMetric представляет метрику, по которой оценивается A/B тест
+type ABTestMetric string
+
+const (
+	MetricConversion  ABTestMetric = "conversion"
+	MetricAvgPurchase ABTestMetric = "avg_purchase"
+	MetricRevenue     ABTestMetric = "revenue"
+)
+
+// isValidABTestMetric проверяет, является ли метрика допустимой
+func isValidABTestMetric(m ABTestMetric) bool {
+	return m == MetricConversion || m == MetricAvgPurchase || m == MetricRevenue
+}
@@ -6,14 +6,21 @@ import (
 )
 
 // ABTestResult представляет результат A/B теста
+// Significance содержит p-value основной метрики и заполняется только в частотном режиме,
+// ProbabilityToBeatControl — вероятность превосходства теста над контролем и заполняется только в байесовском
 type ABTestResult struct {
-	TestID       string         `json:"test_id"`
-	StartDate    time.Time      `json:"start_date"`
-	EndDate      time.Time      `json:"end_date"`
-	Description  string         `json:"description"`
-	ControlGroup GroupStats     `json:"control_group"`
-	TestGroup    TestGroupStats `json:"test_group"`
-	Lift         float64        `json:"lift"`
-	Significance float64        `json:"significance"`
-	IsSignificant bool          `json:"is_significant"`
-}
\ No newline at end of file
+	TestID                   string                    `json:"test_id"`
+	StartDate                time.Time                 `json:"start_date"`
+	EndDate                  time.Time                 `json:"end_date"`
+	Description              string                    `json:"description"`
+	ProductIDs               []string                  `json:"product_ids,omitempty"` // Товары, продажи которых учитываются в тесте
+	AnalysisMode             ABTestAnalysisMode        `json:"analysis_mode,omitempty"`
+	ControlGroup             GroupStats                `json:"control_group"`
+	TestGroup                TestGroupStats            `json:"test_group"`
+	Lift                     float64                   `json:"lift"`
+	Significance             float64                   `json:"significance"`
+	ProbabilityToBeatControl float64                   `json:"probability_to_beat_control,omitempty"`
+	IsSignificant            bool                      `json:"is_significant"`
+	Evaluation               *ABTestEvaluation         `json:"evaluation,omitempty"`
+	BayesianEvaluation       *ABTestBayesianEvaluation `json:"bayesian_evaluation,omitempty"`
+}
@@ -0,0 +1,14 @@
+// internal/domain/entities/ab_test_samples.go
+package entities
+
+// GroupSamples содержит наблюдения по отдельным пользователям группы A/B теста
+type GroupSamples struct {
+	PurchaseAmounts []float64 `json:"purchase_amounts"` // Суммы покупок конвертировавшихся пользователей
+	RevenuePerUser  []float64 `json:"revenue_per_user"` // Выручка на каждого пользователя группы, включая нули
+}
+
+// ABTestSamples содержит наблюдения контрольной и тестовой групп для бутстрепа
+type ABTestSamples struct {
+	Control GroupSamples `json:"control"`
+	Test    GroupSamples `json:"test"`
+}
@@ -0,0 +1,8 @@
+// internal/domain/entities/abc_segmentation_payload.go
+package entities
+
+// ABCSegmentationPayload содержимое событий потока abc_segmentation
+type ABCSegmentationPayload struct {
+	Segments []ProductFullSegmentation `json:"segments"`          // Сегментация всех товаров
+	Changes  []SegmentChange           `json:"changes,omitempty"` // Изменения относительно предыдущей сегментации, только в regenerated
+}
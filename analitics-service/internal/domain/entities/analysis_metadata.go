@@ -12,6 +12,7 @@ type AnalysisMetadata struct {
 	AnalysisDate time.Time `json:"analysis_date"`
 	PeriodStart  time.Time `json:"period_start"`
 	PeriodEnd    time.Time `json:"period_end"`
+	RunID        string    `json:"run_id,omitempty"` // Запуск анализа, по которому можно восстановить параметры и входные данные
 }
 
 // Validate проверяет корректность данных в структуре AnalysisMetadata
@@ -0,0 +1,28 @@
+// internal/domain/entities/analysis_run.go
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AnalysisRun представляет запись о запуске анализа, достаточную для его воспроизведения
+// Результаты анализа ссылаются на запуск через RunID
+type AnalysisRun struct {
+	ID             string          `json:"id"`
+	Type           AnalysisType    `json:"type"`
+	Parameters     json.RawMessage `json:"parameters"` // Полный набор параметров, включая период данных
+	CodeVersion    string          `json:"code_version"`
+	InputRowCounts map[string]int  `json:"input_row_counts"` // Количество строк по каждому источнику данных
+	InputHash      string          `json:"input_hash"`       // SHA-256 входных данных, не зависит от порядка строк
+	OutputCount    int             `json:"output_count"`
+	StartedAt      time.Time       `json:"started_at"`
+	FinishedAt     time.Time       `json:"finished_at"`
+
+	// Итоги проверки качества входных данных; анализ выполняется по очищенным данным
+	DataQuality *DataQualityReport `json:"data_quality,omitempty"`
+
+	// Заполняются только для повторных запусков
+	RerunOf              string `json:"rerun_of,omitempty"`
+	InputMatchesOriginal *bool  `json:"input_matches_original,omitempty"`
+}
@@ -0,0 +1,20 @@
+// internal/domain/entities/analysis_type.go
+package entities
+
+// AnalysisType определяет вид воспроизводимого анализа
+type AnalysisType string
+
+const (
+	AnalysisABC                     AnalysisType = "abc"
+	AnalysisAssociationRules        AnalysisType = "association_rules"
+	AnalysisDiscountRecommendations AnalysisType = "discount_recommendations"
+)
+
+// IsValid проверяет, является ли вид анализа допустимым
+func (t AnalysisType) IsValid() bool {
+	switch t {
+	case AnalysisABC, AnalysisAssociationRules, AnalysisDiscountRecommendations:
+		return true
+	}
+	return false
+}
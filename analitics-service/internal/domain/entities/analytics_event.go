@@ -0,0 +1,22 @@
+// internal/domain/entities/analytics_event.go
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AnalyticsEventVersion текущая версия схемы событий аналитики
+// Версия увеличивается при несовместимом изменении payload; потребители должны проверять ее перед разбором
+const AnalyticsEventVersion = 1
+
+// AnalyticsEvent представляет конверт события аналитики, публикуемого во внешние системы
+type AnalyticsEvent struct {
+	ID         string          `json:"id"` // Уникальный ID события, по нему потребители отбрасывают повторы
+	Type       EventType       `json:"type"`
+	Version    int             `json:"version"`
+	Stream     EventStream     `json:"stream"`
+	RunID      string          `json:"run_id,omitempty"` // Запуск анализа, результат которого описывает событие
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
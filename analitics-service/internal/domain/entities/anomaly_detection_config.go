@@ -0,0 +1,78 @@
+// internal/domain/entities/anomaly_detection_config.go
+package entities
+
+import (
+	"fmt"
+)
+
+// AnomalyDetectionConfig содержит параметры обнаружения аномалий продаж
+type AnomalyDetectionConfig struct {
+	Method                AnomalyMethod `json:"method"`
+	LookbackDays          int           `json:"lookback_days"`           // История для оценки нормального уровня
+	DetectDays            int           `json:"detect_days"`             // Последние дни, в которых ищутся аномалии
+	MinHistoryPoints      int           `json:"min_history_points"`      // Минимум точек истории для оценки ряда
+	LowThreshold          float64       `json:"low_threshold"`           // Порог робастной z-оценки для low
+	MediumThreshold       float64       `json:"medium_threshold"`        // Порог для medium
+	HighThreshold         float64       `json:"high_threshold"`          // Порог для high
+	SuppressDiscountDelta float64       `json:"suppress_discount_delta"` // Превышение средней скидки над обычной (п.п.), при котором алерты подавляются
+	IncludeProducts       bool          `json:"include_products"`        // Искать аномалии также по отдельным товарам
+}
+
+// DefaultAnomalyDetectionConfig возвращает параметры обнаружения по умолчанию
+func DefaultAnomalyDetectionConfig() AnomalyDetectionConfig {
+	return AnomalyDetectionConfig{
+		Method:                AnomalyMethodSeasonal,
+		LookbackDays:          56,
+		DetectDays:            1,
+		MinHistoryPoints:      14,
+		LowThreshold:          3.5,
+		MediumThreshold:       5,
+		HighThreshold:         8,
+		SuppressDiscountDelta: 5,
+		IncludeProducts:       true,
+	}
+}
+
+// Validate проверяет корректность данных в структуре AnomalyDetectionConfig
+func (c *AnomalyDetectionConfig) Validate() error {
+	if c.Method != AnomalyMethodMAD && c.Method != AnomalyMethodSeasonal {
+		return fmt.Errorf("invalid anomaly method: %s", c.Method)
+	}
+
+	if c.LookbackDays <= 0 || c.DetectDays <= 0 {
+		return fmt.Errorf("lookback and detect days must be positive, got %d and %d", c.LookbackDays, c.DetectDays)
+	}
+
+	if c.MinHistoryPoints < 3 {
+		return fmt.Errorf("min history points must be at least 3, got %d", c.MinHistoryPoints)
+	}
+
+	if c.LowThreshold <= 0 || c.MediumThreshold < c.LowThreshold || c.HighThreshold < c.MediumThreshold {
+		return fmt.Errorf("thresholds must be positive and non-decreasing, got %f, %f, %f",
+			c.LowThreshold, c.MediumThreshold, c.HighThreshold)
+	}
+
+	if c.SuppressDiscountDelta <= 0 {
+		return fmt.Errorf("suppress discount delta must be positive, got %f", c.SuppressDiscountDelta)
+	}
+
+	return nil
+}
+
+// SeverityFor возвращает серьезность для робастной z-оценки или false, если отклонение в норме
+func (c *AnomalyDetectionConfig) SeverityFor(score float64) (AnomalySeverity, bool) {
+	if score < 0 {
+		score = -score
+	}
+
+	switch {
+	case score >= c.HighThreshold:
+		return SeverityHigh, true
+	case score >= c.MediumThreshold:
+		return SeverityMedium, true
+	case score >= c.LowThreshold:
+		return SeverityLow, true
+	default:
+		return "", false
+	}
+}
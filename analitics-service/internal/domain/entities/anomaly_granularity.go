@@ -0,0 +1,10 @@
+// internal/domain/entities/anomaly_granularity.go
+package entities
+
+// AnomalyGranularity определяет гранулярность ряда, в котором найдена аномалия
+type AnomalyGranularity string
+
+const (
+	GranularityDaily  AnomalyGranularity = "daily"
+	GranularityHourly AnomalyGranularity = "hourly"
+)
@@ -0,0 +1,10 @@
+// internal/domain/entities/anomaly_method.go
+package entities
+
+// AnomalyMethod определяет метод обнаружения аномалий
+type AnomalyMethod string
+
+const (
+	AnomalyMethodMAD      AnomalyMethod = "mad"      // Робастная z-оценка относительно медианы всего окна
+	AnomalyMethodSeasonal AnomalyMethod = "seasonal" // Робастная z-оценка остатков после вычитания сезонной медианы
+)
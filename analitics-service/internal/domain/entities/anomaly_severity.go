@@ -0,0 +1,25 @@
+// internal/domain/entities/anomaly_severity.go
+package entities
+
+// AnomalySeverity определяет серьезность аномалии продаж
+type AnomalySeverity string
+
+const (
+	SeverityLow    AnomalySeverity = "low"
+	SeverityMedium AnomalySeverity = "medium"
+	SeverityHigh   AnomalySeverity = "high"
+)
+
+// Rank возвращает порядковый номер серьезности для сравнения, 0 для неизвестного значения
+func (s AnomalySeverity) Rank() int {
+	switch s {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	default:
+		return 0
+	}
+}
@@ -11,4 +11,7 @@ type AssociationRule struct {
 	Items      []string   `json:"items"`       // Все товары в правиле (для удобства поиска)
 	Categories []string   `json:"categories"`  // Категории товаров в правиле
 	PriceRange [2]float64 `json:"price_range"` // Диапазон цен товаров в правиле
+
+	// Запуск анализа, в котором найдено правило
+	RunID string `json:"run_id,omitempty"`
 }
@@ -0,0 +1,38 @@
+// internal/domain/entities/association_rule_params.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AssociationRuleParams содержит параметры поиска ассоциативных правил
+type AssociationRuleParams struct {
+	StartDate     time.Time `json:"start_date"`
+	EndDate       time.Time `json:"end_date"`
+	MinSupport    float64   `json:"min_support"`
+	MinConfidence float64   `json:"min_confidence"`
+}
+
+// Validate проверяет корректность данных в структуре AssociationRuleParams
+func (p *AssociationRuleParams) Validate() error {
+	if p.StartDate.IsZero() || p.EndDate.IsZero() {
+		return errors.New("start date and end date are required")
+	}
+
+	if !p.StartDate.Before(p.EndDate) {
+		return fmt.Errorf("start date (%s) must be before end date (%s)",
+			p.StartDate.Format(time.RFC3339), p.EndDate.Format(time.RFC3339))
+	}
+
+	if p.MinSupport <= 0 || p.MinSupport > 1 {
+		return fmt.Errorf("min support must be in (0, 1], got %f", p.MinSupport)
+	}
+
+	if p.MinConfidence <= 0 || p.MinConfidence > 1 {
+		return fmt.Errorf("min confidence must be in (0, 1], got %f", p.MinConfidence)
+	}
+
+	return nil
+}
@@ -0,0 +1,7 @@
+// internal/domain/entities/association_rules_payload.go
+package entities
+
+// AssociationRulesPayload содержимое событий потока association_rules
+type AssociationRulesPayload struct {
+	Rules []AssociationRule `json:"rules"`
+}
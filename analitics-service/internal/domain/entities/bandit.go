@@ -0,0 +1,70 @@
+// internal/domain/entities/bandit.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Bandit представляет многорукий бандит для выбора уровня скидки товара или категории
+type Bandit struct {
+	BaseEntity
+	ProductID        string            `json:"product_id,omitempty"`
+	Category         string            `json:"category,omitempty"`
+	RewardModel      BanditRewardModel `json:"reward_model"`
+	DiscountLevels   []float64         `json:"discount_levels"`   // Кандидатные скидки в процентах
+	ExplorationFloor float64           `json:"exploration_floor"` // Минимальная вероятность выбора каждой руки
+	PriorAlpha       float64           `json:"prior_alpha"`       // Априорное Beta-распределение для bernoulli
+	PriorBeta        float64           `json:"prior_beta"`
+	PriorMean        float64           `json:"prior_mean"` // Априорное нормальное распределение для gaussian
+	PriorStdDev      float64           `json:"prior_std_dev"`
+	IsActive         bool              `json:"is_active"`
+	StartedAt        time.Time         `json:"started_at"`
+}
+
+// Validate проверяет корректность данных в структуре Bandit
+func (b *Bandit) Validate() error {
+	if b.ID == "" {
+		return errors.New("bandit ID is required")
+	}
+
+	if b.ProductID == "" && b.Category == "" {
+		return errors.New("either product ID or category must be specified")
+	}
+
+	if b.RewardModel != RewardBernoulli && b.RewardModel != RewardGaussian {
+		return fmt.Errorf("invalid reward model: %s", b.RewardModel)
+	}
+
+	if len(b.DiscountLevels) < 2 {
+		return fmt.Errorf("bandit must have at least two discount levels, got %d", len(b.DiscountLevels))
+	}
+
+	seen := make(map[float64]struct{}, len(b.DiscountLevels))
+	for _, level := range b.DiscountLevels {
+		if level < 0 || level > 100 {
+			return fmt.Errorf("discount level must be between 0 and 100, got %f", level)
+		}
+		if _, exists := seen[level]; exists {
+			return fmt.Errorf("duplicate discount level: %f", level)
+		}
+		seen[level] = struct{}{}
+	}
+
+	// Суммарная вероятность гарантированного исследования не может превышать 1
+	if b.ExplorationFloor < 0 || b.ExplorationFloor*float64(len(b.DiscountLevels)) > 1 {
+		return fmt.Errorf("exploration floor must be between 0 and %f, got %f",
+			1/float64(len(b.DiscountLevels)), b.ExplorationFloor)
+	}
+
+	if b.RewardModel == RewardBernoulli && (b.PriorAlpha <= 0 || b.PriorBeta <= 0) {
+		return fmt.Errorf("prior parameters must be positive, got alpha=%f beta=%f", b.PriorAlpha, b.PriorBeta)
+	}
+
+	if b.RewardModel == RewardGaussian && b.PriorStdDev <= 0 {
+		return fmt.Errorf("prior standard deviation must be positive, got %f", b.PriorStdDev)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+// internal/domain/entities/bandit_arm_stats.go
+package entities
+
+import "time"
+
+// BanditArm представляет руку бандита — один из кандидатных уровней скидки
+type BanditArm struct {
+	ID               string    `json:"id"`
+	BanditID         string    `json:"bandit_id"`
+	DiscountPct      float64   `json:"discount_pct"`
+	Pulls            int       `json:"pulls"`
+	Successes        int       `json:"successes"`          // Для модели bernoulli
+	RewardSum        float64   `json:"reward_sum"`         // Сумма вознаграждений
+	RewardSumSquares float64   `json:"reward_sum_squares"` // Сумма квадратов вознаграждений для оценки дисперсии
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// MeanReward возвращает среднее наблюдаемое вознаграждение руки
+func (a *BanditArm) MeanReward() float64 {
+	if a.Pulls == 0 {
+		return 0
+	}
+	return a.RewardSum / float64(a.Pulls)
+}
+
+// RewardVariance возвращает несмещенную оценку дисперсии вознаграждения руки
+func (a *BanditArm) RewardVariance() float64 {
+	if a.Pulls < 2 {
+		return 0
+	}
+	n := float64(a.Pulls)
+	mean := a.RewardSum / n
+	variance := (a.RewardSumSquares - n*mean*mean) / (n - 1)
+	if variance < 0 {
+		return 0
+	}
+	return variance
+}
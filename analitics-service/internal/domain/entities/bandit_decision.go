@@ -0,0 +1,13 @@
+// internal/domain/entities/bandit_decision.go
+package entities
+
+import "time"
+
+// BanditDecision представляет выбор руки бандита для одного запроса
+type BanditDecision struct {
+	BanditID    string    `json:"bandit_id"`
+	ArmID       string    `json:"arm_id"`
+	DiscountPct float64   `json:"discount_pct"`
+	Explored    bool      `json:"explored"` // Рука выбрана случайно в рамках минимального исследования
+	DecidedAt   time.Time `json:"decided_at"`
+}
@@ -0,0 +1,25 @@
+// internal/domain/entities/bandit_report.go
+package entities
+
+import "time"
+
+// BanditArmReport содержит статистику руки бандита для отчета
+type BanditArmReport struct {
+	ArmID         string  `json:"arm_id"`
+	DiscountPct   float64 `json:"discount_pct"`
+	Pulls         int     `json:"pulls"`
+	ExpectedValue float64 `json:"expected_value"` // Апостериорное ожидаемое значение руки
+	Regret        float64 `json:"regret"`         // Вклад руки в накопленное сожаление
+}
+
+// BanditReport содержит состояние бандита и накопленное сожаление относительно лучшей руки
+type BanditReport struct {
+	BanditID         string            `json:"bandit_id"`
+	GeneratedAt      time.Time         `json:"generated_at"`
+	TotalPulls       int               `json:"total_pulls"`
+	TotalReward      float64           `json:"total_reward"`
+	BestArmID        string            `json:"best_arm_id"`
+	BestDiscountPct  float64           `json:"best_discount_pct"`
+	CumulativeRegret float64           `json:"cumulative_regret"`
+	Arms             []BanditArmReport `json:"arms"`
+}
@@ -0,0 +1,12 @@
+// internal/domain/entities/bandit_reward_model.go
+package entities
+
+// BanditRewardModel определяет модель вознаграждения многорукого бандита
+type BanditRewardModel string
+
+const (
+	// RewardBernoulli — вознаграждение 0/1 (покупка), апостериорное распределение Beta
+	RewardBernoulli BanditRewardModel = "bernoulli"
+	// RewardGaussian — денежное вознаграждение (выручка или маржа), нормальное апостериорное распределение
+	RewardGaussian BanditRewardModel = "gaussian"
+)
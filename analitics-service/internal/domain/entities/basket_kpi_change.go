@@ -0,0 +1,15 @@
+// internal/domain/entities/basket_kpi_change.go
+package entities
+
+// BasketKPIChange содержит изменение KPI корзины относительно предыдущего периода
+// Средние и выручка сравниваются в процентах, доли — в процентных пунктах
+type BasketKPIChange struct {
+	BasketsPct                 float64 `json:"baskets_pct"`
+	RevenuePct                 float64 `json:"revenue_pct"`
+	AverageOrderValuePct       float64 `json:"average_order_value_pct"`
+	ItemsPerBasketPct          float64 `json:"items_per_basket_pct"`
+	UniqueProductsPerBasketPct float64 `json:"unique_products_per_basket_pct"`
+	RevenuePerCustomerPct      float64 `json:"revenue_per_customer_pct"`
+	DiscountPenetrationPP      float64 `json:"discount_penetration_pp"`
+	AverageDiscountDepthPP     float64 `json:"average_discount_depth_pp"`
+}
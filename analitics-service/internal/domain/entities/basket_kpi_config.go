@@ -0,0 +1,58 @@
+// internal/domain/entities/basket_kpi_config.go
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// BasketKPIConfig содержит параметры расчета KPI корзины
+type BasketKPIConfig struct {
+	Dayparts            []Daypart `json:"dayparts"`
+	Timezone            string    `json:"timezone"`              // Часовой пояс точек продаж для границ периодов и частей дня
+	LoyalMinPurchases   int       `json:"loyal_min_purchases"`   // Число покупок за период ретроспективы, с которого клиент считается лояльным
+	SegmentLookbackDays int       `json:"segment_lookback_days"` // Период ретроспективы для сегментации клиентов
+}
+
+// DefaultBasketKPIConfig возвращает параметры расчета по умолчанию
+func DefaultBasketKPIConfig() BasketKPIConfig {
+	return BasketKPIConfig{
+		Dayparts:            DefaultDayparts(),
+		Timezone:            "UTC",
+		LoyalMinPurchases:   4,
+		SegmentLookbackDays: 180,
+	}
+}
+
+// Validate проверяет корректность данных в структуре BasketKPIConfig
+func (c *BasketKPIConfig) Validate() error {
+	if err := validateDayparts(c.Dayparts); err != nil {
+		return err
+	}
+
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+	}
+
+	if c.LoyalMinPurchases < 2 {
+		return fmt.Errorf("loyal min purchases must be at least 2, got %d", c.LoyalMinPurchases)
+	}
+
+	if c.SegmentLookbackDays <= 0 {
+		return fmt.Errorf("segment lookback days must be positive, got %d", c.SegmentLookbackDays)
+	}
+
+	return nil
+}
+
+// SegmentOf возвращает сегмент клиента по числу его покупок за период ретроспективы до текущей
+func (c *BasketKPIConfig) SegmentOf(priorPurchases int) CustomerSegment {
+	switch {
+	case priorPurchases == 0:
+		return CustomerSegmentNew
+	case priorPurchases+1 >= c.LoyalMinPurchases:
+		return CustomerSegmentLoyal
+	default:
+		return CustomerSegmentReturning
+	}
+}
@@ -0,0 +1,22 @@
+// internal/domain/entities/basket_kpi_dimension.go
+package entities
+
+// BasketKPIDimension определяет разрез, в котором рассчитываются KPI корзины
+type BasketKPIDimension string
+
+const (
+	BasketKPITotal      BasketKPIDimension = "total"    // Все чеки без разреза
+	BasketKPIByCategory BasketKPIDimension = "category" // Позиции чеков по категориям товаров
+	BasketKPIByDaypart  BasketKPIDimension = "daypart"  // Чеки по частям дня
+	BasketKPIBySegment  BasketKPIDimension = "segment"  // Чеки по сегментам клиентов
+)
+
+// IsValid проверяет, что разрез поддерживается
+func (d BasketKPIDimension) IsValid() bool {
+	switch d {
+	case BasketKPITotal, BasketKPIByCategory, BasketKPIByDaypart, BasketKPIBySegment:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,15 @@
+// internal/domain/entities/basket_kpi_period.go
+package entities
+
+import "time"
+
+// BasketKPIPeriod содержит KPI корзины одного периода и значения разреза
+// Previous и Change заполняются, если в предыдущем периоде той же длины были чеки этого разреза
+type BasketKPIPeriod struct {
+	PeriodStart time.Time        `json:"period_start"`
+	PeriodEnd   time.Time        `json:"period_end"`
+	Slice       string           `json:"slice,omitempty"` // Категория, часть дня или сегмент клиента; пусто для разреза total
+	KPIs        BasketKPIs       `json:"kpis"`
+	Previous    *BasketKPIs      `json:"previous,omitempty"`
+	Change      *BasketKPIChange `json:"change,omitempty"`
+}
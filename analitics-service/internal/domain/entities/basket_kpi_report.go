@@ -0,0 +1,15 @@
+// internal/domain/entities/basket_kpi_report.go
+package entities
+
+import "time"
+
+// BasketKPIReport содержит KPI корзины по периодам диапазона в выбранном разрезе
+type BasketKPIReport struct {
+	Period      TimeRange          `json:"period"`
+	Dimension   BasketKPIDimension `json:"dimension"`
+	StartDate   time.Time          `json:"start_date"`
+	EndDate     time.Time          `json:"end_date"`
+	Timezone    string             `json:"timezone"`
+	Periods     []BasketKPIPeriod  `json:"periods"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
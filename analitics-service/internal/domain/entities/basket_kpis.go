@@ -0,0 +1,19 @@
+// internal/domain/entities/basket_kpis.go
+package entities
+
+// BasketKPIs содержит KPI корзины за период
+// Выручка — фактически оплаченная сумма чеков; в разрезе категорий она распределяется
+// по позициям пропорционально их стоимости после скидок
+type BasketKPIs struct {
+	Baskets                 int     `json:"baskets"`
+	Customers               int     `json:"customers"`
+	Units                   int     `json:"units"`
+	GrossRevenue            float64 `json:"gross_revenue"` // Выручка по ценам без скидок
+	Revenue                 float64 `json:"revenue"`
+	AverageOrderValue       float64 `json:"average_order_value"`
+	ItemsPerBasket          float64 `json:"items_per_basket"`
+	UniqueProductsPerBasket float64 `json:"unique_products_per_basket"`
+	DiscountPenetrationPct  float64 `json:"discount_penetration_pct"`   // Доля чеков со скидкой
+	AverageDiscountDepthPct float64 `json:"average_discount_depth_pct"` // Скидка в процентах от выручки без скидок в чеках со скидкой
+	RevenuePerCustomer      float64 `json:"revenue_per_customer"`
+}
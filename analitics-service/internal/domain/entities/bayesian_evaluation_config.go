@@ -0,0 +1,55 @@
+// internal/domain/entities/bayesian_evaluation_config.go
+package entities
+
+import (
+	"fmt"
+)
+
+// BayesianEvaluationConfig содержит параметры байесовской оценки A/B теста
+type BayesianEvaluationConfig struct {
+	PriorAlpha            float64      `json:"prior_alpha"`             // Параметр alpha априорного Beta-распределения конверсии
+	PriorBeta             float64      `json:"prior_beta"`              // Параметр beta априорного Beta-распределения конверсии
+	PosteriorSamples      int          `json:"posterior_samples"`       // Количество выборок Монте-Карло из апостериорных распределений
+	CredibleLevel         float64      `json:"credible_level"`          // Уровень достоверных интервалов
+	ExpectedLossThreshold float64      `json:"expected_loss_threshold"` // Порог ожидаемых потерь относительно значения контроля
+	PrimaryMetric         ABTestMetric `json:"primary_metric"`
+	Seed                  int64        `json:"seed"`
+}
+
+// DefaultBayesianEvaluationConfig возвращает параметры байесовской оценки по умолчанию
+func DefaultBayesianEvaluationConfig() BayesianEvaluationConfig {
+	return BayesianEvaluationConfig{
+		PriorAlpha:            1,
+		PriorBeta:             1,
+		PosteriorSamples:      20000,
+		CredibleLevel:         0.95,
+		ExpectedLossThreshold: 0.01,
+		PrimaryMetric:         MetricConversion,
+		Seed:                  1,
+	}
+}
+
+// Validate проверяет корректность данных в структуре BayesianEvaluationConfig
+func (c *BayesianEvaluationConfig) Validate() error {
+	if c.PriorAlpha <= 0 || c.PriorBeta <= 0 {
+		return fmt.Errorf("prior parameters must be positive, got alpha=%f beta=%f", c.PriorAlpha, c.PriorBeta)
+	}
+
+	if c.PosteriorSamples < 100 {
+		return fmt.Errorf("posterior samples must be at least 100, got %d", c.PosteriorSamples)
+	}
+
+	if c.CredibleLevel <= 0 || c.CredibleLevel >= 1 {
+		return fmt.Errorf("credible level must be between 0 and 1, got %f", c.CredibleLevel)
+	}
+
+	if c.ExpectedLossThreshold <= 0 {
+		return fmt.Errorf("expected loss threshold must be positive, got %f", c.ExpectedLossThreshold)
+	}
+
+	if !isValidABTestMetric(c.PrimaryMetric) {
+		return fmt.Errorf("invalid primary metric: %s", c.PrimaryMetric)
+	}
+
+	return nil
+}
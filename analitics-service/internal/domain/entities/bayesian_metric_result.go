@@ -0,0 +1,16 @@
+// internal/domain/entities/bayesian_metric_result.go
+package entities
+
+// BayesianMetricResult содержит результат байесовской оценки одной метрики A/B теста
+type BayesianMetricResult struct {
+	Metric                   ABTestMetric `json:"metric"`
+	Method                   string       `json:"method"` // beta_binomial, bayesian_bootstrap или normal_approximation
+	ControlMean              float64      `json:"control_mean"`
+	TestMean                 float64      `json:"test_mean"`
+	ProbabilityToBeatControl float64      `json:"probability_to_beat_control"`
+	ExpectedLossTest         float64      `json:"expected_loss_test"`    // Ожидаемые потери при выборе теста
+	ExpectedLossControl      float64      `json:"expected_loss_control"` // Ожидаемые потери при сохранении контроля
+	ControlInterval          [2]float64   `json:"control_interval"`
+	TestInterval             [2]float64   `json:"test_interval"`
+	LiftInterval             [2]float64   `json:"lift_interval"` // Достоверный интервал относительного прироста
+}
@@ -0,0 +1,75 @@
+// internal/domain/entities/bundle_options.go
+package entities
+
+import (
+	"fmt"
+)
+
+// BundleOptions содержит параметры поиска наборов и расчета их цены
+type BundleOptions struct {
+	MinSupport              float64 `json:"min_support"`               // Минимальная поддержка набора
+	MinLift                 float64 `json:"min_lift"`                  // Минимальный lift набора
+	MaxItems                int     `json:"max_items"`                 // Максимальное количество товаров в наборе (2 или 3)
+	MaxCoPurchaseRate       float64 `json:"max_co_purchase_rate"`      // Наборы, которые и так покупают вместе чаще этой доли, пропускаются
+	TargetDiscountPct       float64 `json:"target_discount_pct"`       // Желаемая скидка на набор относительно суммы цен
+	MinMarginPct            float64 `json:"min_margin_pct"`            // Минимальная маржа набора в процентах от его цены
+	DefaultAttachElasticity float64 `json:"default_attach_elasticity"` // Относительный прирост доли присоединения на 1% скидки, если оценить по истории нельзя
+	MinObservationDays      int     `json:"min_observation_days"`      // Минимум дней для оценки чувствительности присоединения к скидке
+	MaxBundles              int     `json:"max_bundles"`
+}
+
+// DefaultBundleOptions возвращает параметры поиска наборов по умолчанию
+func DefaultBundleOptions() BundleOptions {
+	return BundleOptions{
+		MinSupport:              0.01,
+		MinLift:                 1.2,
+		MaxItems:                3,
+		MaxCoPurchaseRate:       0.8,
+		TargetDiscountPct:       10,
+		MinMarginPct:            30,
+		DefaultAttachElasticity: 2,
+		MinObservationDays:      14,
+		MaxBundles:              20,
+	}
+}
+
+// Validate проверяет корректность данных в структуре BundleOptions
+func (o *BundleOptions) Validate() error {
+	if o.MinSupport <= 0 || o.MinSupport > 1 {
+		return fmt.Errorf("min support must be between 0 and 1, got %f", o.MinSupport)
+	}
+
+	if o.MinLift < 1 {
+		return fmt.Errorf("min lift must be at least 1, got %f", o.MinLift)
+	}
+
+	if o.MaxItems < 2 || o.MaxItems > 3 {
+		return fmt.Errorf("max items must be 2 or 3, got %d", o.MaxItems)
+	}
+
+	if o.MaxCoPurchaseRate <= 0 || o.MaxCoPurchaseRate > 1 {
+		return fmt.Errorf("max co-purchase rate must be between 0 and 1, got %f", o.MaxCoPurchaseRate)
+	}
+
+	if o.TargetDiscountPct <= 0 || o.TargetDiscountPct >= 100 {
+		return fmt.Errorf("target discount must be between 0 and 100, got %f", o.TargetDiscountPct)
+	}
+
+	if o.MinMarginPct < 0 || o.MinMarginPct >= 100 {
+		return fmt.Errorf("min margin must be between 0 and 100, got %f", o.MinMarginPct)
+	}
+
+	if o.DefaultAttachElasticity < 0 {
+		return fmt.Errorf("default attach elasticity cannot be negative, got %f", o.DefaultAttachElasticity)
+	}
+
+	if o.MinObservationDays < 3 {
+		return fmt.Errorf("min observation days must be at least 3, got %d", o.MinObservationDays)
+	}
+
+	if o.MaxBundles <= 0 {
+		return fmt.Errorf("max bundles must be positive, got %d", o.MaxBundles)
+	}
+
+	return nil
+}
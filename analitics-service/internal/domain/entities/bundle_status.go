@@ -0,0 +1,21 @@
+// internal/domain/entities/bundle_status.go
+package entities
+
+// BundleStatus представляет состояние предложенного набора в процессе согласования
+type BundleStatus string
+
+const (
+	BundleProposed BundleStatus = "proposed" // Предложен и ожидает решения команды меню
+	BundleApproved BundleStatus = "approved"
+	BundleRejected BundleStatus = "rejected"
+)
+
+// IsValid проверяет, что статус набора поддерживается
+func (s BundleStatus) IsValid() bool {
+	switch s {
+	case BundleProposed, BundleApproved, BundleRejected:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,10 @@
+// internal/domain/entities/campaign_performance.go
+package entities
+
+// CampaignPerformance содержит показатели купонной кампании по всем её кодам
+type CampaignPerformance struct {
+	CampaignID   string   `json:"campaign_id"`
+	CampaignName string   `json:"campaign_name"`
+	CouponCodes  []string `json:"coupon_codes"`
+	CouponMetrics
+}
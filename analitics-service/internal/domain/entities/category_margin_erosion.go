@@ -0,0 +1,23 @@
+// internal/domain/entities/category_margin_erosion.go
+package entities
+
+// CategoryMarginErosion содержит показатели размывания маржи категории за период
+// Себестоимость берется из истории на дату каждой продажи; показатели себестоимости и маржи
+// считаются только по продажам товаров с известной себестоимостью
+type CategoryMarginErosion struct {
+	Category           string  `json:"category"`
+	Units              int     `json:"units"`
+	GrossRevenue       float64 `json:"gross_revenue"`        // Выручка по цене продажи без скидок
+	NetRevenue         float64 `json:"net_revenue"`          // Выручка после скидок
+	DiscountLoss       float64 `json:"discount_loss"`        // Маржа, потерянная на скидках
+	Cost               float64 `json:"cost"`                 // Себестоимость на даты продаж
+	BaseCost           float64 `json:"base_cost"`            // Себестоимость тех же продаж на начало периода
+	CostInflationLoss  float64 `json:"cost_inflation_loss"`  // Маржа, потерянная из-за роста себестоимости за период
+	CostInflationPct   float64 `json:"cost_inflation_pct"`   // Рост себестоимости за период, %
+	PriceChangePct     float64 `json:"price_change_pct"`     // Изменение цены по прейскуранту за период, %, для товаров с историей цен
+	Profit             float64 `json:"profit"`               // Выручка после скидок за вычетом себестоимости
+	MarginPct          float64 `json:"margin_pct"`           // Фактическая маржа, %
+	ListMarginPct      float64 `json:"list_margin_pct"`      // Маржа без скидок при себестоимости на начало периода, %
+	MarginErosionPct   float64 `json:"margin_erosion_pct"`   // Снижение маржи относительно ListMarginPct, п.п.
+	CostedRevenueShare float64 `json:"costed_revenue_share"` // Доля выручки товаров с известной себестоимостью
+}
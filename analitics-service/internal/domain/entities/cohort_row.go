@@ -0,0 +1,13 @@
+// internal/domain/entities/cohort_row.go
+package entities
+
+import "time"
+
+// CohortRow представляет строку треугольника удержания для когорты привлечения
+// Retained[k] — количество клиентов когорты, совершивших покупку через k периодов после первой
+type CohortRow struct {
+	CohortStart    time.Time `json:"cohort_start"`
+	Size           int       `json:"size"`
+	Retained       []int     `json:"retained"`
+	RetentionRates []float64 `json:"retention_rates"`
+}
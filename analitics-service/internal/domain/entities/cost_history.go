@@ -0,0 +1,39 @@
+// internal/domain/entities/cost_history.go
+package entities
+
+import (
+	"sort"
+	"time"
+)
+
+// CostHistory содержит историю себестоимости и цены товаров, отсортированную по дате начала действия
+type CostHistory map[string][]ProductCost
+
+// NewCostHistory группирует записи по товарам и сортирует их по дате начала действия
+func NewCostHistory(costs []ProductCost) CostHistory {
+	history := make(CostHistory)
+	for _, cost := range costs {
+		history[cost.ProductID] = append(history[cost.ProductID], cost)
+	}
+	for _, records := range history {
+		sort.SliceStable(records, func(i, j int) bool {
+			return records[i].EffectiveFrom.Before(records[j].EffectiveFrom)
+		})
+	}
+	return history
+}
+
+// At возвращает запись, действовавшую на дату, или false, если истории товара нет
+// Для дат до начала истории возвращается самая ранняя запись как ближайшая известная оценка
+func (h CostHistory) At(productID string, date time.Time) (ProductCost, bool) {
+	records := h[productID]
+	if len(records) == 0 {
+		return ProductCost{}, false
+	}
+
+	i := sort.Search(len(records), func(i int) bool { return records[i].EffectiveFrom.After(date) })
+	if i == 0 {
+		return records[0], true
+	}
+	return records[i-1], true
+}
@@ -0,0 +1,16 @@
+// internal/domain/entities/coupon_abuse_alert.go
+package entities
+
+import "time"
+
+// CouponAbuseAlert представляет найденный подозрительный паттерн использования купона
+type CouponAbuseAlert struct {
+	CouponCode  string             `json:"coupon_code"`
+	Pattern     CouponAbusePattern `json:"pattern"`
+	Accounts    int                `json:"accounts"`
+	Redemptions int                `json:"redemptions"`
+	CustomerIDs []string           `json:"customer_ids"`
+	WindowStart time.Time          `json:"window_start"`
+	WindowEnd   time.Time          `json:"window_end"`
+	DetectedAt  time.Time          `json:"detected_at"`
+}
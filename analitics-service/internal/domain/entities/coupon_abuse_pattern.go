@@ -0,0 +1,10 @@
+// internal/domain/entities/coupon_abuse_pattern.go
+package entities
+
+// CouponAbusePattern определяет тип подозрительного использования купона
+type CouponAbusePattern string
+
+const (
+	AbuseSharedCode         CouponAbusePattern = "shared_code"         // Один код погашен многими клиентами за короткое время
+	AbuseRepeatedRedemption CouponAbusePattern = "repeated_redemption" // Один клиент многократно погашает один и тот же код
+)
@@ -0,0 +1,51 @@
+// internal/domain/entities/coupon_analytics_config.go
+package entities
+
+import (
+	"fmt"
+)
+
+// CouponAnalyticsConfig содержит параметры анализа эффективности купонов
+type CouponAnalyticsConfig struct {
+	BaselineDays              int `json:"baseline_days"`                // Окно до начала периода для расчета обычного чека клиента
+	RepeatWindowDays          int `json:"repeat_window_days"`           // Окно после погашения, в котором ищется повторный визит
+	AbuseWindowHours          int `json:"abuse_window_hours"`           // Скользящее окно поиска массового использования одного кода
+	AbuseMinAccounts          int `json:"abuse_min_accounts"`           // Число разных клиентов в окне, начиная с которого код считается скомпрометированным
+	MaxRedemptionsPerCustomer int `json:"max_redemptions_per_customer"` // Допустимое число погашений одного кода одним клиентом за период
+}
+
+// DefaultCouponAnalyticsConfig возвращает параметры анализа купонов по умолчанию
+func DefaultCouponAnalyticsConfig() CouponAnalyticsConfig {
+	return CouponAnalyticsConfig{
+		BaselineDays:              90,
+		RepeatWindowDays:          30,
+		AbuseWindowHours:          24,
+		AbuseMinAccounts:          20,
+		MaxRedemptionsPerCustomer: 3,
+	}
+}
+
+// Validate проверяет корректность данных в структуре CouponAnalyticsConfig
+func (c *CouponAnalyticsConfig) Validate() error {
+	if c.BaselineDays <= 0 {
+		return fmt.Errorf("baseline days must be positive, got %d", c.BaselineDays)
+	}
+
+	if c.RepeatWindowDays <= 0 {
+		return fmt.Errorf("repeat window days must be positive, got %d", c.RepeatWindowDays)
+	}
+
+	if c.AbuseWindowHours <= 0 {
+		return fmt.Errorf("abuse window hours must be positive, got %d", c.AbuseWindowHours)
+	}
+
+	if c.AbuseMinAccounts < 2 {
+		return fmt.Errorf("abuse min accounts must be at least 2, got %d", c.AbuseMinAccounts)
+	}
+
+	if c.MaxRedemptionsPerCustomer <= 0 {
+		return fmt.Errorf("max redemptions per customer must be positive, got %d", c.MaxRedemptionsPerCustomer)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+// internal/domain/entities/coupon_metrics.go
+package entities
+
+import "time"
+
+// CouponMetrics содержит показатели погашений купона или группы купонов кампании
+type CouponMetrics struct {
+	Redemptions             int       `json:"redemptions"`
+	UniqueCustomers         int       `json:"unique_customers"`
+	Revenue                 float64   `json:"revenue"`
+	AvgBasketValue          float64   `json:"avg_basket_value"`
+	AvgBasketItems          float64   `json:"avg_basket_items"`
+	BaselineCustomers       int       `json:"baseline_customers"`       // Клиенты, у которых есть покупки без купона
+	BaselineBasketValue     float64   `json:"baseline_basket_value"`    // Обычный чек этих клиентов без купона
+	BaselineBasketItems     float64   `json:"baseline_basket_items"`    // Обычное количество единиц в чеке без купона
+	IncrementalBasketValue  float64   `json:"incremental_basket_value"` // Прирост чека с купоном относительно обычного чека того же клиента
+	IncrementalBasketItems  float64   `json:"incremental_basket_items"`
+	MarginGivenAway         float64   `json:"margin_given_away"`         // Сумма скидок по погашениям
+	MarginGivenAwayShare    float64   `json:"margin_given_away_share"`   // Доля валовой маржи по полной цене, отданная скидкой
+	RepeatEligibleCustomers int       `json:"repeat_eligible_customers"` // Клиенты, для которых окно повторного визита уже завершилось
+	RepeatCustomers         int       `json:"repeat_customers"`
+	RepeatVisitRate         float64   `json:"repeat_visit_rate"`
+	FirstRedemption         time.Time `json:"first_redemption"`
+	LastRedemption          time.Time `json:"last_redemption"`
+}
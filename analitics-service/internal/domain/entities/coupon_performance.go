@@ -0,0 +1,9 @@
+// internal/domain/entities/coupon_performance.go
+package entities
+
+// CouponPerformance содержит показатели отдельного купонного кода
+type CouponPerformance struct {
+	CouponCode string `json:"coupon_code"`
+	CampaignID string `json:"campaign_id,omitempty"` // Акция промо-календаря с этим кодом, если есть
+	CouponMetrics
+}
@@ -0,0 +1,10 @@
+// internal/domain/entities/coupon_report.go
+package entities
+
+// CouponReport содержит отчет об эффективности купонов и кампаний за период
+type CouponReport struct {
+	AnalysisMetadata
+	Coupons     []CouponPerformance   `json:"coupons"`
+	Campaigns   []CampaignPerformance `json:"campaigns"`
+	AbuseAlerts []CouponAbuseAlert    `json:"abuse_alerts"`
+}
@@ -0,0 +1,12 @@
+// internal/domain/entities/cross_effect_type.go
+package entities
+
+// CrossEffectType определяет тип связи соседнего товара с товаром под акцией
+type CrossEffectType string
+
+const (
+	// CrossEffectSubstitute — товар-заменитель, продажи которого акция может каннибализировать
+	CrossEffectSubstitute CrossEffectType = "substitute"
+	// CrossEffectComplement — дополняющий товар, продажи которого акция может подтянуть (halo)
+	CrossEffectComplement CrossEffectType = "complement"
+)
@@ -0,0 +1,21 @@
+// internal/domain/entities/customer_segment.go
+package entities
+
+// CustomerSegment определяет сегмент клиента по числу покупок до текущей
+type CustomerSegment string
+
+const (
+	CustomerSegmentNew       CustomerSegment = "new"       // Нет покупок за период ретроспективы, включая вернувшихся после долгого перерыва
+	CustomerSegmentReturning CustomerSegment = "returning" // Покупал ранее, но реже порога лояльности
+	CustomerSegmentLoyal     CustomerSegment = "loyal"     // Число покупок с текущей достигает порога лояльности
+)
+
+// IsValid проверяет, что сегмент клиента поддерживается
+func (s CustomerSegment) IsValid() bool {
+	switch s {
+	case CustomerSegmentNew, CustomerSegmentReturning, CustomerSegmentLoyal:
+		return true
+	default:
+		return false
+	}
+}
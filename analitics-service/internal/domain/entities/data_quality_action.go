@@ -0,0 +1,20 @@
+// internal/domain/entities/data_quality_action.go
+package entities
+
+// DataQualityAction определяет действие с записью, нарушившей правило качества данных
+type DataQualityAction string
+
+const (
+	DataQualityQuarantine DataQualityAction = "quarantine" // Запись исключается из анализа и сохраняется в карантин
+	DataQualityRepair     DataQualityAction = "repair"     // Запись исправляется и участвует в анализе
+	DataQualityFlag       DataQualityAction = "flag"       // Нарушение только учитывается, запись участвует в анализе без изменений
+)
+
+// IsValid проверяет, является ли действие допустимым
+func (a DataQualityAction) IsValid() bool {
+	switch a {
+	case DataQualityQuarantine, DataQualityRepair, DataQualityFlag:
+		return true
+	}
+	return false
+}
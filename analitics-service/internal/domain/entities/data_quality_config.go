@@ -0,0 +1,70 @@
+// internal/domain/entities/data_quality_config.go
+package entities
+
+import (
+	"fmt"
+)
+
+// DataQualityConfig содержит правила проверки и очистки входных данных анализа
+// Правило, отсутствующее в Rules, не применяется
+type DataQualityConfig struct {
+	Rules             map[DataQualityRule]DataQualityAction `json:"rules"`               // Действие для каждого применяемого правила
+	TotalTolerance    float64                               `json:"total_tolerance"`     // Допустимое расхождение суммы чека и суммы позиций
+	OutlierThreshold  float64                               `json:"outlier_threshold"`   // Порог робастной z-оценки логарифма суммы или количества
+	OutlierMinSamples int                                   `json:"outlier_min_samples"` // Минимум записей в группе для поиска выбросов
+}
+
+// DefaultDataQualityConfig возвращает правила очистки по умолчанию
+func DefaultDataQualityConfig() DataQualityConfig {
+	return DataQualityConfig{
+		Rules: map[DataQualityRule]DataQualityAction{
+			DataQualityDuplicateID:      DataQualityRepair,
+			DataQualityNegativeQuantity: DataQualityQuarantine,
+			DataQualityFullDiscount:     DataQualityQuarantine,
+			DataQualityInvalidRecord:    DataQualityQuarantine,
+			DataQualityZeroTotal:        DataQualityRepair,
+			DataQualityTotalMismatch:    DataQualityRepair,
+			DataQualityOutlier:          DataQualityFlag,
+		},
+		TotalTolerance:    0.05,
+		OutlierThreshold:  8,
+		OutlierMinSamples: 30,
+	}
+}
+
+// Validate проверяет корректность данных в структуре DataQualityConfig
+func (c *DataQualityConfig) Validate() error {
+	for rule, action := range c.Rules {
+		if !rule.IsValid() {
+			return fmt.Errorf("invalid data quality rule: %s", rule)
+		}
+
+		if !action.IsValid() {
+			return fmt.Errorf("invalid action for data quality rule %s: %s", rule, action)
+		}
+
+		if action == DataQualityRepair && !rule.Repairable() {
+			return fmt.Errorf("data quality rule %s cannot be repaired", rule)
+		}
+	}
+
+	if c.TotalTolerance < 0 {
+		return fmt.Errorf("total tolerance cannot be negative, got %f", c.TotalTolerance)
+	}
+
+	if c.OutlierThreshold <= 0 {
+		return fmt.Errorf("outlier threshold must be positive, got %f", c.OutlierThreshold)
+	}
+
+	if c.OutlierMinSamples < 3 {
+		return fmt.Errorf("outlier min samples must be at least 3, got %d", c.OutlierMinSamples)
+	}
+
+	return nil
+}
+
+// ActionFor возвращает действие для правила или false, если правило не применяется
+func (c *DataQualityConfig) ActionFor(rule DataQualityRule) (DataQualityAction, bool) {
+	action, ok := c.Rules[rule]
+	return action, ok
+}
@@ -0,0 +1,15 @@
+// internal/domain/entities/data_quality_report.go
+package entities
+
+// DataQualityReport содержит итоги проверки качества входных данных запуска анализа
+// Score — доля записей без нарушений; запись с несколькими нарушениями учитывается в Violations по каждому правилу
+type DataQualityReport struct {
+	Score       float64                 `json:"score"`
+	Records     int                     `json:"records"`     // Проверено записей
+	Passed      int                     `json:"passed"`      // Записей без нарушений
+	Repaired    int                     `json:"repaired"`    // Исправлено записей
+	Dropped     int                     `json:"dropped"`     // Удалено повторных копий и продаж, исправленных удалением позиции
+	Quarantined int                     `json:"quarantined"` // Исключено в карантин записей
+	Flagged     int                     `json:"flagged"`     // Записей с нарушениями, оставленных без изменений
+	Violations  map[DataQualityRule]int `json:"violations"`  // Количество нарушивших записей по правилам
+}
@@ -0,0 +1,9 @@
+// internal/domain/entities/data_quality_result.go
+package entities
+
+// DataQualityResult содержит итоги очистки одного источника входных данных
+// RunID записей карантина заполняется при сохранении запуска анализа
+type DataQualityResult struct {
+	Report      DataQualityReport   `json:"report"`
+	Quarantined []QuarantinedRecord `json:"quarantined"`
+}
@@ -0,0 +1,45 @@
+// internal/domain/entities/data_quality_rule.go
+package entities
+
+// DataQualityRule определяет правило проверки качества входных данных анализа
+type DataQualityRule string
+
+const (
+	DataQualityDuplicateID      DataQualityRule = "duplicate_id"      // Повтор ID чека или продажи
+	DataQualityNegativeQuantity DataQualityRule = "negative_quantity" // Позиция с нулевым или отрицательным количеством
+	DataQualityFullDiscount     DataQualityRule = "full_discount"     // Позиция со скидкой 100%
+	DataQualityInvalidRecord    DataQualityRule = "invalid_record"    // Запись не прошла проверку Validate сущности
+	DataQualityZeroTotal        DataQualityRule = "zero_total"        // Нулевая сумма чека при позициях с ценой
+	DataQualityTotalMismatch    DataQualityRule = "total_mismatch"    // Сумма чека не совпадает с суммой позиций
+	DataQualityOutlier          DataQualityRule = "outlier"           // Выброс суммы чека или количества товара в продаже
+)
+
+// DataQualityRules возвращает все правила в порядке их применения к записи
+func DataQualityRules() []DataQualityRule {
+	return []DataQualityRule{
+		DataQualityDuplicateID,
+		DataQualityNegativeQuantity,
+		DataQualityFullDiscount,
+		DataQualityInvalidRecord,
+		DataQualityZeroTotal,
+		DataQualityTotalMismatch,
+		DataQualityOutlier,
+	}
+}
+
+// IsValid проверяет, является ли правило допустимым
+func (r DataQualityRule) IsValid() bool {
+	for _, rule := range DataQualityRules() {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// Repairable сообщает, можно ли исправить нарушение правила
+// Повтор исправляется удалением копий, позиции с отрицательным количеством и полной скидкой удаляются из чека,
+// а сумма чека пересчитывается по позициям; некорректную запись и выброс исправить нельзя
+func (r DataQualityRule) Repairable() bool {
+	return r != DataQualityInvalidRecord && r != DataQualityOutlier
+}
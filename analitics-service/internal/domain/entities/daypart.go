@@ -0,0 +1,86 @@
+// internal/domain/entities/daypart.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Daypart представляет часть дня, например завтрак или обед
+// Интервал [StartHour, EndHour) может переходить через полночь, если EndHour <= StartHour
+type Daypart struct {
+	Name      string `json:"name"`
+	StartHour int    `json:"start_hour"`
+	EndHour   int    `json:"end_hour"`
+}
+
+// Validate проверяет корректность данных в структуре Daypart
+func (d *Daypart) Validate() error {
+	if d.Name == "" {
+		return errors.New("daypart name is required")
+	}
+
+	if d.StartHour < 0 || d.StartHour > 23 {
+		return fmt.Errorf("daypart start hour must be between 0 and 23, got %d", d.StartHour)
+	}
+
+	if d.EndHour < 0 || d.EndHour > 24 {
+		return fmt.Errorf("daypart end hour must be between 0 and 24, got %d", d.EndHour)
+	}
+
+	if d.StartHour == d.EndHour {
+		return fmt.Errorf("daypart %s start and end hours must differ", d.Name)
+	}
+
+	return nil
+}
+
+// Contains проверяет, попадает ли час в часть дня
+func (d *Daypart) Contains(hour int) bool {
+	if d.StartHour < d.EndHour {
+		return hour >= d.StartHour && hour < d.EndHour
+	}
+	return hour >= d.StartHour || hour < d.EndHour
+}
+
+// DaypartAt возвращает название части дня, в которую попадает час
+func DaypartAt(dayparts []Daypart, hour int) (string, bool) {
+	for i := range dayparts {
+		if dayparts[i].Contains(hour) {
+			return dayparts[i].Name, true
+		}
+	}
+	return "", false
+}
+
+// validateDayparts проверяет части дня и отсутствие пересечений между ними
+func validateDayparts(dayparts []Daypart) error {
+	if len(dayparts) == 0 {
+		return errors.New("at least one daypart is required")
+	}
+
+	var owners [24]string
+	names := make(map[string]bool, len(dayparts))
+	for i := range dayparts {
+		daypart := &dayparts[i]
+		if err := daypart.Validate(); err != nil {
+			return fmt.Errorf("invalid daypart at index %d: %w", i, err)
+		}
+		if names[daypart.Name] {
+			return fmt.Errorf("duplicate daypart name: %s", daypart.Name)
+		}
+		names[daypart.Name] = true
+
+		for hour := 0; hour < 24; hour++ {
+			if !daypart.Contains(hour) {
+				continue
+			}
+			if owners[hour] != "" {
+				return fmt.Errorf("dayparts %s and %s overlap at hour %d", owners[hour], daypart.Name, hour)
+			}
+			owners[hour] = daypart.Name
+		}
+	}
+
+	return nil
+}
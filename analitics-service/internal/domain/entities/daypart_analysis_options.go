@@ -0,0 +1,74 @@
+// internal/domain/entities/daypart_analysis_options.go
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// DaypartAnalysisOptions содержит параметры анализа продаж по частям дня
+type DaypartAnalysisOptions struct {
+	Dayparts      []Daypart `json:"dayparts"`
+	Timezone      string    `json:"timezone"`       // Часовой пояс точек продаж, например Europe/Moscow
+	TopProducts   int       `json:"top_products"`   // Количество лидеров продаж в каждой части дня
+	MinSupport    float64   `json:"min_support"`    // Минимальная поддержка правил внутри части дня
+	MinConfidence float64   `json:"min_confidence"` // Минимальная достоверность правил внутри части дня
+	MaxDiscount   float64   `json:"max_discount"`   // Верхняя граница рекомендуемой скидки в процентах
+}
+
+// DefaultDayparts возвращает части дня кофейни по умолчанию
+func DefaultDayparts() []Daypart {
+	return []Daypart{
+		{Name: "breakfast", StartHour: 6, EndHour: 11},
+		{Name: "lunch", StartHour: 11, EndHour: 14},
+		{Name: "afternoon", StartHour: 14, EndHour: 17},
+		{Name: "evening", StartHour: 17, EndHour: 22},
+	}
+}
+
+// DefaultDaypartAnalysisOptions возвращает параметры анализа по умолчанию
+func DefaultDaypartAnalysisOptions() DaypartAnalysisOptions {
+	return DaypartAnalysisOptions{
+		Dayparts:      DefaultDayparts(),
+		Timezone:      "UTC",
+		TopProducts:   10,
+		MinSupport:    0.01,
+		MinConfidence: 0.3,
+		MaxDiscount:   30,
+	}
+}
+
+// Validate проверяет корректность данных в структуре DaypartAnalysisOptions
+func (o *DaypartAnalysisOptions) Validate() error {
+	if err := validateDayparts(o.Dayparts); err != nil {
+		return err
+	}
+
+	if _, err := time.LoadLocation(o.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", o.Timezone, err)
+	}
+
+	if o.TopProducts <= 0 {
+		return fmt.Errorf("top products must be positive, got %d", o.TopProducts)
+	}
+
+	if o.MinSupport <= 0 || o.MinSupport > 1 {
+		return fmt.Errorf("min support must be between 0 and 1, got %f", o.MinSupport)
+	}
+
+	if o.MinConfidence <= 0 || o.MinConfidence > 1 {
+		return fmt.Errorf("min confidence must be between 0 and 1, got %f", o.MinConfidence)
+	}
+
+	if o.MaxDiscount <= 0 || o.MaxDiscount > 100 {
+		return fmt.Errorf("max discount must be between 0 and 100, got %f", o.MaxDiscount)
+	}
+
+	return nil
+}
+
+// DaypartOf возвращает часть дня для момента времени в часовом поясе точек продаж
+// Часы, не входящие ни в одну часть дня, возвращают false
+func (o *DaypartAnalysisOptions) DaypartOf(t time.Time, location *time.Location) (string, bool) {
+	return DaypartAt(o.Dayparts, t.In(location).Hour())
+}
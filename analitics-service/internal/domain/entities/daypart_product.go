@@ -0,0 +1,9 @@
+// internal/domain/entities/daypart_product.go
+package entities
+
+// DaypartProduct содержит продажи товара в части дня
+type DaypartProduct struct {
+	ProductID string  `json:"product_id"`
+	Units     int     `json:"units"`
+	Revenue   float64 `json:"revenue"`
+}
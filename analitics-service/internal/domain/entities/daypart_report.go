@@ -0,0 +1,10 @@
+// internal/domain/entities/daypart_report.go
+package entities
+
+// DaypartReport содержит анализ продаж по часам и частям дня
+type DaypartReport struct {
+	AnalysisMetadata
+	Timezone string         `json:"timezone"`
+	Hourly   []HourlySales  `json:"hourly"`
+	Dayparts []DaypartSales `json:"dayparts"`
+}
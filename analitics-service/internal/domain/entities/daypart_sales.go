@@ -0,0 +1,15 @@
+// internal/domain/entities/daypart_sales.go
+package entities
+
+// DaypartSales содержит показатели продаж части дня
+type DaypartSales struct {
+	Daypart        string            `json:"daypart"`
+	Revenue        float64           `json:"revenue"`
+	RevenueShare   float64           `json:"revenue_share"`
+	Units          int               `json:"units"`
+	Transactions   int               `json:"transactions"`
+	AvgBasketSize  float64           `json:"avg_basket_size"`
+	AvgBasketValue float64           `json:"avg_basket_value"`
+	TopProducts    []DaypartProduct  `json:"top_products"`
+	Rules          []AssociationRule `json:"rules"` // Правила, найденные только по чекам этой части дня
+}
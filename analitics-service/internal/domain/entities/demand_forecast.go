@@ -0,0 +1,21 @@
+// internal/domain/entities/demand_forecast.go
+package entities
+
+import "time"
+
+// DemandForecast представляет дневной прогноз спроса в штуках для товара или категории
+type DemandForecast struct {
+	ID           string             `json:"id"`
+	Level        ForecastLevel      `json:"level"`
+	TargetID     string             `json:"target_id"` // ID товара или название категории
+	ModelType    ForecastModelType  `json:"model_type"`
+	Alpha        float64            `json:"alpha,omitempty"`
+	Beta         float64            `json:"beta,omitempty"`
+	Gamma        float64            `json:"gamma,omitempty"`
+	SeasonLength int                `json:"season_length"`
+	HistoryStart time.Time          `json:"history_start"`
+	HistoryEnd   time.Time          `json:"history_end"`
+	GeneratedAt  time.Time          `json:"generated_at"`
+	Points       []ForecastPoint    `json:"points"`
+	Backtests    []ForecastBacktest `json:"backtests"`
+}
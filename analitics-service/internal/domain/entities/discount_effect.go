@@ -0,0 +1,20 @@
+// internal/domain/entities/discount_effect.go
+package entities
+
+import (
+	"time"
+)
+
+// DiscountEffect содержит результат регрессионного анализа влияния скидок на продажи товара или категории
+type DiscountEffect struct {
+	ProductID         string             `json:"product_id,omitempty"`
+	Category          string             `json:"category,omitempty"`
+	LiftFactor        float64            `json:"lift_factor"`      // Коэффициент регрессии при скидке
+	RSquared          float64            `json:"r_squared"`        // Доля дисперсии продаж, объясненная моделью
+	OptimalDiscount   float64            `json:"optimal_discount"` // Скидка, максимизирующая выручку (от 0 до 1)
+	AnalysisTimestamp time.Time          `json:"analysis_timestamp"`
+	Coefficients      map[string]float64 `json:"coefficients"`      // Коэффициенты модели по именам переменных
+	DataPointsCount   int                `json:"data_points_count"` // Количество дней в обучающей выборке
+	PeriodStart       time.Time          `json:"period_start"`
+	PeriodEnd         time.Time          `json:"period_end"`
+}
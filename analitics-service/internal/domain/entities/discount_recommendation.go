@@ -11,11 +11,20 @@ type DiscountRecommendation struct {
 	AnalysisMetadata
 	ProductID        string  `json:"product_id,omitempty"`
 	Category         string  `json:"category,omitempty"`
+	Daypart          string  `json:"daypart,omitempty"` // Пустое значение — рекомендация на весь день
 	OptimalDiscount  float64 `json:"optimal_discount"`
 	LiftFactor       float64 `json:"lift_factor"`
 	ABCCategory      Segment `json:"abc_category"`
 	Confidence       float64 `json:"confidence"`
 	AdjustmentReason string  `json:"adjustment_reason,omitempty"`
+
+	// Стадия жизненного цикла товара или преобладающая стадия категории
+	LifecycleStage LifecycleStage `json:"lifecycle_stage,omitempty"`
+
+	// Чистый эффект акции с учетом соседних товаров, заполняется анализом каннибализации
+	CannibalizedRevenue   float64 `json:"cannibalized_revenue,omitempty"`
+	HaloRevenue           float64 `json:"halo_revenue,omitempty"`
+	NetIncrementalRevenue float64 `json:"net_incremental_revenue,omitempty"`
 }
 
 // Validate проверяет корректность данных в структуре DiscountRecommendation
@@ -0,0 +1,28 @@
+// internal/domain/entities/discount_recommendation_params.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DiscountRecommendationParams содержит параметры генерации рекомендаций по скидкам
+type DiscountRecommendationParams struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// Validate проверяет корректность данных в структуре DiscountRecommendationParams
+func (p *DiscountRecommendationParams) Validate() error {
+	if p.StartDate.IsZero() || p.EndDate.IsZero() {
+		return errors.New("start date and end date are required")
+	}
+
+	if !p.StartDate.Before(p.EndDate) {
+		return fmt.Errorf("start date (%s) must be before end date (%s)",
+			p.StartDate.Format(time.RFC3339), p.EndDate.Format(time.RFC3339))
+	}
+
+	return nil
+}
@@ -0,0 +1,7 @@
+// internal/domain/entities/discount_recommendations_payload.go
+package entities
+
+// DiscountRecommendationsPayload содержимое событий потока discount_recommendations
+type DiscountRecommendationsPayload struct {
+	Recommendations []DiscountRecommendation `json:"recommendations"`
+}
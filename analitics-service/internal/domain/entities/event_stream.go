@@ -0,0 +1,31 @@
+// internal/domain/entities/event_stream.go
+package entities
+
+// EventStream определяет набор данных, изменения которого публикуются событиями
+// Имя потока используется как ключ сообщения Kafka, поэтому события одного потока попадают в одну партицию по порядку
+type EventStream string
+
+const (
+	EventStreamDiscountRecommendations EventStream = "discount_recommendations"
+	EventStreamABCSegmentation         EventStream = "abc_segmentation"
+	EventStreamAssociationRules        EventStream = "association_rules"
+)
+
+// IsValid проверяет, является ли поток событий допустимым
+func (s EventStream) IsValid() bool {
+	switch s {
+	case EventStreamDiscountRecommendations, EventStreamABCSegmentation, EventStreamAssociationRules:
+		return true
+	}
+	return false
+}
+
+// Regenerated возвращает тип события о пересчете набора данных потока
+func (s EventStream) Regenerated() EventType {
+	return EventType(string(s) + ".regenerated")
+}
+
+// Snapshot возвращает тип события со снимком текущего состояния набора данных потока
+func (s EventStream) Snapshot() EventType {
+	return EventType(string(s) + ".snapshot")
+}
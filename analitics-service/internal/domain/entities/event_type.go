@@ -0,0 +1,8 @@
+// internal/domain/entities/event_type.go
+package entities
+
+// EventType определяет тип события аналитики в формате <поток>.<вид>
+//
+//	<поток>.regenerated — набор данных пересчитан, payload содержит новый набор целиком
+//	<поток>.snapshot    — снимок текущего состояния, с которого потребитель может начать чтение потока
+type EventType string
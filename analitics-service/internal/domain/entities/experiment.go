@@ -0,0 +1,119 @@
+// internal/domain/entities/experiment.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ExperimentBuckets — количество бакетов, на которые хэшируются клиенты внутри слоя
+const ExperimentBuckets = 10000
+
+// Experiment представляет эксперимент (A/B тест) в реестре экспериментов
+// Эксперименты одного слоя взаимоисключающие: каждый занимает свой диапазон бакетов слоя
+type Experiment struct {
+	BaseEntity
+	Name           string              `json:"name"`
+	Description    string              `json:"description"`
+	LayerID        string              `json:"layer_id"`     // Пустой слой означает отдельный слой эксперимента
+	BucketStart    int                 `json:"bucket_start"` // Начало диапазона бакетов слоя (включительно)
+	BucketEnd      int                 `json:"bucket_end"`   // Конец диапазона бакетов слоя (не включительно)
+	Variants       []ExperimentVariant `json:"variants"`
+	TargetSegments []string            `json:"target_segments,omitempty"` // Пустой список означает всех клиентов
+	ProductIDs     []string            `json:"product_ids,omitempty"`     // Товары, продажи которых учитываются в результатах
+	AnalysisMode   ABTestAnalysisMode  `json:"analysis_mode,omitempty"`
+	Status         ExperimentStatus    `json:"status"`
+	StartDate      time.Time           `json:"start_date"`
+	EndDate        time.Time           `json:"end_date"`
+}
+
+// Validate проверяет корректность данных в структуре Experiment
+func (e *Experiment) Validate() error {
+	if e.ID == "" {
+		return errors.New("experiment ID is required")
+	}
+
+	if e.Name == "" {
+		return errors.New("experiment name is required")
+	}
+
+	if e.StartDate.IsZero() || e.EndDate.IsZero() {
+		return errors.New("experiment start and end dates are required")
+	}
+
+	if !e.StartDate.Before(e.EndDate) {
+		return fmt.Errorf("start date (%s) must be before end date (%s)",
+			e.StartDate.Format(time.RFC3339), e.EndDate.Format(time.RFC3339))
+	}
+
+	if e.BucketStart < 0 || e.BucketEnd > ExperimentBuckets || e.BucketStart >= e.BucketEnd {
+		return fmt.Errorf("bucket range must be within [0, %d), got [%d, %d)", ExperimentBuckets, e.BucketStart, e.BucketEnd)
+	}
+
+	if len(e.Variants) < 2 {
+		return fmt.Errorf("experiment must have at least two variants, got %d", len(e.Variants))
+	}
+
+	ids := make(map[string]struct{}, len(e.Variants))
+	controls := 0
+	sum := 0.0
+	for i, variant := range e.Variants {
+		if err := variant.Validate(); err != nil {
+			return fmt.Errorf("invalid variant at index %d: %w", i, err)
+		}
+		if _, exists := ids[variant.ID]; exists {
+			return fmt.Errorf("duplicate variant ID: %s", variant.ID)
+		}
+		ids[variant.ID] = struct{}{}
+		if variant.IsControl {
+			controls++
+		}
+		sum += variant.Weight
+	}
+
+	if controls != 1 {
+		return fmt.Errorf("experiment must have exactly one control variant, got %d", controls)
+	}
+
+	// Проверяем, что сумма весов равна 1 (с небольшой погрешностью)
+	if math.Abs(sum-1.0) > 0.001 {
+		return fmt.Errorf("sum of variant weights must be 1.0, got %f", sum)
+	}
+
+	return nil
+}
+
+// IsActive проверяет, проводится ли эксперимент в указанный момент времени
+func (e *Experiment) IsActive(at time.Time) bool {
+	return e.Status == ExperimentRunning && !at.Before(e.StartDate) && at.Before(e.EndDate)
+}
+
+// Layer возвращает идентификатор слоя эксперимента
+func (e *Experiment) Layer() string {
+	if e.LayerID == "" {
+		return "experiment:" + e.ID
+	}
+	return e.LayerID
+}
+
+// ControlVariant возвращает контрольный вариант эксперимента
+func (e *Experiment) ControlVariant() (ExperimentVariant, bool) {
+	for _, variant := range e.Variants {
+		if variant.IsControl {
+			return variant, true
+		}
+	}
+	return ExperimentVariant{}, false
+}
+
+// OverlapsWith проверяет, пересекаются ли эксперименты по слою, бакетам и датам проведения
+func (e *Experiment) OverlapsWith(other Experiment) bool {
+	if e.Layer() != other.Layer() {
+		return false
+	}
+	bucketsOverlap := e.BucketStart < other.BucketEnd && other.BucketStart < e.BucketEnd
+	datesOverlap := e.StartDate.Before(other.EndDate) && other.StartDate.Before(e.EndDate)
+	return bucketsOverlap && datesOverlap
+}
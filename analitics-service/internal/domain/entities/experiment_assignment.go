@@ -0,0 +1,13 @@
+// internal/domain/entities/experiment_assignment.go
+package entities
+
+// ExperimentAssignment представляет назначение клиента в вариант эксперимента
+type ExperimentAssignment struct {
+	ExperimentID string  `json:"experiment_id"`
+	VariantID    string  `json:"variant_id"`
+	CustomerID   string  `json:"customer_id"`
+	LayerBucket  int     `json:"layer_bucket"`
+	IsControl    bool    `json:"is_control"`
+	DiscountPct  float64 `json:"discount_pct,omitempty"`
+	CouponCode   string  `json:"coupon_code,omitempty"`
+}
@@ -0,0 +1,12 @@
+// internal/domain/entities/experiment_status.go
+package entities
+
+// ExperimentStatus представляет состояние эксперимента
+type ExperimentStatus string
+
+const (
+	ExperimentDraft     ExperimentStatus = "draft"
+	ExperimentRunning   ExperimentStatus = "running"
+	ExperimentPaused    ExperimentStatus = "paused"
+	ExperimentCompleted ExperimentStatus = "completed"
+)
@@ -0,0 +1,34 @@
+// internal/domain/entities/experiment_variant.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ExperimentVariant представляет вариант эксперимента
+type ExperimentVariant struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Weight      float64 `json:"weight"` // Доля трафика эксперимента (от 0 до 1)
+	IsControl   bool    `json:"is_control"`
+	DiscountPct float64 `json:"discount_pct,omitempty"`
+	CouponCode  string  `json:"coupon_code,omitempty"`
+}
+
+// Validate проверяет корректность данных в структуре ExperimentVariant
+func (v *ExperimentVariant) Validate() error {
+	if v.ID == "" {
+		return errors.New("variant ID is required")
+	}
+
+	if v.Weight <= 0 || v.Weight > 1 {
+		return fmt.Errorf("variant weight must be between 0 and 1, got %f", v.Weight)
+	}
+
+	if v.DiscountPct < 0 || v.DiscountPct > 100 {
+		return fmt.Errorf("discount percentage must be between 0 and 100, got %f", v.DiscountPct)
+	}
+
+	return nil
+}
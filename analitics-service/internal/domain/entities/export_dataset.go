@@ -0,0 +1,22 @@
+// internal/domain/entities/export_dataset.go
+package entities
+
+// ExportDataset определяет набор аналитических результатов для выгрузки
+type ExportDataset string
+
+const (
+	ExportABCSegmentation         ExportDataset = "abc"
+	ExportAssociationRules        ExportDataset = "rules"
+	ExportDiscountRecommendations ExportDataset = "recommendations"
+	ExportRetentionTriangle       ExportDataset = "retention"
+	ExportForecasts               ExportDataset = "forecasts"
+)
+
+// IsValid проверяет, является ли набор данных допустимым
+func (d ExportDataset) IsValid() bool {
+	switch d {
+	case ExportABCSegmentation, ExportAssociationRules, ExportDiscountRecommendations, ExportRetentionTriangle, ExportForecasts:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+// internal/domain/entities/export_request.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ExportRequest содержит параметры выгрузки набора данных
+type ExportRequest struct {
+	Dataset   ExportDataset `json:"dataset"`
+	StartDate time.Time     `json:"start_date"` // Начало когорт удержания или момент, после которого построены прогнозы
+	EndDate   time.Time     `json:"end_date"`
+	Period    TimeRange     `json:"period,omitempty"` // Гранулярность треугольника удержания
+	Level     ForecastLevel `json:"level,omitempty"`  // Уровень прогнозов
+	Limit     int           `json:"limit,omitempty"`  // Количество последних рекомендаций по скидкам
+}
+
+// Validate проверяет корректность данных в структуре ExportRequest
+func (r *ExportRequest) Validate() error {
+	if !r.Dataset.IsValid() {
+		return fmt.Errorf("unknown export dataset %q", r.Dataset)
+	}
+
+	switch r.Dataset {
+	case ExportRetentionTriangle:
+		if !r.Period.IsValid() {
+			return fmt.Errorf("invalid retention period %q", r.Period)
+		}
+		if r.StartDate.IsZero() || r.EndDate.IsZero() || !r.StartDate.Before(r.EndDate) {
+			return errors.New("retention export requires start date before end date")
+		}
+	case ExportForecasts:
+		if !r.Level.IsValid() {
+			return fmt.Errorf("invalid forecast level %q", r.Level)
+		}
+	case ExportDiscountRecommendations:
+		if r.Limit <= 0 {
+			return fmt.Errorf("limit must be positive, got %d", r.Limit)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+// internal/domain/entities/exposure.go
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+// Exposure представляет факт показа варианта эксперимента клиенту
+type Exposure struct {
+	BaseEntity
+	ExperimentID string    `json:"experiment_id"`
+	VariantID    string    `json:"variant_id"`
+	CustomerID   string    `json:"customer_id"`
+	ExposedAt    time.Time `json:"exposed_at"`
+	Channel      string    `json:"channel,omitempty"`
+}
+
+// Validate проверяет корректность данных в структуре Exposure
+func (e *Exposure) Validate() error {
+	if e.ExperimentID == "" {
+		return errors.New("experiment ID is required")
+	}
+
+	if e.VariantID == "" {
+		return errors.New("variant ID is required")
+	}
+
+	if e.CustomerID == "" {
+		return errors.New("customer ID is required")
+	}
+
+	if e.ExposedAt.IsZero() {
+		return errors.New("exposure time is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+// internal/domain/entities/forecast_backtest.go
+package entities
+
+// ForecastBacktest содержит ошибки модели на скользящем бэктесте
+type ForecastBacktest struct {
+	ModelType ForecastModelType `json:"model_type"`
+	Folds     int               `json:"folds"`
+	MAPE      float64           `json:"mape"`  // В процентах, дни без спроса не учитываются
+	SMAPE     float64           `json:"smape"` // В процентах
+}
@@ -0,0 +1,57 @@
+// internal/domain/entities/forecast_config.go
+package entities
+
+import (
+	"fmt"
+)
+
+// ForecastConfig содержит параметры построения прогноза спроса
+type ForecastConfig struct {
+	HorizonDays     int                 `json:"horizon_days"`
+	HistoryDays     int                 `json:"history_days"`
+	SeasonLength    int                 `json:"season_length"`    // Длина сезона в днях, 7 для недельной сезонности
+	BacktestFolds   int                 `json:"backtest_folds"`   // Количество окон скользящего бэктеста
+	ConfidenceLevel float64             `json:"confidence_level"` // Уровень доверия интервалов прогноза
+	SelectionMetric ForecastErrorMetric `json:"selection_metric"`
+}
+
+// DefaultForecastConfig возвращает параметры прогнозирования по умолчанию
+func DefaultForecastConfig() ForecastConfig {
+	return ForecastConfig{
+		HorizonDays:     14,
+		HistoryDays:     365,
+		SeasonLength:    7,
+		BacktestFolds:   4,
+		ConfidenceLevel: 0.95,
+		SelectionMetric: ForecastMetricSMAPE,
+	}
+}
+
+// Validate проверяет корректность данных в структуре ForecastConfig
+func (c *ForecastConfig) Validate() error {
+	if c.HorizonDays <= 0 {
+		return fmt.Errorf("horizon must be positive, got %d", c.HorizonDays)
+	}
+
+	if c.SeasonLength < 2 {
+		return fmt.Errorf("season length must be at least 2, got %d", c.SeasonLength)
+	}
+
+	if c.HistoryDays < 2*c.SeasonLength {
+		return fmt.Errorf("history must cover at least two seasons (%d days), got %d", 2*c.SeasonLength, c.HistoryDays)
+	}
+
+	if c.BacktestFolds <= 0 {
+		return fmt.Errorf("backtest folds must be positive, got %d", c.BacktestFolds)
+	}
+
+	if c.ConfidenceLevel <= 0 || c.ConfidenceLevel >= 1 {
+		return fmt.Errorf("confidence level must be between 0 and 1, got %f", c.ConfidenceLevel)
+	}
+
+	if c.SelectionMetric != ForecastMetricMAPE && c.SelectionMetric != ForecastMetricSMAPE {
+		return fmt.Errorf("invalid selection metric: %s", c.SelectionMetric)
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+// internal/domain/entities/forecast_error_metric.go
+package entities
+
+// ForecastErrorMetric определяет метрику ошибки для выбора модели
+type ForecastErrorMetric string
+
+const (
+	ForecastMetricMAPE  ForecastErrorMetric = "mape"  // Средняя абсолютная процентная ошибка
+	ForecastMetricSMAPE ForecastErrorMetric = "smape" // Симметричная средняя абсолютная процентная ошибка
+)
@@ -0,0 +1,15 @@
+// internal/domain/entities/forecast_level.go
+package entities
+
+// ForecastLevel определяет уровень агрегации прогноза
+type ForecastLevel string
+
+const (
+	ForecastLevelProduct  ForecastLevel = "product"
+	ForecastLevelCategory ForecastLevel = "category"
+)
+
+// IsValid проверяет, является ли уровень прогноза допустимым
+func (l ForecastLevel) IsValid() bool {
+	return l == ForecastLevelProduct || l == ForecastLevelCategory
+}
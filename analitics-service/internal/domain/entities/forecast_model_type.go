@@ -0,0 +1,10 @@
+// internal/domain/entities/forecast_model_type.go
+package entities
+
+// ForecastModelType определяет модель прогнозирования спроса
+type ForecastModelType string
+
+const (
+	ForecastHoltWinters   ForecastModelType = "holt_winters"   // Тройное экспоненциальное сглаживание
+	ForecastSeasonalNaive ForecastModelType = "seasonal_naive" // Значение того же дня прошлого сезона
+)
@@ -0,0 +1,12 @@
+// internal/domain/entities/forecast_point.go
+package entities
+
+import "time"
+
+// ForecastPoint содержит прогноз спроса на день с интервалом прогноза
+type ForecastPoint struct {
+	Date  time.Time `json:"date"`
+	Value float64   `json:"value"`
+	Lower float64   `json:"lower"`
+	Upper float64   `json:"upper"`
+}
@@ -2,9 +2,12 @@
 package entities
 
 // GroupStats представляет статистику по группе
+// Revenue — суммарная выручка группы, стандартные отклонения считаются по пользователям
 type GroupStats struct {
-	Size        int     `json:"size"`
-	Conversion  float64 `json:"conversion"`
-	AvgPurchase float64 `json:"avg_purchase"`
-	Revenue     float64 `json:"revenue"`
+	Size              int     `json:"size"`
+	Conversion        float64 `json:"conversion"`
+	AvgPurchase       float64 `json:"avg_purchase"`
+	Revenue           float64 `json:"revenue"`
+	AvgPurchaseStdDev float64 `json:"avg_purchase_std_dev,omitempty"`
+	RevenueStdDev     float64 `json:"revenue_std_dev,omitempty"`
 }
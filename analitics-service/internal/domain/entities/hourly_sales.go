@@ -0,0 +1,12 @@
+// internal/domain/entities/hourly_sales.go
+package entities
+
+// HourlySales содержит агрегированные продажи за час суток по всему периоду
+type HourlySales struct {
+	Hour           int     `json:"hour"`
+	Revenue        float64 `json:"revenue"`
+	Units          int     `json:"units"`
+	Transactions   int     `json:"transactions"`
+	AvgBasketSize  float64 `json:"avg_basket_size"`  // Среднее количество товаров в чеке
+	AvgBasketValue float64 `json:"avg_basket_value"` // Средняя сумма чека
+}
@@ -0,0 +1,9 @@
+// internal/domain/entities/impact_metrics.go
+package entities
+
+// ImpactMetrics содержит оценки эффекта методом разности разностей
+type ImpactMetrics struct {
+	Units   float64 `json:"units"`
+	Revenue float64 `json:"revenue"`
+	Margin  float64 `json:"margin"`
+}
@@ -0,0 +1,14 @@
+// internal/domain/entities/job_info.go
+package entities
+
+import "time"
+
+// JobInfo содержит описание зарегистрированной задачи и её расписания
+type JobInfo struct {
+	Name       string            `json:"name"`
+	Schedule   string            `json:"schedule,omitempty"` // Пустое значение — задача запускается только вручную
+	Enabled    bool              `json:"enabled"`
+	Timeout    string            `json:"timeout"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	NextRunAt  *time.Time        `json:"next_run_at,omitempty"`
+}
@@ -0,0 +1,18 @@
+// internal/domain/entities/job_run.go
+package entities
+
+import "time"
+
+// JobRun представляет запись истории запусков фоновой задачи
+type JobRun struct {
+	ID         string            `json:"id"`
+	JobName    string            `json:"job_name"`
+	Status     JobRunStatus      `json:"status"`
+	Trigger    JobTrigger        `json:"trigger"`
+	Instance   string            `json:"instance"` // Реплика сервиса, выполнившая задачу
+	Parameters map[string]string `json:"parameters,omitempty"`
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+	DurationMs int64             `json:"duration_ms"`
+	Error      string            `json:"error,omitempty"`
+}
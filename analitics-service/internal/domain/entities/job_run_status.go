@@ -0,0 +1,11 @@
+// internal/domain/entities/job_run_status.go
+package entities
+
+// JobRunStatus представляет состояние запуска фоновой задачи
+type JobRunStatus string
+
+const (
+	JobRunning   JobRunStatus = "running"
+	JobSucceeded JobRunStatus = "succeeded"
+	JobFailed    JobRunStatus = "failed"
+)
@@ -0,0 +1,10 @@
+// internal/domain/entities/job_trigger.go
+package entities
+
+// JobTrigger определяет источник запуска фоновой задачи
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule" // Запуск по cron-расписанию
+	JobTriggerManual   JobTrigger = "manual"   // Ручной запуск через API
+)
@@ -0,0 +1,61 @@
+// internal/domain/entities/lifecycle_config.go
+package entities
+
+import (
+	"fmt"
+)
+
+// LifecycleConfig содержит параметры классификации жизненного цикла товаров
+type LifecycleConfig struct {
+	HistoryWeeks        int     `json:"history_weeks"`        // Глубина истории продаж
+	NewWeeks            int     `json:"new_weeks"`            // Товар моложе этого числа недель считается новым
+	DormantWeeks        int     `json:"dormant_weeks"`        // Число недель без продаж, после которого товар считается неактивным
+	TrendWeeks          int     `json:"trend_weeks"`          // Последние недели, по которым оценивается тренд
+	GrowthRate          float64 `json:"growth_rate"`          // Минимальный недельный темп роста для стадии growing
+	DeclineRate         float64 `json:"decline_rate"`         // Минимальный недельный темп снижения для стадии declining
+	MinTStat            float64 `json:"min_t_stat"`           // Минимальная t-статистика наклона тренда
+	SeasonalCorrelation float64 `json:"seasonal_correlation"` // Порог годовой автокорреляции для признака сезонности
+}
+
+// DefaultLifecycleConfig возвращает параметры классификации по умолчанию
+func DefaultLifecycleConfig() LifecycleConfig {
+	return LifecycleConfig{
+		HistoryWeeks:        104,
+		NewWeeks:            8,
+		DormantWeeks:        6,
+		TrendWeeks:          13,
+		GrowthRate:          0.02,
+		DeclineRate:         0.02,
+		MinTStat:            2,
+		SeasonalCorrelation: 0.5,
+	}
+}
+
+// Validate проверяет корректность данных в структуре LifecycleConfig
+func (c *LifecycleConfig) Validate() error {
+	if c.TrendWeeks < 4 {
+		return fmt.Errorf("trend weeks must be at least 4, got %d", c.TrendWeeks)
+	}
+
+	if c.NewWeeks <= 0 || c.DormantWeeks <= 0 {
+		return fmt.Errorf("new and dormant weeks must be positive, got %d and %d", c.NewWeeks, c.DormantWeeks)
+	}
+
+	if c.HistoryWeeks < c.TrendWeeks || c.HistoryWeeks < c.NewWeeks {
+		return fmt.Errorf("history weeks (%d) must cover trend and new weeks", c.HistoryWeeks)
+	}
+
+	if c.GrowthRate <= 0 || c.DeclineRate <= 0 || c.DeclineRate >= 1 {
+		return fmt.Errorf("growth rate must be positive and decline rate between 0 and 1, got %f and %f", c.GrowthRate, c.DeclineRate)
+	}
+
+	if c.MinTStat < 0 {
+		return fmt.Errorf("min t-stat cannot be negative, got %f", c.MinTStat)
+	}
+
+	if c.SeasonalCorrelation <= 0 || c.SeasonalCorrelation > 1 {
+		return fmt.Errorf("seasonal correlation must be between 0 and 1, got %f", c.SeasonalCorrelation)
+	}
+
+	return nil
+}
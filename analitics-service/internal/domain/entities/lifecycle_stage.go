@@ -0,0 +1,23 @@
+// internal/domain/entities/lifecycle_stage.go
+package entities
+
+// LifecycleStage определяет стадию жизненного цикла товара
+type LifecycleStage string
+
+const (
+	LifecycleNew       LifecycleStage = "new"       // Товар продается недавно, тренд еще не оценить
+	LifecycleGrowing   LifecycleStage = "growing"   // Значимый рост недельных продаж
+	LifecycleMature    LifecycleStage = "mature"    // Продажи стабильны
+	LifecycleDeclining LifecycleStage = "declining" // Значимое снижение недельных продаж
+	LifecycleDormant   LifecycleStage = "dormant"   // Продаж нет несколько недель подряд
+)
+
+// IsValid проверяет, что стадия жизненного цикла поддерживается
+func (s LifecycleStage) IsValid() bool {
+	switch s {
+	case LifecycleNew, LifecycleGrowing, LifecycleMature, LifecycleDeclining, LifecycleDormant:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,10 @@
+// internal/domain/entities/logistic_model_params.go
+package entities
+
+// LogisticModelParams содержит параметры обученной логистической регрессии
+type LogisticModelParams struct {
+	Weights   []float64 `json:"weights"`
+	Intercept float64   `json:"intercept"`
+	Means     []float64 `json:"means"`  // Средние признаков для стандартизации
+	Scales    []float64 `json:"scales"` // Масштабы признаков для стандартизации
+}
@@ -0,0 +1,10 @@
+// internal/domain/entities/margin_erosion_report.go
+package entities
+
+// MarginErosionReport содержит отчет о размывании маржи скидками и ростом себестоимости за период
+// Категории отсортированы по убыванию потерянной маржи
+type MarginErosionReport struct {
+	AnalysisMetadata
+	Total      CategoryMarginErosion   `json:"total"`
+	Categories []CategoryMarginErosion `json:"categories"`
+}
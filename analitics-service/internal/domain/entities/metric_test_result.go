@@ -0,0 +1,20 @@
+// internal/domain/entities/metric_test_result.go
+package entities
+
+// MetricTestResult содержит результат статистической проверки одной метрики A/B теста
+type MetricTestResult struct {
+	Metric                  ABTestMetric `json:"metric"`
+	Method                  string       `json:"method"` // two_proportion_z или welch_t
+	ControlValue            float64      `json:"control_value"`
+	TestValue               float64      `json:"test_value"`
+	AbsoluteDiff            float64      `json:"absolute_diff"`
+	RelativeLift            float64      `json:"relative_lift"`
+	Statistic               float64      `json:"statistic"`
+	DegreesOfFreedom        float64      `json:"degrees_of_freedom,omitempty"`
+	PValue                  float64      `json:"p_value"`
+	ConfidenceInterval      [2]float64   `json:"confidence_interval"`          // Доверительный интервал для разницы
+	BootstrapInterval       *[2]float64  `json:"bootstrap_interval,omitempty"` // Бутстреп-интервал для разницы
+	Power                   float64      `json:"power"`                        // Достигнутая мощность при наблюдаемом эффекте
+	MinimumDetectableEffect float64      `json:"minimum_detectable_effect"`    // Абсолютный MDE при текущих размерах групп
+	IsSignificant           bool         `json:"is_significant"`
+}
@@ -0,0 +1,22 @@
+// internal/domain/entities/outbox_event.go
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent представляет событие в таблице исходящих сообщений (transactional outbox)
+// Событие записывается в одной транзакции с изменением данных и публикуется позже, поэтому
+// не теряется при сбое брокера и не публикуется для отмененных изменений
+type OutboxEvent struct {
+	Sequence    int64           `json:"sequence"` // Порядковый номер записи, задает порядок публикации
+	EventID     string          `json:"event_id"`
+	Type        EventType       `json:"type"`
+	Key         string          `json:"key"`     // Ключ сообщения Kafka
+	Payload     json.RawMessage `json:"payload"` // Сериализованный конверт AnalyticsEvent
+	CreatedAt   time.Time       `json:"created_at"`
+	PublishedAt *time.Time      `json:"published_at,omitempty"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+}
@@ -0,0 +1,27 @@
+// internal/domain/entities/price_suggestion.go
+package entities
+
+import "time"
+
+// PriceSuggestion представляет предложенную цену товара на временной слот дня
+// Каждое предложение сохраняется в журнале вместе с примененными ограничениями
+type PriceSuggestion struct {
+	ID                 string    `json:"id"`
+	RunID              string    `json:"run_id,omitempty"`
+	ProductID          string    `json:"product_id"`
+	TimeSlot           string    `json:"time_slot"`
+	EffectiveDate      time.Time `json:"effective_date"`
+	BasePrice          float64   `json:"base_price"`
+	Cost               float64   `json:"cost"`
+	PreviousPrice      float64   `json:"previous_price"`
+	UnconstrainedPrice float64   `json:"unconstrained_price"` // Оптимальная цена без учета ограничений
+	SuggestedPrice     float64   `json:"suggested_price"`
+	DiscountPct        float64   `json:"discount_pct"` // Отрицательное значение означает наценку
+	Elasticity         float64   `json:"elasticity"`
+	ElasticityFitted   bool      `json:"elasticity_fitted"` // false — использована эластичность по умолчанию
+	ExpectedUnits      float64   `json:"expected_units"`
+	ExpectedMargin     float64   `json:"expected_margin"`
+	ABCCategory        Segment   `json:"abc_category"`
+	AppliedGuardrails  []string  `json:"applied_guardrails,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
@@ -0,0 +1,61 @@
+// internal/domain/entities/pricing_config.go
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// PricingConfig содержит параметры движка динамического ценообразования
+type PricingConfig struct {
+	TimeSlots         []Daypart         `json:"time_slots"`
+	Timezone          string            `json:"timezone"`
+	HistoryDays       int               `json:"history_days"`       // Глубина истории продаж для оценки спроса
+	MinObservations   int               `json:"min_observations"`   // Минимум наблюдений для оценки эластичности
+	DefaultElasticity float64           `json:"default_elasticity"` // Эластичность при недостатке истории
+	Guardrails        PricingGuardrails `json:"guardrails"`
+}
+
+// DefaultPricingConfig возвращает параметры ценообразования по умолчанию
+func DefaultPricingConfig() PricingConfig {
+	return PricingConfig{
+		TimeSlots:         DefaultDayparts(),
+		Timezone:          "UTC",
+		HistoryDays:       90,
+		MinObservations:   30,
+		DefaultElasticity: -1.5,
+		Guardrails: PricingGuardrails{
+			MaxDailyChangePct: 10,
+			MinMarginPct:      20,
+			MaxDiscountPct:    30,
+			MaxMarkupPct:      15,
+			EndingStep:        10,
+			Endings:           []float64{0, 5, 9},
+		},
+	}
+}
+
+// Validate проверяет корректность данных в структуре PricingConfig
+func (c *PricingConfig) Validate() error {
+	if err := validateDayparts(c.TimeSlots); err != nil {
+		return fmt.Errorf("invalid time slots: %w", err)
+	}
+
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+	}
+
+	if c.HistoryDays <= 0 {
+		return fmt.Errorf("history days must be positive, got %d", c.HistoryDays)
+	}
+
+	if c.MinObservations < 3 {
+		return fmt.Errorf("min observations must be at least 3, got %d", c.MinObservations)
+	}
+
+	if c.DefaultElasticity >= 0 {
+		return fmt.Errorf("default elasticity must be negative, got %f", c.DefaultElasticity)
+	}
+
+	return c.Guardrails.Validate()
+}
@@ -0,0 +1,62 @@
+// internal/domain/entities/pricing_guardrails.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Названия ограничений, фиксируемые в журнале предложенных цен
+const (
+	GuardrailPriceRange          = "price_range"               // Цена ограничена диапазоном скидки и наценки
+	GuardrailMarginFloor         = "margin_floor"              // Цена поднята до минимальной маржи
+	GuardrailDailyChange         = "max_daily_change"          // Изменение цены ограничено дневным лимитом
+	GuardrailNoFeasible          = "no_feasible_price"         // Ни одна цена не удовлетворяет ограничениям, сохранена предыдущая с допустимой маржой
+	GuardrailSegmentCap          = "abc_segment_cap"           // Скидка ограничена ABC-сегментом товара
+	GuardrailDailyChangeExceeded = "max_daily_change_exceeded" // Дневной лимит превышен, чтобы соблюсти минимальную маржу
+)
+
+// PricingGuardrails содержит ограничения на предлагаемые цены
+type PricingGuardrails struct {
+	MaxDailyChangePct float64   `json:"max_daily_change_pct"` // Максимальное изменение цены слота за день
+	MinMarginPct      float64   `json:"min_margin_pct"`       // Минимальная маржа от цены продажи
+	MaxDiscountPct    float64   `json:"max_discount_pct"`     // Максимальная скидка от базовой цены
+	MaxMarkupPct      float64   `json:"max_markup_pct"`       // Максимальная наценка к базовой цене
+	EndingStep        float64   `json:"ending_step"`          // Шаг, внутри которого задаются окончания цен, например 1 или 10
+	Endings           []float64 `json:"endings"`              // Допустимые остатки цены от деления на шаг, например 0.49 и 0.99
+}
+
+// Validate проверяет корректность данных в структуре PricingGuardrails
+func (g *PricingGuardrails) Validate() error {
+	if g.MaxDailyChangePct <= 0 {
+		return fmt.Errorf("max daily change must be positive, got %f", g.MaxDailyChangePct)
+	}
+
+	if g.MinMarginPct < 0 || g.MinMarginPct >= 100 {
+		return fmt.Errorf("min margin must be between 0 and 100, got %f", g.MinMarginPct)
+	}
+
+	if g.MaxDiscountPct < 0 || g.MaxDiscountPct >= 100 {
+		return fmt.Errorf("max discount must be between 0 and 100, got %f", g.MaxDiscountPct)
+	}
+
+	if g.MaxMarkupPct < 0 {
+		return fmt.Errorf("max markup cannot be negative, got %f", g.MaxMarkupPct)
+	}
+
+	if g.EndingStep <= 0 {
+		return fmt.Errorf("ending step must be positive, got %f", g.EndingStep)
+	}
+
+	if len(g.Endings) == 0 {
+		return errors.New("at least one price ending is required")
+	}
+
+	for _, ending := range g.Endings {
+		if ending < 0 || ending >= g.EndingStep {
+			return fmt.Errorf("price ending %f must be within [0, %f)", ending, g.EndingStep)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+// internal/domain/entities/pricing_run.go
+package entities
+
+import "time"
+
+// PricingRun содержит итоги пакетного расчета цен
+type PricingRun struct {
+	ID            string    `json:"id"`
+	EffectiveDate time.Time `json:"effective_date"`
+	StartedAt     time.Time `json:"started_at"`
+	FinishedAt    time.Time `json:"finished_at"`
+	Products      int       `json:"products"`
+	Suggestions   int       `json:"suggestions"`
+	Skipped       int       `json:"skipped"`
+	Constrained   int       `json:"constrained"` // Предложения, скорректированные ограничениями
+}
@@ -0,0 +1,28 @@
+// internal/domain/entities/product_bundle.go
+package entities
+
+import "time"
+
+// ProductBundle представляет предложенный набор товаров по специальной цене
+type ProductBundle struct {
+	BaseEntity
+	Name                  string       `json:"name"`
+	ProductIDs            []string     `json:"product_ids"`
+	AnchorProductID       string       `json:"anchor_product_id"` // Самый популярный товар набора, к которому присоединяются остальные
+	Support               float64      `json:"support"`
+	Lift                  float64      `json:"lift"`
+	CoPurchaseRate        float64      `json:"co_purchase_rate"` // Доля чеков с якорным товаром, где уже есть весь набор
+	ListPrice             float64      `json:"list_price"`       // Сумма обычных цен товаров
+	BundlePrice           float64      `json:"bundle_price"`
+	DiscountPct           float64      `json:"discount_pct"`
+	TotalCost             float64      `json:"total_cost"`
+	MarginPct             float64      `json:"margin_pct"` // Маржа набора в процентах от цены набора
+	CurrentAttachRate     float64      `json:"current_attach_rate"`
+	IncrementalAttachRate float64      `json:"incremental_attach_rate"` // Ожидаемый прирост доли присоединения при цене набора
+	ElasticityFitted      bool         `json:"elasticity_fitted"`       // false — использована чувствительность по умолчанию
+	Status                BundleStatus `json:"status"`
+	PeriodStart           time.Time    `json:"period_start"`
+	PeriodEnd             time.Time    `json:"period_end"`
+	ReviewedBy            string       `json:"reviewed_by,omitempty"`
+	ReviewedAt            time.Time    `json:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,37 @@
+// internal/domain/entities/product_cost.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ProductCost представляет себестоимость и цену товара, действующие с указанной даты до следующей записи товара
+type ProductCost struct {
+	ProductID     string    `json:"product_id"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	Cost          float64   `json:"cost"`
+	Price         float64   `json:"price,omitempty"` // Цена по прейскуранту; 0 — цена в записи не указана
+}
+
+// Validate проверяет корректность данных в структуре ProductCost
+func (c *ProductCost) Validate() error {
+	if c.ProductID == "" {
+		return errors.New("product ID is required")
+	}
+
+	if c.EffectiveFrom.IsZero() {
+		return errors.New("effective date is required")
+	}
+
+	if c.Cost < 0 {
+		return fmt.Errorf("cost cannot be negative, got %f", c.Cost)
+	}
+
+	if c.Price < 0 {
+		return fmt.Errorf("price cannot be negative, got %f", c.Price)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+// internal/domain/entities/product_cross_effect.go
+package entities
+
+// ProductCrossEffect содержит изменение продаж соседнего товара во время акции
+type ProductCrossEffect struct {
+	ProductID            string          `json:"product_id"`
+	Category             string          `json:"category"`
+	Relation             CrossEffectType `json:"relation"`
+	RuleLift             float64         `json:"rule_lift,omitempty"` // Lift ассоциативного правила с товаром под акцией
+	BaselineDailyUnits   float64         `json:"baseline_daily_units"`
+	PromoDailyUnits      float64         `json:"promo_daily_units"`
+	UnitChangePct        float64         `json:"unit_change_pct"`
+	BaselineDailyRevenue float64         `json:"baseline_daily_revenue"`
+	PromoDailyRevenue    float64         `json:"promo_daily_revenue"`
+	IncrementalRevenue   float64         `json:"incremental_revenue"` // За весь период акции
+}
@@ -0,0 +1,24 @@
+// internal/domain/entities/product_lifecycle.go
+package entities
+
+import "time"
+
+// ProductLifecycle содержит стадию жизненного цикла товара и оценку тренда его продаж
+type ProductLifecycle struct {
+	ProductID           string         `json:"product_id"`
+	ProductName         string         `json:"product_name"`
+	Category            string         `json:"category"`
+	Stage               LifecycleStage `json:"stage"`
+	FirstSaleDate       time.Time      `json:"first_sale_date"` // Первая продажа в пределах окна истории
+	LastSaleDate        time.Time      `json:"last_sale_date"`
+	WeeksSinceFirstSale int            `json:"weeks_since_first_sale"`
+	WeeklyGrowthRate    float64        `json:"weekly_growth_rate"` // Темп изменения недельных продаж по логарифмическому тренду
+	TrendTStat          float64        `json:"trend_t_stat"`
+	RecentWeeklyUnits   float64        `json:"recent_weekly_units"` // Средние продажи за последние 4 недели
+	PeakWeeklyUnits     float64        `json:"peak_weekly_units"`   // Максимум скользящего 4-недельного среднего
+	Seasonal            bool           `json:"seasonal"`
+	ABCCategory         Segment        `json:"abc_category,omitempty"`
+	DelistingCandidate  bool           `json:"delisting_candidate"`
+	DelistingReason     string         `json:"delisting_reason,omitempty"`
+	AnalysisDate        time.Time      `json:"analysis_date"`
+}
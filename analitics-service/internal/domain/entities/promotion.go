@@ -0,0 +1,85 @@
+// internal/domain/entities/promotion.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Promotion представляет запланированную акцию в промо-календаре
+type Promotion struct {
+	BaseEntity
+	Name          string            `json:"name"`
+	Description   string            `json:"description,omitempty"`
+	ProductIDs    []string          `json:"product_ids,omitempty"`
+	Categories    []string          `json:"categories,omitempty"`
+	Mechanic      PromotionMechanic `json:"mechanic"`
+	DiscountValue float64           `json:"discount_value"`         // Процент, сумма или цена в зависимости от механики
+	BuyQuantity   int               `json:"buy_quantity,omitempty"` // Для buy_x_get_y
+	GetQuantity   int               `json:"get_quantity,omitempty"` // Для buy_x_get_y
+	CouponCode    string            `json:"coupon_code,omitempty"`  // Для coupon_based
+	Channels      []string          `json:"channels,omitempty"`     // Каналы продаж: in_store, app, delivery
+	StartDate     time.Time         `json:"start_date"`
+	EndDate       time.Time         `json:"end_date"`
+}
+
+// Validate проверяет корректность данных в структуре Promotion
+func (p *Promotion) Validate() error {
+	if p.Name == "" {
+		return errors.New("promotion name is required")
+	}
+
+	if len(p.ProductIDs) == 0 && len(p.Categories) == 0 {
+		return errors.New("promotion must target at least one product or category")
+	}
+
+	if !isValidPromotionMechanic(p.Mechanic) {
+		return fmt.Errorf("invalid promotion mechanic: %s", p.Mechanic)
+	}
+
+	switch p.Mechanic {
+	case MechanicPercentOff:
+		if p.DiscountValue <= 0 || p.DiscountValue > 100 {
+			return fmt.Errorf("percent discount must be between 0 and 100, got %f", p.DiscountValue)
+		}
+	case MechanicAmountOff, MechanicFixedPrice:
+		if p.DiscountValue <= 0 {
+			return fmt.Errorf("discount value must be positive, got %f", p.DiscountValue)
+		}
+	case MechanicBuyXGetY:
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return fmt.Errorf("buy and get quantities must be positive, got %d and %d", p.BuyQuantity, p.GetQuantity)
+		}
+	case MechanicCouponBased:
+		if p.CouponCode == "" {
+			return errors.New("coupon code is required for coupon-based promotion")
+		}
+	}
+
+	if p.StartDate.IsZero() || p.EndDate.IsZero() {
+		return errors.New("promotion start and end dates are required")
+	}
+
+	if !p.StartDate.Before(p.EndDate) {
+		return fmt.Errorf("start date (%s) must be before end date (%s)",
+			p.StartDate.Format(time.RFC3339), p.EndDate.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Covers проверяет, распространяется ли акция на товар
+func (p *Promotion) Covers(product Product) bool {
+	for _, id := range p.ProductIDs {
+		if id == product.ID {
+			return true
+		}
+	}
+	for _, category := range p.Categories {
+		if category == product.Category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+// internal/domain/entities/promotion_cross_effect_analysis.go
+package entities
+
+import "time"
+
+// PromotionCrossEffectAnalysis содержит оценку каннибализации и halo-эффекта акции на товар
+// Период акции хранится в AnalysisMetadata
+type PromotionCrossEffectAnalysis struct {
+	AnalysisMetadata
+	ProductID                     string               `json:"product_id"`
+	Category                      string               `json:"category"`
+	BaselineStart                 time.Time            `json:"baseline_start"`
+	BaselineEnd                   time.Time            `json:"baseline_end"`
+	PromotedIncrementalRevenue    float64              `json:"promoted_incremental_revenue"`
+	CannibalizedRevenue           float64              `json:"cannibalized_revenue"` // Изменение выручки заменителей (обычно отрицательное)
+	HaloRevenue                   float64              `json:"halo_revenue"`         // Изменение выручки дополняющих товаров
+	NetCategoryIncrementalRevenue float64              `json:"net_category_incremental_revenue"`
+	NetIncrementalRevenue         float64              `json:"net_incremental_revenue"`
+	Effects                       []ProductCrossEffect `json:"effects"`
+}
@@ -0,0 +1,23 @@
+// internal/domain/entities/promotion_impact.go
+package entities
+
+import "time"
+
+// PromotionImpact содержит оценку эффекта акции методом разности разностей
+// Эффекты рассчитываются как изменение продаж товаров акции за вычетом изменения
+// продаж контрольных товаров относительно периода до акции
+type PromotionImpact struct {
+	PromotionID         string        `json:"promotion_id"`
+	AnalysisDate        time.Time     `json:"analysis_date"`
+	PreStart            time.Time     `json:"pre_start"`
+	PromoStart          time.Time     `json:"promo_start"`
+	PromoEnd            time.Time     `json:"promo_end"`
+	PostEnd             time.Time     `json:"post_end"`
+	TreatedProducts     []string      `json:"treated_products"`
+	ControlProducts     []string      `json:"control_products"`
+	ControlGroup        string        `json:"control_group"`         // Источник контрольной группы: explicit, same_category или other_categories
+	Incremental         ImpactMetrics `json:"incremental"`           // Прирост за период акции
+	PostPromotionDip    ImpactMetrics `json:"post_promotion_dip"`    // Эффект после акции (обычно отрицательный)
+	NetIncremental      ImpactMetrics `json:"net_incremental"`       // Прирост с учетом провала после акции
+	IncrementalUnitsPct float64       `json:"incremental_units_pct"` // Прирост относительно контрфактических продаж
+}
@@ -0,0 +1,26 @@
+// internal/domain/entities/promotion_impact_options.go
+package entities
+
+import (
+	"fmt"
+)
+
+// PromotionImpactOptions содержит параметры оценки эффекта акции
+type PromotionImpactOptions struct {
+	PrePeriodDays     int      `json:"pre_period_days"`               // 0 означает длительность акции
+	PostPeriodDays    int      `json:"post_period_days"`              // Период после акции для оценки провала спроса
+	ControlProductIDs []string `json:"control_product_ids,omitempty"` // Пустой список — товары тех же категорий вне акции, а при их отсутствии — других категорий
+}
+
+// Validate проверяет корректность данных в структуре PromotionImpactOptions
+func (o *PromotionImpactOptions) Validate() error {
+	if o.PrePeriodDays < 0 {
+		return fmt.Errorf("pre period days cannot be negative, got %d", o.PrePeriodDays)
+	}
+
+	if o.PostPeriodDays < 0 {
+		return fmt.Errorf("post period days cannot be negative, got %d", o.PostPeriodDays)
+	}
+
+	return nil
+}
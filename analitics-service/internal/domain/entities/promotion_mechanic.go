@@ -0,0 +1,23 @@
+// internal/domain/entities/promotion_mechanic.go
+package entities
+
+// PromotionMechanic определяет механику скидки в акции
+type PromotionMechanic string
+
+const (
+	MechanicPercentOff  PromotionMechanic = "percent_off"  // Скидка в процентах
+	MechanicAmountOff   PromotionMechanic = "amount_off"   // Скидка фиксированной суммой
+	MechanicFixedPrice  PromotionMechanic = "fixed_price"  // Фиксированная цена
+	MechanicBuyXGetY    PromotionMechanic = "buy_x_get_y"  // Купи X, получи Y в подарок
+	MechanicCouponBased PromotionMechanic = "coupon_based" // Скидка по купону
+)
+
+// isValidPromotionMechanic проверяет, является ли механика допустимой
+func isValidPromotionMechanic(m PromotionMechanic) bool {
+	switch m {
+	case MechanicPercentOff, MechanicAmountOff, MechanicFixedPrice, MechanicBuyXGetY, MechanicCouponBased:
+		return true
+	default:
+		return false
+	}
+}
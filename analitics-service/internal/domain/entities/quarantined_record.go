@@ -0,0 +1,19 @@
+// internal/domain/entities/quarantined_record.go
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// QuarantinedRecord представляет запись входных данных, исключенную из анализа проверкой качества
+// Payload содержит исходную запись до исправлений, чтобы ее можно было разобрать и загрузить повторно
+type QuarantinedRecord struct {
+	RunID         string          `json:"run_id"`
+	Source        string          `json:"source"` // Источник данных: transactions или sales
+	RecordID      string          `json:"record_id"`
+	Rule          DataQualityRule `json:"rule"`
+	Reason        string          `json:"reason"`
+	Payload       json.RawMessage `json:"payload"`
+	QuarantinedAt time.Time       `json:"quarantined_at"`
+}
@@ -1,9 +1,13 @@
 // internal/domain/entities/retention_metrics.go
 package entities
 
+import "time"
+
 // RetentionMetrics представляет метрики удержания клиентов
 type RetentionMetrics struct {
 	Period             TimeRange `json:"period"`
+	PeriodStart        time.Time `json:"period_start"`
+	PeriodEnd          time.Time `json:"period_end"`
 	ChurnRate          float64   `json:"churn_rate"`
 	RetentionRate      float64   `json:"retention_rate"`
 	NewCustomers       int       `json:"new_customers"`
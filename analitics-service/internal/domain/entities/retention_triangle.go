@@ -0,0 +1,14 @@
+// internal/domain/entities/retention_triangle.go
+package entities
+
+import "time"
+
+// RetentionTriangle представляет треугольник удержания когорт привлечения
+type RetentionTriangle struct {
+	Period           TimeRange   `json:"period"`
+	StartDate        time.Time   `json:"start_date"`
+	EndDate          time.Time   `json:"end_date"`
+	Cohorts          []CohortRow `json:"cohorts"`
+	AverageRetention []float64   `json:"average_retention"` // Удержание по смещению, взвешенное размером когорт
+	GeneratedAt      time.Time   `json:"generated_at"`
+}
@@ -0,0 +1,23 @@
+// internal/domain/entities/sales_anomaly.go
+package entities
+
+import "time"
+
+// SalesAnomaly представляет найденный всплеск или провал продаж
+type SalesAnomaly struct {
+	ID             string             `json:"id"`
+	ProductID      string             `json:"product_id,omitempty"` // Пустое значение — продажи всего магазина
+	Metric         string             `json:"metric"`               // units, revenue или transactions
+	Granularity    AnomalyGranularity `json:"granularity"`
+	PeriodStart    time.Time          `json:"period_start"`
+	Observed       float64            `json:"observed"`
+	Expected       float64            `json:"expected"`
+	Score          float64            `json:"score"` // Робастная z-оценка, знак указывает направление
+	IsSpike        bool               `json:"is_spike"`
+	Severity       AnomalySeverity    `json:"severity"`
+	Method         AnomalyMethod      `json:"method"`
+	Suppressed     bool               `json:"suppressed"` // Алерт подавлен из-за повышенных скидок в периоде
+	AvgDiscount    float64            `json:"avg_discount"`
+	NormalDiscount float64            `json:"normal_discount"`
+	DetectedAt     time.Time          `json:"detected_at"`
+}
@@ -0,0 +1,11 @@
+// internal/domain/entities/sample_ratio_check.go
+package entities
+
+// SampleRatioCheck содержит результат проверки на несоответствие соотношения групп (SRM)
+type SampleRatioCheck struct {
+	ExpectedTestShare float64 `json:"expected_test_share"`
+	ObservedTestShare float64 `json:"observed_test_share"`
+	ChiSquare         float64 `json:"chi_square"`
+	PValue            float64 `json:"p_value"`
+	Mismatch          bool    `json:"mismatch"`
+}
@@ -0,0 +1,10 @@
+// internal/domain/entities/segment_change.go
+package entities
+
+// SegmentChange представляет изменение итогового ABC-сегмента товара между двумя сегментациями
+// Пустой PreviousSegment означает новый товар, пустой Segment — товар, выбывший из сегментации
+type SegmentChange struct {
+	ProductID       string  `json:"product_id"`
+	PreviousSegment Segment `json:"previous_segment,omitempty"`
+	Segment         Segment `json:"segment,omitempty"`
+}
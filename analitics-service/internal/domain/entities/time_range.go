@@ -1,6 +1,8 @@
 // internal/domain/entities/time_range.go
 package entities
 
+import "time"
+
 // TimeRange представляет временные периоды для отчетов
 type TimeRange string
 
@@ -9,3 +11,34 @@ const (
 	Weekly  TimeRange = "weekly"
 	Monthly TimeRange = "monthly"
 )
+
+// IsValid проверяет, является ли период допустимым
+func (r TimeRange) IsValid() bool {
+	return r == Daily || r == Weekly || r == Monthly
+}
+
+// Truncate возвращает начало периода, содержащего момент t; недели начинаются с понедельника
+func (r TimeRange) Truncate(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch r {
+	case Weekly:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case Monthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// Advance сдвигает начало периода на n периодов
+func (r TimeRange) Advance(t time.Time, n int) time.Time {
+	switch r {
+	case Weekly:
+		return t.AddDate(0, 0, 7*n)
+	case Monthly:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
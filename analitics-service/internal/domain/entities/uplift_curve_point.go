@@ -0,0 +1,10 @@
+// internal/domain/entities/uplift_curve_point.go
+package entities
+
+// UpliftCurvePoint представляет точку Qini и uplift кривых
+type UpliftCurvePoint struct {
+	Fraction float64 `json:"fraction"` // Доля клиентов с наибольшим прогнозным uplift
+	Qini     float64 `json:"qini"`     // Дополнительные покупки в выбранной доле
+	Uplift   float64 `json:"uplift"`   // Разница конверсий групп со скидкой и без в выбранной доле
+	Random   float64 `json:"random"`   // Значение Qini при случайном выборе клиентов
+}
@@ -0,0 +1,10 @@
+// internal/domain/entities/uplift_evaluation.go
+package entities
+
+// UpliftEvaluation содержит оценку uplift-модели на отложенной выборке
+type UpliftEvaluation struct {
+	HoldoutRows     int                `json:"holdout_rows"`
+	QiniCoefficient float64            `json:"qini_coefficient"` // Площадь между Qini кривой модели и случайной
+	AUUC            float64            `json:"auuc"`             // Площадь под uplift кривой
+	Curve           []UpliftCurvePoint `json:"curve"`
+}
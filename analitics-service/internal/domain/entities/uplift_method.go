@@ -0,0 +1,12 @@
+// internal/domain/entities/uplift_method.go
+package entities
+
+// UpliftMethod определяет подход к построению uplift-модели
+type UpliftMethod string
+
+const (
+	// UpliftTwoModel — две модели вероятности покупки для групп со скидкой и без нее
+	UpliftTwoModel UpliftMethod = "two_model"
+	// UpliftClassTransformation — одна модель на преобразованной целевой переменной
+	UpliftClassTransformation UpliftMethod = "class_transformation"
+)
@@ -0,0 +1,22 @@
+// internal/domain/entities/uplift_model.go
+package entities
+
+import (
+	"time"
+)
+
+// UpliftModel представляет обученную uplift-модель
+// Для two_model заполнены TreatmentModel и ControlModel, для class_transformation — TransformedModel
+type UpliftModel struct {
+	ID               string               `json:"id"`
+	Method           UpliftMethod         `json:"method"`
+	Config           UpliftModelConfig    `json:"config"`
+	FeatureNames     []string             `json:"feature_names"`
+	TreatmentModel   *LogisticModelParams `json:"treatment_model,omitempty"`
+	ControlModel     *LogisticModelParams `json:"control_model,omitempty"`
+	TransformedModel *LogisticModelParams `json:"transformed_model,omitempty"`
+	TreatmentShare   float64              `json:"treatment_share"`
+	TrainingRows     int                  `json:"training_rows"`
+	Evaluation       UpliftEvaluation     `json:"evaluation"`
+	TrainedAt        time.Time            `json:"trained_at"`
+}
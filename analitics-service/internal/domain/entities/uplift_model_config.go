@@ -0,0 +1,63 @@
+// internal/domain/entities/uplift_model_config.go
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UpliftModelConfig содержит параметры обучения uplift-модели
+type UpliftModelConfig struct {
+	Method            UpliftMethod `json:"method"`
+	ExperimentIDs     []string     `json:"experiment_ids"`      // Эксперименты, показы которых образуют обучающую выборку
+	LookbackDays      int          `json:"lookback_days"`       // Окно истории клиента для признаков
+	OutcomeWindowDays int          `json:"outcome_window_days"` // Окно после показа, в котором учитывается покупка
+	HoldoutShare      float64      `json:"holdout_share"`       // Доля клиентов для оценки модели
+	Iterations        int          `json:"iterations"`
+	LearningRate      float64      `json:"learning_rate"`
+	L2                float64      `json:"l2"`
+	CurveBins         int          `json:"curve_bins"` // Количество точек Qini и uplift кривых
+}
+
+// DefaultUpliftModelConfig возвращает параметры обучения по умолчанию
+func DefaultUpliftModelConfig() UpliftModelConfig {
+	return UpliftModelConfig{
+		Method:            UpliftTwoModel,
+		LookbackDays:      180,
+		OutcomeWindowDays: 14,
+		HoldoutShare:      0.3,
+		Iterations:        500,
+		LearningRate:      0.1,
+		L2:                0.001,
+		CurveBins:         10,
+	}
+}
+
+// Validate проверяет корректность данных в структуре UpliftModelConfig
+func (c *UpliftModelConfig) Validate() error {
+	if c.Method != UpliftTwoModel && c.Method != UpliftClassTransformation {
+		return fmt.Errorf("invalid uplift method: %s", c.Method)
+	}
+
+	if len(c.ExperimentIDs) == 0 {
+		return errors.New("at least one experiment ID is required")
+	}
+
+	if c.LookbackDays <= 0 || c.OutcomeWindowDays <= 0 {
+		return fmt.Errorf("lookback and outcome windows must be positive, got %d and %d", c.LookbackDays, c.OutcomeWindowDays)
+	}
+
+	if c.HoldoutShare <= 0 || c.HoldoutShare >= 1 {
+		return fmt.Errorf("holdout share must be between 0 and 1, got %f", c.HoldoutShare)
+	}
+
+	if c.Iterations <= 0 || c.LearningRate <= 0 || c.L2 < 0 {
+		return fmt.Errorf("invalid optimizer parameters: iterations=%d learning_rate=%f l2=%f", c.Iterations, c.LearningRate, c.L2)
+	}
+
+	if c.CurveBins < 2 {
+		return fmt.Errorf("curve bins must be at least 2, got %d", c.CurveBins)
+	}
+
+	return nil
+}
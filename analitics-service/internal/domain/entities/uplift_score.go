@@ -0,0 +1,11 @@
+// internal/domain/entities/uplift_score.go
+package entities
+
+// UpliftScore представляет прогноз прироста вероятности покупки клиента под действием скидки
+type UpliftScore struct {
+	CustomerID         string  `json:"customer_id"`
+	Uplift             float64 `json:"uplift"`
+	ProbabilityTreated float64 `json:"probability_treated,omitempty"`
+	ProbabilityControl float64 `json:"probability_control,omitempty"`
+	Rank               int     `json:"rank"`
+}
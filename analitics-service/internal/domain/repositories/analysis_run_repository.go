@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// AnalysisRunRepository определяет интерфейс для работы с записями о запусках анализа
+type AnalysisRunRepository interface {
+	// SaveRun сохраняет запись о запуске анализа
+	SaveRun(ctx context.Context, run entities.AnalysisRun) error
+
+	// GetRunByID возвращает запуск по его ID или nil, если запуск не найден
+	GetRunByID(ctx context.Context, runID string) (*entities.AnalysisRun, error)
+
+	// GetRuns возвращает последние запуски анализа указанного вида, пустой вид — запуски всех видов
+	GetRuns(ctx context.Context, analysisType entities.AnalysisType, limit int) ([]entities.AnalysisRun, error)
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// AnomalyRepository определяет интерфейс для хранения найденных аномалий продаж
+type AnomalyRepository interface {
+	// SaveAnomalies сохраняет аномалии, повторное обнаружение того же периода и метрики обновляет запись
+	SaveAnomalies(ctx context.Context, anomalies []entities.SalesAnomaly) error
+
+	// GetAnomaliesByPeriod возвращает аномалии за период с серьезностью не ниже указанной
+	GetAnomaliesByPeriod(ctx context.Context, startDate, endDate time.Time, minSeverity entities.AnomalySeverity) ([]entities.SalesAnomaly, error)
+
+	// GetAnomaliesByProduct возвращает аномалии товара за период
+	GetAnomaliesByProduct(ctx context.Context, productID string, startDate, endDate time.Time) ([]entities.SalesAnomaly, error)
+}
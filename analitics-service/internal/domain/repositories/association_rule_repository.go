@@ -25,4 +25,8 @@ type AssociationRuleRepository interface {
 
 	// GetRulesByLift возвращает ассоциативные правила с подъемом выше указанного порога
 	GetRulesByLift(ctx context.Context, minLift float64) ([]entities.AssociationRule, error)
+
+	// StreamRules передает сохраненные правила в fn по одному, не загружая весь набор в память
+	// Ошибка, возвращенная fn, прерывает чтение и возвращается вызывающему
+	StreamRules(ctx context.Context, fn func(rule entities.AssociationRule) error) error
 }
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// BanditRepository определяет интерфейс для хранения бандитов и статистики их рук
+type BanditRepository interface {
+	// SaveBandit создает или обновляет бандита вместе с его руками
+	SaveBandit(ctx context.Context, bandit entities.Bandit, arms []entities.BanditArm) error
+
+	// GetBanditByID возвращает бандита по его ID или пустого бандита, если его нет
+	GetBanditByID(ctx context.Context, banditID string) (entities.Bandit, error)
+
+	// GetActiveBandit возвращает активного бандита для товара или категории
+	GetActiveBandit(ctx context.Context, productID, category string) (entities.Bandit, error)
+
+	// GetArms возвращает руки бандита с накопленной статистикой
+	GetArms(ctx context.Context, banditID string) ([]entities.BanditArm, error)
+
+	// RecordArmOutcome атомарно добавляет наблюдение к статистике руки
+	RecordArmOutcome(ctx context.Context, banditID, armID string, reward float64, success bool) error
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// BundleRepository определяет интерфейс для работы с предложенными наборами товаров
+type BundleRepository interface {
+	// SaveBundles создает или обновляет наборы
+	SaveBundles(ctx context.Context, bundles []entities.ProductBundle) error
+
+	// GetBundleByID возвращает набор по его ID
+	GetBundleByID(ctx context.Context, bundleID string) (entities.ProductBundle, error)
+
+	// GetBundlesByStatus возвращает наборы с указанным статусом
+	GetBundlesByStatus(ctx context.Context, status entities.BundleStatus) ([]entities.ProductBundle, error)
+
+	// UpdateBundle обновляет набор после решения команды меню
+	UpdateBundle(ctx context.Context, bundle entities.ProductBundle) error
+}
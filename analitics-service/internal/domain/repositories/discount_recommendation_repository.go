@@ -22,4 +22,7 @@ type DiscountRecommendationRepository interface {
 
 	// GetLatestRecommendations возвращает последние рекомендации по скидкам
 	GetLatestRecommendations(ctx context.Context, limit int) ([]entities.DiscountRecommendation, error)
+
+	// GetRecommendationsByRunID возвращает рекомендации, сохраненные запуском анализа
+	GetRecommendationsByRunID(ctx context.Context, runID string) ([]entities.DiscountRecommendation, error)
 }
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// ExperimentRepository определяет интерфейс для работы с реестром экспериментов
+type ExperimentRepository interface {
+	// SaveExperiment создает или обновляет эксперимент
+	SaveExperiment(ctx context.Context, experiment entities.Experiment) error
+
+	// GetExperimentByID возвращает эксперимент по его ID
+	GetExperimentByID(ctx context.Context, experimentID string) (entities.Experiment, error)
+
+	// GetExperimentsByLayer возвращает все эксперименты указанного слоя
+	GetExperimentsByLayer(ctx context.Context, layerID string) ([]entities.Experiment, error)
+
+	// GetActiveExperiments возвращает эксперименты, запущенные на указанный момент времени
+	GetActiveExperiments(ctx context.Context, at time.Time) ([]entities.Experiment, error)
+}
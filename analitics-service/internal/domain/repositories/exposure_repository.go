@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// ExposureRepository определяет интерфейс для работы с журналом показов экспериментов
+type ExposureRepository interface {
+	// LogExposure сохраняет факт показа варианта клиенту
+	LogExposure(ctx context.Context, exposure entities.Exposure) error
+
+	// GetFirstExposures возвращает первый показ эксперимента каждому клиенту
+	GetFirstExposures(ctx context.Context, experimentID string) ([]entities.Exposure, error)
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// ForecastRepository определяет интерфейс для хранения прогнозов спроса
+type ForecastRepository interface {
+	// SaveForecast сохраняет прогноз вместе с точками и результатами бэктеста
+	SaveForecast(ctx context.Context, forecast entities.DemandForecast) error
+
+	// GetLatestForecast возвращает последний прогноз для товара или категории
+	GetLatestForecast(ctx context.Context, level entities.ForecastLevel, targetID string) (entities.DemandForecast, error)
+
+	// GetForecastsGeneratedAfter возвращает прогнозы, построенные после указанного момента
+	GetForecastsGeneratedAfter(ctx context.Context, level entities.ForecastLevel, after time.Time) ([]entities.DemandForecast, error)
+}
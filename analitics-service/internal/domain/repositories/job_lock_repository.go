@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// JobLockRepository определяет интерфейс распределенных блокировок фоновых задач
+// Блокировка гарантирует, что задачу одновременно выполняет только одна реплика сервиса
+type JobLockRepository interface {
+	// TryAcquireLock захватывает блокировку задачи на время ttl, если она свободна или истекла
+	// Возвращает false, если блокировку удерживает другой запуск
+	TryAcquireLock(ctx context.Context, jobName, owner string, ttl time.Duration) (bool, error)
+
+	// ReleaseLock освобождает блокировку задачи, если она принадлежит owner
+	ReleaseLock(ctx context.Context, jobName, owner string) error
+
+	// TryClaimSlot закрепляет за owner запуск задачи по расписанию на время scheduledAt
+	// Запись о слоте хранится и после завершения запуска, поэтому тот же запуск не выполнит другая реплика
+	// Возвращает false, если слот уже закреплен
+	TryClaimSlot(ctx context.Context, jobName string, scheduledAt time.Time, owner string) (bool, error)
+
+	// DeleteSlotsBefore удаляет записи о слотах, запланированных раньше before, и возвращает их количество
+	DeleteSlotsBefore(ctx context.Context, before time.Time) (int64, error)
+}
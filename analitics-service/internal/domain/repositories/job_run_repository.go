@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// JobRunRepository определяет интерфейс для работы с историей запусков фоновых задач
+type JobRunRepository interface {
+	// CreateRun сохраняет новый запуск задачи
+	CreateRun(ctx context.Context, run entities.JobRun) error
+
+	// UpdateRun обновляет статус, длительность и ошибку завершенного запуска
+	UpdateRun(ctx context.Context, run entities.JobRun) error
+
+	// GetRunByID возвращает запуск по его ID или nil, если запуск не найден
+	GetRunByID(ctx context.Context, runID string) (*entities.JobRun, error)
+
+	// GetRuns возвращает последние запуски задачи, начиная с самых новых; пустое имя — запуски всех задач
+	GetRuns(ctx context.Context, jobName string, limit int) ([]entities.JobRun, error)
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// OutboxRepository определяет интерфейс работы с таблицей исходящих событий
+type OutboxRepository interface {
+	// Enqueue добавляет события в очередь на публикацию
+	// Вызывается в транзакции изменения данных, чтобы события сохранялись только вместе с ним
+	Enqueue(ctx context.Context, events []entities.OutboxEvent) error
+
+	// GetPending возвращает до limit неопубликованных событий в порядке добавления
+	GetPending(ctx context.Context, limit int) ([]entities.OutboxEvent, error)
+
+	// MarkPublished отмечает события опубликованными
+	MarkPublished(ctx context.Context, sequences []int64) error
+
+	// MarkFailed увеличивает счетчик попыток публикации события и сохраняет текст ошибки
+	MarkFailed(ctx context.Context, sequence int64, publishErr string) error
+
+	// DeletePublishedBefore удаляет события, опубликованные раньше before, и возвращает их количество
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
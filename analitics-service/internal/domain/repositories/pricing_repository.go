@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// PricingRepository определяет интерфейс для журнала предложенных цен
+type PricingRepository interface {
+	// SaveSuggestions добавляет предложенные цены в журнал, записи журнала не изменяются
+	SaveSuggestions(ctx context.Context, suggestions []entities.PriceSuggestion) error
+
+	// GetLatestSuggestion возвращает последнее предложение для слота товара с датой действия раньше указанной
+	// Возвращает nil, если предложений еще не было
+	GetLatestSuggestion(ctx context.Context, productID, timeSlot string, before time.Time) (*entities.PriceSuggestion, error)
+
+	// GetSuggestionHistory возвращает журнал предложенных цен товара за период
+	GetSuggestionHistory(ctx context.Context, productID string, startDate, endDate time.Time) ([]entities.PriceSuggestion, error)
+
+	// SavePricingRun сохраняет итоги пакетного расчета цен
+	SavePricingRun(ctx context.Context, run entities.PricingRun) error
+}
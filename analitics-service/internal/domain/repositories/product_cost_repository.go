@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// ProductCostRepository определяет интерфейс для работы с историей себестоимости и цен товаров
+type ProductCostRepository interface {
+	// GetCostHistory возвращает историю себестоимости всех товаров
+	GetCostHistory(ctx context.Context) ([]entities.ProductCost, error)
+
+	// GetProductCostHistory возвращает историю себестоимости товара по возрастанию даты начала действия
+	GetProductCostHistory(ctx context.Context, productID string) ([]entities.ProductCost, error)
+
+	// SaveCosts сохраняет записи истории; запись товара с той же датой начала действия заменяется
+	SaveCosts(ctx context.Context, costs []entities.ProductCost) error
+}
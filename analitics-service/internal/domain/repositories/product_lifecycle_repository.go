@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// ProductLifecycleRepository определяет интерфейс для работы со стадиями жизненного цикла товаров
+type ProductLifecycleRepository interface {
+	// SaveLifecycles сохраняет результаты классификации, заменяя предыдущие
+	SaveLifecycles(ctx context.Context, lifecycles []entities.ProductLifecycle) error
+
+	// GetLatestLifecycles возвращает результаты последней классификации всех товаров
+	GetLatestLifecycles(ctx context.Context) ([]entities.ProductLifecycle, error)
+
+	// GetLifecyclesByStage возвращает товары с указанной стадией по последней классификации
+	GetLifecyclesByStage(ctx context.Context, stage entities.LifecycleStage) ([]entities.ProductLifecycle, error)
+
+	// GetDelistingCandidates возвращает кандидатов на вывод из ассортимента по последней классификации
+	GetDelistingCandidates(ctx context.Context) ([]entities.ProductLifecycle, error)
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// PromotionRepository определяет интерфейс для работы с промо-календарем
+type PromotionRepository interface {
+	// SavePromotion создает или обновляет акцию
+	SavePromotion(ctx context.Context, promotion entities.Promotion) error
+
+	// GetPromotionByID возвращает акцию по её ID
+	GetPromotionByID(ctx context.Context, promotionID string) (entities.Promotion, error)
+
+	// GetPromotionsByPeriod возвращает акции, пересекающиеся с указанным периодом
+	GetPromotionsByPeriod(ctx context.Context, startDate, endDate time.Time) ([]entities.Promotion, error)
+
+	// GetPromotionsByProduct возвращает акции, распространяющиеся на товар
+	GetPromotionsByProduct(ctx context.Context, productID string) ([]entities.Promotion, error)
+
+	// DeletePromotion удаляет акцию по её ID
+	DeletePromotion(ctx context.Context, promotionID string) error
+
+	// SavePromotionImpact сохраняет результат оценки эффекта акции
+	SavePromotionImpact(ctx context.Context, impact entities.PromotionImpact) error
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// QuarantineRepository определяет интерфейс для работы с записями, исключенными проверкой качества данных
+type QuarantineRepository interface {
+	// SaveQuarantined сохраняет записи карантина запуска анализа
+	SaveQuarantined(ctx context.Context, records []entities.QuarantinedRecord) error
+
+	// GetQuarantined возвращает записи карантина запуска анализа
+	GetQuarantined(ctx context.Context, runID string) ([]entities.QuarantinedRecord, error)
+}
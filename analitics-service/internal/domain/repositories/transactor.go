@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+)
+
+// Transactor определяет интерфейс выполнения операций нескольких репозиториев в одной транзакции
+// Репозитории выполняют запросы в транзакции, переданной через контекст fn, если она есть
+type Transactor interface {
+	// WithinTransaction выполняет fn в транзакции: фиксирует ее, если fn завершилась без ошибки, иначе откатывает
+	// Вложенный вызов выполняется в уже открытой транзакции
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// UpliftModelRepository определяет интерфейс для хранения uplift-моделей и целевых списков
+type UpliftModelRepository interface {
+	// SaveModel сохраняет обученную модель
+	SaveModel(ctx context.Context, model entities.UpliftModel) error
+
+	// GetModelByID возвращает модель по её ID
+	GetModelByID(ctx context.Context, modelID string) (entities.UpliftModel, error)
+
+	// GetLatestModel возвращает последнюю обученную модель
+	GetLatestModel(ctx context.Context) (entities.UpliftModel, error)
+
+	// SaveTargetList сохраняет ранжированный список клиентов для таргетирования скидки
+	SaveTargetList(ctx context.Context, modelID string, scores []entities.UpliftScore) error
+}
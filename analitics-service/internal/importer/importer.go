@@ -0,0 +1,144 @@
+// internal/importer/importer.go
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+
+	"analitics-service/internal/infrastructure/postgres"
+)
+
+// Result содержит итоги импорта
+type Result struct {
+	Records    int `json:"records"`    // Прочитано записей файла
+	Rejected   int `json:"rejected"`   // Отклонено записей
+	Valid      int `json:"valid"`      // Сущностей, прошедших проверку
+	Imported   int `json:"imported"`   // Добавлено сущностей
+	Duplicates int `json:"duplicates"` // Пропущено сущностей, загруженных ранее или повторяющихся в файле
+}
+
+// Options содержит обработчики событий импорта
+type Options struct {
+	Rejected func(rejection Rejection) error // Вызывается для каждой отклоненной записи
+	Progress func(result Result)             // Вызывается после записи каждой пачки
+}
+
+// Importer загружает исторические выгрузки кассовых систем в таблицы исходных данных
+// Каждая пачка записывается в отдельной транзакции, уже загруженные сущности пропускаются
+// по естественному ключу, поэтому прерванный импорт можно безопасно запустить повторно
+type Importer struct {
+	db      *sql.DB
+	mapping *Mapping
+	options Options
+}
+
+// New создает импортер; при db == nil файл только проверяется, без записи в базу
+func New(db *sql.DB, mapping *Mapping, options Options) *Importer {
+	return &Importer{
+		db:      db,
+		mapping: mapping,
+		options: options,
+	}
+}
+
+// Import читает записи source в указанном формате и загружает сущности пачками через COPY
+func (i *Importer) Import(ctx context.Context, source io.Reader, format Format) (*Result, error) {
+	reader, err := newRecordReader(format, source, i.mapping.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.db != nil {
+		if _, err := i.db.ExecContext(ctx, postgres.SourceSchema); err != nil {
+			return nil, fmt.Errorf("failed to create source tables: %w", err)
+		}
+	}
+
+	loader := newBatchLoader(i.mapping.Entity)
+	result := &Result{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read record %d: %w", result.Records+1, err)
+		}
+		result.Records++
+
+		if record.Err != nil {
+			err = i.reject(result, []Rejection{{Line: record.Line, Reason: record.Err.Error()}})
+		} else {
+			err = i.reject(result, loader.add(&row{mapping: i.mapping, record: record}))
+		}
+		if err != nil {
+			return result, err
+		}
+
+		if loader.size() >= i.mapping.BatchSize {
+			if err := i.flush(ctx, loader, result); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if err := i.reject(result, loader.finish()); err != nil {
+		return result, err
+	}
+	if err := i.flush(ctx, loader, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// reject учитывает отклоненные записи и передает их обработчику
+func (i *Importer) reject(result *Result, rejections []Rejection) error {
+	for _, rejection := range rejections {
+		result.Rejected++
+		if i.options.Rejected != nil {
+			if err := i.options.Rejected(rejection); err != nil {
+				return fmt.Errorf("failed to report rejected record: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// flush записывает накопленную пачку в отдельной транзакции
+func (i *Importer) flush(ctx context.Context, loader batchLoader, result *Result) error {
+	size := loader.size()
+	if size == 0 {
+		return nil
+	}
+	result.Valid += size
+
+	if i.db != nil {
+		tx, err := i.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		inserted, err := loader.flush(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit batch: %w", err)
+		}
+		result.Imported += inserted
+		result.Duplicates += size - inserted
+	}
+
+	loader.reset()
+	if i.options.Progress != nil {
+		i.options.Progress(*result)
+	}
+	return nil
+}
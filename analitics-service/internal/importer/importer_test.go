@@ -0,0 +1,280 @@
+// internal/importer/importer_test.go
+package importer_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"analitics-service/internal/importer"
+)
+
+// testTransactionMapping возвращает сопоставление выгрузки кассы с десятичной запятой и датами в формате ДД.ММ.ГГГГ
+func testTransactionMapping(t *testing.T) *importer.Mapping {
+	t.Helper()
+
+	mapping := &importer.Mapping{
+		Entity:       importer.EntityTransactions,
+		Delimiter:    ";",
+		TimeFormat:   "02.01.2006 15:04",
+		Timezone:     "Europe/Moscow",
+		DecimalComma: true,
+		BatchSize:    2,
+		Columns: map[string]string{
+			"id":          "receipt_no",
+			"customer_id": "card_number",
+			"date":        "created_at",
+			"product_id":  "sku",
+			"name":        "item_name",
+			"price":       "unit_price",
+			"quantity":    "qty",
+		},
+		Defaults: map[string]string{"category": "unknown"},
+	}
+	if err := mapping.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	return mapping
+}
+
+func TestMappingValidate(t *testing.T) {
+	valid := func() importer.Mapping {
+		return importer.Mapping{
+			Entity: importer.EntitySales,
+			Columns: map[string]string{
+				"product_id": "sku", "quantity": "qty", "price": "price",
+				"purchase_date": "date", "customer_id": "card", "transaction_id": "receipt",
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(m *importer.Mapping)
+		wantError string
+	}{
+		{name: "valid", modify: func(m *importer.Mapping) {}},
+		{name: "required field from default", modify: func(m *importer.Mapping) {
+			delete(m.Columns, "customer_id")
+			m.Defaults = map[string]string{"customer_id": "anonymous"}
+		}},
+		{name: "unknown entity", modify: func(m *importer.Mapping) { m.Entity = "orders" }, wantError: "unsupported entity"},
+		{name: "unknown format", modify: func(m *importer.Mapping) { m.Format = "xml" }, wantError: "unsupported format"},
+		{name: "long delimiter", modify: func(m *importer.Mapping) { m.Delimiter = ";;" }, wantError: "delimiter"},
+		{name: "negative batch size", modify: func(m *importer.Mapping) { m.BatchSize = -1 }, wantError: "batch size"},
+		{name: "invalid timezone", modify: func(m *importer.Mapping) { m.Timezone = "Mars/Olympus" }, wantError: "invalid timezone"},
+		{name: "no columns", modify: func(m *importer.Mapping) { m.Columns = nil }, wantError: "at least one column"},
+		{name: "unknown field", modify: func(m *importer.Mapping) { m.Columns["cashier"] = "cashier" }, wantError: "unknown sales fields: cashier"},
+		{name: "missing required fields", modify: func(m *importer.Mapping) {
+			delete(m.Columns, "price")
+			delete(m.Columns, "customer_id")
+		}, wantError: "not mapped: customer_id, price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping := valid()
+			tt.modify(&mapping)
+
+			err := mapping.Validate()
+			if tt.wantError == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				if mapping.Delimiter != "," || mapping.BatchSize <= 0 {
+					t.Errorf("defaults not applied: delimiter %q, batch size %d", mapping.Delimiter, mapping.BatchSize)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestMappingFormatFor(t *testing.T) {
+	tests := []struct {
+		path   string
+		format importer.Format
+		want   importer.Format
+	}{
+		{path: "sales.csv", want: importer.FormatCSV},
+		{path: "sales.jsonl", want: importer.FormatJSONL},
+		{path: "sales.ndjson", want: importer.FormatJSONL},
+		{path: "sales.txt", format: importer.FormatJSONL, want: importer.FormatJSONL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			mapping := importer.Mapping{Format: tt.format}
+			if got := mapping.FormatFor(tt.path); got != tt.want {
+				t.Errorf("FormatFor(%s) = %s, want %s", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportTransactionsValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantRecords  int
+		wantRejected int
+		wantValid    int
+		// Номера отклоненных строк файла
+		wantLines []int
+	}{
+		{
+			name: "rows grouped into receipts",
+			input: "receipt_no;card_number;created_at;sku;item_name;unit_price;qty\n" +
+				"R1;C1;01.03.2024 10:15;P1;Tea;1 299,50;2\n" +
+				"R1;C1;01.03.2024 10:15;P2;Cup;99,90;1\n" +
+				"R2;C2;01.03.2024 11:00;P1;Tea;1299,50;1,000\n",
+			wantRecords: 3,
+			wantValid:   2,
+		},
+		{
+			// Ошибка в одной позиции отклоняет все строки чека
+			name: "invalid item rejects whole receipt",
+			input: "receipt_no;card_number;created_at;sku;item_name;unit_price;qty\n" +
+				"R1;C1;01.03.2024 10:15;P1;Tea;100;1\n" +
+				"R1;C1;01.03.2024 10:15;P2;Cup;abc;1\n" +
+				"R2;C2;01.03.2024 11:00;P1;Tea;100;1\n",
+			wantRecords:  3,
+			wantRejected: 2,
+			wantValid:    1,
+			wantLines:    []int{2, 3},
+		},
+		{
+			name: "receipt without items data",
+			input: "receipt_no;card_number;created_at;sku;item_name;unit_price;qty\n" +
+				"R1;C1;01.03.2024 10:15;P1;;100;1\n" +
+				"R2;C2;32.03.2024 11:00;P1;Tea;100;1\n" +
+				"R3;C3;01.03.2024 12:00;P1;Tea;100;0\n",
+			wantRecords:  3,
+			wantRejected: 3,
+			wantLines:    []int{2, 3, 4},
+		},
+		{
+			name: "malformed csv line",
+			input: "receipt_no;card_number;created_at;sku;item_name;unit_price;qty\n" +
+				"R1;C1;01.03.2024 10:15;P1;Tea;100\n" +
+				"R2;C2;01.03.2024 11:00;P1;Tea;100;1\n",
+			wantRecords:  2,
+			wantRejected: 1,
+			wantValid:    1,
+			wantLines:    []int{2},
+		},
+		{
+			// Без номера чека и даты строку нельзя отнести к чеку
+			name: "missing receipt key",
+			input: "receipt_no;card_number;created_at;sku;item_name;unit_price;qty\n" +
+				";C1;;P1;Tea;100;1\n" +
+				";C1;01.03.2024 10:15;P1;Tea;100;1\n" +
+				";C1;01.03.2024 10:15;P2;Cup;50;1\n",
+			wantRecords:  3,
+			wantRejected: 1,
+			wantValid:    1,
+			wantLines:    []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []int
+			imp := importer.New(nil, testTransactionMapping(t), importer.Options{
+				Rejected: func(rejection importer.Rejection) error {
+					lines = append(lines, rejection.Line)
+					return nil
+				},
+			})
+
+			result, err := imp.Import(context.Background(), strings.NewReader(tt.input), importer.FormatCSV)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if result.Records != tt.wantRecords || result.Rejected != tt.wantRejected || result.Valid != tt.wantValid {
+				t.Errorf("result = %+v, want records %d, rejected %d, valid %d",
+					*result, tt.wantRecords, tt.wantRejected, tt.wantValid)
+			}
+			if result.Imported != 0 || result.Duplicates != 0 {
+				t.Errorf("validation-only import wrote %d and skipped %d entities", result.Imported, result.Duplicates)
+			}
+			if len(lines) != len(tt.wantLines) {
+				t.Fatalf("rejected lines = %v, want %v", lines, tt.wantLines)
+			}
+			for i := range lines {
+				if lines[i] != tt.wantLines[i] {
+					t.Errorf("rejected lines = %v, want %v", lines, tt.wantLines)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestImportSalesJSONL(t *testing.T) {
+	mapping := &importer.Mapping{
+		Entity: importer.EntitySales,
+		Columns: map[string]string{
+			"product_id": "sku", "quantity": "qty", "price": "price",
+			"purchase_date": "date", "customer_id": "card", "transaction_id": "receipt",
+		},
+		BatchSize: 1,
+	}
+	if err := mapping.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	input := `{"sku": "P1", "qty": 2, "price": 10.5, "date": "2024-03-01", "card": "C1", "receipt": "R1"}
+{"sku": "P2", "qty": 1, "price": 5, "date": "2024-03-01T10:00:00+03:00", "card": "C1", "receipt": "R1", "discount": null}
+
+{"sku": "P3", "qty": 1, "price": 5, "date": "2024-03-01", "card": "C1", "receipt": "R1", "tags": ["promo"]}
+{"sku": "P4", "qty": 0, "price": 5, "date": "2024-03-01", "card": "C1", "receipt": "R1"}
+{"sku": "P5",
+`
+
+	var rejected []importer.Rejection
+	var progress []importer.Result
+	imp := importer.New(nil, mapping, importer.Options{
+		Rejected: func(rejection importer.Rejection) error {
+			rejected = append(rejected, rejection)
+			return nil
+		},
+		Progress: func(result importer.Result) { progress = append(progress, result) },
+	})
+
+	result, err := imp.Import(context.Background(), strings.NewReader(input), importer.FormatJSONL)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Records != 5 || result.Valid != 2 || result.Rejected != 3 {
+		t.Errorf("result = %+v, want 5 records, 2 valid, 3 rejected", *result)
+	}
+	// Пачки по одной продаже: прогресс сообщается после каждой записанной пачки
+	if len(progress) != 2 {
+		t.Errorf("progress callbacks = %d, want 2", len(progress))
+	}
+
+	tests := []struct {
+		line   int
+		reason string
+	}{
+		{line: 4, reason: "nested values"},
+		{line: 5, reason: "quantity must be positive"},
+		{line: 6, reason: "invalid JSON"},
+	}
+	if len(rejected) != len(tests) {
+		t.Fatalf("rejected = %+v, want %d records", rejected, len(tests))
+	}
+	for i, tt := range tests {
+		if rejected[i].Line != tt.line || !strings.Contains(rejected[i].Reason, tt.reason) {
+			t.Errorf("rejection %d = line %d %q, want line %d %q", i, rejected[i].Line, rejected[i].Reason, tt.line, tt.reason)
+		}
+	}
+	// Ключ продажи без ID строится по чеку и товару
+	if !strings.HasPrefix(rejected[1].Key, "sale-") {
+		t.Errorf("natural key = %q, want sale- prefix", rejected[1].Key)
+	}
+}
@@ -0,0 +1,115 @@
+// internal/importer/loader.go
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Rejection описывает отклоненную запись файла импорта
+type Rejection struct {
+	Line   int               `json:"line"`
+	Key    string            `json:"key,omitempty"` // Естественный ключ сущности, если его удалось определить
+	Reason string            `json:"reason"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// errProductID возвращается для товара без ID: без него товар нельзя сопоставить с продажами
+var errProductID = errors.New("product ID is required")
+
+// rejection создает описание отклоненной записи
+func rejection(record Record, key string, err error) Rejection {
+	return Rejection{Line: record.Line, Key: key, Reason: err.Error(), Fields: record.Fields}
+}
+
+// batchLoader накапливает сущности одного вида и записывает их пачками
+type batchLoader interface {
+	// add разбирает запись и возвращает отклоненные записи
+	add(r *row) []Rejection
+
+	// finish завершает разбор файла и возвращает отклоненные записи последней группы
+	finish() []Rejection
+
+	// size возвращает количество сущностей, готовых к записи
+	size() int
+
+	// flush записывает готовые сущности, пропуская уже загруженные, и возвращает количество добавленных
+	flush(ctx context.Context, tx *sql.Tx) (int, error)
+
+	// reset очищает записанную пачку
+	reset()
+}
+
+// newBatchLoader создает накопитель сущностей вида, указанного в сопоставлении
+func newBatchLoader(entity Entity) batchLoader {
+	switch entity {
+	case EntityProducts:
+		return &productLoader{}
+	case EntityProductCosts:
+		return &productCostLoader{}
+	case EntityTransactions:
+		return &transactionLoader{}
+	}
+	return &saleLoader{}
+}
+
+// naturalKey возвращает детерминированный ID сущности по значениям ее естественного ключа
+// Используется, когда в выгрузке нет собственного ID записи, чтобы повторный импорт находил дубликаты
+func naturalKey(prefix string, parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x1f")))
+	return prefix + "-" + hex.EncodeToString(sum[:12])
+}
+
+// stage создает временную таблицу по образцу public.<target> и заполняет ее через COPY
+// Таблица удаляется при завершении транзакции
+func stage(ctx context.Context, tx *sql.Tx, target string, columns []string, rows [][]interface{}) error {
+	staging := "import_" + target
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TEMP TABLE %s (LIKE public.%s INCLUDING DEFAULTS) ON COMMIT DROP", staging, target,
+	)); err != nil {
+		return fmt.Errorf("failed to create staging table %s: %w", staging, err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(staging, columns...))
+	if err != nil {
+		return fmt.Errorf("failed to prepare copy into %s: %w", staging, err)
+	}
+	defer stmt.Close()
+
+	for _, values := range rows {
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return fmt.Errorf("failed to copy into %s: %w", staging, err)
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to copy into %s: %w", staging, err)
+	}
+	return nil
+}
+
+// merge переносит строки временной таблицы в public.<target>, пропуская ключи, которые уже есть в таблице
+// или повторяются в пачке, и возвращает количество добавленных строк
+func merge(ctx context.Context, tx *sql.Tx, target string, columns []string, key string) (int, error) {
+	list := strings.Join(columns, ", ")
+	query := fmt.Sprintf(
+		"INSERT INTO public.%[1]s (%[2]s) SELECT DISTINCT ON (%[3]s) %[2]s FROM import_%[1]s ORDER BY %[3]s ON CONFLICT (%[3]s) DO NOTHING",
+		target, list, key,
+	)
+
+	result, err := tx.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to merge %s: %w", target, err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(inserted), nil
+}
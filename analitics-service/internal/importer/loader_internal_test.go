@@ -0,0 +1,128 @@
+// internal/importer/loader_internal_test.go
+package importer
+
+import (
+	"math"
+	"testing"
+)
+
+// testLoaderMapping возвращает сопоставление, где поля сущности называются так же, как колонки файла
+func testLoaderMapping(t *testing.T, entity Entity, fields ...string) *Mapping {
+	t.Helper()
+
+	mapping := &Mapping{Entity: entity, Columns: make(map[string]string)}
+	for _, field := range fields {
+		mapping.Columns[field] = field
+	}
+	if err := mapping.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	return mapping
+}
+
+func TestTransactionLoaderMergesReceiptLines(t *testing.T) {
+	mapping := testLoaderMapping(t, EntityTransactions,
+		"id", "customer_id", "date", "total_amount", "product_id", "name", "price", "quantity", "discount_pct")
+
+	tests := []struct {
+		name       string
+		records    []map[string]string
+		wantItems  map[string]int
+		wantTotal  float64
+		wantSameID bool
+	}{
+		{
+			// Повторные строки товара в чеке суммируются, итог считается по позициям со скидкой
+			name: "repeated product lines summed",
+			records: []map[string]string{
+				{"id": "R1", "customer_id": "C1", "date": "2024-03-01", "product_id": "P1", "name": "Tea", "price": "100", "quantity": "1"},
+				{"id": "R1", "customer_id": "C1", "date": "2024-03-01", "product_id": "P1", "name": "Tea", "price": "100", "quantity": "2"},
+				{"id": "R1", "customer_id": "C1", "date": "2024-03-01", "product_id": "P2", "name": "Cup", "price": "33.335", "discount_pct": "10"},
+			},
+			wantItems: map[string]int{"P1": 3, "P2": 1},
+			wantTotal: 330,
+		},
+		{
+			name: "total from export kept",
+			records: []map[string]string{
+				{"id": "R1", "customer_id": "C1", "date": "2024-03-01", "total_amount": "250", "product_id": "P1", "name": "Tea", "price": "100", "quantity": "3"},
+			},
+			wantItems: map[string]int{"P1": 3},
+			wantTotal: 250,
+		},
+		{
+			// Без номера чека ключ строится по клиенту и времени покупки, поэтому строки попадают в один чек
+			name: "natural key groups lines",
+			records: []map[string]string{
+				{"customer_id": "C1", "date": "2024-03-01T10:15:00Z", "product_id": "P1", "name": "Tea", "price": "10"},
+				{"customer_id": "C1", "date": "2024-03-01T10:15:00Z", "product_id": "P2", "name": "Cup", "price": "5"},
+			},
+			wantItems:  map[string]int{"P1": 1, "P2": 1},
+			wantTotal:  15,
+			wantSameID: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := &transactionLoader{}
+			for i, fields := range tt.records {
+				if rejections := loader.add(&row{mapping: mapping, record: Record{Line: i + 2, Fields: fields}}); len(rejections) > 0 {
+					t.Fatalf("add() rejections = %+v", rejections)
+				}
+			}
+			if rejections := loader.finish(); len(rejections) > 0 {
+				t.Fatalf("finish() rejections = %+v", rejections)
+			}
+
+			if loader.size() != 1 {
+				t.Fatalf("transactions = %d, want 1", loader.size())
+			}
+			transaction := loader.transactions[0]
+			if len(transaction.Items) != len(tt.wantItems) {
+				t.Errorf("items = %d, want %d", len(transaction.Items), len(tt.wantItems))
+			}
+			for _, item := range transaction.Items {
+				if item.Quantity != tt.wantItems[item.ProductID] {
+					t.Errorf("%s quantity = %d, want %d", item.ProductID, item.Quantity, tt.wantItems[item.ProductID])
+				}
+			}
+			if math.Abs(transaction.TotalAmount-tt.wantTotal) > 1e-9 {
+				t.Errorf("total = %.2f, want %.2f", transaction.TotalAmount, tt.wantTotal)
+			}
+			if tt.wantSameID && transaction.ID != naturalKey("txn", "C1", "2024-03-01T10:15:00Z") {
+				t.Errorf("transaction ID = %s, want natural key", transaction.ID)
+			}
+
+			loader.reset()
+			if loader.size() != 0 {
+				t.Errorf("size after reset = %d, want 0", loader.size())
+			}
+		})
+	}
+}
+
+func TestNaturalKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  []string
+		equal bool
+	}{
+		{name: "same parts", a: []string{"R1", "P1"}, b: []string{"R1", "P1"}, equal: true},
+		{name: "different order", a: []string{"R1", "P1"}, b: []string{"P1", "R1"}},
+		// Разделитель частей не дает склеенным значениям совпасть
+		{name: "shifted boundary", a: []string{"R1", "P1"}, b: []string{"R1P", "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := naturalKey("sale", tt.a...), naturalKey("sale", tt.b...)
+			if (a == b) != tt.equal {
+				t.Errorf("naturalKey(%v) = %s, naturalKey(%v) = %s, want equal %v", tt.a, a, tt.b, b, tt.equal)
+			}
+			if len(a) != len("sale-")+24 {
+				t.Errorf("naturalKey() = %s, want sale- prefix and 24 hex digits", a)
+			}
+		})
+	}
+}
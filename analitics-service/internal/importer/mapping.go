@@ -0,0 +1,182 @@
+// internal/importer/mapping.go
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entity определяет вид импортируемых записей
+type Entity string
+
+const (
+	EntityProducts     Entity = "products"
+	EntityProductCosts Entity = "product_costs"
+	EntityTransactions Entity = "transactions"
+	EntitySales        Entity = "sales"
+)
+
+// Format определяет формат файла импорта
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// defaultBatchSize количество сущностей, записываемых одним COPY
+const defaultBatchSize = 5000
+
+// entityFields поля сущностей, доступные для сопоставления, и признак обязательности
+// Позиции чека описываются полями product_id, name, category_id, category, price, quantity и discount_pct
+// в строках транзакции: одна строка источника — одна позиция
+var entityFields = map[Entity]map[string]bool{
+	EntityProducts: {
+		"id": true, "name": true, "category": false, "category_id": true, "sub_category": false,
+		"price": true, "cost": false, "description": false, "image_url": false, "is_active": false,
+	},
+	EntityProductCosts: {
+		"product_id": true, "effective_from": true, "cost": true, "price": false,
+	},
+	EntityTransactions: {
+		"id": false, "customer_id": true, "date": true, "total_amount": false, "discount_used": false, "coupon_code": false,
+		"product_id": true, "name": true, "category_id": false, "category": false,
+		"price": true, "quantity": false, "discount_pct": false,
+	},
+	EntitySales: {
+		"id": false, "product_id": true, "quantity": true, "price": true, "discount_rate": false,
+		"purchase_date": true, "customer_id": true, "transaction_id": true,
+	},
+}
+
+// Mapping описывает сопоставление колонок файла выгрузки кассовой системы полям сущности
+//
+//	entity: transactions
+//	format: csv
+//	delimiter: ";"
+//	time_format: "02.01.2006 15:04"
+//	timezone: Europe/Moscow
+//	decimal_comma: true
+//	columns:
+//	  id: receipt_no
+//	  customer_id: card_number
+//	  date: created_at
+//	  product_id: sku
+//	  name: item_name
+//	  price: unit_price
+//	  quantity: qty
+//	defaults:
+//	  category: unknown
+type Mapping struct {
+	Entity       Entity            `yaml:"entity"`
+	Format       Format            `yaml:"format"`        // По умолчанию определяется по расширению файла
+	Delimiter    string            `yaml:"delimiter"`     // Разделитель CSV, по умолчанию запятая
+	TimeFormat   string            `yaml:"time_format"`   // Формат дат в нотации Go; кроме него принимаются RFC 3339 и YYYY-MM-DD
+	Timezone     string            `yaml:"timezone"`      // Часовой пояс дат без смещения, по умолчанию UTC
+	DecimalComma bool              `yaml:"decimal_comma"` // Дробная часть чисел отделяется запятой
+	BatchSize    int               `yaml:"batch_size"`    // Количество сущностей в одной пачке COPY
+	Columns      map[string]string `yaml:"columns"`       // Поле сущности -> колонка CSV или ключ JSON
+	Defaults     map[string]string `yaml:"defaults"`      // Значения полей, отсутствующих в источнике или пустых
+
+	location *time.Location
+}
+
+// LoadMapping читает и проверяет сопоставление колонок из YAML-файла
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapping Mapping
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping: %w", err)
+	}
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// Validate проверяет сопоставление и заполняет значения по умолчанию
+func (m *Mapping) Validate() error {
+	fields, ok := entityFields[m.Entity]
+	if !ok {
+		return fmt.Errorf("unsupported entity %q, expected products, product_costs, transactions or sales", m.Entity)
+	}
+
+	if m.Format != "" && m.Format != FormatCSV && m.Format != FormatJSONL {
+		return fmt.Errorf("unsupported format %q, expected csv or jsonl", m.Format)
+	}
+
+	if m.Delimiter == "" {
+		m.Delimiter = ","
+	}
+	if len([]rune(m.Delimiter)) != 1 {
+		return fmt.Errorf("delimiter must be a single character, got %q", m.Delimiter)
+	}
+
+	if m.BatchSize == 0 {
+		m.BatchSize = defaultBatchSize
+	}
+	if m.BatchSize < 0 {
+		return fmt.Errorf("batch size must be positive, got %d", m.BatchSize)
+	}
+
+	m.location = time.UTC
+	if m.Timezone != "" {
+		location, err := time.LoadLocation(m.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+		m.location = location
+	}
+
+	if len(m.Columns) == 0 {
+		return errors.New("at least one column mapping is required")
+	}
+
+	var unknown, missing []string
+	for field := range m.Columns {
+		if _, ok := fields[field]; !ok {
+			unknown = append(unknown, field)
+		}
+	}
+	for field := range m.Defaults {
+		if _, ok := fields[field]; !ok {
+			unknown = append(unknown, field)
+		}
+	}
+	for field, required := range fields {
+		if required && m.Columns[field] == "" && m.Defaults[field] == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown %s fields: %s", m.Entity, strings.Join(unknown, ", "))
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("required %s fields are not mapped: %s", m.Entity, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// FormatFor возвращает формат файла: указанный в сопоставлении или определенный по расширению
+func (m *Mapping) FormatFor(path string) Format {
+	if m.Format != "" {
+		return m.Format
+	}
+	if strings.HasSuffix(path, ".jsonl") || strings.HasSuffix(path, ".ndjson") {
+		return FormatJSONL
+	}
+	return FormatCSV
+}
@@ -0,0 +1,61 @@
+// internal/importer/product_costs.go
+package importer
+
+import (
+	"context"
+	"database/sql"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// productCostColumns колонки public.product_costs, заполняемые импортом
+var productCostColumns = []string{"product_id", "effective_from", "cost", "price"}
+
+// productCostLoader накапливает записи истории себестоимости; естественный ключ — товар и дата начала действия
+type productCostLoader struct {
+	costs []entities.ProductCost
+}
+
+func (l *productCostLoader) add(r *row) []Rejection {
+	cost := entities.ProductCost{
+		ProductID:     r.str("product_id"),
+		EffectiveFrom: r.time("effective_from"),
+		Cost:          r.float("cost"),
+		Price:         r.float("price"),
+	}
+
+	err := r.err
+	if err == nil {
+		err = cost.Validate()
+	}
+	if err != nil {
+		return []Rejection{rejection(r.record, cost.ProductID, err)}
+	}
+
+	l.costs = append(l.costs, cost)
+	return nil
+}
+
+func (l *productCostLoader) finish() []Rejection {
+	return nil
+}
+
+func (l *productCostLoader) size() int {
+	return len(l.costs)
+}
+
+func (l *productCostLoader) flush(ctx context.Context, tx *sql.Tx) (int, error) {
+	rows := make([][]interface{}, 0, len(l.costs))
+	for _, c := range l.costs {
+		rows = append(rows, []interface{}{c.ProductID, c.EffectiveFrom, c.Cost, c.Price})
+	}
+
+	if err := stage(ctx, tx, "product_costs", productCostColumns, rows); err != nil {
+		return 0, err
+	}
+	return merge(ctx, tx, "product_costs", productCostColumns, "product_id, effective_from")
+}
+
+func (l *productCostLoader) reset() {
+	l.costs = l.costs[:0]
+}
@@ -0,0 +1,78 @@
+// internal/importer/products.go
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// productColumns колонки public.products, заполняемые импортом
+var productColumns = []string{
+	"id", "name", "category", "category_id", "sub_category", "price", "cost",
+	"description", "image_url", "is_active", "created_at", "updated_at",
+}
+
+// productLoader накапливает товары; естественный ключ товара — его ID
+type productLoader struct {
+	products []entities.Product
+}
+
+func (l *productLoader) add(r *row) []Rejection {
+	now := time.Now()
+	product := entities.Product{
+		BaseEntity:  entities.BaseEntity{ID: r.str("id"), CreatedAt: now, UpdatedAt: now},
+		Name:        r.str("name"),
+		Category:    r.str("category"),
+		CategoryID:  r.str("category_id"),
+		SubCategory: r.str("sub_category"),
+		Price:       r.float("price"),
+		Cost:        r.float("cost"),
+		Description: r.str("description"),
+		ImageURL:    r.str("image_url"),
+		IsActive:    r.str("is_active") == "" || r.bool("is_active"),
+	}
+
+	err := r.err
+	if err == nil && product.ID == "" {
+		err = errProductID
+	}
+	if err == nil {
+		err = product.Validate()
+	}
+	if err != nil {
+		return []Rejection{rejection(r.record, product.ID, err)}
+	}
+
+	l.products = append(l.products, product)
+	return nil
+}
+
+func (l *productLoader) finish() []Rejection {
+	return nil
+}
+
+func (l *productLoader) size() int {
+	return len(l.products)
+}
+
+func (l *productLoader) flush(ctx context.Context, tx *sql.Tx) (int, error) {
+	rows := make([][]interface{}, 0, len(l.products))
+	for _, p := range l.products {
+		rows = append(rows, []interface{}{
+			p.ID, p.Name, p.Category, p.CategoryID, p.SubCategory, p.Price, p.Cost,
+			p.Description, p.ImageURL, p.IsActive, p.CreatedAt, p.UpdatedAt,
+		})
+	}
+
+	if err := stage(ctx, tx, "products", productColumns, rows); err != nil {
+		return 0, err
+	}
+	return merge(ctx, tx, "products", productColumns, "id")
+}
+
+func (l *productLoader) reset() {
+	l.products = l.products[:0]
+}
@@ -0,0 +1,123 @@
+// internal/importer/reader.go
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxLineSize максимальный размер строки JSON Lines
+const maxLineSize = 16 << 20
+
+// Record представляет запись файла импорта
+// Err заполняется, если запись не удалось прочитать; такая запись попадает в отчет об отклоненных строках
+type Record struct {
+	Line   int
+	Fields map[string]string // Колонка источника -> значение
+	Err    error
+}
+
+// recordReader последовательно читает записи файла импорта; в конце файла возвращает io.EOF
+type recordReader interface {
+	Read() (Record, error)
+}
+
+// newRecordReader создает читателя записей в указанном формате
+func newRecordReader(format Format, r io.Reader, delimiter string) (recordReader, error) {
+	if format == FormatJSONL {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+		return &jsonlReader{scanner: scanner}, nil
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = []rune(delimiter)[0]
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make([]string, len(header))
+	for i, column := range header {
+		columns[i] = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+	}
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+// csvReader читает записи CSV с заголовком
+type csvReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+func (c *csvReader) Read() (Record, error) {
+	values, err := c.reader.Read()
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		// Строка с лишними или недостающими полями или незакрытой кавычкой отклоняется, чтение продолжается
+		return Record{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return Record{}, err
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	fields := make(map[string]string, len(c.columns))
+	for i, column := range c.columns {
+		fields[column] = strings.TrimSpace(values[i])
+	}
+	return Record{Line: line, Fields: fields}, nil
+}
+
+// jsonlReader читает записи JSON Lines; вложенные объекты и массивы не поддерживаются
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (j *jsonlReader) Read() (Record, error) {
+	for j.scanner.Scan() {
+		j.line++
+		data := bytes.TrimSpace(j.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			return Record{Line: j.line, Err: fmt.Errorf("invalid JSON: %w", err)}, nil
+		}
+
+		fields := make(map[string]string, len(object))
+		for key, value := range object {
+			switch v := value.(type) {
+			case nil:
+				fields[key] = ""
+			case string:
+				fields[key] = strings.TrimSpace(v)
+			case json.Number:
+				fields[key] = v.String()
+			case bool:
+				fields[key] = fmt.Sprint(v)
+			default:
+				return Record{Line: j.line, Err: fmt.Errorf("field %s: nested values are not supported", key)}, nil
+			}
+		}
+		return Record{Line: j.line, Fields: fields}, nil
+	}
+
+	if err := j.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
@@ -0,0 +1,49 @@
+// internal/importer/report.go
+package importer
+
+import (
+	"encoding/json"
+	"io"
+
+	"analitics-service/pkg/export"
+)
+
+// reportColumns колонки отчета об отклоненных записях; record содержит исходные поля записи в JSON
+var reportColumns = []export.Column{
+	{Name: "line", Type: export.Int},
+	{Name: "key", Type: export.String},
+	{Name: "reason", Type: export.String},
+	{Name: "record", Type: export.String},
+}
+
+// ReportWriter записывает отклоненные записи в CSV-отчет
+type ReportWriter struct {
+	writer export.Writer
+}
+
+// NewReportWriter создает CSV-отчет об отклоненных записях
+func NewReportWriter(w io.Writer) (*ReportWriter, error) {
+	writer, err := export.NewWriter(export.FormatCSV, w, reportColumns)
+	if err != nil {
+		return nil, err
+	}
+	return &ReportWriter{writer: writer}, nil
+}
+
+// Write добавляет отклоненную запись в отчет
+func (r *ReportWriter) Write(rejection Rejection) error {
+	record := ""
+	if len(rejection.Fields) > 0 {
+		data, err := json.Marshal(rejection.Fields)
+		if err != nil {
+			return err
+		}
+		record = string(data)
+	}
+	return r.writer.WriteRow([]interface{}{rejection.Line, rejection.Key, rejection.Reason, record})
+}
+
+// Close дописывает буферизованные строки отчета
+func (r *ReportWriter) Close() error {
+	return r.writer.Close()
+}
@@ -0,0 +1,101 @@
+// internal/importer/row.go
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// row читает поля сущности из записи по сопоставлению колонок
+// Первая ошибка разбора сохраняется в err, последующие обращения возвращают нулевые значения
+type row struct {
+	mapping *Mapping
+	record  Record
+	err     error
+}
+
+// str возвращает значение поля или значение по умолчанию, если колонка не сопоставлена или пуста
+func (r *row) str(field string) string {
+	if column, ok := r.mapping.Columns[field]; ok {
+		if value := r.record.Fields[column]; value != "" {
+			return value
+		}
+	}
+	return r.mapping.Defaults[field]
+}
+
+// float возвращает числовое поле; пустое значение — 0
+func (r *row) float(field string) float64 {
+	raw := r.str(field)
+	if raw == "" || r.err != nil {
+		return 0
+	}
+
+	raw = strings.ReplaceAll(raw, " ", "")
+	if r.mapping.DecimalComma {
+		raw = strings.ReplaceAll(strings.ReplaceAll(raw, ".", ""), ",", ".")
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		r.err = fmt.Errorf("%s: invalid number %q", field, r.str(field))
+	}
+	return value
+}
+
+// int возвращает целочисленное поле; пустое значение — 0
+func (r *row) int(field string) int {
+	raw := r.str(field)
+	if raw == "" || r.err != nil {
+		return 0
+	}
+
+	value, err := strconv.Atoi(strings.ReplaceAll(raw, " ", ""))
+	if err != nil {
+		// Некоторые кассовые системы выгружают количество как 2.000
+		number := r.float(field)
+		if r.err == nil && number == float64(int(number)) {
+			return int(number)
+		}
+		r.err = fmt.Errorf("%s: invalid integer %q", field, raw)
+	}
+	return value
+}
+
+// bool возвращает логическое поле; пустое значение — false
+func (r *row) bool(field string) bool {
+	raw := strings.ToLower(r.str(field))
+	if raw == "" || r.err != nil {
+		return false
+	}
+
+	switch raw {
+	case "1", "t", "true", "y", "yes":
+		return true
+	case "0", "f", "false", "n", "no":
+		return false
+	}
+	r.err = fmt.Errorf("%s: invalid boolean %q", field, raw)
+	return false
+}
+
+// time возвращает поле даты; даты без смещения интерпретируются в часовом поясе сопоставления
+func (r *row) time(field string) time.Time {
+	raw := r.str(field)
+	if raw == "" || r.err != nil {
+		return time.Time{}
+	}
+
+	layouts := []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+	if r.mapping.TimeFormat != "" {
+		layouts = append([]string{r.mapping.TimeFormat}, layouts...)
+	}
+	for _, layout := range layouts {
+		if value, err := time.ParseInLocation(layout, raw, r.mapping.location); err == nil {
+			return value
+		}
+	}
+	r.err = fmt.Errorf("%s: invalid date %q", field, raw)
+	return time.Time{}
+}
@@ -0,0 +1,77 @@
+// internal/importer/sales.go
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// saleColumns колонки public.sales, заполняемые импортом
+var saleColumns = []string{
+	"id", "product_id", "quantity", "price", "discount_rate", "purchase_date",
+	"customer_id", "transaction_id", "created_at", "updated_at",
+}
+
+// saleLoader накапливает продажи; естественный ключ продажи — ее ID,
+// а если его нет в выгрузке — пара чека и товара
+type saleLoader struct {
+	sales []entities.Sale
+}
+
+func (l *saleLoader) add(r *row) []Rejection {
+	now := time.Now()
+	sale := entities.Sale{
+		BaseEntity:    entities.BaseEntity{ID: r.str("id"), CreatedAt: now, UpdatedAt: now},
+		ProductID:     r.str("product_id"),
+		Quantity:      r.int("quantity"),
+		Price:         r.float("price"),
+		DiscountRate:  r.float("discount_rate"),
+		PurchaseDate:  r.time("purchase_date"),
+		CustomerID:    r.str("customer_id"),
+		TransactionID: r.str("transaction_id"),
+	}
+	if sale.ID == "" && sale.TransactionID != "" && sale.ProductID != "" {
+		sale.ID = naturalKey("sale", sale.TransactionID, sale.ProductID)
+	}
+
+	err := r.err
+	if err == nil {
+		err = sale.Validate()
+	}
+	if err != nil {
+		return []Rejection{rejection(r.record, sale.ID, err)}
+	}
+
+	l.sales = append(l.sales, sale)
+	return nil
+}
+
+func (l *saleLoader) finish() []Rejection {
+	return nil
+}
+
+func (l *saleLoader) size() int {
+	return len(l.sales)
+}
+
+func (l *saleLoader) flush(ctx context.Context, tx *sql.Tx) (int, error) {
+	rows := make([][]interface{}, 0, len(l.sales))
+	for _, s := range l.sales {
+		rows = append(rows, []interface{}{
+			s.ID, s.ProductID, s.Quantity, s.Price, s.DiscountRate, s.PurchaseDate,
+			s.CustomerID, s.TransactionID, s.CreatedAt, s.UpdatedAt,
+		})
+	}
+
+	if err := stage(ctx, tx, "sales", saleColumns, rows); err != nil {
+		return 0, err
+	}
+	return merge(ctx, tx, "sales", saleColumns, "id")
+}
+
+func (l *saleLoader) reset() {
+	l.sales = l.sales[:0]
+}
@@ -0,0 +1,187 @@
+// internal/importer/transactions.go
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// transactionColumns колонки public.transactions, заполняемые импортом
+var transactionColumns = []string{
+	"id", "customer_id", "date", "total_amount", "discount_used", "coupon_code", "created_at", "updated_at",
+}
+
+// transactionItemColumns колонки public.transaction_items, заполняемые импортом
+var transactionItemColumns = []string{
+	"transaction_id", "product_id", "name", "category_id", "category", "price", "quantity", "discount_pct",
+}
+
+// transactionGroup содержит строки одного чека
+type transactionGroup struct {
+	transaction entities.Transaction
+	records     []Record
+	hasTotal    bool
+	err         error
+}
+
+// transactionLoader собирает чеки из строк позиций; естественный ключ чека — его ID,
+// а если его нет в выгрузке — клиент и время покупки
+// Строки одного чека должны идти в файле подряд, как в выгрузках кассовых систем
+type transactionLoader struct {
+	current      *transactionGroup
+	transactions []entities.Transaction
+}
+
+func (l *transactionLoader) add(r *row) []Rejection {
+	id := r.str("id")
+	customerID := r.str("customer_id")
+	date := r.time("date")
+	if id == "" && r.err == nil && customerID != "" && !date.IsZero() {
+		id = naturalKey("txn", customerID, date.UTC().Format(time.RFC3339Nano))
+	}
+	if id == "" {
+		// Без ключа строку нельзя отнести к чеку, поэтому она отклоняется отдельно
+		err := r.err
+		if err == nil {
+			err = fmt.Errorf("transaction ID or customer ID and date are required")
+		}
+		return []Rejection{rejection(r.record, "", err)}
+	}
+
+	var rejections []Rejection
+	if l.current == nil || l.current.transaction.ID != id {
+		rejections = l.finish()
+		now := time.Now()
+		l.current = &transactionGroup{
+			transaction: entities.Transaction{
+				BaseEntity:   entities.BaseEntity{ID: id, CreatedAt: now, UpdatedAt: now},
+				CustomerID:   customerID,
+				Date:         date,
+				DiscountUsed: r.bool("discount_used"),
+				CouponCode:   r.str("coupon_code"),
+			},
+		}
+		if r.str("total_amount") != "" {
+			l.current.transaction.TotalAmount = r.float("total_amount")
+			l.current.hasTotal = true
+		}
+	}
+
+	group := l.current
+	group.records = append(group.records, r.record)
+	item := entities.Item{
+		ProductID:   r.str("product_id"),
+		Name:        r.str("name"),
+		CategoryID:  r.str("category_id"),
+		Category:    r.str("category"),
+		Price:       r.float("price"),
+		Quantity:    1,
+		DiscountPct: r.float("discount_pct"),
+	}
+	if r.str("quantity") != "" {
+		item.Quantity = r.int("quantity")
+	}
+
+	if group.err == nil && r.err != nil {
+		group.err = fmt.Errorf("line %d: %w", r.record.Line, r.err)
+	}
+	if group.err == nil {
+		if err := item.Validate(); err != nil {
+			group.err = fmt.Errorf("line %d: %w", r.record.Line, err)
+		}
+	}
+	if group.err == nil {
+		group.addItem(item)
+	}
+	return rejections
+}
+
+// addItem добавляет позицию в чек; повторные строки того же товара суммируются
+func (g *transactionGroup) addItem(item entities.Item) {
+	for i := range g.transaction.Items {
+		if g.transaction.Items[i].ProductID == item.ProductID {
+			g.transaction.Items[i].Quantity += item.Quantity
+			return
+		}
+	}
+	g.transaction.Items = append(g.transaction.Items, item)
+}
+
+// finish проверяет собранный чек и добавляет его в пачку; при ошибке отклоняются все строки чека
+func (l *transactionLoader) finish() []Rejection {
+	group := l.current
+	l.current = nil
+	if group == nil {
+		return nil
+	}
+
+	transaction := group.transaction
+	if group.err == nil && !group.hasTotal {
+		for _, item := range transaction.Items {
+			transaction.TotalAmount += item.Price * float64(item.Quantity) * (1 - item.DiscountPct/100)
+		}
+		transaction.TotalAmount = math.Round(transaction.TotalAmount*100) / 100
+	}
+	if group.err == nil {
+		group.err = transaction.Validate()
+	}
+
+	if group.err != nil {
+		rejections := make([]Rejection, 0, len(group.records))
+		for _, record := range group.records {
+			rejections = append(rejections, rejection(record, transaction.ID, group.err))
+		}
+		return rejections
+	}
+
+	l.transactions = append(l.transactions, transaction)
+	return nil
+}
+
+func (l *transactionLoader) size() int {
+	return len(l.transactions)
+}
+
+// flush записывает чеки и их позиции; позиции чеков, загруженных ранее, не добавляются
+func (l *transactionLoader) flush(ctx context.Context, tx *sql.Tx) (int, error) {
+	transactions := make([][]interface{}, 0, len(l.transactions))
+	var items [][]interface{}
+	for _, t := range l.transactions {
+		transactions = append(transactions, []interface{}{
+			t.ID, t.CustomerID, t.Date, t.TotalAmount, t.DiscountUsed, t.CouponCode, t.CreatedAt, t.UpdatedAt,
+		})
+		for _, item := range t.Items {
+			items = append(items, []interface{}{
+				t.ID, item.ProductID, item.Name, item.CategoryID, item.Category, item.Price, item.Quantity, item.DiscountPct,
+			})
+		}
+	}
+
+	if err := stage(ctx, tx, "transactions", transactionColumns, transactions); err != nil {
+		return 0, err
+	}
+	if err := stage(ctx, tx, "transaction_items", transactionItemColumns, items); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM import_transaction_items i USING public.transactions t WHERE i.transaction_id = t.id`); err != nil {
+		return 0, fmt.Errorf("failed to skip items of imported transactions: %w", err)
+	}
+	inserted, err := merge(ctx, tx, "transactions", transactionColumns, "id")
+	if err != nil {
+		return 0, err
+	}
+	if _, err := merge(ctx, tx, "transaction_items", transactionItemColumns, "transaction_id, product_id"); err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+func (l *transactionLoader) reset() {
+	l.transactions = l.transactions[:0]
+}
@@ -0,0 +1,165 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+)
+
+// relayLockName имя блокировки ретранслятора в таблице блокировок задач
+const relayLockName = "outbox_relay"
+
+// Publisher отправляет сообщение во внешний брокер; реализуется продюсером Kafka
+type Publisher interface {
+	SendMessage(ctx context.Context, key string, value interface{}) error
+}
+
+// RelayOptions содержит настройки ретранслятора исходящих событий
+type RelayOptions struct {
+	InstanceID string        // Владелец блокировки ретранслятора
+	Interval   time.Duration // Период опроса таблицы исходящих событий
+	BatchSize  int           // Количество событий, читаемых за один запрос
+	LockTTL    time.Duration // Время аренды блокировки; должно превышать время публикации одной пачки
+	Retention  time.Duration // Срок хранения опубликованных событий; 0 — не удалять
+}
+
+// Relay публикует события из таблицы исходящих сообщений в порядке их добавления
+// Ретранслятор работает под распределенной блокировкой, поэтому события публикует только одна реплика
+// и порядок сохраняется. Доставка не реже одного раза: при сбое между отправкой и отметкой
+// событие будет отправлено повторно, потребители отбрасывают повторы по ID события
+type Relay struct {
+	outboxRepo repositories.OutboxRepository
+	lockRepo   repositories.JobLockRepository
+	publisher  Publisher
+	options    RelayOptions
+	logger     logger.Logger
+}
+
+// NewRelay создает ретранслятор исходящих событий
+func NewRelay(
+	outboxRepo repositories.OutboxRepository,
+	lockRepo repositories.JobLockRepository,
+	publisher Publisher,
+	options RelayOptions,
+	logger logger.Logger,
+) (*Relay, error) {
+	if options.InstanceID == "" {
+		return nil, errors.New("instance id is required")
+	}
+	if options.Interval <= 0 {
+		return nil, errors.New("relay interval must be positive")
+	}
+	if options.BatchSize <= 0 {
+		return nil, errors.New("relay batch size must be positive")
+	}
+	if options.LockTTL <= 0 {
+		return nil, errors.New("lock ttl must be positive")
+	}
+
+	return &Relay{
+		outboxRepo: outboxRepo,
+		lockRepo:   lockRepo,
+		publisher:  publisher,
+		options:    options,
+		logger:     logger,
+	}, nil
+}
+
+// Start публикует накопившиеся события каждые Interval до отмены контекста
+func (r *Relay) Start(ctx context.Context) {
+	r.logger.Info(ctx, "Ретранслятор событий запущен", "instance", r.options.InstanceID, "interval", r.options.Interval)
+
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.PublishPending(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error(ctx, "Ошибка публикации событий", "error", err)
+		}
+		if err := r.cleanup(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error(ctx, "Ошибка удаления опубликованных событий", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info(ctx, "Ретранслятор событий остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending публикует все неопубликованные события и возвращает их количество
+// Если блокировку удерживает другая реплика, ничего не делает
+// Публикация останавливается на первом событии, которое не удалось отправить, чтобы не нарушить порядок
+func (r *Relay) PublishPending(ctx context.Context) (int, error) {
+	acquired, err := r.lockRepo.TryAcquireLock(ctx, relayLockName, r.options.InstanceID, r.options.LockTTL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire relay lock: %w", err)
+	}
+	if !acquired {
+		return 0, nil
+	}
+	defer func() {
+		// Блокировка освобождается и после отмены ctx, иначе следующая реплика ждала бы истечения аренды
+		if err := r.lockRepo.ReleaseLock(context.WithoutCancel(ctx), relayLockName, r.options.InstanceID); err != nil {
+			r.logger.Error(ctx, "Не удалось освободить блокировку ретранслятора", "error", err)
+		}
+	}()
+
+	published := 0
+	for {
+		events, err := r.outboxRepo.GetPending(ctx, r.options.BatchSize)
+		if err != nil {
+			return published, fmt.Errorf("failed to get pending events: %w", err)
+		}
+
+		sent := make([]int64, 0, len(events))
+		var sendErr error
+		for _, event := range events {
+			if sendErr = r.publisher.SendMessage(ctx, event.Key, event.Payload); sendErr != nil {
+				if err := r.outboxRepo.MarkFailed(ctx, event.Sequence, sendErr.Error()); err != nil {
+					r.logger.Error(ctx, "Не удалось сохранить ошибку публикации события", "eventID", event.EventID, "error", err)
+				}
+				sendErr = fmt.Errorf("failed to publish event %s: %w", event.EventID, sendErr)
+				break
+			}
+			sent = append(sent, event.Sequence)
+		}
+
+		if len(sent) > 0 {
+			if err := r.outboxRepo.MarkPublished(ctx, sent); err != nil {
+				return published, fmt.Errorf("failed to mark events published: %w", err)
+			}
+			published += len(sent)
+			r.logger.Info(ctx, "События опубликованы", "count", len(sent))
+		}
+
+		if sendErr != nil {
+			return published, sendErr
+		}
+		if len(events) < r.options.BatchSize {
+			return published, nil
+		}
+	}
+}
+
+// cleanup удаляет опубликованные события старше срока хранения
+func (r *Relay) cleanup(ctx context.Context) error {
+	if r.options.Retention <= 0 {
+		return nil
+	}
+
+	deleted, err := r.outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(-r.options.Retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		r.logger.Info(ctx, "Удалены опубликованные события", "count", deleted)
+	}
+	return nil
+}
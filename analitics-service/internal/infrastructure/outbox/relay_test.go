@@ -0,0 +1,188 @@
+// internal/infrastructure/outbox/relay_test.go
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/outbox"
+	"analitics-service/pkg/logger"
+)
+
+// memoryOutboxRepository хранит исходящие события в памяти в порядке добавления
+type memoryOutboxRepository struct {
+	events []entities.OutboxEvent
+}
+
+func (r *memoryOutboxRepository) Enqueue(_ context.Context, events []entities.OutboxEvent) error {
+	for _, event := range events {
+		event.Sequence = int64(len(r.events) + 1)
+		r.events = append(r.events, event)
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepository) GetPending(_ context.Context, limit int) ([]entities.OutboxEvent, error) {
+	var result []entities.OutboxEvent
+	for _, event := range r.events {
+		if event.PublishedAt == nil && len(result) < limit {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryOutboxRepository) MarkPublished(_ context.Context, sequences []int64) error {
+	now := time.Now()
+	for _, sequence := range sequences {
+		r.events[sequence-1].PublishedAt = &now
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepository) MarkFailed(_ context.Context, sequence int64, publishErr string) error {
+	r.events[sequence-1].Attempts++
+	r.events[sequence-1].LastError = publishErr
+	return nil
+}
+
+func (r *memoryOutboxRepository) DeletePublishedBefore(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+// memoryLockRepository хранит владельцев блокировок в памяти
+type memoryLockRepository struct {
+	owners map[string]string
+}
+
+func (r *memoryLockRepository) TryAcquireLock(_ context.Context, jobName, owner string, _ time.Duration) (bool, error) {
+	if current, ok := r.owners[jobName]; ok && current != owner {
+		return false, nil
+	}
+	r.owners[jobName] = owner
+	return true, nil
+}
+
+func (r *memoryLockRepository) ReleaseLock(_ context.Context, jobName, owner string) error {
+	if r.owners[jobName] == owner {
+		delete(r.owners, jobName)
+	}
+	return nil
+}
+
+func (r *memoryLockRepository) TryClaimSlot(_ context.Context, _ string, _ time.Time, _ string) (bool, error) {
+	return true, nil
+}
+
+func (r *memoryLockRepository) DeleteSlotsBefore(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+// recordingPublisher запоминает ключи отправленных сообщений и отказывает на сообщении номер failAt
+type recordingPublisher struct {
+	keys   []string
+	failAt int
+}
+
+func (p *recordingPublisher) SendMessage(_ context.Context, key string, _ interface{}) error {
+	if len(p.keys)+1 == p.failAt {
+		return errors.New("broker unavailable")
+	}
+	p.keys = append(p.keys, key)
+	return nil
+}
+
+// testRelayOptions возвращает настройки ретранслятора с пачками по два события
+func testRelayOptions() outbox.RelayOptions {
+	return outbox.RelayOptions{InstanceID: "replica-1", Interval: time.Second, BatchSize: 2, LockTTL: time.Minute}
+}
+
+func TestPublishPending(t *testing.T) {
+	tests := []struct {
+		name          string
+		lockOwner     string
+		failAt        int
+		wantPublished int
+		wantErr       bool
+	}{
+		// Пять событий публикуются тремя пачками в порядке добавления
+		{name: "all batches", wantPublished: 5},
+		{name: "lock held by another replica", lockOwner: "replica-2"},
+		// Публикация останавливается на первой ошибке, чтобы не нарушить порядок
+		{name: "broker failure", failAt: 3, wantPublished: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryOutboxRepository{}
+			for i := 1; i <= 5; i++ {
+				_ = repo.Enqueue(context.Background(), []entities.OutboxEvent{{EventID: fmt.Sprintf("E%d", i), Key: fmt.Sprintf("K%d", i)}})
+			}
+			locks := &memoryLockRepository{owners: map[string]string{}}
+			if tt.lockOwner != "" {
+				locks.owners["outbox_relay"] = tt.lockOwner
+			}
+			publisher := &recordingPublisher{failAt: tt.failAt}
+
+			relay, err := outbox.NewRelay(repo, locks, publisher, testRelayOptions(), logger.NewLogger("ERROR"))
+			if err != nil {
+				t.Fatalf("NewRelay() error = %v", err)
+			}
+
+			published, err := relay.PublishPending(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PublishPending() error = %v, want error %v", err, tt.wantErr)
+			}
+			if published != tt.wantPublished || len(publisher.keys) != tt.wantPublished {
+				t.Errorf("published = %d, sent = %v, want %d", published, publisher.keys, tt.wantPublished)
+			}
+
+			for i, event := range repo.events {
+				wantPublished := i < tt.wantPublished
+				if (event.PublishedAt != nil) != wantPublished {
+					t.Errorf("event %s published = %v, want %v", event.EventID, event.PublishedAt != nil, wantPublished)
+				}
+				if i < len(publisher.keys) && publisher.keys[i] != event.Key {
+					t.Errorf("message %d key = %s, want %s", i, publisher.keys[i], event.Key)
+				}
+			}
+			if tt.failAt > 0 {
+				failed := repo.events[tt.failAt-1]
+				if failed.Attempts != 1 || failed.LastError == "" {
+					t.Errorf("failed event attempts = %d, error %q, want 1 attempt with error", failed.Attempts, failed.LastError)
+				}
+			}
+
+			// Своя блокировка освобождается после публикации, чужая остается
+			if owner := locks.owners["outbox_relay"]; owner != tt.lockOwner {
+				t.Errorf("lock owner = %q, want %q", owner, tt.lockOwner)
+			}
+		})
+	}
+}
+
+func TestNewRelayValidatesOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(options *outbox.RelayOptions)
+	}{
+		{name: "no instance", modify: func(options *outbox.RelayOptions) { options.InstanceID = "" }},
+		{name: "zero interval", modify: func(options *outbox.RelayOptions) { options.Interval = 0 }},
+		{name: "zero batch size", modify: func(options *outbox.RelayOptions) { options.BatchSize = 0 }},
+		{name: "zero lock ttl", modify: func(options *outbox.RelayOptions) { options.LockTTL = 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := testRelayOptions()
+			tt.modify(&options)
+			if _, err := outbox.NewRelay(&memoryOutboxRepository{}, &memoryLockRepository{}, &recordingPublisher{}, options, logger.NewLogger("ERROR")); err == nil {
+				t.Errorf("NewRelay() error = nil, want invalid options error")
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// analitics-service/internal/infrastructure/postgres/ab_test_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// ABTestRepository хранит результаты A/B тестов в таблице public.ab_test_results (см. AnalyticsSchema)
+// Повторное сохранение теста заменяет его результат
+type ABTestRepository struct {
+	db *sql.DB
+}
+
+func NewABTestRepository(db *sql.DB) repositories.ABTestRepository {
+	return &ABTestRepository{db: db}
+}
+
+// GetTestResults возвращает тесты, период которых пересекается с периодом startDate-endDate
+func (r *ABTestRepository) GetTestResults(ctx context.Context, startDate, endDate time.Time) ([]entities.ABTestResult, error) {
+	query := `SELECT payload
+              FROM public.ab_test_results
+              WHERE start_date < $2 AND end_date > $1
+              ORDER BY start_date, test_id`
+	return queryPayloads[entities.ABTestResult](ctx, executor(ctx, r.db), query, startDate, endDate)
+}
+
+// GetTestResultByID возвращает пустой результат, если тест не найден
+func (r *ABTestRepository) GetTestResultByID(ctx context.Context, testID string) (entities.ABTestResult, error) {
+	query := `SELECT payload FROM public.ab_test_results WHERE test_id = $1`
+	return queryPayload[entities.ABTestResult](ctx, executor(ctx, r.db), query, testID)
+}
+
+func (r *ABTestRepository) SaveTestResult(ctx context.Context, result entities.ABTestResult) error {
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.ab_test_results (test_id, start_date, end_date, product_ids, payload)
+              VALUES ($1, $2, $3, $4, $5)
+              ON CONFLICT (test_id) DO UPDATE
+              SET start_date = EXCLUDED.start_date, end_date = EXCLUDED.end_date, product_ids = EXCLUDED.product_ids,
+                  payload = EXCLUDED.payload, updated_at = now()`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, result.TestID, result.StartDate, result.EndDate,
+		pq.Array(nonNilStrings(result.ProductIDs)), payload)
+	return err
+}
+
+func (r *ABTestRepository) GetTestsByProduct(ctx context.Context, productID string) ([]entities.ABTestResult, error) {
+	query := `SELECT payload
+              FROM public.ab_test_results
+              WHERE $1 = ANY(product_ids)
+              ORDER BY start_date, test_id`
+	return queryPayloads[entities.ABTestResult](ctx, executor(ctx, r.db), query, productID)
+}
+
+// GetTestsByCategory возвращает тесты, в которых участвует хотя бы один товар категории
+func (r *ABTestRepository) GetTestsByCategory(ctx context.Context, category string) ([]entities.ABTestResult, error) {
+	query := `SELECT t.payload
+              FROM public.ab_test_results t
+              WHERE EXISTS (SELECT 1 FROM public.products p WHERE p.id = ANY(t.product_ids) AND p.category = $1)
+              ORDER BY t.start_date, t.test_id`
+	return queryPayloads[entities.ABTestResult](ctx, executor(ctx, r.db), query, category)
+}
@@ -0,0 +1,82 @@
+// analitics-service/internal/infrastructure/postgres/abc_analysis_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// ABCAnalysisRepository хранит результаты ABC-анализа в таблице public.abc_analysis_results
+// и использованные критерии в таблице public.abc_analysis_criteria (см. AnalyticsSchema)
+type ABCAnalysisRepository struct {
+	db *sql.DB
+}
+
+func NewABCAnalysisRepository(db *sql.DB) repositories.ABCAnalysisRepository {
+	return &ABCAnalysisRepository{db: db}
+}
+
+func (r *ABCAnalysisRepository) SaveAnalysisResult(ctx context.Context, result entities.ABCAnalysisResult) error {
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.abc_analysis_results (analysis_date, run_id, payload) VALUES ($1, $2, $3)`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, result.AnalysisDate, result.RunID, payload)
+	return err
+}
+
+// GetAnalysisResultByDate возвращает последний результат за календарный день date
+// или пустой результат, если в этот день анализ не проводился
+func (r *ABCAnalysisRepository) GetAnalysisResultByDate(ctx context.Context, date time.Time) (entities.ABCAnalysisResult, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	query := `SELECT payload
+              FROM public.abc_analysis_results
+              WHERE analysis_date >= $1 AND analysis_date < $2
+              ORDER BY analysis_date DESC
+              LIMIT 1`
+	return queryPayload[entities.ABCAnalysisResult](ctx, executor(ctx, r.db), query, day, day.AddDate(0, 0, 1))
+}
+
+// GetLatestAnalysisResult возвращает пустой результат, если анализ еще не проводился
+func (r *ABCAnalysisRepository) GetLatestAnalysisResult(ctx context.Context) (entities.ABCAnalysisResult, error) {
+	query := `SELECT payload
+              FROM public.abc_analysis_results
+              ORDER BY analysis_date DESC
+              LIMIT 1`
+	return queryPayload[entities.ABCAnalysisResult](ctx, executor(ctx, r.db), query)
+}
+
+func (r *ABCAnalysisRepository) GetAnalysisHistory(ctx context.Context, startDate, endDate time.Time) ([]entities.ABCAnalysisResult, error) {
+	query := `SELECT payload
+              FROM public.abc_analysis_results
+              WHERE analysis_date >= $1 AND analysis_date < $2
+              ORDER BY analysis_date`
+	return queryPayloads[entities.ABCAnalysisResult](ctx, executor(ctx, r.db), query, startDate, endDate)
+}
+
+func (r *ABCAnalysisRepository) SaveAnalysisCriteria(ctx context.Context, criteria entities.ABCAnalysisCriteria) error {
+	payload, err := json.Marshal(criteria)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.abc_analysis_criteria (saved_at, payload) VALUES (now(), $1)`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, payload)
+	return err
+}
+
+// GetLatestAnalysisCriteria возвращает пустые критерии, если они еще не сохранялись
+func (r *ABCAnalysisRepository) GetLatestAnalysisCriteria(ctx context.Context) (entities.ABCAnalysisCriteria, error) {
+	query := `SELECT payload
+              FROM public.abc_analysis_criteria
+              ORDER BY saved_at DESC
+              LIMIT 1`
+	return queryPayload[entities.ABCAnalysisCriteria](ctx, executor(ctx, r.db), query)
+}
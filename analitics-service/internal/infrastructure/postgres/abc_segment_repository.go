@@ -0,0 +1,115 @@
+// analitics-service/internal/infrastructure/postgres/abc_segment_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// ABCSegmentRepository хранит сегментацию товаров в таблице public.abc_segments (см. AnalyticsSchema)
+// Каждое сохранение добавляет новый срез; чтение возвращает срез последнего анализа
+type ABCSegmentRepository struct {
+	db *sql.DB
+}
+
+func NewABCSegmentRepository(db *sql.DB) repositories.ABCSegmentRepository {
+	return &ABCSegmentRepository{db: db}
+}
+
+// latestSegmentsCondition отбирает строки последнего анализа
+const latestSegmentsCondition = `analysis_date = (SELECT MAX(analysis_date) FROM public.abc_segments)`
+
+func (r *ABCSegmentRepository) SaveSegmentation(ctx context.Context, segmentation map[string]entities.ProductFullSegmentation) error {
+	// Все строки среза получают одну дату, по ней срез отличается от предыдущих
+	analysisDate := time.Now()
+	query := `INSERT INTO public.abc_segments (product_id, analysis_date, revenue_segment, quantity_segment, profit_segment,
+                  final_segment, score)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	return NewTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		for productID, segment := range segmentation {
+			if _, err := executor(ctx, r.db).ExecContext(ctx, query, productID, analysisDate, segment.RevenueSegment,
+				segment.QuantitySegment, segment.ProfitSegment, segment.FinalSegment, segment.Score); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetProductSegmentation возвращает nil, если товар не входил в последний анализ
+func (r *ABCSegmentRepository) GetProductSegmentation(ctx context.Context, productID string) (*entities.ProductSegmentation, error) {
+	query := `SELECT product_id, final_segment, score, analysis_date
+              FROM public.abc_segments
+              WHERE product_id = $1 AND ` + latestSegmentsCondition
+	var segmentation entities.ProductSegmentation
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, productID).Scan(&segmentation.ProductID, &segmentation.Segment,
+		&segmentation.Score, &segmentation.AnalysisDate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &segmentation, nil
+}
+
+func (r *ABCSegmentRepository) GetFullSegmentation(ctx context.Context) (map[string]entities.ProductFullSegmentation, error) {
+	query := `SELECT product_id, revenue_segment, quantity_segment, profit_segment, final_segment, score
+              FROM public.abc_segments
+              WHERE ` + latestSegmentsCondition
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	segmentation := make(map[string]entities.ProductFullSegmentation)
+	for rows.Next() {
+		var segment entities.ProductFullSegmentation
+		if err := rows.Scan(&segment.ProductID, &segment.RevenueSegment, &segment.QuantitySegment, &segment.ProfitSegment,
+			&segment.FinalSegment, &segment.Score); err != nil {
+			return nil, err
+		}
+		segmentation[segment.ProductID] = segment
+	}
+	return segmentation, rows.Err()
+}
+
+func (r *ABCSegmentRepository) GetSegmentationByCategory(ctx context.Context, category string) ([]entities.ProductSegmentation, error) {
+	query := `SELECT s.product_id, s.final_segment, s.score, s.analysis_date
+              FROM public.abc_segments s
+              JOIN public.products p ON p.id = s.product_id
+              WHERE p.category = $1 AND s.` + latestSegmentsCondition + `
+              ORDER BY s.score DESC, s.product_id`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, category)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var segments []entities.ProductSegmentation
+	for rows.Next() {
+		var segment entities.ProductSegmentation
+		if err := rows.Scan(&segment.ProductID, &segment.Segment, &segment.Score, &segment.AnalysisDate); err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+	return segments, rows.Err()
+}
+
+// GetLatestAnalysisDate возвращает нулевое время, если анализ еще не проводился
+func (r *ABCSegmentRepository) GetLatestAnalysisDate(ctx context.Context) (time.Time, error) {
+	query := `SELECT MAX(analysis_date) FROM public.abc_segments`
+	var latest sql.NullTime
+	if err := executor(ctx, r.db).QueryRowContext(ctx, query).Scan(&latest); err != nil {
+		return time.Time{}, err
+	}
+	return latest.Time, nil
+}
@@ -0,0 +1,107 @@
+// analitics-service/internal/infrastructure/postgres/analysis_run_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// AnalysisRunRepository хранит записи о запусках анализа в таблице public.analysis_runs (см. AnalyticsSchema)
+type AnalysisRunRepository struct {
+	db *sql.DB
+}
+
+func NewAnalysisRunRepository(db *sql.DB) repositories.AnalysisRunRepository {
+	return &AnalysisRunRepository{db: db}
+}
+
+func (r *AnalysisRunRepository) SaveRun(ctx context.Context, run entities.AnalysisRun) error {
+	rowCounts, err := json.Marshal(run.InputRowCounts)
+	if err != nil {
+		return err
+	}
+	var dataQuality []byte
+	if run.DataQuality != nil {
+		if dataQuality, err = json.Marshal(run.DataQuality); err != nil {
+			return err
+		}
+	}
+
+	query := `INSERT INTO public.analysis_runs (id, type, parameters, code_version, input_row_counts, input_hash,
+                  output_count, started_at, finished_at, rerun_of, input_matches_original, data_quality)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $12)`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, run.ID, run.Type, []byte(run.Parameters), run.CodeVersion, rowCounts, run.InputHash,
+		run.OutputCount, run.StartedAt, run.FinishedAt, run.RerunOf, run.InputMatchesOriginal, dataQuality)
+	return err
+}
+
+func (r *AnalysisRunRepository) GetRunByID(ctx context.Context, runID string) (*entities.AnalysisRun, error) {
+	query := `SELECT id, type, parameters, code_version, input_row_counts, input_hash,
+                  output_count, started_at, finished_at, COALESCE(rerun_of, ''), input_matches_original,
+                  data_quality
+              FROM public.analysis_runs
+              WHERE id = $1`
+	run, err := scanAnalysisRun(executor(ctx, r.db).QueryRowContext(ctx, query, runID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return run, nil
+}
+
+func (r *AnalysisRunRepository) GetRuns(ctx context.Context, analysisType entities.AnalysisType, limit int) ([]entities.AnalysisRun, error) {
+	query := `SELECT id, type, parameters, code_version, input_row_counts, input_hash,
+                  output_count, started_at, finished_at, COALESCE(rerun_of, ''), input_matches_original,
+                  data_quality
+              FROM public.analysis_runs
+              WHERE $1 = '' OR type = $1
+              ORDER BY started_at DESC
+              LIMIT $2`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, analysisType, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []entities.AnalysisRun
+	for rows.Next() {
+		run, err := scanAnalysisRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	return runs, rows.Err()
+}
+
+func scanAnalysisRun(row rowScanner) (*entities.AnalysisRun, error) {
+	var run entities.AnalysisRun
+	var parameters, rowCounts, dataQuality []byte
+	var inputMatches sql.NullBool
+	if err := row.Scan(&run.ID, &run.Type, &parameters, &run.CodeVersion, &rowCounts, &run.InputHash,
+		&run.OutputCount, &run.StartedAt, &run.FinishedAt, &run.RerunOf, &inputMatches, &dataQuality); err != nil {
+		return nil, err
+	}
+
+	run.Parameters = parameters
+	if inputMatches.Valid {
+		run.InputMatchesOriginal = &inputMatches.Bool
+	}
+	if err := json.Unmarshal(rowCounts, &run.InputRowCounts); err != nil {
+		return nil, err
+	}
+	if dataQuality != nil {
+		run.DataQuality = &entities.DataQualityReport{}
+		if err := json.Unmarshal(dataQuality, run.DataQuality); err != nil {
+			return nil, err
+		}
+	}
+	return &run, nil
+}
@@ -0,0 +1,273 @@
+// analitics-service/internal/infrastructure/postgres/analytics_schema.go
+package postgres
+
+// AnalyticsSchema описывает таблицы, в которые аналитический сервис сохраняет результаты анализа и служебное состояние
+// Назначение таблиц описано в комментариях к репозиториям, которые с ними работают.
+// Все операторы идемпотентны, схема применяется при каждом запуске сервиса
+const AnalyticsSchema = `
+CREATE TABLE IF NOT EXISTS public.promotion_impacts (
+	promotion_id  TEXT NOT NULL,
+	analysis_date TIMESTAMPTZ NOT NULL,
+	payload       JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_promotion_impacts_promotion_id ON public.promotion_impacts (promotion_id, analysis_date);
+
+CREATE TABLE IF NOT EXISTS public.demand_forecasts (
+	id           TEXT PRIMARY KEY,
+	level        TEXT NOT NULL,
+	target_id    TEXT NOT NULL,
+	generated_at TIMESTAMPTZ NOT NULL,
+	payload      JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_demand_forecasts_target ON public.demand_forecasts (level, target_id, generated_at);
+
+CREATE TABLE IF NOT EXISTS public.price_suggestions (
+	id             TEXT PRIMARY KEY,
+	run_id         TEXT NOT NULL DEFAULT '',
+	product_id     TEXT NOT NULL,
+	time_slot      TEXT NOT NULL,
+	effective_date TIMESTAMPTZ NOT NULL,
+	created_at     TIMESTAMPTZ NOT NULL,
+	payload        JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_price_suggestions_product ON public.price_suggestions (product_id, time_slot, effective_date);
+
+CREATE TABLE IF NOT EXISTS public.pricing_runs (
+	id             TEXT PRIMARY KEY,
+	effective_date TIMESTAMPTZ NOT NULL,
+	payload        JSONB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.sales_anomalies (
+	id            TEXT PRIMARY KEY,
+	product_id    TEXT NOT NULL,
+	metric        TEXT NOT NULL,
+	granularity   TEXT NOT NULL,
+	period_start  TIMESTAMPTZ NOT NULL,
+	severity_rank INTEGER NOT NULL,
+	payload       JSONB NOT NULL,
+	UNIQUE (product_id, metric, granularity, period_start)
+);
+CREATE INDEX IF NOT EXISTS idx_sales_anomalies_period_start ON public.sales_anomalies (period_start);
+
+CREATE TABLE IF NOT EXISTS public.retention_metrics (
+	period       TEXT NOT NULL,
+	period_start TIMESTAMPTZ NOT NULL,
+	period_end   TIMESTAMPTZ NOT NULL,
+	payload      JSONB NOT NULL,
+	PRIMARY KEY (period, period_start)
+);
+
+CREATE TABLE IF NOT EXISTS public.product_bundles (
+	id         TEXT PRIMARY KEY,
+	status     TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	payload    JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_product_bundles_status ON public.product_bundles (status, created_at);
+
+CREATE TABLE IF NOT EXISTS public.product_lifecycles (
+	product_id          TEXT PRIMARY KEY,
+	stage               TEXT NOT NULL,
+	delisting_candidate BOOLEAN NOT NULL DEFAULT FALSE,
+	payload             JSONB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.abc_analysis_results (
+	analysis_date TIMESTAMPTZ NOT NULL,
+	run_id        TEXT NOT NULL DEFAULT '',
+	payload       JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_abc_analysis_results_analysis_date ON public.abc_analysis_results (analysis_date);
+
+CREATE TABLE IF NOT EXISTS public.abc_analysis_criteria (
+	saved_at TIMESTAMPTZ NOT NULL,
+	payload  JSONB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.abc_segments (
+	product_id       TEXT NOT NULL,
+	analysis_date    TIMESTAMPTZ NOT NULL,
+	revenue_segment  TEXT NOT NULL,
+	quantity_segment TEXT NOT NULL,
+	profit_segment   TEXT NOT NULL,
+	final_segment    TEXT NOT NULL,
+	score            DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (analysis_date, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.association_rules (
+	run_id     TEXT NOT NULL DEFAULT '',
+	saved_at   TIMESTAMPTZ NOT NULL,
+	items      TEXT[] NOT NULL DEFAULT '{}',
+	categories TEXT[] NOT NULL DEFAULT '{}',
+	support    DOUBLE PRECISION NOT NULL,
+	confidence DOUBLE PRECISION NOT NULL,
+	lift       DOUBLE PRECISION NOT NULL,
+	payload    JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_association_rules_saved_at ON public.association_rules (saved_at);
+
+CREATE TABLE IF NOT EXISTS public.discount_recommendations (
+	run_id        TEXT NOT NULL DEFAULT '',
+	product_id    TEXT NOT NULL DEFAULT '',
+	category      TEXT NOT NULL DEFAULT '',
+	abc_category  TEXT NOT NULL DEFAULT '',
+	daypart       TEXT NOT NULL DEFAULT '',
+	analysis_date TIMESTAMPTZ NOT NULL,
+	payload       JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_discount_recommendations_run_id ON public.discount_recommendations (run_id);
+CREATE INDEX IF NOT EXISTS idx_discount_recommendations_product ON public.discount_recommendations (product_id, category, daypart, analysis_date);
+
+CREATE TABLE IF NOT EXISTS public.product_profit_margins (
+	product_id TEXT PRIMARY KEY,
+	margin     NUMERIC(7, 2) NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS public.analytics_outbox (
+	sequence     BIGSERIAL PRIMARY KEY,
+	event_id     TEXT NOT NULL UNIQUE,
+	type         TEXT NOT NULL,
+	key          TEXT NOT NULL,
+	payload      JSONB NOT NULL,
+	created_at   TIMESTAMPTZ NOT NULL,
+	published_at TIMESTAMPTZ,
+	attempts     INTEGER NOT NULL DEFAULT 0,
+	last_error   TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_analytics_outbox_pending ON public.analytics_outbox (sequence) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_analytics_outbox_published_at ON public.analytics_outbox (published_at) WHERE published_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS public.analytics_job_locks (
+	job_name     TEXT PRIMARY KEY,
+	owner        TEXT NOT NULL,
+	locked_until TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.analytics_job_slots (
+	job_name     TEXT NOT NULL,
+	scheduled_at TIMESTAMPTZ NOT NULL,
+	owner        TEXT NOT NULL,
+	claimed_at   TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (job_name, scheduled_at)
+);
+CREATE INDEX IF NOT EXISTS idx_analytics_job_slots_scheduled_at ON public.analytics_job_slots (scheduled_at);
+
+CREATE TABLE IF NOT EXISTS public.analytics_job_runs (
+	id          TEXT PRIMARY KEY,
+	job_name    TEXT NOT NULL,
+	status      TEXT NOT NULL,
+	trigger     TEXT NOT NULL,
+	instance    TEXT NOT NULL,
+	parameters  JSONB NOT NULL,
+	started_at  TIMESTAMPTZ NOT NULL,
+	finished_at TIMESTAMPTZ,
+	duration_ms BIGINT NOT NULL DEFAULT 0,
+	error       TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_analytics_job_runs_job_started ON public.analytics_job_runs (job_name, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_analytics_job_runs_started_at ON public.analytics_job_runs (started_at DESC);
+
+CREATE TABLE IF NOT EXISTS public.analysis_runs (
+	id                     TEXT PRIMARY KEY,
+	type                   TEXT NOT NULL,
+	parameters             JSONB NOT NULL,
+	code_version           TEXT NOT NULL,
+	input_row_counts       JSONB NOT NULL,
+	input_hash             TEXT NOT NULL,
+	output_count           INTEGER NOT NULL,
+	started_at             TIMESTAMPTZ NOT NULL,
+	finished_at            TIMESTAMPTZ NOT NULL,
+	rerun_of               TEXT REFERENCES public.analysis_runs (id),
+	input_matches_original BOOLEAN,
+	data_quality           JSONB
+);
+CREATE INDEX IF NOT EXISTS idx_analysis_runs_type_started ON public.analysis_runs (type, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_analysis_runs_started_at ON public.analysis_runs (started_at DESC);
+
+CREATE TABLE IF NOT EXISTS public.quarantined_records (
+	run_id         TEXT NOT NULL REFERENCES public.analysis_runs (id) ON DELETE CASCADE,
+	source         TEXT NOT NULL,
+	record_id      TEXT NOT NULL,
+	rule           TEXT NOT NULL,
+	reason         TEXT NOT NULL,
+	payload        JSONB NOT NULL,
+	quarantined_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_quarantined_records_run_id ON public.quarantined_records (run_id);
+
+CREATE TABLE IF NOT EXISTS public.ab_test_results (
+	test_id     TEXT PRIMARY KEY,
+	start_date  TIMESTAMPTZ NOT NULL,
+	end_date    TIMESTAMPTZ NOT NULL,
+	product_ids TEXT[] NOT NULL DEFAULT '{}',
+	payload     JSONB NOT NULL,
+	updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_ab_test_results_period ON public.ab_test_results (start_date, end_date);
+CREATE INDEX IF NOT EXISTS idx_ab_test_results_product_ids ON public.ab_test_results USING GIN (product_ids);
+
+CREATE TABLE IF NOT EXISTS public.experiments (
+	id         TEXT PRIMARY KEY,
+	layer      TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	start_date TIMESTAMPTZ NOT NULL,
+	end_date   TIMESTAMPTZ NOT NULL,
+	payload    JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_experiments_layer ON public.experiments (layer);
+CREATE INDEX IF NOT EXISTS idx_experiments_active ON public.experiments (status, start_date, end_date);
+
+CREATE TABLE IF NOT EXISTS public.experiment_exposures (
+	id            TEXT PRIMARY KEY,
+	experiment_id TEXT NOT NULL REFERENCES public.experiments (id) ON DELETE CASCADE,
+	variant_id    TEXT NOT NULL,
+	customer_id   TEXT NOT NULL,
+	exposed_at    TIMESTAMPTZ NOT NULL,
+	channel       TEXT NOT NULL DEFAULT '',
+	created_at    TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_experiment_exposures_first ON public.experiment_exposures (experiment_id, customer_id, exposed_at);
+
+CREATE TABLE IF NOT EXISTS public.bandits (
+	id         TEXT PRIMARY KEY,
+	product_id TEXT NOT NULL DEFAULT '',
+	category   TEXT NOT NULL DEFAULT '',
+	is_active  BOOLEAN NOT NULL,
+	started_at TIMESTAMPTZ NOT NULL,
+	payload    JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_bandits_active_product ON public.bandits (product_id, category) WHERE is_active;
+
+CREATE TABLE IF NOT EXISTS public.bandit_arms (
+	bandit_id          TEXT NOT NULL REFERENCES public.bandits (id) ON DELETE CASCADE,
+	id                 TEXT NOT NULL,
+	discount_pct       DOUBLE PRECISION NOT NULL,
+	pulls              INTEGER NOT NULL DEFAULT 0,
+	successes          INTEGER NOT NULL DEFAULT 0,
+	reward_sum         DOUBLE PRECISION NOT NULL DEFAULT 0,
+	reward_sum_squares DOUBLE PRECISION NOT NULL DEFAULT 0,
+	updated_at         TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (bandit_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS public.uplift_models (
+	id         TEXT PRIMARY KEY,
+	method     TEXT NOT NULL,
+	trained_at TIMESTAMPTZ NOT NULL,
+	payload    JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_uplift_models_trained_at ON public.uplift_models (trained_at DESC);
+
+CREATE TABLE IF NOT EXISTS public.uplift_targets (
+	model_id    TEXT NOT NULL REFERENCES public.uplift_models (id) ON DELETE CASCADE,
+	rank        INTEGER NOT NULL,
+	customer_id TEXT NOT NULL,
+	uplift      DOUBLE PRECISION NOT NULL,
+	payload     JSONB NOT NULL,
+	created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (model_id, rank)
+);
+`
@@ -0,0 +1,59 @@
+// analitics-service/internal/infrastructure/postgres/anomaly_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// AnomalyRepository хранит аномалии продаж в таблице public.sales_anomalies (см. AnalyticsSchema)
+// Повторное обнаружение аномалии той же метрики товара за тот же период заменяет сохраненную
+// severity_rank хранит AnomalySeverity.Rank, чтобы отбирать аномалии не ниже заданной серьезности
+type AnomalyRepository struct {
+	db *sql.DB
+}
+
+func NewAnomalyRepository(db *sql.DB) repositories.AnomalyRepository {
+	return &AnomalyRepository{db: db}
+}
+
+func (r *AnomalyRepository) SaveAnomalies(ctx context.Context, anomalies []entities.SalesAnomaly) error {
+	query := `INSERT INTO public.sales_anomalies (id, product_id, metric, granularity, period_start, severity_rank, payload)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)
+              ON CONFLICT (product_id, metric, granularity, period_start) DO UPDATE
+              SET id = EXCLUDED.id, severity_rank = EXCLUDED.severity_rank, payload = EXCLUDED.payload`
+	return NewTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, anomaly := range anomalies {
+			payload, err := json.Marshal(anomaly)
+			if err != nil {
+				return err
+			}
+			if _, err := executor(ctx, r.db).ExecContext(ctx, query, anomaly.ID, anomaly.ProductID, anomaly.Metric, anomaly.Granularity,
+				anomaly.PeriodStart, anomaly.Severity.Rank(), payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *AnomalyRepository) GetAnomaliesByPeriod(ctx context.Context, startDate, endDate time.Time, minSeverity entities.AnomalySeverity) ([]entities.SalesAnomaly, error) {
+	query := `SELECT payload
+              FROM public.sales_anomalies
+              WHERE period_start >= $1 AND period_start < $2 AND severity_rank >= $3
+              ORDER BY period_start, product_id, metric`
+	return queryPayloads[entities.SalesAnomaly](ctx, executor(ctx, r.db), query, startDate, endDate, minSeverity.Rank())
+}
+
+func (r *AnomalyRepository) GetAnomaliesByProduct(ctx context.Context, productID string, startDate, endDate time.Time) ([]entities.SalesAnomaly, error) {
+	query := `SELECT payload
+              FROM public.sales_anomalies
+              WHERE product_id = $1 AND period_start >= $2 AND period_start < $3
+              ORDER BY period_start, metric`
+	return queryPayloads[entities.SalesAnomaly](ctx, executor(ctx, r.db), query, productID, startDate, endDate)
+}
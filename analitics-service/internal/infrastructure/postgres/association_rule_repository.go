@@ -0,0 +1,104 @@
+// analitics-service/internal/infrastructure/postgres/association_rule_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// AssociationRuleRepository хранит ассоциативные правила в таблице public.association_rules (см. AnalyticsSchema)
+// Каждое сохранение добавляет новый набор правил; чтение возвращает правила последнего набора
+type AssociationRuleRepository struct {
+	db *sql.DB
+}
+
+func NewAssociationRuleRepository(db *sql.DB) repositories.AssociationRuleRepository {
+	return &AssociationRuleRepository{db: db}
+}
+
+// latestRulesCondition отбирает правила последнего сохраненного набора
+const latestRulesCondition = `saved_at = (SELECT MAX(saved_at) FROM public.association_rules)`
+
+func (r *AssociationRuleRepository) SaveRules(ctx context.Context, rules []entities.AssociationRule) error {
+	// Все правила набора получают одно время сохранения, по нему набор отличается от предыдущих
+	savedAt := time.Now()
+	query := `INSERT INTO public.association_rules (run_id, saved_at, items, categories, support, confidence, lift, payload)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	return NewTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, rule := range rules {
+			payload, err := json.Marshal(rule)
+			if err != nil {
+				return err
+			}
+			if _, err := executor(ctx, r.db).ExecContext(ctx, query, rule.RunID, savedAt, pq.Array(nonNilStrings(rule.Items)),
+				pq.Array(nonNilStrings(rule.Categories)), rule.Support, rule.Confidence, rule.Lift, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *AssociationRuleRepository) GetRulesByProduct(ctx context.Context, productID string) ([]entities.AssociationRule, error) {
+	return r.queryRules(ctx, `$1 = ANY(items)`, productID)
+}
+
+func (r *AssociationRuleRepository) GetRulesByCategory(ctx context.Context, category string) ([]entities.AssociationRule, error) {
+	return r.queryRules(ctx, `$1 = ANY(categories)`, category)
+}
+
+func (r *AssociationRuleRepository) GetRulesByConfidence(ctx context.Context, minConfidence float64) ([]entities.AssociationRule, error) {
+	return r.queryRules(ctx, `confidence >= $1`, minConfidence)
+}
+
+func (r *AssociationRuleRepository) GetRulesBySupport(ctx context.Context, minSupport float64) ([]entities.AssociationRule, error) {
+	return r.queryRules(ctx, `support >= $1`, minSupport)
+}
+
+func (r *AssociationRuleRepository) GetRulesByLift(ctx context.Context, minLift float64) ([]entities.AssociationRule, error) {
+	return r.queryRules(ctx, `lift >= $1`, minLift)
+}
+
+func (r *AssociationRuleRepository) StreamRules(ctx context.Context, fn func(rule entities.AssociationRule) error) error {
+	query := `SELECT payload
+              FROM public.association_rules
+              WHERE ` + latestRulesCondition + `
+              ORDER BY lift DESC, confidence DESC`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return err
+		}
+		var rule entities.AssociationRule
+		if err := json.Unmarshal(payload, &rule); err != nil {
+			return err
+		}
+		if err := fn(rule); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// queryRules возвращает правила последнего набора, отобранные условием where, от сильных к слабым
+func (r *AssociationRuleRepository) queryRules(ctx context.Context, where string, args ...interface{}) ([]entities.AssociationRule, error) {
+	query := `SELECT payload
+              FROM public.association_rules
+              WHERE ` + where + ` AND ` + latestRulesCondition + `
+              ORDER BY lift DESC, confidence DESC`
+	return queryPayloads[entities.AssociationRule](ctx, executor(ctx, r.db), query, args...)
+}
@@ -0,0 +1,107 @@
+// analitics-service/internal/infrastructure/postgres/bandit_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// BanditRepository хранит бандитов в таблице public.bandits и статистику их рук в таблице public.bandit_arms
+// (см. AnalyticsSchema). Статистика рук обновляется инкрементально в базе, поэтому исходы,
+// записанные разными репликами одновременно, не теряются
+type BanditRepository struct {
+	db *sql.DB
+}
+
+func NewBanditRepository(db *sql.DB) repositories.BanditRepository {
+	return &BanditRepository{db: db}
+}
+
+// SaveBandit сохраняет бандита и добавляет отсутствующие руки; статистика существующих рук не меняется
+func (r *BanditRepository) SaveBandit(ctx context.Context, bandit entities.Bandit, arms []entities.BanditArm) error {
+	payload, err := json.Marshal(bandit)
+	if err != nil {
+		return err
+	}
+
+	banditQuery := `INSERT INTO public.bandits (id, product_id, category, is_active, started_at, payload)
+                    VALUES ($1, $2, $3, $4, $5, $6)
+                    ON CONFLICT (id) DO UPDATE
+                    SET product_id = EXCLUDED.product_id, category = EXCLUDED.category, is_active = EXCLUDED.is_active,
+                        started_at = EXCLUDED.started_at, payload = EXCLUDED.payload`
+	armQuery := `INSERT INTO public.bandit_arms (bandit_id, id, discount_pct, pulls, successes, reward_sum, reward_sum_squares, updated_at)
+                 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+                 ON CONFLICT (bandit_id, id) DO NOTHING`
+	return NewTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := executor(ctx, r.db).ExecContext(ctx, banditQuery, bandit.ID, bandit.ProductID, bandit.Category,
+			bandit.IsActive, bandit.StartedAt, payload); err != nil {
+			return err
+		}
+		for _, arm := range arms {
+			if _, err := executor(ctx, r.db).ExecContext(ctx, armQuery, bandit.ID, arm.ID, arm.DiscountPct, arm.Pulls,
+				arm.Successes, arm.RewardSum, arm.RewardSumSquares, arm.UpdatedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetBanditByID возвращает пустого бандита, если бандит не найден
+func (r *BanditRepository) GetBanditByID(ctx context.Context, banditID string) (entities.Bandit, error) {
+	query := `SELECT payload FROM public.bandits WHERE id = $1`
+	return queryPayload[entities.Bandit](ctx, executor(ctx, r.db), query, banditID)
+}
+
+// GetActiveBandit предпочитает бандита товара бандиту его категории, а среди равных — запущенного последним
+// Возвращает пустого бандита, если активного бандита нет
+func (r *BanditRepository) GetActiveBandit(ctx context.Context, productID, category string) (entities.Bandit, error) {
+	query := `SELECT payload
+              FROM public.bandits
+              WHERE is_active
+                AND (($1 <> '' AND product_id = $1) OR ($2 <> '' AND product_id = '' AND category = $2))
+              ORDER BY product_id <> '' DESC, started_at DESC
+              LIMIT 1`
+	return queryPayload[entities.Bandit](ctx, executor(ctx, r.db), query, productID, category)
+}
+
+func (r *BanditRepository) GetArms(ctx context.Context, banditID string) ([]entities.BanditArm, error) {
+	query := `SELECT id, bandit_id, discount_pct, pulls, successes, reward_sum, reward_sum_squares, updated_at
+              FROM public.bandit_arms
+              WHERE bandit_id = $1
+              ORDER BY discount_pct`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, banditID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var arms []entities.BanditArm
+	for rows.Next() {
+		var arm entities.BanditArm
+		if err := rows.Scan(&arm.ID, &arm.BanditID, &arm.DiscountPct, &arm.Pulls, &arm.Successes, &arm.RewardSum,
+			&arm.RewardSumSquares, &arm.UpdatedAt); err != nil {
+			return nil, err
+		}
+		arms = append(arms, arm)
+	}
+	return arms, rows.Err()
+}
+
+// RecordArmOutcome увеличивает счетчики руки одним UPDATE; неизвестная рука не изменяется
+func (r *BanditRepository) RecordArmOutcome(ctx context.Context, banditID, armID string, reward float64, success bool) error {
+	query := `UPDATE public.bandit_arms
+              SET pulls = pulls + 1,
+                  successes = successes + CASE WHEN $4 THEN 1 ELSE 0 END,
+                  reward_sum = reward_sum + $3,
+                  reward_sum_squares = reward_sum_squares + $3 * $3,
+                  updated_at = now()
+              WHERE bandit_id = $1 AND id = $2`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, banditID, armID, reward, success)
+	return err
+}
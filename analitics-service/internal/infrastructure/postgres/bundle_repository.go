@@ -0,0 +1,63 @@
+// analitics-service/internal/infrastructure/postgres/bundle_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// BundleRepository хранит предложенные наборы в таблице public.product_bundles (см. AnalyticsSchema)
+type BundleRepository struct {
+	db *sql.DB
+}
+
+func NewBundleRepository(db *sql.DB) repositories.BundleRepository {
+	return &BundleRepository{db: db}
+}
+
+func (r *BundleRepository) SaveBundles(ctx context.Context, bundles []entities.ProductBundle) error {
+	query := `INSERT INTO public.product_bundles (id, status, created_at, payload)
+              VALUES ($1, $2, $3, $4)
+              ON CONFLICT (id) DO UPDATE SET status = EXCLUDED.status, payload = EXCLUDED.payload`
+	return NewTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, bundle := range bundles {
+			payload, err := json.Marshal(bundle)
+			if err != nil {
+				return err
+			}
+			if _, err := executor(ctx, r.db).ExecContext(ctx, query, bundle.ID, bundle.Status, bundle.CreatedAt, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetBundleByID возвращает пустой набор, если набор не найден
+func (r *BundleRepository) GetBundleByID(ctx context.Context, bundleID string) (entities.ProductBundle, error) {
+	query := `SELECT payload FROM public.product_bundles WHERE id = $1`
+	return queryPayload[entities.ProductBundle](ctx, executor(ctx, r.db), query, bundleID)
+}
+
+func (r *BundleRepository) GetBundlesByStatus(ctx context.Context, status entities.BundleStatus) ([]entities.ProductBundle, error) {
+	query := `SELECT payload
+              FROM public.product_bundles
+              WHERE status = $1
+              ORDER BY created_at DESC, id`
+	return queryPayloads[entities.ProductBundle](ctx, executor(ctx, r.db), query, status)
+}
+
+func (r *BundleRepository) UpdateBundle(ctx context.Context, bundle entities.ProductBundle) error {
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE public.product_bundles SET status = $1, payload = $2 WHERE id = $3`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, bundle.Status, payload, bundle.ID)
+	return err
+}
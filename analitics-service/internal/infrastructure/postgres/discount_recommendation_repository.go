@@ -0,0 +1,81 @@
+// analitics-service/internal/infrastructure/postgres/discount_recommendation_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// DiscountRecommendationRepository хранит рекомендации по скидкам в таблице public.discount_recommendations
+// (см. AnalyticsSchema)
+// Рекомендации каждого запуска добавляются к истории; выборки по товару, категории и сегменту возвращают
+// последнюю рекомендацию для каждого товара, категории и части дня
+type DiscountRecommendationRepository struct {
+	db *sql.DB
+}
+
+func NewDiscountRecommendationRepository(db *sql.DB) repositories.DiscountRecommendationRepository {
+	return &DiscountRecommendationRepository{db: db}
+}
+
+func (r *DiscountRecommendationRepository) SaveRecommendation(ctx context.Context, recommendation entities.DiscountRecommendation) error {
+	payload, err := json.Marshal(recommendation)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.discount_recommendations (run_id, product_id, category, abc_category, daypart, analysis_date, payload)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, recommendation.RunID, recommendation.ProductID, recommendation.Category,
+		recommendation.ABCCategory, recommendation.Daypart, recommendation.AnalysisDate, payload)
+	return err
+}
+
+// GetRecommendationByProductID возвращает последнюю рекомендацию на весь день
+// или пустую рекомендацию, если для товара ее нет
+func (r *DiscountRecommendationRepository) GetRecommendationByProductID(ctx context.Context, productID string) (entities.DiscountRecommendation, error) {
+	query := `SELECT payload
+              FROM public.discount_recommendations
+              WHERE product_id = $1 AND daypart = ''
+              ORDER BY analysis_date DESC
+              LIMIT 1`
+	return queryPayload[entities.DiscountRecommendation](ctx, executor(ctx, r.db), query, productID)
+}
+
+func (r *DiscountRecommendationRepository) GetRecommendationsByCategory(ctx context.Context, category string) ([]entities.DiscountRecommendation, error) {
+	return r.queryLatest(ctx, `category = $1`, category)
+}
+
+func (r *DiscountRecommendationRepository) GetRecommendationsBySegment(ctx context.Context, segment entities.Segment) ([]entities.DiscountRecommendation, error) {
+	return r.queryLatest(ctx, `abc_category = $1`, segment)
+}
+
+// GetLatestRecommendations возвращает все рекомендации при limit = 0
+func (r *DiscountRecommendationRepository) GetLatestRecommendations(ctx context.Context, limit int) ([]entities.DiscountRecommendation, error) {
+	query := `SELECT payload
+              FROM public.discount_recommendations
+              ORDER BY analysis_date DESC
+              LIMIT NULLIF($1, 0)`
+	return queryPayloads[entities.DiscountRecommendation](ctx, executor(ctx, r.db), query, limit)
+}
+
+func (r *DiscountRecommendationRepository) GetRecommendationsByRunID(ctx context.Context, runID string) ([]entities.DiscountRecommendation, error) {
+	query := `SELECT payload
+              FROM public.discount_recommendations
+              WHERE run_id = $1
+              ORDER BY product_id, category, daypart`
+	return queryPayloads[entities.DiscountRecommendation](ctx, executor(ctx, r.db), query, runID)
+}
+
+// queryLatest возвращает последние рекомендации, отобранные условием where, по одной на товар, категорию и часть дня
+func (r *DiscountRecommendationRepository) queryLatest(ctx context.Context, where string, args ...interface{}) ([]entities.DiscountRecommendation, error) {
+	query := `SELECT DISTINCT ON (product_id, category, daypart) payload
+              FROM public.discount_recommendations
+              WHERE ` + where + `
+              ORDER BY product_id, category, daypart, analysis_date DESC`
+	return queryPayloads[entities.DiscountRecommendation](ctx, executor(ctx, r.db), query, args...)
+}
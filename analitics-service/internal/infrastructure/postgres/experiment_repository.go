@@ -0,0 +1,60 @@
+// analitics-service/internal/infrastructure/postgres/experiment_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// ExperimentRepository хранит реестр экспериментов в таблице public.experiments (см. AnalyticsSchema)
+// Колонка layer хранит Experiment.Layer(), поэтому эксперименты без явного слоя отбираются по собственному слою
+type ExperimentRepository struct {
+	db *sql.DB
+}
+
+func NewExperimentRepository(db *sql.DB) repositories.ExperimentRepository {
+	return &ExperimentRepository{db: db}
+}
+
+func (r *ExperimentRepository) SaveExperiment(ctx context.Context, experiment entities.Experiment) error {
+	payload, err := json.Marshal(experiment)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.experiments (id, layer, status, start_date, end_date, payload)
+              VALUES ($1, $2, $3, $4, $5, $6)
+              ON CONFLICT (id) DO UPDATE
+              SET layer = EXCLUDED.layer, status = EXCLUDED.status, start_date = EXCLUDED.start_date,
+                  end_date = EXCLUDED.end_date, payload = EXCLUDED.payload`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, experiment.ID, experiment.Layer(), experiment.Status,
+		experiment.StartDate, experiment.EndDate, payload)
+	return err
+}
+
+// GetExperimentByID возвращает пустой эксперимент, если эксперимент не найден
+func (r *ExperimentRepository) GetExperimentByID(ctx context.Context, experimentID string) (entities.Experiment, error) {
+	query := `SELECT payload FROM public.experiments WHERE id = $1`
+	return queryPayload[entities.Experiment](ctx, executor(ctx, r.db), query, experimentID)
+}
+
+func (r *ExperimentRepository) GetExperimentsByLayer(ctx context.Context, layerID string) ([]entities.Experiment, error) {
+	query := `SELECT payload
+              FROM public.experiments
+              WHERE layer = $1
+              ORDER BY start_date, id`
+	return queryPayloads[entities.Experiment](ctx, executor(ctx, r.db), query, layerID)
+}
+
+func (r *ExperimentRepository) GetActiveExperiments(ctx context.Context, at time.Time) ([]entities.Experiment, error) {
+	query := `SELECT payload
+              FROM public.experiments
+              WHERE status = $1 AND start_date <= $2 AND end_date > $2
+              ORDER BY id`
+	return queryPayloads[entities.Experiment](ctx, executor(ctx, r.db), query, entities.ExperimentRunning, at)
+}
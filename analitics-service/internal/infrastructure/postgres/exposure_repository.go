@@ -0,0 +1,53 @@
+// analitics-service/internal/infrastructure/postgres/exposure_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// ExposureRepository хранит журнал показов экспериментов в таблице public.experiment_exposures (см. AnalyticsSchema)
+type ExposureRepository struct {
+	db *sql.DB
+}
+
+func NewExposureRepository(db *sql.DB) repositories.ExposureRepository {
+	return &ExposureRepository{db: db}
+}
+
+// LogExposure не дублирует показ при повторной отправке с тем же ID
+func (r *ExposureRepository) LogExposure(ctx context.Context, exposure entities.Exposure) error {
+	query := `INSERT INTO public.experiment_exposures (id, experiment_id, variant_id, customer_id, exposed_at, channel, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)
+              ON CONFLICT (id) DO NOTHING`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, exposure.ID, exposure.ExperimentID, exposure.VariantID,
+		exposure.CustomerID, exposure.ExposedAt, exposure.Channel, exposure.CreatedAt)
+	return err
+}
+
+func (r *ExposureRepository) GetFirstExposures(ctx context.Context, experimentID string) ([]entities.Exposure, error) {
+	query := `SELECT DISTINCT ON (customer_id) id, experiment_id, variant_id, customer_id, exposed_at, channel, created_at
+              FROM public.experiment_exposures
+              WHERE experiment_id = $1
+              ORDER BY customer_id, exposed_at, id`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exposures []entities.Exposure
+	for rows.Next() {
+		var exposure entities.Exposure
+		if err := rows.Scan(&exposure.ID, &exposure.ExperimentID, &exposure.VariantID, &exposure.CustomerID,
+			&exposure.ExposedAt, &exposure.Channel, &exposure.CreatedAt); err != nil {
+			return nil, err
+		}
+		exposures = append(exposures, exposure)
+	}
+	return exposures, rows.Err()
+}
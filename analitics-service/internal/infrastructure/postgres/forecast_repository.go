@@ -0,0 +1,52 @@
+// analitics-service/internal/infrastructure/postgres/forecast_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// ForecastRepository хранит прогнозы спроса в таблице public.demand_forecasts (см. AnalyticsSchema)
+type ForecastRepository struct {
+	db *sql.DB
+}
+
+func NewForecastRepository(db *sql.DB) repositories.ForecastRepository {
+	return &ForecastRepository{db: db}
+}
+
+func (r *ForecastRepository) SaveForecast(ctx context.Context, forecast entities.DemandForecast) error {
+	payload, err := json.Marshal(forecast)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.demand_forecasts (id, level, target_id, generated_at, payload)
+              VALUES ($1, $2, $3, $4, $5)
+              ON CONFLICT (id) DO UPDATE SET generated_at = EXCLUDED.generated_at, payload = EXCLUDED.payload`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, forecast.ID, forecast.Level, forecast.TargetID, forecast.GeneratedAt, payload)
+	return err
+}
+
+// GetLatestForecast возвращает пустой прогноз, если прогнозов для цели нет
+func (r *ForecastRepository) GetLatestForecast(ctx context.Context, level entities.ForecastLevel, targetID string) (entities.DemandForecast, error) {
+	query := `SELECT payload
+              FROM public.demand_forecasts
+              WHERE level = $1 AND target_id = $2
+              ORDER BY generated_at DESC
+              LIMIT 1`
+	return queryPayload[entities.DemandForecast](ctx, executor(ctx, r.db), query, level, targetID)
+}
+
+func (r *ForecastRepository) GetForecastsGeneratedAfter(ctx context.Context, level entities.ForecastLevel, after time.Time) ([]entities.DemandForecast, error) {
+	query := `SELECT payload
+              FROM public.demand_forecasts
+              WHERE level = $1 AND generated_at > $2
+              ORDER BY target_id, generated_at`
+	return queryPayloads[entities.DemandForecast](ctx, executor(ctx, r.db), query, level, after)
+}
@@ -0,0 +1,72 @@
+// analitics-service/internal/infrastructure/postgres/job_lock_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"analitics-service/internal/domain/repositories"
+)
+
+// JobLockRepository хранит аренды блокировок задач в таблице public.analytics_job_locks
+// и закрепленные запуски по расписанию в таблице public.analytics_job_slots (см. AnalyticsSchema)
+type JobLockRepository struct {
+	db *sql.DB
+}
+
+func NewJobLockRepository(db *sql.DB) repositories.JobLockRepository {
+	return &JobLockRepository{db: db}
+}
+
+// TryAcquireLock захватывает блокировку одним upsert: строка перезаписывается только если аренда истекла,
+// поэтому время сравнивается по часам базы данных, а не реплик
+func (r *JobLockRepository) TryAcquireLock(ctx context.Context, jobName, owner string, ttl time.Duration) (bool, error) {
+	query := `INSERT INTO public.analytics_job_locks (job_name, owner, locked_until)
+              VALUES ($1, $2, now() + $3 * interval '1 millisecond')
+              ON CONFLICT (job_name) DO UPDATE
+              SET owner = EXCLUDED.owner, locked_until = EXCLUDED.locked_until
+              WHERE analytics_job_locks.locked_until < now()`
+	res, err := r.db.ExecContext(ctx, query, jobName, owner, ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *JobLockRepository) ReleaseLock(ctx context.Context, jobName, owner string) error {
+	query := `DELETE FROM public.analytics_job_locks WHERE job_name = $1 AND owner = $2`
+	_, err := r.db.ExecContext(ctx, query, jobName, owner)
+	return err
+}
+
+// TryClaimSlot вставляет запись о слоте; конфликт по первичному ключу означает, что слот закрепила другая реплика
+func (r *JobLockRepository) TryClaimSlot(ctx context.Context, jobName string, scheduledAt time.Time, owner string) (bool, error) {
+	query := `INSERT INTO public.analytics_job_slots (job_name, scheduled_at, owner, claimed_at)
+              VALUES ($1, $2, $3, now())
+              ON CONFLICT (job_name, scheduled_at) DO NOTHING`
+	res, err := r.db.ExecContext(ctx, query, jobName, scheduledAt, owner)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *JobLockRepository) DeleteSlotsBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM public.analytics_job_slots WHERE scheduled_at < $1`
+	res, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
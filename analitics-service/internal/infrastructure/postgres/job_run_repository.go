@@ -0,0 +1,102 @@
+// analitics-service/internal/infrastructure/postgres/job_run_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// JobRunRepository хранит историю запусков задач в таблице public.analytics_job_runs (см. AnalyticsSchema)
+type JobRunRepository struct {
+	db *sql.DB
+}
+
+func NewJobRunRepository(db *sql.DB) repositories.JobRunRepository {
+	return &JobRunRepository{db: db}
+}
+
+func (r *JobRunRepository) CreateRun(ctx context.Context, run entities.JobRun) error {
+	parameters, err := json.Marshal(run.Parameters)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.analytics_job_runs (id, job_name, status, trigger, instance, parameters, started_at, finished_at, duration_ms, error)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err = r.db.ExecContext(ctx, query, run.ID, run.JobName, run.Status, run.Trigger, run.Instance,
+		parameters, run.StartedAt, run.FinishedAt, run.DurationMs, run.Error)
+	return err
+}
+
+func (r *JobRunRepository) UpdateRun(ctx context.Context, run entities.JobRun) error {
+	query := `UPDATE public.analytics_job_runs
+              SET status = $1, finished_at = $2, duration_ms = $3, error = $4
+              WHERE id = $5`
+	_, err := r.db.ExecContext(ctx, query, run.Status, run.FinishedAt, run.DurationMs, run.Error, run.ID)
+	return err
+}
+
+func (r *JobRunRepository) GetRunByID(ctx context.Context, runID string) (*entities.JobRun, error) {
+	query := `SELECT id, job_name, status, trigger, instance, parameters, started_at, finished_at, duration_ms, error
+              FROM public.analytics_job_runs
+              WHERE id = $1`
+	run, err := scanJobRun(r.db.QueryRowContext(ctx, query, runID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return run, nil
+}
+
+func (r *JobRunRepository) GetRuns(ctx context.Context, jobName string, limit int) ([]entities.JobRun, error) {
+	query := `SELECT id, job_name, status, trigger, instance, parameters, started_at, finished_at, duration_ms, error
+              FROM public.analytics_job_runs
+              WHERE $1 = '' OR job_name = $1
+              ORDER BY started_at DESC
+              LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, jobName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []entities.JobRun
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	return runs, rows.Err()
+}
+
+// rowScanner общий интерфейс sql.Row и sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJobRun(row rowScanner) (*entities.JobRun, error) {
+	var run entities.JobRun
+	var parameters []byte
+	var finishedAt sql.NullTime
+	if err := row.Scan(&run.ID, &run.JobName, &run.Status, &run.Trigger, &run.Instance,
+		&parameters, &run.StartedAt, &finishedAt, &run.DurationMs, &run.Error); err != nil {
+		return nil, err
+	}
+
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	if err := json.Unmarshal(parameters, &run.Parameters); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
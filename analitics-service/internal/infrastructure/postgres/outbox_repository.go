@@ -0,0 +1,87 @@
+// analitics-service/internal/infrastructure/postgres/outbox_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// OutboxRepository хранит исходящие события в таблице public.analytics_outbox (см. AnalyticsSchema)
+// Запись выполняется в транзакции из контекста, если она открыта через Transactor
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) repositories.OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) Enqueue(ctx context.Context, events []entities.OutboxEvent) error {
+	query := `INSERT INTO public.analytics_outbox (event_id, type, key, payload, created_at, attempts)
+              VALUES ($1, $2, $3, $4, $5, 0)`
+	for _, event := range events {
+		if _, err := executor(ctx, r.db).ExecContext(ctx, query, event.EventID, event.Type, event.Key, []byte(event.Payload),
+			event.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *OutboxRepository) GetPending(ctx context.Context, limit int) ([]entities.OutboxEvent, error) {
+	query := `SELECT sequence, event_id, type, key, payload, created_at, attempts, COALESCE(last_error, '')
+              FROM public.analytics_outbox
+              WHERE published_at IS NULL
+              ORDER BY sequence
+              LIMIT $1`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []entities.OutboxEvent
+	for rows.Next() {
+		var event entities.OutboxEvent
+		var payload []byte
+		if err := rows.Scan(&event.Sequence, &event.EventID, &event.Type, &event.Key, &payload, &event.CreatedAt,
+			&event.Attempts, &event.LastError); err != nil {
+			return nil, err
+		}
+		event.Payload = payload
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, sequences []int64) error {
+	query := `UPDATE public.analytics_outbox
+              SET published_at = now(), attempts = attempts + 1, last_error = NULL
+              WHERE sequence = ANY($1)`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, pq.Array(sequences))
+	return err
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, sequence int64, publishErr string) error {
+	query := `UPDATE public.analytics_outbox
+              SET attempts = attempts + 1, last_error = $2
+              WHERE sequence = $1`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, sequence, publishErr)
+	return err
+}
+
+func (r *OutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM public.analytics_outbox WHERE published_at < $1`
+	res, err := executor(ctx, r.db).ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
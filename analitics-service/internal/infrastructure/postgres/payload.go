@@ -0,0 +1,47 @@
+// analitics-service/internal/infrastructure/postgres/payload.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+// Результаты анализа хранятся целиком в колонке payload jsonb; отдельные колонки таблиц нужны только для отбора
+
+// queryPayloads выполняет запрос, возвращающий одну колонку jsonb, и разбирает каждую строку в T
+func queryPayloads[T any](ctx context.Context, exec dbExecutor, query string, args ...interface{}) ([]T, error) {
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []T
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, err
+		}
+		var value T
+		if err := json.Unmarshal(payload, &value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// queryPayload возвращает первую строку запроса или пустое значение, если строк нет
+func queryPayload[T any](ctx context.Context, exec dbExecutor, query string, args ...interface{}) (T, error) {
+	var value T
+	var payload []byte
+	if err := exec.QueryRowContext(ctx, query, args...).Scan(&payload); err != nil {
+		if err == sql.ErrNoRows {
+			return value, nil
+		}
+		return value, err
+	}
+	err := json.Unmarshal(payload, &value)
+	return value, err
+}
@@ -0,0 +1,72 @@
+// analitics-service/internal/infrastructure/postgres/pricing_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// PricingRepository хранит журнал предложенных цен в таблице public.price_suggestions
+// и итоги пакетных расчетов в таблице public.pricing_runs (см. AnalyticsSchema)
+type PricingRepository struct {
+	db *sql.DB
+}
+
+func NewPricingRepository(db *sql.DB) repositories.PricingRepository {
+	return &PricingRepository{db: db}
+}
+
+func (r *PricingRepository) SaveSuggestions(ctx context.Context, suggestions []entities.PriceSuggestion) error {
+	query := `INSERT INTO public.price_suggestions (id, run_id, product_id, time_slot, effective_date, created_at, payload)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	return NewTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, suggestion := range suggestions {
+			payload, err := json.Marshal(suggestion)
+			if err != nil {
+				return err
+			}
+			if _, err := executor(ctx, r.db).ExecContext(ctx, query, suggestion.ID, suggestion.RunID, suggestion.ProductID,
+				suggestion.TimeSlot, suggestion.EffectiveDate, suggestion.CreatedAt, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PricingRepository) GetLatestSuggestion(ctx context.Context, productID, timeSlot string, before time.Time) (*entities.PriceSuggestion, error) {
+	query := `SELECT payload
+              FROM public.price_suggestions
+              WHERE product_id = $1 AND time_slot = $2 AND effective_date < $3
+              ORDER BY effective_date DESC, created_at DESC
+              LIMIT 1`
+	suggestions, err := queryPayloads[entities.PriceSuggestion](ctx, executor(ctx, r.db), query, productID, timeSlot, before)
+	if err != nil || len(suggestions) == 0 {
+		return nil, err
+	}
+	return &suggestions[0], nil
+}
+
+func (r *PricingRepository) GetSuggestionHistory(ctx context.Context, productID string, startDate, endDate time.Time) ([]entities.PriceSuggestion, error) {
+	query := `SELECT payload
+              FROM public.price_suggestions
+              WHERE product_id = $1 AND effective_date >= $2 AND effective_date < $3
+              ORDER BY effective_date, time_slot, created_at`
+	return queryPayloads[entities.PriceSuggestion](ctx, executor(ctx, r.db), query, productID, startDate, endDate)
+}
+
+func (r *PricingRepository) SavePricingRun(ctx context.Context, run entities.PricingRun) error {
+	payload, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.pricing_runs (id, effective_date, payload) VALUES ($1, $2, $3)`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, run.ID, run.EffectiveDate, payload)
+	return err
+}
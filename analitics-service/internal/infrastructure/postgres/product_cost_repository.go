@@ -0,0 +1,64 @@
+// analitics-service/internal/infrastructure/postgres/product_cost_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// ProductCostRepository хранит историю себестоимости и цен товаров в таблице public.product_costs (см. SourceSchema)
+type ProductCostRepository struct {
+	db *sql.DB
+}
+
+func NewProductCostRepository(db *sql.DB) repositories.ProductCostRepository {
+	return &ProductCostRepository{db: db}
+}
+
+func (r *ProductCostRepository) GetCostHistory(ctx context.Context) ([]entities.ProductCost, error) {
+	query := `SELECT product_id, effective_from, cost, price
+              FROM public.product_costs
+              ORDER BY product_id, effective_from`
+	return r.queryCosts(ctx, query)
+}
+
+func (r *ProductCostRepository) GetProductCostHistory(ctx context.Context, productID string) ([]entities.ProductCost, error) {
+	query := `SELECT product_id, effective_from, cost, price
+              FROM public.product_costs
+              WHERE product_id = $1
+              ORDER BY effective_from`
+	return r.queryCosts(ctx, query, productID)
+}
+
+func (r *ProductCostRepository) SaveCosts(ctx context.Context, costs []entities.ProductCost) error {
+	query := `INSERT INTO public.product_costs (product_id, effective_from, cost, price)
+              VALUES ($1, $2, $3, $4)
+              ON CONFLICT (product_id, effective_from) DO UPDATE SET cost = EXCLUDED.cost, price = EXCLUDED.price`
+	for _, cost := range costs {
+		if _, err := executor(ctx, r.db).ExecContext(ctx, query, cost.ProductID, cost.EffectiveFrom, cost.Cost, cost.Price); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ProductCostRepository) queryCosts(ctx context.Context, query string, args ...interface{}) ([]entities.ProductCost, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var costs []entities.ProductCost
+	for rows.Next() {
+		var cost entities.ProductCost
+		if err := rows.Scan(&cost.ProductID, &cost.EffectiveFrom, &cost.Cost, &cost.Price); err != nil {
+			return nil, err
+		}
+		costs = append(costs, cost)
+	}
+	return costs, rows.Err()
+}
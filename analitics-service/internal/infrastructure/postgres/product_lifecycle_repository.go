@@ -0,0 +1,57 @@
+// analitics-service/internal/infrastructure/postgres/product_lifecycle_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// ProductLifecycleRepository хранит результаты последней классификации жизненного цикла в таблице
+// public.product_lifecycles (см. AnalyticsSchema)
+type ProductLifecycleRepository struct {
+	db *sql.DB
+}
+
+func NewProductLifecycleRepository(db *sql.DB) repositories.ProductLifecycleRepository {
+	return &ProductLifecycleRepository{db: db}
+}
+
+// SaveLifecycles заменяет предыдущую классификацию в одной транзакции, чтобы читатели не увидели неполный результат
+func (r *ProductLifecycleRepository) SaveLifecycles(ctx context.Context, lifecycles []entities.ProductLifecycle) error {
+	query := `INSERT INTO public.product_lifecycles (product_id, stage, delisting_candidate, payload) VALUES ($1, $2, $3, $4)`
+	return NewTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := executor(ctx, r.db).ExecContext(ctx, `DELETE FROM public.product_lifecycles`); err != nil {
+			return err
+		}
+		for _, lifecycle := range lifecycles {
+			payload, err := json.Marshal(lifecycle)
+			if err != nil {
+				return err
+			}
+			if _, err := executor(ctx, r.db).ExecContext(ctx, query, lifecycle.ProductID, lifecycle.Stage,
+				lifecycle.DelistingCandidate, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *ProductLifecycleRepository) GetLatestLifecycles(ctx context.Context) ([]entities.ProductLifecycle, error) {
+	query := `SELECT payload FROM public.product_lifecycles ORDER BY product_id`
+	return queryPayloads[entities.ProductLifecycle](ctx, executor(ctx, r.db), query)
+}
+
+func (r *ProductLifecycleRepository) GetLifecyclesByStage(ctx context.Context, stage entities.LifecycleStage) ([]entities.ProductLifecycle, error) {
+	query := `SELECT payload FROM public.product_lifecycles WHERE stage = $1 ORDER BY product_id`
+	return queryPayloads[entities.ProductLifecycle](ctx, executor(ctx, r.db), query, stage)
+}
+
+func (r *ProductLifecycleRepository) GetDelistingCandidates(ctx context.Context) ([]entities.ProductLifecycle, error) {
+	query := `SELECT payload FROM public.product_lifecycles WHERE delisting_candidate ORDER BY product_id`
+	return queryPayloads[entities.ProductLifecycle](ctx, executor(ctx, r.db), query)
+}
@@ -0,0 +1,88 @@
+// analitics-service/internal/infrastructure/postgres/product_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// ProductRepository хранит товары в таблице public.products (см. SourceSchema)
+type ProductRepository struct {
+	db *sql.DB
+}
+
+func NewProductRepository(db *sql.DB) repositories.ProductRepository {
+	return &ProductRepository{db: db}
+}
+
+const productColumns = `id, name, category, category_id, sub_category, price, cost, description, image_url, is_active,
+                  created_at, updated_at`
+
+func (r *ProductRepository) GetAllProducts(ctx context.Context) ([]entities.Product, error) {
+	query := `SELECT ` + productColumns + `
+              FROM public.products
+              ORDER BY id`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []entities.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
+
+// GetProductByID возвращает пустой товар, если товар не найден
+func (r *ProductRepository) GetProductByID(ctx context.Context, productID string) (entities.Product, error) {
+	query := `SELECT ` + productColumns + `
+              FROM public.products
+              WHERE id = $1`
+	product, err := scanProduct(executor(ctx, r.db).QueryRowContext(ctx, query, productID))
+	if err == sql.ErrNoRows {
+		return entities.Product{}, nil
+	}
+	return product, err
+}
+
+func (r *ProductRepository) CreateProduct(ctx context.Context, product entities.Product) error {
+	query := `INSERT INTO public.products (id, name, category, category_id, sub_category, price, cost, description, image_url, is_active)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, product.ID, product.Name, product.Category, product.CategoryID,
+		product.SubCategory, product.Price, product.Cost, product.Description, product.ImageURL, product.IsActive)
+	return err
+}
+
+func (r *ProductRepository) UpdateProduct(ctx context.Context, product entities.Product) error {
+	query := `UPDATE public.products
+              SET name = $1, category = $2, category_id = $3, sub_category = $4, price = $5, cost = $6,
+                  description = $7, image_url = $8, is_active = $9, updated_at = now()
+              WHERE id = $10`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, product.Name, product.Category, product.CategoryID, product.SubCategory,
+		product.Price, product.Cost, product.Description, product.ImageURL, product.IsActive, product.ID)
+	return err
+}
+
+func (r *ProductRepository) DeleteProduct(ctx context.Context, productID string) error {
+	query := `DELETE FROM public.products WHERE id = $1`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, productID)
+	return err
+}
+
+func scanProduct(row rowScanner) (entities.Product, error) {
+	var product entities.Product
+	err := row.Scan(&product.ID, &product.Name, &product.Category, &product.CategoryID, &product.SubCategory,
+		&product.Price, &product.Cost, &product.Description, &product.ImageURL, &product.IsActive,
+		&product.CreatedAt, &product.UpdatedAt)
+	return product, err
+}
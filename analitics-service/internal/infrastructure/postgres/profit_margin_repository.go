@@ -0,0 +1,74 @@
+// analitics-service/internal/infrastructure/postgres/profit_margin_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"analitics-service/internal/domain/repositories"
+)
+
+// ProfitMarginRepository хранит маржу товаров в процентах в таблице public.product_profit_margins (см. AnalyticsSchema)
+// Для товаров без сохраненной маржи она рассчитывается по цене и себестоимости из public.products
+type ProfitMarginRepository struct {
+	db *sql.DB
+}
+
+func NewProfitMarginRepository(db *sql.DB) repositories.ProfitMarginRepository {
+	return &ProfitMarginRepository{db: db}
+}
+
+// productMarginQuery возвращает товары с сохраненной или рассчитанной маржой
+const productMarginQuery = `SELECT p.id,
+                  COALESCE(m.margin, CASE WHEN p.price > 0 THEN (p.price - p.cost) / p.price * 100 ELSE 0 END)
+              FROM public.products p
+              LEFT JOIN public.product_profit_margins m ON m.product_id = p.id`
+
+func (r *ProfitMarginRepository) GetProfitMargins(ctx context.Context) (map[string]float64, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, productMarginQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	margins := make(map[string]float64)
+	for rows.Next() {
+		var productID string
+		var margin float64
+		if err := rows.Scan(&productID, &margin); err != nil {
+			return nil, err
+		}
+		margins[productID] = margin
+	}
+	return margins, rows.Err()
+}
+
+// GetProfitMarginByProductID возвращает 0, если товар не найден
+func (r *ProfitMarginRepository) GetProfitMarginByProductID(ctx context.Context, productID string) (float64, error) {
+	var id string
+	var margin float64
+	err := executor(ctx, r.db).QueryRowContext(ctx, productMarginQuery+` WHERE p.id = $1`, productID).Scan(&id, &margin)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return margin, err
+}
+
+func (r *ProfitMarginRepository) UpdateProfitMargin(ctx context.Context, productID string, margin float64) error {
+	query := `INSERT INTO public.product_profit_margins (product_id, margin, updated_at)
+              VALUES ($1, $2, now())
+              ON CONFLICT (product_id) DO UPDATE SET margin = EXCLUDED.margin, updated_at = EXCLUDED.updated_at`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, productID, margin)
+	return err
+}
+
+func (r *ProfitMarginRepository) UpdateProfitMargins(ctx context.Context, margins map[string]float64) error {
+	return NewTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		for productID, margin := range margins {
+			if err := r.UpdateProfitMargin(ctx, productID, margin); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
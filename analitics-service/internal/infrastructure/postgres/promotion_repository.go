@@ -0,0 +1,125 @@
+// analitics-service/internal/infrastructure/postgres/promotion_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// PromotionRepository хранит промо-календарь в таблице public.promotions (см. SourceSchema)
+// и оценки эффекта акций в таблице public.promotion_impacts (см. AnalyticsSchema)
+type PromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) repositories.PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+const promotionColumns = `id, name, description, product_ids, categories, mechanic, discount_value, buy_quantity, get_quantity,
+                  coupon_code, channels, start_date, end_date, created_at, updated_at`
+
+func (r *PromotionRepository) SavePromotion(ctx context.Context, promotion entities.Promotion) error {
+	query := `INSERT INTO public.promotions (id, name, description, product_ids, categories, mechanic, discount_value,
+                  buy_quantity, get_quantity, coupon_code, channels, start_date, end_date)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+              ON CONFLICT (id) DO UPDATE
+              SET name = EXCLUDED.name, description = EXCLUDED.description, product_ids = EXCLUDED.product_ids,
+                  categories = EXCLUDED.categories, mechanic = EXCLUDED.mechanic, discount_value = EXCLUDED.discount_value,
+                  buy_quantity = EXCLUDED.buy_quantity, get_quantity = EXCLUDED.get_quantity, coupon_code = EXCLUDED.coupon_code,
+                  channels = EXCLUDED.channels, start_date = EXCLUDED.start_date, end_date = EXCLUDED.end_date, updated_at = now()`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, promotion.ID, promotion.Name, promotion.Description,
+		pq.Array(nonNilStrings(promotion.ProductIDs)), pq.Array(nonNilStrings(promotion.Categories)), promotion.Mechanic,
+		promotion.DiscountValue, promotion.BuyQuantity, promotion.GetQuantity, promotion.CouponCode,
+		pq.Array(nonNilStrings(promotion.Channels)), promotion.StartDate, promotion.EndDate)
+	return err
+}
+
+// GetPromotionByID возвращает пустую акцию, если акция не найдена
+func (r *PromotionRepository) GetPromotionByID(ctx context.Context, promotionID string) (entities.Promotion, error) {
+	query := `SELECT ` + promotionColumns + `
+              FROM public.promotions
+              WHERE id = $1`
+	promotion, err := scanPromotion(executor(ctx, r.db).QueryRowContext(ctx, query, promotionID))
+	if err == sql.ErrNoRows {
+		return entities.Promotion{}, nil
+	}
+	return promotion, err
+}
+
+func (r *PromotionRepository) GetPromotionsByPeriod(ctx context.Context, startDate, endDate time.Time) ([]entities.Promotion, error) {
+	query := `SELECT ` + promotionColumns + `
+              FROM public.promotions
+              WHERE start_date < $2 AND end_date > $1
+              ORDER BY start_date, id`
+	return r.queryPromotions(ctx, query, startDate, endDate)
+}
+
+// GetPromotionsByProduct возвращает акции на сам товар и на его категорию
+func (r *PromotionRepository) GetPromotionsByProduct(ctx context.Context, productID string) ([]entities.Promotion, error) {
+	query := `SELECT ` + promotionColumns + `
+              FROM public.promotions
+              WHERE $1 = ANY(product_ids)
+                 OR EXISTS (SELECT 1 FROM public.products p WHERE p.id = $1 AND p.category = ANY(categories))
+              ORDER BY start_date, id`
+	return r.queryPromotions(ctx, query, productID)
+}
+
+func (r *PromotionRepository) DeletePromotion(ctx context.Context, promotionID string) error {
+	query := `DELETE FROM public.promotions WHERE id = $1`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, promotionID)
+	return err
+}
+
+func (r *PromotionRepository) SavePromotionImpact(ctx context.Context, impact entities.PromotionImpact) error {
+	payload, err := json.Marshal(impact)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.promotion_impacts (promotion_id, analysis_date, payload) VALUES ($1, $2, $3)`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, impact.PromotionID, impact.AnalysisDate, payload)
+	return err
+}
+
+func (r *PromotionRepository) queryPromotions(ctx context.Context, query string, args ...interface{}) ([]entities.Promotion, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []entities.Promotion
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions, rows.Err()
+}
+
+func scanPromotion(row rowScanner) (entities.Promotion, error) {
+	var promotion entities.Promotion
+	err := row.Scan(&promotion.ID, &promotion.Name, &promotion.Description, pq.Array(&promotion.ProductIDs),
+		pq.Array(&promotion.Categories), &promotion.Mechanic, &promotion.DiscountValue, &promotion.BuyQuantity,
+		&promotion.GetQuantity, &promotion.CouponCode, pq.Array(&promotion.Channels), &promotion.StartDate,
+		&promotion.EndDate, &promotion.CreatedAt, &promotion.UpdatedAt)
+	return promotion, err
+}
+
+// nonNilStrings заменяет nil пустым срезом: колонки-массивы объявлены NOT NULL
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
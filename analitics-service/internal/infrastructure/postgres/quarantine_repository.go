@@ -0,0 +1,59 @@
+// analitics-service/internal/infrastructure/postgres/quarantine_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// QuarantineRepository хранит записи, исключенные проверкой качества данных, в таблице public.quarantined_records
+// (см. AnalyticsSchema)
+// Запись выполняется в транзакции из контекста вместе с записью о запуске анализа
+type QuarantineRepository struct {
+	db *sql.DB
+}
+
+func NewQuarantineRepository(db *sql.DB) repositories.QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+func (r *QuarantineRepository) SaveQuarantined(ctx context.Context, records []entities.QuarantinedRecord) error {
+	query := `INSERT INTO public.quarantined_records (run_id, source, record_id, rule, reason, payload, quarantined_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, record := range records {
+		if _, err := executor(ctx, r.db).ExecContext(ctx, query, record.RunID, record.Source, record.RecordID, record.Rule,
+			record.Reason, []byte(record.Payload), record.QuarantinedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *QuarantineRepository) GetQuarantined(ctx context.Context, runID string) ([]entities.QuarantinedRecord, error) {
+	query := `SELECT run_id, source, record_id, rule, reason, payload, quarantined_at
+              FROM public.quarantined_records
+              WHERE run_id = $1
+              ORDER BY source, record_id, rule`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []entities.QuarantinedRecord
+	for rows.Next() {
+		var record entities.QuarantinedRecord
+		var payload []byte
+		if err := rows.Scan(&record.RunID, &record.Source, &record.RecordID, &record.Rule, &record.Reason, &payload,
+			&record.QuarantinedAt); err != nil {
+			return nil, err
+		}
+		record.Payload = payload
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,63 @@
+// analitics-service/internal/infrastructure/postgres/retention_metrics_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// RetentionMetricsRepository хранит метрики удержания в таблице public.retention_metrics (см. AnalyticsSchema)
+// Повторный расчет того же периода заменяет метрики
+type RetentionMetricsRepository struct {
+	db *sql.DB
+}
+
+func NewRetentionMetricsRepository(db *sql.DB) repositories.RetentionMetricsRepository {
+	return &RetentionMetricsRepository{db: db}
+}
+
+func (r *RetentionMetricsRepository) SaveMetrics(ctx context.Context, metrics entities.RetentionMetrics) error {
+	payload, err := json.Marshal(metrics)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.retention_metrics (period, period_start, period_end, payload)
+              VALUES ($1, $2, $3, $4)
+              ON CONFLICT (period, period_start) DO UPDATE SET period_end = EXCLUDED.period_end, payload = EXCLUDED.payload`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, metrics.Period, metrics.PeriodStart, metrics.PeriodEnd, payload)
+	return err
+}
+
+// GetMetricsByPeriod возвращает метрики периода, в который попадает date, или пустые метрики, если их нет
+func (r *RetentionMetricsRepository) GetMetricsByPeriod(ctx context.Context, period entities.TimeRange, date time.Time) (entities.RetentionMetrics, error) {
+	query := `SELECT payload
+              FROM public.retention_metrics
+              WHERE period = $1 AND period_start <= $2 AND period_end > $2
+              ORDER BY period_start DESC
+              LIMIT 1`
+	return queryPayload[entities.RetentionMetrics](ctx, executor(ctx, r.db), query, period, date)
+}
+
+func (r *RetentionMetricsRepository) GetMetricsHistory(ctx context.Context, period entities.TimeRange, startDate, endDate time.Time) ([]entities.RetentionMetrics, error) {
+	query := `SELECT payload
+              FROM public.retention_metrics
+              WHERE period = $1 AND period_start >= $2 AND period_start < $3
+              ORDER BY period_start`
+	return queryPayloads[entities.RetentionMetrics](ctx, executor(ctx, r.db), query, period, startDate, endDate)
+}
+
+// GetLatestMetrics возвращает пустые метрики, если расчетов еще не было
+func (r *RetentionMetricsRepository) GetLatestMetrics(ctx context.Context, period entities.TimeRange) (entities.RetentionMetrics, error) {
+	query := `SELECT payload
+              FROM public.retention_metrics
+              WHERE period = $1
+              ORDER BY period_start DESC
+              LIMIT 1`
+	return queryPayload[entities.RetentionMetrics](ctx, executor(ctx, r.db), query, period)
+}
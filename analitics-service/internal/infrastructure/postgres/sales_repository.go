@@ -0,0 +1,126 @@
+// analitics-service/internal/infrastructure/postgres/sales_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// SalesRepository хранит продажи в таблице public.sales (см. SourceSchema)
+// Периоды задаются полуинтервалом [startDate, endDate)
+type SalesRepository struct {
+	db *sql.DB
+}
+
+func NewSalesRepository(db *sql.DB) repositories.SalesRepository {
+	return &SalesRepository{db: db}
+}
+
+const saleColumns = `id, product_id, quantity, price, discount_rate, purchase_date, customer_id, transaction_id,
+                  created_at, updated_at`
+
+func (r *SalesRepository) GetSalesByPeriod(ctx context.Context, startDate, endDate time.Time) ([]entities.Sale, error) {
+	query := `SELECT ` + saleColumns + `
+              FROM public.sales
+              WHERE purchase_date >= $1 AND purchase_date < $2
+              ORDER BY purchase_date, id`
+	return r.querySales(ctx, query, startDate, endDate)
+}
+
+func (r *SalesRepository) GetSalesByProductID(ctx context.Context, productID string, startDate, endDate time.Time) ([]entities.Sale, error) {
+	query := `SELECT ` + saleColumns + `
+              FROM public.sales
+              WHERE product_id = $1 AND purchase_date >= $2 AND purchase_date < $3
+              ORDER BY purchase_date, id`
+	return r.querySales(ctx, query, productID, startDate, endDate)
+}
+
+func (r *SalesRepository) GetSalesByCustomerID(ctx context.Context, customerID string, startDate, endDate time.Time) ([]entities.Sale, error) {
+	query := `SELECT ` + saleColumns + `
+              FROM public.sales
+              WHERE customer_id = $1 AND purchase_date >= $2 AND purchase_date < $3
+              ORDER BY purchase_date, id`
+	return r.querySales(ctx, query, customerID, startDate, endDate)
+}
+
+func (r *SalesRepository) CreateSale(ctx context.Context, sale entities.Sale) error {
+	query := `INSERT INTO public.sales (id, product_id, quantity, price, discount_rate, purchase_date, customer_id, transaction_id)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, sale.ID, sale.ProductID, sale.Quantity, sale.Price, sale.DiscountRate,
+		sale.PurchaseDate, sale.CustomerID, sale.TransactionID)
+	return err
+}
+
+// GetSaleByID возвращает пустую продажу, если продажа не найдена
+func (r *SalesRepository) GetSaleByID(ctx context.Context, saleID string) (entities.Sale, error) {
+	query := `SELECT ` + saleColumns + `
+              FROM public.sales
+              WHERE id = $1`
+	sale, err := scanSale(executor(ctx, r.db).QueryRowContext(ctx, query, saleID))
+	if err == sql.ErrNoRows {
+		return entities.Sale{}, nil
+	}
+	return sale, err
+}
+
+// GetDailySalesData агрегирует продажи по дням: Sales — проданные единицы, TotalPrice — выручка после скидок,
+// AvgDiscount — средняя скидка, взвешенная по количеству
+func (r *SalesRepository) GetDailySalesData(ctx context.Context, startDate, endDate time.Time) ([]entities.DailyTransactionData, error) {
+	query := `SELECT date_trunc('day', purchase_date) AS day,
+                  SUM(quantity),
+                  SUM(price * quantity * (1 - discount_rate / 100)),
+                  COALESCE(SUM(discount_rate * quantity) / NULLIF(SUM(quantity), 0), 0),
+                  COUNT(DISTINCT transaction_id),
+                  COUNT(DISTINCT transaction_id) FILTER (WHERE discount_rate > 0),
+                  COUNT(DISTINCT product_id)
+              FROM public.sales
+              WHERE purchase_date >= $1 AND purchase_date < $2
+              GROUP BY day
+              ORDER BY day`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var daily []entities.DailyTransactionData
+	for rows.Next() {
+		var data entities.DailyTransactionData
+		if err := rows.Scan(&data.Date, &data.Sales, &data.TotalPrice, &data.AvgDiscount, &data.TotalTx,
+			&data.DiscountedTx, &data.ProductCount); err != nil {
+			return nil, err
+		}
+		daily = append(daily, data)
+	}
+	return daily, rows.Err()
+}
+
+func (r *SalesRepository) querySales(ctx context.Context, query string, args ...interface{}) ([]entities.Sale, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []entities.Sale
+	for rows.Next() {
+		sale, err := scanSale(rows)
+		if err != nil {
+			return nil, err
+		}
+		sales = append(sales, sale)
+	}
+	return sales, rows.Err()
+}
+
+func scanSale(row rowScanner) (entities.Sale, error) {
+	var sale entities.Sale
+	err := row.Scan(&sale.ID, &sale.ProductID, &sale.Quantity, &sale.Price, &sale.DiscountRate, &sale.PurchaseDate,
+		&sale.CustomerID, &sale.TransactionID, &sale.CreatedAt, &sale.UpdatedAt)
+	return sale, err
+}
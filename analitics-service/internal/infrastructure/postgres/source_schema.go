@@ -0,0 +1,90 @@
+// analitics-service/internal/infrastructure/postgres/source_schema.go
+package postgres
+
+// SourceSchema описывает таблицы исходных данных аналитического сервиса: товары с историей себестоимости,
+// акции, чеки с позициями и продажи
+// Колонки совпадают с JSON-полями сущностей Product, ProductCost, Promotion, Transaction, Item и Sale.
+// Все операторы идемпотентны, схему можно применять перед каждой загрузкой
+const SourceSchema = `
+CREATE TABLE IF NOT EXISTS public.products (
+	id           TEXT PRIMARY KEY,
+	name         TEXT NOT NULL,
+	category     TEXT NOT NULL,
+	category_id  TEXT NOT NULL,
+	sub_category TEXT NOT NULL DEFAULT '',
+	price        NUMERIC(12, 2) NOT NULL,
+	cost         NUMERIC(12, 2) NOT NULL,
+	description  TEXT NOT NULL DEFAULT '',
+	image_url    TEXT NOT NULL DEFAULT '',
+	is_active    BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS public.product_costs (
+	product_id     TEXT NOT NULL,
+	effective_from TIMESTAMPTZ NOT NULL,
+	cost           NUMERIC(12, 2) NOT NULL,
+	price          NUMERIC(12, 2) NOT NULL DEFAULT 0,
+	PRIMARY KEY (product_id, effective_from)
+);
+
+CREATE TABLE IF NOT EXISTS public.promotions (
+	id             TEXT PRIMARY KEY,
+	name           TEXT NOT NULL,
+	description    TEXT NOT NULL DEFAULT '',
+	product_ids    TEXT[] NOT NULL DEFAULT '{}',
+	categories     TEXT[] NOT NULL DEFAULT '{}',
+	mechanic       TEXT NOT NULL,
+	discount_value NUMERIC(12, 2) NOT NULL,
+	buy_quantity   INTEGER NOT NULL DEFAULT 0,
+	get_quantity   INTEGER NOT NULL DEFAULT 0,
+	coupon_code    TEXT NOT NULL DEFAULT '',
+	channels       TEXT[] NOT NULL DEFAULT '{}',
+	start_date     TIMESTAMPTZ NOT NULL,
+	end_date       TIMESTAMPTZ NOT NULL,
+	created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS public.transactions (
+	id            TEXT PRIMARY KEY,
+	customer_id   TEXT NOT NULL,
+	date          TIMESTAMPTZ NOT NULL,
+	total_amount  NUMERIC(12, 2) NOT NULL,
+	discount_used BOOLEAN NOT NULL DEFAULT FALSE,
+	coupon_code   TEXT NOT NULL DEFAULT '',
+	created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_transactions_date ON public.transactions (date);
+CREATE INDEX IF NOT EXISTS idx_transactions_customer_id ON public.transactions (customer_id, date);
+
+CREATE TABLE IF NOT EXISTS public.transaction_items (
+	transaction_id TEXT NOT NULL REFERENCES public.transactions (id) ON DELETE CASCADE,
+	product_id     TEXT NOT NULL,
+	name           TEXT NOT NULL,
+	category_id    TEXT NOT NULL,
+	category       TEXT NOT NULL,
+	price          NUMERIC(12, 2) NOT NULL,
+	quantity       INTEGER NOT NULL,
+	discount_pct   NUMERIC(5, 2) NOT NULL DEFAULT 0,
+	PRIMARY KEY (transaction_id, product_id)
+);
+CREATE INDEX IF NOT EXISTS idx_transaction_items_product_id ON public.transaction_items (product_id);
+
+CREATE TABLE IF NOT EXISTS public.sales (
+	id             TEXT PRIMARY KEY,
+	product_id     TEXT NOT NULL,
+	quantity       INTEGER NOT NULL,
+	price          NUMERIC(12, 2) NOT NULL,
+	discount_rate  NUMERIC(5, 2) NOT NULL DEFAULT 0,
+	purchase_date  TIMESTAMPTZ NOT NULL,
+	customer_id    TEXT NOT NULL,
+	transaction_id TEXT NOT NULL,
+	created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_sales_purchase_date ON public.sales (purchase_date);
+CREATE INDEX IF NOT EXISTS idx_sales_product_id ON public.sales (product_id, purchase_date);
+`
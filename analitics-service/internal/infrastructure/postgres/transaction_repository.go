@@ -0,0 +1,120 @@
+// analitics-service/internal/infrastructure/postgres/transaction_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// TransactionRepository хранит чеки в таблицах public.transactions и public.transaction_items (см. SourceSchema)
+// Периоды задаются полуинтервалом [startDate, endDate)
+type TransactionRepository struct {
+	db *sql.DB
+}
+
+func NewTransactionRepository(db *sql.DB) repositories.TransactionRepository {
+	return &TransactionRepository{db: db}
+}
+
+func (r *TransactionRepository) GetTransactionsByPeriod(ctx context.Context, startDate, endDate time.Time) ([]entities.Transaction, error) {
+	return r.queryTransactions(ctx, `t.date >= $1 AND t.date < $2`, startDate, endDate)
+}
+
+// GetTransactionByID возвращает пустую транзакцию, если транзакция не найдена
+func (r *TransactionRepository) GetTransactionByID(ctx context.Context, transactionID string) (entities.Transaction, error) {
+	transactions, err := r.queryTransactions(ctx, `t.id = $1`, transactionID)
+	if err != nil || len(transactions) == 0 {
+		return entities.Transaction{}, err
+	}
+	return transactions[0], nil
+}
+
+func (r *TransactionRepository) GetTransactionsByCustomerID(ctx context.Context, customerID string, startDate, endDate time.Time) ([]entities.Transaction, error) {
+	return r.queryTransactions(ctx, `t.customer_id = $1 AND t.date >= $2 AND t.date < $3`, customerID, startDate, endDate)
+}
+
+// CreateTransaction сохраняет чек вместе с позициями
+// Запись выполняется в транзакции из контекста или в собственной, чтобы чек не сохранился без позиций
+func (r *TransactionRepository) CreateTransaction(ctx context.Context, transaction entities.Transaction) error {
+	return NewTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		query := `INSERT INTO public.transactions (id, customer_id, date, total_amount, discount_used, coupon_code)
+                  VALUES ($1, $2, $3, $4, $5, $6)`
+		if _, err := executor(ctx, r.db).ExecContext(ctx, query, transaction.ID, transaction.CustomerID, transaction.Date,
+			transaction.TotalAmount, transaction.DiscountUsed, transaction.CouponCode); err != nil {
+			return err
+		}
+
+		itemQuery := `INSERT INTO public.transaction_items (transaction_id, product_id, name, category_id, category, price, quantity, discount_pct)
+                      VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		for _, item := range transaction.Items {
+			if _, err := executor(ctx, r.db).ExecContext(ctx, itemQuery, transaction.ID, item.ProductID, item.Name, item.CategoryID,
+				item.Category, item.Price, item.Quantity, item.DiscountPct); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *TransactionRepository) GetTransactionsWithProduct(ctx context.Context, productID string, startDate, endDate time.Time) ([]entities.Transaction, error) {
+	where := `t.date >= $2 AND t.date < $3
+                AND EXISTS (SELECT 1 FROM public.transaction_items p WHERE p.transaction_id = t.id AND p.product_id = $1)`
+	return r.queryTransactions(ctx, where, productID, startDate, endDate)
+}
+
+func (r *TransactionRepository) GetTransactionCount(ctx context.Context, startDate, endDate time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM public.transactions WHERE date >= $1 AND date < $2`
+	var count int
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, startDate, endDate).Scan(&count)
+	return count, err
+}
+
+// queryTransactions читает чеки, отобранные условием where по таблице t, вместе с позициями одним запросом
+// Строки отсортированы по чеку, поэтому позиции одного чека идут подряд
+func (r *TransactionRepository) queryTransactions(ctx context.Context, where string, args ...interface{}) ([]entities.Transaction, error) {
+	query := `SELECT t.id, t.customer_id, t.date, t.total_amount, t.discount_used, t.coupon_code, t.created_at, t.updated_at,
+                  i.product_id, i.name, i.category_id, i.category, i.price, i.quantity, i.discount_pct
+              FROM public.transactions t
+              LEFT JOIN public.transaction_items i ON i.transaction_id = t.id
+              WHERE ` + where + `
+              ORDER BY t.date, t.id, i.product_id`
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []entities.Transaction
+	for rows.Next() {
+		var tx entities.Transaction
+		var productID, name, categoryID, category sql.NullString
+		var price, discountPct sql.NullFloat64
+		var quantity sql.NullInt64
+		if err := rows.Scan(&tx.ID, &tx.CustomerID, &tx.Date, &tx.TotalAmount, &tx.DiscountUsed, &tx.CouponCode,
+			&tx.CreatedAt, &tx.UpdatedAt, &productID, &name, &categoryID, &category, &price, &quantity, &discountPct); err != nil {
+			return nil, err
+		}
+
+		if n := len(transactions); n == 0 || transactions[n-1].ID != tx.ID {
+			transactions = append(transactions, tx)
+		}
+		if productID.Valid {
+			last := &transactions[len(transactions)-1]
+			last.Items = append(last.Items, entities.Item{
+				ProductID:   productID.String,
+				Name:        name.String,
+				CategoryID:  categoryID.String,
+				Category:    category.String,
+				Price:       price.Float64,
+				Quantity:    int(quantity.Int64),
+				DiscountPct: discountPct.Float64,
+			})
+		}
+	}
+	return transactions, rows.Err()
+}
@@ -0,0 +1,67 @@
+// analitics-service/internal/infrastructure/postgres/transactor.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"analitics-service/internal/domain/repositories"
+)
+
+// txKey ключ контекста, под которым хранится открытая транзакция
+type txKey struct{}
+
+// dbExecutor общий интерфейс sql.DB и sql.Tx для выполнения запросов
+type dbExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// executor возвращает транзакцию из контекста или db, если транзакция не открыта
+// Репозитории, которые должны участвовать в транзакциях Transactor, выполняют запросы через него
+func executor(ctx context.Context, db *sql.DB) dbExecutor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// Transactor открывает транзакции PostgreSQL и передает их репозиториям через контекст
+type Transactor struct {
+	db *sql.DB
+}
+
+func NewTransactor(db *sql.DB) repositories.Transactor {
+	return &Transactor{db: db}
+}
+
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// analitics-service/internal/infrastructure/postgres/uplift_model_repository.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+)
+
+// UpliftModelRepository хранит uplift-модели в таблице public.uplift_models и целевые списки
+// в таблице public.uplift_targets (см. AnalyticsSchema)
+type UpliftModelRepository struct {
+	db *sql.DB
+}
+
+func NewUpliftModelRepository(db *sql.DB) repositories.UpliftModelRepository {
+	return &UpliftModelRepository{db: db}
+}
+
+// SaveModel сохраняет модель; повторное обучение с тем же ID заменяет модель
+func (r *UpliftModelRepository) SaveModel(ctx context.Context, model entities.UpliftModel) error {
+	payload, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO public.uplift_models (id, method, trained_at, payload)
+              VALUES ($1, $2, $3, $4)
+              ON CONFLICT (id) DO UPDATE
+              SET method = EXCLUDED.method, trained_at = EXCLUDED.trained_at, payload = EXCLUDED.payload`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, model.ID, string(model.Method), model.TrainedAt, payload)
+	return err
+}
+
+// GetModelByID возвращает пустую модель, если модель не найдена
+func (r *UpliftModelRepository) GetModelByID(ctx context.Context, modelID string) (entities.UpliftModel, error) {
+	query := `SELECT payload FROM public.uplift_models WHERE id = $1`
+	return queryPayload[entities.UpliftModel](ctx, executor(ctx, r.db), query, modelID)
+}
+
+// GetLatestModel возвращает пустую модель, если ни одна модель еще не обучена
+func (r *UpliftModelRepository) GetLatestModel(ctx context.Context) (entities.UpliftModel, error) {
+	query := `SELECT payload FROM public.uplift_models ORDER BY trained_at DESC, id LIMIT 1`
+	return queryPayload[entities.UpliftModel](ctx, executor(ctx, r.db), query)
+}
+
+// SaveTargetList заменяет целевой список модели новым
+func (r *UpliftModelRepository) SaveTargetList(ctx context.Context, modelID string, scores []entities.UpliftScore) error {
+	deleteQuery := `DELETE FROM public.uplift_targets WHERE model_id = $1`
+	insertQuery := `INSERT INTO public.uplift_targets (model_id, rank, customer_id, uplift, payload)
+                    VALUES ($1, $2, $3, $4, $5)`
+	return NewTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := executor(ctx, r.db).ExecContext(ctx, deleteQuery, modelID); err != nil {
+			return err
+		}
+		for _, score := range scores {
+			payload, err := json.Marshal(score)
+			if err != nil {
+				return err
+			}
+			if _, err := executor(ctx, r.db).ExecContext(ctx, insertQuery, modelID, score.Rank, score.CustomerID,
+				score.Uplift, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
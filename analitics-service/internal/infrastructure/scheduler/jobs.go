@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+)
+
+// Имена встроенных аналитических задач
+const (
+	ABCAnalysisJobName             = "abc_analysis"
+	AprioriJobName                 = "apriori"
+	RetentionJobName               = "retention"
+	DiscountRecommendationsJobName = "discount_recommendations"
+)
+
+// abcAnalysisJob пересчитывает ABC-сегментацию товаров как воспроизводимый запуск анализа
+// Параметры: lookback_days, a_threshold и b_threshold в процентах, revenue_weight, quantity_weight, profit_weight
+type abcAnalysisJob struct {
+	service services.AnalysisRunService
+}
+
+// NewABCAnalysisJob создает задачу ABC-анализа
+func NewABCAnalysisJob(service services.AnalysisRunService) Job {
+	return &abcAnalysisJob{service: service}
+}
+
+func (j *abcAnalysisJob) Name() string {
+	return ABCAnalysisJobName
+}
+
+func (j *abcAnalysisJob) Run(ctx context.Context, params Params) error {
+	lookback, err := params.Lookback(90)
+	if err != nil {
+		return err
+	}
+
+	thresholds := entities.Thresholds{}
+	if thresholds.AThreshold, err = params.Float("a_threshold", 80); err != nil {
+		return err
+	}
+	if thresholds.BThreshold, err = params.Float("b_threshold", 95); err != nil {
+		return err
+	}
+
+	weights := entities.CriteriaWeights{}
+	if weights.RevenueWeight, err = params.Float("revenue_weight", 0.5); err != nil {
+		return err
+	}
+	if weights.QuantityWeight, err = params.Float("quantity_weight", 0.2); err != nil {
+		return err
+	}
+	if weights.ProfitWeight, err = params.Float("profit_weight", 0.3); err != nil {
+		return err
+	}
+
+	endDate := time.Now()
+	criteria := entities.ABCAnalysisCriteria{
+		StartDate:          endDate.Add(-lookback),
+		EndDate:            endDate,
+		ThresholdsRevenue:  thresholds,
+		ThresholdsQuantity: thresholds,
+		ThresholdsProfit:   thresholds,
+		Weights:            weights,
+	}
+
+	if _, err := j.service.RunABCAnalysis(ctx, criteria); err != nil {
+		return fmt.Errorf("failed to run ABC analysis: %w", err)
+	}
+	return nil
+}
+
+// aprioriJob ищет ассоциативные правила по транзакциям периода как воспроизводимый запуск анализа
+// Параметры: lookback_days, min_support, min_confidence
+type aprioriJob struct {
+	service services.AnalysisRunService
+}
+
+// NewAprioriJob создает задачу поиска ассоциативных правил
+func NewAprioriJob(service services.AnalysisRunService) Job {
+	return &aprioriJob{service: service}
+}
+
+func (j *aprioriJob) Name() string {
+	return AprioriJobName
+}
+
+func (j *aprioriJob) Run(ctx context.Context, params Params) error {
+	lookback, err := params.Lookback(30)
+	if err != nil {
+		return err
+	}
+
+	endDate := time.Now()
+	ruleParams := entities.AssociationRuleParams{
+		StartDate: endDate.Add(-lookback),
+		EndDate:   endDate,
+	}
+	if ruleParams.MinSupport, err = params.Float("min_support", 0.01); err != nil {
+		return err
+	}
+	if ruleParams.MinConfidence, err = params.Float("min_confidence", 0.5); err != nil {
+		return err
+	}
+
+	if _, err := j.service.RunAssociationRules(ctx, ruleParams); err != nil {
+		return fmt.Errorf("failed to run association rules analysis: %w", err)
+	}
+	return nil
+}
+
+// retentionJob пересчитывает метрики удержания клиентов
+// Параметры: period (daily, weekly, monthly), lookback_days
+type retentionJob struct {
+	service services.RetentionService
+}
+
+// NewRetentionJob создает задачу расчета метрик удержания
+func NewRetentionJob(service services.RetentionService) Job {
+	return &retentionJob{service: service}
+}
+
+func (j *retentionJob) Name() string {
+	return RetentionJobName
+}
+
+func (j *retentionJob) Run(ctx context.Context, params Params) error {
+	period := entities.TimeRange(params.String("period", string(entities.Weekly)))
+	if !period.IsValid() {
+		return fmt.Errorf("parameter period must be daily, weekly or monthly, got %q", period)
+	}
+	lookback, err := params.Lookback(182)
+	if err != nil {
+		return err
+	}
+
+	endDate := time.Now()
+	if _, err := j.service.ComputeMetrics(ctx, period, endDate.Add(-lookback), endDate); err != nil {
+		return fmt.Errorf("failed to compute retention metrics: %w", err)
+	}
+	return nil
+}
+
+// discountRecommendationsJob генерирует и сохраняет рекомендации по скидкам как воспроизводимый запуск анализа
+// Параметры: lookback_days
+type discountRecommendationsJob struct {
+	service services.AnalysisRunService
+}
+
+// NewDiscountRecommendationsJob создает задачу генерации рекомендаций по скидкам
+func NewDiscountRecommendationsJob(service services.AnalysisRunService) Job {
+	return &discountRecommendationsJob{service: service}
+}
+
+func (j *discountRecommendationsJob) Name() string {
+	return DiscountRecommendationsJobName
+}
+
+func (j *discountRecommendationsJob) Run(ctx context.Context, params Params) error {
+	lookback, err := params.Lookback(90)
+	if err != nil {
+		return err
+	}
+
+	endDate := time.Now()
+	recommendationParams := entities.DiscountRecommendationParams{
+		StartDate: endDate.Add(-lookback),
+		EndDate:   endDate,
+	}
+	if _, err := j.service.RunDiscountRecommendations(ctx, recommendationParams); err != nil {
+		return fmt.Errorf("failed to run discount recommendations: %w", err)
+	}
+	return nil
+}
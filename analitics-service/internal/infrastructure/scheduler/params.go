@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Params параметры запуска задачи из конфигурации или ручного запуска
+type Params map[string]string
+
+// Merge возвращает копию параметров, дополненную переопределениями
+func (p Params) Merge(overrides Params) Params {
+	merged := make(Params, len(p)+len(overrides))
+	for key, value := range p {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
+
+// String возвращает строковый параметр или значение по умолчанию
+func (p Params) String(key, fallback string) string {
+	if value, ok := p[key]; ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Int возвращает целочисленный параметр или значение по умолчанию
+func (p Params) Int(key string, fallback int) (int, error) {
+	value, ok := p[key]
+	if !ok || value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s must be an integer, got %q", key, value)
+	}
+	return parsed, nil
+}
+
+// Float возвращает вещественный параметр или значение по умолчанию
+func (p Params) Float(key string, fallback float64) (float64, error) {
+	value, ok := p[key]
+	if !ok || value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s must be a number, got %q", key, value)
+	}
+	return parsed, nil
+}
+
+// Lookback возвращает период анализа из параметра lookback_days
+func (p Params) Lookback(fallbackDays int) (time.Duration, error) {
+	days, err := p.Int("lookback_days", fallbackDays)
+	if err != nil {
+		return 0, err
+	}
+	if days <= 0 {
+		return 0, fmt.Errorf("parameter lookback_days must be positive, got %d", days)
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
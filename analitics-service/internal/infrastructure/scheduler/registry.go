@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Job описывает аналитическую задачу, которую может запускать планировщик
+type Job interface {
+	// Name возвращает уникальное имя задачи, по которому она настраивается в конфигурации
+	Name() string
+
+	// Run выполняет задачу с указанными параметрами
+	Run(ctx context.Context, params Params) error
+}
+
+// Registry содержит задачи, доступные для запуска
+type Registry struct {
+	jobs map[string]Job
+}
+
+// NewRegistry создает пустой реестр задач
+func NewRegistry() *Registry {
+	return &Registry{jobs: make(map[string]Job)}
+}
+
+// Register добавляет задачу в реестр
+func (r *Registry) Register(job Job) error {
+	if _, exists := r.jobs[job.Name()]; exists {
+		return fmt.Errorf("job %s is already registered", job.Name())
+	}
+	r.jobs[job.Name()] = job
+	return nil
+}
+
+// Get возвращает задачу по имени
+func (r *Registry) Get(name string) (Job, bool) {
+	job, ok := r.jobs[name]
+	return job, ok
+}
+
+// Names возвращает отсортированные имена зарегистрированных задач
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.jobs))
+	for name := range r.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,368 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/cron"
+	"analitics-service/pkg/logger"
+)
+
+// Ошибки планировщика задач
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobLocked   = errors.New("job is already running")
+	ErrStopped     = errors.New("scheduler is stopping")
+)
+
+// slotRetention срок хранения записей о закрепленных слотах: повторный запуск того же слота возможен,
+// только пока часы реплик расходятся или таймер запаздывает, поэтому старые слоты можно удалять
+const slotRetention = 7 * 24 * time.Hour
+
+// JobSchedule описывает расписание и параметры зарегистрированной задачи
+type JobSchedule struct {
+	Name     string
+	Spec     string // Cron-выражение; пустое значение — только ручной запуск
+	Enabled  bool
+	Timeout  time.Duration
+	Params   Params
+	schedule *cron.Schedule
+}
+
+// Scheduler запускает зарегистрированные задачи по расписанию и вручную
+// Перед запуском задача захватывает распределенную блокировку, а запуск по расписанию — еще и слот своего времени,
+// поэтому каждый запуск среди реплик выполняет только одна
+type Scheduler struct {
+	registry   *Registry
+	schedules  map[string]*JobSchedule
+	lockRepo   repositories.JobLockRepository
+	runRepo    repositories.JobRunRepository
+	instanceID string
+	lockTTL    time.Duration
+	logger     logger.Logger
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+	stopping   bool // Выставляется при остановке: после этого wg.Add запрещен, иначе он гонится с wg.Wait
+}
+
+// NewScheduler создает планировщик и проверяет расписания задач
+// Задачи реестра без расписания в конфигурации доступны только для ручного запуска
+func NewScheduler(
+	registry *Registry,
+	schedules []JobSchedule,
+	lockRepo repositories.JobLockRepository,
+	runRepo repositories.JobRunRepository,
+	instanceID string,
+	lockTTL time.Duration,
+	logger logger.Logger,
+) (*Scheduler, error) {
+	if instanceID == "" {
+		return nil, errors.New("instance id is required")
+	}
+	if lockTTL <= 0 {
+		return nil, errors.New("lock ttl must be positive")
+	}
+
+	s := &Scheduler{
+		registry:   registry,
+		schedules:  make(map[string]*JobSchedule),
+		lockRepo:   lockRepo,
+		runRepo:    runRepo,
+		instanceID: instanceID,
+		lockTTL:    lockTTL,
+		logger:     logger,
+	}
+
+	for _, schedule := range schedules {
+		if _, ok := registry.Get(schedule.Name); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrJobNotFound, schedule.Name)
+		}
+		if _, exists := s.schedules[schedule.Name]; exists {
+			return nil, fmt.Errorf("duplicate schedule for job %s", schedule.Name)
+		}
+
+		// Блокировка не должна истечь, пока задача еще выполняется
+		if schedule.Timeout <= 0 {
+			schedule.Timeout = lockTTL
+		}
+		if schedule.Timeout > lockTTL {
+			return nil, fmt.Errorf("timeout of job %s exceeds lock ttl %s", schedule.Name, lockTTL)
+		}
+
+		if schedule.Spec != "" {
+			parsed, err := cron.Parse(schedule.Spec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schedule of job %s: %w", schedule.Name, err)
+			}
+			if parsed.Next(time.Now()).IsZero() {
+				return nil, fmt.Errorf("schedule of job %s never fires", schedule.Name)
+			}
+			schedule.schedule = parsed
+		}
+
+		schedule := schedule
+		s.schedules[schedule.Name] = &schedule
+	}
+
+	for _, name := range registry.Names() {
+		if _, ok := s.schedules[name]; !ok {
+			s.schedules[name] = &JobSchedule{Name: name, Enabled: true, Timeout: lockTTL}
+		}
+	}
+
+	return s, nil
+}
+
+// Start запускает задачи по расписанию до отмены контекста и дожидается завершения выполняющихся задач
+func (s *Scheduler) Start(ctx context.Context) {
+	next := make(map[string]time.Time)
+	for name, schedule := range s.schedules {
+		if schedule.Enabled && schedule.schedule != nil {
+			next[name] = schedule.schedule.Next(time.Now())
+		}
+	}
+	s.logger.Info(ctx, "Планировщик задач запущен", "instance", s.instanceID, "scheduled", len(next))
+
+	for {
+		var earliest time.Time
+		for _, at := range next {
+			if earliest.IsZero() || at.Before(earliest) {
+				earliest = at
+			}
+		}
+
+		// Без задач по расписанию ожидаем только остановки
+		var wake <-chan time.Time
+		var timer *time.Timer
+		if !earliest.IsZero() {
+			timer = time.NewTimer(time.Until(earliest))
+			wake = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			s.mu.Lock()
+			s.stopping = true
+			s.mu.Unlock()
+			s.wg.Wait()
+			s.logger.Info(context.Background(), "Планировщик задач остановлен", "instance", s.instanceID)
+			return
+		case now := <-wake:
+			s.pruneSlots(ctx, now)
+			for name, at := range next {
+				if at.After(now) {
+					continue
+				}
+				next[name] = s.schedules[name].schedule.Next(now)
+
+				s.wg.Add(1)
+				go func(name string, scheduledAt time.Time) {
+					defer s.wg.Done()
+					if _, err := s.execute(ctx, name, nil, entities.JobTriggerSchedule, scheduledAt, nil); err != nil {
+						if errors.Is(err, ErrJobLocked) {
+							s.logger.Info(ctx, "Задача пропущена, её выполняет другая реплика", "job", name)
+							return
+						}
+						s.logger.Error(ctx, "Не удалось запустить задачу", "job", name, "error", err)
+					}
+				}(name, at)
+			}
+		}
+	}
+}
+
+// RunNow запускает задачу вручную с переопределенными параметрами и возвращает созданный запуск
+// Задача выполняется в фоне; результат доступен в истории запусков
+func (s *Scheduler) RunNow(ctx context.Context, name string, overrides Params) (*entities.JobRun, error) {
+	if _, ok := s.registry.Get(name); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+
+	// Выполнение не должно прерываться по завершении HTTP-запроса
+	runCtx := context.WithoutCancel(ctx)
+	started := make(chan error, 1)
+	var run entities.JobRun
+
+	s.mu.Lock()
+	if s.stopping {
+		s.mu.Unlock()
+		return nil, ErrStopped
+	}
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		_, err := s.execute(runCtx, name, overrides, entities.JobTriggerManual, time.Time{}, func(created entities.JobRun) {
+			run = created
+			started <- nil
+		})
+		if err != nil {
+			started <- err
+		}
+	}()
+
+	if err := <-started; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// Jobs возвращает описание зарегистрированных задач и время их следующего запуска
+func (s *Scheduler) Jobs() []entities.JobInfo {
+	now := time.Now()
+	jobs := make([]entities.JobInfo, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		info := entities.JobInfo{
+			Name:       schedule.Name,
+			Schedule:   schedule.Spec,
+			Enabled:    schedule.Enabled,
+			Timeout:    schedule.Timeout.String(),
+			Parameters: schedule.Params,
+		}
+		if schedule.Enabled && schedule.schedule != nil {
+			nextRun := schedule.schedule.Next(now)
+			info.NextRunAt = &nextRun
+		}
+		jobs = append(jobs, info)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// GetRuns возвращает историю запусков задачи; пустое имя — всех задач
+func (s *Scheduler) GetRuns(ctx context.Context, name string, limit int) ([]entities.JobRun, error) {
+	if name != "" {
+		if _, ok := s.registry.Get(name); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+		}
+	}
+
+	runs, err := s.runRepo.GetRuns(ctx, name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetRun возвращает запуск задачи по ID или nil, если запуск не найден
+func (s *Scheduler) GetRun(ctx context.Context, runID string) (*entities.JobRun, error) {
+	run, err := s.runRepo.GetRunByID(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job run: %w", err)
+	}
+	return run, nil
+}
+
+// pruneSlots удаляет записи о слотах старше slotRetention; ошибка удаления не мешает запуску задач
+func (s *Scheduler) pruneSlots(ctx context.Context, now time.Time) {
+	deleted, err := s.lockRepo.DeleteSlotsBefore(ctx, now.Add(-slotRetention))
+	if err != nil {
+		s.logger.Error(ctx, "Не удалось удалить старые слоты задач", "error", err)
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info(ctx, "Удалены старые слоты задач", "deleted", deleted)
+	}
+}
+
+// execute захватывает блокировку, записывает запуск в историю и выполняет задачу с таймаутом
+// Запуск по расписанию сначала закрепляет слот scheduledAt: аренда блокировки освобождается после выполнения,
+// и без слота реплика с отстающими часами или запоздавшим таймером выполнила бы тот же запуск повторно
+// onStart, если задан, вызывается после записи запуска, до начала выполнения задачи
+func (s *Scheduler) execute(
+	ctx context.Context,
+	name string,
+	overrides Params,
+	trigger entities.JobTrigger,
+	scheduledAt time.Time,
+	onStart func(entities.JobRun),
+) (*entities.JobRun, error) {
+	job, _ := s.registry.Get(name)
+	schedule := s.schedules[name]
+	params := schedule.Params.Merge(overrides)
+
+	if !scheduledAt.IsZero() {
+		claimed, err := s.lockRepo.TryClaimSlot(ctx, name, scheduledAt, s.instanceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim job slot: %w", err)
+		}
+		if !claimed {
+			return nil, fmt.Errorf("%w: %s at %s", ErrJobLocked, name, scheduledAt.Format(time.RFC3339))
+		}
+	}
+
+	acquired, err := s.lockRepo.TryAcquireLock(ctx, name, s.instanceID, s.lockTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire job lock: %w", err)
+	}
+	if !acquired {
+		return nil, fmt.Errorf("%w: %s", ErrJobLocked, name)
+	}
+	// Блокировку и историю обновляем даже после отмены контекста, иначе задача останется заблокированной до истечения аренды
+	defer func() {
+		if err := s.lockRepo.ReleaseLock(context.WithoutCancel(ctx), name, s.instanceID); err != nil {
+			s.logger.Error(ctx, "Не удалось освободить блокировку задачи", "job", name, "error", err)
+		}
+	}()
+
+	startedAt := time.Now()
+	run := entities.JobRun{
+		ID:         fmt.Sprintf("%s-%d", name, startedAt.UnixNano()),
+		JobName:    name,
+		Status:     entities.JobRunning,
+		Trigger:    trigger,
+		Instance:   s.instanceID,
+		Parameters: params,
+		StartedAt:  startedAt,
+	}
+	if err := s.runRepo.CreateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to create job run: %w", err)
+	}
+	if onStart != nil {
+		onStart(run)
+	}
+
+	s.logger.Info(ctx, "Задача запущена", "job", name, "run", run.ID, "trigger", trigger)
+
+	runCtx, cancel := context.WithTimeout(ctx, schedule.Timeout)
+	jobErr := runJob(runCtx, job, params)
+	cancel()
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(startedAt).Milliseconds()
+	run.Status = entities.JobSucceeded
+	if jobErr != nil {
+		run.Status = entities.JobFailed
+		run.Error = jobErr.Error()
+		s.logger.Error(ctx, "Задача завершилась с ошибкой", "job", name, "run", run.ID, "error", jobErr)
+	} else {
+		s.logger.Info(ctx, "Задача выполнена", "job", name, "run", run.ID, "duration_ms", run.DurationMs)
+	}
+
+	if err := s.runRepo.UpdateRun(context.WithoutCancel(ctx), run); err != nil {
+		return &run, fmt.Errorf("failed to update job run: %w", err)
+	}
+	return &run, nil
+}
+
+// runJob выполняет задачу, превращая панику в ошибку запуска
+func runJob(ctx context.Context, job Job, params Params) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return job.Run(ctx, params)
+}
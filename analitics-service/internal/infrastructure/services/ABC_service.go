@@ -13,6 +13,10 @@ type ABCAnalysisService interface {
 	// PerformABCAnalysis выполняет ABC-анализ товаров на основе переданных критериев
 	PerformABCAnalysis(ctx context.Context, criteria entities.ABCAnalysisCriteria) (*entities.ABCAnalysisResult, error)
 
+	// AnalyzeSales выполняет ABC-анализ по переданным товарам, продажам, марже и истории себестоимости,
+	// например очищенным проверкой качества данных
+	AnalyzeSales(ctx context.Context, criteria entities.ABCAnalysisCriteria, products []entities.Product, sales []entities.Sale, profitMargins map[string]float64, costs []entities.ProductCost) (*entities.ABCAnalysisResult, error)
+
 	// GetProductSegmentation возвращает сегментацию продуктов по категориям A, B, C
 	GetProductSegmentation(ctx context.Context, productID string) (*entities.ProductSegmentation, error)
 
@@ -26,6 +30,7 @@ type ABCAnalysisServiceImpl struct {
 	salesRepo        repositories.SalesRepository
 	abcSegmentRepo   repositories.ABCSegmentRepository
 	profitMarginRepo repositories.ProfitMarginRepository
+	costRepo         repositories.ProductCostRepository
 }
 
 // NewABCAnalysisService создает новый экземпляр сервиса ABC-анализа
@@ -34,12 +39,14 @@ func NewABCAnalysisService(
 	salesRepo repositories.SalesRepository,
 	abcSegmentRepo repositories.ABCSegmentRepository,
 	profitMarginRepo repositories.ProfitMarginRepository,
+	costRepo repositories.ProductCostRepository,
 ) ABCAnalysisService {
 	return &ABCAnalysisServiceImpl{
 		productRepo:      productRepo,
 		salesRepo:        salesRepo,
 		abcSegmentRepo:   abcSegmentRepo,
 		profitMarginRepo: profitMarginRepo,
+		costRepo:         costRepo,
 	}
 }
 
@@ -63,8 +70,26 @@ func (s *ABCAnalysisServiceImpl) PerformABCAnalysis(ctx context.Context, criteri
 		return nil, err
 	}
 
+	// Получаем историю себестоимости, чтобы прибыль считалась по себестоимости на дату продажи
+	costs, err := s.costRepo.GetCostHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.AnalyzeSales(ctx, criteria, products, sales, profitMargins, costs)
+}
+
+// AnalyzeSales выполняет многокритериальный ABC-анализ по переданным данным и сохраняет сегментацию
+func (s *ABCAnalysisServiceImpl) AnalyzeSales(
+	ctx context.Context,
+	criteria entities.ABCAnalysisCriteria,
+	products []entities.Product,
+	sales []entities.Sale,
+	profitMargins map[string]float64,
+	costs []entities.ProductCost,
+) (*entities.ABCAnalysisResult, error) {
 	// Подготавливаем данные для анализа
-	productsData := prepareProductsData(products, sales, profitMargins)
+	productsData := prepareProductsData(products, sales, profitMargins, entities.NewCostHistory(costs))
 
 	// Выполняем анализ по каждому критерию
 	revenueSegmentation := s.analyzeByRevenue(productsData, criteria.ThresholdsRevenue)
@@ -80,14 +105,17 @@ func (s *ABCAnalysisServiceImpl) PerformABCAnalysis(ctx context.Context, criteri
 	)
 
 	// Сохраняем результаты в репозиторий
-	err = s.abcSegmentRepo.SaveSegmentation(ctx, finalSegmentation)
-	if err != nil {
+	if err := s.abcSegmentRepo.SaveSegmentation(ctx, finalSegmentation); err != nil {
 		return nil, err
 	}
 
 	// Формируем и возвращаем результат
-	return &ABCAnalysisResult{
-		AnalysisDate:         criteria.EndDate,
+	return &entities.ABCAnalysisResult{
+		AnalysisMetadata: entities.AnalysisMetadata{
+			AnalysisDate: criteria.EndDate,
+			PeriodStart:  criteria.StartDate,
+			PeriodEnd:    criteria.EndDate,
+		},
 		ProductsSegmentation: finalSegmentation,
 		Summary:              calculateSummary(finalSegmentation),
 	}, nil
@@ -119,7 +147,9 @@ func (s *ABCAnalysisServiceImpl) GetSegmentSummary(ctx context.Context) (*entiti
 }
 
 // prepareProductsData подготавливает данные о продуктах для анализа
-func prepareProductsData(products []entities.Product, sales []entities.Sale, profitMargins map[string]float64) []ProductAnalysisData {
+// Прибыль продажи — выручка после скидки за вычетом себестоимости, действовавшей на дату продажи;
+// для товаров без истории себестоимость выводится из текущей маржи товара
+func prepareProductsData(products []entities.Product, sales []entities.Sale, profitMargins map[string]float64, costs entities.CostHistory) []ProductAnalysisData {
 	// Агрегируем данные по каждому продукту
 	productData := make(map[string]ProductAnalysisData)
 
@@ -139,7 +169,13 @@ func prepareProductsData(products []entities.Product, sales []entities.Sale, pro
 		if data, exists := productData[sale.ProductID]; exists {
 			data.Revenue += sale.Price * float64(sale.Quantity)
 			data.Quantity += sale.Quantity
-			data.Profit += (sale.Price * float64(sale.Quantity)) * (data.ProfitMargin / 100.0)
+			// Без истории себестоимость выводится из маржи к цене продажи без скидки,
+			// чтобы прибыль обоих видов товаров считалась от выручки после скидки
+			unitCost := sale.Price * (1 - data.ProfitMargin/100.0)
+			if cost, ok := costs.At(sale.ProductID, sale.PurchaseDate); ok {
+				unitCost = cost.Cost
+			}
+			data.Profit += saleRevenue(sale) - unitCost*float64(sale.Quantity)
 			productData[sale.ProductID] = data
 		}
 	}
@@ -163,7 +199,7 @@ type ProductAnalysisData struct {
 }
 
 // analyzeByRevenue выполняет ABC-анализ по выручке
-func (s *ABCAnalysisServiceImpl) analyzeByRevenue(productsData []ProductAnalysisData, thresholds entities.Thresholds) map[string]string {
+func (s *ABCAnalysisServiceImpl) analyzeByRevenue(productsData []ProductAnalysisData, thresholds entities.Thresholds) map[string]entities.Segment {
 	// Сортируем продукты по выручке в порядке убывания
 	sort.Slice(productsData, func(i, j int) bool {
 		return productsData[i].Revenue > productsData[j].Revenue
@@ -182,7 +218,7 @@ func (s *ABCAnalysisServiceImpl) analyzeByRevenue(productsData []ProductAnalysis
 }
 
 // analyzeByQuantity выполняет ABC-анализ по количеству продаж
-func (s *ABCAnalysisServiceImpl) analyzeByQuantity(productsData []ProductAnalysisData, thresholds entities.Thresholds) map[string]string {
+func (s *ABCAnalysisServiceImpl) analyzeByQuantity(productsData []ProductAnalysisData, thresholds entities.Thresholds) map[string]entities.Segment {
 	// Сортируем продукты по количеству продаж в порядке убывания
 	sort.Slice(productsData, func(i, j int) bool {
 		return productsData[i].Quantity > productsData[j].Quantity
@@ -201,7 +237,7 @@ func (s *ABCAnalysisServiceImpl) analyzeByQuantity(productsData []ProductAnalysi
 }
 
 // analyzeByProfit выполняет ABC-анализ по прибыли
-func (s *ABCAnalysisServiceImpl) analyzeByProfit(productsData []ProductAnalysisData, thresholds entities.Thresholds) map[string]string {
+func (s *ABCAnalysisServiceImpl) analyzeByProfit(productsData []ProductAnalysisData, thresholds entities.Thresholds) map[string]entities.Segment {
 	// Сортируем продукты по прибыли в порядке убывания
 	sort.Slice(productsData, func(i, j int) bool {
 		return productsData[i].Profit > productsData[j].Profit
@@ -220,23 +256,23 @@ func (s *ABCAnalysisServiceImpl) analyzeByProfit(productsData []ProductAnalysisD
 }
 
 // determineSegments определяет сегменты A, B, C на основе кумулятивного процента
-func determineSegments(productsData []ProductAnalysisData, total float64, valueFunc func(ProductAnalysisData) float64, thresholds entities.Thresholds) map[string]string {
-	segments := make(map[string]string)
+func determineSegments(productsData []ProductAnalysisData, total float64, valueFunc func(ProductAnalysisData) float64, thresholds entities.Thresholds) map[string]entities.Segment {
+	segments := make(map[string]entities.Segment)
 	cumulativePercent := 0.0
 
 	for _, data := range productsData {
-		value := valueSelector(data)
+		value := valueFunc(data)
 		percent := (value / total) * 100
 		cumulativePercent += percent
 
 		// Определяем сегмент на основе кумулятивного процента
 		switch {
 		case cumulativePercent <= thresholds.AThreshold:
-			segments[data.Product.ID] = "A"
+			segments[data.Product.ID] = entities.SegmentA
 		case cumulativePercent <= thresholds.BThreshold:
-			segments[data.Product.ID] = "B"
+			segments[data.Product.ID] = entities.SegmentB
 		default:
-			segments[data.Product.ID] = "C"
+			segments[data.Product.ID] = entities.SegmentC
 		}
 	}
 
@@ -245,19 +281,19 @@ func determineSegments(productsData []ProductAnalysisData, total float64, valueF
 
 // combineSegmentations объединяет результаты сегментаций по разным критериям
 func (s *ABCAnalysisServiceImpl) combineSegmentations(
-	revenueSegmentation map[string]string,
-	quantitySegmentation map[string]string,
-	profitSegmentation map[string]string,
+	revenueSegmentation map[string]entities.Segment,
+	quantitySegmentation map[string]entities.Segment,
+	profitSegmentation map[string]entities.Segment,
 	weights entities.CriteriaWeights,
 ) map[string]entities.ProductFullSegmentation {
 
-	combinedSegmentation := make(map[string]ProductFullSegmentation)
+	combinedSegmentation := make(map[string]entities.ProductFullSegmentation)
 
 	// Присваиваем числовые значения сегментам (A=3, B=2, C=1)
-	segmentValues := map[string]int{
-		"A": 3,
-		"B": 2,
-		"C": 1,
+	segmentValues := map[entities.Segment]int{
+		entities.SegmentA: 3,
+		entities.SegmentB: 2,
+		entities.SegmentC: 1,
 	}
 
 	// Объединяем сегментации для каждого продукта
@@ -271,18 +307,18 @@ func (s *ABCAnalysisServiceImpl) combineSegmentations(
 			float64(segmentValues[profitSegment])*weights.ProfitWeight
 
 		// Определяем финальный сегмент на основе взвешенной оценки
-		var finalSegment string
+		var finalSegment entities.Segment
 		switch {
 		case weightedScore >= 2.5:
-			finalSegment = "A"
+			finalSegment = entities.SegmentA
 		case weightedScore >= 1.5:
-			finalSegment = "B"
+			finalSegment = entities.SegmentB
 		default:
-			finalSegment = "C"
+			finalSegment = entities.SegmentC
 		}
 
 		// Сохраняем результат
-		combinedSegmentation[productID] = ProductFullSegmentation{
+		combinedSegmentation[productID] = entities.ProductFullSegmentation{
 			ProductID:       productID,
 			RevenueSegment:  revenueSegment,
 			QuantitySegment: quantitySegment,
@@ -297,16 +333,16 @@ func (s *ABCAnalysisServiceImpl) combineSegmentations(
 
 // calculateSummary рассчитывает сводную информацию по сегментам
 func calculateSummary(segmentation map[string]entities.ProductFullSegmentation) *entities.ABCSegmentSummary {
-	summary := &ABCSegmentSummary{
-		SegmentCounts: map[string]int{
-			"A": 0,
-			"B": 0,
-			"C": 0,
+	summary := &entities.ABCSegmentSummary{
+		SegmentCounts: map[entities.Segment]int{
+			entities.SegmentA: 0,
+			entities.SegmentB: 0,
+			entities.SegmentC: 0,
 		},
-		SegmentPercentages: map[string]float64{
-			"A": 0,
-			"B": 0,
-			"C": 0,
+		SegmentPercentages: map[entities.Segment]float64{
+			entities.SegmentA: 0,
+			entities.SegmentB: 0,
+			entities.SegmentC: 0,
 		},
 	}
 
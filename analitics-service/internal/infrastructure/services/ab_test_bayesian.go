@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/pkg/stats"
+)
+
+// Методы построения апостериорных распределений
+const (
+	MethodBetaBinomial        = "beta_binomial"
+	MethodBayesianBootstrap   = "bayesian_bootstrap"
+	MethodNormalApproximation = "normal_approximation"
+)
+
+// maxBayesianBootstrapDraws ограничивает число выборок байесовского бутстрепа,
+// так как каждая выборка требует прохода по всем наблюдениям группы
+const maxBayesianBootstrapDraws = 2000
+
+// EvaluateBayesian выполняет байесовскую оценку переданного результата теста без сохранения
+func (s *abTestEvaluationService) EvaluateBayesian(ctx context.Context, result entities.ABTestResult, config entities.BayesianEvaluationConfig, samples *entities.ABTestSamples) (*entities.ABTestBayesianEvaluation, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bayesian evaluation config: %w", err)
+	}
+
+	control := result.ControlGroup
+	test := result.TestGroup.GroupStats
+	if control.Size <= 0 || test.Size <= 0 {
+		return nil, fmt.Errorf("%w: both groups must be non-empty", ErrInvalidGroupStats)
+	}
+
+	rng := rand.New(rand.NewSource(config.Seed))
+	evaluation := &entities.ABTestBayesianEvaluation{
+		TestID:      result.TestID,
+		EvaluatedAt: time.Now(),
+		Config:      config,
+	}
+
+	// Конверсия: Beta-Binomial модель с сопряженным априорным распределением
+	controlDraws := sampleConversionPosterior(rng, control, config)
+	testDraws := sampleConversionPosterior(rng, test, config)
+	evaluation.Metrics = append(evaluation.Metrics,
+		summarizePosterior(entities.MetricConversion, MethodBetaBinomial, controlDraws, testDraws, config.CredibleLevel))
+
+	// Средний чек и выручка на пользователя: байесовский бутстреп по наблюдениям
+	// или нормальная аппроксимация апостериорного распределения среднего
+	for _, metric := range []entities.ABTestMetric{entities.MetricAvgPurchase, entities.MetricRevenue} {
+		var controlValues, testValues []float64
+		if samples != nil {
+			controlValues, testValues = metricSamples(metric, samples)
+		}
+
+		if len(controlValues) >= 2 && len(testValues) >= 2 {
+			draws := config.PosteriorSamples
+			if draws > maxBayesianBootstrapDraws {
+				draws = maxBayesianBootstrapDraws
+			}
+			evaluation.Metrics = append(evaluation.Metrics, summarizePosterior(metric, MethodBayesianBootstrap,
+				bayesianBootstrapMeans(rng, controlValues, draws),
+				bayesianBootstrapMeans(rng, testValues, draws),
+				config.CredibleLevel))
+			continue
+		}
+
+		controlMean, controlSE, okControl := meanAndStandardError(metric, control)
+		testMean, testSE, okTest := meanAndStandardError(metric, test)
+		if !okControl || !okTest {
+			s.logger.Warn(ctx, "Метрика пропущена в байесовской оценке", "metric", metric, "testID", result.TestID)
+			continue
+		}
+
+		evaluation.Metrics = append(evaluation.Metrics, summarizePosterior(metric, MethodNormalApproximation,
+			sampleNormalPosterior(rng, controlMean, controlSE, config.PosteriorSamples),
+			sampleNormalPosterior(rng, testMean, testSE, config.PosteriorSamples),
+			config.CredibleLevel))
+	}
+
+	primary, ok := evaluation.MetricResult(config.PrimaryMetric)
+	if !ok {
+		return nil, fmt.Errorf("%w: primary metric %s cannot be evaluated", ErrInvalidGroupStats, config.PrimaryMetric)
+	}
+
+	evaluation.Decision = decideByExpectedLoss(primary, config.ExpectedLossThreshold)
+	evaluation.StopTest = evaluation.Decision != entities.DecisionContinue
+
+	return evaluation, nil
+}
+
+// applyBayesianEvaluation переносит итог байесовской оценки в результат теста
+func applyBayesianEvaluation(result *entities.ABTestResult, evaluation *entities.ABTestBayesianEvaluation) {
+	primary, _ := evaluation.MetricResult(evaluation.Config.PrimaryMetric)
+
+	result.BayesianEvaluation = evaluation
+	result.Lift = relativeLift(primary.ControlMean, primary.TestMean)
+	result.Significance = 0 // p-value в байесовском режиме не рассчитывается
+	result.ProbabilityToBeatControl = primary.ProbabilityToBeatControl
+	result.IsSignificant = evaluation.Decision == entities.DecisionShipTest
+}
+
+// decideByExpectedLoss применяет правило остановки по порогу ожидаемых потерь
+// Порог задается относительно значения метрики в контрольной группе
+func decideByExpectedLoss(primary entities.BayesianMetricResult, relativeThreshold float64) entities.ABTestDecision {
+	threshold := relativeThreshold * math.Abs(primary.ControlMean)
+
+	shipOK := primary.ExpectedLossTest < threshold
+	keepOK := primary.ExpectedLossControl < threshold
+
+	switch {
+	case shipOK && keepOK:
+		// Варианты практически эквивалентны, выбираем вариант с меньшими потерями
+		if primary.ExpectedLossTest < primary.ExpectedLossControl {
+			return entities.DecisionShipTest
+		}
+		return entities.DecisionKeepControl
+	case shipOK:
+		return entities.DecisionShipTest
+	case keepOK:
+		return entities.DecisionKeepControl
+	default:
+		return entities.DecisionContinue
+	}
+}
+
+// sampleConversionPosterior генерирует выборку из апостериорного Beta-распределения конверсии
+func sampleConversionPosterior(rng *rand.Rand, group entities.GroupStats, config entities.BayesianEvaluationConfig) []float64 {
+	successes := math.Round(float64(group.Size) * group.Conversion)
+	failures := float64(group.Size) - successes
+
+	draws := make([]float64, config.PosteriorSamples)
+	for i := range draws {
+		draws[i] = stats.SampleBeta(rng, config.PriorAlpha+successes, config.PriorBeta+failures)
+	}
+	return draws
+}
+
+// sampleNormalPosterior генерирует выборку из нормальной аппроксимации апостериорного распределения среднего
+func sampleNormalPosterior(rng *rand.Rand, mean, se float64, n int) []float64 {
+	draws := make([]float64, n)
+	for i := range draws {
+		draws[i] = mean + se*rng.NormFloat64()
+	}
+	return draws
+}
+
+// bayesianBootstrapMeans генерирует выборку средних методом байесовского бутстрепа
+func bayesianBootstrapMeans(rng *rand.Rand, values []float64, n int) []float64 {
+	draws := make([]float64, n)
+	for i := range draws {
+		weights := stats.DirichletWeights(rng, len(values))
+		mean := 0.0
+		for j, v := range values {
+			mean += weights[j] * v
+		}
+		draws[i] = mean
+	}
+	return draws
+}
+
+// metricSamples возвращает наблюдения групп для указанной метрики
+func metricSamples(metric entities.ABTestMetric, samples *entities.ABTestSamples) ([]float64, []float64) {
+	switch metric {
+	case entities.MetricAvgPurchase:
+		return samples.Control.PurchaseAmounts, samples.Test.PurchaseAmounts
+	case entities.MetricRevenue:
+		return samples.Control.RevenuePerUser, samples.Test.RevenuePerUser
+	default:
+		return nil, nil
+	}
+}
+
+// meanAndStandardError возвращает среднее и стандартную ошибку метрики по статистикам группы
+func meanAndStandardError(metric entities.ABTestMetric, group entities.GroupStats) (float64, float64, bool) {
+	switch metric {
+	case entities.MetricAvgPurchase:
+		n := math.Round(float64(group.Size) * group.Conversion)
+		if n < 2 || group.AvgPurchaseStdDev <= 0 {
+			return 0, 0, false
+		}
+		return group.AvgPurchase, group.AvgPurchaseStdDev / math.Sqrt(n), true
+	case entities.MetricRevenue:
+		n := float64(group.Size)
+		if n < 2 || group.RevenueStdDev <= 0 {
+			return 0, 0, false
+		}
+		return group.Revenue / n, group.RevenueStdDev / math.Sqrt(n), true
+	default:
+		return 0, 0, false
+	}
+}
+
+// summarizePosterior рассчитывает вероятность превосходства, ожидаемые потери и достоверные интервалы
+// по совместной выборке апостериорных распределений контрольной и тестовой групп
+func summarizePosterior(metric entities.ABTestMetric, method string, controlDraws, testDraws []float64, credibleLevel float64) entities.BayesianMetricResult {
+	n := len(controlDraws)
+	if len(testDraws) < n {
+		n = len(testDraws)
+	}
+
+	wins := 0
+	lossTest, lossControl := 0.0, 0.0
+	lifts := make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		c, t := controlDraws[i], testDraws[i]
+		if t > c {
+			wins++
+			lossControl += t - c
+		} else {
+			lossTest += c - t
+		}
+		if c != 0 {
+			lifts = append(lifts, (t-c)/c)
+		}
+	}
+
+	tail := (1 - credibleLevel) / 2
+	controlSorted := sortedCopy(controlDraws[:n])
+	testSorted := sortedCopy(testDraws[:n])
+	sort.Float64s(lifts)
+
+	return entities.BayesianMetricResult{
+		Metric:                   metric,
+		Method:                   method,
+		ControlMean:              stats.Mean(controlSorted),
+		TestMean:                 stats.Mean(testSorted),
+		ProbabilityToBeatControl: float64(wins) / float64(n),
+		ExpectedLossTest:         lossTest / float64(n),
+		ExpectedLossControl:      lossControl / float64(n),
+		ControlInterval:          [2]float64{stats.QuantileSorted(controlSorted, tail), stats.QuantileSorted(controlSorted, 1-tail)},
+		TestInterval:             [2]float64{stats.QuantileSorted(testSorted, tail), stats.QuantileSorted(testSorted, 1-tail)},
+		LiftInterval:             [2]float64{stats.QuantileSorted(lifts, tail), stats.QuantileSorted(lifts, 1-tail)},
+	}
+}
+
+// sortedCopy возвращает отсортированную копию массива
+func sortedCopy(values []float64) []float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return sorted
+}
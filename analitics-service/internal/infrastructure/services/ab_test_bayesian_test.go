@@ -0,0 +1,120 @@
+// internal/infrastructure/services/ab_test_bayesian_test.go
+package services_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// Допустимое расхождение оценок Монте-Карло с точными значениями апостериорных распределений
+const testPosteriorTolerance = 0.01
+
+func TestEvaluateBayesianConversion(t *testing.T) {
+	tests := []struct {
+		name          string
+		control, test entities.GroupStats
+		// Средние апостериорных Beta(1 + успехи, 1 + неудачи)
+		controlMean, testMean float64
+		// P(test > control), посчитанная численным интегрированием плотностей
+		probability float64
+		decision    entities.ABTestDecision
+	}{
+		{
+			name:        "test clearly better",
+			control:     entities.GroupStats{Size: 1000, Conversion: 0.10},
+			test:        entities.GroupStats{Size: 1000, Conversion: 0.13},
+			controlMean: 101.0 / 1002,
+			testMean:    131.0 / 1002,
+			probability: 0.982165,
+			decision:    entities.DecisionShipTest,
+		},
+		{
+			name:        "test clearly worse",
+			control:     entities.GroupStats{Size: 1000, Conversion: 0.13},
+			test:        entities.GroupStats{Size: 1000, Conversion: 0.10},
+			controlMean: 131.0 / 1002,
+			testMean:    101.0 / 1002,
+			probability: 1 - 0.982165,
+			decision:    entities.DecisionKeepControl,
+		},
+		{
+			name:        "equal small groups",
+			control:     entities.GroupStats{Size: 100, Conversion: 0.10},
+			test:        entities.GroupStats{Size: 100, Conversion: 0.10},
+			controlMean: 11.0 / 102,
+			testMean:    11.0 / 102,
+			probability: 0.5,
+			decision:    entities.DecisionContinue,
+		},
+	}
+
+	service := services.NewABTestEvaluationService(nil, logger.NewLogger("ERROR"))
+	config := entities.DefaultBayesianEvaluationConfig()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := entities.ABTestResult{TestID: "test", ControlGroup: tt.control}
+			result.TestGroup.GroupStats = tt.test
+
+			evaluation, err := service.EvaluateBayesian(context.Background(), result, config, nil)
+			if err != nil {
+				t.Fatalf("EvaluateBayesian() error = %v", err)
+			}
+
+			metric, ok := evaluation.MetricResult(entities.MetricConversion)
+			if !ok {
+				t.Fatalf("conversion not evaluated")
+			}
+			if metric.Method != services.MethodBetaBinomial {
+				t.Errorf("method = %s, want %s", metric.Method, services.MethodBetaBinomial)
+			}
+			if math.Abs(metric.ControlMean-tt.controlMean) > testPosteriorTolerance*tt.controlMean {
+				t.Errorf("control mean = %.5f, want %.5f", metric.ControlMean, tt.controlMean)
+			}
+			if math.Abs(metric.TestMean-tt.testMean) > testPosteriorTolerance*tt.testMean {
+				t.Errorf("test mean = %.5f, want %.5f", metric.TestMean, tt.testMean)
+			}
+			if math.Abs(metric.ProbabilityToBeatControl-tt.probability) > testPosteriorTolerance {
+				t.Errorf("probability to beat control = %.4f, want %.4f", metric.ProbabilityToBeatControl, tt.probability)
+			}
+			if lo, hi := metric.ControlInterval[0], metric.ControlInterval[1]; lo > metric.ControlMean || hi < metric.ControlMean {
+				t.Errorf("control interval [%.4f, %.4f] does not contain mean %.4f", lo, hi, metric.ControlMean)
+			}
+			if evaluation.Decision != tt.decision {
+				t.Errorf("decision = %s, want %s", evaluation.Decision, tt.decision)
+			}
+			if evaluation.StopTest != (tt.decision != entities.DecisionContinue) {
+				t.Errorf("stop test = %v for decision %s", evaluation.StopTest, tt.decision)
+			}
+		})
+	}
+}
+
+func TestEvaluateBayesianIsDeterministicForSeed(t *testing.T) {
+	service := services.NewABTestEvaluationService(nil, logger.NewLogger("ERROR"))
+	config := entities.DefaultBayesianEvaluationConfig()
+	config.PosteriorSamples = 2000
+
+	result := entities.ABTestResult{TestID: "test", ControlGroup: entities.GroupStats{Size: 500, Conversion: 0.2}}
+	result.TestGroup.GroupStats = entities.GroupStats{Size: 500, Conversion: 0.22}
+
+	first, err := service.EvaluateBayesian(context.Background(), result, config, nil)
+	if err != nil {
+		t.Fatalf("EvaluateBayesian() error = %v", err)
+	}
+	second, err := service.EvaluateBayesian(context.Background(), result, config, nil)
+	if err != nil {
+		t.Fatalf("EvaluateBayesian() error = %v", err)
+	}
+
+	a, _ := first.MetricResult(entities.MetricConversion)
+	b, _ := second.MetricResult(entities.MetricConversion)
+	if a.ProbabilityToBeatControl != b.ProbabilityToBeatControl || a.ExpectedLossTest != b.ExpectedLossTest {
+		t.Errorf("evaluations with the same seed differ: %+v vs %+v", a, b)
+	}
+}
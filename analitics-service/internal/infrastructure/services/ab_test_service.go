@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+	"analitics-service/pkg/stats"
+)
+
+// Методы статистической проверки метрик
+const (
+	MethodTwoProportionZ = "two_proportion_z"
+	MethodWelchT         = "welch_t"
+)
+
+// Ошибки оценки A/B тестов
+var (
+	// ErrInvalidGroupStats возвращается, если статистики групп не позволяют провести тест
+	ErrInvalidGroupStats = errors.New("invalid group statistics for A/B test evaluation")
+	ErrABTestNotFound    = errors.New("A/B test not found")
+)
+
+// ABTestEvaluationService определяет интерфейс оценки A/B тестов в частотном и байесовском режимах
+type ABTestEvaluationService interface {
+	// EvaluateTest загружает тест, оценивает его в режиме теста (AnalysisMode) и сохраняет результат
+	// samples могут быть nil, тогда бутстреп-оценки не рассчитываются
+	EvaluateTest(ctx context.Context, testID string, options entities.ABTestAnalysisOptions, samples *entities.ABTestSamples) (*entities.ABTestResult, error)
+
+	// Evaluate выполняет частотную оценку переданного результата теста без сохранения
+	Evaluate(ctx context.Context, result entities.ABTestResult, config entities.ABTestEvaluationConfig, samples *entities.ABTestSamples) (*entities.ABTestEvaluation, error)
+
+	// EvaluateBayesian выполняет байесовскую оценку переданного результата теста без сохранения
+	EvaluateBayesian(ctx context.Context, result entities.ABTestResult, config entities.BayesianEvaluationConfig, samples *entities.ABTestSamples) (*entities.ABTestBayesianEvaluation, error)
+
+	// RequiredSampleSize возвращает необходимый размер каждой группы для обнаружения
+	// относительного эффекта relativeMDE по метрике при заданных alpha и мощности
+	RequiredSampleSize(baseline entities.GroupStats, metric entities.ABTestMetric, relativeMDE float64, config entities.ABTestEvaluationConfig) (int, error)
+}
+
+// abTestEvaluationService реализует интерфейс ABTestEvaluationService
+type abTestEvaluationService struct {
+	abTestRepo repositories.ABTestRepository
+	logger     logger.Logger
+}
+
+// NewABTestEvaluationService создает новый экземпляр сервиса оценки A/B тестов
+func NewABTestEvaluationService(abTestRepo repositories.ABTestRepository, logger logger.Logger) ABTestEvaluationService {
+	return &abTestEvaluationService{
+		abTestRepo: abTestRepo,
+		logger:     logger,
+	}
+}
+
+// EvaluateTest загружает тест, оценивает его в режиме теста и сохраняет результат через репозиторий
+func (s *abTestEvaluationService) EvaluateTest(ctx context.Context, testID string, options entities.ABTestAnalysisOptions, samples *entities.ABTestSamples) (*entities.ABTestResult, error) {
+	result, err := s.abTestRepo.GetTestResultByID(ctx, testID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve test %s: %w", testID, err)
+	}
+	if result.TestID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrABTestNotFound, testID)
+	}
+
+	switch result.AnalysisMode {
+	case entities.AnalysisModeBayesian:
+		evaluation, err := s.EvaluateBayesian(ctx, result, options.Bayesian, samples)
+		if err != nil {
+			return nil, err
+		}
+		applyBayesianEvaluation(&result, evaluation)
+	case entities.AnalysisModeFrequentist, "":
+		evaluation, err := s.Evaluate(ctx, result, options.Frequentist, samples)
+		if err != nil {
+			return nil, err
+		}
+		applyEvaluation(&result, evaluation)
+	default:
+		return nil, fmt.Errorf("%w: unknown analysis mode %s", ErrInvalidParameter, result.AnalysisMode)
+	}
+
+	if err := s.abTestRepo.SaveTestResult(ctx, result); err != nil {
+		return nil, fmt.Errorf("failed to save test result %s: %w", testID, err)
+	}
+
+	s.logger.Info(ctx, "A/B тест оценен", "testID", testID, "mode", result.AnalysisMode,
+		"significance", result.Significance, "probabilityToBeatControl", result.ProbabilityToBeatControl,
+		"significant", result.IsSignificant)
+	return &result, nil
+}
+
+// Evaluate оценивает переданный результат теста без сохранения
+func (s *abTestEvaluationService) Evaluate(ctx context.Context, result entities.ABTestResult, config entities.ABTestEvaluationConfig, samples *entities.ABTestSamples) (*entities.ABTestEvaluation, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid evaluation config: %w", err)
+	}
+
+	control := result.ControlGroup
+	test := result.TestGroup.GroupStats
+	if control.Size <= 0 || test.Size <= 0 {
+		return nil, fmt.Errorf("%w: both groups must be non-empty", ErrInvalidGroupStats)
+	}
+
+	evaluation := &entities.ABTestEvaluation{
+		TestID:      result.TestID,
+		EvaluatedAt: time.Now(),
+		Config:      config,
+		SampleRatio: checkSampleRatio(control.Size, test.Size, config),
+	}
+
+	if evaluation.SampleRatio.Mismatch {
+		s.logger.Warn(ctx, "Обнаружено несоответствие соотношения групп", "testID", result.TestID,
+			"expected", config.ExpectedTestShare, "observed", evaluation.SampleRatio.ObservedTestShare)
+	}
+
+	evaluation.Metrics = append(evaluation.Metrics, evaluateConversion(control, test, config))
+
+	rng := rand.New(rand.NewSource(config.Seed))
+
+	avgPurchase, err := evaluateMean(entities.MetricAvgPurchase, control, test, config)
+	if err != nil {
+		s.logger.Warn(ctx, "Метрика пропущена", "metric", entities.MetricAvgPurchase, "error", err)
+	} else {
+		if samples != nil && config.BootstrapIterations > 0 {
+			avgPurchase.BootstrapInterval = bootstrapMeanDiff(rng, samples.Control.PurchaseAmounts, samples.Test.PurchaseAmounts, config)
+		}
+		evaluation.Metrics = append(evaluation.Metrics, avgPurchase)
+	}
+
+	revenue, err := evaluateMean(entities.MetricRevenue, control, test, config)
+	if err != nil {
+		s.logger.Warn(ctx, "Метрика пропущена", "metric", entities.MetricRevenue, "error", err)
+	} else {
+		if samples != nil && config.BootstrapIterations > 0 {
+			revenue.BootstrapInterval = bootstrapMeanDiff(rng, samples.Control.RevenuePerUser, samples.Test.RevenuePerUser, config)
+		}
+		evaluation.Metrics = append(evaluation.Metrics, revenue)
+	}
+
+	if _, ok := evaluation.MetricResult(config.PrimaryMetric); !ok {
+		return nil, fmt.Errorf("%w: primary metric %s cannot be evaluated", ErrInvalidGroupStats, config.PrimaryMetric)
+	}
+
+	return evaluation, nil
+}
+
+// RequiredSampleSize возвращает необходимый размер каждой группы для обнаружения эффекта
+func (s *abTestEvaluationService) RequiredSampleSize(baseline entities.GroupStats, metric entities.ABTestMetric, relativeMDE float64, config entities.ABTestEvaluationConfig) (int, error) {
+	if err := config.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid evaluation config: %w", err)
+	}
+	if relativeMDE <= 0 {
+		return 0, fmt.Errorf("%w: relative MDE must be positive, got %f", ErrInvalidParameter, relativeMDE)
+	}
+
+	z := stats.NormalQuantile(1-config.Alpha/2) + stats.NormalQuantile(config.TargetPower)
+
+	var variance, delta float64
+	switch metric {
+	case entities.MetricConversion:
+		p1 := baseline.Conversion
+		p2 := math.Min(p1*(1+relativeMDE), 1)
+		if p1 <= 0 || p1 >= 1 {
+			return 0, fmt.Errorf("%w: baseline conversion must be between 0 and 1", ErrInvalidGroupStats)
+		}
+		variance = p1*(1-p1) + p2*(1-p2)
+		delta = p2 - p1
+	case entities.MetricAvgPurchase:
+		variance = 2 * baseline.AvgPurchaseStdDev * baseline.AvgPurchaseStdDev
+		delta = baseline.AvgPurchase * relativeMDE
+	case entities.MetricRevenue:
+		if baseline.Size <= 0 {
+			return 0, fmt.Errorf("%w: baseline size must be positive", ErrInvalidGroupStats)
+		}
+		variance = 2 * baseline.RevenueStdDev * baseline.RevenueStdDev
+		delta = baseline.Revenue / float64(baseline.Size) * relativeMDE
+	default:
+		return 0, fmt.Errorf("%w: unknown metric %s", ErrInvalidParameter, metric)
+	}
+
+	if variance <= 0 || delta == 0 {
+		return 0, fmt.Errorf("%w: baseline variance and effect must be positive", ErrInvalidGroupStats)
+	}
+
+	n := z * z * variance / (delta * delta)
+	if metric == entities.MetricAvgPurchase && baseline.Conversion > 0 {
+		// Средний чек считается только по конвертировавшимся пользователям
+		n /= baseline.Conversion
+	}
+
+	return int(math.Ceil(n)), nil
+}
+
+// applyEvaluation переносит итог оценки в результат теста
+func applyEvaluation(result *entities.ABTestResult, evaluation *entities.ABTestEvaluation) {
+	primary, _ := evaluation.MetricResult(evaluation.Config.PrimaryMetric)
+
+	result.Evaluation = evaluation
+	result.Lift = primary.RelativeLift
+	result.Significance = primary.PValue
+	result.ProbabilityToBeatControl = 0
+	// При SRM результат недостоверен независимо от p-value
+	result.IsSignificant = primary.IsSignificant && !evaluation.SampleRatio.Mismatch
+}
+
+// evaluateConversion выполняет двухвыборочный z-тест для долей
+func evaluateConversion(control, test entities.GroupStats, config entities.ABTestEvaluationConfig) entities.MetricTestResult {
+	n1, n2 := float64(control.Size), float64(test.Size)
+	p1, p2 := control.Conversion, test.Conversion
+	diff := p2 - p1
+
+	// Для статистики используем объединенную долю, для интервала — раздельные дисперсии
+	pooled := (p1*n1 + p2*n2) / (n1 + n2)
+	sePooled := math.Sqrt(pooled * (1 - pooled) * (1/n1 + 1/n2))
+	seUnpooled := math.Sqrt(p1*(1-p1)/n1 + p2*(1-p2)/n2)
+
+	zCrit := stats.NormalQuantile(1 - config.Alpha/2)
+
+	result := entities.MetricTestResult{
+		Metric:       entities.MetricConversion,
+		Method:       MethodTwoProportionZ,
+		ControlValue: p1,
+		TestValue:    p2,
+		AbsoluteDiff: diff,
+		RelativeLift: relativeLift(p1, p2),
+		PValue:       1,
+	}
+
+	if sePooled > 0 {
+		result.Statistic = diff / sePooled
+		result.PValue = 2 * (1 - stats.NormalCDF(math.Abs(result.Statistic)))
+	}
+
+	result.ConfidenceInterval = [2]float64{diff - zCrit*seUnpooled, diff + zCrit*seUnpooled}
+	result.Power = achievedPower(diff, seUnpooled, config.Alpha)
+	result.MinimumDetectableEffect = minimumDetectableEffect(math.Sqrt(p1*(1-p1)*(1/n1+1/n2)), config)
+	result.IsSignificant = result.PValue < config.Alpha
+
+	return result
+}
+
+// evaluateMean выполняет t-тест Уэлча для среднего чека или выручки на пользователя
+func evaluateMean(metric entities.ABTestMetric, control, test entities.GroupStats, config entities.ABTestEvaluationConfig) (entities.MetricTestResult, error) {
+	var m1, m2, s1, s2, n1, n2 float64
+
+	switch metric {
+	case entities.MetricAvgPurchase:
+		m1, m2 = control.AvgPurchase, test.AvgPurchase
+		s1, s2 = control.AvgPurchaseStdDev, test.AvgPurchaseStdDev
+		n1 = math.Round(float64(control.Size) * control.Conversion)
+		n2 = math.Round(float64(test.Size) * test.Conversion)
+	case entities.MetricRevenue:
+		n1, n2 = float64(control.Size), float64(test.Size)
+		m1, m2 = control.Revenue/n1, test.Revenue/n2
+		s1, s2 = control.RevenueStdDev, test.RevenueStdDev
+	default:
+		return entities.MetricTestResult{}, fmt.Errorf("%w: unknown metric %s", ErrInvalidParameter, metric)
+	}
+
+	if n1 < 2 || n2 < 2 {
+		return entities.MetricTestResult{}, fmt.Errorf("%w: at least two observations per group are required", ErrInsufficientData)
+	}
+	if s1 <= 0 && s2 <= 0 {
+		return entities.MetricTestResult{}, fmt.Errorf("%w: standard deviations are missing", ErrInvalidGroupStats)
+	}
+
+	v1, v2 := s1*s1/n1, s2*s2/n2
+	se := math.Sqrt(v1 + v2)
+	diff := m2 - m1
+
+	// Степени свободы по формуле Уэлча–Саттертуэйта
+	df := (v1 + v2) * (v1 + v2) / (v1*v1/(n1-1) + v2*v2/(n2-1))
+	t := diff / se
+	tCrit := stats.StudentTQuantile(1-config.Alpha/2, df)
+
+	result := entities.MetricTestResult{
+		Metric:                  metric,
+		Method:                  MethodWelchT,
+		ControlValue:            m1,
+		TestValue:               m2,
+		AbsoluteDiff:            diff,
+		RelativeLift:            relativeLift(m1, m2),
+		Statistic:               t,
+		DegreesOfFreedom:        df,
+		PValue:                  2 * (1 - stats.StudentTCDF(math.Abs(t), df)),
+		ConfidenceInterval:      [2]float64{diff - tCrit*se, diff + tCrit*se},
+		Power:                   achievedPower(diff, se, config.Alpha),
+		MinimumDetectableEffect: minimumDetectableEffect(se, config),
+	}
+	result.IsSignificant = result.PValue < config.Alpha
+
+	return result, nil
+}
+
+// checkSampleRatio проверяет соответствие размеров групп ожидаемому разбиению критерием хи-квадрат
+func checkSampleRatio(controlSize, testSize int, config entities.ABTestEvaluationConfig) entities.SampleRatioCheck {
+	total := float64(controlSize + testSize)
+	expectedTest := total * config.ExpectedTestShare
+	expectedControl := total - expectedTest
+
+	chi := math.Pow(float64(testSize)-expectedTest, 2)/expectedTest +
+		math.Pow(float64(controlSize)-expectedControl, 2)/expectedControl
+	pValue := stats.ChiSquareSurvival(chi, 1)
+
+	return entities.SampleRatioCheck{
+		ExpectedTestShare: config.ExpectedTestShare,
+		ObservedTestShare: float64(testSize) / total,
+		ChiSquare:         chi,
+		PValue:            pValue,
+		Mismatch:          pValue < config.SRMAlpha,
+	}
+}
+
+// bootstrapMeanDiff строит перцентильный бутстреп-интервал для разницы средних
+func bootstrapMeanDiff(rng *rand.Rand, control, test []float64, config entities.ABTestEvaluationConfig) *[2]float64 {
+	if len(control) < 2 || len(test) < 2 {
+		return nil
+	}
+
+	diffs := make([]float64, config.BootstrapIterations)
+	for i := range diffs {
+		diffs[i] = resampleMean(rng, test) - resampleMean(rng, control)
+	}
+	sort.Float64s(diffs)
+
+	return &[2]float64{
+		stats.QuantileSorted(diffs, config.Alpha/2),
+		stats.QuantileSorted(diffs, 1-config.Alpha/2),
+	}
+}
+
+// resampleMean возвращает среднее выборки с возвращением того же размера
+func resampleMean(rng *rand.Rand, values []float64) float64 {
+	sum := 0.0
+	for range values {
+		sum += values[rng.Intn(len(values))]
+	}
+	return sum / float64(len(values))
+}
+
+// achievedPower рассчитывает мощность двустороннего теста при наблюдаемом эффекте
+func achievedPower(diff, se, alpha float64) float64 {
+	if se <= 0 {
+		return 0
+	}
+	zCrit := stats.NormalQuantile(1 - alpha/2)
+	shift := math.Abs(diff) / se
+	return stats.NormalCDF(shift-zCrit) + stats.NormalCDF(-shift-zCrit)
+}
+
+// minimumDetectableEffect рассчитывает абсолютный MDE при заданной стандартной ошибке
+func minimumDetectableEffect(se float64, config entities.ABTestEvaluationConfig) float64 {
+	return (stats.NormalQuantile(1-config.Alpha/2) + stats.NormalQuantile(config.TargetPower)) * se
+}
+
+// relativeLift возвращает относительный прирост тестовой группы над контрольной
+func relativeLift(control, test float64) float64 {
+	if control == 0 {
+		return 0
+	}
+	return (test - control) / control
+}
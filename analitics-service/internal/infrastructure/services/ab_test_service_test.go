@@ -0,0 +1,151 @@
+// internal/infrastructure/services/ab_test_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// Допустимое расхождение статистик и p-value со значениями, посчитанными независимо
+const testStatTolerance = 1e-4
+
+func TestEvaluateFrequentist(t *testing.T) {
+	tests := []struct {
+		name          string
+		control, test entities.GroupStats
+		metric        entities.ABTestMetric
+		method        string
+		statistic     float64
+		df            float64
+		pValue        float64
+		significant   bool
+	}{
+		{
+			name:        "conversion z-test significant",
+			control:     entities.GroupStats{Size: 1000, Conversion: 0.10},
+			test:        entities.GroupStats{Size: 1000, Conversion: 0.13},
+			metric:      entities.MetricConversion,
+			method:      services.MethodTwoProportionZ,
+			statistic:   2.102741,
+			pValue:      0.035488,
+			significant: true,
+		},
+		{
+			name:      "conversion z-test not significant",
+			control:   entities.GroupStats{Size: 1000, Conversion: 0.10},
+			test:      entities.GroupStats{Size: 1000, Conversion: 0.105},
+			metric:    entities.MetricConversion,
+			method:    services.MethodTwoProportionZ,
+			statistic: 0.368617,
+			pValue:    0.712413,
+		},
+		{
+			name:        "revenue welch large sample",
+			control:     entities.GroupStats{Size: 1000, Conversion: 0.5, Revenue: 50000, RevenueStdDev: 20},
+			test:        entities.GroupStats{Size: 1000, Conversion: 0.5, Revenue: 52000, RevenueStdDev: 25},
+			metric:      entities.MetricRevenue,
+			method:      services.MethodWelchT,
+			statistic:   1.975459,
+			df:          1906.150965,
+			pValue:      0.048360,
+			significant: true,
+		},
+		{
+			// Средний чек считается по купившим: 40 * 0.5 = 20 наблюдений в каждой группе
+			name:      "avg purchase welch unequal variances",
+			control:   entities.GroupStats{Size: 40, Conversion: 0.5, AvgPurchase: 100, AvgPurchaseStdDev: 3},
+			test:      entities.GroupStats{Size: 40, Conversion: 0.5, AvgPurchase: 103, AvgPurchaseStdDev: 6},
+			metric:    entities.MetricAvgPurchase,
+			method:    services.MethodWelchT,
+			statistic: 2,
+			df:        27.941176,
+			pValue:    0.055306,
+		},
+	}
+
+	service := services.NewABTestEvaluationService(nil, logger.NewLogger("ERROR"))
+	config := entities.DefaultABTestEvaluationConfig()
+	config.BootstrapIterations = 0
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := entities.ABTestResult{TestID: "test", ControlGroup: tt.control}
+			result.TestGroup.GroupStats = tt.test
+
+			evaluation, err := service.Evaluate(context.Background(), result, config, nil)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			metric, ok := evaluation.MetricResult(tt.metric)
+			if !ok {
+				t.Fatalf("metric %s not evaluated", tt.metric)
+			}
+			if metric.Method != tt.method {
+				t.Errorf("method = %s, want %s", metric.Method, tt.method)
+			}
+			if math.Abs(metric.Statistic-tt.statistic) > testStatTolerance {
+				t.Errorf("statistic = %.6f, want %.6f", metric.Statistic, tt.statistic)
+			}
+			if math.Abs(metric.DegreesOfFreedom-tt.df) > testStatTolerance {
+				t.Errorf("degrees of freedom = %.6f, want %.6f", metric.DegreesOfFreedom, tt.df)
+			}
+			if math.Abs(metric.PValue-tt.pValue) > testStatTolerance {
+				t.Errorf("p-value = %.6f, want %.6f", metric.PValue, tt.pValue)
+			}
+			if metric.IsSignificant != tt.significant {
+				t.Errorf("significant = %v, want %v", metric.IsSignificant, tt.significant)
+			}
+			if lo, hi := metric.ConfidenceInterval[0], metric.ConfidenceInterval[1]; lo > metric.AbsoluteDiff || hi < metric.AbsoluteDiff {
+				t.Errorf("confidence interval [%.4f, %.4f] does not contain diff %.4f", lo, hi, metric.AbsoluteDiff)
+			}
+		})
+	}
+}
+
+func TestEvaluateSampleRatioMismatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		controlSize  int
+		testSize     int
+		wantMismatch bool
+	}{
+		{name: "balanced", controlSize: 5000, testSize: 5040},
+		{name: "skewed", controlSize: 5000, testSize: 5600, wantMismatch: true},
+	}
+
+	service := services.NewABTestEvaluationService(nil, logger.NewLogger("ERROR"))
+	config := entities.DefaultABTestEvaluationConfig()
+	config.BootstrapIterations = 0
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := entities.ABTestResult{TestID: "test", ControlGroup: entities.GroupStats{Size: tt.controlSize, Conversion: 0.1}}
+			result.TestGroup.GroupStats = entities.GroupStats{Size: tt.testSize, Conversion: 0.1}
+
+			evaluation, err := service.Evaluate(context.Background(), result, config, nil)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if evaluation.SampleRatio.Mismatch != tt.wantMismatch {
+				t.Errorf("mismatch = %v, want %v", evaluation.SampleRatio.Mismatch, tt.wantMismatch)
+			}
+		})
+	}
+}
+
+func TestEvaluateRejectsEmptyGroup(t *testing.T) {
+	service := services.NewABTestEvaluationService(nil, logger.NewLogger("ERROR"))
+
+	result := entities.ABTestResult{TestID: "test", ControlGroup: entities.GroupStats{Size: 100, Conversion: 0.1}}
+	_, err := service.Evaluate(context.Background(), result, entities.DefaultABTestEvaluationConfig(), nil)
+	if !errors.Is(err, services.ErrInvalidGroupStats) {
+		t.Errorf("Evaluate() error = %v, want %v", err, services.ErrInvalidGroupStats)
+	}
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/buildinfo"
+	"analitics-service/pkg/logger"
+)
+
+// ErrAnalysisRunNotFound возвращается при обращении к несуществующему запуску анализа
+var ErrAnalysisRunNotFound = errors.New("analysis run not found")
+
+// AnalysisRunService определяет интерфейс воспроизводимых запусков анализа
+// Каждый запуск сохраняет параметры, версию кода, количество и хэш входных строк, а результаты ссылаются на запуск
+// Запись о запуске, результаты и событие об их пересчете сохраняются в одной транзакции
+// Перед анализом входные данные проходят проверку качества; отпечаток считается по исходным данным,
+// а анализ выполняется по очищенным, итоги проверки и записи карантина сохраняются вместе с запуском
+type AnalysisRunService interface {
+	// RunABCAnalysis выполняет ABC-анализ и сохраняет результат, связанный с запуском
+	RunABCAnalysis(ctx context.Context, criteria entities.ABCAnalysisCriteria) (*entities.AnalysisRun, error)
+
+	// RunAssociationRules ищет ассоциативные правила по транзакциям периода и сохраняет их, связав с запуском
+	RunAssociationRules(ctx context.Context, params entities.AssociationRuleParams) (*entities.AnalysisRun, error)
+
+	// RunDiscountRecommendations генерирует рекомендации по скидкам и сохраняет их, связав с запуском
+	RunDiscountRecommendations(ctx context.Context, params entities.DiscountRecommendationParams) (*entities.AnalysisRun, error)
+
+	// Rerun повторяет анализ с параметрами сохраненного запуска
+	Rerun(ctx context.Context, runID string) (*entities.AnalysisRun, error)
+
+	// GetRun возвращает запуск по ID или nil, если запуск не найден
+	GetRun(ctx context.Context, runID string) (*entities.AnalysisRun, error)
+
+	// GetRuns возвращает последние запуски анализа указанного вида, пустой вид — всех видов
+	GetRuns(ctx context.Context, analysisType entities.AnalysisType, limit int) ([]entities.AnalysisRun, error)
+
+	// GetQuarantined возвращает записи, исключенные проверкой качества данных при запуске
+	GetQuarantined(ctx context.Context, runID string) ([]entities.QuarantinedRecord, error)
+}
+
+// analysisRunService реализует интерфейс AnalysisRunService
+// Входные данные для отпечатка читаются из тех же репозиториев и за тот же период, что и в самом анализе
+type analysisRunService struct {
+	abcService         ABCAnalysisService
+	aprioriService     AprioriService
+	regressionService  RegressionService
+	productRepo        repositories.ProductRepository
+	salesRepo          repositories.SalesRepository
+	profitMarginRepo   repositories.ProfitMarginRepository
+	costRepo           repositories.ProductCostRepository
+	transactionRepo    repositories.TransactionRepository
+	lifecycleRepo      repositories.ProductLifecycleRepository
+	abcAnalysisRepo    repositories.ABCAnalysisRepository
+	ruleRepo           repositories.AssociationRuleRepository
+	recommendationRepo repositories.DiscountRecommendationRepository
+	abcSegmentRepo     repositories.ABCSegmentRepository
+	runRepo            repositories.AnalysisRunRepository
+	quarantineRepo     repositories.QuarantineRepository
+	transactor         repositories.Transactor
+	eventService       AnalyticsEventService
+	dataQualityService DataQualityService
+	dataQualityConfig  entities.DataQualityConfig
+	logger             logger.Logger
+}
+
+// productProfitMargin строка маржи товара для отпечатка входных данных
+type productProfitMargin struct {
+	ProductID string  `json:"product_id"`
+	Margin    float64 `json:"margin"`
+}
+
+// NewAnalysisRunService создает новый экземпляр сервиса воспроизводимых запусков анализа
+func NewAnalysisRunService(
+	abcService ABCAnalysisService,
+	aprioriService AprioriService,
+	regressionService RegressionService,
+	productRepo repositories.ProductRepository,
+	salesRepo repositories.SalesRepository,
+	profitMarginRepo repositories.ProfitMarginRepository,
+	costRepo repositories.ProductCostRepository,
+	transactionRepo repositories.TransactionRepository,
+	lifecycleRepo repositories.ProductLifecycleRepository,
+	abcAnalysisRepo repositories.ABCAnalysisRepository,
+	ruleRepo repositories.AssociationRuleRepository,
+	recommendationRepo repositories.DiscountRecommendationRepository,
+	abcSegmentRepo repositories.ABCSegmentRepository,
+	runRepo repositories.AnalysisRunRepository,
+	quarantineRepo repositories.QuarantineRepository,
+	transactor repositories.Transactor,
+	eventService AnalyticsEventService,
+	dataQualityService DataQualityService,
+	dataQualityConfig entities.DataQualityConfig,
+	logger logger.Logger,
+) AnalysisRunService {
+	return &analysisRunService{
+		abcService:         abcService,
+		aprioriService:     aprioriService,
+		regressionService:  regressionService,
+		productRepo:        productRepo,
+		salesRepo:          salesRepo,
+		profitMarginRepo:   profitMarginRepo,
+		costRepo:           costRepo,
+		transactionRepo:    transactionRepo,
+		lifecycleRepo:      lifecycleRepo,
+		abcAnalysisRepo:    abcAnalysisRepo,
+		ruleRepo:           ruleRepo,
+		recommendationRepo: recommendationRepo,
+		abcSegmentRepo:     abcSegmentRepo,
+		runRepo:            runRepo,
+		quarantineRepo:     quarantineRepo,
+		transactor:         transactor,
+		eventService:       eventService,
+		dataQualityService: dataQualityService,
+		dataQualityConfig:  dataQualityConfig,
+		logger:             logger,
+	}
+}
+
+// RunABCAnalysis выполняет ABC-анализ и сохраняет результат, связанный с запуском
+func (s *analysisRunService) RunABCAnalysis(ctx context.Context, criteria entities.ABCAnalysisCriteria) (*entities.AnalysisRun, error) {
+	return s.runABCAnalysis(ctx, criteria, "")
+}
+
+// RunAssociationRules ищет ассоциативные правила по транзакциям периода и сохраняет их, связав с запуском
+func (s *analysisRunService) RunAssociationRules(ctx context.Context, params entities.AssociationRuleParams) (*entities.AnalysisRun, error) {
+	return s.runAssociationRules(ctx, params, "")
+}
+
+// RunDiscountRecommendations генерирует рекомендации по скидкам и сохраняет их, связав с запуском
+func (s *analysisRunService) RunDiscountRecommendations(ctx context.Context, params entities.DiscountRecommendationParams) (*entities.AnalysisRun, error) {
+	return s.runDiscountRecommendations(ctx, params, "")
+}
+
+// Rerun повторяет анализ с параметрами сохраненного запуска и сравнивает отпечаток входных данных с исходным
+func (s *analysisRunService) Rerun(ctx context.Context, runID string) (*entities.AnalysisRun, error) {
+	original, err := s.runRepo.GetRunByID(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis run: %w", err)
+	}
+	if original == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAnalysisRunNotFound, runID)
+	}
+
+	var run *entities.AnalysisRun
+	switch original.Type {
+	case entities.AnalysisABC:
+		var criteria entities.ABCAnalysisCriteria
+		if err := json.Unmarshal(original.Parameters, &criteria); err != nil {
+			return nil, fmt.Errorf("failed to decode parameters of run %s: %w", runID, err)
+		}
+		run, err = s.runABCAnalysis(ctx, criteria, original.ID)
+	case entities.AnalysisAssociationRules:
+		var params entities.AssociationRuleParams
+		if err := json.Unmarshal(original.Parameters, &params); err != nil {
+			return nil, fmt.Errorf("failed to decode parameters of run %s: %w", runID, err)
+		}
+		run, err = s.runAssociationRules(ctx, params, original.ID)
+	case entities.AnalysisDiscountRecommendations:
+		var params entities.DiscountRecommendationParams
+		if err := json.Unmarshal(original.Parameters, &params); err != nil {
+			return nil, fmt.Errorf("failed to decode parameters of run %s: %w", runID, err)
+		}
+		run, err = s.runDiscountRecommendations(ctx, params, original.ID)
+	default:
+		return nil, fmt.Errorf("%w: unknown analysis type %s", ErrInvalidParameter, original.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !*run.InputMatchesOriginal {
+		s.logger.Warn(ctx, "Входные данные повторного запуска отличаются от исходных", "runID", run.ID, "originalRunID", original.ID,
+			"inputHash", run.InputHash, "originalInputHash", original.InputHash)
+	}
+	return run, nil
+}
+
+// GetRun возвращает запуск по ID или nil, если запуск не найден
+func (s *analysisRunService) GetRun(ctx context.Context, runID string) (*entities.AnalysisRun, error) {
+	run, err := s.runRepo.GetRunByID(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis run: %w", err)
+	}
+	return run, nil
+}
+
+// GetRuns возвращает последние запуски анализа указанного вида, пустой вид — всех видов
+func (s *analysisRunService) GetRuns(ctx context.Context, analysisType entities.AnalysisType, limit int) ([]entities.AnalysisRun, error) {
+	if analysisType != "" && !analysisType.IsValid() {
+		return nil, fmt.Errorf("%w: unknown analysis type %s", ErrInvalidParameter, analysisType)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", ErrInvalidParameter)
+	}
+
+	runs, err := s.runRepo.GetRuns(ctx, analysisType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetQuarantined возвращает записи карантина запуска
+func (s *analysisRunService) GetQuarantined(ctx context.Context, runID string) ([]entities.QuarantinedRecord, error) {
+	run, err := s.runRepo.GetRunByID(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis run: %w", err)
+	}
+	if run == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAnalysisRunNotFound, runID)
+	}
+
+	records, err := s.quarantineRepo.GetQuarantined(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quarantined records: %w", err)
+	}
+	return records, nil
+}
+
+// runABCAnalysis выполняет ABC-анализ; входные данные — товары, продажи периода, маржа и история себестоимости товаров
+func (s *analysisRunService) runABCAnalysis(ctx context.Context, criteria entities.ABCAnalysisCriteria, rerunOf string) (*entities.AnalysisRun, error) {
+	if err := criteria.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	run, err := s.newRun(entities.AnalysisABC, criteria, rerunOf)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := newInputFingerprint()
+	products, err := s.productRepo.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+	if err := addFingerprintRows(fingerprint, "products", products); err != nil {
+		return nil, err
+	}
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, criteria.StartDate, criteria.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+	if err := addFingerprintRows(fingerprint, "sales", sales); err != nil {
+		return nil, err
+	}
+	margins, err := s.profitMarginRepo.GetProfitMargins(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve profit margins: %w", err)
+	}
+	marginRows := make([]productProfitMargin, 0, len(margins))
+	for productID, margin := range margins {
+		marginRows = append(marginRows, productProfitMargin{ProductID: productID, Margin: margin})
+	}
+	if err := addFingerprintRows(fingerprint, "profit_margins", marginRows); err != nil {
+		return nil, err
+	}
+	costs, err := s.costRepo.GetCostHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product cost history: %w", err)
+	}
+	if err := addFingerprintRows(fingerprint, "product_costs", costs); err != nil {
+		return nil, err
+	}
+
+	sales, quality, err := s.dataQualityService.CleanseSales(ctx, sales, s.dataQualityConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cleanse sales: %w", err)
+	}
+
+	// Анализ выполняется в транзакции, потому что сам сохраняет сегментацию товаров
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		previous, err := s.abcSegmentRepo.GetFullSegmentation(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get previous ABC segmentation: %w", err)
+		}
+
+		result, err := s.abcService.AnalyzeSales(ctx, criteria, products, sales, margins, costs)
+		if err != nil {
+			return fmt.Errorf("failed to perform ABC analysis: %w", err)
+		}
+
+		if err := s.finishRun(ctx, run, fingerprint, quality, len(result.ProductsSegmentation)); err != nil {
+			return err
+		}
+
+		result.RunID = run.ID
+		result.PeriodStart = criteria.StartDate
+		result.PeriodEnd = criteria.EndDate
+		if err := s.abcAnalysisRepo.SaveAnalysisResult(ctx, *result); err != nil {
+			return fmt.Errorf("failed to save ABC analysis result: %w", err)
+		}
+
+		payload := entities.ABCSegmentationPayload{
+			Segments: sortedSegments(result.ProductsSegmentation),
+			Changes:  segmentChanges(previous, result.ProductsSegmentation),
+		}
+		_, err = s.eventService.Enqueue(ctx, entities.EventStreamABCSegmentation, entities.EventStreamABCSegmentation.Regenerated(), run.ID, payload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logRun(ctx, run)
+	return run, nil
+}
+
+// runAssociationRules ищет ассоциативные правила; входные данные — транзакции периода
+func (s *analysisRunService) runAssociationRules(ctx context.Context, params entities.AssociationRuleParams, rerunOf string) (*entities.AnalysisRun, error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	run, err := s.newRun(entities.AnalysisAssociationRules, params, rerunOf)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, params.StartDate, params.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+	fingerprint := newInputFingerprint()
+	if err := addFingerprintRows(fingerprint, "transactions", transactions); err != nil {
+		return nil, err
+	}
+
+	transactions, quality, err := s.dataQualityService.CleanseTransactions(ctx, transactions, s.dataQualityConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cleanse transactions: %w", err)
+	}
+
+	rules, err := s.aprioriService.AnalyzeTransactions(ctx, transactions, params.MinSupport, params.MinConfidence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze transactions: %w", err)
+	}
+
+	for i := range rules {
+		rules[i].RunID = run.ID
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.finishRun(ctx, run, fingerprint, quality, len(rules)); err != nil {
+			return err
+		}
+		if err := s.ruleRepo.SaveRules(ctx, rules); err != nil {
+			return fmt.Errorf("failed to save association rules: %w", err)
+		}
+
+		payload := entities.AssociationRulesPayload{Rules: rules}
+		_, err := s.eventService.Enqueue(ctx, entities.EventStreamAssociationRules, entities.EventStreamAssociationRules.Regenerated(), run.ID, payload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logRun(ctx, run)
+	return run, nil
+}
+
+// runDiscountRecommendations генерирует рекомендации по скидкам;
+// входные данные — транзакции периода, каталог товаров и последняя классификация жизненного цикла
+func (s *analysisRunService) runDiscountRecommendations(ctx context.Context, params entities.DiscountRecommendationParams, rerunOf string) (*entities.AnalysisRun, error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	run, err := s.newRun(entities.AnalysisDiscountRecommendations, params, rerunOf)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := newInputFingerprint()
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, params.StartDate, params.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+	if err := addFingerprintRows(fingerprint, "transactions", transactions); err != nil {
+		return nil, err
+	}
+	products, err := s.productRepo.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+	if err := addFingerprintRows(fingerprint, "products", products); err != nil {
+		return nil, err
+	}
+	lifecycles, err := s.lifecycleRepo.GetLatestLifecycles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product lifecycles: %w", err)
+	}
+	if err := addFingerprintRows(fingerprint, "product_lifecycles", lifecycles); err != nil {
+		return nil, err
+	}
+
+	transactions, quality, err := s.dataQualityService.CleanseTransactions(ctx, transactions, s.dataQualityConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cleanse transactions: %w", err)
+	}
+
+	recommendations, err := s.regressionService.GenerateDiscountRecommendationsForTransactions(ctx, params.StartDate, params.EndDate, transactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate discount recommendations: %w", err)
+	}
+
+	payload := entities.DiscountRecommendationsPayload{
+		Recommendations: make([]entities.DiscountRecommendation, 0, len(recommendations)),
+	}
+	for _, recommendation := range recommendations {
+		recommendation.RunID = run.ID
+		recommendation.PeriodStart = params.StartDate
+		recommendation.PeriodEnd = params.EndDate
+		payload.Recommendations = append(payload.Recommendations, *recommendation)
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.finishRun(ctx, run, fingerprint, quality, len(recommendations)); err != nil {
+			return err
+		}
+		for _, recommendation := range payload.Recommendations {
+			if err := s.recommendationRepo.SaveRecommendation(ctx, recommendation); err != nil {
+				return fmt.Errorf("failed to save discount recommendation: %w", err)
+			}
+		}
+
+		_, err := s.eventService.Enqueue(ctx, entities.EventStreamDiscountRecommendations, entities.EventStreamDiscountRecommendations.Regenerated(), run.ID, payload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logRun(ctx, run)
+	return run, nil
+}
+
+// newRun создает запись о запуске с сериализованными параметрами и версией кода
+func (s *analysisRunService) newRun(analysisType entities.AnalysisType, params interface{}, rerunOf string) (*entities.AnalysisRun, error) {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode analysis parameters: %w", err)
+	}
+
+	startedAt := time.Now()
+	return &entities.AnalysisRun{
+		ID:          fmt.Sprintf("%s-%d", analysisType, startedAt.UnixNano()),
+		Type:        analysisType,
+		Parameters:  encoded,
+		CodeVersion: buildinfo.CodeVersion(),
+		StartedAt:   startedAt,
+		RerunOf:     rerunOf,
+	}, nil
+}
+
+// finishRun дополняет запуск отпечатком и итогами проверки входных данных и сохраняет его вместе с записями
+// карантина до сохранения результатов, чтобы каждый сохраненный результат ссылался на существующий запуск
+func (s *analysisRunService) finishRun(ctx context.Context, run *entities.AnalysisRun, fingerprint *inputFingerprint, quality *entities.DataQualityResult, outputCount int) error {
+	run.InputRowCounts = fingerprint.rowCounts()
+	run.InputHash = fingerprint.hash()
+	run.DataQuality = &quality.Report
+	run.OutputCount = outputCount
+	run.FinishedAt = time.Now()
+
+	if run.RerunOf != "" {
+		original, err := s.runRepo.GetRunByID(ctx, run.RerunOf)
+		if err != nil {
+			return fmt.Errorf("failed to get original analysis run: %w", err)
+		}
+		matches := original != nil && original.InputHash == run.InputHash
+		run.InputMatchesOriginal = &matches
+	}
+
+	if err := s.runRepo.SaveRun(ctx, *run); err != nil {
+		return fmt.Errorf("failed to save analysis run: %w", err)
+	}
+
+	if len(quality.Quarantined) > 0 {
+		for i := range quality.Quarantined {
+			quality.Quarantined[i].RunID = run.ID
+		}
+		if err := s.quarantineRepo.SaveQuarantined(ctx, quality.Quarantined); err != nil {
+			return fmt.Errorf("failed to save quarantined records: %w", err)
+		}
+	}
+	return nil
+}
+
+// logRun пишет в лог итог запуска анализа
+func (s *analysisRunService) logRun(ctx context.Context, run *entities.AnalysisRun) {
+	s.logger.Info(ctx, "Анализ выполнен", "runID", run.ID, "type", run.Type, "codeVersion", run.CodeVersion,
+		"inputHash", run.InputHash, "outputs", run.OutputCount, "rerunOf", run.RerunOf,
+		"dataQualityScore", run.DataQuality.Score)
+}
@@ -0,0 +1,228 @@
+// internal/infrastructure/services/analysis_run_service_test.go
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryAnalysisRunRepository хранит запуски анализа в памяти в порядке сохранения
+type memoryAnalysisRunRepository struct {
+	runs []entities.AnalysisRun
+}
+
+func (r *memoryAnalysisRunRepository) SaveRun(_ context.Context, run entities.AnalysisRun) error {
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+func (r *memoryAnalysisRunRepository) GetRunByID(_ context.Context, runID string) (*entities.AnalysisRun, error) {
+	for _, run := range r.runs {
+		if run.ID == runID {
+			return &run, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryAnalysisRunRepository) GetRuns(_ context.Context, analysisType entities.AnalysisType, limit int) ([]entities.AnalysisRun, error) {
+	var result []entities.AnalysisRun
+	for i := len(r.runs) - 1; i >= 0 && len(result) < limit; i-- {
+		if analysisType == "" || r.runs[i].Type == analysisType {
+			result = append(result, r.runs[i])
+		}
+	}
+	return result, nil
+}
+
+// memoryQuarantineRepository хранит записи карантина в памяти
+type memoryQuarantineRepository struct {
+	records []entities.QuarantinedRecord
+}
+
+func (r *memoryQuarantineRepository) SaveQuarantined(_ context.Context, records []entities.QuarantinedRecord) error {
+	r.records = append(r.records, records...)
+	return nil
+}
+
+func (r *memoryQuarantineRepository) GetQuarantined(_ context.Context, runID string) ([]entities.QuarantinedRecord, error) {
+	var result []entities.QuarantinedRecord
+	for _, record := range r.records {
+		if record.RunID == runID {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+// directTransactor выполняет операции без транзакции
+type directTransactor struct{}
+
+func (directTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// recordingEventService запоминает поставленные в очередь события
+type recordingEventService struct {
+	runIDs []string
+}
+
+func (s *recordingEventService) Enqueue(_ context.Context, stream entities.EventStream, eventType entities.EventType, runID string, _ interface{}) (*entities.AnalyticsEvent, error) {
+	s.runIDs = append(s.runIDs, runID)
+	return &entities.AnalyticsEvent{Stream: stream, Type: eventType, RunID: runID}, nil
+}
+
+func (s *recordingEventService) PublishSnapshot(_ context.Context, stream entities.EventStream) (*entities.AnalyticsEvent, error) {
+	return &entities.AnalyticsEvent{Stream: stream}, nil
+}
+
+// testRuleBaskets возвращает чеки, в которых A и B покупаются вместе в трех из четырех случаев
+func testRuleBaskets(day time.Time) []entities.Transaction {
+	return []entities.Transaction{
+		testTransaction("T1", day.Add(10*time.Hour), 2, "A", "B"),
+		testTransaction("T2", day.Add(11*time.Hour), 2, "A", "B"),
+		testTransaction("T3", day.Add(12*time.Hour), 3, "A", "B", "C"),
+		testTransaction("T4", day.Add(13*time.Hour), 1, "C"),
+	}
+}
+
+// testAnalysisRunService возвращает сервис запусков, которому для поиска правил нужны только чеки
+func testAnalysisRunService(transactions *memoryTransactionRepository) (services.AnalysisRunService, *memoryAnalysisRunRepository, *memoryRuleRepository, *recordingEventService) {
+	runs := &memoryAnalysisRunRepository{}
+	rules := &memoryRuleRepository{}
+	events := &recordingEventService{}
+	log := logger.NewLogger("ERROR")
+
+	service := services.NewAnalysisRunService(nil, services.NewAprioriService(log), nil, nil, nil, nil, nil,
+		transactions, nil, nil, rules, nil, nil, runs, &memoryQuarantineRepository{}, directTransactor{}, events,
+		services.NewDataQualityService(log), entities.DefaultDataQualityConfig(), log)
+	return service, runs, rules, events
+}
+
+func TestRunAssociationRules(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	params := entities.AssociationRuleParams{StartDate: day, EndDate: day.AddDate(0, 0, 1), MinSupport: 0.5, MinConfidence: 0.6}
+	transactions := &memoryTransactionRepository{transactions: testRuleBaskets(day)}
+	service, runs, rules, events := testAnalysisRunService(transactions)
+
+	run, err := service.RunAssociationRules(context.Background(), params)
+	if err != nil {
+		t.Fatalf("RunAssociationRules() error = %v", err)
+	}
+
+	// A -> B и B -> A: поддержка 0.75, достоверность 1
+	if run.Type != entities.AnalysisAssociationRules || run.OutputCount != 2 || len(rules.rules) != 2 {
+		t.Fatalf("run = %s with %d outputs, saved rules = %d, want association_rules with 2", run.Type, run.OutputCount, len(rules.rules))
+	}
+	for _, rule := range rules.rules {
+		if rule.RunID != run.ID {
+			t.Errorf("rule run ID = %q, want %q", rule.RunID, run.ID)
+		}
+	}
+	if run.InputRowCounts["transactions"] != 4 || len(run.InputHash) != 64 || run.CodeVersion == "" {
+		t.Errorf("input = %v hash %q version %q, want 4 transactions and SHA-256", run.InputRowCounts, run.InputHash, run.CodeVersion)
+	}
+	if run.DataQuality == nil || run.RerunOf != "" || run.InputMatchesOriginal != nil || run.FinishedAt.Before(run.StartedAt) {
+		t.Errorf("run = %+v, want first run with data quality report", run)
+	}
+	var saved entities.AssociationRuleParams
+	if err := json.Unmarshal(run.Parameters, &saved); err != nil || saved.MinSupport != 0.5 || !saved.StartDate.Equal(day) {
+		t.Errorf("parameters = %s (%v), want the run parameters", run.Parameters, err)
+	}
+	if len(runs.runs) != 1 || len(events.runIDs) != 1 || events.runIDs[0] != run.ID {
+		t.Errorf("saved runs = %d, events = %v, want one of each for %s", len(runs.runs), events.runIDs, run.ID)
+	}
+
+	tests := []struct {
+		name         string
+		transactions []entities.Transaction
+		wantMatch    bool
+	}{
+		{name: "same input", transactions: testRuleBaskets(day), wantMatch: true},
+		// Хэш не зависит от порядка, в котором репозиторий вернул чеки
+		{name: "reordered input", transactions: append(testRuleBaskets(day)[2:], testRuleBaskets(day)[:2]...), wantMatch: true},
+		{name: "changed input", transactions: append(testRuleBaskets(day), testTransaction("T5", day.Add(14*time.Hour), 2, "B", "C"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactions.transactions = tt.transactions
+			rerun, err := service.Rerun(context.Background(), run.ID)
+			if err != nil {
+				t.Fatalf("Rerun() error = %v", err)
+			}
+
+			if rerun.ID == run.ID || rerun.RerunOf != run.ID || string(rerun.Parameters) != string(run.Parameters) {
+				t.Errorf("rerun = %s of %q with %s, want new run of %s with the same parameters",
+					rerun.ID, rerun.RerunOf, rerun.Parameters, run.ID)
+			}
+			if rerun.InputMatchesOriginal == nil || *rerun.InputMatchesOriginal != tt.wantMatch {
+				t.Fatalf("input matches original = %v, want %v", rerun.InputMatchesOriginal, tt.wantMatch)
+			}
+			if (rerun.InputHash == run.InputHash) != tt.wantMatch {
+				t.Errorf("input hash = %s, original %s, want match %v", rerun.InputHash, run.InputHash, tt.wantMatch)
+			}
+		})
+	}
+
+	latest, err := service.GetRuns(context.Background(), entities.AnalysisAssociationRules, 2)
+	if err != nil {
+		t.Fatalf("GetRuns() error = %v", err)
+	}
+	if len(runs.runs) != 4 || len(latest) != 2 || latest[0].ID != runs.runs[3].ID || latest[1].ID != runs.runs[2].ID {
+		t.Errorf("latest runs = %d, want the last two reruns newest first", len(latest))
+	}
+}
+
+func TestAnalysisRunServiceErrors(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	service, _, _, _ := testAnalysisRunService(&memoryTransactionRepository{transactions: testRuleBaskets(day)})
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{name: "zero min support", call: func() error {
+			_, err := service.RunAssociationRules(context.Background(), entities.AssociationRuleParams{
+				StartDate: day, EndDate: day.AddDate(0, 0, 1), MinConfidence: 0.5})
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "reversed recommendation period", call: func() error {
+			_, err := service.RunDiscountRecommendations(context.Background(), entities.DiscountRecommendationParams{
+				StartDate: day, EndDate: day.AddDate(0, 0, -1)})
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "rerun unknown run", call: func() error {
+			_, err := service.Rerun(context.Background(), "abc-1")
+			return err
+		}, want: services.ErrAnalysisRunNotFound},
+		{name: "quarantine of unknown run", call: func() error {
+			_, err := service.GetQuarantined(context.Background(), "abc-1")
+			return err
+		}, want: services.ErrAnalysisRunNotFound},
+		{name: "unknown analysis type", call: func() error {
+			_, err := service.GetRuns(context.Background(), "forecast", 10)
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "non-positive limit", call: func() error {
+			_, err := service.GetRuns(context.Background(), "", 0)
+			return err
+		}, want: services.ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
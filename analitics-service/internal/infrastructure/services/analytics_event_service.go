@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+)
+
+// AnalyticsEventService определяет интерфейс публикации событий аналитики через таблицу исходящих событий
+// События попадают в Kafka только после фиксации транзакции, в которой они поставлены в очередь
+type AnalyticsEventService interface {
+	// Enqueue ставит в очередь событие потока с payload
+	// Вызывается внутри Transactor.WithinTransaction вместе с сохранением данных, которые описывает событие
+	Enqueue(ctx context.Context, stream entities.EventStream, eventType entities.EventType, runID string, payload interface{}) (*entities.AnalyticsEvent, error)
+
+	// PublishSnapshot ставит в очередь снимок текущего состояния потока
+	// С последнего снимка потребитель может восстановить состояние, не читая поток с начала
+	PublishSnapshot(ctx context.Context, stream entities.EventStream) (*entities.AnalyticsEvent, error)
+}
+
+// analyticsEventService реализует интерфейс AnalyticsEventService
+type analyticsEventService struct {
+	transactor         repositories.Transactor
+	outboxRepo         repositories.OutboxRepository
+	runRepo            repositories.AnalysisRunRepository
+	abcSegmentRepo     repositories.ABCSegmentRepository
+	ruleRepo           repositories.AssociationRuleRepository
+	recommendationRepo repositories.DiscountRecommendationRepository
+	logger             logger.Logger
+}
+
+// NewAnalyticsEventService создает новый экземпляр сервиса событий аналитики
+func NewAnalyticsEventService(
+	transactor repositories.Transactor,
+	outboxRepo repositories.OutboxRepository,
+	runRepo repositories.AnalysisRunRepository,
+	abcSegmentRepo repositories.ABCSegmentRepository,
+	ruleRepo repositories.AssociationRuleRepository,
+	recommendationRepo repositories.DiscountRecommendationRepository,
+	logger logger.Logger,
+) AnalyticsEventService {
+	return &analyticsEventService{
+		transactor:         transactor,
+		outboxRepo:         outboxRepo,
+		runRepo:            runRepo,
+		abcSegmentRepo:     abcSegmentRepo,
+		ruleRepo:           ruleRepo,
+		recommendationRepo: recommendationRepo,
+		logger:             logger,
+	}
+}
+
+// Enqueue ставит в очередь событие потока с payload
+func (s *analyticsEventService) Enqueue(ctx context.Context, stream entities.EventStream, eventType entities.EventType, runID string, payload interface{}) (*entities.AnalyticsEvent, error) {
+	encodedPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", eventType, err)
+	}
+
+	occurredAt := time.Now()
+	event := &entities.AnalyticsEvent{
+		ID:         fmt.Sprintf("%s-%d", eventType, occurredAt.UnixNano()),
+		Type:       eventType,
+		Version:    entities.AnalyticsEventVersion,
+		Stream:     stream,
+		RunID:      runID,
+		OccurredAt: occurredAt,
+		Payload:    encodedPayload,
+	}
+	encodedEvent, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	outboxEvent := entities.OutboxEvent{
+		EventID:   event.ID,
+		Type:      eventType,
+		Key:       string(stream),
+		Payload:   encodedEvent,
+		CreatedAt: occurredAt,
+	}
+	if err := s.outboxRepo.Enqueue(ctx, []entities.OutboxEvent{outboxEvent}); err != nil {
+		return nil, fmt.Errorf("failed to enqueue %s event: %w", eventType, err)
+	}
+	return event, nil
+}
+
+// PublishSnapshot ставит в очередь снимок текущего состояния потока
+// Состояние читается в той же транзакции, что и постановка в очередь, поэтому снимок согласован
+// с событиями regenerated, зафиксированными до и после него
+func (s *analyticsEventService) PublishSnapshot(ctx context.Context, stream entities.EventStream) (*entities.AnalyticsEvent, error) {
+	if !stream.IsValid() {
+		return nil, fmt.Errorf("%w: unknown event stream %s", ErrInvalidParameter, stream)
+	}
+
+	var event *entities.AnalyticsEvent
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		runID, payload, err := s.currentState(ctx, stream)
+		if err != nil {
+			return err
+		}
+		event, err = s.Enqueue(ctx, stream, stream.Snapshot(), runID, payload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "Снимок потока событий поставлен в очередь", "stream", stream, "eventID", event.ID, "runID", event.RunID)
+	return event, nil
+}
+
+// currentState возвращает текущее состояние набора данных потока и запуск анализа, которым оно получено
+func (s *analyticsEventService) currentState(ctx context.Context, stream entities.EventStream) (string, interface{}, error) {
+	switch stream {
+	case entities.EventStreamABCSegmentation:
+		segmentation, err := s.abcSegmentRepo.GetFullSegmentation(ctx)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get ABC segmentation: %w", err)
+		}
+		runID, err := s.latestRunID(ctx, entities.AnalysisABC)
+		if err != nil {
+			return "", nil, err
+		}
+		return runID, entities.ABCSegmentationPayload{Segments: sortedSegments(segmentation)}, nil
+
+	case entities.EventStreamAssociationRules:
+		runID, err := s.latestRunID(ctx, entities.AnalysisAssociationRules)
+		if err != nil {
+			return "", nil, err
+		}
+		// Правила без запуска сохранены до появления учета запусков и входят в снимок, только если запусков еще не было
+		payload := entities.AssociationRulesPayload{Rules: []entities.AssociationRule{}}
+		err = s.ruleRepo.StreamRules(ctx, func(rule entities.AssociationRule) error {
+			if runID == "" || rule.RunID == runID {
+				payload.Rules = append(payload.Rules, rule)
+			}
+			return nil
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read association rules: %w", err)
+		}
+		return runID, payload, nil
+
+	default:
+		runID, err := s.latestRunID(ctx, entities.AnalysisDiscountRecommendations)
+		if err != nil {
+			return "", nil, err
+		}
+		payload := entities.DiscountRecommendationsPayload{Recommendations: []entities.DiscountRecommendation{}}
+		if runID == "" {
+			return "", payload, nil
+		}
+		recommendations, err := s.recommendationRepo.GetRecommendationsByRunID(ctx, runID)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get discount recommendations: %w", err)
+		}
+		payload.Recommendations = append(payload.Recommendations, recommendations...)
+		return runID, payload, nil
+	}
+}
+
+// latestRunID возвращает ID последнего запуска анализа указанного вида или пустую строку, если запусков не было
+func (s *analyticsEventService) latestRunID(ctx context.Context, analysisType entities.AnalysisType) (string, error) {
+	runs, err := s.runRepo.GetRuns(ctx, analysisType, 1)
+	if err != nil {
+		return "", fmt.Errorf("failed to get latest %s run: %w", analysisType, err)
+	}
+	if len(runs) == 0 {
+		return "", nil
+	}
+	return runs[0].ID, nil
+}
+
+// sortedSegments возвращает сегментацию товаров, упорядоченную по ID товара
+func sortedSegments(segmentation map[string]entities.ProductFullSegmentation) []entities.ProductFullSegmentation {
+	segments := make([]entities.ProductFullSegmentation, 0, len(segmentation))
+	for _, productID := range sortedGroupKeys(segmentation) {
+		segments = append(segments, segmentation[productID])
+	}
+	return segments
+}
+
+// segmentChanges возвращает товары, итоговый сегмент которых отличается между previous и current,
+// включая новые и выбывшие товары
+func segmentChanges(previous, current map[string]entities.ProductFullSegmentation) []entities.SegmentChange {
+	var changes []entities.SegmentChange
+	for _, productID := range sortedGroupKeys(current) {
+		before, existed := previous[productID]
+		if existed && before.FinalSegment == current[productID].FinalSegment {
+			continue
+		}
+		changes = append(changes, entities.SegmentChange{
+			ProductID:       productID,
+			PreviousSegment: before.FinalSegment,
+			Segment:         current[productID].FinalSegment,
+		})
+	}
+	for _, productID := range sortedGroupKeys(previous) {
+		if _, exists := current[productID]; !exists {
+			changes = append(changes, entities.SegmentChange{
+				ProductID:       productID,
+				PreviousSegment: previous[productID].FinalSegment,
+			})
+		}
+	}
+	return changes
+}
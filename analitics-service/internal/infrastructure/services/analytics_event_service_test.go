@@ -0,0 +1,201 @@
+// internal/infrastructure/services/analytics_event_service_test.go
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryOutboxRepository хранит исходящие события в памяти, присваивая им порядковые номера
+type memoryOutboxRepository struct {
+	events []entities.OutboxEvent
+}
+
+func (r *memoryOutboxRepository) Enqueue(_ context.Context, events []entities.OutboxEvent) error {
+	for _, event := range events {
+		event.Sequence = int64(len(r.events) + 1)
+		r.events = append(r.events, event)
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepository) GetPending(_ context.Context, limit int) ([]entities.OutboxEvent, error) {
+	var result []entities.OutboxEvent
+	for _, event := range r.events {
+		if event.PublishedAt == nil && len(result) < limit {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryOutboxRepository) MarkPublished(_ context.Context, sequences []int64) error {
+	now := time.Now()
+	for _, sequence := range sequences {
+		r.events[sequence-1].PublishedAt = &now
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepository) MarkFailed(_ context.Context, sequence int64, publishErr string) error {
+	r.events[sequence-1].Attempts++
+	r.events[sequence-1].LastError = publishErr
+	return nil
+}
+
+func (r *memoryOutboxRepository) DeletePublishedBefore(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+// testEventPayload объединяет содержимое событий всех потоков для проверки
+type testEventPayload struct {
+	Segments        []entities.ProductFullSegmentation `json:"segments"`
+	Rules           []entities.AssociationRule         `json:"rules"`
+	Recommendations []entities.DiscountRecommendation  `json:"recommendations"`
+}
+
+// decodeOutboxEvent возвращает конверт события из записи исходящих сообщений и его содержимое
+func decodeOutboxEvent(t *testing.T, outboxEvent entities.OutboxEvent) (entities.AnalyticsEvent, testEventPayload) {
+	t.Helper()
+
+	var event entities.AnalyticsEvent
+	if err := json.Unmarshal(outboxEvent.Payload, &event); err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+	var payload testEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	return event, payload
+}
+
+func TestEnqueueAnalyticsEvent(t *testing.T) {
+	outbox := &memoryOutboxRepository{}
+	service := services.NewAnalyticsEventService(directTransactor{}, outbox, &memoryAnalysisRunRepository{},
+		&memorySegmentRepository{}, &memoryRuleRepository{}, &memoryRecommendationRepository{}, logger.NewLogger("ERROR"))
+
+	stream := entities.EventStreamDiscountRecommendations
+	payload := entities.DiscountRecommendationsPayload{Recommendations: []entities.DiscountRecommendation{{ProductID: "P1", OptimalDiscount: 15}}}
+	event, err := service.Enqueue(context.Background(), stream, stream.Regenerated(), "run-1", payload)
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	if len(outbox.events) != 1 {
+		t.Fatalf("outbox events = %d, want 1", len(outbox.events))
+	}
+	saved := outbox.events[0]
+	if saved.EventID != event.ID || saved.Key != string(stream) || saved.Type != "discount_recommendations.regenerated" {
+		t.Errorf("outbox event = %s key %s type %s, want %s keyed by stream", saved.EventID, saved.Key, saved.Type, event.ID)
+	}
+
+	// В таблицу записывается полный конверт события, который публикуется без изменений
+	envelope, decoded := decodeOutboxEvent(t, saved)
+	if envelope.ID != event.ID || envelope.Version != entities.AnalyticsEventVersion || envelope.RunID != "run-1" || envelope.Stream != stream {
+		t.Errorf("envelope = %+v, want event %s of run-1 with version %d", envelope, event.ID, entities.AnalyticsEventVersion)
+	}
+	if len(decoded.Recommendations) != 1 || decoded.Recommendations[0].ProductID != "P1" || decoded.Recommendations[0].OptimalDiscount != 15 {
+		t.Errorf("payload = %+v, want the P1 recommendation", decoded.Recommendations)
+	}
+}
+
+func TestPublishSnapshot(t *testing.T) {
+	rules := []entities.AssociationRule{testRule("A", "B", 2), testRule("A", "C", 2), testRule("B", "C", 2)}
+	rules[1].RunID = "rules-1"
+	rules[2].RunID = "rules-2"
+
+	recommendation := func(productID, runID string) entities.DiscountRecommendation {
+		recommendation := entities.DiscountRecommendation{ProductID: productID}
+		recommendation.RunID = runID
+		return recommendation
+	}
+
+	runs := []entities.AnalysisRun{
+		{ID: "abc-1", Type: entities.AnalysisABC},
+		{ID: "rules-1", Type: entities.AnalysisAssociationRules},
+		{ID: "discounts-1", Type: entities.AnalysisDiscountRecommendations},
+		{ID: "rules-2", Type: entities.AnalysisAssociationRules},
+	}
+
+	tests := []struct {
+		name      string
+		stream    entities.EventStream
+		runs      []entities.AnalysisRun
+		wantRunID string
+		// Идентификаторы товаров, правил (по консеквенту) или рекомендаций в снимке
+		wantItems []string
+	}{
+		{name: "abc segmentation sorted by product", stream: entities.EventStreamABCSegmentation, runs: runs,
+			wantRunID: "abc-1", wantItems: []string{"A", "B", "C"}},
+		// Снимок содержит только правила последнего запуска
+		{name: "rules of latest run", stream: entities.EventStreamAssociationRules, runs: runs,
+			wantRunID: "rules-2", wantItems: []string{"C"}},
+		// Без запусков в снимок входят правила, сохраненные до учета запусков
+		{name: "rules without runs", stream: entities.EventStreamAssociationRules, wantItems: []string{"B", "C", "C"}},
+		{name: "discounts of latest run", stream: entities.EventStreamDiscountRecommendations, runs: runs,
+			wantRunID: "discounts-1", wantItems: []string{"P2", "P3"}},
+		{name: "discounts without runs", stream: entities.EventStreamDiscountRecommendations, wantItems: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outbox := &memoryOutboxRepository{}
+			segments := &memorySegmentRepository{segments: map[string]entities.Segment{
+				"C": entities.SegmentC, "A": entities.SegmentA, "B": entities.SegmentB}}
+			recommendations := &memoryRecommendationRepository{recommendations: []entities.DiscountRecommendation{
+				recommendation("P1", ""), recommendation("P2", "discounts-1"), recommendation("P3", "discounts-1")}}
+			service := services.NewAnalyticsEventService(directTransactor{}, outbox, &memoryAnalysisRunRepository{runs: tt.runs},
+				segments, &memoryRuleRepository{rules: rules}, recommendations, logger.NewLogger("ERROR"))
+
+			event, err := service.PublishSnapshot(context.Background(), tt.stream)
+			if err != nil {
+				t.Fatalf("PublishSnapshot() error = %v", err)
+			}
+			if event.Type != tt.stream.Snapshot() || event.RunID != tt.wantRunID || len(outbox.events) != 1 {
+				t.Fatalf("event = %s of run %q, outbox = %d, want %s of run %q", event.Type, event.RunID,
+					len(outbox.events), tt.stream.Snapshot(), tt.wantRunID)
+			}
+
+			_, payload := decodeOutboxEvent(t, outbox.events[0])
+			items := []string{}
+			for _, segment := range payload.Segments {
+				items = append(items, segment.ProductID)
+			}
+			for _, rule := range payload.Rules {
+				items = append(items, rule.Consequent[0].ProductID)
+			}
+			for _, recommendation := range payload.Recommendations {
+				items = append(items, recommendation.ProductID)
+			}
+			if len(items) != len(tt.wantItems) {
+				t.Fatalf("snapshot items = %v, want %v", items, tt.wantItems)
+			}
+			for i := range items {
+				if items[i] != tt.wantItems[i] {
+					t.Errorf("snapshot items = %v, want %v", items, tt.wantItems)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestPublishSnapshotUnknownStream(t *testing.T) {
+	outbox := &memoryOutboxRepository{}
+	service := services.NewAnalyticsEventService(directTransactor{}, outbox, &memoryAnalysisRunRepository{},
+		&memorySegmentRepository{}, &memoryRuleRepository{}, &memoryRecommendationRepository{}, logger.NewLogger("ERROR"))
+
+	if _, err := service.PublishSnapshot(context.Background(), "forecasts"); !errors.Is(err, services.ErrInvalidParameter) {
+		t.Errorf("PublishSnapshot() error = %v, want %v", err, services.ErrInvalidParameter)
+	}
+	if len(outbox.events) != 0 {
+		t.Errorf("outbox events = %d, want none", len(outbox.events))
+	}
+}
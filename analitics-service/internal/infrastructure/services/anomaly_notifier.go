@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// AnomalyNotifier интерфейс для отправки уведомлений об аномалиях продаж
+// Реализации каналов доставки находятся в пакете interfaces/notifier
+type AnomalyNotifier interface {
+	Notify(ctx context.Context, anomalies []entities.SalesAnomaly) error
+}
+
+// MultiNotifier рассылает уведомления через несколько каналов
+type MultiNotifier struct {
+	notifiers []AnomalyNotifier
+}
+
+// NewMultiNotifier создает новый экземпляр MultiNotifier
+func NewMultiNotifier(notifiers ...AnomalyNotifier) AnomalyNotifier {
+	return &MultiNotifier{notifiers: notifiers}
+}
+
+// Notify отправляет уведомления во все каналы, ошибка одного канала не прерывает отправку в остальные
+func (n *MultiNotifier) Notify(ctx context.Context, anomalies []entities.SalesAnomaly) error {
+	var errs []error
+	for _, notifier := range n.notifiers {
+		if err := notifier.Notify(ctx, anomalies); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,371 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+	"analitics-service/pkg/stats"
+)
+
+// Коэффициенты приведения MAD и среднего абсолютного отклонения к стандартному отклонению
+const (
+	madScale     = 1.4826
+	meanAbsScale = 1.2533
+)
+
+// AnomalyService определяет интерфейс обнаружения аномалий продаж
+type AnomalyService interface {
+	// DetectDaily ищет аномалии в дневных продажах магазина и товаров за последние дни перед asOf
+	DetectDaily(ctx context.Context, asOf time.Time, config entities.AnomalyDetectionConfig) ([]entities.SalesAnomaly, error)
+
+	// DetectHourly ищет аномалии в почасовых продажах магазина за последние дни перед asOf
+	DetectHourly(ctx context.Context, asOf time.Time, config entities.AnomalyDetectionConfig) ([]entities.SalesAnomaly, error)
+
+	// GetAnomalies возвращает сохраненные аномалии за период с серьезностью не ниже указанной
+	GetAnomalies(ctx context.Context, startDate, endDate time.Time, minSeverity entities.AnomalySeverity) ([]entities.SalesAnomaly, error)
+}
+
+// anomalyService реализует интерфейс AnomalyService
+type anomalyService struct {
+	salesRepo   repositories.SalesRepository
+	anomalyRepo repositories.AnomalyRepository
+	notifier    AnomalyNotifier
+	logger      logger.Logger
+}
+
+// seriesPoint представляет точку временного ряда с сезонным индексом и средней скидкой
+type seriesPoint struct {
+	at       time.Time
+	value    float64
+	discount float64
+	season   int
+}
+
+// seriesKey идентифицирует временной ряд для поиска аномалий
+type seriesKey struct {
+	productID string
+	metric    string
+}
+
+// NewAnomalyService создает новый экземпляр сервиса обнаружения аномалий
+func NewAnomalyService(
+	salesRepo repositories.SalesRepository,
+	anomalyRepo repositories.AnomalyRepository,
+	notifier AnomalyNotifier,
+	logger logger.Logger,
+) AnomalyService {
+	return &anomalyService{
+		salesRepo:   salesRepo,
+		anomalyRepo: anomalyRepo,
+		notifier:    notifier,
+		logger:      logger,
+	}
+}
+
+// DetectDaily ищет аномалии в дневных продажах магазина и товаров
+func (s *anomalyService) DetectDaily(ctx context.Context, asOf time.Time, config entities.AnomalyDetectionConfig) ([]entities.SalesAnomaly, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	end := truncateToDay(asOf)
+	detectFrom := end.AddDate(0, 0, -config.DetectDays)
+	start := detectFrom.AddDate(0, 0, -config.LookbackDays)
+	days := int(end.Sub(start).Hours() / 24)
+
+	daily, err := s.salesRepo.GetDailySalesData(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve daily sales data: %w", err)
+	}
+
+	// Пропущенные дни заполняются нулями: полное отсутствие продаж тоже аномалия
+	series := map[seriesKey][]seriesPoint{
+		{metric: "units"}:        newDailySeries(start, days),
+		{metric: "revenue"}:      newDailySeries(start, days),
+		{metric: "transactions"}: newDailySeries(start, days),
+	}
+	for _, data := range daily {
+		day := int(truncateToDay(data.Date).Sub(start).Hours() / 24)
+		if day < 0 || day >= days {
+			continue
+		}
+		for key, value := range map[string]float64{"units": data.Sales, "revenue": data.TotalPrice, "transactions": float64(data.TotalTx)} {
+			points := series[seriesKey{metric: key}]
+			points[day].value += value
+			points[day].discount = data.AvgDiscount
+		}
+	}
+
+	if config.IncludeProducts {
+		sales, err := s.salesRepo.GetSalesByPeriod(ctx, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+		}
+
+		discountUnits := make(map[seriesKey][]float64)
+		for _, sale := range sales {
+			day := int(truncateToDay(sale.PurchaseDate).Sub(start).Hours() / 24)
+			if day < 0 || day >= days {
+				continue
+			}
+			key := seriesKey{productID: sale.ProductID, metric: "units"}
+			if _, ok := series[key]; !ok {
+				series[key] = newDailySeries(start, days)
+				discountUnits[key] = make([]float64, days)
+			}
+			series[key][day].value += float64(sale.Quantity)
+			discountUnits[key][day] += sale.DiscountRate * float64(sale.Quantity)
+		}
+		for key, weighted := range discountUnits {
+			for day, sum := range weighted {
+				if units := series[key][day].value; units > 0 {
+					series[key][day].discount = sum / units
+				}
+			}
+		}
+	}
+
+	return s.detectAndReport(ctx, series, detectFrom, entities.GranularityDaily, config)
+}
+
+// DetectHourly ищет аномалии в почасовых продажах магазина
+// В сезонном режиме нормальный уровень оценивается отдельно для каждого часа суток
+func (s *anomalyService) DetectHourly(ctx context.Context, asOf time.Time, config entities.AnomalyDetectionConfig) ([]entities.SalesAnomaly, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	end := asOf.Truncate(time.Hour)
+	detectFrom := end.Add(-time.Duration(config.DetectDays) * 24 * time.Hour)
+	start := detectFrom.Add(-time.Duration(config.LookbackDays) * 24 * time.Hour)
+	hours := int(end.Sub(start).Hours())
+
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	newSeries := func() []seriesPoint {
+		points := make([]seriesPoint, hours)
+		for i := range points {
+			points[i].at = start.Add(time.Duration(i) * time.Hour)
+			points[i].season = points[i].at.Hour()
+		}
+		return points
+	}
+	revenue, transactions := newSeries(), newSeries()
+	units := make([]float64, hours)
+	discountUnits := make([]float64, hours)
+	seen := make([]map[string]bool, hours)
+
+	for _, sale := range sales {
+		hour := int(sale.PurchaseDate.Sub(start).Hours())
+		if hour < 0 || hour >= hours {
+			continue
+		}
+		revenue[hour].value += saleRevenue(sale)
+		units[hour] += float64(sale.Quantity)
+		discountUnits[hour] += sale.DiscountRate * float64(sale.Quantity)
+		if seen[hour] == nil {
+			seen[hour] = make(map[string]bool)
+		}
+		if !seen[hour][sale.TransactionID] {
+			seen[hour][sale.TransactionID] = true
+			transactions[hour].value++
+		}
+	}
+
+	for hour := range units {
+		if units[hour] > 0 {
+			revenue[hour].discount = discountUnits[hour] / units[hour]
+			transactions[hour].discount = revenue[hour].discount
+		}
+	}
+
+	series := map[seriesKey][]seriesPoint{
+		{metric: "revenue"}:      revenue,
+		{metric: "transactions"}: transactions,
+	}
+	return s.detectAndReport(ctx, series, detectFrom, entities.GranularityHourly, config)
+}
+
+// GetAnomalies возвращает сохраненные аномалии за период с серьезностью не ниже указанной
+func (s *anomalyService) GetAnomalies(ctx context.Context, startDate, endDate time.Time, minSeverity entities.AnomalySeverity) ([]entities.SalesAnomaly, error) {
+	if minSeverity == "" {
+		minSeverity = entities.SeverityLow
+	}
+	if minSeverity.Rank() == 0 {
+		return nil, fmt.Errorf("%w: invalid severity %s", ErrInvalidParameter, minSeverity)
+	}
+
+	anomalies, err := s.anomalyRepo.GetAnomaliesByPeriod(ctx, startDate, endDate, minSeverity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve anomalies: %w", err)
+	}
+	return anomalies, nil
+}
+
+// detectAndReport ищет аномалии во всех рядах, сохраняет их и отправляет неподавленные уведомления
+func (s *anomalyService) detectAndReport(
+	ctx context.Context,
+	series map[seriesKey][]seriesPoint,
+	detectFrom time.Time,
+	granularity entities.AnomalyGranularity,
+	config entities.AnomalyDetectionConfig,
+) ([]entities.SalesAnomaly, error) {
+	now := time.Now()
+	var anomalies []entities.SalesAnomaly
+
+	for key, points := range series {
+		for _, anomaly := range detectSeriesAnomalies(points, detectFrom, config) {
+			anomaly.ProductID = key.productID
+			anomaly.Metric = key.metric
+			anomaly.Granularity = granularity
+			anomaly.DetectedAt = now
+
+			scope := key.productID
+			if scope == "" {
+				scope = "store"
+			}
+			anomaly.ID = fmt.Sprintf("%s:%s:%s:%s", granularity, scope, key.metric, anomaly.PeriodStart.Format(time.RFC3339))
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		if !anomalies[i].PeriodStart.Equal(anomalies[j].PeriodStart) {
+			return anomalies[i].PeriodStart.Before(anomalies[j].PeriodStart)
+		}
+		return anomalies[i].ID < anomalies[j].ID
+	})
+
+	if len(anomalies) == 0 {
+		return anomalies, nil
+	}
+
+	if err := s.anomalyRepo.SaveAnomalies(ctx, anomalies); err != nil {
+		return nil, fmt.Errorf("failed to save anomalies: %w", err)
+	}
+
+	var alerts []entities.SalesAnomaly
+	for _, anomaly := range anomalies {
+		if !anomaly.Suppressed {
+			alerts = append(alerts, anomaly)
+		}
+	}
+
+	// Аномалии уже сохранены, поэтому ошибка доставки уведомления не прерывает обнаружение
+	if len(alerts) > 0 {
+		if err := s.notifier.Notify(ctx, alerts); err != nil {
+			s.logger.Warn(ctx, "Не удалось отправить уведомления об аномалиях", "count", len(alerts), "error", err)
+		}
+	}
+
+	s.logger.Info(ctx, "Поиск аномалий завершен", "granularity", granularity,
+		"anomalies", len(anomalies), "alerts", len(alerts))
+	return anomalies, nil
+}
+
+// detectSeriesAnomalies оценивает точки ряда начиная с detectFrom по истории до detectFrom
+func detectSeriesAnomalies(points []seriesPoint, detectFrom time.Time, config entities.AnomalyDetectionConfig) []entities.SalesAnomaly {
+	var history, evaluated []seriesPoint
+	for _, point := range points {
+		if point.at.Before(detectFrom) {
+			history = append(history, point)
+		} else {
+			evaluated = append(evaluated, point)
+		}
+	}
+	if len(history) < config.MinHistoryPoints || len(evaluated) == 0 {
+		return nil
+	}
+
+	values := make([]float64, len(history))
+	discounts := make([]float64, len(history))
+	bySeason := make(map[int][]float64)
+	for i, point := range history {
+		values[i] = point.value
+		discounts[i] = point.discount
+		bySeason[point.season] = append(bySeason[point.season], point.value)
+	}
+
+	overall := stats.Quantile(values, 0.5)
+	baseline := func(season int) float64 {
+		if config.Method == entities.AnomalyMethodSeasonal && len(bySeason[season]) >= 3 {
+			return stats.Quantile(bySeason[season], 0.5)
+		}
+		return overall
+	}
+
+	residuals := make([]float64, len(history))
+	for i, point := range history {
+		residuals[i] = point.value - baseline(point.season)
+	}
+	center, scale := robustScale(residuals)
+	if scale == 0 {
+		return nil
+	}
+	normalDiscount := stats.Mean(discounts)
+
+	var anomalies []entities.SalesAnomaly
+	for _, point := range evaluated {
+		expected := baseline(point.season) + center
+		score := (point.value - expected) / scale
+		severity, ok := config.SeverityFor(score)
+		if !ok {
+			continue
+		}
+
+		anomalies = append(anomalies, entities.SalesAnomaly{
+			PeriodStart:    point.at,
+			Observed:       point.value,
+			Expected:       roundTo(expected, 2),
+			Score:          roundTo(score, 2),
+			IsSpike:        score > 0,
+			Severity:       severity,
+			Method:         config.Method,
+			Suppressed:     point.discount-normalDiscount > config.SuppressDiscountDelta,
+			AvgDiscount:    roundTo(point.discount, 2),
+			NormalDiscount: roundTo(normalDiscount, 2),
+		})
+	}
+
+	return anomalies
+}
+
+// robustScale возвращает медиану остатков и робастную оценку их стандартного отклонения
+// При нулевом MAD используется среднее абсолютное отклонение от медианы
+func robustScale(residuals []float64) (float64, float64) {
+	center := stats.Quantile(residuals, 0.5)
+
+	deviations := make([]float64, len(residuals))
+	for i, r := range residuals {
+		deviations[i] = math.Abs(r - center)
+	}
+
+	if mad := stats.Quantile(deviations, 0.5); mad > 0 {
+		return center, madScale * mad
+	}
+	return center, meanAbsScale * stats.Mean(deviations)
+}
+
+// newDailySeries создает ряд из нулевых дневных точек с днем недели в качестве сезона
+func newDailySeries(start time.Time, days int) []seriesPoint {
+	points := make([]seriesPoint, days)
+	for i := range points {
+		points[i].at = start.AddDate(0, 0, i)
+		points[i].season = int(points[i].at.Weekday())
+	}
+	return points
+}
+
+// truncateToDay возвращает начало дня в часовом поясе значения
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
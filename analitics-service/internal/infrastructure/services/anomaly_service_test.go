@@ -0,0 +1,284 @@
+// internal/infrastructure/services/anomaly_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryAnomalyRepository хранит найденные аномалии в памяти
+type memoryAnomalyRepository struct {
+	anomalies []entities.SalesAnomaly
+}
+
+func (r *memoryAnomalyRepository) SaveAnomalies(_ context.Context, anomalies []entities.SalesAnomaly) error {
+	r.anomalies = append(r.anomalies, anomalies...)
+	return nil
+}
+
+func (r *memoryAnomalyRepository) GetAnomaliesByPeriod(_ context.Context, startDate, endDate time.Time, minSeverity entities.AnomalySeverity) ([]entities.SalesAnomaly, error) {
+	var result []entities.SalesAnomaly
+	for _, anomaly := range r.anomalies {
+		if !anomaly.PeriodStart.Before(startDate) && !anomaly.PeriodStart.After(endDate) && anomaly.Severity.Rank() >= minSeverity.Rank() {
+			result = append(result, anomaly)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryAnomalyRepository) GetAnomaliesByProduct(_ context.Context, productID string, startDate, endDate time.Time) ([]entities.SalesAnomaly, error) {
+	var result []entities.SalesAnomaly
+	for _, anomaly := range r.anomalies {
+		if anomaly.ProductID == productID && !anomaly.PeriodStart.Before(startDate) && !anomaly.PeriodStart.After(endDate) {
+			result = append(result, anomaly)
+		}
+	}
+	return result, nil
+}
+
+// recordingNotifier запоминает отправленные уведомления и возвращает заданную ошибку
+type recordingNotifier struct {
+	alerts []entities.SalesAnomaly
+	err    error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, anomalies []entities.SalesAnomaly) error {
+	n.alerts = append(n.alerts, anomalies...)
+	return n.err
+}
+
+// testAnomalyConfig возвращает конфигурацию с четырьмя неделями истории и одним проверяемым днем
+func testAnomalyConfig(method entities.AnomalyMethod) entities.AnomalyDetectionConfig {
+	config := entities.DefaultAnomalyDetectionConfig()
+	config.Method = method
+	config.LookbackDays = 28
+	config.IncludeProducts = false
+	return config
+}
+
+func TestDetectDailyAnomalies(t *testing.T) {
+	asOf := time.Date(2024, 3, 29, 10, 0, 0, 0, time.UTC)
+	detectDay := time.Date(2024, 3, 28, 0, 0, 0, 0, time.UTC)
+
+	// История чередует 100 и 104 единицы: медиана 102, MAD 2, масштаб 1.4826·2 = 2.9652.
+	// Выручка и число чеков не заданы, поэтому их ряды постоянны и аномалий не дают
+	history := func() []entities.DailyTransactionData {
+		var daily []entities.DailyTransactionData
+		for i := 28; i >= 1; i-- {
+			daily = append(daily, entities.DailyTransactionData{Date: detectDay.AddDate(0, 0, -i), Sales: float64(100 + 4*(i%2))})
+		}
+		return daily
+	}
+
+	tests := []struct {
+		name           string
+		observed       float64
+		discount       float64
+		wantSeverity   entities.AnomalySeverity
+		wantSpike      bool
+		wantSuppressed bool
+	}{
+		{name: "within normal range", observed: 110},
+		{name: "low spike", observed: 115, wantSeverity: entities.SeverityLow, wantSpike: true},
+		{name: "medium spike", observed: 120, wantSeverity: entities.SeverityMedium, wantSpike: true},
+		{name: "high spike", observed: 150, wantSeverity: entities.SeverityHigh, wantSpike: true},
+		{name: "high drop", observed: 60, wantSeverity: entities.SeverityHigh},
+		// Всплеск при скидке выше обычной на 20 п.п. сохраняется, но уведомление не отправляется
+		{name: "spike during discounts", observed: 150, discount: 20, wantSeverity: entities.SeverityHigh, wantSpike: true, wantSuppressed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sales := &memorySalesRepository{daily: append(history(),
+				entities.DailyTransactionData{Date: detectDay, Sales: tt.observed, AvgDiscount: tt.discount})}
+			repo := &memoryAnomalyRepository{}
+			notifier := &recordingNotifier{}
+			service := services.NewAnomalyService(sales, repo, notifier, logger.NewLogger("ERROR"))
+
+			anomalies, err := service.DetectDaily(context.Background(), asOf, testAnomalyConfig(entities.AnomalyMethodMAD))
+			if err != nil {
+				t.Fatalf("DetectDaily() error = %v", err)
+			}
+
+			if tt.wantSeverity == "" {
+				if len(anomalies) != 0 || len(repo.anomalies) != 0 {
+					t.Errorf("anomalies = %+v, want none", anomalies)
+				}
+				return
+			}
+			if len(anomalies) != 1 {
+				t.Fatalf("anomalies = %+v, want 1", anomalies)
+			}
+
+			got := anomalies[0]
+			if got.Metric != "units" || got.ProductID != "" || !got.PeriodStart.Equal(detectDay) {
+				t.Errorf("anomaly = %s/%q at %s, want store units at %s", got.Metric, got.ProductID, got.PeriodStart, detectDay)
+			}
+			if want := math.Round((tt.observed-102)/2.9652*100) / 100; got.Expected != 102 || got.Score != want {
+				t.Errorf("expected = %.2f, score = %.2f, want 102 and %.2f", got.Expected, got.Score, want)
+			}
+			if got.Severity != tt.wantSeverity || got.IsSpike != tt.wantSpike || got.Suppressed != tt.wantSuppressed {
+				t.Errorf("anomaly = %s spike %v suppressed %v, want %s spike %v suppressed %v",
+					got.Severity, got.IsSpike, got.Suppressed, tt.wantSeverity, tt.wantSpike, tt.wantSuppressed)
+			}
+			if len(repo.anomalies) != 1 {
+				t.Errorf("saved anomalies = %d, want 1", len(repo.anomalies))
+			}
+			wantAlerts := 1
+			if tt.wantSuppressed {
+				wantAlerts = 0
+			}
+			if len(notifier.alerts) != wantAlerts {
+				t.Errorf("alerts = %d, want %d", len(notifier.alerts), wantAlerts)
+			}
+		})
+	}
+}
+
+func TestDetectDailyProductAnomalies(t *testing.T) {
+	asOf := time.Date(2024, 3, 29, 0, 0, 0, 0, time.UTC)
+	detectDay := asOf.AddDate(0, 0, -1)
+
+	var sales []entities.Sale
+	for i := 28; i >= 1; i-- {
+		sales = append(sales, testSale("P1", 10+2*(i%2), 10, 0, detectDay.AddDate(0, 0, -i).Add(12*time.Hour)))
+	}
+	sales = append(sales, testSale("P1", 40, 10, 0, detectDay.Add(12*time.Hour)))
+
+	// Ошибка доставки уведомления не отменяет найденные и сохраненные аномалии
+	notifier := &recordingNotifier{err: errors.New("webhook unavailable")}
+	repo := &memoryAnomalyRepository{}
+	service := services.NewAnomalyService(&memorySalesRepository{sales: sales}, repo, notifier, logger.NewLogger("ERROR"))
+
+	config := testAnomalyConfig(entities.AnomalyMethodMAD)
+	config.IncludeProducts = true
+	anomalies, err := service.DetectDaily(context.Background(), asOf, config)
+	if err != nil {
+		t.Fatalf("DetectDaily() error = %v", err)
+	}
+	if len(anomalies) != 1 || anomalies[0].ProductID != "P1" || anomalies[0].Severity != entities.SeverityHigh {
+		t.Fatalf("anomalies = %+v, want one high P1 anomaly", anomalies)
+	}
+	if len(repo.anomalies) != 1 || len(notifier.alerts) != 1 {
+		t.Errorf("saved = %d, notified = %d, want 1 and 1", len(repo.anomalies), len(notifier.alerts))
+	}
+}
+
+func TestDetectHourlyAnomalies(t *testing.T) {
+	asOf := time.Date(2024, 3, 8, 0, 30, 0, 0, time.UTC)
+	detectDay := time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)
+
+	// С 6 до 22 выручка около 100, ночью около 10 с отклонением ±1 по дням; в проверяемый день в 03:00 выручка 60
+	var sales []entities.Sale
+	for day := 0; day < 8; day++ {
+		for hour := 0; hour < 24; hour++ {
+			price := 10.0
+			if hour >= 6 && hour < 22 {
+				price = 100
+			}
+			price += float64(1 - 2*(day%2))
+			at := detectDay.AddDate(0, 0, day-7).Add(time.Duration(hour) * time.Hour)
+			if day == 7 && hour == 3 {
+				price = 60
+			}
+			sales = append(sales, testSale("P1", 1, price, 0, at))
+		}
+	}
+
+	config := testAnomalyConfig(entities.AnomalyMethodSeasonal)
+	config.LookbackDays = 7
+
+	tests := []struct {
+		name   string
+		method entities.AnomalyMethod
+		check  func(t *testing.T, anomalies []entities.SalesAnomaly)
+	}{
+		{
+			// Нормальный уровень оценивается отдельно для каждого часа, поэтому аномален только всплеск в 03:00
+			name:   "seasonal",
+			method: entities.AnomalyMethodSeasonal,
+			check: func(t *testing.T, anomalies []entities.SalesAnomaly) {
+				if len(anomalies) != 1 {
+					t.Fatalf("anomalies = %+v, want 1", anomalies)
+				}
+				got := anomalies[0]
+				if got.Metric != "revenue" || got.Granularity != entities.GranularityHourly || !got.IsSpike ||
+					!got.PeriodStart.Equal(detectDay.Add(3*time.Hour)) {
+					t.Errorf("anomaly = %+v, want revenue spike at 03:00", got)
+				}
+			},
+		},
+		{
+			// Общая медиана близка к дневному уровню, поэтому спокойные ночные часы выглядят провалами
+			name:   "mad",
+			method: entities.AnomalyMethodMAD,
+			check: func(t *testing.T, anomalies []entities.SalesAnomaly) {
+				if len(anomalies) <= 1 {
+					t.Fatalf("anomalies = %d, want night hours flagged as drops", len(anomalies))
+				}
+				for _, anomaly := range anomalies {
+					if anomaly.IsSpike {
+						t.Errorf("anomaly at %s is a spike, want only drops", anomaly.PeriodStart)
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewAnomalyService(&memorySalesRepository{sales: sales}, &memoryAnomalyRepository{},
+				&recordingNotifier{}, logger.NewLogger("ERROR"))
+
+			methodConfig := config
+			methodConfig.Method = tt.method
+			anomalies, err := service.DetectHourly(context.Background(), asOf, methodConfig)
+			if err != nil {
+				t.Fatalf("DetectHourly() error = %v", err)
+			}
+			tt.check(t, anomalies)
+		})
+	}
+}
+
+func TestAnomalyServiceErrors(t *testing.T) {
+	service := services.NewAnomalyService(&memorySalesRepository{}, &memoryAnomalyRepository{}, &recordingNotifier{}, logger.NewLogger("ERROR"))
+	asOf := time.Date(2024, 3, 29, 0, 0, 0, 0, time.UTC)
+
+	invalid := testAnomalyConfig("zscore")
+	thresholds := testAnomalyConfig(entities.AnomalyMethodMAD)
+	thresholds.HighThreshold = 1
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "unknown method", call: func() error {
+			_, err := service.DetectDaily(context.Background(), asOf, invalid)
+			return err
+		}},
+		{name: "decreasing thresholds", call: func() error {
+			_, err := service.DetectHourly(context.Background(), asOf, thresholds)
+			return err
+		}},
+		{name: "unknown severity", call: func() error {
+			_, err := service.GetAnomalies(context.Background(), asOf.AddDate(0, 0, -7), asOf, "critical")
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, services.ErrInvalidParameter) {
+				t.Errorf("error = %v, want %v", err, services.ErrInvalidParameter)
+			}
+		})
+	}
+}
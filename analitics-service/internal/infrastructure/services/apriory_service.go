@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 
 	"analitics-service/internal/domain/entities"
@@ -45,22 +46,29 @@ func NewAprioriService(logger logger.Logger) *aprioriService {
 func (s *aprioriService) GenerateFrequentItemsets(ctx context.Context, transactions []entities.Transaction, minSupport float64) ([]entities.FrequentItemset, error) {
 	s.logger.Info(ctx, "Генерация частых наборов товаров", "транзакций", len(transactions), "minSupport", minSupport)
 
+	// Библиотека паникует при неположительной поддержке
+	if minSupport <= 0 || minSupport > 1 {
+		return nil, fmt.Errorf("%w: min support must be in (0, 1], got %f", ErrInvalidParameter, minSupport)
+	}
+
 	// Преобразуем транзакции в формат, требуемый библиотекой go-apriori
-	itemMatrix := prepareTransactionsData(transactions)
+	itemMatrix, catalog := prepareTransactionsData(transactions)
 
 	// Создаем новый экземпляр обработчика Apriori
 	ap := apriori.NewApriori(itemMatrix)
 
-	// Генерируем частые наборы с указанной минимальной поддержкой
-	aprioriResults := ap.Calculate(minSupport)
+	// Генерируем частые наборы с указанной минимальной поддержкой; без порогов достоверности и лифта
+	// библиотека возвращает запись для каждого частого набора
+	aprioriResults := ap.Calculate(apriori.NewOptions(minSupport, 0, 0, 0))
 
 	// Преобразуем результаты библиотеки в наши доменные сущности
 	result := make([]entities.FrequentItemset, 0, len(aprioriResults))
 	for _, apResult := range aprioriResults {
+		supportRecord := apResult.GetSupportRecord()
 		itemset := entities.FrequentItemset{
-			Items:   convertAprioriItems(apResult.Items),
-			Support: apResult.Support,
-			Count:   int(apResult.Support * float64(len(transactions))),
+			Items:   convertAprioriItems(supportRecord.GetItems(), catalog),
+			Support: supportRecord.GetSupport(),
+			Count:   int(math.Round(supportRecord.GetSupport() * float64(len(transactions)))),
 		}
 		result = append(result, itemset)
 	}
@@ -78,30 +86,40 @@ func (s *aprioriService) GenerateFrequentItemsets(ctx context.Context, transacti
 func (s *aprioriService) GenerateAssociationRules(ctx context.Context, frequentItemsets []entities.FrequentItemset, minConfidence float64) ([]entities.AssociationRule, error) {
 	s.logger.Info(ctx, "Генерация ассоциативных правил", "наборов", len(frequentItemsets), "minConfidence", minConfidence)
 
-	// Преобразуем наши доменные сущности в формат, требуемый для библиотеки
-	aprioriItemsets := make([]apriori.Itemset, 0, len(frequentItemsets))
+	// Как и библиотека go-apriori, строим правила с одним товаром в следствии:
+	// по свойству Apriori все подмножества частого набора тоже частые, поэтому их поддержка известна
+	rules := make([]entities.AssociationRule, 0)
 	for _, itemset := range frequentItemsets {
-		aprioriItemset := apriori.Itemset{
-			Items:   convertToAprioriItems(itemset.Items),
-			Support: itemset.Support,
+		if len(itemset.Items) < 2 {
+			continue
 		}
-		aprioriItemsets = append(aprioriItemsets, aprioriItemset)
-	}
 
-	// Генерируем правила ассоциаций
-	aprioriRules := apriori.GenerateAssociationRules(aprioriItemsets, minConfidence)
-
-	// Преобразуем результаты в наши доменные сущности
-	rules := make([]entities.AssociationRule, 0, len(aprioriRules))
-	for _, rule := range aprioriRules {
-		domainRule := entities.AssociationRule{
-			Antecedent: convertAprioriItems(rule.From),
-			Consequent: convertAprioriItems(rule.To),
-			Support:    rule.Support,
-			Confidence: rule.Confidence,
-			Lift:       calculateLift(rule.From, rule.To, frequentItemsets),
+		for i := range itemset.Items {
+			antecedent := make([]entities.Item, 0, len(itemset.Items)-1)
+			antecedent = append(antecedent, itemset.Items[:i]...)
+			antecedent = append(antecedent, itemset.Items[i+1:]...)
+			consequent := []entities.Item{itemset.Items[i]}
+
+			antSupport := findItemsetSupport(antecedent, frequentItemsets)
+			if antSupport == 0 {
+				continue
+			}
+
+			confidence := itemset.Support / antSupport
+			if confidence < minConfidence {
+				continue
+			}
+
+			rule := entities.AssociationRule{
+				Antecedent: antecedent,
+				Consequent: consequent,
+				Support:    itemset.Support,
+				Confidence: confidence,
+				Lift:       calculateLift(antecedent, consequent, frequentItemsets),
+			}
+			describeRuleItems(&rule, itemset.Items)
+			rules = append(rules, rule)
 		}
-		rules = append(rules, domainRule)
 	}
 
 	// Сортируем правила по убыванию уверенности
@@ -162,7 +180,7 @@ func (s *aprioriService) GetProductRecommendations(ctx context.Context, currentB
 		// Все элементы из antecedent должны быть в корзине
 		matchedAll := true
 		for _, antItem := range rule.Antecedent {
-			if !basketIDs[antItem.ID] {
+			if !basketIDs[antItem.ProductID] {
 				matchedAll = false
 				break
 			}
@@ -175,28 +193,28 @@ func (s *aprioriService) GetProductRecommendations(ctx context.Context, currentB
 		// Для каждого товара из consequent создаем или обновляем рекомендацию
 		for _, conseqItem := range rule.Consequent {
 			// Не рекомендуем товары, которые уже есть в корзине
-			if basketIDs[conseqItem.ID] {
+			if basketIDs[conseqItem.ProductID] {
 				continue
 			}
 
 			// Если товар уже есть в рекомендациях, обновляем его score
-			if rec, exists := recommendationsMap[conseqItem.ID]; exists {
+			if rec, exists := recommendationsMap[conseqItem.ProductID]; exists {
 				// Используем максимальное значение confidence как основной показатель
 				if rule.Confidence > rec.Score {
 					rec.Score = rule.Confidence
 					rec.Lift = rule.Lift
 					rec.Support = rule.Support
-					recommendationsMap[conseqItem.ID] = rec
+					recommendationsMap[conseqItem.ProductID] = rec
 				}
 			} else {
 				// Создаем новую рекомендацию
-				product, err := s.getProductDetails(ctx, conseqItem.ID)
+				product, err := s.getProductDetails(ctx, conseqItem.ProductID)
 				if err != nil {
-					s.logger.Error(ctx, "Ошибка получения деталей товара", "error", err, "productID", conseqItem.ID)
+					s.logger.Error(ctx, "Ошибка получения деталей товара", "error", err, "productID", conseqItem.ProductID)
 					continue
 				}
 
-				recommendationsMap[conseqItem.ID] = entities.ProductRecommendation{
+				recommendationsMap[conseqItem.ProductID] = entities.ProductRecommendation{
 					Product: product,
 					Score:   rule.Confidence,
 					Lift:    rule.Lift,
@@ -229,52 +247,81 @@ func (s *aprioriService) GetProductRecommendations(ctx context.Context, currentB
 // Вспомогательные функции
 
 // prepareTransactionsData преобразует транзакции в формат для библиотеки go-apriori
-func prepareTransactionsData(transactions []entities.Transaction) [][]apriori.Item {
-	itemMatrix := make([][]apriori.Item, 0, len(transactions))
+// и собирает описание товаров по их ID; библиотека считает каждое вхождение товара в чек,
+// поэтому повторяющиеся позиции чека схлопываются
+func prepareTransactionsData(transactions []entities.Transaction) ([][]string, map[string]entities.Item) {
+	itemMatrix := make([][]string, 0, len(transactions))
+	catalog := make(map[string]entities.Item)
 
 	for _, transaction := range transactions {
-		items := make([]apriori.Item, 0, len(transaction.Items))
+		items := make([]string, 0, len(transaction.Items))
+		seen := make(map[string]bool, len(transaction.Items))
 		for _, item := range transaction.Items {
-			items = append(items, apriori.Item(item.ID))
+			if seen[item.ProductID] {
+				continue
+			}
+			seen[item.ProductID] = true
+			items = append(items, item.ProductID)
+
+			if _, ok := catalog[item.ProductID]; !ok {
+				catalog[item.ProductID] = entities.Item{
+					ProductID:  item.ProductID,
+					Name:       item.Name,
+					CategoryID: item.CategoryID,
+					Category:   item.Category,
+					Price:      item.Price,
+				}
+			}
 		}
 		itemMatrix = append(itemMatrix, items)
 	}
 
-	return itemMatrix
+	return itemMatrix, catalog
 }
 
-// convertAprioriItems преобразует items из формата библиотеки в наш формат
-func convertAprioriItems(apItems []apriori.Item) []entities.Item {
-	items := make([]entities.Item, 0, len(apItems))
-	for _, item := range apItems {
-		items = append(items, entities.Item{
-			ID: string(item),
-		})
+// convertAprioriItems преобразует ID товаров из формата библиотеки в наш формат
+func convertAprioriItems(productIDs []string, catalog map[string]entities.Item) []entities.Item {
+	items := make([]entities.Item, 0, len(productIDs))
+	for _, productID := range productIDs {
+		item, ok := catalog[productID]
+		if !ok {
+			item = entities.Item{ProductID: productID}
+		}
+		items = append(items, item)
 	}
 	return items
 }
 
-// convertToAprioriItems преобразует наши items в формат библиотеки
-func convertToAprioriItems(items []entities.Item) []apriori.Item {
-	apItems := make([]apriori.Item, 0, len(items))
-	for _, item := range items {
-		apItems = append(apItems, apriori.Item(item.ID))
+// describeRuleItems заполняет поля правила для поиска: ID и категории всех товаров правила и диапазон их цен
+func describeRuleItems(rule *entities.AssociationRule, items []entities.Item) {
+	seenCategories := make(map[string]bool)
+	for i, item := range items {
+		rule.Items = append(rule.Items, item.ProductID)
+		if item.Category != "" && !seenCategories[item.Category] {
+			seenCategories[item.Category] = true
+			rule.Categories = append(rule.Categories, item.Category)
+		}
+		if i == 0 || item.Price < rule.PriceRange[0] {
+			rule.PriceRange[0] = item.Price
+		}
+		if i == 0 || item.Price > rule.PriceRange[1] {
+			rule.PriceRange[1] = item.Price
+		}
 	}
-	return apItems
 }
 
 // calculateLift вычисляет показатель Lift для правила
-func calculateLift(antecedent []apriori.Item, consequent []apriori.Item, itemsets []entities.FrequentItemset) float64 {
+func calculateLift(antecedent []entities.Item, consequent []entities.Item, itemsets []entities.FrequentItemset) float64 {
 	// Находим поддержку antecedent
-	antSupport := findItemsetSupport(convertAprioriItems(antecedent), itemsets)
+	antSupport := findItemsetSupport(antecedent, itemsets)
 
 	// Находим поддержку consequent
-	consSupport := findItemsetSupport(convertAprioriItems(consequent), itemsets)
+	consSupport := findItemsetSupport(consequent, itemsets)
 
 	// Находим общую поддержку
-	combined := append([]apriori.Item{}, antecedent...)
+	combined := append([]entities.Item{}, antecedent...)
 	combined = append(combined, consequent...)
-	combSupport := findItemsetSupport(convertAprioriItems(combined), itemsets)
+	combSupport := findItemsetSupport(combined, itemsets)
 
 	// Вычисляем lift
 	// Lift = P(A∪B) / (P(A) * P(B))
@@ -290,7 +337,7 @@ func findItemsetSupport(items []entities.Item, itemsets []entities.FrequentItems
 	// Создаем карту ID товаров для быстрого поиска
 	itemIDs := make(map[string]bool)
 	for _, item := range items {
-		itemIDs[item.ID] = true
+		itemIDs[item.ProductID] = true
 	}
 
 	// Ищем точное совпадение
@@ -301,7 +348,7 @@ func findItemsetSupport(items []entities.Item, itemsets []entities.FrequentItems
 
 		matchAll := true
 		for _, item := range itemset.Items {
-			if !itemIDs[item.ID] {
+			if !itemIDs[item.ProductID] {
 				matchAll = false
 				break
 			}
@@ -323,8 +370,8 @@ func (s *aprioriService) getProductDetails(ctx context.Context, productID string
 
 	// Временный заглушка
 	return entities.Product{
-		ID:    productID,
-		Name:  "Product " + productID,
-		Price: 0,
+		BaseEntity: entities.BaseEntity{ID: productID},
+		Name:       "Product " + productID,
+		Price:      0,
 	}, nil
 }
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+	"analitics-service/pkg/stats"
+)
+
+// Ошибки сервиса бандитов
+var (
+	ErrBanditNotFound = errors.New("bandit not found")
+	ErrBanditInactive = errors.New("bandit is not active")
+	ErrUnknownArm     = errors.New("unknown bandit arm")
+	ErrBanditExists   = errors.New("bandit already exists")
+)
+
+// BanditService определяет интерфейс выбора уровня скидки методом Томпсоновского сэмплирования
+type BanditService interface {
+	// CreateBandit проверяет и сохраняет нового бандита, создавая руки для всех уровней скидки
+	// Для уже существующего ID возвращает ErrBanditExists, не трогая накопленную статистику
+	CreateBandit(ctx context.Context, bandit entities.Bandit) error
+
+	// SelectArm выбирает уровень скидки для очередного запроса
+	SelectArm(ctx context.Context, banditID string) (*entities.BanditDecision, error)
+
+	// SelectArmFor выбирает уровень скидки активного бандита товара или категории
+	SelectArmFor(ctx context.Context, productID, category string) (*entities.BanditDecision, error)
+
+	// RecordOutcome добавляет исход показа руки: для bernoulli reward равен 1 при покупке, иначе 0
+	RecordOutcome(ctx context.Context, banditID, armID string, reward float64) error
+
+	// GetReport возвращает статистику рук и накопленное сожаление относительно лучшей руки
+	GetReport(ctx context.Context, banditID string) (*entities.BanditReport, error)
+}
+
+// banditService реализует интерфейс BanditService
+type banditService struct {
+	banditRepo repositories.BanditRepository
+	logger     logger.Logger
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewBanditService создает новый экземпляр сервиса бандитов
+func NewBanditService(banditRepo repositories.BanditRepository, logger logger.Logger) BanditService {
+	return &banditService{
+		banditRepo: banditRepo,
+		logger:     logger,
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// CreateBandit проверяет и сохраняет нового бандита, создавая руки для всех уровней скидки
+func (s *banditService) CreateBandit(ctx context.Context, bandit entities.Bandit) error {
+	if err := bandit.Validate(); err != nil {
+		return fmt.Errorf("%w: invalid bandit: %v", ErrInvalidParameter, err)
+	}
+
+	// Повторное сохранение обнулило бы статистику рук, поэтому существующий бандит не перезаписывается
+	existing, err := s.banditRepo.GetBanditByID(ctx, bandit.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check bandit %s: %w", bandit.ID, err)
+	}
+	if existing.ID != "" {
+		return fmt.Errorf("%w: %s", ErrBanditExists, bandit.ID)
+	}
+
+	now := time.Now()
+	if bandit.CreatedAt.IsZero() {
+		bandit.CreatedAt = now
+	}
+	if bandit.StartedAt.IsZero() {
+		bandit.StartedAt = now
+	}
+	bandit.UpdatedAt = now
+
+	arms := make([]entities.BanditArm, 0, len(bandit.DiscountLevels))
+	for _, level := range bandit.DiscountLevels {
+		arms = append(arms, entities.BanditArm{
+			ID:          fmt.Sprintf("%s:%g", bandit.ID, level),
+			BanditID:    bandit.ID,
+			DiscountPct: level,
+			UpdatedAt:   now,
+		})
+	}
+
+	if err := s.banditRepo.SaveBandit(ctx, bandit, arms); err != nil {
+		return fmt.Errorf("failed to save bandit: %w", err)
+	}
+
+	s.logger.Info(ctx, "Бандит создан", "banditID", bandit.ID, "arms", len(arms), "model", bandit.RewardModel)
+	return nil
+}
+
+// SelectArm выбирает уровень скидки для очередного запроса
+func (s *banditService) SelectArm(ctx context.Context, banditID string) (*entities.BanditDecision, error) {
+	bandit, err := s.banditRepo.GetBanditByID(ctx, banditID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve bandit %s: %w", banditID, err)
+	}
+	if bandit.ID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrBanditNotFound, banditID)
+	}
+
+	return s.selectArm(ctx, bandit)
+}
+
+// SelectArmFor выбирает уровень скидки активного бандита товара или категории
+func (s *banditService) SelectArmFor(ctx context.Context, productID, category string) (*entities.BanditDecision, error) {
+	bandit, err := s.banditRepo.GetActiveBandit(ctx, productID, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve active bandit: %w", err)
+	}
+	if bandit.ID == "" {
+		return nil, fmt.Errorf("%w: no active bandit for product %q or category %q", ErrBanditNotFound, productID, category)
+	}
+
+	return s.selectArm(ctx, bandit)
+}
+
+// RecordOutcome добавляет исход показа руки
+func (s *banditService) RecordOutcome(ctx context.Context, banditID, armID string, reward float64) error {
+	bandit, err := s.banditRepo.GetBanditByID(ctx, banditID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve bandit %s: %w", banditID, err)
+	}
+	if bandit.ID == "" {
+		return fmt.Errorf("%w: %s", ErrBanditNotFound, banditID)
+	}
+
+	if bandit.RewardModel == entities.RewardBernoulli && reward != 0 && reward != 1 {
+		return fmt.Errorf("%w: bernoulli reward must be 0 or 1, got %f", ErrInvalidParameter, reward)
+	}
+
+	// Исход неизвестной руки репозиторий молча пропустил бы, поэтому руку проверяем заранее
+	arms, err := s.banditRepo.GetArms(ctx, banditID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve arms: %w", err)
+	}
+	if !hasArm(arms, armID) {
+		return fmt.Errorf("%w: %s", ErrUnknownArm, armID)
+	}
+
+	if err := s.banditRepo.RecordArmOutcome(ctx, banditID, armID, reward, reward > 0); err != nil {
+		return fmt.Errorf("failed to record outcome for arm %s: %w", armID, err)
+	}
+
+	return nil
+}
+
+// GetReport возвращает статистику рук и накопленное сожаление относительно лучшей руки
+func (s *banditService) GetReport(ctx context.Context, banditID string) (*entities.BanditReport, error) {
+	bandit, err := s.banditRepo.GetBanditByID(ctx, banditID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve bandit %s: %w", banditID, err)
+	}
+	if bandit.ID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrBanditNotFound, banditID)
+	}
+
+	arms, err := s.banditRepo.GetArms(ctx, banditID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve arms: %w", err)
+	}
+	if len(arms) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	report := &entities.BanditReport{
+		BanditID:    banditID,
+		GeneratedAt: time.Now(),
+		Arms:        make([]entities.BanditArmReport, 0, len(arms)),
+	}
+
+	// Лучшая рука определяется по апостериорному ожидаемому значению
+	values := make([]float64, len(arms))
+	best := 0
+	for i, arm := range arms {
+		values[i] = armValue(bandit, arm, posteriorMean(bandit, arm))
+		if values[i] > values[best] {
+			best = i
+		}
+	}
+
+	report.BestArmID = arms[best].ID
+	report.BestDiscountPct = arms[best].DiscountPct
+
+	// Сожаление — упущенное ожидаемое значение от выбора неоптимальных рук
+	for i, arm := range arms {
+		regret := float64(arm.Pulls) * (values[best] - values[i])
+		report.TotalPulls += arm.Pulls
+		report.TotalReward += arm.RewardSum
+		report.CumulativeRegret += regret
+		report.Arms = append(report.Arms, entities.BanditArmReport{
+			ArmID:         arm.ID,
+			DiscountPct:   arm.DiscountPct,
+			Pulls:         arm.Pulls,
+			ExpectedValue: values[i],
+			Regret:        regret,
+		})
+	}
+
+	return report, nil
+}
+
+// selectArm выбирает руку с учетом минимальной вероятности исследования
+func (s *banditService) selectArm(ctx context.Context, bandit entities.Bandit) (*entities.BanditDecision, error) {
+	if !bandit.IsActive {
+		return nil, ErrBanditInactive
+	}
+
+	arms, err := s.banditRepo.GetArms(ctx, bandit.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve arms: %w", err)
+	}
+	if len(arms) == 0 {
+		return nil, ErrUnknownArm
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	decision := &entities.BanditDecision{
+		BanditID:  bandit.ID,
+		DecidedAt: time.Now(),
+	}
+
+	// С вероятностью floor*K выбираем руку равномерно, что гарантирует каждой руке
+	// вероятность не ниже floor независимо от апостериорных распределений
+	if s.rng.Float64() < bandit.ExplorationFloor*float64(len(arms)) {
+		arm := arms[s.rng.Intn(len(arms))]
+		decision.ArmID = arm.ID
+		decision.DiscountPct = arm.DiscountPct
+		decision.Explored = true
+		return decision, nil
+	}
+
+	best := -1
+	bestScore := math.Inf(-1)
+	for i, arm := range arms {
+		score := armValue(bandit, arm, s.samplePosterior(bandit, arm))
+		if score > bestScore {
+			best = i
+			bestScore = score
+		}
+	}
+
+	decision.ArmID = arms[best].ID
+	decision.DiscountPct = arms[best].DiscountPct
+	return decision, nil
+}
+
+// hasArm проверяет, есть ли рука среди рук бандита
+func hasArm(arms []entities.BanditArm, armID string) bool {
+	for _, arm := range arms {
+		if arm.ID == armID {
+			return true
+		}
+	}
+	return false
+}
+
+// samplePosterior генерирует значение из апостериорного распределения вознаграждения руки
+func (s *banditService) samplePosterior(bandit entities.Bandit, arm entities.BanditArm) float64 {
+	if bandit.RewardModel == entities.RewardBernoulli {
+		return stats.SampleBeta(s.rng,
+			bandit.PriorAlpha+float64(arm.Successes),
+			bandit.PriorBeta+float64(arm.Pulls-arm.Successes))
+	}
+
+	mean, sd := gaussianPosterior(bandit, arm)
+	return mean + sd*s.rng.NormFloat64()
+}
+
+// posteriorMean возвращает апостериорное среднее вознаграждения руки
+func posteriorMean(bandit entities.Bandit, arm entities.BanditArm) float64 {
+	if bandit.RewardModel == entities.RewardBernoulli {
+		alpha := bandit.PriorAlpha + float64(arm.Successes)
+		beta := bandit.PriorBeta + float64(arm.Pulls-arm.Successes)
+		return alpha / (alpha + beta)
+	}
+
+	mean, _ := gaussianPosterior(bandit, arm)
+	return mean
+}
+
+// gaussianPosterior возвращает параметры нормального апостериорного распределения среднего вознаграждения
+// Дисперсия наблюдений оценивается по данным руки, до двух наблюдений используется априорная
+func gaussianPosterior(bandit entities.Bandit, arm entities.BanditArm) (float64, float64) {
+	priorVariance := bandit.PriorStdDev * bandit.PriorStdDev
+	if arm.Pulls == 0 {
+		return bandit.PriorMean, bandit.PriorStdDev
+	}
+
+	noiseVariance := arm.RewardVariance()
+	if noiseVariance <= 0 {
+		noiseVariance = priorVariance
+	}
+
+	precision := 1/priorVariance + float64(arm.Pulls)/noiseVariance
+	mean := (bandit.PriorMean/priorVariance + arm.RewardSum/noiseVariance) / precision
+	return mean, math.Sqrt(1 / precision)
+}
+
+// armValue переводит вознаграждение руки в сопоставимую между руками ценность
+// Для bernoulli вероятность покупки взвешивается долей цены после скидки, чтобы
+// большая скидка выигрывала только при достаточном приросте конверсии
+func armValue(bandit entities.Bandit, arm entities.BanditArm, reward float64) float64 {
+	if bandit.RewardModel == entities.RewardBernoulli {
+		return reward * (1 - arm.DiscountPct/100)
+	}
+	return reward
+}
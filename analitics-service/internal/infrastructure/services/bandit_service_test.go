@@ -0,0 +1,271 @@
+// internal/infrastructure/services/bandit_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryBanditRepository хранит бандитов и руки в памяти
+type memoryBanditRepository struct {
+	mu      sync.Mutex
+	bandits map[string]entities.Bandit
+	arms    map[string][]entities.BanditArm
+}
+
+func newMemoryBanditRepository() *memoryBanditRepository {
+	return &memoryBanditRepository{
+		bandits: make(map[string]entities.Bandit),
+		arms:    make(map[string][]entities.BanditArm),
+	}
+}
+
+func (r *memoryBanditRepository) SaveBandit(_ context.Context, bandit entities.Bandit, arms []entities.BanditArm) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bandits[bandit.ID] = bandit
+	if _, exists := r.arms[bandit.ID]; !exists {
+		r.arms[bandit.ID] = append([]entities.BanditArm(nil), arms...)
+	}
+	return nil
+}
+
+func (r *memoryBanditRepository) GetBanditByID(_ context.Context, banditID string) (entities.Bandit, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bandits[banditID], nil
+}
+
+func (r *memoryBanditRepository) GetActiveBandit(_ context.Context, productID, category string) (entities.Bandit, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, bandit := range r.bandits {
+		if bandit.IsActive && bandit.ProductID != "" && bandit.ProductID == productID {
+			return bandit, nil
+		}
+	}
+	for _, bandit := range r.bandits {
+		if bandit.IsActive && bandit.ProductID == "" && bandit.Category != "" && bandit.Category == category {
+			return bandit, nil
+		}
+	}
+	return entities.Bandit{}, nil
+}
+
+func (r *memoryBanditRepository) GetArms(_ context.Context, banditID string) ([]entities.BanditArm, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]entities.BanditArm(nil), r.arms[banditID]...), nil
+}
+
+func (r *memoryBanditRepository) RecordArmOutcome(_ context.Context, banditID, armID string, reward float64, success bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.arms[banditID] {
+		arm := &r.arms[banditID][i]
+		if arm.ID != armID {
+			continue
+		}
+		arm.Pulls++
+		if success {
+			arm.Successes++
+		}
+		arm.RewardSum += reward
+		arm.RewardSumSquares += reward * reward
+	}
+	return nil
+}
+
+// newTestBandit возвращает bernoulli-бандита товара с тремя уровнями скидки
+func newTestBandit(floor float64) entities.Bandit {
+	bandit := entities.Bandit{
+		ProductID:        "P1",
+		RewardModel:      entities.RewardBernoulli,
+		DiscountLevels:   []float64{0, 10, 20},
+		ExplorationFloor: floor,
+		PriorAlpha:       1,
+		PriorBeta:        1,
+		IsActive:         true,
+	}
+	bandit.ID = "bandit-1"
+	return bandit
+}
+
+// TestThompsonSamplingConvergesToBestArm проверяет, что после обучения бандит почти всегда
+// выбирает руку с наибольшей ожидаемой выручкой с учетом скидки
+func TestThompsonSamplingConvergesToBestArm(t *testing.T) {
+	// Ценность руки — конверсия, умноженная на долю цены после скидки: 0.10, 0.18 и 0.096
+	conversion := map[float64]float64{0: 0.10, 10: 0.20, 20: 0.12}
+	const bestDiscount = 10.0
+
+	ctx := context.Background()
+	service := services.NewBanditService(newMemoryBanditRepository(), logger.NewLogger("ERROR"))
+	if err := service.CreateBandit(ctx, newTestBandit(0.01)); err != nil {
+		t.Fatalf("CreateBandit() error = %v", err)
+	}
+
+	customers := rand.New(rand.NewSource(7))
+	const rounds, tail = 4000, 500
+	bestInTail := 0
+	for i := 0; i < rounds; i++ {
+		decision, err := service.SelectArm(ctx, "bandit-1")
+		if err != nil {
+			t.Fatalf("SelectArm() error = %v", err)
+		}
+		if i >= rounds-tail && decision.DiscountPct == bestDiscount {
+			bestInTail++
+		}
+
+		reward := 0.0
+		if customers.Float64() < conversion[decision.DiscountPct] {
+			reward = 1
+		}
+		if err := service.RecordOutcome(ctx, "bandit-1", decision.ArmID, reward); err != nil {
+			t.Fatalf("RecordOutcome() error = %v", err)
+		}
+	}
+
+	if share := float64(bestInTail) / tail; share < 0.8 {
+		t.Errorf("best arm share over the last %d rounds = %.2f, want at least 0.8", tail, share)
+	}
+
+	report, err := service.GetReport(ctx, "bandit-1")
+	if err != nil {
+		t.Fatalf("GetReport() error = %v", err)
+	}
+	if report.BestDiscountPct != bestDiscount {
+		t.Errorf("best discount = %v, want %v", report.BestDiscountPct, bestDiscount)
+	}
+	if report.TotalPulls != rounds {
+		t.Errorf("total pulls = %d, want %d", report.TotalPulls, rounds)
+	}
+}
+
+func TestBanditExplorationFloor(t *testing.T) {
+	tests := []struct {
+		name          string
+		floor         float64
+		wantExploreLo float64
+		wantExploreHi float64
+	}{
+		{name: "no forced exploration", floor: 0, wantExploreLo: 0, wantExploreHi: 0},
+		{name: "floor", floor: 0.1, wantExploreLo: 0.25, wantExploreHi: 0.35},
+		{name: "uniform", floor: 1.0 / 3, wantExploreLo: 1, wantExploreHi: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service := services.NewBanditService(newMemoryBanditRepository(), logger.NewLogger("ERROR"))
+			if err := service.CreateBandit(ctx, newTestBandit(tt.floor)); err != nil {
+				t.Fatalf("CreateBandit() error = %v", err)
+			}
+
+			const draws = 3000
+			explored := 0
+			for i := 0; i < draws; i++ {
+				decision, err := service.SelectArm(ctx, "bandit-1")
+				if err != nil {
+					t.Fatalf("SelectArm() error = %v", err)
+				}
+				if decision.Explored {
+					explored++
+				}
+			}
+
+			share := float64(explored) / draws
+			if share < tt.wantExploreLo || share > tt.wantExploreHi {
+				t.Errorf("explored share = %.3f, want between %.2f and %.2f", share, tt.wantExploreLo, tt.wantExploreHi)
+			}
+		})
+	}
+}
+
+func TestBanditErrors(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewBanditService(newMemoryBanditRepository(), logger.NewLogger("ERROR"))
+	if err := service.CreateBandit(ctx, newTestBandit(0)); err != nil {
+		t.Fatalf("CreateBandit() error = %v", err)
+	}
+
+	invalid := newTestBandit(0)
+	invalid.ID = "bandit-2"
+	invalid.DiscountLevels = []float64{10}
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{name: "duplicate bandit", call: func() error { return service.CreateBandit(ctx, newTestBandit(0)) }, want: services.ErrBanditExists},
+		{name: "invalid bandit", call: func() error { return service.CreateBandit(ctx, invalid) }, want: services.ErrInvalidParameter},
+		{name: "unknown bandit", call: func() error { _, err := service.SelectArm(ctx, "missing"); return err }, want: services.ErrBanditNotFound},
+		{name: "no active bandit", call: func() error { _, err := service.SelectArmFor(ctx, "P2", "tea"); return err }, want: services.ErrBanditNotFound},
+		{name: "unknown arm", call: func() error { return service.RecordOutcome(ctx, "bandit-1", "bandit-1:50", 1) }, want: services.ErrUnknownArm},
+		{name: "non-binary reward", call: func() error { return service.RecordOutcome(ctx, "bandit-1", "bandit-1:10", 0.5) }, want: services.ErrInvalidParameter},
+		{name: "report of unknown bandit", call: func() error { _, err := service.GetReport(ctx, "missing"); return err }, want: services.ErrBanditNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBanditReportRegret(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryBanditRepository()
+	service := services.NewBanditService(repo, logger.NewLogger("ERROR"))
+	if err := service.CreateBandit(ctx, newTestBandit(0)); err != nil {
+		t.Fatalf("CreateBandit() error = %v", err)
+	}
+
+	// Апостериорные средние Beta(1+s, 1+n-s): 10/100 → 11/102, 20/100 → 21/102, 0/0 → 1/2
+	outcomes := []struct {
+		armID     string
+		pulls     int
+		successes int
+	}{
+		{armID: "bandit-1:0", pulls: 100, successes: 10},
+		{armID: "bandit-1:10", pulls: 100, successes: 20},
+	}
+	for _, o := range outcomes {
+		for i := 0; i < o.pulls; i++ {
+			reward := 0.0
+			if i < o.successes {
+				reward = 1
+			}
+			if err := service.RecordOutcome(ctx, "bandit-1", o.armID, reward); err != nil {
+				t.Fatalf("RecordOutcome() error = %v", err)
+			}
+		}
+	}
+
+	report, err := service.GetReport(ctx, "bandit-1")
+	if err != nil {
+		t.Fatalf("GetReport() error = %v", err)
+	}
+
+	// Неиспробованная рука 20% имеет ценность 0.5 * 0.8 = 0.4 и остается лучшей по апостериорному среднему
+	if report.BestArmID != "bandit-1:20" {
+		t.Fatalf("best arm = %s, want bandit-1:20", report.BestArmID)
+	}
+	wantRegret := 100*(0.4-11.0/102) + 100*(0.4-21.0/102*0.9)
+	if math.Abs(report.CumulativeRegret-wantRegret) > 1e-9 {
+		t.Errorf("cumulative regret = %.6f, want %.6f", report.CumulativeRegret, wantRegret)
+	}
+	if report.TotalReward != 30 {
+		t.Errorf("total reward = %v, want 30", report.TotalReward)
+	}
+}
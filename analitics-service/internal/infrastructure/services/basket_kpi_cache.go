@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/pkg/logger"
+)
+
+// basketKPICacheKey определяет запрос отчета KPI корзины
+type basketKPICacheKey struct {
+	period    entities.TimeRange
+	dimension entities.BasketKPIDimension
+	start     int64
+	end       int64
+}
+
+// basketKPICacheEntry содержит отчет или ожидание его расчета
+// Поля кроме ready заполняются до закрытия ready и после этого не меняются
+type basketKPICacheEntry struct {
+	ready   chan struct{}
+	done    bool // Расчет завершен; защищено мьютексом кэша
+	report  *entities.BasketKPIReport
+	err     error
+	expires time.Time
+}
+
+// cachedBasketKPIService кэширует отчеты KPI корзины в памяти на время ttl
+type cachedBasketKPIService struct {
+	next       BasketKPIService
+	ttl        time.Duration
+	maxEntries int
+	logger     logger.Logger
+
+	mu      sync.Mutex
+	entries map[basketKPICacheKey]*basketKPICacheEntry
+}
+
+// NewCachedBasketKPIService оборачивает сервис KPI корзины кэшем отчетов
+// Одинаковые запросы, пришедшие во время расчета, ждут его результата вместо повторного чтения чеков;
+// ошибки не кэшируются. Если все maxEntries записей еще рассчитываются, запрос выполняется без кэша
+// Отчеты из кэша общие для всех вызывающих и не должны изменяться
+func NewCachedBasketKPIService(next BasketKPIService, ttl time.Duration, maxEntries int, logger logger.Logger) BasketKPIService {
+	return &cachedBasketKPIService{
+		next:       next,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		logger:     logger,
+		entries:    make(map[basketKPICacheKey]*basketKPICacheEntry),
+	}
+}
+
+// BuildReport возвращает отчет из кэша или рассчитывает его
+func (s *cachedBasketKPIService) BuildReport(ctx context.Context, period entities.TimeRange, dimension entities.BasketKPIDimension, startDate, endDate time.Time) (*entities.BasketKPIReport, error) {
+	if s.ttl <= 0 {
+		return s.next.BuildReport(ctx, period, dimension, startDate, endDate)
+	}
+	key := basketKPICacheKey{
+		period:    period,
+		dimension: dimension,
+		start:     startDate.UnixNano(),
+		end:       endDate.UnixNano(),
+	}
+
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	if ok && entry.done && time.Now().After(entry.expires) {
+		delete(s.entries, key)
+		ok = false
+	}
+	if ok {
+		s.mu.Unlock()
+		select {
+		case <-entry.ready:
+			return entry.report, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if !s.evict() {
+		s.mu.Unlock()
+		return s.next.BuildReport(ctx, period, dimension, startDate, endDate)
+	}
+	entry = &basketKPICacheEntry{ready: make(chan struct{})}
+	s.entries[key] = entry
+	s.mu.Unlock()
+
+	// Результат общий для всех ожидающих, поэтому отмена запроса первого вызывающего
+	// не должна прерывать расчет для остальных
+	report, err := s.next.BuildReport(context.WithoutCancel(ctx), period, dimension, startDate, endDate)
+
+	s.mu.Lock()
+	entry.report, entry.err = report, err
+	entry.done = true
+	entry.expires = time.Now().Add(s.ttl)
+	if err != nil && s.entries[key] == entry {
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+	close(entry.ready)
+
+	if err == nil {
+		s.logger.Debug(ctx, "Отчет KPI корзины сохранен в кэш", "period", period, "dimension", dimension)
+	}
+	return report, err
+}
+
+// evict освобождает место для новой записи: удаляет истекшие отчеты, а если их нет — отчет,
+// который истекает раньше остальных; записи, которые еще рассчитываются, не удаляются
+// Возвращает false, если кэш заполнен рассчитываемыми записями и новую добавить нельзя
+// Вызывается под мьютексом
+func (s *cachedBasketKPIService) evict() bool {
+	if s.maxEntries <= 0 || len(s.entries) < s.maxEntries {
+		return true
+	}
+
+	now := time.Now()
+	var oldestKey basketKPICacheKey
+	var oldest *basketKPICacheEntry
+	for key, entry := range s.entries {
+		if !entry.done {
+			continue
+		}
+		if now.After(entry.expires) {
+			delete(s.entries, key)
+			continue
+		}
+		if oldest == nil || entry.expires.Before(oldest.expires) {
+			oldestKey, oldest = key, entry
+		}
+	}
+	if len(s.entries) >= s.maxEntries && oldest != nil {
+		delete(s.entries, oldestKey)
+	}
+	return len(s.entries) < s.maxEntries
+}
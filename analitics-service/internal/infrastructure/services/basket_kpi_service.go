@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+)
+
+// minBasketDiscount минимальная скидка в чеке, с которой он считается чеком со скидкой;
+// отсекает расхождения суммы чека из-за округления
+const minBasketDiscount = 0.01
+
+// BasketKPIService определяет интерфейс расчета KPI корзины
+type BasketKPIService interface {
+	// BuildReport рассчитывает KPI корзины по периодам диапазона в выбранном разрезе
+	// с изменением относительно предыдущего периода
+	BuildReport(ctx context.Context, period entities.TimeRange, dimension entities.BasketKPIDimension, startDate, endDate time.Time) (*entities.BasketKPIReport, error)
+}
+
+// basketKPIService реализует интерфейс BasketKPIService
+type basketKPIService struct {
+	transactionRepo repositories.TransactionRepository
+	config          entities.BasketKPIConfig
+	logger          logger.Logger
+}
+
+// basketLine содержит часть чека, относящуюся к одному значению разреза
+type basketLine struct {
+	date       time.Time
+	customerID string
+	slice      string
+	units      int
+	products   int
+	gross      float64
+	revenue    float64
+}
+
+// basketKPIAccumulator накапливает показатели чеков одного значения разреза
+type basketKPIAccumulator struct {
+	baskets         int
+	units           int
+	products        int
+	discounted      int
+	gross           float64
+	revenue         float64
+	discountedGross float64
+	discount        float64
+	customers       map[string]struct{}
+}
+
+// NewBasketKPIService создает новый экземпляр сервиса KPI корзины
+func NewBasketKPIService(
+	transactionRepo repositories.TransactionRepository,
+	config entities.BasketKPIConfig,
+	logger logger.Logger,
+) BasketKPIService {
+	return &basketKPIService{
+		transactionRepo: transactionRepo,
+		config:          config,
+		logger:          logger,
+	}
+}
+
+// BuildReport рассчитывает KPI корзины для каждого периода диапазона
+// Начало диапазона выравнивается на начало периода в часовом поясе точек продаж; последний неполный период
+// сравнивается с отрезком той же длины от начала предыдущего периода
+func (s *basketKPIService) BuildReport(ctx context.Context, period entities.TimeRange, dimension entities.BasketKPIDimension, startDate, endDate time.Time) (*entities.BasketKPIReport, error) {
+	if !period.IsValid() {
+		return nil, fmt.Errorf("%w: invalid period %s", ErrInvalidParameter, period)
+	}
+	if !dimension.IsValid() {
+		return nil, fmt.Errorf("%w: invalid dimension %s", ErrInvalidParameter, dimension)
+	}
+	if !startDate.Before(endDate) {
+		return nil, fmt.Errorf("%w: start date must be before end date", ErrInvalidParameter)
+	}
+	if err := s.config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	location, _ := time.LoadLocation(s.config.Timezone)
+
+	rangeStart := period.Truncate(startDate.In(location))
+	previousStart := period.Advance(rangeStart, -1)
+
+	// Для сегментации нужны покупки клиентов за период ретроспективы до первого сравниваемого периода
+	loadStart := previousStart
+	if dimension == entities.BasketKPIBySegment {
+		loadStart = previousStart.AddDate(0, 0, -s.config.SegmentLookbackDays)
+	}
+
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, loadStart, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+	if len(transactions) == 0 {
+		return nil, ErrInsufficientData
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].Date.Before(transactions[j].Date)
+	})
+
+	var segments []entities.CustomerSegment
+	if dimension == entities.BasketKPIBySegment {
+		segments = s.customerSegments(transactions)
+	}
+
+	lines := make([]basketLine, 0, len(transactions))
+	for i, transaction := range transactions {
+		if transaction.Date.Before(previousStart) {
+			continue
+		}
+		var segment entities.CustomerSegment
+		if segments != nil {
+			segment = segments[i]
+		}
+		lines = append(lines, s.basketLines(transaction, dimension, location, segment)...)
+	}
+
+	report := &entities.BasketKPIReport{
+		Period:      period,
+		Dimension:   dimension,
+		StartDate:   rangeStart,
+		EndDate:     endDate,
+		Timezone:    s.config.Timezone,
+		Periods:     make([]entities.BasketKPIPeriod, 0),
+		GeneratedAt: time.Now(),
+	}
+	for start := rangeStart; start.Before(endDate); start = period.Advance(start, 1) {
+		end := period.Advance(start, 1)
+		prevStart, prevEnd := period.Advance(start, -1), start
+		if end.After(endDate) {
+			end = endDate
+			prevEnd = prevStart.Add(end.Sub(start))
+		}
+
+		current := aggregateBasketLines(lines, start, end)
+		previous := aggregateBasketLines(lines, prevStart, prevEnd)
+		for _, slice := range basketKPISlices(current, previous) {
+			row := entities.BasketKPIPeriod{
+				PeriodStart: start,
+				PeriodEnd:   end,
+				Slice:       slice,
+			}
+			if acc, ok := current[slice]; ok {
+				row.KPIs = acc.kpis()
+			}
+			if acc, ok := previous[slice]; ok {
+				kpis := acc.kpis()
+				change := basketKPIChange(row.KPIs, kpis)
+				row.Previous = &kpis
+				row.Change = &change
+			}
+			report.Periods = append(report.Periods, row)
+		}
+	}
+
+	s.logger.Info(ctx, "Рассчитаны KPI корзины", "period", period, "dimension", dimension,
+		"from", rangeStart, "to", endDate, "rows", len(report.Periods))
+	return report, nil
+}
+
+// customerSegments определяет сегмент клиента для каждого чека по числу его покупок
+// за период ретроспективы до этого чека; транзакции должны быть отсортированы по времени
+func (s *basketKPIService) customerSegments(transactions []entities.Transaction) []entities.CustomerSegment {
+	lookback := s.config.SegmentLookbackDays
+	visits := make(map[string][]time.Time)
+	segments := make([]entities.CustomerSegment, len(transactions))
+
+	for i, transaction := range transactions {
+		dates := visits[transaction.CustomerID]
+		from := transaction.Date.AddDate(0, 0, -lookback)
+		first := sort.Search(len(dates), func(j int) bool { return !dates[j].Before(from) })
+		segments[i] = s.config.SegmentOf(len(dates) - first)
+		visits[transaction.CustomerID] = append(dates, transaction.Date)
+	}
+	return segments
+}
+
+// basketLines делит чек на части по значениям разреза
+// Оплаченная сумма чека распределяется по позициям пропорционально их стоимости после скидок,
+// чтобы скидки на весь чек, например по купону, попадали в выручку разрезов
+func (s *basketKPIService) basketLines(transaction entities.Transaction, dimension entities.BasketKPIDimension, location *time.Location, segment entities.CustomerSegment) []basketLine {
+	slice := ""
+	switch dimension {
+	case entities.BasketKPIByDaypart:
+		name, ok := entities.DaypartAt(s.config.Dayparts, transaction.Date.In(location).Hour())
+		if !ok {
+			return nil
+		}
+		slice = name
+	case entities.BasketKPIBySegment:
+		slice = string(segment)
+	}
+
+	net := 0.0
+	for _, item := range transaction.Items {
+		net += item.Price * float64(item.Quantity) * (1 - item.DiscountPct/100)
+	}
+	scale := 1.0
+	if net > 0 && transaction.TotalAmount > 0 {
+		scale = transaction.TotalAmount / net
+	}
+
+	lines := make(map[string]*basketLine)
+	products := make(map[string]map[string]struct{})
+	order := make([]string, 0, 1)
+	for _, item := range transaction.Items {
+		key := slice
+		if dimension == entities.BasketKPIByCategory {
+			key = item.Category
+			if key == "" {
+				key = "unknown"
+			}
+		}
+
+		line, ok := lines[key]
+		if !ok {
+			line = &basketLine{date: transaction.Date, customerID: transaction.CustomerID, slice: key}
+			lines[key] = line
+			products[key] = make(map[string]struct{})
+			order = append(order, key)
+		}
+		gross := item.Price * float64(item.Quantity)
+		line.units += item.Quantity
+		line.gross += gross
+		line.revenue += gross * (1 - item.DiscountPct/100) * scale
+		products[key][item.ProductID] = struct{}{}
+	}
+
+	result := make([]basketLine, 0, len(order))
+	for _, key := range order {
+		line := lines[key]
+		line.products = len(products[key])
+		result = append(result, *line)
+	}
+	return result
+}
+
+// aggregateBasketLines суммирует части чеков интервала [from, to) по значениям разреза
+// Части чеков должны быть отсортированы по времени
+func aggregateBasketLines(lines []basketLine, from, to time.Time) map[string]*basketKPIAccumulator {
+	first := sort.Search(len(lines), func(i int) bool { return !lines[i].date.Before(from) })
+	last := sort.Search(len(lines), func(i int) bool { return !lines[i].date.Before(to) })
+
+	slices := make(map[string]*basketKPIAccumulator)
+	for _, line := range lines[first:last] {
+		acc, ok := slices[line.slice]
+		if !ok {
+			acc = &basketKPIAccumulator{customers: make(map[string]struct{})}
+			slices[line.slice] = acc
+		}
+		acc.add(line)
+	}
+	return slices
+}
+
+// basketKPISlices возвращает отсортированные значения разреза текущего и предыдущего периодов
+func basketKPISlices(current, previous map[string]*basketKPIAccumulator) []string {
+	seen := make(map[string]struct{}, len(current)+len(previous))
+	slices := make([]string, 0, len(current)+len(previous))
+	for _, group := range []map[string]*basketKPIAccumulator{current, previous} {
+		for slice := range group {
+			if _, ok := seen[slice]; !ok {
+				seen[slice] = struct{}{}
+				slices = append(slices, slice)
+			}
+		}
+	}
+	sort.Strings(slices)
+	return slices
+}
+
+// add учитывает часть чека
+func (a *basketKPIAccumulator) add(line basketLine) {
+	a.baskets++
+	a.units += line.units
+	a.products += line.products
+	a.gross += line.gross
+	a.revenue += line.revenue
+	a.customers[line.customerID] = struct{}{}
+
+	if discount := line.gross - line.revenue; discount >= minBasketDiscount {
+		a.discounted++
+		a.discountedGross += line.gross
+		a.discount += discount
+	}
+}
+
+// kpis рассчитывает KPI по накопленным итогам
+func (a *basketKPIAccumulator) kpis() entities.BasketKPIs {
+	kpis := entities.BasketKPIs{
+		Baskets:      a.baskets,
+		Customers:    len(a.customers),
+		Units:        a.units,
+		GrossRevenue: roundTo(a.gross, 2),
+		Revenue:      roundTo(a.revenue, 2),
+	}
+	if a.baskets > 0 {
+		baskets := float64(a.baskets)
+		kpis.AverageOrderValue = roundTo(a.revenue/baskets, 2)
+		kpis.ItemsPerBasket = roundTo(float64(a.units)/baskets, 2)
+		kpis.UniqueProductsPerBasket = roundTo(float64(a.products)/baskets, 2)
+		kpis.DiscountPenetrationPct = roundTo(float64(a.discounted)/baskets*100, 2)
+	}
+	if a.discountedGross > 0 {
+		kpis.AverageDiscountDepthPct = roundTo(a.discount/a.discountedGross*100, 2)
+	}
+	if len(a.customers) > 0 {
+		kpis.RevenuePerCustomer = roundTo(a.revenue/float64(len(a.customers)), 2)
+	}
+	return kpis
+}
+
+// basketKPIChange рассчитывает изменение KPI относительно предыдущего периода
+func basketKPIChange(current, previous entities.BasketKPIs) entities.BasketKPIChange {
+	return entities.BasketKPIChange{
+		BasketsPct:                 percentChange(float64(current.Baskets), float64(previous.Baskets)),
+		RevenuePct:                 percentChange(current.Revenue, previous.Revenue),
+		AverageOrderValuePct:       percentChange(current.AverageOrderValue, previous.AverageOrderValue),
+		ItemsPerBasketPct:          percentChange(current.ItemsPerBasket, previous.ItemsPerBasket),
+		UniqueProductsPerBasketPct: percentChange(current.UniqueProductsPerBasket, previous.UniqueProductsPerBasket),
+		RevenuePerCustomerPct:      percentChange(current.RevenuePerCustomer, previous.RevenuePerCustomer),
+		DiscountPenetrationPP:      roundTo(current.DiscountPenetrationPct-previous.DiscountPenetrationPct, 2),
+		AverageDiscountDepthPP:     roundTo(current.AverageDiscountDepthPct-previous.AverageDiscountDepthPct, 2),
+	}
+}
+
+// percentChange возвращает изменение в процентах или 0, если базовое значение нулевое
+func percentChange(current, previous float64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	return roundTo((current/previous-1)*100, 2)
+}
@@ -0,0 +1,276 @@
+// internal/infrastructure/services/basket_kpi_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// testBasket возвращает оплаченный клиентом чек с позициями
+func testBasket(id, customerID string, at time.Time, total float64, items ...entities.Item) entities.Transaction {
+	transaction := entities.Transaction{CustomerID: customerID, Date: at, TotalAmount: total, Items: items}
+	transaction.ID = id
+	return transaction
+}
+
+// testBasketItem возвращает позицию чека товара категории
+func testBasketItem(productID, category string, price float64, quantity int, discountPct float64) entities.Item {
+	return entities.Item{ProductID: productID, Name: productID, Category: category, Price: price, Quantity: quantity, DiscountPct: discountPct}
+}
+
+// testBasketKPITransactions возвращает чеки 1 и 2 марта:
+// 1 марта — C1 покупает кофе за 100 утром, C2 две булки по 50 в обед;
+// 2 марта — C1 утром берет два кофе со скидкой 10% и булку, C3 в обед кофе с купоном на 10, C1 вечером печенье за 20.
+// Две февральские покупки C1 делают его лояльным клиентом ко 2 марта
+func testBasketKPITransactions() []entities.Transaction {
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	return []entities.Transaction{
+		testBasket("T0", "C1", at(1, 9).AddDate(0, 0, -20), 100, testBasketItem("P1", "coffee", 100, 1, 0)),
+		testBasket("T00", "C1", at(1, 9).AddDate(0, 0, -10), 100, testBasketItem("P1", "coffee", 100, 1, 0)),
+		testBasket("T1", "C1", at(1, 9), 100, testBasketItem("P1", "coffee", 100, 1, 0)),
+		testBasket("T2", "C2", at(1, 13), 100, testBasketItem("P2", "bakery", 50, 2, 0)),
+		testBasket("T3", "C1", at(2, 9), 230, testBasketItem("P1", "coffee", 100, 2, 10), testBasketItem("P2", "bakery", 50, 1, 0)),
+		testBasket("T4", "C3", at(2, 13), 90, testBasketItem("P1", "coffee", 100, 1, 0)),
+		testBasket("T5", "C1", at(2, 20), 20, testBasketItem("P3", "bakery", 20, 1, 0)),
+	}
+}
+
+func TestBuildBasketKPIReportTotal(t *testing.T) {
+	start, end := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+	service := services.NewBasketKPIService(&memoryTransactionRepository{transactions: testBasketKPITransactions()},
+		entities.DefaultBasketKPIConfig(), logger.NewLogger("ERROR"))
+
+	report, err := service.BuildReport(context.Background(), entities.Daily, entities.BasketKPITotal, start, end)
+	if err != nil {
+		t.Fatalf("BuildReport() error = %v", err)
+	}
+	if len(report.Periods) != 1 {
+		t.Fatalf("periods = %d, want 1", len(report.Periods))
+	}
+	row := report.Periods[0]
+
+	// Скидка 20 на кофе C1 и купон C3 на 10 дают 30 из 350 выручки без скидок чеков со скидкой
+	wantKPIs := entities.BasketKPIs{Baskets: 3, Customers: 2, Units: 5, GrossRevenue: 370, Revenue: 340,
+		AverageOrderValue: 113.33, ItemsPerBasket: 1.67, UniqueProductsPerBasket: 1.33,
+		DiscountPenetrationPct: 66.67, AverageDiscountDepthPct: 8.57, RevenuePerCustomer: 170}
+	wantPrevious := entities.BasketKPIs{Baskets: 2, Customers: 2, Units: 3, GrossRevenue: 200, Revenue: 200,
+		AverageOrderValue: 100, ItemsPerBasket: 1.5, UniqueProductsPerBasket: 1, RevenuePerCustomer: 100}
+	wantChange := entities.BasketKPIChange{BasketsPct: 50, RevenuePct: 70, AverageOrderValuePct: 13.33,
+		ItemsPerBasketPct: 11.33, UniqueProductsPerBasketPct: 33, RevenuePerCustomerPct: 70,
+		DiscountPenetrationPP: 66.67, AverageDiscountDepthPP: 8.57}
+
+	if !row.PeriodStart.Equal(start) || !row.PeriodEnd.Equal(end) || row.Slice != "" {
+		t.Errorf("row = %s..%s slice %q, want the whole day without slice", row.PeriodStart, row.PeriodEnd, row.Slice)
+	}
+	if !reflect.DeepEqual(row.KPIs, wantKPIs) {
+		t.Errorf("kpis = %+v, want %+v", row.KPIs, wantKPIs)
+	}
+	if row.Previous == nil || row.Change == nil {
+		t.Fatalf("previous = %v, change = %v, want both", row.Previous, row.Change)
+	}
+	if !reflect.DeepEqual(*row.Previous, wantPrevious) {
+		t.Errorf("previous = %+v, want %+v", *row.Previous, wantPrevious)
+	}
+	if !reflect.DeepEqual(*row.Change, wantChange) {
+		t.Errorf("change = %+v, want %+v", *row.Change, wantChange)
+	}
+}
+
+func TestBuildBasketKPIReportSlices(t *testing.T) {
+	start := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	type wantRow struct {
+		slice        string
+		baskets      int
+		revenue      float64
+		penetration  float64
+		wantPrevious bool
+		revenuePct   float64
+	}
+
+	tests := []struct {
+		name      string
+		dimension entities.BasketKPIDimension
+		end       time.Time
+		want      []wantRow
+	}{
+		{
+			// Оплаченная сумма чека T3 делится между кофе и булкой по стоимости после скидок
+			name:      "category",
+			dimension: entities.BasketKPIByCategory,
+			end:       start.AddDate(0, 0, 1),
+			want: []wantRow{
+				{slice: "bakery", baskets: 2, revenue: 70, wantPrevious: true, revenuePct: -30},
+				{slice: "coffee", baskets: 2, revenue: 270, penetration: 100, wantPrevious: true, revenuePct: 170},
+			},
+		},
+		{
+			name:      "daypart",
+			dimension: entities.BasketKPIByDaypart,
+			end:       start.AddDate(0, 0, 1),
+			want: []wantRow{
+				{slice: "breakfast", baskets: 1, revenue: 230, penetration: 100, wantPrevious: true, revenuePct: 130},
+				{slice: "evening", baskets: 1, revenue: 20},
+				{slice: "lunch", baskets: 1, revenue: 90, penetration: 100, wantPrevious: true, revenuePct: -10},
+			},
+		},
+		{
+			// C1 лоялен после четырех покупок за период ретроспективы; сегмент, который был только
+			// в предыдущем периоде, выводится с нулевыми KPI
+			name:      "segment",
+			dimension: entities.BasketKPIBySegment,
+			end:       start.AddDate(0, 0, 1),
+			want: []wantRow{
+				{slice: "loyal", baskets: 2, revenue: 250, penetration: 50},
+				{slice: "new", baskets: 1, revenue: 90, penetration: 100, wantPrevious: true, revenuePct: -10},
+				{slice: "returning", wantPrevious: true, revenuePct: -100},
+			},
+		},
+		{
+			// Неполный период сравнивается с тем же отрезком предыдущего дня
+			name:      "partial period",
+			dimension: entities.BasketKPITotal,
+			end:       start.Add(12 * time.Hour),
+			want: []wantRow{
+				{baskets: 1, revenue: 230, penetration: 100, wantPrevious: true, revenuePct: 130},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewBasketKPIService(&memoryTransactionRepository{transactions: testBasketKPITransactions()},
+				entities.DefaultBasketKPIConfig(), logger.NewLogger("ERROR"))
+			report, err := service.BuildReport(context.Background(), entities.Daily, tt.dimension, start, tt.end)
+			if err != nil {
+				t.Fatalf("BuildReport() error = %v", err)
+			}
+
+			if len(report.Periods) != len(tt.want) {
+				t.Fatalf("rows = %+v, want %d", report.Periods, len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := report.Periods[i]
+				if got.Slice != want.slice || got.KPIs.Baskets != want.baskets || got.KPIs.Revenue != want.revenue ||
+					got.KPIs.DiscountPenetrationPct != want.penetration {
+					t.Errorf("row %d = %q: %d baskets, revenue %.2f, penetration %.2f; want %q: %d, %.2f, %.2f", i, got.Slice,
+						got.KPIs.Baskets, got.KPIs.Revenue, got.KPIs.DiscountPenetrationPct, want.slice, want.baskets,
+						want.revenue, want.penetration)
+				}
+				if (got.Change != nil) != want.wantPrevious {
+					t.Errorf("row %q change = %v, want change %v", got.Slice, got.Change, want.wantPrevious)
+					continue
+				}
+				if got.Change != nil && got.Change.RevenuePct != want.revenuePct {
+					t.Errorf("row %q revenue change = %.2f%%, want %.2f%%", got.Slice, got.Change.RevenuePct, want.revenuePct)
+				}
+			}
+		})
+	}
+}
+
+func TestBasketKPIServiceErrors(t *testing.T) {
+	start, end := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+	transactions := &memoryTransactionRepository{transactions: testBasketKPITransactions()}
+
+	badTimezone := entities.DefaultBasketKPIConfig()
+	badTimezone.Timezone = "Mars/Olympus"
+
+	tests := []struct {
+		name      string
+		config    entities.BasketKPIConfig
+		period    entities.TimeRange
+		dimension entities.BasketKPIDimension
+		start     time.Time
+		end       time.Time
+		want      error
+	}{
+		{name: "invalid period", config: entities.DefaultBasketKPIConfig(), period: "quarterly", dimension: entities.BasketKPITotal,
+			start: start, end: end, want: services.ErrInvalidParameter},
+		{name: "invalid dimension", config: entities.DefaultBasketKPIConfig(), period: entities.Daily, dimension: "store",
+			start: start, end: end, want: services.ErrInvalidParameter},
+		{name: "reversed range", config: entities.DefaultBasketKPIConfig(), period: entities.Daily, dimension: entities.BasketKPITotal,
+			start: end, end: start, want: services.ErrInvalidParameter},
+		{name: "invalid timezone", config: badTimezone, period: entities.Daily, dimension: entities.BasketKPITotal,
+			start: start, end: end, want: services.ErrInvalidParameter},
+		{name: "no transactions", config: entities.DefaultBasketKPIConfig(), period: entities.Daily, dimension: entities.BasketKPITotal,
+			start: start.AddDate(1, 0, 0), end: end.AddDate(1, 0, 0), want: services.ErrInsufficientData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewBasketKPIService(transactions, tt.config, logger.NewLogger("ERROR"))
+			if _, err := service.BuildReport(context.Background(), tt.period, tt.dimension, tt.start, tt.end); !errors.Is(err, tt.want) {
+				t.Errorf("BuildReport() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// countingBasketKPIService считает обращения к расчету отчета и возвращает заданную ошибку
+type countingBasketKPIService struct {
+	calls int
+	err   error
+}
+
+func (s *countingBasketKPIService) BuildReport(_ context.Context, period entities.TimeRange, dimension entities.BasketKPIDimension, startDate, endDate time.Time) (*entities.BasketKPIReport, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &entities.BasketKPIReport{Period: period, Dimension: dimension, StartDate: startDate, EndDate: endDate}, nil
+}
+
+func TestCachedBasketKPIService(t *testing.T) {
+	start, end := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		ttl        time.Duration
+		maxEntries int
+		err        error
+		dimensions []entities.BasketKPIDimension
+		wantCalls  int
+	}{
+		{name: "repeated query is cached", ttl: time.Minute, maxEntries: 10,
+			dimensions: []entities.BasketKPIDimension{entities.BasketKPITotal, entities.BasketKPITotal}, wantCalls: 1},
+		{name: "different queries", ttl: time.Minute, maxEntries: 10,
+			dimensions: []entities.BasketKPIDimension{entities.BasketKPITotal, entities.BasketKPIByCategory, entities.BasketKPITotal}, wantCalls: 2},
+		// Запрос, вытесненный из заполненного кэша, рассчитывается заново
+		{name: "evicted by size", ttl: time.Minute, maxEntries: 1,
+			dimensions: []entities.BasketKPIDimension{entities.BasketKPITotal, entities.BasketKPIByCategory, entities.BasketKPITotal}, wantCalls: 3},
+		{name: "cache disabled", maxEntries: 10,
+			dimensions: []entities.BasketKPIDimension{entities.BasketKPITotal, entities.BasketKPITotal}, wantCalls: 2},
+		{name: "errors are not cached", ttl: time.Minute, maxEntries: 10, err: services.ErrInsufficientData,
+			dimensions: []entities.BasketKPIDimension{entities.BasketKPITotal, entities.BasketKPITotal}, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &countingBasketKPIService{err: tt.err}
+			service := services.NewCachedBasketKPIService(next, tt.ttl, tt.maxEntries, logger.NewLogger("ERROR"))
+
+			for _, dimension := range tt.dimensions {
+				report, err := service.BuildReport(context.Background(), entities.Daily, dimension, start, end)
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("BuildReport() error = %v, want %v", err, tt.err)
+				}
+				if err == nil && report.Dimension != dimension {
+					t.Errorf("report dimension = %s, want %s", report.Dimension, dimension)
+				}
+			}
+			if next.calls != tt.wantCalls {
+				t.Errorf("calculations = %d, want %d", next.calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+)
+
+// maxBundleBasketSize максимальное количество разных товаров в чеке, для которого перебираются тройки
+const maxBundleBasketSize = 20
+
+// BundleService определяет интерфейс поиска наборов товаров и расчета их цены
+type BundleService interface {
+	// RecommendBundles находит частые наборы из 2–3 товаров с высоким lift, рассчитывает цену набора
+	// с соблюдением минимальной маржи и ожидаемый прирост присоединения, сохраняет предложения
+	// Наборы, по которым команда меню уже приняла решение, повторно не предлагаются
+	RecommendBundles(ctx context.Context, startDate, endDate time.Time, options entities.BundleOptions) ([]entities.ProductBundle, error)
+
+	// ReviewBundle фиксирует решение команды меню по предложенному набору
+	ReviewBundle(ctx context.Context, bundleID string, approve bool, reviewer string) (*entities.ProductBundle, error)
+
+	// GetBundles возвращает наборы с указанным статусом
+	GetBundles(ctx context.Context, status entities.BundleStatus) ([]entities.ProductBundle, error)
+}
+
+// bundleService реализует интерфейс BundleService
+type bundleService struct {
+	transactionRepo repositories.TransactionRepository
+	productRepo     repositories.ProductRepository
+	bundleRepo      repositories.BundleRepository
+	logger          logger.Logger
+}
+
+// attachDay содержит дневную статистику присоединения товаров к якорному
+type attachDay struct {
+	anchorBaskets int
+	attached      int
+	discountSum   float64
+	discountCount int
+}
+
+// NewBundleService создает новый экземпляр сервиса наборов товаров
+func NewBundleService(
+	transactionRepo repositories.TransactionRepository,
+	productRepo repositories.ProductRepository,
+	bundleRepo repositories.BundleRepository,
+	logger logger.Logger,
+) BundleService {
+	return &bundleService{
+		transactionRepo: transactionRepo,
+		productRepo:     productRepo,
+		bundleRepo:      bundleRepo,
+		logger:          logger,
+	}
+}
+
+// RecommendBundles находит наборы товаров, рассчитывает их цену и сохраняет предложения
+func (s *bundleService) RecommendBundles(ctx context.Context, startDate, endDate time.Time, options entities.BundleOptions) ([]entities.ProductBundle, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+	if len(transactions) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	allProducts, err := s.productRepo.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+	products := make(map[string]entities.Product, len(allProducts))
+	for _, product := range allProducts {
+		products[product.ID] = product
+	}
+
+	// Наборы, одобренные или отклоненные ранее, не предлагаются повторно
+	reviewed := make(map[string]bool)
+	for _, status := range []entities.BundleStatus{entities.BundleApproved, entities.BundleRejected} {
+		bundles, err := s.bundleRepo.GetBundlesByStatus(ctx, status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve %s bundles: %w", status, err)
+		}
+		for _, bundle := range bundles {
+			reviewed[bundle.ID] = true
+		}
+	}
+
+	baskets := make([][]string, 0, len(transactions))
+	for _, transaction := range transactions {
+		baskets = append(baskets, basketProductIDs(transaction))
+	}
+	itemsets, single := mineBundleItemsets(baskets, options.MinSupport, options.MaxItems)
+
+	total := float64(len(baskets))
+	now := time.Now()
+	bundles := make([]entities.ProductBundle, 0)
+	for _, itemset := range itemsets {
+		ids := make([]string, 0, len(itemset.Items))
+		expected := 1.0
+		anchor := ""
+		for _, item := range itemset.Items {
+			ids = append(ids, item.ProductID)
+			expected *= float64(single[item.ProductID]) / total
+			if anchor == "" || single[item.ProductID] > single[anchor] {
+				anchor = item.ProductID
+			}
+		}
+
+		lift := itemset.Support / expected
+		if lift < options.MinLift {
+			continue
+		}
+
+		// Если набор и так почти всегда покупают целиком, скидка на него только теряет маржу
+		coPurchase := float64(itemset.Count) / float64(single[anchor])
+		if coPurchase >= options.MaxCoPurchaseRate {
+			continue
+		}
+
+		bundleID := "bundle:" + strings.Join(ids, "+")
+		if reviewed[bundleID] {
+			continue
+		}
+
+		bundle, ok := priceBundle(ids, anchor, products, options)
+		if !ok {
+			continue
+		}
+
+		bundle.ID = bundleID
+		bundle.CreatedAt = now
+		bundle.UpdatedAt = now
+		bundle.Support = itemset.Support
+		bundle.Lift = lift
+		bundle.CoPurchaseRate = coPurchase
+		bundle.CurrentAttachRate = coPurchase
+		bundle.Status = entities.BundleProposed
+		bundle.PeriodStart = startDate
+		bundle.PeriodEnd = endDate
+
+		// Для покупателя якорного товара выгода набора — скидка на присоединяемые товары
+		addonDiscount := (bundle.ListPrice - bundle.BundlePrice) / (bundle.ListPrice - products[anchor].Price) * 100
+		bundle.IncrementalAttachRate, bundle.ElasticityFitted = estimateAttachUplift(transactions, anchor, ids, coPurchase, addonDiscount, options)
+
+		bundles = append(bundles, bundle)
+	}
+
+	sort.Slice(bundles, func(i, j int) bool {
+		if bundles[i].Lift != bundles[j].Lift {
+			return bundles[i].Lift > bundles[j].Lift
+		}
+		return bundles[i].ID < bundles[j].ID
+	})
+	if len(bundles) > options.MaxBundles {
+		bundles = bundles[:options.MaxBundles]
+	}
+
+	if len(bundles) > 0 {
+		if err := s.bundleRepo.SaveBundles(ctx, bundles); err != nil {
+			return nil, fmt.Errorf("failed to save bundles: %w", err)
+		}
+	}
+
+	s.logger.Info(ctx, "Предложены наборы товаров", "transactions", len(transactions),
+		"itemsets", len(itemsets), "bundles", len(bundles))
+	return bundles, nil
+}
+
+// ReviewBundle фиксирует решение команды меню по предложенному набору
+func (s *bundleService) ReviewBundle(ctx context.Context, bundleID string, approve bool, reviewer string) (*entities.ProductBundle, error) {
+	if reviewer == "" {
+		return nil, fmt.Errorf("%w: reviewer is required", ErrInvalidParameter)
+	}
+
+	bundle, err := s.bundleRepo.GetBundleByID(ctx, bundleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundle: %w", err)
+	}
+
+	if bundle.Status != entities.BundleProposed {
+		return nil, fmt.Errorf("%w: bundle %s is already %s", ErrInvalidParameter, bundleID, bundle.Status)
+	}
+
+	bundle.Status = entities.BundleRejected
+	if approve {
+		bundle.Status = entities.BundleApproved
+	}
+	bundle.ReviewedBy = reviewer
+	bundle.ReviewedAt = time.Now()
+	bundle.UpdatedAt = bundle.ReviewedAt
+
+	if err := s.bundleRepo.UpdateBundle(ctx, bundle); err != nil {
+		return nil, fmt.Errorf("failed to update bundle: %w", err)
+	}
+
+	s.logger.Info(ctx, "Решение по набору товаров", "bundle_id", bundleID, "status", bundle.Status, "reviewer", reviewer)
+	return &bundle, nil
+}
+
+// GetBundles возвращает наборы с указанным статусом
+func (s *bundleService) GetBundles(ctx context.Context, status entities.BundleStatus) ([]entities.ProductBundle, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown bundle status %q", ErrInvalidParameter, status)
+	}
+
+	bundles, err := s.bundleRepo.GetBundlesByStatus(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundles: %w", err)
+	}
+
+	return bundles, nil
+}
+
+// priceBundle рассчитывает цену набора: желаемая скидка, но не ниже цены, дающей минимальную маржу
+// Наборы с неактивными товарами, товарами без себестоимости или без запаса для скидки пропускаются
+func priceBundle(ids []string, anchor string, products map[string]entities.Product, options entities.BundleOptions) (entities.ProductBundle, bool) {
+	bundle := entities.ProductBundle{
+		ProductIDs:      ids,
+		AnchorProductID: anchor,
+	}
+
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		product, ok := products[id]
+		if !ok || !product.IsActive || product.Price <= 0 || product.Cost <= 0 {
+			return entities.ProductBundle{}, false
+		}
+		names = append(names, product.Name)
+		bundle.ListPrice += product.Price
+		bundle.TotalCost += product.Cost
+	}
+	bundle.Name = strings.Join(names, " + ")
+
+	target := bundle.ListPrice * (1 - options.TargetDiscountPct/100)
+	floor := bundle.TotalCost / (1 - options.MinMarginPct/100)
+	price := math.Ceil(math.Max(target, floor)*100) / 100
+	if price >= bundle.ListPrice {
+		return entities.ProductBundle{}, false
+	}
+
+	bundle.BundlePrice = price
+	bundle.DiscountPct = roundTo((1-price/bundle.ListPrice)*100, 2)
+	bundle.MarginPct = roundTo((price-bundle.TotalCost)/price*100, 2)
+	return bundle, true
+}
+
+// estimateAttachUplift оценивает прирост доли чеков с якорным товаром, в которые попадает весь набор
+// Чувствительность оценивается по дням: доля присоединения против средней скидки на присоединяемые товары
+// Если дней мало, скидки не менялись или зависимость не положительна, используется относительная
+// чувствительность по умолчанию
+func estimateAttachUplift(transactions []entities.Transaction, anchor string, ids []string, current, addonDiscount float64, options entities.BundleOptions) (float64, bool) {
+	days := make(map[time.Time]*attachDay)
+	for _, transaction := range transactions {
+		discounts := make(map[string]float64, len(transaction.Items))
+		for _, item := range transaction.Items {
+			discounts[item.ProductID] = item.DiscountPct
+		}
+
+		day := days[truncateToDay(transaction.Date)]
+		if day == nil {
+			day = &attachDay{}
+			days[truncateToDay(transaction.Date)] = day
+		}
+
+		hasAll := true
+		for _, id := range ids {
+			discount, ok := discounts[id]
+			if !ok {
+				hasAll = false
+				continue
+			}
+			if id != anchor {
+				day.discountSum += discount
+				day.discountCount++
+			}
+		}
+		if _, ok := discounts[anchor]; ok {
+			day.anchorBaskets++
+			if hasAll {
+				day.attached++
+			}
+		}
+	}
+
+	var x, y []float64
+	for _, day := range days {
+		if day.anchorBaskets == 0 || day.discountCount == 0 {
+			continue
+		}
+		x = append(x, day.discountSum/float64(day.discountCount))
+		y = append(y, float64(day.attached)/float64(day.anchorBaskets))
+	}
+
+	uplift := current * options.DefaultAttachElasticity * addonDiscount / 100
+	fitted := false
+	if len(x) >= options.MinObservationDays && hasVariation(x) {
+		if _, slope, _ := simpleLinearFit(x, y); slope > 0 {
+			uplift = slope * addonDiscount
+			fitted = true
+		}
+	}
+
+	return roundTo(math.Min(uplift, 1-current), 4), fitted
+}
+
+// mineBundleItemsets находит частые наборы из 2–3 товаров и возвращает также число чеков с каждым товаром
+// Тройки считаются только из пар, которые сами являются частыми
+func mineBundleItemsets(baskets [][]string, minSupport float64, maxItems int) ([]entities.FrequentItemset, map[string]int) {
+	single := make(map[string]int)
+	pairs := make(map[[2]string]int)
+	for _, ids := range baskets {
+		for i, id := range ids {
+			single[id]++
+			for _, other := range ids[i+1:] {
+				pairs[[2]string{id, other}]++
+			}
+		}
+	}
+
+	total := float64(len(baskets))
+	minCount := int(math.Ceil(minSupport * total))
+	frequentPairs := make(map[[2]string]bool)
+	itemsets := make([]entities.FrequentItemset, 0)
+	for pair, count := range pairs {
+		if count < minCount {
+			continue
+		}
+		frequentPairs[pair] = true
+		itemsets = append(itemsets, newBundleItemset([]string{pair[0], pair[1]}, count, total))
+	}
+
+	if maxItems >= 3 {
+		triples := make(map[[3]string]int)
+		for _, ids := range baskets {
+			if len(ids) > maxBundleBasketSize {
+				continue
+			}
+			for i := 0; i < len(ids); i++ {
+				for j := i + 1; j < len(ids); j++ {
+					if !frequentPairs[[2]string{ids[i], ids[j]}] {
+						continue
+					}
+					for k := j + 1; k < len(ids); k++ {
+						if frequentPairs[[2]string{ids[i], ids[k]}] && frequentPairs[[2]string{ids[j], ids[k]}] {
+							triples[[3]string{ids[i], ids[j], ids[k]}]++
+						}
+					}
+				}
+			}
+		}
+		for triple, count := range triples {
+			if count >= minCount {
+				itemsets = append(itemsets, newBundleItemset([]string{triple[0], triple[1], triple[2]}, count, total))
+			}
+		}
+	}
+
+	return itemsets, single
+}
+
+// newBundleItemset создает частый набор из отсортированных ID товаров
+func newBundleItemset(ids []string, count int, total float64) entities.FrequentItemset {
+	items := make([]entities.Item, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, entities.Item{ProductID: id})
+	}
+	return entities.FrequentItemset{
+		Items:   items,
+		Support: float64(count) / total,
+		Count:   count,
+	}
+}
+
+// basketProductIDs возвращает отсортированные уникальные ID товаров чека
+func basketProductIDs(transaction entities.Transaction) []string {
+	seen := make(map[string]bool, len(transaction.Items))
+	ids := make([]string, 0, len(transaction.Items))
+	for _, item := range transaction.Items {
+		if seen[item.ProductID] {
+			continue
+		}
+		seen[item.ProductID] = true
+		ids = append(ids, item.ProductID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// hasVariation проверяет, что значения выборки не совпадают
+func hasVariation(values []float64) bool {
+	for _, value := range values[1:] {
+		if value != values[0] {
+			return true
+		}
+	}
+	return false
+}
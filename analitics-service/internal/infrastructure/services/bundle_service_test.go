@@ -0,0 +1,327 @@
+// internal/infrastructure/services/bundle_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryBundleRepository хранит предложенные наборы в памяти
+type memoryBundleRepository struct {
+	bundles map[string]entities.ProductBundle
+}
+
+func newMemoryBundleRepository(bundles ...entities.ProductBundle) *memoryBundleRepository {
+	repo := &memoryBundleRepository{bundles: make(map[string]entities.ProductBundle)}
+	for _, bundle := range bundles {
+		repo.bundles[bundle.ID] = bundle
+	}
+	return repo
+}
+
+func (r *memoryBundleRepository) SaveBundles(_ context.Context, bundles []entities.ProductBundle) error {
+	for _, bundle := range bundles {
+		r.bundles[bundle.ID] = bundle
+	}
+	return nil
+}
+
+func (r *memoryBundleRepository) GetBundleByID(_ context.Context, bundleID string) (entities.ProductBundle, error) {
+	bundle, ok := r.bundles[bundleID]
+	if !ok {
+		return entities.ProductBundle{}, fmt.Errorf("bundle %s not found", bundleID)
+	}
+	return bundle, nil
+}
+
+func (r *memoryBundleRepository) GetBundlesByStatus(_ context.Context, status entities.BundleStatus) ([]entities.ProductBundle, error) {
+	var result []entities.ProductBundle
+	for _, bundle := range r.bundles {
+		if bundle.Status == status {
+			result = append(result, bundle)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *memoryBundleRepository) UpdateBundle(_ context.Context, bundle entities.ProductBundle) error {
+	r.bundles[bundle.ID] = bundle
+	return nil
+}
+
+// testBundleBaskets возвращает 100 чеков одного дня: 30 — кофе A с круассаном B, 30 — только A,
+// 10 — сахар X с салфетками Y, 1 — только X, 10 — только B, 19 — вода Z
+func testBundleBaskets(day time.Time) []entities.Transaction {
+	groups := []struct {
+		count    int
+		products []string
+	}{
+		{30, []string{"A", "B"}},
+		{30, []string{"A"}},
+		{10, []string{"X", "Y"}},
+		{1, []string{"X"}},
+		{10, []string{"B"}},
+		{19, []string{"Z"}},
+	}
+
+	var transactions []entities.Transaction
+	for _, group := range groups {
+		for i := 0; i < group.count; i++ {
+			transactions = append(transactions, testTransaction(fmt.Sprintf("T%d", len(transactions)+1), day, 1, group.products...))
+		}
+	}
+	return transactions
+}
+
+// testBundleProducts возвращает каталог: A — 200 (себестоимость 60), B — 100 (40), X — 10 (1), Y — 5 (1), Z — 50 (20)
+func testBundleProducts() []entities.Product {
+	catalog := []struct {
+		id          string
+		price, cost float64
+	}{
+		{"A", 200, 60}, {"B", 100, 40}, {"X", 10, 1}, {"Y", 5, 1}, {"Z", 50, 20},
+	}
+
+	products := make([]entities.Product, 0, len(catalog))
+	for _, item := range catalog {
+		product := testProduct(item.id, "menu", item.price)
+		product.Cost = item.cost
+		products = append(products, product)
+	}
+	return products
+}
+
+// testBundleOptions возвращает параметры поиска пар с поддержкой от 5%
+func testBundleOptions() entities.BundleOptions {
+	options := entities.DefaultBundleOptions()
+	options.MinSupport = 0.05
+	options.MaxItems = 2
+	return options
+}
+
+func TestRecommendBundlesCandidates(t *testing.T) {
+	day := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// A+B: поддержка 0.3 при ожидаемой 0.6·0.4, lift 1.25, A покупают с B в половине чеков;
+	// X+Y: lift 9.09, но X почти всегда покупают вместе с Y (10 из 11 чеков)
+	tests := []struct {
+		name     string
+		modify   func(options *entities.BundleOptions)
+		reviewed []entities.ProductBundle
+		wantIDs  []string
+	}{
+		{name: "high lift pair", wantIDs: []string{"bundle:A+B"}},
+		{name: "lift below threshold", modify: func(options *entities.BundleOptions) { options.MinLift = 1.3 }},
+		{
+			name:    "co-purchase filter relaxed",
+			modify:  func(options *entities.BundleOptions) { options.MaxCoPurchaseRate = 0.95 },
+			wantIDs: []string{"bundle:X+Y", "bundle:A+B"},
+		},
+		{
+			name:     "rejected bundle not proposed again",
+			reviewed: []entities.ProductBundle{{BaseEntity: entities.BaseEntity{ID: "bundle:A+B"}, Status: entities.BundleRejected}},
+		},
+		{name: "limited number of bundles", modify: func(options *entities.BundleOptions) {
+			options.MaxCoPurchaseRate = 0.95
+			options.MaxBundles = 1
+		}, wantIDs: []string{"bundle:X+Y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := testBundleOptions()
+			if tt.modify != nil {
+				tt.modify(&options)
+			}
+			repo := newMemoryBundleRepository(tt.reviewed...)
+			service := services.NewBundleService(&memoryTransactionRepository{transactions: testBundleBaskets(day)},
+				&memoryProductRepository{products: testBundleProducts()}, repo, logger.NewLogger("ERROR"))
+
+			bundles, err := service.RecommendBundles(context.Background(), day, day.Add(time.Hour), options)
+			if err != nil {
+				t.Fatalf("RecommendBundles() error = %v", err)
+			}
+
+			var ids []string
+			for _, bundle := range bundles {
+				ids = append(ids, bundle.ID)
+				if stored, ok := repo.bundles[bundle.ID]; !ok || stored.Status != entities.BundleProposed {
+					t.Errorf("bundle %s not saved as proposed", bundle.ID)
+				}
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("bundles = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestRecommendBundlesPricing(t *testing.T) {
+	day := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// Сумма цен A+B — 300, желаемая скидка 10% дает 270, минимальная маржа 30% — себестоимость / 0.7
+	tests := []struct {
+		name         string
+		modify       func(a, b *entities.Product)
+		wantPrice    float64
+		wantDiscount float64
+		wantMargin   float64
+	}{
+		{name: "target discount above margin floor", wantPrice: 270, wantDiscount: 10, wantMargin: 62.96},
+		{
+			// Себестоимость 200: цена не ниже 285.714..., округляется вверх до копеек
+			name:      "price raised to margin floor",
+			modify:    func(a, b *entities.Product) { a.Cost, b.Cost = 120, 80 },
+			wantPrice: 285.72, wantDiscount: 4.76, wantMargin: 30,
+		},
+		{name: "no room for discount", modify: func(a, b *entities.Product) { a.Cost, b.Cost = 150, 80 }},
+		{name: "inactive product", modify: func(a, b *entities.Product) { b.IsActive = false }},
+		{name: "unknown cost", modify: func(a, b *entities.Product) { b.Cost = 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products := testBundleProducts()
+			if tt.modify != nil {
+				tt.modify(&products[0], &products[1])
+			}
+			service := services.NewBundleService(&memoryTransactionRepository{transactions: testBundleBaskets(day)},
+				&memoryProductRepository{products: products}, newMemoryBundleRepository(), logger.NewLogger("ERROR"))
+
+			bundles, err := service.RecommendBundles(context.Background(), day, day.Add(time.Hour), testBundleOptions())
+			if err != nil {
+				t.Fatalf("RecommendBundles() error = %v", err)
+			}
+
+			if tt.wantPrice == 0 {
+				if len(bundles) != 0 {
+					t.Errorf("bundles = %+v, want none", bundles)
+				}
+				return
+			}
+			if len(bundles) != 1 {
+				t.Fatalf("bundles = %d, want 1", len(bundles))
+			}
+			got := bundles[0]
+			if got.BundlePrice != tt.wantPrice || got.DiscountPct != tt.wantDiscount || got.MarginPct != tt.wantMargin {
+				t.Errorf("price = %.2f (discount %.2f%%, margin %.2f%%), want %.2f (%.2f%%, %.2f%%)",
+					got.BundlePrice, got.DiscountPct, got.MarginPct, tt.wantPrice, tt.wantDiscount, tt.wantMargin)
+			}
+			if got.AnchorProductID != "A" || got.ListPrice != 300 || got.Name != "A + B" {
+				t.Errorf("bundle = %s anchored on %s, list price %.2f", got.Name, got.AnchorProductID, got.ListPrice)
+			}
+		})
+	}
+}
+
+func TestRecommendBundlesAttachRate(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// single — один день: чувствительность по умолчанию 2, скидка на B для покупателя A — 30 из 100,
+	// прирост 0.5·2·0.3 = 0.3. daily — 14 дней по 10 чеков с A и 10 с Z: при скидке на B 0, 10 и 20%
+	// B присоединяют в 2, 3 и 4 чеках, наклон 0.01 на процент, прирост 0.01·30 = 0.3
+	daily := func() []entities.Transaction {
+		var transactions []entities.Transaction
+		for i := 0; i < 14; i++ {
+			day := start.AddDate(0, 0, i)
+			attached, discount := 2+i%3, float64(10*(i%3))
+			for j := 0; j < 10; j++ {
+				transaction := testTransaction(fmt.Sprintf("A%d-%d", i, j), day, 1, "A")
+				if j < attached {
+					transaction.Items = append(transaction.Items, entities.Item{ProductID: "B", Name: "B", Price: 100, Quantity: 1, DiscountPct: discount})
+				}
+				transactions = append(transactions, transaction, testTransaction(fmt.Sprintf("Z%d-%d", i, j), day, 1, "Z"))
+			}
+		}
+		return transactions
+	}
+
+	tests := []struct {
+		name         string
+		transactions []entities.Transaction
+		wantUplift   float64
+		wantFitted   bool
+	}{
+		{name: "default sensitivity", transactions: testBundleBaskets(start), wantUplift: 0.3},
+		{name: "sensitivity fitted by day", transactions: daily(), wantUplift: 0.3, wantFitted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewBundleService(&memoryTransactionRepository{transactions: tt.transactions},
+				&memoryProductRepository{products: testBundleProducts()}, newMemoryBundleRepository(), logger.NewLogger("ERROR"))
+
+			bundles, err := service.RecommendBundles(context.Background(), start, start.AddDate(0, 0, 14), testBundleOptions())
+			if err != nil {
+				t.Fatalf("RecommendBundles() error = %v", err)
+			}
+			if len(bundles) != 1 || bundles[0].ID != "bundle:A+B" {
+				t.Fatalf("bundles = %+v, want bundle:A+B", bundles)
+			}
+			got := bundles[0]
+			if math.Abs(got.IncrementalAttachRate-tt.wantUplift) > 1e-9 || got.ElasticityFitted != tt.wantFitted {
+				t.Errorf("incremental attach rate = %.4f (fitted %v), want %.4f (fitted %v)",
+					got.IncrementalAttachRate, got.ElasticityFitted, tt.wantUplift, tt.wantFitted)
+			}
+		})
+	}
+}
+
+func TestReviewBundle(t *testing.T) {
+	proposed := entities.ProductBundle{BaseEntity: entities.BaseEntity{ID: "bundle:A+B"}, Status: entities.BundleProposed}
+	approved := entities.ProductBundle{BaseEntity: entities.BaseEntity{ID: "bundle:X+Y"}, Status: entities.BundleApproved}
+
+	tests := []struct {
+		name       string
+		bundleID   string
+		approve    bool
+		reviewer   string
+		wantStatus entities.BundleStatus
+		wantErr    error
+	}{
+		{name: "approve", bundleID: "bundle:A+B", approve: true, reviewer: "menu-team", wantStatus: entities.BundleApproved},
+		{name: "reject", bundleID: "bundle:A+B", reviewer: "menu-team", wantStatus: entities.BundleRejected},
+		{name: "reviewer required", bundleID: "bundle:A+B", approve: true, wantErr: services.ErrInvalidParameter},
+		{name: "already reviewed", bundleID: "bundle:X+Y", reviewer: "menu-team", wantErr: services.ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryBundleRepository(proposed, approved)
+			service := services.NewBundleService(&memoryTransactionRepository{}, &memoryProductRepository{}, repo, logger.NewLogger("ERROR"))
+
+			bundle, err := service.ReviewBundle(context.Background(), tt.bundleID, tt.approve, tt.reviewer)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ReviewBundle() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReviewBundle() error = %v", err)
+			}
+			if bundle.Status != tt.wantStatus || bundle.ReviewedBy != tt.reviewer || bundle.ReviewedAt.IsZero() {
+				t.Errorf("bundle = %s reviewed by %q at %s, want %s by %s", bundle.Status, bundle.ReviewedBy, bundle.ReviewedAt, tt.wantStatus, tt.reviewer)
+			}
+			if repo.bundles[tt.bundleID].Status != tt.wantStatus {
+				t.Errorf("stored status = %s, want %s", repo.bundles[tt.bundleID].Status, tt.wantStatus)
+			}
+		})
+	}
+
+	service := services.NewBundleService(&memoryTransactionRepository{}, &memoryProductRepository{}, newMemoryBundleRepository(), logger.NewLogger("ERROR"))
+	if _, err := service.GetBundles(context.Background(), "archived"); !errors.Is(err, services.ErrInvalidParameter) {
+		t.Errorf("GetBundles() error = %v, want %v", err, services.ErrInvalidParameter)
+	}
+}
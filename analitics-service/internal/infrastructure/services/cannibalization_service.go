@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+)
+
+// CannibalizationService определяет интерфейс анализа каннибализации и halo-эффекта акций
+type CannibalizationService interface {
+	// AnalyzeCrossEffects оценивает влияние скидки на товар в период акции на продажи соседних товаров
+	// Базовый период равной длины берется непосредственно перед акцией
+	AnalyzeCrossEffects(ctx context.Context, productID string, promoStart, promoEnd time.Time) (*entities.PromotionCrossEffectAnalysis, error)
+
+	// ApplyNetEffect дополняет рекомендацию по скидке товара чистым эффектом с учетом соседних товаров
+	ApplyNetEffect(ctx context.Context, recommendation *entities.DiscountRecommendation, promoStart, promoEnd time.Time) error
+}
+
+// cannibalizationService реализует интерфейс CannibalizationService
+type cannibalizationService struct {
+	salesRepo   repositories.SalesRepository
+	productRepo repositories.ProductRepository
+	ruleRepo    repositories.AssociationRuleRepository
+	logger      logger.Logger
+}
+
+// substituteMinDrop минимальное относительное падение продаж соседнего товара той же категории,
+// при котором он считается заменителем товара под акцией
+const substituteMinDrop = 0.1
+
+// periodTotals содержит продажи товара за период
+type periodTotals struct {
+	units   float64
+	revenue float64
+}
+
+// NewCannibalizationService создает новый экземпляр сервиса анализа каннибализации
+func NewCannibalizationService(
+	salesRepo repositories.SalesRepository,
+	productRepo repositories.ProductRepository,
+	ruleRepo repositories.AssociationRuleRepository,
+	logger logger.Logger,
+) CannibalizationService {
+	return &cannibalizationService{
+		salesRepo:   salesRepo,
+		productRepo: productRepo,
+		ruleRepo:    ruleRepo,
+		logger:      logger,
+	}
+}
+
+// AnalyzeCrossEffects оценивает влияние скидки на товар на продажи соседних товаров
+func (s *cannibalizationService) AnalyzeCrossEffects(ctx context.Context, productID string, promoStart, promoEnd time.Time) (*entities.PromotionCrossEffectAnalysis, error) {
+	if !promoStart.Before(promoEnd) {
+		return nil, fmt.Errorf("%w: promotion start must be before end", ErrInvalidParameter)
+	}
+
+	promoted, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product %s: %w", productID, err)
+	}
+
+	products, err := s.productRepo.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+
+	rules, err := s.ruleRepo.GetRulesByProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve association rules: %w", err)
+	}
+
+	duration := promoEnd.Sub(promoStart)
+	baselineStart := promoStart.Add(-duration)
+	days := duration.Hours() / 24
+
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, baselineStart, promoEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	baseline, promo := splitSalesByPeriod(sales, promoStart)
+	relations := classifyNeighbours(promoted, products, rules, baseline, promo)
+
+	analysis := &entities.PromotionCrossEffectAnalysis{
+		AnalysisMetadata: entities.AnalysisMetadata{
+			AnalysisDate: time.Now(),
+			PeriodStart:  promoStart,
+			PeriodEnd:    promoEnd,
+		},
+		ProductID:     productID,
+		Category:      promoted.Category,
+		BaselineStart: baselineStart,
+		BaselineEnd:   promoStart,
+	}
+
+	promotedEffect := crossEffect(productID, promoted.Category, "", 0, baseline[productID], promo[productID], days)
+	analysis.PromotedIncrementalRevenue = promotedEffect.IncrementalRevenue
+	analysis.NetCategoryIncrementalRevenue = promotedEffect.IncrementalRevenue
+	analysis.NetIncrementalRevenue = promotedEffect.IncrementalRevenue
+
+	for neighbourID, relation := range relations {
+		effect := crossEffect(neighbourID, relation.category, relation.relation, relation.lift,
+			baseline[neighbourID], promo[neighbourID], days)
+		if effect.BaselineDailyUnits == 0 && effect.PromoDailyUnits == 0 {
+			continue
+		}
+
+		switch relation.relation {
+		case entities.CrossEffectSubstitute:
+			analysis.CannibalizedRevenue += effect.IncrementalRevenue
+		case entities.CrossEffectComplement:
+			analysis.HaloRevenue += effect.IncrementalRevenue
+		}
+
+		if relation.category == promoted.Category {
+			analysis.NetCategoryIncrementalRevenue += effect.IncrementalRevenue
+		}
+		analysis.NetIncrementalRevenue += effect.IncrementalRevenue
+		analysis.Effects = append(analysis.Effects, effect)
+	}
+
+	s.logger.Info(ctx, "Оценены перекрестные эффекты акции", "productID", productID,
+		"neighbours", len(analysis.Effects), "net", analysis.NetIncrementalRevenue)
+	return analysis, nil
+}
+
+// ApplyNetEffect дополняет рекомендацию по скидке товара чистым эффектом с учетом соседних товаров
+func (s *cannibalizationService) ApplyNetEffect(ctx context.Context, recommendation *entities.DiscountRecommendation, promoStart, promoEnd time.Time) error {
+	if recommendation == nil || recommendation.ProductID == "" {
+		return fmt.Errorf("%w: product-level recommendation is required", ErrInvalidParameter)
+	}
+
+	analysis, err := s.AnalyzeCrossEffects(ctx, recommendation.ProductID, promoStart, promoEnd)
+	if err != nil {
+		return err
+	}
+
+	recommendation.CannibalizedRevenue = analysis.CannibalizedRevenue
+	recommendation.HaloRevenue = analysis.HaloRevenue
+	recommendation.NetIncrementalRevenue = analysis.NetIncrementalRevenue
+
+	// Если каннибализация съедает весь прирост, скидка не имеет смысла
+	if analysis.NetIncrementalRevenue <= 0 && recommendation.OptimalDiscount > 0 {
+		recommendation.OptimalDiscount = 0
+		recommendation.AdjustmentReason = "Скидка отменена: каннибализация заменителей превышает прирост продаж"
+	}
+
+	return nil
+}
+
+// neighbourRelation описывает связь соседнего товара с товаром под акцией
+type neighbourRelation struct {
+	relation entities.CrossEffectType
+	category string
+	lift     float64
+}
+
+// classifyNeighbours определяет заменители и дополняющие товары для товара под акцией
+// Дополняющие — товары из правил с lift > 1. Заменители — товары той же категории без положительной ассоциации,
+// для которых есть свидетельство замещения: правило с lift < 1 или падение продаж не менее чем на substituteMinDrop
+// при росте продаж товара под акцией. Остальные товары категории в анализ не попадают
+func classifyNeighbours(
+	promoted entities.Product,
+	products []entities.Product,
+	rules []entities.AssociationRule,
+	baseline, promo map[string]periodTotals,
+) map[string]neighbourRelation {
+	categories := make(map[string]string, len(products))
+	for _, product := range products {
+		categories[product.ID] = product.Category
+	}
+
+	// Максимальный lift правила, связывающего товар под акцией с другим товаром
+	lifts := make(map[string]float64)
+	for _, rule := range rules {
+		if !ruleContainsProduct(rule, promoted.ID) {
+			continue
+		}
+		for _, item := range append(append([]entities.Item{}, rule.Antecedent...), rule.Consequent...) {
+			if item.ProductID == promoted.ID {
+				continue
+			}
+			if rule.Lift > lifts[item.ProductID] {
+				lifts[item.ProductID] = rule.Lift
+			}
+		}
+	}
+
+	relations := make(map[string]neighbourRelation)
+	for productID, lift := range lifts {
+		if lift > 1 {
+			relations[productID] = neighbourRelation{
+				relation: entities.CrossEffectComplement,
+				category: categories[productID],
+				lift:     lift,
+			}
+		}
+	}
+
+	promotedGrew := promo[promoted.ID].units > baseline[promoted.ID].units
+	for _, product := range products {
+		if product.ID == promoted.ID || product.Category != promoted.Category {
+			continue
+		}
+		if _, isComplement := relations[product.ID]; isComplement {
+			continue
+		}
+
+		lift, hasRule := lifts[product.ID]
+		negativeAssociation := hasRule && lift < 1
+		before, during := baseline[product.ID].units, promo[product.ID].units
+		displaced := promotedGrew && before > 0 && (before-during)/before >= substituteMinDrop
+		if !negativeAssociation && !displaced {
+			continue
+		}
+
+		relations[product.ID] = neighbourRelation{
+			relation: entities.CrossEffectSubstitute,
+			category: product.Category,
+			lift:     lift,
+		}
+	}
+
+	return relations
+}
+
+// ruleContainsProduct проверяет, входит ли товар в правило
+func ruleContainsProduct(rule entities.AssociationRule, productID string) bool {
+	for _, item := range rule.Antecedent {
+		if item.ProductID == productID {
+			return true
+		}
+	}
+	for _, item := range rule.Consequent {
+		if item.ProductID == productID {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSalesByPeriod агрегирует продажи по товарам до и после начала акции
+func splitSalesByPeriod(sales []entities.Sale, promoStart time.Time) (map[string]periodTotals, map[string]periodTotals) {
+	baseline := make(map[string]periodTotals)
+	promo := make(map[string]periodTotals)
+
+	for _, sale := range sales {
+		target := promo
+		if sale.PurchaseDate.Before(promoStart) {
+			target = baseline
+		}
+		totals := target[sale.ProductID]
+		totals.units += float64(sale.Quantity)
+		totals.revenue += saleRevenue(sale)
+		target[sale.ProductID] = totals
+	}
+
+	return baseline, promo
+}
+
+// crossEffect рассчитывает изменение продаж товара между базовым периодом и периодом акции
+func crossEffect(productID, category string, relation entities.CrossEffectType, lift float64, baseline, promo periodTotals, days float64) entities.ProductCrossEffect {
+	effect := entities.ProductCrossEffect{
+		ProductID:            productID,
+		Category:             category,
+		Relation:             relation,
+		RuleLift:             lift,
+		BaselineDailyUnits:   baseline.units / days,
+		PromoDailyUnits:      promo.units / days,
+		BaselineDailyRevenue: baseline.revenue / days,
+		PromoDailyRevenue:    promo.revenue / days,
+		IncrementalRevenue:   promo.revenue - baseline.revenue,
+	}
+
+	// Без продаж в базовом периоде относительное изменение не определено и остается нулевым
+	if effect.BaselineDailyUnits > 0 {
+		effect.UnitChangePct = (effect.PromoDailyUnits - effect.BaselineDailyUnits) / effect.BaselineDailyUnits * 100
+	}
+
+	return effect
+}
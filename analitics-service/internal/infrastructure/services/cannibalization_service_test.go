@@ -0,0 +1,399 @@
+// internal/infrastructure/services/cannibalization_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memorySalesRepository хранит продажи и дневные агрегаты в памяти
+type memorySalesRepository struct {
+	sales []entities.Sale
+	daily []entities.DailyTransactionData
+}
+
+func (r *memorySalesRepository) GetSalesByPeriod(_ context.Context, startDate, endDate time.Time) ([]entities.Sale, error) {
+	var result []entities.Sale
+	for _, sale := range r.sales {
+		if !sale.PurchaseDate.Before(startDate) && !sale.PurchaseDate.After(endDate) {
+			result = append(result, sale)
+		}
+	}
+	return result, nil
+}
+
+func (r *memorySalesRepository) GetSalesByProductID(ctx context.Context, productID string, startDate, endDate time.Time) ([]entities.Sale, error) {
+	sales, _ := r.GetSalesByPeriod(ctx, startDate, endDate)
+	var result []entities.Sale
+	for _, sale := range sales {
+		if sale.ProductID == productID {
+			result = append(result, sale)
+		}
+	}
+	return result, nil
+}
+
+func (r *memorySalesRepository) GetSalesByCustomerID(ctx context.Context, customerID string, startDate, endDate time.Time) ([]entities.Sale, error) {
+	sales, _ := r.GetSalesByPeriod(ctx, startDate, endDate)
+	var result []entities.Sale
+	for _, sale := range sales {
+		if sale.CustomerID == customerID {
+			result = append(result, sale)
+		}
+	}
+	return result, nil
+}
+
+func (r *memorySalesRepository) CreateSale(_ context.Context, sale entities.Sale) error {
+	r.sales = append(r.sales, sale)
+	return nil
+}
+
+func (r *memorySalesRepository) GetSaleByID(_ context.Context, saleID string) (entities.Sale, error) {
+	for _, sale := range r.sales {
+		if sale.ID == saleID {
+			return sale, nil
+		}
+	}
+	return entities.Sale{}, nil
+}
+
+func (r *memorySalesRepository) GetDailySalesData(_ context.Context, startDate, endDate time.Time) ([]entities.DailyTransactionData, error) {
+	var result []entities.DailyTransactionData
+	for _, data := range r.daily {
+		if !data.Date.Before(startDate) && !data.Date.After(endDate) {
+			result = append(result, data)
+		}
+	}
+	return result, nil
+}
+
+// memoryProductRepository хранит каталог товаров в памяти
+type memoryProductRepository struct {
+	products []entities.Product
+}
+
+func (r *memoryProductRepository) GetAllProducts(_ context.Context) ([]entities.Product, error) {
+	return r.products, nil
+}
+
+func (r *memoryProductRepository) GetProductByID(_ context.Context, productID string) (entities.Product, error) {
+	for _, product := range r.products {
+		if product.ID == productID {
+			return product, nil
+		}
+	}
+	return entities.Product{}, nil
+}
+
+func (r *memoryProductRepository) CreateProduct(_ context.Context, product entities.Product) error {
+	r.products = append(r.products, product)
+	return nil
+}
+
+func (r *memoryProductRepository) UpdateProduct(_ context.Context, product entities.Product) error {
+	for i := range r.products {
+		if r.products[i].ID == product.ID {
+			r.products[i] = product
+		}
+	}
+	return nil
+}
+
+func (r *memoryProductRepository) DeleteProduct(_ context.Context, productID string) error {
+	for i := range r.products {
+		if r.products[i].ID == productID {
+			r.products = append(r.products[:i], r.products[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// memoryRuleRepository хранит ассоциативные правила в памяти
+type memoryRuleRepository struct {
+	rules []entities.AssociationRule
+}
+
+func (r *memoryRuleRepository) SaveRules(_ context.Context, rules []entities.AssociationRule) error {
+	r.rules = append(r.rules, rules...)
+	return nil
+}
+
+func (r *memoryRuleRepository) GetRulesByProduct(_ context.Context, productID string) ([]entities.AssociationRule, error) {
+	return r.filter(func(rule entities.AssociationRule) bool {
+		for _, item := range append(append([]entities.Item{}, rule.Antecedent...), rule.Consequent...) {
+			if item.ProductID == productID {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (r *memoryRuleRepository) GetRulesByCategory(_ context.Context, category string) ([]entities.AssociationRule, error) {
+	return r.filter(func(rule entities.AssociationRule) bool {
+		for _, c := range rule.Categories {
+			if c == category {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (r *memoryRuleRepository) GetRulesByConfidence(_ context.Context, minConfidence float64) ([]entities.AssociationRule, error) {
+	return r.filter(func(rule entities.AssociationRule) bool { return rule.Confidence >= minConfidence }), nil
+}
+
+func (r *memoryRuleRepository) GetRulesBySupport(_ context.Context, minSupport float64) ([]entities.AssociationRule, error) {
+	return r.filter(func(rule entities.AssociationRule) bool { return rule.Support >= minSupport }), nil
+}
+
+func (r *memoryRuleRepository) GetRulesByLift(_ context.Context, minLift float64) ([]entities.AssociationRule, error) {
+	return r.filter(func(rule entities.AssociationRule) bool { return rule.Lift >= minLift }), nil
+}
+
+func (r *memoryRuleRepository) StreamRules(_ context.Context, fn func(rule entities.AssociationRule) error) error {
+	for _, rule := range r.rules {
+		if err := fn(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryRuleRepository) filter(keep func(rule entities.AssociationRule) bool) []entities.AssociationRule {
+	var result []entities.AssociationRule
+	for _, rule := range r.rules {
+		if keep(rule) {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// testProduct возвращает активный товар категории
+func testProduct(id, category string, price float64) entities.Product {
+	product := entities.Product{Name: id, Category: category, Price: price, IsActive: true}
+	product.ID = id
+	return product
+}
+
+// testSale возвращает продажу товара в указанный момент
+func testSale(productID string, quantity int, price, discount float64, at time.Time) entities.Sale {
+	return entities.Sale{
+		ProductID:     productID,
+		Quantity:      quantity,
+		Price:         price,
+		DiscountRate:  discount,
+		PurchaseDate:  at,
+		CustomerID:    "C1",
+		TransactionID: "T1",
+	}
+}
+
+// testRule возвращает правило антецедент -> консеквент с указанным lift
+func testRule(antecedent, consequent string, lift float64) entities.AssociationRule {
+	return entities.AssociationRule{
+		Antecedent: []entities.Item{{ProductID: antecedent}},
+		Consequent: []entities.Item{{ProductID: consequent}},
+		Lift:       lift,
+	}
+}
+
+// testCrossEffectService возвращает сервис с каталогом кофе и выпечки и продажами до и во время акции на P1:
+// P2 — кофе, P3 — кофе с неизменными продажами, P4 — выпечка из правила с lift 2, P5 — кофе из правила с lift 0.8
+func testCrossEffectService(promoStart time.Time, promotedUnits, substituteBaseline int) services.CannibalizationService {
+	before, during := promoStart.AddDate(0, 0, -3), promoStart.AddDate(0, 0, 3)
+	sales := &memorySalesRepository{sales: []entities.Sale{
+		testSale("P1", 10, 100, 0, before),
+		testSale("P1", promotedUnits, 100, 10, during),
+		testSale("P2", substituteBaseline, 100, 0, before),
+		testSale("P2", 5, 100, 0, during),
+		testSale("P3", 10, 80, 0, before),
+		testSale("P3", 10, 80, 0, during),
+		testSale("P4", 4, 50, 0, before),
+		testSale("P4", 8, 50, 0, during),
+		testSale("P5", 10, 10, 0, before),
+		testSale("P5", 10, 10, 0, during),
+	}}
+	products := &memoryProductRepository{products: []entities.Product{
+		testProduct("P1", "coffee", 100),
+		testProduct("P2", "coffee", 100),
+		testProduct("P3", "coffee", 80),
+		testProduct("P4", "pastry", 50),
+		testProduct("P5", "coffee", 10),
+	}}
+	rules := &memoryRuleRepository{rules: []entities.AssociationRule{
+		testRule("P1", "P4", 2),
+		testRule("P5", "P1", 0.8),
+	}}
+	return services.NewCannibalizationService(sales, products, rules, logger.NewLogger("ERROR"))
+}
+
+func TestAnalyzeCrossEffects(t *testing.T) {
+	promoStart := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	promoEnd := promoStart.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name          string
+		promotedUnits int
+		// Ожидаемая выручка: P1 со скидкой 10% и изменения соседних товаров за период акции
+		wantPromoted     float64
+		wantCannibalized float64
+		wantHalo         float64
+		wantNetCategory  float64
+		wantNet          float64
+		wantRelations    map[string]entities.CrossEffectType
+	}{
+		{
+			// P2 упал на 50% при росте P1 и считается заменителем, P3 без изменений в анализ не попадает
+			name:             "promoted product grew",
+			promotedUnits:    20,
+			wantPromoted:     800,
+			wantCannibalized: -500,
+			wantHalo:         200,
+			wantNetCategory:  300,
+			wantNet:          500,
+			wantRelations: map[string]entities.CrossEffectType{
+				"P2": entities.CrossEffectSubstitute,
+				"P4": entities.CrossEffectComplement,
+				"P5": entities.CrossEffectSubstitute,
+			},
+		},
+		{
+			// Без роста продаж P1 падение P2 не свидетельствует о замещении
+			name:            "promoted product flat",
+			promotedUnits:   10,
+			wantPromoted:    -100,
+			wantHalo:        200,
+			wantNetCategory: -100,
+			wantNet:         100,
+			wantRelations: map[string]entities.CrossEffectType{
+				"P4": entities.CrossEffectComplement,
+				"P5": entities.CrossEffectSubstitute,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := testCrossEffectService(promoStart, tt.promotedUnits, 10)
+			analysis, err := service.AnalyzeCrossEffects(context.Background(), "P1", promoStart, promoEnd)
+			if err != nil {
+				t.Fatalf("AnalyzeCrossEffects() error = %v", err)
+			}
+
+			got := []struct {
+				name      string
+				got, want float64
+			}{
+				{"promoted incremental revenue", analysis.PromotedIncrementalRevenue, tt.wantPromoted},
+				{"cannibalized revenue", analysis.CannibalizedRevenue, tt.wantCannibalized},
+				{"halo revenue", analysis.HaloRevenue, tt.wantHalo},
+				{"net category revenue", analysis.NetCategoryIncrementalRevenue, tt.wantNetCategory},
+				{"net revenue", analysis.NetIncrementalRevenue, tt.wantNet},
+			}
+			for _, g := range got {
+				if math.Abs(g.got-g.want) > 1e-9 {
+					t.Errorf("%s = %.2f, want %.2f", g.name, g.got, g.want)
+				}
+			}
+
+			relations := make(map[string]entities.CrossEffectType)
+			for _, effect := range analysis.Effects {
+				relations[effect.ProductID] = effect.Relation
+			}
+			if len(relations) != len(tt.wantRelations) {
+				t.Errorf("neighbours = %v, want %v", relations, tt.wantRelations)
+			}
+			for productID, relation := range tt.wantRelations {
+				if relations[productID] != relation {
+					t.Errorf("%s relation = %q, want %q", productID, relations[productID], relation)
+				}
+			}
+			if !analysis.BaselineStart.Equal(promoStart.AddDate(0, 0, -7)) || !analysis.BaselineEnd.Equal(promoStart) {
+				t.Errorf("baseline = %s..%s, want the week before the promotion", analysis.BaselineStart, analysis.BaselineEnd)
+			}
+		})
+	}
+}
+
+func TestApplyNetEffect(t *testing.T) {
+	promoStart := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	promoEnd := promoStart.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name               string
+		substituteBaseline int
+		recommendation     *entities.DiscountRecommendation
+		wantDiscount       float64
+		wantNet            float64
+		wantErr            error
+	}{
+		{
+			name:               "net positive keeps discount",
+			substituteBaseline: 10,
+			recommendation:     &entities.DiscountRecommendation{ProductID: "P1", OptimalDiscount: 10},
+			wantDiscount:       10,
+			wantNet:            500,
+		},
+		{
+			// Падение P2 с 30 до 5 единиц съедает прирост P1 и halo выпечки
+			name:               "cannibalization cancels discount",
+			substituteBaseline: 30,
+			recommendation:     &entities.DiscountRecommendation{ProductID: "P1", OptimalDiscount: 10},
+			wantNet:            -1500,
+		},
+		{
+			name:           "category recommendation",
+			recommendation: &entities.DiscountRecommendation{Category: "coffee", OptimalDiscount: 10},
+			wantErr:        services.ErrInvalidParameter,
+		},
+		{name: "no recommendation", wantErr: services.ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := testCrossEffectService(promoStart, 20, tt.substituteBaseline)
+			err := service.ApplyNetEffect(context.Background(), tt.recommendation, promoStart, promoEnd)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ApplyNetEffect() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyNetEffect() error = %v", err)
+			}
+
+			if tt.recommendation.OptimalDiscount != tt.wantDiscount {
+				t.Errorf("discount = %v, want %v", tt.recommendation.OptimalDiscount, tt.wantDiscount)
+			}
+			if math.Abs(tt.recommendation.NetIncrementalRevenue-tt.wantNet) > 1e-9 {
+				t.Errorf("net incremental revenue = %.2f, want %.2f", tt.recommendation.NetIncrementalRevenue, tt.wantNet)
+			}
+			if (tt.wantDiscount == 0) != (tt.recommendation.AdjustmentReason != "") {
+				t.Errorf("adjustment reason = %q for discount %v", tt.recommendation.AdjustmentReason, tt.wantDiscount)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCrossEffectsInvalidPeriod(t *testing.T) {
+	promoStart := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	service := testCrossEffectService(promoStart, 20, 10)
+
+	_, err := service.AnalyzeCrossEffects(context.Background(), "P1", promoStart, promoStart)
+	if !errors.Is(err, services.ErrInvalidParameter) {
+		t.Errorf("AnalyzeCrossEffects() error = %v, want %v", err, services.ErrInvalidParameter)
+	}
+}
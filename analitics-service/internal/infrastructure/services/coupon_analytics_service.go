@@ -0,0 +1,492 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+)
+
+// CouponAnalyticsService определяет интерфейс анализа эффективности купонов
+type CouponAnalyticsService interface {
+	// BuildReport строит отчет по купонным кодам и купонным кампаниям промо-календаря за период,
+	// включая найденные паттерны злоупотреблений
+	BuildReport(ctx context.Context, startDate, endDate time.Time, config entities.CouponAnalyticsConfig) (*entities.CouponReport, error)
+
+	// DetectAbuse ищет подозрительное использование купонов за период:
+	// массовое погашение одного кода разными клиентами и многократное погашение одним клиентом
+	DetectAbuse(ctx context.Context, startDate, endDate time.Time, config entities.CouponAnalyticsConfig) ([]entities.CouponAbuseAlert, error)
+}
+
+// couponAnalyticsService реализует интерфейс CouponAnalyticsService
+type couponAnalyticsService struct {
+	transactionRepo repositories.TransactionRepository
+	productRepo     repositories.ProductRepository
+	promotionRepo   repositories.PromotionRepository
+	costRepo        repositories.ProductCostRepository
+	logger          logger.Logger
+}
+
+// customerBaseline содержит обычный чек клиента без купона
+type customerBaseline struct {
+	value float64
+	items float64
+}
+
+// couponContext содержит данные, общие для расчета показателей всех кодов
+type couponContext struct {
+	baselines     map[string]customerBaseline
+	visits        map[string][]time.Time // Отсортированные моменты всех покупок клиента
+	costs         *productCosts
+	repeatWindow  time.Duration
+	observedUntil time.Time // Момент, до которого известны покупки клиентов
+}
+
+// NewCouponAnalyticsService создает новый экземпляр сервиса анализа купонов
+func NewCouponAnalyticsService(
+	transactionRepo repositories.TransactionRepository,
+	productRepo repositories.ProductRepository,
+	promotionRepo repositories.PromotionRepository,
+	costRepo repositories.ProductCostRepository,
+	logger logger.Logger,
+) CouponAnalyticsService {
+	return &couponAnalyticsService{
+		transactionRepo: transactionRepo,
+		productRepo:     productRepo,
+		promotionRepo:   promotionRepo,
+		costRepo:        costRepo,
+		logger:          logger,
+	}
+}
+
+// BuildReport строит отчет по купонным кодам и кампаниям за период
+func (s *couponAnalyticsService) BuildReport(ctx context.Context, startDate, endDate time.Time, config entities.CouponAnalyticsConfig) (*entities.CouponReport, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	if !startDate.Before(endDate) {
+		return nil, fmt.Errorf("%w: start date must be before end date", ErrInvalidParameter)
+	}
+
+	// Транзакции до периода дают обычный чек, после периода — повторные визиты
+	observedUntil := endDate.AddDate(0, 0, config.RepeatWindowDays)
+	if now := time.Now(); observedUntil.After(now) && now.After(endDate) {
+		observedUntil = now
+	}
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, startDate.AddDate(0, 0, -config.BaselineDays), observedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+
+	redemptions := couponRedemptions(transactions, startDate, endDate)
+	if len(redemptions) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	products, err := s.productRepo.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+
+	promotions, err := s.promotionRepo.GetPromotionsByPeriod(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve promotions: %w", err)
+	}
+
+	costs, err := loadProductCosts(ctx, s.costRepo, products)
+	if err != nil {
+		return nil, err
+	}
+
+	cc := newCouponContext(transactions, costs, endDate, observedUntil, config)
+
+	report := &entities.CouponReport{
+		AnalysisMetadata: entities.AnalysisMetadata{
+			AnalysisDate: time.Now(),
+			PeriodStart:  startDate,
+			PeriodEnd:    endDate,
+		},
+		Coupons:     make([]entities.CouponPerformance, 0, len(redemptions)),
+		Campaigns:   make([]entities.CampaignPerformance, 0),
+		AbuseAlerts: detectCouponAbuse(redemptions, config, time.Now()),
+	}
+
+	campaignRedemptions := make(map[string][]entities.Transaction)
+	campaignCodes := make(map[string]map[string]bool)
+	for _, code := range sortedGroupKeys(redemptions) {
+		performance := entities.CouponPerformance{
+			CouponCode:    code,
+			CouponMetrics: cc.metrics(redemptions[code]),
+		}
+
+		// Один код может переиспользоваться в разных акциях, поэтому погашение
+		// относится к акции, действовавшей в момент покупки
+		campaigns := make(map[string]bool)
+		for _, redemption := range redemptions[code] {
+			promotion, ok := couponCampaign(promotions, code, redemption.Date)
+			if !ok {
+				continue
+			}
+			campaigns[promotion.ID] = true
+			campaignRedemptions[promotion.ID] = append(campaignRedemptions[promotion.ID], redemption)
+			if campaignCodes[promotion.ID] == nil {
+				campaignCodes[promotion.ID] = make(map[string]bool)
+			}
+			campaignCodes[promotion.ID][code] = true
+		}
+		if len(campaigns) == 1 {
+			performance.CampaignID = sortedKeys(campaigns)[0]
+		}
+
+		report.Coupons = append(report.Coupons, performance)
+	}
+
+	for _, promotion := range promotions {
+		campaignTransactions, ok := campaignRedemptions[promotion.ID]
+		if !ok {
+			continue
+		}
+		report.Campaigns = append(report.Campaigns, entities.CampaignPerformance{
+			CampaignID:    promotion.ID,
+			CampaignName:  promotion.Name,
+			CouponCodes:   sortedKeys(campaignCodes[promotion.ID]),
+			CouponMetrics: cc.metrics(campaignTransactions),
+		})
+		delete(campaignRedemptions, promotion.ID)
+	}
+
+	sort.SliceStable(report.Coupons, func(i, j int) bool {
+		return report.Coupons[i].Redemptions > report.Coupons[j].Redemptions
+	})
+	sort.SliceStable(report.Campaigns, func(i, j int) bool {
+		return report.Campaigns[i].Redemptions > report.Campaigns[j].Redemptions
+	})
+
+	s.logger.Info(ctx, "Построен отчет по купонам", "coupons", len(report.Coupons),
+		"campaigns", len(report.Campaigns), "abuse_alerts", len(report.AbuseAlerts))
+	return report, nil
+}
+
+// DetectAbuse ищет подозрительное использование купонов за период
+func (s *couponAnalyticsService) DetectAbuse(ctx context.Context, startDate, endDate time.Time, config entities.CouponAnalyticsConfig) ([]entities.CouponAbuseAlert, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	if !startDate.Before(endDate) {
+		return nil, fmt.Errorf("%w: start date must be before end date", ErrInvalidParameter)
+	}
+
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+
+	alerts := detectCouponAbuse(couponRedemptions(transactions, startDate, endDate), config, time.Now())
+	for _, alert := range alerts {
+		s.logger.Warn(ctx, "Подозрительное использование купона", "coupon", alert.CouponCode,
+			"pattern", alert.Pattern, "accounts", alert.Accounts, "redemptions", alert.Redemptions)
+	}
+
+	return alerts, nil
+}
+
+// newCouponContext собирает обычные чеки клиентов без купона и историю визитов
+// Обычный чек считается по покупкам без купона от начала окна до конца анализируемого периода
+func newCouponContext(transactions []entities.Transaction, costs *productCosts, endDate, observedUntil time.Time, config entities.CouponAnalyticsConfig) *couponContext {
+	cc := &couponContext{
+		baselines:     make(map[string]customerBaseline),
+		visits:        make(map[string][]time.Time),
+		costs:         costs,
+		repeatWindow:  time.Duration(config.RepeatWindowDays) * 24 * time.Hour,
+		observedUntil: observedUntil,
+	}
+
+	counts := make(map[string]int)
+	for _, transaction := range transactions {
+		cc.visits[transaction.CustomerID] = append(cc.visits[transaction.CustomerID], transaction.Date)
+
+		if isCouponRedemption(transaction) || !transaction.Date.Before(endDate) {
+			continue
+		}
+		baseline := cc.baselines[transaction.CustomerID]
+		baseline.value += transaction.TotalAmount
+		baseline.items += float64(transactionUnits(transaction))
+		cc.baselines[transaction.CustomerID] = baseline
+		counts[transaction.CustomerID]++
+	}
+
+	for customerID, baseline := range cc.baselines {
+		n := float64(counts[customerID])
+		cc.baselines[customerID] = customerBaseline{value: baseline.value / n, items: baseline.items / n}
+	}
+	for _, visits := range cc.visits {
+		sort.Slice(visits, func(i, j int) bool { return visits[i].Before(visits[j]) })
+	}
+
+	return cc
+}
+
+// metrics рассчитывает показатели по набору погашений
+func (c *couponContext) metrics(redemptions []entities.Transaction) entities.CouponMetrics {
+	m := entities.CouponMetrics{Redemptions: len(redemptions)}
+
+	firstByCustomer := make(map[string]time.Time)
+	fullMargin := 0.0
+	withBaseline := 0
+	basketValue, basketItems := 0.0, 0.0
+	baselineValue, baselineItems := 0.0, 0.0
+
+	for _, redemption := range redemptions {
+		units := float64(transactionUnits(redemption))
+		m.Revenue += redemption.TotalAmount
+		m.AvgBasketItems += units
+
+		discount, margin := c.discountAndMargin(redemption)
+		m.MarginGivenAway += discount
+		fullMargin += margin
+
+		if first, ok := firstByCustomer[redemption.CustomerID]; !ok || redemption.Date.Before(first) {
+			firstByCustomer[redemption.CustomerID] = redemption.Date
+		}
+		if m.FirstRedemption.IsZero() || redemption.Date.Before(m.FirstRedemption) {
+			m.FirstRedemption = redemption.Date
+		}
+		if redemption.Date.After(m.LastRedemption) {
+			m.LastRedemption = redemption.Date
+		}
+
+		// Прирост считается только по клиентам, для которых известен обычный чек
+		if baseline, ok := c.baselines[redemption.CustomerID]; ok {
+			withBaseline++
+			basketValue += redemption.TotalAmount
+			basketItems += units
+			baselineValue += baseline.value
+			baselineItems += baseline.items
+		}
+	}
+
+	if len(redemptions) > 0 {
+		n := float64(len(redemptions))
+		m.AvgBasketValue = m.Revenue / n
+		m.AvgBasketItems /= n
+	}
+	if fullMargin > 0 {
+		m.MarginGivenAwayShare = m.MarginGivenAway / fullMargin
+	}
+	if withBaseline > 0 {
+		n := float64(withBaseline)
+		m.BaselineBasketValue = baselineValue / n
+		m.BaselineBasketItems = baselineItems / n
+		m.IncrementalBasketValue = basketValue/n - m.BaselineBasketValue
+		m.IncrementalBasketItems = basketItems/n - m.BaselineBasketItems
+	}
+
+	m.UniqueCustomers = len(firstByCustomer)
+	for customerID, first := range firstByCustomer {
+		if _, ok := c.baselines[customerID]; ok {
+			m.BaselineCustomers++
+		}
+
+		// Клиенты с незавершенным окном не учитываются, чтобы не занижать долю повторных визитов
+		deadline := first.Add(c.repeatWindow)
+		if deadline.After(c.observedUntil) {
+			continue
+		}
+		m.RepeatEligibleCustomers++
+		if c.visitedBetween(customerID, first, deadline) {
+			m.RepeatCustomers++
+		}
+	}
+	if m.RepeatEligibleCustomers > 0 {
+		m.RepeatVisitRate = float64(m.RepeatCustomers) / float64(m.RepeatEligibleCustomers)
+	}
+
+	return m
+}
+
+// discountAndMargin возвращает сумму скидки по чеку и валовую маржу чека по полной цене
+// Если скидка не разнесена по позициям, она считается как разница между полной ценой и суммой чека
+// Маржа учитывает только товары с известной себестоимостью на дату чека
+func (c *couponContext) discountAndMargin(transaction entities.Transaction) (float64, float64) {
+	listValue, discount, margin := 0.0, 0.0, 0.0
+	for _, item := range transaction.Items {
+		value := item.Price * float64(item.Quantity)
+		listValue += value
+		discount += value * item.DiscountPct / 100
+		if cost, ok := c.costs.at(item.ProductID, transaction.Date); ok {
+			margin += (item.Price - cost) * float64(item.Quantity)
+		}
+	}
+
+	if discount == 0 && transaction.TotalAmount < listValue {
+		discount = listValue - transaction.TotalAmount
+	}
+	return discount, margin
+}
+
+// visitedBetween проверяет, была ли у клиента покупка в интервале (from, to]
+func (c *couponContext) visitedBetween(customerID string, from, to time.Time) bool {
+	visits := c.visits[customerID]
+	i := sort.Search(len(visits), func(i int) bool { return visits[i].After(from) })
+	return i < len(visits) && !visits[i].After(to)
+}
+
+// detectCouponAbuse ищет массовое погашение кода в скользящем окне и многократные погашения одним клиентом
+// Погашения каждого кода должны быть отсортированы по времени
+func detectCouponAbuse(redemptions map[string][]entities.Transaction, config entities.CouponAnalyticsConfig, detectedAt time.Time) []entities.CouponAbuseAlert {
+	window := time.Duration(config.AbuseWindowHours) * time.Hour
+	alerts := make([]entities.CouponAbuseAlert, 0)
+
+	for _, code := range sortedGroupKeys(redemptions) {
+		list := redemptions[code]
+		alerts = append(alerts, sharedCodeAlerts(code, list, config.AbuseMinAccounts, window, detectedAt)...)
+
+		byCustomer := make(map[string][]time.Time)
+		for _, redemption := range list {
+			byCustomer[redemption.CustomerID] = append(byCustomer[redemption.CustomerID], redemption.Date)
+		}
+		for _, customerID := range sortedGroupKeys(byCustomer) {
+			dates := byCustomer[customerID]
+			if len(dates) <= config.MaxRedemptionsPerCustomer {
+				continue
+			}
+			alerts = append(alerts, entities.CouponAbuseAlert{
+				CouponCode:  code,
+				Pattern:     entities.AbuseRepeatedRedemption,
+				Accounts:    1,
+				Redemptions: len(dates),
+				CustomerIDs: []string{customerID},
+				WindowStart: dates[0],
+				WindowEnd:   dates[len(dates)-1],
+				DetectedAt:  detectedAt,
+			})
+		}
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].WindowStart.Before(alerts[j].WindowStart)
+	})
+	return alerts
+}
+
+// sharedCodeAlerts находит интервалы, в которых код погасили не меньше minAccounts разных клиентов
+// в пределах окна; перекрывающиеся окна объединяются в один алерт
+func sharedCodeAlerts(code string, redemptions []entities.Transaction, minAccounts int, window time.Duration, detectedAt time.Time) []entities.CouponAbuseAlert {
+	var (
+		alerts    []entities.CouponAbuseAlert
+		current   *entities.CouponAbuseAlert
+		customers map[string]bool
+		first     int
+		added     int
+	)
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.CustomerIDs = sortedKeys(customers)
+		current.Accounts = len(customers)
+		alerts = append(alerts, *current)
+	}
+
+	counts := make(map[string]int)
+	left := 0
+	for right, redemption := range redemptions {
+		counts[redemption.CustomerID]++
+		for redemption.Date.Sub(redemptions[left].Date) > window {
+			counts[redemptions[left].CustomerID]--
+			if counts[redemptions[left].CustomerID] == 0 {
+				delete(counts, redemptions[left].CustomerID)
+			}
+			left++
+		}
+		if len(counts) < minAccounts {
+			continue
+		}
+
+		if current == nil || redemptions[left].Date.After(current.WindowEnd) {
+			flush()
+			current = &entities.CouponAbuseAlert{
+				CouponCode:  code,
+				Pattern:     entities.AbuseSharedCode,
+				WindowStart: redemptions[left].Date,
+				DetectedAt:  detectedAt,
+			}
+			customers = make(map[string]bool)
+			first, added = left, left-1
+		}
+		for _, r := range redemptions[added+1 : right+1] {
+			customers[r.CustomerID] = true
+		}
+		added = right
+		current.WindowEnd = redemption.Date
+		current.Redemptions = right - first + 1
+	}
+	flush()
+
+	return alerts
+}
+
+// couponRedemptions группирует погашения купонов периода по нормализованному коду,
+// погашения каждого кода отсортированы по времени
+func couponRedemptions(transactions []entities.Transaction, startDate, endDate time.Time) map[string][]entities.Transaction {
+	redemptions := make(map[string][]entities.Transaction)
+	for _, transaction := range transactions {
+		if !isCouponRedemption(transaction) || transaction.Date.Before(startDate) || !transaction.Date.Before(endDate) {
+			continue
+		}
+		code := normalizeCouponCode(transaction.CouponCode)
+		redemptions[code] = append(redemptions[code], transaction)
+	}
+
+	for _, list := range redemptions {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Date.Before(list[j].Date) })
+	}
+	return redemptions
+}
+
+// couponCampaign возвращает купонную акцию с указанным кодом, действовавшую в момент погашения
+func couponCampaign(promotions []entities.Promotion, code string, date time.Time) (entities.Promotion, bool) {
+	for _, promotion := range promotions {
+		if promotion.CouponCode == "" || normalizeCouponCode(promotion.CouponCode) != code {
+			continue
+		}
+		if !date.Before(promotion.StartDate) && date.Before(promotion.EndDate) {
+			return promotion, true
+		}
+	}
+	return entities.Promotion{}, false
+}
+
+// isCouponRedemption проверяет, что в транзакции был применен купон
+func isCouponRedemption(transaction entities.Transaction) bool {
+	return transaction.DiscountUsed && transaction.CouponCode != ""
+}
+
+// normalizeCouponCode приводит код купона к единому виду
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// transactionUnits возвращает количество единиц товара в чеке
+func transactionUnits(transaction entities.Transaction) int {
+	units := 0
+	for _, item := range transaction.Items {
+		units += item.Quantity
+	}
+	return units
+}
+
+// sortedGroupKeys возвращает ключи карты в отсортированном порядке
+func sortedGroupKeys[T any](groups map[string]T) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
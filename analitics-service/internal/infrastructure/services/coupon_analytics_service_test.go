@@ -0,0 +1,287 @@
+// internal/infrastructure/services/coupon_analytics_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// testCouponTransaction возвращает чек клиента с одной позицией; непустой код означает погашение купона
+func testCouponTransaction(id, customerID string, at time.Time, code string, total float64, item entities.Item) entities.Transaction {
+	transaction := entities.Transaction{
+		CustomerID:   customerID,
+		Date:         at,
+		TotalAmount:  total,
+		Items:        []entities.Item{item},
+		DiscountUsed: code != "",
+		CouponCode:   code,
+	}
+	transaction.ID = id
+	return transaction
+}
+
+// testCouponService возвращает сервис анализа купонов по указанным чекам
+// Себестоимость P1 — 60 при цене 100, P2 — 20 при цене 50
+func testCouponService(transactions []entities.Transaction, promotions ...entities.Promotion) services.CouponAnalyticsService {
+	coffee, croissant := testProduct("P1", "coffee", 100), testProduct("P2", "pastry", 50)
+	coffee.Cost, croissant.Cost = 60, 20
+	products := &memoryProductRepository{products: []entities.Product{coffee, croissant}}
+
+	promotionRepo := newMemoryPromotionRepository()
+	for _, promotion := range promotions {
+		promotionRepo.promotions[promotion.ID] = promotion
+	}
+
+	return services.NewCouponAnalyticsService(&memoryTransactionRepository{transactions: transactions},
+		products, promotionRepo, &memoryCostRepository{}, logger.NewLogger("ERROR"))
+}
+
+func TestBuildCouponReport(t *testing.T) {
+	start, end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	coffee := func(quantity int, discountPct float64) entities.Item {
+		return entities.Item{ProductID: "P1", Name: "Coffee", Price: 100, Quantity: quantity, DiscountPct: discountPct}
+	}
+
+	// C1 обычно покупает на 150 (3 единицы), с купоном взял 3 кофе со скидкой 10% и вернулся через 8 дней;
+	// C2 без истории погасил тот же код с нераспределенной скидкой 20; C3 погасил другой код
+	transactions := []entities.Transaction{
+		testCouponTransaction("T1", "C1", start.AddDate(0, 0, -20), "", 100, coffee(2, 50)),
+		testCouponTransaction("T2", "C1", start.AddDate(0, 0, -10), "", 200, coffee(4, 50)),
+		testCouponTransaction("T3", "C1", start.AddDate(0, 0, 1), " save10 ", 270, coffee(3, 10)),
+		testCouponTransaction("T4", "C2", start.AddDate(0, 0, 2), "SAVE10", 80, coffee(1, 0)),
+		testCouponTransaction("T5", "C3", start.AddDate(0, 0, 6), "WELCOME", 100,
+			entities.Item{ProductID: "P2", Name: "Croissant", Price: 50, Quantity: 2}),
+		testCouponTransaction("T6", "C1", start.AddDate(0, 0, 9), "", 100, coffee(1, 0)),
+	}
+
+	campaign := testPromotion(start, []string{"P1"}, nil)
+	campaign.ID = "spring-coupon"
+	campaign.CouponCode = "SAVE10"
+
+	config := entities.DefaultCouponAnalyticsConfig()
+	config.BaselineDays = 30
+	config.RepeatWindowDays = 14
+
+	report, err := testCouponService(transactions, campaign).BuildReport(context.Background(), start, end, config)
+	if err != nil {
+		t.Fatalf("BuildReport() error = %v", err)
+	}
+
+	if len(report.Coupons) != 2 || len(report.Campaigns) != 1 || len(report.AbuseAlerts) != 0 {
+		t.Fatalf("report = %d coupons, %d campaigns, %d alerts, want 2, 1 and 0",
+			len(report.Coupons), len(report.Campaigns), len(report.AbuseAlerts))
+	}
+
+	// SAVE10: скидки 30 и 20 при марже по полной цене 120 и 40; WELCOME: скидки нет, маржа 60
+	save10 := entities.CouponMetrics{
+		Redemptions: 2, UniqueCustomers: 2, Revenue: 350, AvgBasketValue: 175, AvgBasketItems: 2,
+		BaselineCustomers: 1, BaselineBasketValue: 150, BaselineBasketItems: 3,
+		IncrementalBasketValue: 120, IncrementalBasketItems: 0,
+		MarginGivenAway: 50, MarginGivenAwayShare: 50.0 / 160,
+		RepeatEligibleCustomers: 2, RepeatCustomers: 1, RepeatVisitRate: 0.5,
+		FirstRedemption: start.AddDate(0, 0, 1), LastRedemption: start.AddDate(0, 0, 2),
+	}
+	welcome := entities.CouponMetrics{
+		Redemptions: 1, UniqueCustomers: 1, Revenue: 100, AvgBasketValue: 100, AvgBasketItems: 2,
+		RepeatEligibleCustomers: 1,
+		FirstRedemption:         start.AddDate(0, 0, 6), LastRedemption: start.AddDate(0, 0, 6),
+	}
+
+	tests := []struct {
+		name         string
+		got          entities.CouponMetrics
+		want         entities.CouponMetrics
+		gotCampaign  string
+		wantCampaign string
+	}{
+		{name: "coupon SAVE10", got: report.Coupons[0].CouponMetrics, want: save10, gotCampaign: report.Coupons[0].CouponCode + "/" + report.Coupons[0].CampaignID, wantCampaign: "SAVE10/spring-coupon"},
+		{name: "coupon WELCOME", got: report.Coupons[1].CouponMetrics, want: welcome, gotCampaign: report.Coupons[1].CouponCode + "/" + report.Coupons[1].CampaignID, wantCampaign: "WELCOME/"},
+		{name: "campaign", got: report.Campaigns[0].CouponMetrics, want: save10, gotCampaign: report.Campaigns[0].CampaignID, wantCampaign: "spring-coupon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.gotCampaign != tt.wantCampaign {
+				t.Errorf("coupon/campaign = %s, want %s", tt.gotCampaign, tt.wantCampaign)
+			}
+
+			got, want := tt.got, tt.want
+			floats := []struct {
+				name      string
+				got, want float64
+			}{
+				{"revenue", got.Revenue, want.Revenue},
+				{"avg basket value", got.AvgBasketValue, want.AvgBasketValue},
+				{"avg basket items", got.AvgBasketItems, want.AvgBasketItems},
+				{"baseline basket value", got.BaselineBasketValue, want.BaselineBasketValue},
+				{"baseline basket items", got.BaselineBasketItems, want.BaselineBasketItems},
+				{"incremental basket value", got.IncrementalBasketValue, want.IncrementalBasketValue},
+				{"incremental basket items", got.IncrementalBasketItems, want.IncrementalBasketItems},
+				{"margin given away", got.MarginGivenAway, want.MarginGivenAway},
+				{"margin given away share", got.MarginGivenAwayShare, want.MarginGivenAwayShare},
+				{"repeat visit rate", got.RepeatVisitRate, want.RepeatVisitRate},
+			}
+			for _, f := range floats {
+				if math.Abs(f.got-f.want) > 1e-9 {
+					t.Errorf("%s = %.4f, want %.4f", f.name, f.got, f.want)
+				}
+			}
+
+			counts := []struct {
+				name      string
+				got, want int
+			}{
+				{"redemptions", got.Redemptions, want.Redemptions},
+				{"unique customers", got.UniqueCustomers, want.UniqueCustomers},
+				{"baseline customers", got.BaselineCustomers, want.BaselineCustomers},
+				{"repeat eligible customers", got.RepeatEligibleCustomers, want.RepeatEligibleCustomers},
+				{"repeat customers", got.RepeatCustomers, want.RepeatCustomers},
+			}
+			for _, c := range counts {
+				if c.got != c.want {
+					t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+				}
+			}
+			if !got.FirstRedemption.Equal(want.FirstRedemption) || !got.LastRedemption.Equal(want.LastRedemption) {
+				t.Errorf("redemptions = %s..%s, want %s..%s", got.FirstRedemption, got.LastRedemption, want.FirstRedemption, want.LastRedemption)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(report.Campaigns[0].CouponCodes, []string{"SAVE10"}) {
+		t.Errorf("campaign codes = %v, want [SAVE10]", report.Campaigns[0].CouponCodes)
+	}
+}
+
+func TestDetectCouponAbuse(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days, hours int) time.Time { return day.AddDate(0, 0, days).Add(time.Duration(hours) * time.Hour) }
+	redemption := func(customerID string, when time.Time, code string) entities.Transaction {
+		return testCouponTransaction(customerID+when.Format(time.RFC3339), customerID, when, code, 90,
+			entities.Item{ProductID: "P1", Name: "Coffee", Price: 100, Quantity: 1})
+	}
+
+	config := entities.DefaultCouponAnalyticsConfig()
+	config.AbuseWindowHours = 24
+	config.AbuseMinAccounts = 3
+	config.MaxRedemptionsPerCustomer = 2
+
+	tests := []struct {
+		name         string
+		transactions []entities.Transaction
+		want         []entities.CouponAbuseAlert
+	}{
+		{
+			// Регистр и пробелы в коде не различаются
+			name: "shared code within window",
+			transactions: []entities.Transaction{
+				redemption("C1", at(0, 10), "FREE"),
+				redemption("C2", at(0, 14), "free "),
+				redemption("C3", at(0, 20), "Free"),
+			},
+			want: []entities.CouponAbuseAlert{{CouponCode: "FREE", Pattern: entities.AbuseSharedCode, Accounts: 3, Redemptions: 3,
+				CustomerIDs: []string{"C1", "C2", "C3"}, WindowStart: at(0, 10), WindowEnd: at(0, 20)}},
+		},
+		{
+			name: "redemptions spread beyond window",
+			transactions: []entities.Transaction{
+				redemption("C1", at(0, 10), "FREE"),
+				redemption("C2", at(1, 11), "FREE"),
+				redemption("C3", at(2, 12), "FREE"),
+			},
+		},
+		{
+			// Окна 10:00–08:00 и 20:00–18:00 перекрываются и объединяются в один алерт
+			name: "overlapping windows merged",
+			transactions: []entities.Transaction{
+				redemption("C1", at(0, 10), "FREE"),
+				redemption("C2", at(0, 20), "FREE"),
+				redemption("C3", at(1, 8), "FREE"),
+				redemption("C4", at(1, 18), "FREE"),
+			},
+			want: []entities.CouponAbuseAlert{{CouponCode: "FREE", Pattern: entities.AbuseSharedCode, Accounts: 4, Redemptions: 4,
+				CustomerIDs: []string{"C1", "C2", "C3", "C4"}, WindowStart: at(0, 10), WindowEnd: at(1, 18)}},
+		},
+		{
+			name: "repeated redemption by one customer",
+			transactions: []entities.Transaction{
+				redemption("C1", at(0, 10), "WELCOME"),
+				redemption("C1", at(2, 10), "WELCOME"),
+				redemption("C1", at(4, 10), "WELCOME"),
+			},
+			want: []entities.CouponAbuseAlert{{CouponCode: "WELCOME", Pattern: entities.AbuseRepeatedRedemption, Accounts: 1, Redemptions: 3,
+				CustomerIDs: []string{"C1"}, WindowStart: at(0, 10), WindowEnd: at(4, 10)}},
+		},
+		{
+			name: "repeated redemption within limit",
+			transactions: []entities.Transaction{
+				redemption("C1", at(0, 10), "WELCOME"),
+				redemption("C1", at(2, 10), "WELCOME"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, err := testCouponService(tt.transactions).DetectAbuse(context.Background(), day, day.AddDate(0, 0, 7), config)
+			if err != nil {
+				t.Fatalf("DetectAbuse() error = %v", err)
+			}
+			if len(alerts) != len(tt.want) {
+				t.Fatalf("alerts = %+v, want %+v", alerts, tt.want)
+			}
+			for i := range alerts {
+				got := alerts[i]
+				got.DetectedAt = time.Time{}
+				if !reflect.DeepEqual(got, tt.want[i]) {
+					t.Errorf("alert = %+v, want %+v", got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCouponAnalyticsErrors(t *testing.T) {
+	start, end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	plain := testCouponTransaction("T1", "C1", start.AddDate(0, 0, 1), "", 100,
+		entities.Item{ProductID: "P1", Name: "Coffee", Price: 100, Quantity: 1})
+	service := testCouponService([]entities.Transaction{plain})
+
+	invalid := entities.DefaultCouponAnalyticsConfig()
+	invalid.AbuseMinAccounts = 1
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{name: "single account is not abuse", call: func() error {
+			_, err := service.DetectAbuse(context.Background(), start, end, invalid)
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "reversed period", call: func() error {
+			_, err := service.BuildReport(context.Background(), end, start, entities.DefaultCouponAnalyticsConfig())
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "no redemptions", call: func() error {
+			_, err := service.BuildReport(context.Background(), start, end, entities.DefaultCouponAnalyticsConfig())
+			return err
+		}, want: services.ErrInsufficientData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/pkg/logger"
+)
+
+// minOutlierScale нижняя граница робастного разброса логарифма значений, чтобы при почти одинаковых
+// значениях (например, количестве 1 почти во всех продажах) выбросом не считалось любое отличие
+const minOutlierScale = 0.25
+
+// DataQualityService определяет интерфейс проверки и очистки входных данных анализа
+// Записи проверяются валидаторами сущностей и правилами конфигурации; нарушившие правило записи
+// исключаются в карантин, исправляются или только учитываются в зависимости от действия правила
+type DataQualityService interface {
+	// CleanseTransactions проверяет чеки и возвращает чеки, допущенные к анализу, с итогами проверки
+	CleanseTransactions(ctx context.Context, transactions []entities.Transaction, config entities.DataQualityConfig) ([]entities.Transaction, *entities.DataQualityResult, error)
+
+	// CleanseSales проверяет продажи и возвращает продажи, допущенные к анализу, с итогами проверки
+	CleanseSales(ctx context.Context, sales []entities.Sale, config entities.DataQualityConfig) ([]entities.Sale, *entities.DataQualityResult, error)
+}
+
+// dataQualityService реализует интерфейс DataQualityService
+type dataQualityService struct {
+	logger logger.Logger
+}
+
+// qualityRecord содержит нарушения одной записи источника данных
+type qualityRecord struct {
+	id          string
+	original    interface{}
+	rules       []entities.DataQualityRule
+	quarantined bool
+	dropped     bool
+	repaired    bool
+	rule        entities.DataQualityRule // Правило, по которому запись исключена в карантин
+	reason      string
+}
+
+// excluded сообщает, исключена ли запись из анализа
+func (r *qualityRecord) excluded() bool {
+	return r.quarantined || r.dropped
+}
+
+// dataQualityCheck накапливает нарушения записей одного источника данных
+type dataQualityCheck struct {
+	source  string
+	config  entities.DataQualityConfig
+	records []*qualityRecord
+}
+
+// NewDataQualityService создает новый экземпляр сервиса проверки качества данных
+func NewDataQualityService(logger logger.Logger) DataQualityService {
+	return &dataQualityService{
+		logger: logger,
+	}
+}
+
+// CleanseTransactions проверяет чеки: повторы ID, позиции с отрицательным количеством и полной скидкой,
+// валидатор сущности, сумму чека относительно суммы позиций и выбросы суммы чека
+func (s *dataQualityService) CleanseTransactions(ctx context.Context, transactions []entities.Transaction, config entities.DataQualityConfig) ([]entities.Transaction, *entities.DataQualityResult, error) {
+	if err := config.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	check := &dataQualityCheck{source: "transactions", config: config}
+	cleansed := make([]entities.Transaction, len(transactions))
+	seen := make(map[string]bool, len(transactions))
+	for i, original := range transactions {
+		record := check.add(original.ID, original)
+
+		// Позиции копируются, чтобы исправления не меняли исходные данные и запись для карантина
+		transaction := original
+		transaction.Items = append([]entities.Item(nil), original.Items...)
+		if original.ID != "" && seen[original.ID] {
+			if action, ok := check.violate(record, entities.DataQualityDuplicateID, "duplicate transaction ID"); ok && action == entities.DataQualityRepair {
+				record.dropped = true
+			}
+		}
+		seen[original.ID] = true
+
+		if !record.excluded() {
+			check.checkTransaction(record, &transaction)
+		}
+		cleansed[i] = transaction
+	}
+
+	// Выбросы ищутся по логарифму суммы среди чеков, оставшихся после проверки записей
+	var candidates []int
+	var values []float64
+	for i, record := range check.records {
+		if !record.excluded() && cleansed[i].TotalAmount > 0 {
+			candidates = append(candidates, i)
+			values = append(values, math.Log1p(cleansed[i].TotalAmount))
+		}
+	}
+	for j, score := range outlierScores(values, config.OutlierMinSamples) {
+		if math.Abs(score) >= config.OutlierThreshold {
+			i := candidates[j]
+			check.violate(check.records[i], entities.DataQualityOutlier,
+				fmt.Sprintf("total amount %.2f is an outlier, robust z-score %.1f", cleansed[i].TotalAmount, score))
+		}
+	}
+
+	result, err := check.result()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kept := make([]entities.Transaction, 0, len(transactions))
+	for i, record := range check.records {
+		if !record.excluded() {
+			kept = append(kept, cleansed[i])
+		}
+	}
+
+	s.logResult(ctx, check.source, result)
+	return kept, result, nil
+}
+
+// CleanseSales проверяет продажи: повторы ID, отрицательное количество, полную скидку,
+// валидатор сущности и выбросы количества в продажах товара
+// Продажа состоит из одной позиции, поэтому исправление отрицательного количества и полной скидки удаляет продажу
+func (s *dataQualityService) CleanseSales(ctx context.Context, sales []entities.Sale, config entities.DataQualityConfig) ([]entities.Sale, *entities.DataQualityResult, error) {
+	if err := config.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	check := &dataQualityCheck{source: "sales", config: config}
+	seen := make(map[string]bool, len(sales))
+	for _, sale := range sales {
+		record := check.add(sale.ID, sale)
+		if sale.ID != "" && seen[sale.ID] {
+			if action, ok := check.violate(record, entities.DataQualityDuplicateID, "duplicate sale ID"); ok && action == entities.DataQualityRepair {
+				record.dropped = true
+			}
+		}
+		seen[sale.ID] = true
+
+		if !record.excluded() {
+			check.checkSale(record, sale)
+		}
+	}
+
+	// Выбросы ищутся по логарифму количества отдельно по каждому товару
+	candidates := make(map[string][]int)
+	var products []string
+	for i, record := range check.records {
+		if record.excluded() || sales[i].Quantity <= 0 {
+			continue
+		}
+		if _, ok := candidates[sales[i].ProductID]; !ok {
+			products = append(products, sales[i].ProductID)
+		}
+		candidates[sales[i].ProductID] = append(candidates[sales[i].ProductID], i)
+	}
+	for _, productID := range products {
+		indices := candidates[productID]
+		values := make([]float64, len(indices))
+		for j, i := range indices {
+			values[j] = math.Log1p(float64(sales[i].Quantity))
+		}
+		for j, score := range outlierScores(values, config.OutlierMinSamples) {
+			if math.Abs(score) >= config.OutlierThreshold {
+				i := indices[j]
+				check.violate(check.records[i], entities.DataQualityOutlier,
+					fmt.Sprintf("quantity %d is an outlier for product %s, robust z-score %.1f", sales[i].Quantity, productID, score))
+			}
+		}
+	}
+
+	result, err := check.result()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kept := make([]entities.Sale, 0, len(sales))
+	for i, record := range check.records {
+		if !record.excluded() {
+			kept = append(kept, sales[i])
+		}
+	}
+
+	s.logResult(ctx, check.source, result)
+	return kept, result, nil
+}
+
+// logResult пишет в лог итоги проверки источника данных
+func (s *dataQualityService) logResult(ctx context.Context, source string, result *entities.DataQualityResult) {
+	report := result.Report
+	s.logger.Info(ctx, "Проверено качество входных данных", "source", source, "score", report.Score,
+		"records", report.Records, "quarantined", report.Quarantined, "repaired", report.Repaired,
+		"dropped", report.Dropped, "flagged", report.Flagged)
+}
+
+// add добавляет запись источника в проверку
+func (c *dataQualityCheck) add(id string, original interface{}) *qualityRecord {
+	record := &qualityRecord{id: id, original: original}
+	c.records = append(c.records, record)
+	return record
+}
+
+// violate учитывает нарушение правила записью и возвращает действие правила
+// Возвращает false, если правило не применяется или запись уже исключена из анализа
+func (c *dataQualityCheck) violate(record *qualityRecord, rule entities.DataQualityRule, reason string) (entities.DataQualityAction, bool) {
+	action, ok := c.config.ActionFor(rule)
+	if !ok || record.excluded() {
+		return "", false
+	}
+
+	record.rules = append(record.rules, rule)
+	switch action {
+	case entities.DataQualityQuarantine:
+		record.quarantined = true
+		record.rule = rule
+		record.reason = reason
+	case entities.DataQualityRepair:
+		record.repaired = true
+	}
+	return action, true
+}
+
+// checkTransaction проверяет чек и исправляет его, если этого требует действие правила
+func (c *dataQualityCheck) checkTransaction(record *qualityRecord, t *entities.Transaction) {
+	var negative, fullDiscount []string
+	for _, item := range t.Items {
+		if item.Quantity <= 0 {
+			negative = append(negative, item.ProductID)
+		}
+		if item.DiscountPct >= 100 {
+			fullDiscount = append(fullDiscount, item.ProductID)
+		}
+	}
+
+	if len(negative) > 0 {
+		reason := fmt.Sprintf("items with non-positive quantity: %s", strings.Join(negative, ", "))
+		if action, ok := c.violate(record, entities.DataQualityNegativeQuantity, reason); ok && action == entities.DataQualityRepair {
+			removeItems(t, func(item entities.Item) bool { return item.Quantity > 0 })
+		}
+	}
+	if len(fullDiscount) > 0 {
+		reason := fmt.Sprintf("items with full discount: %s", strings.Join(fullDiscount, ", "))
+		if action, ok := c.violate(record, entities.DataQualityFullDiscount, reason); ok && action == entities.DataQualityRepair {
+			removeItems(t, func(item entities.Item) bool { return item.DiscountPct < 100 })
+		}
+	}
+
+	// Валидатор проверяет чек после удаления позиций, поэтому чек без оставшихся позиций считается некорректным
+	if err := t.Validate(); err != nil {
+		c.violate(record, entities.DataQualityInvalidRecord, err.Error())
+	}
+	if record.excluded() {
+		return
+	}
+
+	expected := 0.0
+	for _, item := range t.Items {
+		expected += itemAmount(item)
+	}
+	expected = roundTo(expected, 2)
+
+	switch {
+	case t.TotalAmount == 0 && expected > 0:
+		reason := fmt.Sprintf("total amount is zero while items sum to %.2f", expected)
+		if action, ok := c.violate(record, entities.DataQualityZeroTotal, reason); ok && action == entities.DataQualityRepair {
+			t.TotalAmount = expected
+		}
+	// Скидка по купону может применяться ко всему чеку, поэтому такой чек может быть меньше суммы позиций
+	case math.Abs(t.TotalAmount-expected) > c.config.TotalTolerance && !(t.DiscountUsed && t.TotalAmount < expected):
+		reason := fmt.Sprintf("total amount %.2f differs from items sum %.2f", t.TotalAmount, expected)
+		if action, ok := c.violate(record, entities.DataQualityTotalMismatch, reason); ok && action == entities.DataQualityRepair {
+			t.TotalAmount = expected
+		}
+	}
+}
+
+// checkSale проверяет продажу; исправление продажи означает ее удаление
+func (c *dataQualityCheck) checkSale(record *qualityRecord, sale entities.Sale) {
+	if sale.Quantity <= 0 {
+		reason := fmt.Sprintf("non-positive quantity %d", sale.Quantity)
+		if action, ok := c.violate(record, entities.DataQualityNegativeQuantity, reason); ok && action == entities.DataQualityRepair {
+			record.dropped = true
+		}
+	}
+	if sale.DiscountRate >= 100 {
+		reason := fmt.Sprintf("full discount %.2f", sale.DiscountRate)
+		if action, ok := c.violate(record, entities.DataQualityFullDiscount, reason); ok && action == entities.DataQualityRepair {
+			record.dropped = true
+		}
+	}
+
+	if err := sale.Validate(); err != nil {
+		c.violate(record, entities.DataQualityInvalidRecord, err.Error())
+	}
+}
+
+// result подводит итоги проверки; записи карантина содержат исходную запись до исправлений
+func (c *dataQualityCheck) result() (*entities.DataQualityResult, error) {
+	quarantinedAt := time.Now()
+	result := &entities.DataQualityResult{
+		Report: entities.DataQualityReport{
+			Score:      1,
+			Records:    len(c.records),
+			Violations: make(map[entities.DataQualityRule]int),
+		},
+	}
+
+	report := &result.Report
+	for _, record := range c.records {
+		for _, rule := range record.rules {
+			report.Violations[rule]++
+		}
+
+		switch {
+		case len(record.rules) == 0:
+			report.Passed++
+		case record.quarantined:
+			payload, err := json.Marshal(record.original)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode quarantined %s record: %w", c.source, err)
+			}
+			result.Quarantined = append(result.Quarantined, entities.QuarantinedRecord{
+				Source:        c.source,
+				RecordID:      record.id,
+				Rule:          record.rule,
+				Reason:        record.reason,
+				Payload:       payload,
+				QuarantinedAt: quarantinedAt,
+			})
+			report.Quarantined++
+		case record.dropped:
+			report.Dropped++
+		case record.repaired:
+			report.Repaired++
+		default:
+			report.Flagged++
+		}
+	}
+
+	if report.Records > 0 {
+		report.Score = roundTo(float64(report.Passed)/float64(report.Records), 4)
+	}
+	return result, nil
+}
+
+// outlierScores возвращает робастные z-оценки значений или nil, если значений меньше minSamples
+func outlierScores(values []float64, minSamples int) []float64 {
+	if len(values) < minSamples {
+		return nil
+	}
+
+	center, scale := robustScale(values)
+	scale = math.Max(scale, minOutlierScale)
+
+	scores := make([]float64, len(values))
+	for i, value := range values {
+		scores[i] = (value - center) / scale
+	}
+	return scores
+}
+
+// removeItems оставляет в чеке позиции, для которых keep возвращает true, и вычитает удаленные позиции из суммы чека,
+// чтобы исправленный чек не считался расхождением суммы; нулевая сумма остается нулевой для правила zero_total
+func removeItems(t *entities.Transaction, keep func(item entities.Item) bool) {
+	filtered := t.Items[:0]
+	removed := 0.0
+	for _, item := range t.Items {
+		if keep(item) {
+			filtered = append(filtered, item)
+		} else {
+			removed += itemAmount(item)
+		}
+	}
+	t.Items = filtered
+	if t.TotalAmount != 0 {
+		t.TotalAmount = roundTo(t.TotalAmount-removed, 2)
+	}
+}
+
+// itemAmount возвращает сумму позиции с учетом скидки
+func itemAmount(item entities.Item) float64 {
+	return item.Price * float64(item.Quantity) * (1 - item.DiscountPct/100)
+}
@@ -0,0 +1,331 @@
+// internal/infrastructure/services/data_quality_service_test.go
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// testQualityTransaction возвращает чек клиента C1 с указанной суммой и позициями
+func testQualityTransaction(id string, total float64, items ...entities.Item) entities.Transaction {
+	transaction := entities.Transaction{
+		CustomerID:  "C1",
+		Date:        time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		TotalAmount: total,
+		Items:       items,
+	}
+	transaction.ID = id
+	return transaction
+}
+
+// testQualityItem возвращает позицию чека
+func testQualityItem(productID string, price float64, quantity int, discountPct float64) entities.Item {
+	return entities.Item{ProductID: productID, Name: productID, Price: price, Quantity: quantity, DiscountPct: discountPct}
+}
+
+// testQualityConfig возвращает правила по умолчанию с переопределенными действиями
+func testQualityConfig(overrides map[entities.DataQualityRule]entities.DataQualityAction) entities.DataQualityConfig {
+	config := entities.DefaultDataQualityConfig()
+	for rule, action := range overrides {
+		config.Rules[rule] = action
+	}
+	return config
+}
+
+func TestCleanseTransactions(t *testing.T) {
+	coupon := testQualityTransaction("T1", 180, testQualityItem("P1", 100, 2, 0))
+	coupon.DiscountUsed = true
+	coupon.CouponCode = "SPRING"
+
+	tests := []struct {
+		name         string
+		transactions []entities.Transaction
+		overrides    map[entities.DataQualityRule]entities.DataQualityAction
+		// Ожидаемые итоги по первой записи и по чекам, оставшимся в анализе
+		wantReport    entities.DataQualityReport
+		wantKept      int
+		wantTotal     float64
+		wantItems     int
+		wantViolation entities.DataQualityRule
+	}{
+		{
+			name:         "clean transaction",
+			transactions: []entities.Transaction{testQualityTransaction("T1", 250, testQualityItem("P1", 100, 2, 0), testQualityItem("P2", 50, 1, 0))},
+			wantReport:   entities.DataQualityReport{Score: 1, Records: 1, Passed: 1},
+			wantKept:     1,
+			wantTotal:    250,
+			wantItems:    2,
+		},
+		{
+			name: "duplicate ID dropped",
+			transactions: []entities.Transaction{
+				testQualityTransaction("T1", 100, testQualityItem("P1", 100, 1, 0)),
+				testQualityTransaction("T1", 100, testQualityItem("P1", 100, 1, 0)),
+			},
+			wantReport:    entities.DataQualityReport{Score: 0.5, Records: 2, Passed: 1, Dropped: 1},
+			wantKept:      1,
+			wantTotal:     100,
+			wantItems:     1,
+			wantViolation: entities.DataQualityDuplicateID,
+		},
+		{
+			name:          "negative quantity quarantined",
+			transactions:  []entities.Transaction{testQualityTransaction("T1", 150, testQualityItem("P1", 100, 2, 0), testQualityItem("P2", 50, -1, 0))},
+			wantReport:    entities.DataQualityReport{Records: 1, Quarantined: 1},
+			wantViolation: entities.DataQualityNegativeQuantity,
+		},
+		{
+			// Возврат уменьшил сумму чека, после удаления позиции сумма снова совпадает с позициями
+			name:          "negative quantity repaired",
+			transactions:  []entities.Transaction{testQualityTransaction("T1", 150, testQualityItem("P1", 100, 2, 0), testQualityItem("P2", 50, -1, 0))},
+			overrides:     map[entities.DataQualityRule]entities.DataQualityAction{entities.DataQualityNegativeQuantity: entities.DataQualityRepair},
+			wantReport:    entities.DataQualityReport{Records: 1, Repaired: 1},
+			wantKept:      1,
+			wantTotal:     200,
+			wantItems:     1,
+			wantViolation: entities.DataQualityNegativeQuantity,
+		},
+		{
+			name:          "full discount repaired",
+			transactions:  []entities.Transaction{testQualityTransaction("T1", 100, testQualityItem("P1", 100, 1, 0), testQualityItem("P2", 30, 1, 100))},
+			overrides:     map[entities.DataQualityRule]entities.DataQualityAction{entities.DataQualityFullDiscount: entities.DataQualityRepair},
+			wantReport:    entities.DataQualityReport{Records: 1, Repaired: 1},
+			wantKept:      1,
+			wantTotal:     100,
+			wantItems:     1,
+			wantViolation: entities.DataQualityFullDiscount,
+		},
+		{
+			// После удаления единственной позиции чек не проходит валидатор
+			name:          "repair removing all items quarantines",
+			transactions:  []entities.Transaction{testQualityTransaction("T1", 0, testQualityItem("P1", 30, 1, 100))},
+			overrides:     map[entities.DataQualityRule]entities.DataQualityAction{entities.DataQualityFullDiscount: entities.DataQualityRepair},
+			wantReport:    entities.DataQualityReport{Records: 1, Quarantined: 1},
+			wantViolation: entities.DataQualityInvalidRecord,
+		},
+		{
+			name:          "zero total repaired",
+			transactions:  []entities.Transaction{testQualityTransaction("T1", 0, testQualityItem("P1", 100, 2, 10))},
+			wantReport:    entities.DataQualityReport{Records: 1, Repaired: 1},
+			wantKept:      1,
+			wantTotal:     180,
+			wantItems:     1,
+			wantViolation: entities.DataQualityZeroTotal,
+		},
+		{
+			name:          "total mismatch repaired",
+			transactions:  []entities.Transaction{testQualityTransaction("T1", 210, testQualityItem("P1", 100, 2, 0))},
+			wantReport:    entities.DataQualityReport{Records: 1, Repaired: 1},
+			wantKept:      1,
+			wantTotal:     200,
+			wantItems:     1,
+			wantViolation: entities.DataQualityTotalMismatch,
+		},
+		{
+			name:          "total mismatch flagged",
+			transactions:  []entities.Transaction{testQualityTransaction("T1", 210, testQualityItem("P1", 100, 2, 0))},
+			overrides:     map[entities.DataQualityRule]entities.DataQualityAction{entities.DataQualityTotalMismatch: entities.DataQualityFlag},
+			wantReport:    entities.DataQualityReport{Records: 1, Flagged: 1},
+			wantKept:      1,
+			wantTotal:     210,
+			wantItems:     1,
+			wantViolation: entities.DataQualityTotalMismatch,
+		},
+		{
+			name:         "difference within tolerance",
+			transactions: []entities.Transaction{testQualityTransaction("T1", 200.04, testQualityItem("P1", 100, 2, 0))},
+			wantReport:   entities.DataQualityReport{Score: 1, Records: 1, Passed: 1},
+			wantKept:     1,
+			wantTotal:    200.04,
+			wantItems:    1,
+		},
+		{
+			// Скидка по купону на весь чек делает сумму меньше суммы позиций
+			name:         "coupon discount below items sum",
+			transactions: []entities.Transaction{coupon},
+			wantReport:   entities.DataQualityReport{Score: 1, Records: 1, Passed: 1},
+			wantKept:     1,
+			wantTotal:    180,
+			wantItems:    1,
+		},
+	}
+
+	service := services.NewDataQualityService(logger.NewLogger("ERROR"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, _ := json.Marshal(tt.transactions[len(tt.transactions)-1])
+
+			kept, result, err := service.CleanseTransactions(context.Background(), tt.transactions, testQualityConfig(tt.overrides))
+			if err != nil {
+				t.Fatalf("CleanseTransactions() error = %v", err)
+			}
+
+			report := result.Report
+			if report.Score != tt.wantReport.Score || report.Records != tt.wantReport.Records || report.Passed != tt.wantReport.Passed ||
+				report.Repaired != tt.wantReport.Repaired || report.Dropped != tt.wantReport.Dropped ||
+				report.Quarantined != tt.wantReport.Quarantined || report.Flagged != tt.wantReport.Flagged {
+				t.Errorf("report = %+v, want %+v", report, tt.wantReport)
+			}
+			if tt.wantViolation != "" && report.Violations[tt.wantViolation] != 1 {
+				t.Errorf("violations = %v, want one %s", report.Violations, tt.wantViolation)
+			}
+
+			if len(kept) != tt.wantKept {
+				t.Fatalf("kept = %d, want %d", len(kept), tt.wantKept)
+			}
+			if tt.wantKept > 0 {
+				if math.Abs(kept[0].TotalAmount-tt.wantTotal) > 1e-9 {
+					t.Errorf("total = %.2f, want %.2f", kept[0].TotalAmount, tt.wantTotal)
+				}
+				if len(kept[0].Items) != tt.wantItems {
+					t.Errorf("items = %d, want %d", len(kept[0].Items), tt.wantItems)
+				}
+			}
+
+			// Исправления не меняют исходные данные, в карантин попадает запись до исправлений
+			if current, _ := json.Marshal(tt.transactions[len(tt.transactions)-1]); string(current) != string(original) {
+				t.Errorf("input transaction modified: %s", current)
+			}
+			if report.Quarantined > 0 && string(result.Quarantined[0].Payload) != string(original) {
+				t.Errorf("quarantined payload = %s, want original %s", result.Quarantined[0].Payload, original)
+			}
+		})
+	}
+}
+
+func TestCleanseTransactionsOutliers(t *testing.T) {
+	var transactions []entities.Transaction
+	for i := 0; i < 40; i++ {
+		price := 100 + float64(i%10)*5
+		transactions = append(transactions, testQualityTransaction(fmt.Sprintf("T%d", i), price, testQualityItem("P1", price, 1, 0)))
+	}
+
+	tests := []struct {
+		name        string
+		total       float64
+		records     int
+		wantFlagged int
+	}{
+		{name: "regular total", total: 120, records: 40},
+		{name: "extreme total", total: 5_000_000, records: 40, wantFlagged: 1},
+		// Меньше минимального числа записей: выбросы не ищутся
+		{name: "too few records", total: 5_000_000, records: 20},
+	}
+
+	service := services.NewDataQualityService(logger.NewLogger("ERROR"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]entities.Transaction(nil), transactions[:tt.records-1]...)
+			input = append(input, testQualityTransaction("T-last", tt.total, testQualityItem("P1", tt.total, 1, 0)))
+
+			kept, result, err := service.CleanseTransactions(context.Background(), input, entities.DefaultDataQualityConfig())
+			if err != nil {
+				t.Fatalf("CleanseTransactions() error = %v", err)
+			}
+			if result.Report.Flagged != tt.wantFlagged || result.Report.Violations[entities.DataQualityOutlier] != tt.wantFlagged {
+				t.Errorf("report = %+v, want %d flagged outliers", result.Report, tt.wantFlagged)
+			}
+			// Выбросы по умолчанию только отмечаются и остаются в анализе
+			if len(kept) != tt.records {
+				t.Errorf("kept = %d, want %d", len(kept), tt.records)
+			}
+		})
+	}
+}
+
+func TestCleanseSales(t *testing.T) {
+	sale := func(id string, quantity int, discount float64) entities.Sale {
+		s := entities.Sale{
+			ProductID:     "P1",
+			Quantity:      quantity,
+			Price:         100,
+			DiscountRate:  discount,
+			PurchaseDate:  time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+			CustomerID:    "C1",
+			TransactionID: "T1",
+		}
+		s.ID = id
+		return s
+	}
+	anonymous := sale("S1", 1, 0)
+	anonymous.CustomerID = ""
+
+	tests := []struct {
+		name      string
+		sales     []entities.Sale
+		overrides map[entities.DataQualityRule]entities.DataQualityAction
+		want      entities.DataQualityReport
+		wantKept  int
+		wantRule  entities.DataQualityRule
+	}{
+		{name: "clean", sales: []entities.Sale{sale("S1", 2, 10)}, want: entities.DataQualityReport{Score: 1, Records: 1, Passed: 1}, wantKept: 1},
+		{name: "duplicate dropped", sales: []entities.Sale{sale("S1", 1, 0), sale("S1", 1, 0)}, want: entities.DataQualityReport{Score: 0.5, Records: 2, Passed: 1, Dropped: 1}, wantKept: 1},
+		{name: "negative quantity quarantined", sales: []entities.Sale{sale("S1", -1, 0)}, want: entities.DataQualityReport{Records: 1, Quarantined: 1}, wantRule: entities.DataQualityNegativeQuantity},
+		{
+			// Продажа из одной позиции исправляется удалением
+			name:      "full discount repaired by dropping",
+			sales:     []entities.Sale{sale("S1", 1, 100)},
+			overrides: map[entities.DataQualityRule]entities.DataQualityAction{entities.DataQualityFullDiscount: entities.DataQualityRepair},
+			want:      entities.DataQualityReport{Records: 1, Dropped: 1},
+		},
+		{name: "invalid sale quarantined", sales: []entities.Sale{anonymous}, want: entities.DataQualityReport{Records: 1, Quarantined: 1}, wantRule: entities.DataQualityInvalidRecord},
+	}
+
+	service := services.NewDataQualityService(logger.NewLogger("ERROR"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, result, err := service.CleanseSales(context.Background(), tt.sales, testQualityConfig(tt.overrides))
+			if err != nil {
+				t.Fatalf("CleanseSales() error = %v", err)
+			}
+
+			report := result.Report
+			if report.Score != tt.want.Score || report.Records != tt.want.Records || report.Passed != tt.want.Passed ||
+				report.Dropped != tt.want.Dropped || report.Quarantined != tt.want.Quarantined {
+				t.Errorf("report = %+v, want %+v", report, tt.want)
+			}
+			if len(kept) != tt.wantKept {
+				t.Errorf("kept = %d, want %d", len(kept), tt.wantKept)
+			}
+			if tt.wantRule != "" {
+				if len(result.Quarantined) != 1 || result.Quarantined[0].Rule != tt.wantRule || result.Quarantined[0].Source != "sales" {
+					t.Errorf("quarantined = %+v, want one sales record for rule %s", result.Quarantined, tt.wantRule)
+				}
+			}
+		})
+	}
+}
+
+func TestCleanseRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *entities.DataQualityConfig)
+	}{
+		{name: "outlier cannot be repaired", modify: func(c *entities.DataQualityConfig) { c.Rules[entities.DataQualityOutlier] = entities.DataQualityRepair }},
+		{name: "unknown action", modify: func(c *entities.DataQualityConfig) { c.Rules[entities.DataQualityZeroTotal] = "ignore" }},
+		{name: "negative tolerance", modify: func(c *entities.DataQualityConfig) { c.TotalTolerance = -1 }},
+		{name: "too few outlier samples", modify: func(c *entities.DataQualityConfig) { c.OutlierMinSamples = 2 }},
+	}
+
+	service := services.NewDataQualityService(logger.NewLogger("ERROR"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := entities.DefaultDataQualityConfig()
+			tt.modify(&config)
+
+			_, _, err := service.CleanseTransactions(context.Background(), nil, config)
+			if !errors.Is(err, services.ErrInvalidParameter) {
+				t.Errorf("CleanseTransactions() error = %v, want %v", err, services.ErrInvalidParameter)
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+	"analitics-service/pkg/stats"
+)
+
+// minDaypartObservations минимальное количество дней с продажами в части дня для оценки скидки
+const minDaypartObservations = 14
+
+// DaypartService определяет интерфейс анализа продаж по часам и частям дня
+type DaypartService interface {
+	// BuildReport строит отчет по часам и частям дня: выручка, размер чека, лидеры продаж
+	// и ассоциативные правила, найденные отдельно для каждой части дня
+	BuildReport(ctx context.Context, startDate, endDate time.Time, options entities.DaypartAnalysisOptions) (*entities.DaypartReport, error)
+
+	// RecommendDiscountsByDaypart оценивает оптимальную скидку на товар отдельно для каждой части дня
+	// Части дня без достаточной истории скидок пропускаются
+	RecommendDiscountsByDaypart(ctx context.Context, productID string, startDate, endDate time.Time, options entities.DaypartAnalysisOptions) ([]entities.DiscountRecommendation, error)
+}
+
+// daypartService реализует интерфейс DaypartService
+type daypartService struct {
+	transactionRepo repositories.TransactionRepository
+	salesRepo       repositories.SalesRepository
+	segmentRepo     repositories.ABCSegmentRepository
+	aprioriService  AprioriService
+	logger          logger.Logger
+}
+
+// daypartAccumulator накапливает показатели части дня или часа
+type daypartAccumulator struct {
+	revenue      float64
+	units        int
+	transactions int
+	basketItems  int
+	basketValue  float64
+	products     map[string]*entities.DaypartProduct
+	baskets      []entities.Transaction
+}
+
+// NewDaypartService создает новый экземпляр сервиса анализа частей дня
+func NewDaypartService(
+	transactionRepo repositories.TransactionRepository,
+	salesRepo repositories.SalesRepository,
+	segmentRepo repositories.ABCSegmentRepository,
+	aprioriService AprioriService,
+	logger logger.Logger,
+) DaypartService {
+	return &daypartService{
+		transactionRepo: transactionRepo,
+		salesRepo:       salesRepo,
+		segmentRepo:     segmentRepo,
+		aprioriService:  aprioriService,
+		logger:          logger,
+	}
+}
+
+// BuildReport строит отчет по часам и частям дня
+func (s *daypartService) BuildReport(ctx context.Context, startDate, endDate time.Time, options entities.DaypartAnalysisOptions) (*entities.DaypartReport, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	location, _ := time.LoadLocation(options.Timezone)
+
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	if len(transactions) == 0 && len(sales) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	hourly := make([]*daypartAccumulator, 24)
+	for hour := range hourly {
+		hourly[hour] = newDaypartAccumulator()
+	}
+	parts := make(map[string]*daypartAccumulator, len(options.Dayparts))
+	for _, daypart := range options.Dayparts {
+		parts[daypart.Name] = newDaypartAccumulator()
+	}
+
+	// Чеки дают размер корзины и правила, продажи — выручку с учетом скидок и лидеров продаж
+	for _, transaction := range transactions {
+		targets := []*daypartAccumulator{hourly[transaction.Date.In(location).Hour()]}
+		if name, ok := options.DaypartOf(transaction.Date, location); ok {
+			targets = append(targets, parts[name])
+		}
+		for _, acc := range targets {
+			acc.addTransaction(transaction)
+		}
+	}
+
+	totalRevenue := 0.0
+	for _, sale := range sales {
+		revenue := saleRevenue(sale)
+		totalRevenue += revenue
+
+		targets := []*daypartAccumulator{hourly[sale.PurchaseDate.In(location).Hour()]}
+		if name, ok := options.DaypartOf(sale.PurchaseDate, location); ok {
+			targets = append(targets, parts[name])
+		}
+		for _, acc := range targets {
+			acc.addSale(sale, revenue)
+		}
+	}
+
+	report := &entities.DaypartReport{
+		AnalysisMetadata: entities.AnalysisMetadata{
+			AnalysisDate: time.Now(),
+			PeriodStart:  startDate,
+			PeriodEnd:    endDate,
+		},
+		Timezone: options.Timezone,
+		Hourly:   make([]entities.HourlySales, 24),
+		Dayparts: make([]entities.DaypartSales, 0, len(options.Dayparts)),
+	}
+
+	for hour, acc := range hourly {
+		size, value := acc.basketAverages()
+		report.Hourly[hour] = entities.HourlySales{
+			Hour:           hour,
+			Revenue:        acc.revenue,
+			Units:          acc.units,
+			Transactions:   acc.transactions,
+			AvgBasketSize:  size,
+			AvgBasketValue: value,
+		}
+	}
+
+	for _, daypart := range options.Dayparts {
+		acc := parts[daypart.Name]
+		size, value := acc.basketAverages()
+		rules, err := s.daypartRules(ctx, acc.baskets, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to mine rules for daypart %s: %w", daypart.Name, err)
+		}
+		daypartSales := entities.DaypartSales{
+			Daypart:        daypart.Name,
+			Revenue:        acc.revenue,
+			Units:          acc.units,
+			Transactions:   acc.transactions,
+			AvgBasketSize:  size,
+			AvgBasketValue: value,
+			TopProducts:    acc.topProducts(options.TopProducts),
+			Rules:          rules,
+		}
+		if totalRevenue > 0 {
+			daypartSales.RevenueShare = acc.revenue / totalRevenue
+		}
+		report.Dayparts = append(report.Dayparts, daypartSales)
+	}
+
+	s.logger.Info(ctx, "Построен отчет по частям дня", "transactions", len(transactions),
+		"sales", len(sales), "dayparts", len(report.Dayparts))
+	return report, nil
+}
+
+// RecommendDiscountsByDaypart оценивает оптимальную скидку на товар отдельно для каждой части дня
+// Для каждой части дня строится линейная зависимость дневных продаж от средней скидки,
+// оптимальной считается скидка, максимизирующая выручку
+func (s *daypartService) RecommendDiscountsByDaypart(ctx context.Context, productID string, startDate, endDate time.Time, options entities.DaypartAnalysisOptions) ([]entities.DiscountRecommendation, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	location, _ := time.LoadLocation(options.Timezone)
+
+	sales, err := s.salesRepo.GetSalesByProductID(ctx, productID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales for product %s: %w", productID, err)
+	}
+
+	segmentation, err := s.segmentRepo.GetProductSegmentation(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ABC segment for product %s: %w", productID, err)
+	}
+	// Товар без ABC-сегмента оценивается без ограничения скидки по сегменту
+	var segment entities.Segment
+	if segmentation != nil {
+		segment = segmentation.Segment
+	}
+
+	// Продажи и взвешенная скидка по дням внутри каждой части дня
+	type dayKey struct {
+		daypart string
+		day     string
+	}
+	type dayTotals struct {
+		units        float64
+		discountUnit float64
+	}
+	daily := make(map[dayKey]*dayTotals)
+	for _, sale := range sales {
+		name, ok := options.DaypartOf(sale.PurchaseDate, location)
+		if !ok {
+			continue
+		}
+		key := dayKey{daypart: name, day: sale.PurchaseDate.In(location).Format("2006-01-02")}
+		totals, exists := daily[key]
+		if !exists {
+			totals = &dayTotals{}
+			daily[key] = totals
+		}
+		totals.units += float64(sale.Quantity)
+		totals.discountUnit += sale.DiscountRate * float64(sale.Quantity)
+	}
+
+	discounts := make(map[string][]float64)
+	units := make(map[string][]float64)
+	for key, totals := range daily {
+		if totals.units == 0 {
+			continue
+		}
+		discounts[key.daypart] = append(discounts[key.daypart], totals.discountUnit/totals.units)
+		units[key.daypart] = append(units[key.daypart], totals.units)
+	}
+
+	var recommendations []entities.DiscountRecommendation
+	for _, daypart := range options.Dayparts {
+		x, y := discounts[daypart.Name], units[daypart.Name]
+		if len(x) < minDaypartObservations || stats.Variance(x) == 0 {
+			s.logger.Debug(ctx, "Недостаточно истории скидок для части дня", "productID", productID, "daypart", daypart.Name)
+			continue
+		}
+
+		intercept, slope, rSquared := simpleLinearFit(x, y)
+		recommendation := entities.DiscountRecommendation{
+			AnalysisMetadata: entities.AnalysisMetadata{
+				AnalysisDate: time.Now(),
+				PeriodStart:  startDate,
+				PeriodEnd:    endDate,
+			},
+			ProductID:   productID,
+			Daypart:     daypart.Name,
+			ABCCategory: segment,
+			Confidence:  rSquared,
+			LiftFactor:  1,
+		}
+
+		// Выручка (1 - d/100)(a + b·d) максимальна при d = (100b - a) / 2b, если спрос растет со скидкой
+		if slope > 0 && intercept > 0 {
+			optimal := (100*slope - intercept) / (2 * slope)
+			recommendation.OptimalDiscount = math.Min(math.Max(optimal, 0), options.MaxDiscount)
+			capDiscountBySegment(&recommendation)
+			recommendation.LiftFactor = (intercept + slope*recommendation.OptimalDiscount) / intercept
+		}
+
+		recommendations = append(recommendations, recommendation)
+	}
+
+	if len(recommendations) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	return recommendations, nil
+}
+
+// newDaypartAccumulator создает пустой накопитель показателей
+func newDaypartAccumulator() *daypartAccumulator {
+	return &daypartAccumulator{products: make(map[string]*entities.DaypartProduct)}
+}
+
+// addTransaction учитывает чек в размере корзины и в выборке для правил
+func (a *daypartAccumulator) addTransaction(transaction entities.Transaction) {
+	a.transactions++
+	a.basketValue += transaction.TotalAmount
+	for _, item := range transaction.Items {
+		a.basketItems += item.Quantity
+	}
+	a.baskets = append(a.baskets, transaction)
+}
+
+// addSale учитывает продажу в выручке и рейтинге товаров
+func (a *daypartAccumulator) addSale(sale entities.Sale, revenue float64) {
+	a.revenue += revenue
+	a.units += sale.Quantity
+
+	product, ok := a.products[sale.ProductID]
+	if !ok {
+		product = &entities.DaypartProduct{ProductID: sale.ProductID}
+		a.products[sale.ProductID] = product
+	}
+	product.Units += sale.Quantity
+	product.Revenue += revenue
+}
+
+// basketAverages возвращает среднее количество товаров и среднюю сумму чека
+func (a *daypartAccumulator) basketAverages() (float64, float64) {
+	if a.transactions == 0 {
+		return 0, 0
+	}
+	return float64(a.basketItems) / float64(a.transactions), a.basketValue / float64(a.transactions)
+}
+
+// topProducts возвращает товары с наибольшей выручкой
+func (a *daypartAccumulator) topProducts(limit int) []entities.DaypartProduct {
+	products := make([]entities.DaypartProduct, 0, len(a.products))
+	for _, product := range a.products {
+		products = append(products, *product)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		if products[i].Revenue != products[j].Revenue {
+			return products[i].Revenue > products[j].Revenue
+		}
+		return products[i].ProductID < products[j].ProductID
+	})
+	if len(products) > limit {
+		products = products[:limit]
+	}
+	return products
+}
+
+// daypartRules находит ассоциативные правила по чекам одной части дня и сортирует их по убыванию lift
+func (s *daypartService) daypartRules(ctx context.Context, baskets []entities.Transaction, options entities.DaypartAnalysisOptions) ([]entities.AssociationRule, error) {
+	if len(baskets) == 0 {
+		return nil, nil
+	}
+
+	rules, err := s.aprioriService.AnalyzeTransactions(ctx, baskets, options.MinSupport, options.MinConfidence)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Lift > rules[j].Lift
+	})
+	return rules, nil
+}
+
+// simpleLinearFit оценивает y = a + b·x методом наименьших квадратов и возвращает a, b и R²
+func simpleLinearFit(x, y []float64) (float64, float64, float64) {
+	meanX, meanY := stats.Mean(x), stats.Mean(y)
+
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+
+	slope := sxy / sxx
+	intercept := meanY - slope*meanX
+	rSquared := 0.0
+	if syy > 0 {
+		rSquared = sxy * sxy / (sxx * syy)
+	}
+	return intercept, slope, rSquared
+}
+
+// capDiscountBySegment ограничивает скидку в зависимости от ABC-сегмента товара
+func capDiscountBySegment(recommendation *entities.DiscountRecommendation) {
+	limit, ok := segmentDiscountCap(recommendation.ABCCategory)
+	if !ok || recommendation.OptimalDiscount <= limit {
+		return
+	}
+
+	recommendation.OptimalDiscount = limit
+	if recommendation.ABCCategory == entities.SegmentA {
+		recommendation.AdjustmentReason = "Скидка ограничена для высокодоходной категории A"
+	} else {
+		recommendation.AdjustmentReason = "Скидка скорректирована для категории B"
+	}
+}
+
+// segmentDiscountCap возвращает максимальную скидку в процентах для ABC-сегмента
+// Для сегмента C ограничение не задается
+func segmentDiscountCap(segment entities.Segment) (float64, bool) {
+	switch segment {
+	case entities.SegmentA:
+		return 20, true
+	case entities.SegmentB:
+		return 30, true
+	default:
+		return 0, false
+	}
+}
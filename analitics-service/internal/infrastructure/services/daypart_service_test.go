@@ -0,0 +1,364 @@
+// internal/infrastructure/services/daypart_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryTransactionRepository хранит чеки в памяти
+type memoryTransactionRepository struct {
+	transactions []entities.Transaction
+}
+
+func (r *memoryTransactionRepository) GetTransactionsByPeriod(_ context.Context, startDate, endDate time.Time) ([]entities.Transaction, error) {
+	var result []entities.Transaction
+	for _, transaction := range r.transactions {
+		if !transaction.Date.Before(startDate) && !transaction.Date.After(endDate) {
+			result = append(result, transaction)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryTransactionRepository) GetTransactionByID(_ context.Context, transactionID string) (entities.Transaction, error) {
+	for _, transaction := range r.transactions {
+		if transaction.ID == transactionID {
+			return transaction, nil
+		}
+	}
+	return entities.Transaction{}, errors.New("transaction not found")
+}
+
+func (r *memoryTransactionRepository) GetTransactionsByCustomerID(ctx context.Context, customerID string, startDate, endDate time.Time) ([]entities.Transaction, error) {
+	transactions, _ := r.GetTransactionsByPeriod(ctx, startDate, endDate)
+	var result []entities.Transaction
+	for _, transaction := range transactions {
+		if transaction.CustomerID == customerID {
+			result = append(result, transaction)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryTransactionRepository) CreateTransaction(_ context.Context, transaction entities.Transaction) error {
+	r.transactions = append(r.transactions, transaction)
+	return nil
+}
+
+func (r *memoryTransactionRepository) GetTransactionsWithProduct(ctx context.Context, productID string, startDate, endDate time.Time) ([]entities.Transaction, error) {
+	transactions, _ := r.GetTransactionsByPeriod(ctx, startDate, endDate)
+	var result []entities.Transaction
+	for _, transaction := range transactions {
+		for _, item := range transaction.Items {
+			if item.ProductID == productID {
+				result = append(result, transaction)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryTransactionRepository) GetTransactionCount(ctx context.Context, startDate, endDate time.Time) (int, error) {
+	transactions, _ := r.GetTransactionsByPeriod(ctx, startDate, endDate)
+	return len(transactions), nil
+}
+
+// memorySegmentRepository хранит ABC-сегменты товаров в памяти
+type memorySegmentRepository struct {
+	segments map[string]entities.Segment
+}
+
+func (r *memorySegmentRepository) SaveSegmentation(_ context.Context, _ map[string]entities.ProductFullSegmentation) error {
+	return nil
+}
+
+func (r *memorySegmentRepository) GetProductSegmentation(_ context.Context, productID string) (*entities.ProductSegmentation, error) {
+	segment, ok := r.segments[productID]
+	if !ok {
+		return nil, nil
+	}
+	return &entities.ProductSegmentation{ProductID: productID, Segment: segment}, nil
+}
+
+func (r *memorySegmentRepository) GetFullSegmentation(_ context.Context) (map[string]entities.ProductFullSegmentation, error) {
+	result := make(map[string]entities.ProductFullSegmentation, len(r.segments))
+	for productID, segment := range r.segments {
+		result[productID] = entities.ProductFullSegmentation{ProductID: productID, FinalSegment: segment}
+	}
+	return result, nil
+}
+
+func (r *memorySegmentRepository) GetSegmentationByCategory(_ context.Context, _ string) ([]entities.ProductSegmentation, error) {
+	return nil, nil
+}
+
+func (r *memorySegmentRepository) GetLatestAnalysisDate(_ context.Context) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+// testTransaction возвращает чек клиента с позициями по одной единице товара
+func testTransaction(id string, at time.Time, total float64, productIDs ...string) entities.Transaction {
+	transaction := entities.Transaction{CustomerID: "C1", Date: at, TotalAmount: total}
+	transaction.ID = id
+	for _, productID := range productIDs {
+		transaction.Items = append(transaction.Items, entities.Item{ProductID: productID, Name: productID, Price: 1, Quantity: 1})
+	}
+	return transaction
+}
+
+// testDaypartService возвращает сервис частей дня с указанными чеками, продажами и сегментами
+func testDaypartService(transactions []entities.Transaction, sales []entities.Sale, segments map[string]entities.Segment) services.DaypartService {
+	log := logger.NewLogger("ERROR")
+	return services.NewDaypartService(
+		&memoryTransactionRepository{transactions: transactions},
+		&memorySalesRepository{sales: sales},
+		&memorySegmentRepository{segments: segments},
+		services.NewAprioriService(log),
+		log,
+	)
+}
+
+func TestBuildDaypartReport(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	// Время UTC, отчет строится по Москве (UTC+3): 05:30 — завтрак, 09:00 — обед, 21:00 — полночь вне частей дня
+	breakfast, lunch, night := day.Add(5*time.Hour+30*time.Minute), day.Add(9*time.Hour), day.Add(21*time.Hour)
+
+	transactions := []entities.Transaction{
+		testTransaction("T1", breakfast, 300, "P1", "P2"),
+		testTransaction("T2", breakfast, 200, "P1", "P2"),
+		testTransaction("T3", breakfast, 100, "P1"),
+		testTransaction("T4", lunch, 500, "P3"),
+	}
+	sales := []entities.Sale{
+		testSale("P1", 3, 100, 0, breakfast),
+		testSale("P2", 2, 150, 50, breakfast),
+		testSale("P4", 1, 150, 0, breakfast),
+		testSale("P3", 1, 500, 0, lunch),
+		testSale("P1", 1, 100, 0, night),
+	}
+
+	options := entities.DefaultDaypartAnalysisOptions()
+	options.Timezone = "Europe/Moscow"
+	options.TopProducts = 2
+	options.MinSupport = 0.5
+	options.MinConfidence = 0.5
+
+	service := testDaypartService(transactions, sales, nil)
+	report, err := service.BuildReport(context.Background(), day, day.AddDate(0, 0, 1), options)
+	if err != nil {
+		t.Fatalf("BuildReport() error = %v", err)
+	}
+
+	// Выручка: завтрак 300 + 150 + 150 = 600, обед 500, полночь 100, всего 1200
+	tests := []struct {
+		name         string
+		daypart      string
+		revenue      float64
+		share        float64
+		units        int
+		transactions int
+		basketSize   float64
+		basketValue  float64
+		topProducts  []string
+		withRules    bool
+	}{
+		{
+			// P2 и P4 делят выручку 150, при равной выручке порядок определяется ID товара
+			name: "breakfast", daypart: "breakfast", revenue: 600, share: 0.5, units: 6,
+			transactions: 3, basketSize: 5.0 / 3, basketValue: 200, topProducts: []string{"P1", "P2"}, withRules: true,
+		},
+		{
+			name: "lunch", daypart: "lunch", revenue: 500, share: 500.0 / 1200, units: 1,
+			transactions: 1, basketSize: 1, basketValue: 500, topProducts: []string{"P3"},
+		},
+		{name: "afternoon without sales", daypart: "afternoon"},
+	}
+
+	parts := make(map[string]entities.DaypartSales, len(report.Dayparts))
+	for _, daypart := range report.Dayparts {
+		parts[daypart.Daypart] = daypart
+	}
+	if len(report.Dayparts) != len(options.Dayparts) {
+		t.Fatalf("dayparts = %d, want %d", len(report.Dayparts), len(options.Dayparts))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parts[tt.daypart]
+			if math.Abs(got.Revenue-tt.revenue) > 1e-9 || math.Abs(got.RevenueShare-tt.share) > 1e-9 {
+				t.Errorf("revenue = %.2f (share %.4f), want %.2f (share %.4f)", got.Revenue, got.RevenueShare, tt.revenue, tt.share)
+			}
+			if got.Units != tt.units || got.Transactions != tt.transactions {
+				t.Errorf("units = %d, transactions = %d, want %d and %d", got.Units, got.Transactions, tt.units, tt.transactions)
+			}
+			if math.Abs(got.AvgBasketSize-tt.basketSize) > 1e-9 || math.Abs(got.AvgBasketValue-tt.basketValue) > 1e-9 {
+				t.Errorf("basket = %.4f items / %.2f, want %.4f / %.2f", got.AvgBasketSize, got.AvgBasketValue, tt.basketSize, tt.basketValue)
+			}
+			if len(got.TopProducts) != len(tt.topProducts) {
+				t.Fatalf("top products = %+v, want %v", got.TopProducts, tt.topProducts)
+			}
+			for i, productID := range tt.topProducts {
+				if got.TopProducts[i].ProductID != productID {
+					t.Errorf("top products = %+v, want %v", got.TopProducts, tt.topProducts)
+					break
+				}
+			}
+			if (len(got.Rules) > 0) != tt.withRules {
+				t.Errorf("rules = %d, want rules %v", len(got.Rules), tt.withRules)
+			}
+			for i := 1; i < len(got.Rules); i++ {
+				if got.Rules[i].Lift > got.Rules[i-1].Lift {
+					t.Errorf("rules not sorted by lift: %.4f after %.4f", got.Rules[i].Lift, got.Rules[i-1].Lift)
+				}
+			}
+		})
+	}
+
+	// Почасовые показатели считаются в часовом поясе отчета и включают часы вне частей дня
+	hourly := []struct {
+		hour    int
+		revenue float64
+	}{
+		{hour: 8, revenue: 600},
+		{hour: 12, revenue: 500},
+		{hour: 0, revenue: 100},
+		{hour: 5, revenue: 0},
+	}
+	for _, tt := range hourly {
+		if got := report.Hourly[tt.hour]; got.Hour != tt.hour || math.Abs(got.Revenue-tt.revenue) > 1e-9 {
+			t.Errorf("hour %d revenue = %.2f, want %.2f", tt.hour, got.Revenue, tt.revenue)
+		}
+	}
+}
+
+func TestBuildDaypartReportErrors(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	service := testDaypartService(nil, nil, nil)
+
+	overlapping := entities.DefaultDaypartAnalysisOptions()
+	overlapping.Dayparts = append(overlapping.Dayparts, entities.Daypart{Name: "brunch", StartHour: 10, EndHour: 12})
+	unknownZone := entities.DefaultDaypartAnalysisOptions()
+	unknownZone.Timezone = "Mars/Olympus"
+
+	tests := []struct {
+		name    string
+		options entities.DaypartAnalysisOptions
+		want    error
+	}{
+		{name: "overlapping dayparts", options: overlapping, want: services.ErrInvalidParameter},
+		{name: "unknown timezone", options: unknownZone, want: services.ErrInvalidParameter},
+		{name: "no data", options: entities.DefaultDaypartAnalysisOptions(), want: services.ErrInsufficientData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.BuildReport(context.Background(), day, day.AddDate(0, 0, 1), tt.options); !errors.Is(err, tt.want) {
+				t.Errorf("BuildReport() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendDiscountsByDaypart(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// demandSales возвращает по одной продаже в завтрак за каждый день двух недель со скидкой 0, 2, ..., 26%
+	// и спросом intercept + slope·скидка, а также три дня продаж в обед
+	demandSales := func(intercept, slope int) []entities.Sale {
+		var sales []entities.Sale
+		for i := 0; i < 14; i++ {
+			discount := 2 * i
+			sales = append(sales, testSale("P1", intercept+slope*discount, 100, float64(discount), start.AddDate(0, 0, i).Add(8*time.Hour)))
+		}
+		for i := 0; i < 3; i++ {
+			sales = append(sales, testSale("P1", 10, 100, float64(10*i), start.AddDate(0, 0, i).Add(12*time.Hour)))
+		}
+		return sales
+	}
+
+	options := entities.DefaultDaypartAnalysisOptions()
+	options.MaxDiscount = 40
+
+	// При спросе 10 + d выручка максимальна при d = (100 - 10) / 2 = 45%
+	tests := []struct {
+		name         string
+		sales        []entities.Sale
+		segment      entities.Segment
+		wantDiscount float64
+		wantLift     float64
+		wantReason   bool
+	}{
+		{name: "capped by max discount", sales: demandSales(10, 1), segment: entities.SegmentC, wantDiscount: 40, wantLift: 5},
+		{name: "capped for segment B", sales: demandSales(10, 1), segment: entities.SegmentB, wantDiscount: 30, wantLift: 4, wantReason: true},
+		{name: "capped for segment A", sales: demandSales(10, 1), segment: entities.SegmentA, wantDiscount: 20, wantLift: 3, wantReason: true},
+		{name: "demand falls with discount", sales: demandSales(40, -1), segment: entities.SegmentC, wantDiscount: 0, wantLift: 1},
+		// Товар без ABC-сегмента ограничивается только максимальной скидкой
+		{name: "without ABC segment", sales: demandSales(10, 1), wantDiscount: 40, wantLift: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments := map[string]entities.Segment{}
+			if tt.segment != "" {
+				segments["P1"] = tt.segment
+			}
+			service := testDaypartService(nil, tt.sales, segments)
+			recommendations, err := service.RecommendDiscountsByDaypart(context.Background(), "P1", start, start.AddDate(0, 0, 14), options)
+			if err != nil {
+				t.Fatalf("RecommendDiscountsByDaypart() error = %v", err)
+			}
+
+			// Обед с тремя днями истории пропускается
+			if len(recommendations) != 1 || recommendations[0].Daypart != "breakfast" {
+				t.Fatalf("recommendations = %+v, want only breakfast", recommendations)
+			}
+			got := recommendations[0]
+			if math.Abs(got.OptimalDiscount-tt.wantDiscount) > 1e-9 || math.Abs(got.LiftFactor-tt.wantLift) > 1e-9 {
+				t.Errorf("discount = %.2f (lift %.4f), want %.2f (lift %.4f)", got.OptimalDiscount, got.LiftFactor, tt.wantDiscount, tt.wantLift)
+			}
+			if math.Abs(got.Confidence-1) > 1e-9 {
+				t.Errorf("confidence = %.4f, want 1 for exact linear demand", got.Confidence)
+			}
+			if (got.AdjustmentReason != "") != tt.wantReason {
+				t.Errorf("adjustment reason = %q, want reason %v", got.AdjustmentReason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRecommendDiscountsByDaypartInsufficientData(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var constant []entities.Sale
+	for i := 0; i < 20; i++ {
+		constant = append(constant, testSale("P1", 10, 100, 10, start.AddDate(0, 0, i).Add(8*time.Hour)))
+	}
+
+	tests := []struct {
+		name  string
+		sales []entities.Sale
+	}{
+		{name: "no sales"},
+		// Скидка не менялась, поэтому зависимость спроса от нее оценить нельзя
+		{name: "constant discount", sales: constant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := testDaypartService(nil, tt.sales, nil)
+			_, err := service.RecommendDiscountsByDaypart(context.Background(), "P1", start, start.AddDate(0, 0, 20), entities.DefaultDaypartAnalysisOptions())
+			if !errors.Is(err, services.ErrInsufficientData) {
+				t.Errorf("RecommendDiscountsByDaypart() error = %v, want %v", err, services.ErrInsufficientData)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+	"analitics-service/pkg/stats"
+)
+
+// Ошибки сервиса экспериментов
+var (
+	ErrExperimentNotFound   = errors.New("experiment not found")
+	ErrExperimentNotActive  = errors.New("experiment is not active")
+	ErrCustomerNotEligible  = errors.New("customer is not eligible for experiment")
+	ErrExperimentOverlap    = errors.New("experiment overlaps with another experiment in the same layer")
+	ErrNoExperimentExposure = errors.New("experiment has no exposures")
+)
+
+// ExperimentService определяет интерфейс реестра экспериментов и назначения клиентов в варианты
+type ExperimentService interface {
+	// RegisterExperiment проверяет и сохраняет эксперимент в реестре
+	RegisterExperiment(ctx context.Context, experiment entities.Experiment) error
+
+	// Assign детерминированно назначает клиента в вариант эксперимента
+	// segments — сегменты клиента, используемые для проверки таргетинга
+	Assign(ctx context.Context, experimentID, customerID string, segments []string) (*entities.ExperimentAssignment, error)
+
+	// AssignAll назначает клиента во все активные эксперименты, по одному на слой
+	AssignAll(ctx context.Context, customerID string, segments []string, at time.Time) ([]entities.ExperimentAssignment, error)
+
+	// LogExposure фиксирует показ варианта клиенту
+	LogExposure(ctx context.Context, assignment entities.ExperimentAssignment, exposedAt time.Time, channel string) error
+
+	// ComputeTestResults рассчитывает статистики групп по реальным продажам после первого показа
+	// и сохраняет результат через ABTestRepository. Для каждого тестового варианта
+	// формируется отдельный результат относительно контроля
+	ComputeTestResults(ctx context.Context, experimentID string) ([]entities.ABTestResult, map[string]*entities.ABTestSamples, error)
+}
+
+// experimentService реализует интерфейс ExperimentService
+type experimentService struct {
+	experimentRepo repositories.ExperimentRepository
+	exposureRepo   repositories.ExposureRepository
+	salesRepo      repositories.SalesRepository
+	abTestRepo     repositories.ABTestRepository
+	logger         logger.Logger
+}
+
+// NewExperimentService создает новый экземпляр сервиса экспериментов
+func NewExperimentService(
+	experimentRepo repositories.ExperimentRepository,
+	exposureRepo repositories.ExposureRepository,
+	salesRepo repositories.SalesRepository,
+	abTestRepo repositories.ABTestRepository,
+	logger logger.Logger,
+) ExperimentService {
+	return &experimentService{
+		experimentRepo: experimentRepo,
+		exposureRepo:   exposureRepo,
+		salesRepo:      salesRepo,
+		abTestRepo:     abTestRepo,
+		logger:         logger,
+	}
+}
+
+// RegisterExperiment проверяет и сохраняет эксперимент в реестре
+func (s *experimentService) RegisterExperiment(ctx context.Context, experiment entities.Experiment) error {
+	if err := experiment.Validate(); err != nil {
+		return fmt.Errorf("%w: invalid experiment: %v", ErrInvalidParameter, err)
+	}
+
+	// Эксперименты одного слоя не должны делить бакеты в пересекающиеся периоды
+	layerExperiments, err := s.experimentRepo.GetExperimentsByLayer(ctx, experiment.Layer())
+	if err != nil {
+		return fmt.Errorf("failed to retrieve layer experiments: %w", err)
+	}
+	for _, other := range layerExperiments {
+		if other.ID == experiment.ID || other.Status == entities.ExperimentCompleted {
+			continue
+		}
+		if experiment.OverlapsWith(other) {
+			return fmt.Errorf("%w: %s", ErrExperimentOverlap, other.ID)
+		}
+	}
+
+	if experiment.Status == "" {
+		experiment.Status = entities.ExperimentDraft
+	}
+	now := time.Now()
+	if experiment.CreatedAt.IsZero() {
+		experiment.CreatedAt = now
+	}
+	experiment.UpdatedAt = now
+
+	if err := s.experimentRepo.SaveExperiment(ctx, experiment); err != nil {
+		return fmt.Errorf("failed to save experiment: %w", err)
+	}
+
+	s.logger.Info(ctx, "Эксперимент зарегистрирован", "experimentID", experiment.ID, "layer", experiment.Layer())
+	return nil
+}
+
+// Assign детерминированно назначает клиента в вариант эксперимента
+func (s *experimentService) Assign(ctx context.Context, experimentID, customerID string, segments []string) (*entities.ExperimentAssignment, error) {
+	experiment, err := s.experimentRepo.GetExperimentByID(ctx, experimentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve experiment %s: %w", experimentID, err)
+	}
+	if experiment.ID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrExperimentNotFound, experimentID)
+	}
+
+	if !experiment.IsActive(time.Now()) {
+		return nil, ErrExperimentNotActive
+	}
+
+	return assignToExperiment(experiment, customerID, segments)
+}
+
+// AssignAll назначает клиента во все активные эксперименты, по одному на слой
+func (s *experimentService) AssignAll(ctx context.Context, customerID string, segments []string, at time.Time) ([]entities.ExperimentAssignment, error) {
+	experiments, err := s.experimentRepo.GetActiveExperiments(ctx, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve active experiments: %w", err)
+	}
+
+	// Сортируем для детерминированного порядка при пересечениях, допущенных вне реестра
+	sort.Slice(experiments, func(i, j int) bool {
+		return experiments[i].ID < experiments[j].ID
+	})
+
+	assignments := make([]entities.ExperimentAssignment, 0)
+	assignedLayers := make(map[string]struct{})
+	for _, experiment := range experiments {
+		if !experiment.IsActive(at) {
+			continue
+		}
+		if _, taken := assignedLayers[experiment.Layer()]; taken {
+			continue
+		}
+
+		assignment, err := assignToExperiment(experiment, customerID, segments)
+		if errors.Is(err, ErrCustomerNotEligible) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		assignedLayers[experiment.Layer()] = struct{}{}
+		assignments = append(assignments, *assignment)
+	}
+
+	return assignments, nil
+}
+
+// LogExposure фиксирует показ варианта клиенту
+func (s *experimentService) LogExposure(ctx context.Context, assignment entities.ExperimentAssignment, exposedAt time.Time, channel string) error {
+	exposure := entities.Exposure{
+		BaseEntity: entities.BaseEntity{
+			ID:        fmt.Sprintf("%s:%s:%d", assignment.ExperimentID, assignment.CustomerID, exposedAt.UnixNano()),
+			CreatedAt: time.Now(),
+		},
+		ExperimentID: assignment.ExperimentID,
+		VariantID:    assignment.VariantID,
+		CustomerID:   assignment.CustomerID,
+		ExposedAt:    exposedAt,
+		Channel:      channel,
+	}
+
+	if err := exposure.Validate(); err != nil {
+		return fmt.Errorf("%w: invalid exposure: %v", ErrInvalidParameter, err)
+	}
+
+	if err := s.exposureRepo.LogExposure(ctx, exposure); err != nil {
+		return fmt.Errorf("failed to log exposure: %w", err)
+	}
+
+	return nil
+}
+
+// ComputeTestResults рассчитывает статистики групп по реальным продажам после первого показа
+func (s *experimentService) ComputeTestResults(ctx context.Context, experimentID string) ([]entities.ABTestResult, map[string]*entities.ABTestSamples, error) {
+	experiment, err := s.experimentRepo.GetExperimentByID(ctx, experimentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve experiment %s: %w", experimentID, err)
+	}
+	if experiment.ID == "" {
+		return nil, nil, fmt.Errorf("%w: %s", ErrExperimentNotFound, experimentID)
+	}
+
+	exposures, err := s.exposureRepo.GetFirstExposures(ctx, experimentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve exposures: %w", err)
+	}
+	if len(exposures) == 0 {
+		return nil, nil, ErrNoExperimentExposure
+	}
+
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, experiment.StartDate, experiment.EndDate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	revenueByVariant := aggregateExposedRevenue(experiment, exposures, sales)
+
+	control, _ := experiment.ControlVariant()
+	controlRevenue := revenueByVariant[control.ID]
+
+	results := make([]entities.ABTestResult, 0, len(experiment.Variants)-1)
+	samples := make(map[string]*entities.ABTestSamples, len(experiment.Variants)-1)
+	for _, variant := range experiment.Variants {
+		if variant.IsControl {
+			continue
+		}
+
+		testRevenue := revenueByVariant[variant.ID]
+		result := entities.ABTestResult{
+			TestID:       experimentTestID(experiment, variant),
+			StartDate:    experiment.StartDate,
+			EndDate:      experiment.EndDate,
+			Description:  fmt.Sprintf("%s: %s vs %s", experiment.Name, variant.Name, control.Name),
+			ProductIDs:   experiment.ProductIDs,
+			AnalysisMode: experiment.AnalysisMode,
+			ControlGroup: groupStatsFromRevenue(controlRevenue),
+			TestGroup: entities.TestGroupStats{
+				GroupStats:  groupStatsFromRevenue(testRevenue),
+				DiscountPct: variant.DiscountPct,
+				CouponUsed:  variant.CouponCode != "",
+			},
+		}
+		result.Lift = relativeLift(result.ControlGroup.Conversion, result.TestGroup.Conversion)
+
+		if err := s.abTestRepo.SaveTestResult(ctx, result); err != nil {
+			return nil, nil, fmt.Errorf("failed to save test result %s: %w", result.TestID, err)
+		}
+
+		results = append(results, result)
+		samples[result.TestID] = &entities.ABTestSamples{
+			Control: groupSamplesFromRevenue(controlRevenue),
+			Test:    groupSamplesFromRevenue(testRevenue),
+		}
+	}
+
+	s.logger.Info(ctx, "Рассчитаны результаты эксперимента", "experimentID", experimentID,
+		"exposures", len(exposures), "results", len(results))
+	return results, samples, nil
+}
+
+// assignToExperiment назначает клиента в вариант эксперимента по хэшу его идентификатора
+func assignToExperiment(experiment entities.Experiment, customerID string, segments []string) (*entities.ExperimentAssignment, error) {
+	if customerID == "" {
+		return nil, fmt.Errorf("%w: customer ID is required", ErrInvalidParameter)
+	}
+
+	if !matchesTargetSegments(experiment.TargetSegments, segments) {
+		return nil, ErrCustomerNotEligible
+	}
+
+	// Бакет слоя определяет, попадает ли клиент в диапазон эксперимента
+	layerBucket := hashBucket(experiment.Layer(), customerID, entities.ExperimentBuckets)
+	if layerBucket < experiment.BucketStart || layerBucket >= experiment.BucketEnd {
+		return nil, ErrCustomerNotEligible
+	}
+
+	// Вариант выбирается независимым хэшем с солью эксперимента
+	point := float64(hashBucket(experiment.ID, customerID, entities.ExperimentBuckets)) / entities.ExperimentBuckets
+	variant := experiment.Variants[len(experiment.Variants)-1]
+	cumulative := 0.0
+	for _, v := range experiment.Variants {
+		cumulative += v.Weight
+		if point < cumulative {
+			variant = v
+			break
+		}
+	}
+
+	return &entities.ExperimentAssignment{
+		ExperimentID: experiment.ID,
+		VariantID:    variant.ID,
+		CustomerID:   customerID,
+		LayerBucket:  layerBucket,
+		IsControl:    variant.IsControl,
+		DiscountPct:  variant.DiscountPct,
+		CouponCode:   variant.CouponCode,
+	}, nil
+}
+
+// hashBucket возвращает номер бакета клиента для указанной соли
+func hashBucket(salt, customerID string, buckets int) int {
+	sum := sha256.Sum256([]byte(salt + ":" + customerID))
+	return int(binary.BigEndian.Uint64(sum[:8]) % uint64(buckets))
+}
+
+// matchesTargetSegments проверяет, входит ли клиент хотя бы в один целевой сегмент
+func matchesTargetSegments(targets, segments []string) bool {
+	if len(targets) == 0 {
+		return true
+	}
+	for _, target := range targets {
+		for _, segment := range segments {
+			if target == segment {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// experimentTestID формирует идентификатор результата A/B теста для варианта эксперимента
+func experimentTestID(experiment entities.Experiment, variant entities.ExperimentVariant) string {
+	if len(experiment.Variants) == 2 {
+		return experiment.ID
+	}
+	return experiment.ID + "/" + variant.ID
+}
+
+// aggregateExposedRevenue рассчитывает выручку каждого показанного клиента после первого показа
+// Результат сгруппирован по вариантам: variantID -> customerID -> выручка
+func aggregateExposedRevenue(experiment entities.Experiment, exposures []entities.Exposure, sales []entities.Sale) map[string]map[string]float64 {
+	firstExposure := make(map[string]entities.Exposure, len(exposures))
+	revenue := make(map[string]map[string]float64, len(experiment.Variants))
+	for _, variant := range experiment.Variants {
+		revenue[variant.ID] = make(map[string]float64)
+	}
+
+	for _, exposure := range exposures {
+		if _, known := revenue[exposure.VariantID]; !known {
+			continue
+		}
+		if prev, exists := firstExposure[exposure.CustomerID]; exists && !exposure.ExposedAt.Before(prev.ExposedAt) {
+			continue
+		}
+		firstExposure[exposure.CustomerID] = exposure
+	}
+	for customerID, exposure := range firstExposure {
+		revenue[exposure.VariantID][customerID] = 0
+	}
+
+	products := make(map[string]struct{}, len(experiment.ProductIDs))
+	for _, productID := range experiment.ProductIDs {
+		products[productID] = struct{}{}
+	}
+
+	for _, sale := range sales {
+		exposure, exposed := firstExposure[sale.CustomerID]
+		if !exposed || sale.PurchaseDate.Before(exposure.ExposedAt) {
+			continue
+		}
+		if len(products) > 0 {
+			if _, tracked := products[sale.ProductID]; !tracked {
+				continue
+			}
+		}
+		revenue[exposure.VariantID][sale.CustomerID] += saleRevenue(sale)
+	}
+
+	return revenue
+}
+
+// saleRevenue возвращает выручку продажи с учетом скидки
+func saleRevenue(sale entities.Sale) float64 {
+	return sale.Price * float64(sale.Quantity) * (1 - sale.DiscountRate/100)
+}
+
+// groupStatsFromRevenue рассчитывает статистики группы по выручке её клиентов
+func groupStatsFromRevenue(revenue map[string]float64) entities.GroupStats {
+	samples := groupSamplesFromRevenue(revenue)
+
+	group := entities.GroupStats{
+		Size: len(samples.RevenuePerUser),
+	}
+	if group.Size == 0 {
+		return group
+	}
+
+	for _, value := range samples.RevenuePerUser {
+		group.Revenue += value
+	}
+	group.Conversion = float64(len(samples.PurchaseAmounts)) / float64(group.Size)
+	group.AvgPurchase = stats.Mean(samples.PurchaseAmounts)
+	group.AvgPurchaseStdDev = stats.StdDev(samples.PurchaseAmounts)
+	group.RevenueStdDev = stats.StdDev(samples.RevenuePerUser)
+
+	return group
+}
+
+// groupSamplesFromRevenue формирует наблюдения группы по выручке её клиентов
+func groupSamplesFromRevenue(revenue map[string]float64) entities.GroupSamples {
+	// Сортируем клиентов, чтобы порядок наблюдений (и бутстреп) был воспроизводимым
+	customers := make([]string, 0, len(revenue))
+	for customerID := range revenue {
+		customers = append(customers, customerID)
+	}
+	sort.Strings(customers)
+
+	samples := entities.GroupSamples{
+		RevenuePerUser: make([]float64, 0, len(customers)),
+	}
+	for _, customerID := range customers {
+		value := revenue[customerID]
+		samples.RevenuePerUser = append(samples.RevenuePerUser, value)
+		if value > 0 {
+			samples.PurchaseAmounts = append(samples.PurchaseAmounts, value)
+		}
+	}
+
+	return samples
+}
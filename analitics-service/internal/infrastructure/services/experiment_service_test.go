@@ -0,0 +1,242 @@
+// internal/infrastructure/services/experiment_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryExperimentRepository хранит эксперименты в памяти
+type memoryExperimentRepository struct {
+	experiments map[string]entities.Experiment
+}
+
+func newMemoryExperimentRepository() *memoryExperimentRepository {
+	return &memoryExperimentRepository{experiments: make(map[string]entities.Experiment)}
+}
+
+func (r *memoryExperimentRepository) SaveExperiment(_ context.Context, experiment entities.Experiment) error {
+	r.experiments[experiment.ID] = experiment
+	return nil
+}
+
+func (r *memoryExperimentRepository) GetExperimentByID(_ context.Context, experimentID string) (entities.Experiment, error) {
+	return r.experiments[experimentID], nil
+}
+
+func (r *memoryExperimentRepository) GetExperimentsByLayer(_ context.Context, layerID string) ([]entities.Experiment, error) {
+	var result []entities.Experiment
+	for _, experiment := range r.experiments {
+		if experiment.Layer() == layerID {
+			result = append(result, experiment)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryExperimentRepository) GetActiveExperiments(_ context.Context, at time.Time) ([]entities.Experiment, error) {
+	var result []entities.Experiment
+	for _, experiment := range r.experiments {
+		if experiment.IsActive(at) {
+			result = append(result, experiment)
+		}
+	}
+	return result, nil
+}
+
+// newTestExperiment возвращает запущенный эксперимент слоя checkout с долей теста testWeight
+func newTestExperiment(id string, bucketStart, bucketEnd int, testWeight float64) entities.Experiment {
+	experiment := entities.Experiment{
+		Name:        id,
+		LayerID:     "checkout",
+		BucketStart: bucketStart,
+		BucketEnd:   bucketEnd,
+		Variants: []entities.ExperimentVariant{
+			{ID: "control", Name: "Control", Weight: 1 - testWeight, IsControl: true},
+			{ID: "discount", Name: "Discount", Weight: testWeight, DiscountPct: 10},
+		},
+		Status:    entities.ExperimentRunning,
+		StartDate: time.Now().Add(-24 * time.Hour),
+		EndDate:   time.Now().Add(24 * time.Hour),
+	}
+	experiment.ID = id
+	return experiment
+}
+
+func TestExperimentAssignmentSplit(t *testing.T) {
+	tests := []struct {
+		name          string
+		bucketStart   int
+		bucketEnd     int
+		testWeight    float64
+		wantEligible  float64
+		wantTestShare float64
+	}{
+		{name: "full layer even split", bucketStart: 0, bucketEnd: entities.ExperimentBuckets, testWeight: 0.5, wantEligible: 1, wantTestShare: 0.5},
+		{name: "half layer", bucketStart: 0, bucketEnd: entities.ExperimentBuckets / 2, testWeight: 0.5, wantEligible: 0.5, wantTestShare: 0.5},
+		{name: "uneven split", bucketStart: 2500, bucketEnd: 7500, testWeight: 0.2, wantEligible: 0.5, wantTestShare: 0.2},
+	}
+
+	const customers = 20000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryExperimentRepository()
+			service := services.NewExperimentService(repo, nil, nil, nil, logger.NewLogger("ERROR"))
+			if err := service.RegisterExperiment(ctx, newTestExperiment("exp-1", tt.bucketStart, tt.bucketEnd, tt.testWeight)); err != nil {
+				t.Fatalf("RegisterExperiment() error = %v", err)
+			}
+
+			eligible, test := 0, 0
+			for i := 0; i < customers; i++ {
+				customerID := fmt.Sprintf("C%05d", i)
+				assignment, err := service.Assign(ctx, "exp-1", customerID, nil)
+				if errors.Is(err, services.ErrCustomerNotEligible) {
+					continue
+				}
+				if err != nil {
+					t.Fatalf("Assign() error = %v", err)
+				}
+
+				again, err := service.Assign(ctx, "exp-1", customerID, nil)
+				if err != nil || again.VariantID != assignment.VariantID {
+					t.Fatalf("Assign(%s) is not deterministic: %s then %v (err %v)", customerID, assignment.VariantID, again, err)
+				}
+				if assignment.LayerBucket < tt.bucketStart || assignment.LayerBucket >= tt.bucketEnd {
+					t.Fatalf("layer bucket %d outside [%d, %d)", assignment.LayerBucket, tt.bucketStart, tt.bucketEnd)
+				}
+
+				eligible++
+				if !assignment.IsControl {
+					test++
+				}
+			}
+
+			if share := float64(eligible) / customers; math.Abs(share-tt.wantEligible) > 0.02 {
+				t.Errorf("eligible share = %.3f, want %.3f", share, tt.wantEligible)
+			}
+			if share := float64(test) / float64(eligible); math.Abs(share-tt.wantTestShare) > 0.02 {
+				t.Errorf("test share = %.3f, want %.3f", share, tt.wantTestShare)
+			}
+		})
+	}
+}
+
+func TestExperimentLayersAreMutuallyExclusive(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryExperimentRepository()
+	service := services.NewExperimentService(repo, nil, nil, nil, logger.NewLogger("ERROR"))
+	for _, experiment := range []entities.Experiment{
+		newTestExperiment("exp-a", 0, 5000, 0.5),
+		newTestExperiment("exp-b", 5000, 10000, 0.5),
+	} {
+		if err := service.RegisterExperiment(ctx, experiment); err != nil {
+			t.Fatalf("RegisterExperiment(%s) error = %v", experiment.ID, err)
+		}
+	}
+
+	perExperiment := make(map[string]int)
+	for i := 0; i < 2000; i++ {
+		assignments, err := service.AssignAll(ctx, fmt.Sprintf("C%05d", i), nil, time.Now())
+		if err != nil {
+			t.Fatalf("AssignAll() error = %v", err)
+		}
+		if len(assignments) != 1 {
+			t.Fatalf("customer assigned to %d experiments of one layer, want 1", len(assignments))
+		}
+		perExperiment[assignments[0].ExperimentID]++
+	}
+
+	for _, id := range []string{"exp-a", "exp-b"} {
+		if perExperiment[id] < 900 || perExperiment[id] > 1100 {
+			t.Errorf("%s customers = %d, want about 1000", id, perExperiment[id])
+		}
+	}
+}
+
+func TestRegisterExperimentOverlap(t *testing.T) {
+	// Завершенный эксперимент слоя archive занимает все бакеты, но не блокирует новые эксперименты
+	completed := newTestExperiment("exp-done", 0, entities.ExperimentBuckets, 0.5)
+	completed.LayerID = "archive"
+	completed.Status = entities.ExperimentCompleted
+
+	archived := newTestExperiment("exp-new", 0, 5000, 0.5)
+	archived.LayerID = "archive"
+
+	otherLayer := newTestExperiment("exp-other", 0, entities.ExperimentBuckets, 0.5)
+	otherLayer.LayerID = "search"
+
+	later := newTestExperiment("exp-later", 0, 5000, 0.5)
+	later.StartDate = time.Now().Add(48 * time.Hour)
+	later.EndDate = time.Now().Add(72 * time.Hour)
+
+	tests := []struct {
+		name       string
+		experiment entities.Experiment
+		want       error
+	}{
+		{name: "overlapping buckets and dates", experiment: newTestExperiment("exp-new", 4000, 6000, 0.5), want: services.ErrExperimentOverlap},
+		{name: "disjoint buckets", experiment: newTestExperiment("exp-new", 5000, 10000, 0.5)},
+		{name: "disjoint dates", experiment: later},
+		{name: "other layer", experiment: otherLayer},
+		{name: "completed experiment frees buckets", experiment: archived},
+		{name: "invalid weights", experiment: newTestExperiment("exp-new", 5000, 10000, 1.5), want: services.ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryExperimentRepository()
+			repo.experiments["exp-base"] = newTestExperiment("exp-base", 0, 5000, 0.5)
+			repo.experiments[completed.ID] = completed
+
+			service := services.NewExperimentService(repo, nil, nil, nil, logger.NewLogger("ERROR"))
+			err := service.RegisterExperiment(ctx, tt.experiment)
+			if tt.want == nil && err != nil {
+				t.Errorf("RegisterExperiment() error = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("RegisterExperiment() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignTargetSegments(t *testing.T) {
+	experiment := newTestExperiment("exp-1", 0, entities.ExperimentBuckets, 0.5)
+	experiment.TargetSegments = []string{"loyal"}
+
+	tests := []struct {
+		name     string
+		segments []string
+		want     error
+	}{
+		{name: "in target segment", segments: []string{"new", "loyal"}},
+		{name: "outside target segments", segments: []string{"new"}, want: services.ErrCustomerNotEligible},
+		{name: "no segments", want: services.ErrCustomerNotEligible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryExperimentRepository()
+			repo.experiments[experiment.ID] = experiment
+			service := services.NewExperimentService(repo, nil, nil, nil, logger.NewLogger("ERROR"))
+
+			_, err := service.Assign(context.Background(), experiment.ID, "C00001", tt.segments)
+			if tt.want == nil && err != nil {
+				t.Errorf("Assign() error = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Assign() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/export"
+	"analitics-service/pkg/logger"
+)
+
+// exportListSeparator разделитель элементов списка внутри одной ячейки выгрузки
+const exportListSeparator = ";"
+
+// Колонки выгрузок; имена совпадают с JSON-полями API во всех форматах
+var (
+	abcExportColumns = []export.Column{
+		{Name: "product_id", Type: export.String},
+		{Name: "revenue_segment", Type: export.String},
+		{Name: "quantity_segment", Type: export.String},
+		{Name: "profit_segment", Type: export.String},
+		{Name: "final_segment", Type: export.String},
+		{Name: "score", Type: export.Float},
+	}
+
+	ruleExportColumns = []export.Column{
+		{Name: "antecedent", Type: export.String},
+		{Name: "consequent", Type: export.String},
+		{Name: "support", Type: export.Float},
+		{Name: "confidence", Type: export.Float},
+		{Name: "lift", Type: export.Float},
+		{Name: "categories", Type: export.String},
+		{Name: "min_price", Type: export.Float},
+		{Name: "max_price", Type: export.Float},
+		{Name: "run_id", Type: export.String},
+	}
+
+	recommendationExportColumns = []export.Column{
+		{Name: "product_id", Type: export.String},
+		{Name: "category", Type: export.String},
+		{Name: "daypart", Type: export.String},
+		{Name: "optimal_discount", Type: export.Float},
+		{Name: "lift_factor", Type: export.Float},
+		{Name: "abc_category", Type: export.String},
+		{Name: "confidence", Type: export.Float},
+		{Name: "lifecycle_stage", Type: export.String},
+		{Name: "adjustment_reason", Type: export.String},
+		{Name: "cannibalized_revenue", Type: export.Float},
+		{Name: "halo_revenue", Type: export.Float},
+		{Name: "net_incremental_revenue", Type: export.Float},
+		{Name: "analysis_date", Type: export.Time},
+		{Name: "period_start", Type: export.Time},
+		{Name: "period_end", Type: export.Time},
+		{Name: "run_id", Type: export.String},
+	}
+
+	retentionExportColumns = []export.Column{
+		{Name: "period", Type: export.String},
+		{Name: "cohort_start", Type: export.Time},
+		{Name: "cohort_size", Type: export.Int},
+		{Name: "period_offset", Type: export.Int},
+		{Name: "retained", Type: export.Int},
+		{Name: "retention_rate", Type: export.Float},
+	}
+
+	forecastExportColumns = []export.Column{
+		{Name: "forecast_id", Type: export.String},
+		{Name: "level", Type: export.String},
+		{Name: "target_id", Type: export.String},
+		{Name: "model_type", Type: export.String},
+		{Name: "generated_at", Type: export.Time},
+		{Name: "date", Type: export.Time},
+		{Name: "value", Type: export.Float},
+		{Name: "lower", Type: export.Float},
+		{Name: "upper", Type: export.Float},
+	}
+)
+
+// ExportService определяет интерфейс выгрузки аналитических результатов в файлы
+type ExportService interface {
+	// Export построчно записывает набор данных в w в указанном формате
+	// Если ошибка возникла до первой строки, в w ничего не записывается
+	Export(ctx context.Context, request entities.ExportRequest, format export.Format, w io.Writer) error
+}
+
+// exportService реализует интерфейс ExportService
+type exportService struct {
+	abcSegmentRepo     repositories.ABCSegmentRepository
+	ruleRepo           repositories.AssociationRuleRepository
+	recommendationRepo repositories.DiscountRecommendationRepository
+	forecastRepo       repositories.ForecastRepository
+	retentionService   RetentionService
+	logger             logger.Logger
+}
+
+// exportEmitFunc передает в выгрузку одну строку значений в порядке колонок набора
+type exportEmitFunc func(values ...interface{}) error
+
+// NewExportService создает новый экземпляр сервиса выгрузки
+func NewExportService(
+	abcSegmentRepo repositories.ABCSegmentRepository,
+	ruleRepo repositories.AssociationRuleRepository,
+	recommendationRepo repositories.DiscountRecommendationRepository,
+	forecastRepo repositories.ForecastRepository,
+	retentionService RetentionService,
+	logger logger.Logger,
+) ExportService {
+	return &exportService{
+		abcSegmentRepo:     abcSegmentRepo,
+		ruleRepo:           ruleRepo,
+		recommendationRepo: recommendationRepo,
+		forecastRepo:       forecastRepo,
+		retentionService:   retentionService,
+		logger:             logger,
+	}
+}
+
+// Export построчно записывает набор данных в w в указанном формате
+func (s *exportService) Export(ctx context.Context, request entities.ExportRequest, format export.Format, w io.Writer) error {
+	if err := request.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	var columns []export.Column
+	var stream func(ctx context.Context, request entities.ExportRequest, emit exportEmitFunc) error
+	switch request.Dataset {
+	case entities.ExportABCSegmentation:
+		columns, stream = abcExportColumns, s.streamABCSegmentation
+	case entities.ExportAssociationRules:
+		columns, stream = ruleExportColumns, s.streamAssociationRules
+	case entities.ExportDiscountRecommendations:
+		columns, stream = recommendationExportColumns, s.streamDiscountRecommendations
+	case entities.ExportRetentionTriangle:
+		columns, stream = retentionExportColumns, s.streamRetentionTriangle
+	case entities.ExportForecasts:
+		columns, stream = forecastExportColumns, s.streamForecasts
+	}
+
+	// Писатель создается при первой строке, чтобы ошибки чтения данных можно было вернуть до начала ответа
+	var writer export.Writer
+	open := func() error {
+		var err error
+		writer, err = export.NewWriter(format, w, columns)
+		if err != nil {
+			return fmt.Errorf("failed to create %s writer: %w", format, err)
+		}
+		return nil
+	}
+
+	rows := 0
+	err := stream(ctx, request, func(values ...interface{}) error {
+		if writer == nil {
+			if err := open(); err != nil {
+				return err
+			}
+		}
+		rows++
+		return writer.WriteRow(values)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", request.Dataset, err)
+	}
+
+	if writer == nil {
+		if err := open(); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finish %s export: %w", request.Dataset, err)
+	}
+
+	s.logger.Info(ctx, "Выгрузка сформирована", "dataset", request.Dataset, "format", format, "rows", rows)
+	return nil
+}
+
+// streamABCSegmentation выгружает текущую ABC-сегментацию товаров
+func (s *exportService) streamABCSegmentation(ctx context.Context, _ entities.ExportRequest, emit exportEmitFunc) error {
+	segmentation, err := s.abcSegmentRepo.GetFullSegmentation(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get ABC segmentation: %w", err)
+	}
+
+	for _, productID := range sortedGroupKeys(segmentation) {
+		segment := segmentation[productID]
+		if err := emit(segment.ProductID, string(segment.RevenueSegment), string(segment.QuantitySegment),
+			string(segment.ProfitSegment), string(segment.FinalSegment), segment.Score); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamAssociationRules выгружает правила потоком из репозитория, не загружая их в память
+func (s *exportService) streamAssociationRules(ctx context.Context, _ entities.ExportRequest, emit exportEmitFunc) error {
+	return s.ruleRepo.StreamRules(ctx, func(rule entities.AssociationRule) error {
+		return emit(joinItemIDs(rule.Antecedent), joinItemIDs(rule.Consequent), rule.Support, rule.Confidence, rule.Lift,
+			strings.Join(rule.Categories, exportListSeparator), rule.PriceRange[0], rule.PriceRange[1], rule.RunID)
+	})
+}
+
+// streamDiscountRecommendations выгружает последние рекомендации по скидкам
+func (s *exportService) streamDiscountRecommendations(ctx context.Context, request entities.ExportRequest, emit exportEmitFunc) error {
+	recommendations, err := s.recommendationRepo.GetLatestRecommendations(ctx, request.Limit)
+	if err != nil {
+		return fmt.Errorf("failed to get discount recommendations: %w", err)
+	}
+
+	for _, r := range recommendations {
+		if err := emit(r.ProductID, r.Category, r.Daypart, r.OptimalDiscount, r.LiftFactor, string(r.ABCCategory), r.Confidence,
+			string(r.LifecycleStage), r.AdjustmentReason, r.CannibalizedRevenue, r.HaloRevenue, r.NetIncrementalRevenue,
+			r.AnalysisDate, r.PeriodStart, r.PeriodEnd, r.RunID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamRetentionTriangle выгружает треугольник удержания в длинном формате: строка на когорту и смещение
+func (s *exportService) streamRetentionTriangle(ctx context.Context, request entities.ExportRequest, emit exportEmitFunc) error {
+	triangle, err := s.retentionService.BuildCohortTriangle(ctx, request.Period, request.StartDate, request.EndDate)
+	if err != nil {
+		return fmt.Errorf("failed to build retention triangle: %w", err)
+	}
+
+	for _, cohort := range triangle.Cohorts {
+		for offset, retained := range cohort.Retained {
+			if err := emit(string(triangle.Period), cohort.CohortStart, cohort.Size, offset, retained,
+				cohort.RetentionRates[offset]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// streamForecasts выгружает точки прогнозов, построенных после начала периода
+func (s *exportService) streamForecasts(ctx context.Context, request entities.ExportRequest, emit exportEmitFunc) error {
+	forecasts, err := s.forecastRepo.GetForecastsGeneratedAfter(ctx, request.Level, request.StartDate)
+	if err != nil {
+		return fmt.Errorf("failed to get forecasts: %w", err)
+	}
+
+	sort.Slice(forecasts, func(i, j int) bool {
+		if forecasts[i].TargetID != forecasts[j].TargetID {
+			return forecasts[i].TargetID < forecasts[j].TargetID
+		}
+		return forecasts[i].GeneratedAt.Before(forecasts[j].GeneratedAt)
+	})
+
+	for _, forecast := range forecasts {
+		for _, point := range forecast.Points {
+			if err := emit(forecast.ID, string(forecast.Level), forecast.TargetID, string(forecast.ModelType),
+				forecast.GeneratedAt, point.Date, point.Value, point.Lower, point.Upper); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// joinItemIDs объединяет ID товаров правила в одну ячейку
+func joinItemIDs(items []entities.Item) string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ProductID
+	}
+	return strings.Join(ids, exportListSeparator)
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/pkg/stats"
+)
+
+// Сетка параметров сглаживания для подбора Holt-Winters
+var (
+	holtWintersAlphas = []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.7}
+	holtWintersBetas  = []float64{0.01, 0.05, 0.1, 0.2}
+	holtWintersGammas = []float64{0.05, 0.1, 0.2, 0.3, 0.5}
+)
+
+// demandModel определяет обученную модель дневного спроса
+type demandModel interface {
+	// forecast возвращает прогноз и границы интервала на horizon дней вперед
+	forecast(horizon int, z float64) (values, lower, upper []float64)
+}
+
+// holtWintersModel представляет аддитивную модель Holt-Winters
+type holtWintersModel struct {
+	alpha, beta, gamma float64
+	level, trend       float64
+	seasonal           []float64
+	seasonLength       int
+	observations       int
+	residualStdDev     float64
+}
+
+// seasonalNaiveModel повторяет значения последнего сезона
+type seasonalNaiveModel struct {
+	lastSeason     []float64
+	residualStdDev float64
+}
+
+// fitDemandModel обучает модель указанного типа на дневном ряде
+func fitDemandModel(modelType entities.ForecastModelType, series []float64, seasonLength int) (demandModel, error) {
+	if len(series) < 2*seasonLength {
+		return nil, fmt.Errorf("%w: need at least %d observations, got %d", ErrInsufficientData, 2*seasonLength, len(series))
+	}
+
+	switch modelType {
+	case entities.ForecastHoltWinters:
+		return fitHoltWinters(series, seasonLength), nil
+	case entities.ForecastSeasonalNaive:
+		return fitSeasonalNaive(series, seasonLength), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown forecast model %s", ErrInvalidParameter, modelType)
+	}
+}
+
+// fitHoltWinters подбирает параметры сглаживания по сетке, минимизируя ошибку прогноза на шаг вперед
+func fitHoltWinters(series []float64, seasonLength int) *holtWintersModel {
+	var best *holtWintersModel
+	bestSSE := math.Inf(1)
+
+	for _, alpha := range holtWintersAlphas {
+		for _, beta := range holtWintersBetas {
+			for _, gamma := range holtWintersGammas {
+				model, sse := runHoltWinters(series, seasonLength, alpha, beta, gamma)
+				if sse < bestSSE {
+					best = model
+					bestSSE = sse
+				}
+			}
+		}
+	}
+
+	return best
+}
+
+// runHoltWinters прогоняет сглаживание по ряду и возвращает модель и сумму квадратов ошибок
+// Первый сезон используется для начальных компонент и в ошибку не входит
+func runHoltWinters(series []float64, m int, alpha, beta, gamma float64) (*holtWintersModel, float64) {
+	firstMean := stats.Mean(series[:m])
+	secondMean := stats.Mean(series[m : 2*m])
+
+	model := &holtWintersModel{
+		alpha:        alpha,
+		beta:         beta,
+		gamma:        gamma,
+		level:        firstMean,
+		trend:        (secondMean - firstMean) / float64(m),
+		seasonal:     make([]float64, m),
+		seasonLength: m,
+		observations: len(series),
+	}
+	for i := 0; i < m; i++ {
+		model.seasonal[i] = series[i] - firstMean
+	}
+
+	sse := 0.0
+	for t := m; t < len(series); t++ {
+		season := t % m
+		predicted := model.level + model.trend + model.seasonal[season]
+		residual := series[t] - predicted
+		sse += residual * residual
+
+		previousLevel := model.level
+		model.level = alpha*(series[t]-model.seasonal[season]) + (1-alpha)*(model.level+model.trend)
+		model.trend = beta*(model.level-previousLevel) + (1-beta)*model.trend
+		model.seasonal[season] = gamma*(series[t]-model.level) + (1-gamma)*model.seasonal[season]
+	}
+
+	model.residualStdDev = math.Sqrt(sse / float64(len(series)-m))
+	return model, sse
+}
+
+// forecast возвращает прогноз Holt-Winters с интервалами аддитивной модели ETS(A,A,A)
+func (m *holtWintersModel) forecast(horizon int, z float64) ([]float64, []float64, []float64) {
+	values := make([]float64, horizon)
+	lower := make([]float64, horizon)
+	upper := make([]float64, horizon)
+
+	// Параметры в форме пространства состояний для дисперсии ошибки на h шагов
+	betaETS := m.alpha * m.beta
+	gammaETS := (1 - m.alpha) * m.gamma
+	variance := 0.0
+
+	for h := 1; h <= horizon; h++ {
+		if h > 1 {
+			j := h - 1
+			c := m.alpha + float64(j)*betaETS
+			if j%m.seasonLength == 0 {
+				c += gammaETS
+			}
+			variance += c * c
+		}
+
+		season := (m.observations + h - 1) % m.seasonLength
+		value := m.level + float64(h)*m.trend + m.seasonal[season]
+		spread := z * m.residualStdDev * math.Sqrt(1+variance)
+
+		values[h-1] = math.Max(value, 0)
+		lower[h-1] = math.Max(value-spread, 0)
+		upper[h-1] = math.Max(value+spread, 0)
+	}
+
+	return values, lower, upper
+}
+
+// fitSeasonalNaive строит сезонную наивную модель по последнему сезону ряда
+func fitSeasonalNaive(series []float64, seasonLength int) *seasonalNaiveModel {
+	residuals := make([]float64, 0, len(series)-seasonLength)
+	for t := seasonLength; t < len(series); t++ {
+		residuals = append(residuals, series[t]-series[t-seasonLength])
+	}
+
+	sumSquares := 0.0
+	for _, r := range residuals {
+		sumSquares += r * r
+	}
+
+	return &seasonalNaiveModel{
+		lastSeason:     append([]float64(nil), series[len(series)-seasonLength:]...),
+		residualStdDev: math.Sqrt(sumSquares / float64(len(residuals))),
+	}
+}
+
+// forecast возвращает сезонный наивный прогноз, дисперсия растет с числом пройденных сезонов
+func (m *seasonalNaiveModel) forecast(horizon int, z float64) ([]float64, []float64, []float64) {
+	values := make([]float64, horizon)
+	lower := make([]float64, horizon)
+	upper := make([]float64, horizon)
+	seasonLength := len(m.lastSeason)
+
+	for h := 1; h <= horizon; h++ {
+		value := m.lastSeason[(h-1)%seasonLength]
+		seasons := float64((h-1)/seasonLength + 1)
+		spread := z * m.residualStdDev * math.Sqrt(seasons)
+
+		values[h-1] = value
+		lower[h-1] = math.Max(value-spread, 0)
+		upper[h-1] = value + spread
+	}
+
+	return values, lower, upper
+}
+
+// backtestDemandModel оценивает модель скользящим бэктестом с началом прогноза,
+// сдвигающимся на горизонт от конца ряда назад
+func backtestDemandModel(modelType entities.ForecastModelType, series []float64, seasonLength, horizon, folds int) (entities.ForecastBacktest, error) {
+	result := entities.ForecastBacktest{ModelType: modelType}
+
+	var absPctSum, symPctSum float64
+	var absPctCount, symPctCount int
+
+	for fold := folds; fold >= 1; fold-- {
+		origin := len(series) - fold*horizon
+		if origin < 2*seasonLength {
+			continue
+		}
+
+		model, err := fitDemandModel(modelType, series[:origin], seasonLength)
+		if err != nil {
+			return result, err
+		}
+
+		predicted, _, _ := model.forecast(horizon, 0)
+		for h, value := range predicted {
+			actual := series[origin+h]
+			if actual != 0 {
+				absPctSum += math.Abs(actual-value) / math.Abs(actual)
+				absPctCount++
+			}
+			if denominator := math.Abs(actual) + math.Abs(value); denominator > 0 {
+				symPctSum += 2 * math.Abs(actual-value) / denominator
+			}
+			symPctCount++
+		}
+		result.Folds++
+	}
+
+	if result.Folds == 0 {
+		return result, fmt.Errorf("%w: history too short for backtesting", ErrInsufficientData)
+	}
+
+	if absPctCount > 0 {
+		result.MAPE = absPctSum / float64(absPctCount) * 100
+	}
+	result.SMAPE = symPctSum / float64(symPctCount) * 100
+
+	return result, nil
+}
+
+// backtestScore возвращает значение метрики, по которой выбирается модель
+func backtestScore(backtest entities.ForecastBacktest, metric entities.ForecastErrorMetric) float64 {
+	if metric == entities.ForecastMetricMAPE {
+		return backtest.MAPE
+	}
+	return backtest.SMAPE
+}
@@ -0,0 +1,159 @@
+// internal/infrastructure/services/forecast_models_internal_test.go
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// Недельный профиль спроса, используемый в тестовых рядах
+var testWeeklyProfile = []float64{-20, -10, 0, 5, 10, 25, -10}
+
+// testDemandSeries возвращает ряд из days дней с недельной сезонностью и линейным трендом
+func testDemandSeries(days int, trend float64) []float64 {
+	series := make([]float64, days)
+	for t := range series {
+		series[t] = 100 + trend*float64(t) + testWeeklyProfile[t%7]
+	}
+	return series
+}
+
+func TestHoltWintersForecast(t *testing.T) {
+	tests := []struct {
+		name  string
+		trend float64
+	}{
+		{name: "flat seasonal", trend: 0},
+		{name: "growing seasonal", trend: 0.5},
+		{name: "declining seasonal", trend: -0.3},
+	}
+
+	const days, horizon = 84, 14
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := testDemandSeries(days+horizon, tt.trend)
+			model, err := fitDemandModel(entities.ForecastHoltWinters, series[:days], 7)
+			if err != nil {
+				t.Fatalf("fitDemandModel() error = %v", err)
+			}
+
+			values, lower, upper := model.forecast(horizon, 1.96)
+			for h, value := range values {
+				actual := series[days+h]
+				if math.Abs(value-actual) > 0.03*actual {
+					t.Errorf("forecast day %d = %.2f, want %.2f", h+1, value, actual)
+				}
+				if lower[h] > value || upper[h] < value {
+					t.Errorf("day %d interval [%.2f, %.2f] does not contain forecast %.2f", h+1, lower[h], upper[h], value)
+				}
+				if h > 0 && upper[h]-lower[h] < upper[h-1]-lower[h-1]-1e-9 {
+					t.Errorf("interval narrows from day %d to day %d", h, h+1)
+				}
+			}
+		})
+	}
+}
+
+func TestSeasonalNaiveForecast(t *testing.T) {
+	// Ряд из двух недель: вторая неделя выше первой на 7 в каждый день, остатки постоянны
+	series := append(testDemandSeries(7, 0), testDemandSeries(7, 0)...)
+	for t := 7; t < 14; t++ {
+		series[t] += 7
+	}
+
+	model, err := fitDemandModel(entities.ForecastSeasonalNaive, series, 7)
+	if err != nil {
+		t.Fatalf("fitDemandModel() error = %v", err)
+	}
+
+	values, lower, upper := model.forecast(10, 1)
+	tests := []struct {
+		name       string
+		day        int
+		wantValue  float64
+		wantSpread float64
+	}{
+		{name: "first day", day: 1, wantValue: series[7], wantSpread: 7},
+		{name: "end of first season", day: 7, wantValue: series[13], wantSpread: 7},
+		{name: "second season", day: 8, wantValue: series[7], wantSpread: 7 * math.Sqrt2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.day - 1
+			if values[h] != tt.wantValue {
+				t.Errorf("forecast = %.2f, want %.2f", values[h], tt.wantValue)
+			}
+			if spread := upper[h] - values[h]; math.Abs(spread-tt.wantSpread) > 1e-9 {
+				t.Errorf("upper spread = %.4f, want %.4f", spread, tt.wantSpread)
+			}
+			if lower[h] != math.Max(values[h]-tt.wantSpread, 0) {
+				t.Errorf("lower = %.4f, want %.4f", lower[h], values[h]-tt.wantSpread)
+			}
+		})
+	}
+}
+
+func TestFitDemandModelErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		modelType entities.ForecastModelType
+		days      int
+		want      error
+	}{
+		{name: "shorter than two seasons", modelType: entities.ForecastHoltWinters, days: 13, want: ErrInsufficientData},
+		{name: "unknown model", modelType: "arima", days: 28, want: ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fitDemandModel(tt.modelType, testDemandSeries(tt.days, 0), 7)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("fitDemandModel() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBacktestDemandModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		modelType entities.ForecastModelType
+		days      int
+		folds     int
+		wantFolds int
+		wantErr   error
+		// Верхняя граница SMAPE в процентах
+		maxSMAPE float64
+	}{
+		// Без тренда сезонный наивный прогноз повторяет ряд точно
+		{name: "seasonal naive exact", modelType: entities.ForecastSeasonalNaive, days: 56, folds: 3, wantFolds: 3, maxSMAPE: 1e-9},
+		{name: "holt-winters", modelType: entities.ForecastHoltWinters, days: 56, folds: 3, wantFolds: 3, maxSMAPE: 3},
+		// Начало первого фолда приходится на 7-й день, меньше двух сезонов, поэтому он пропускается
+		{name: "short folds skipped", modelType: entities.ForecastSeasonalNaive, days: 28, folds: 3, wantFolds: 2, maxSMAPE: 1e-9},
+		{name: "history too short", modelType: entities.ForecastSeasonalNaive, days: 20, folds: 2, wantErr: ErrInsufficientData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backtest, err := backtestDemandModel(tt.modelType, testDemandSeries(tt.days, 0), 7, 7, tt.folds)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("backtestDemandModel() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("backtestDemandModel() error = %v", err)
+			}
+			if backtest.Folds != tt.wantFolds {
+				t.Errorf("folds = %d, want %d", backtest.Folds, tt.wantFolds)
+			}
+			if backtest.SMAPE > tt.maxSMAPE {
+				t.Errorf("SMAPE = %.4f, want at most %.4f", backtest.SMAPE, tt.maxSMAPE)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+	"analitics-service/pkg/stats"
+)
+
+// ForecastService определяет интерфейс прогнозирования дневного спроса
+type ForecastService interface {
+	// ForecastProduct строит и сохраняет прогноз спроса на товар
+	ForecastProduct(ctx context.Context, productID string, config entities.ForecastConfig) (*entities.DemandForecast, error)
+
+	// ForecastCategory строит и сохраняет прогноз спроса на категорию
+	ForecastCategory(ctx context.Context, category string, config entities.ForecastConfig) (*entities.DemandForecast, error)
+
+	// RunForecasts строит прогнозы для всех товаров и категорий с достаточной историей
+	RunForecasts(ctx context.Context, config entities.ForecastConfig) ([]entities.DemandForecast, error)
+
+	// GetLatestForecast возвращает последний сохраненный прогноз
+	GetLatestForecast(ctx context.Context, level entities.ForecastLevel, targetID string) (*entities.DemandForecast, error)
+}
+
+// forecastService реализует интерфейс ForecastService
+type forecastService struct {
+	salesRepo    repositories.SalesRepository
+	productRepo  repositories.ProductRepository
+	forecastRepo repositories.ForecastRepository
+	logger       logger.Logger
+}
+
+// NewForecastService создает новый экземпляр сервиса прогнозирования спроса
+func NewForecastService(
+	salesRepo repositories.SalesRepository,
+	productRepo repositories.ProductRepository,
+	forecastRepo repositories.ForecastRepository,
+	logger logger.Logger,
+) ForecastService {
+	return &forecastService{
+		salesRepo:    salesRepo,
+		productRepo:  productRepo,
+		forecastRepo: forecastRepo,
+		logger:       logger,
+	}
+}
+
+// ForecastProduct строит и сохраняет прогноз спроса на товар
+func (s *forecastService) ForecastProduct(ctx context.Context, productID string, config entities.ForecastConfig) (*entities.DemandForecast, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	historyStart, historyEnd := forecastHistoryWindow(config)
+	sales, err := s.salesRepo.GetSalesByProductID(ctx, productID, historyStart, historyEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales for product %s: %w", productID, err)
+	}
+
+	series := dailyUnitSeries(sales, historyStart, historyEnd, func(entities.Sale) (string, bool) { return productID, true })
+	return s.buildAndSave(ctx, entities.ForecastLevelProduct, productID, series[productID], historyStart, historyEnd, config)
+}
+
+// ForecastCategory строит и сохраняет прогноз спроса на категорию
+func (s *forecastService) ForecastCategory(ctx context.Context, category string, config entities.ForecastConfig) (*entities.DemandForecast, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	categories, err := s.productCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	historyStart, historyEnd := forecastHistoryWindow(config)
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, historyStart, historyEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	series := dailyUnitSeries(sales, historyStart, historyEnd, func(sale entities.Sale) (string, bool) {
+		return category, categories[sale.ProductID] == category
+	})
+	return s.buildAndSave(ctx, entities.ForecastLevelCategory, category, series[category], historyStart, historyEnd, config)
+}
+
+// RunForecasts строит прогнозы для всех товаров и категорий с достаточной историей
+func (s *forecastService) RunForecasts(ctx context.Context, config entities.ForecastConfig) ([]entities.DemandForecast, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	categories, err := s.productCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	historyStart, historyEnd := forecastHistoryWindow(config)
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, historyStart, historyEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	productSeries := dailyUnitSeries(sales, historyStart, historyEnd, func(sale entities.Sale) (string, bool) {
+		return sale.ProductID, true
+	})
+	categorySeries := dailyUnitSeries(sales, historyStart, historyEnd, func(sale entities.Sale) (string, bool) {
+		category, ok := categories[sale.ProductID]
+		return category, ok && category != ""
+	})
+
+	var forecasts []entities.DemandForecast
+	run := func(level entities.ForecastLevel, seriesByTarget map[string][]float64) error {
+		targets := make([]string, 0, len(seriesByTarget))
+		for target := range seriesByTarget {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+
+		for _, target := range targets {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			forecast, err := s.buildAndSave(ctx, level, target, seriesByTarget[target], historyStart, historyEnd, config)
+			if errors.Is(err, ErrInsufficientData) {
+				s.logger.Debug(ctx, "Недостаточно истории для прогноза", "level", level, "target", target)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			forecasts = append(forecasts, *forecast)
+		}
+		return nil
+	}
+
+	if err := run(entities.ForecastLevelProduct, productSeries); err != nil {
+		return forecasts, err
+	}
+	if err := run(entities.ForecastLevelCategory, categorySeries); err != nil {
+		return forecasts, err
+	}
+
+	s.logger.Info(ctx, "Прогнозы спроса построены", "count", len(forecasts))
+	return forecasts, nil
+}
+
+// GetLatestForecast возвращает последний сохраненный прогноз
+func (s *forecastService) GetLatestForecast(ctx context.Context, level entities.ForecastLevel, targetID string) (*entities.DemandForecast, error) {
+	if !level.IsValid() {
+		return nil, fmt.Errorf("%w: invalid forecast level %s", ErrInvalidParameter, level)
+	}
+
+	forecast, err := s.forecastRepo.GetLatestForecast(ctx, level, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve forecast for %s %s: %w", level, targetID, err)
+	}
+
+	return &forecast, nil
+}
+
+// buildAndSave выбирает модель по бэктесту, строит прогноз и сохраняет его
+func (s *forecastService) buildAndSave(
+	ctx context.Context,
+	level entities.ForecastLevel,
+	targetID string,
+	series []float64,
+	historyStart, historyEnd time.Time,
+	config entities.ForecastConfig,
+) (*entities.DemandForecast, error) {
+	// История начинается с первой продажи, чтобы не учитывать дни до появления товара
+	first := 0
+	for first < len(series) && series[first] == 0 {
+		first++
+	}
+	series = series[first:]
+	if len(series) < 2*config.SeasonLength+config.HorizonDays {
+		return nil, fmt.Errorf("%w: %s %s has %d days of history", ErrInsufficientData, level, targetID, len(series))
+	}
+
+	candidates := []entities.ForecastModelType{entities.ForecastHoltWinters, entities.ForecastSeasonalNaive}
+	backtests := make([]entities.ForecastBacktest, 0, len(candidates))
+	selected := -1
+	for _, modelType := range candidates {
+		backtest, err := backtestDemandModel(modelType, series, config.SeasonLength, config.HorizonDays, config.BacktestFolds)
+		if err != nil {
+			return nil, err
+		}
+		backtests = append(backtests, backtest)
+		if selected < 0 || backtestScore(backtest, config.SelectionMetric) < backtestScore(backtests[selected], config.SelectionMetric) {
+			selected = len(backtests) - 1
+		}
+	}
+
+	modelType := backtests[selected].ModelType
+	model, err := fitDemandModel(modelType, series, config.SeasonLength)
+	if err != nil {
+		return nil, err
+	}
+
+	z := stats.NormalQuantile(1 - (1-config.ConfidenceLevel)/2)
+	values, lower, upper := model.forecast(config.HorizonDays, z)
+
+	now := time.Now()
+	forecast := &entities.DemandForecast{
+		ID:           fmt.Sprintf("%s:%s:%d", level, targetID, now.Unix()),
+		Level:        level,
+		TargetID:     targetID,
+		ModelType:    modelType,
+		SeasonLength: config.SeasonLength,
+		HistoryStart: historyStart.AddDate(0, 0, first),
+		HistoryEnd:   historyEnd,
+		GeneratedAt:  now,
+		Points:       make([]entities.ForecastPoint, config.HorizonDays),
+		Backtests:    backtests,
+	}
+
+	if hw, ok := model.(*holtWintersModel); ok {
+		forecast.Alpha, forecast.Beta, forecast.Gamma = hw.alpha, hw.beta, hw.gamma
+	}
+
+	for h := range values {
+		forecast.Points[h] = entities.ForecastPoint{
+			Date:  historyEnd.AddDate(0, 0, h),
+			Value: roundTo(values[h], 2),
+			Lower: roundTo(lower[h], 2),
+			Upper: roundTo(upper[h], 2),
+		}
+	}
+
+	if err := s.forecastRepo.SaveForecast(ctx, *forecast); err != nil {
+		return nil, fmt.Errorf("failed to save forecast: %w", err)
+	}
+
+	s.logger.Debug(ctx, "Прогноз спроса построен", "level", level, "target", targetID,
+		"model", modelType, "score", backtestScore(backtests[selected], config.SelectionMetric))
+	return forecast, nil
+}
+
+// productCategories возвращает категории товаров по их ID
+func (s *forecastService) productCategories(ctx context.Context) (map[string]string, error) {
+	products, err := s.productRepo.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+
+	categories := make(map[string]string, len(products))
+	for _, product := range products {
+		categories[product.ID] = product.Category
+	}
+	return categories, nil
+}
+
+// forecastHistoryWindow возвращает период истории, заканчивающийся началом текущего дня
+func forecastHistoryWindow(config entities.ForecastConfig) (time.Time, time.Time) {
+	now := time.Now().UTC()
+	historyEnd := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return historyEnd.AddDate(0, 0, -config.HistoryDays), historyEnd
+}
+
+// dailyUnitSeries строит плотные ряды дневных продаж в штуках по ключу, возвращаемому keyOf
+func dailyUnitSeries(sales []entities.Sale, start, end time.Time, keyOf func(entities.Sale) (string, bool)) map[string][]float64 {
+	days := int(end.Sub(start).Hours() / 24)
+	series := make(map[string][]float64)
+
+	for _, sale := range sales {
+		key, ok := keyOf(sale)
+		if !ok {
+			continue
+		}
+		day := int(sale.PurchaseDate.UTC().Sub(start).Hours() / 24)
+		if day < 0 || day >= days {
+			continue
+		}
+		if series[key] == nil {
+			series[key] = make([]float64, days)
+		}
+		series[key][day] += float64(sale.Quantity)
+	}
+
+	return series
+}
+
+// roundTo округляет значение до указанного числа знаков после запятой
+func roundTo(value float64, digits int) float64 {
+	factor := math.Pow(10, float64(digits))
+	return math.Round(value*factor) / factor
+}
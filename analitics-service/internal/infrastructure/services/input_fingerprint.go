@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// inputFingerprint накапливает количество строк и хэш входных данных анализа
+// Хэш не зависит от порядка, в котором репозиторий вернул строки
+type inputFingerprint struct {
+	counts  map[string]int
+	digests map[string][][sha256.Size]byte
+}
+
+// newInputFingerprint создает пустой отпечаток входных данных
+func newInputFingerprint() *inputFingerprint {
+	return &inputFingerprint{
+		counts:  make(map[string]int),
+		digests: make(map[string][][sha256.Size]byte),
+	}
+}
+
+// addFingerprintRows добавляет строки источника данных в отпечаток
+func addFingerprintRows[T any](fp *inputFingerprint, source string, rows []T) error {
+	for _, row := range rows {
+		encoded, err := json.Marshal(row)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s row: %w", source, err)
+		}
+		fp.digests[source] = append(fp.digests[source], sha256.Sum256(encoded))
+	}
+	fp.counts[source] += len(rows)
+	return nil
+}
+
+// rowCounts возвращает количество строк по источникам данных
+func (fp *inputFingerprint) rowCounts() map[string]int {
+	counts := make(map[string]int, len(fp.counts))
+	for source, count := range fp.counts {
+		counts[source] = count
+	}
+	return counts
+}
+
+// hash возвращает SHA-256 по источникам в алфавитном порядке и отсортированным хэшам их строк
+func (fp *inputFingerprint) hash() string {
+	sources := make([]string, 0, len(fp.counts))
+	for source := range fp.counts {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	h := sha256.New()
+	for _, source := range sources {
+		digests := fp.digests[source]
+		sort.Slice(digests, func(i, j int) bool {
+			return bytes.Compare(digests[i][:], digests[j][:]) < 0
+		})
+
+		fmt.Fprintf(h, "%s:%d\n", source, fp.counts[source])
+		for _, digest := range digests {
+			h.Write(digest[:])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
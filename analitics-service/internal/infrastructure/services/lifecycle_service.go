@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+	"analitics-service/pkg/stats"
+)
+
+const (
+	// lifecycleLevelWeeks окно скользящего среднего для текущего и пикового уровня продаж
+	lifecycleLevelWeeks = 4
+	// seasonalLagWeeks лаг автокорреляции для признака годовой сезонности
+	seasonalLagWeeks = 52
+	// maxTrendTStat ограничение t-статистики тренда, в том числе для рядов, идеально лежащих на прямой
+	maxTrendTStat = 100
+)
+
+// lifecycleStageOrder порядок стадий для выбора преобладающей стадии категории при равенстве
+var lifecycleStageOrder = []entities.LifecycleStage{
+	entities.LifecycleDeclining,
+	entities.LifecycleDormant,
+	entities.LifecycleMature,
+	entities.LifecycleGrowing,
+	entities.LifecycleNew,
+}
+
+// LifecycleService определяет интерфейс классификации жизненного цикла товаров
+type LifecycleService interface {
+	// ClassifyProducts оценивает тренд недельных продаж каждого товара с момента первой продажи,
+	// присваивает стадию жизненного цикла, отмечает кандидатов на вывод из ассортимента и сохраняет результат
+	// Анализируются только полные недели до asOf
+	ClassifyProducts(ctx context.Context, asOf time.Time, config entities.LifecycleConfig) ([]entities.ProductLifecycle, error)
+
+	// GetLifecycles возвращает результаты последней классификации, пустая стадия — все товары
+	GetLifecycles(ctx context.Context, stage entities.LifecycleStage) ([]entities.ProductLifecycle, error)
+
+	// GetDelistingCandidates возвращает кандидатов на вывод из ассортимента по последней классификации
+	GetDelistingCandidates(ctx context.Context) ([]entities.ProductLifecycle, error)
+}
+
+// lifecycleService реализует интерфейс LifecycleService
+type lifecycleService struct {
+	productRepo   repositories.ProductRepository
+	salesRepo     repositories.SalesRepository
+	segmentRepo   repositories.ABCSegmentRepository
+	lifecycleRepo repositories.ProductLifecycleRepository
+	logger        logger.Logger
+}
+
+// NewLifecycleService создает новый экземпляр сервиса жизненного цикла товаров
+func NewLifecycleService(
+	productRepo repositories.ProductRepository,
+	salesRepo repositories.SalesRepository,
+	segmentRepo repositories.ABCSegmentRepository,
+	lifecycleRepo repositories.ProductLifecycleRepository,
+	logger logger.Logger,
+) LifecycleService {
+	return &lifecycleService{
+		productRepo:   productRepo,
+		salesRepo:     salesRepo,
+		segmentRepo:   segmentRepo,
+		lifecycleRepo: lifecycleRepo,
+		logger:        logger,
+	}
+}
+
+// ClassifyProducts классифицирует товары по стадиям жизненного цикла
+func (s *lifecycleService) ClassifyProducts(ctx context.Context, asOf time.Time, config entities.LifecycleConfig) ([]entities.ProductLifecycle, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	endDate := entities.Weekly.Truncate(asOf)
+	startDate := entities.Weekly.Advance(endDate, -config.HistoryWeeks)
+
+	products, err := s.productRepo.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+	if len(sales) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	segmentation, err := s.segmentRepo.GetFullSegmentation(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ABC segmentation: %w", err)
+	}
+
+	weekly := make(map[string][]float64)
+	firstSale := make(map[string]time.Time)
+	lastSale := make(map[string]time.Time)
+	for _, sale := range sales {
+		week := int(math.Round(entities.Weekly.Truncate(sale.PurchaseDate).Sub(startDate).Hours()/24)) / 7
+		if week < 0 || week >= config.HistoryWeeks || sale.Quantity <= 0 {
+			continue
+		}
+		series, ok := weekly[sale.ProductID]
+		if !ok {
+			series = make([]float64, config.HistoryWeeks)
+			weekly[sale.ProductID] = series
+		}
+		series[week] += float64(sale.Quantity)
+
+		if first, ok := firstSale[sale.ProductID]; !ok || sale.PurchaseDate.Before(first) {
+			firstSale[sale.ProductID] = sale.PurchaseDate
+		}
+		if sale.PurchaseDate.After(lastSale[sale.ProductID]) {
+			lastSale[sale.ProductID] = sale.PurchaseDate
+		}
+	}
+
+	now := time.Now()
+	lifecycles := make([]entities.ProductLifecycle, 0, len(products))
+	for _, product := range products {
+		series, sold := weekly[product.ID]
+		if !sold && !product.IsActive {
+			continue
+		}
+
+		lifecycle := entities.ProductLifecycle{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			Category:     product.Category,
+			ABCCategory:  segmentation[product.ID].FinalSegment,
+			AnalysisDate: now,
+		}
+
+		if !sold {
+			// Активный товар без продаж: новый, если заведен недавно, иначе неактивный
+			lifecycle.Stage = entities.LifecycleDormant
+			if product.CreatedAt.After(entities.Weekly.Advance(endDate, -config.NewWeeks)) {
+				lifecycle.Stage = entities.LifecycleNew
+			}
+		} else {
+			lifecycle.FirstSaleDate = firstSale[product.ID]
+			lifecycle.LastSaleDate = lastSale[product.ID]
+			classifyLifecycle(&lifecycle, series, config)
+		}
+
+		markDelistingCandidate(&lifecycle, config)
+		lifecycles = append(lifecycles, lifecycle)
+	}
+
+	sort.Slice(lifecycles, func(i, j int) bool {
+		return lifecycles[i].ProductID < lifecycles[j].ProductID
+	})
+
+	if err := s.lifecycleRepo.SaveLifecycles(ctx, lifecycles); err != nil {
+		return nil, fmt.Errorf("failed to save lifecycles: %w", err)
+	}
+
+	candidates := 0
+	for _, lifecycle := range lifecycles {
+		if lifecycle.DelistingCandidate {
+			candidates++
+		}
+	}
+	s.logger.Info(ctx, "Классифицирован жизненный цикл товаров", "products", len(lifecycles),
+		"delisting_candidates", candidates)
+	return lifecycles, nil
+}
+
+// GetLifecycles возвращает результаты последней классификации
+func (s *lifecycleService) GetLifecycles(ctx context.Context, stage entities.LifecycleStage) ([]entities.ProductLifecycle, error) {
+	if stage == "" {
+		lifecycles, err := s.lifecycleRepo.GetLatestLifecycles(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lifecycles: %w", err)
+		}
+		return lifecycles, nil
+	}
+
+	if !stage.IsValid() {
+		return nil, fmt.Errorf("%w: unknown lifecycle stage %q", ErrInvalidParameter, stage)
+	}
+
+	lifecycles, err := s.lifecycleRepo.GetLifecyclesByStage(ctx, stage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lifecycles: %w", err)
+	}
+	return lifecycles, nil
+}
+
+// GetDelistingCandidates возвращает кандидатов на вывод из ассортимента
+func (s *lifecycleService) GetDelistingCandidates(ctx context.Context) ([]entities.ProductLifecycle, error) {
+	candidates, err := s.lifecycleRepo.GetDelistingCandidates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delisting candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// classifyLifecycle определяет стадию по недельному ряду продаж окна истории
+// Тренд оценивается как наклон log(1 + продажи) по последним неделям, темп роста — exp(наклон) − 1
+func classifyLifecycle(lifecycle *entities.ProductLifecycle, series []float64, config entities.LifecycleConfig) {
+	first, last := -1, -1
+	for week, units := range series {
+		if units > 0 {
+			if first < 0 {
+				first = week
+			}
+			last = week
+		}
+	}
+
+	history := series[first:]
+	lifecycle.WeeksSinceFirstSale = len(history)
+	lifecycle.RecentWeeklyUnits = stats.Mean(history[max(0, len(history)-lifecycleLevelWeeks):])
+	for end := min(lifecycleLevelWeeks, len(history)); end <= len(history); end++ {
+		lifecycle.PeakWeeklyUnits = math.Max(lifecycle.PeakWeeklyUnits, stats.Mean(history[max(0, end-lifecycleLevelWeeks):end]))
+	}
+	if len(history) >= seasonalLagWeeks+config.TrendWeeks {
+		lifecycle.Seasonal = lagAutocorrelation(history, seasonalLagWeeks) >= config.SeasonalCorrelation
+	}
+
+	window := history[max(0, len(history)-config.TrendWeeks):]
+	if len(window) >= 3 {
+		x := make([]float64, len(window))
+		y := make([]float64, len(window))
+		for i, units := range window {
+			x[i] = float64(i)
+			y[i] = math.Log1p(units)
+		}
+		slope, tStat := trendSlope(x, y)
+		lifecycle.WeeklyGrowthRate = roundTo(math.Expm1(slope), 4)
+		lifecycle.TrendTStat = roundTo(tStat, 2)
+	}
+
+	switch {
+	case len(series)-1-last >= config.DormantWeeks:
+		lifecycle.Stage = entities.LifecycleDormant
+	case len(history) < config.NewWeeks:
+		lifecycle.Stage = entities.LifecycleNew
+	case lifecycle.WeeklyGrowthRate >= config.GrowthRate && lifecycle.TrendTStat >= config.MinTStat:
+		lifecycle.Stage = entities.LifecycleGrowing
+	case lifecycle.WeeklyGrowthRate <= -config.DeclineRate && lifecycle.TrendTStat <= -config.MinTStat:
+		lifecycle.Stage = entities.LifecycleDeclining
+	default:
+		lifecycle.Stage = entities.LifecycleMature
+	}
+}
+
+// markDelistingCandidate отмечает товары категории C на спаде или без продаж
+// Сезонные товары без продаж не отмечаются: для них это межсезонье
+func markDelistingCandidate(lifecycle *entities.ProductLifecycle, config entities.LifecycleConfig) {
+	if lifecycle.ABCCategory != entities.SegmentC {
+		return
+	}
+
+	switch {
+	case lifecycle.Stage == entities.LifecycleDeclining:
+		lifecycle.DelistingCandidate = true
+		lifecycle.DelistingReason = fmt.Sprintf("Товар категории C, продажи снижаются на %.1f%% в неделю",
+			-lifecycle.WeeklyGrowthRate*100)
+	case lifecycle.Stage == entities.LifecycleDormant && !lifecycle.Seasonal:
+		lifecycle.DelistingCandidate = true
+		lifecycle.DelistingReason = fmt.Sprintf("Товар категории C без продаж не менее %d недель", config.DormantWeeks)
+	}
+}
+
+// trendSlope возвращает наклон линейного тренда и его t-статистику
+func trendSlope(x, y []float64) (float64, float64) {
+	_, slope, rSquared := simpleLinearFit(x, y)
+
+	meanX := stats.Mean(x)
+	var sxx float64
+	for _, value := range x {
+		sxx += (value - meanX) * (value - meanX)
+	}
+	syy := stats.Variance(y) * float64(len(y)-1)
+
+	if slope == 0 {
+		return 0, 0
+	}
+
+	residualVariance := syy * (1 - rSquared) / float64(len(y)-2)
+	if residualVariance <= 0 {
+		return slope, math.Copysign(maxTrendTStat, slope)
+	}
+	tStat := slope / math.Sqrt(residualVariance/sxx)
+	return slope, math.Max(-maxTrendTStat, math.Min(maxTrendTStat, tStat))
+}
+
+// lagAutocorrelation возвращает автокорреляцию ряда с указанным лагом
+func lagAutocorrelation(series []float64, lag int) float64 {
+	mean := stats.Mean(series)
+	var numerator, denominator float64
+	for i, value := range series {
+		denominator += (value - mean) * (value - mean)
+		if i >= lag {
+			numerator += (value - mean) * (series[i-lag] - mean)
+		}
+	}
+	if denominator == 0 {
+		return 0
+	}
+	return numerator / denominator
+}
+
+// dominantLifecycleStage возвращает стадию, к которой относится большинство товаров категории
+func dominantLifecycleStage(lifecycles []entities.ProductLifecycle, category string) (entities.LifecycleStage, bool) {
+	counts := make(map[entities.LifecycleStage]int)
+	for _, lifecycle := range lifecycles {
+		if lifecycle.Category == category {
+			counts[lifecycle.Stage]++
+		}
+	}
+
+	var dominant entities.LifecycleStage
+	for _, stage := range lifecycleStageOrder {
+		if counts[stage] > counts[dominant] {
+			dominant = stage
+		}
+	}
+	return dominant, dominant != ""
+}
@@ -0,0 +1,252 @@
+// internal/infrastructure/services/lifecycle_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryLifecycleRepository хранит результаты последней классификации в памяти
+type memoryLifecycleRepository struct {
+	lifecycles []entities.ProductLifecycle
+}
+
+func (r *memoryLifecycleRepository) SaveLifecycles(_ context.Context, lifecycles []entities.ProductLifecycle) error {
+	r.lifecycles = lifecycles
+	return nil
+}
+
+func (r *memoryLifecycleRepository) GetLatestLifecycles(_ context.Context) ([]entities.ProductLifecycle, error) {
+	return r.lifecycles, nil
+}
+
+func (r *memoryLifecycleRepository) GetLifecyclesByStage(_ context.Context, stage entities.LifecycleStage) ([]entities.ProductLifecycle, error) {
+	var result []entities.ProductLifecycle
+	for _, lifecycle := range r.lifecycles {
+		if lifecycle.Stage == stage {
+			result = append(result, lifecycle)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryLifecycleRepository) GetDelistingCandidates(_ context.Context) ([]entities.ProductLifecycle, error) {
+	var result []entities.ProductLifecycle
+	for _, lifecycle := range r.lifecycles {
+		if lifecycle.DelistingCandidate {
+			result = append(result, lifecycle)
+		}
+	}
+	return result, nil
+}
+
+// testLifecycleConfig возвращает конфигурацию с полугодовой историей, при которой сезонность не оценивается
+func testLifecycleConfig() entities.LifecycleConfig {
+	config := entities.DefaultLifecycleConfig()
+	config.HistoryWeeks = 26
+	return config
+}
+
+// testWeeklySales возвращает продажи товара по средам недель окна истории, начиная с недели from
+func testWeeklySales(productID string, historyStart time.Time, from int, units ...int) []entities.Sale {
+	var sales []entities.Sale
+	for i, quantity := range units {
+		at := historyStart.AddDate(0, 0, 7*(from+i)+2).Add(12 * time.Hour)
+		sales = append(sales, testSale(productID, quantity, 10, 0, at))
+	}
+	return sales
+}
+
+func TestClassifyProducts(t *testing.T) {
+	// Понедельник: анализируются 26 полных недель до него
+	asOf := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	historyStart := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -7*26)
+
+	growingUnits := make([]int, 26)
+	decliningUnits := make([]int, 26)
+	stableUnits := make([]int, 26)
+	for week := range 26 {
+		growingUnits[week] = int(math.Round(10 * math.Pow(1.05, float64(week))))
+		decliningUnits[week] = int(math.Round(100 * math.Pow(0.9, float64(week))))
+		stableUnits[week] = 20
+	}
+	stoppedUnits := make([]int, 16)
+	for week := range stoppedUnits {
+		stoppedUnits[week] = 10
+	}
+
+	var sales []entities.Sale
+	sales = append(sales, testWeeklySales("GROW", historyStart, 0, growingUnits...)...)
+	sales = append(sales, testWeeklySales("STABLE", historyStart, 0, stableUnits...)...)
+	sales = append(sales, testWeeklySales("DECL_A", historyStart, 0, decliningUnits...)...)
+	sales = append(sales, testWeeklySales("DECL_C", historyStart, 0, decliningUnits...)...)
+	sales = append(sales, testWeeklySales("LAUNCH", historyStart, 22, 5, 5, 5, 5)...)
+	sales = append(sales, testWeeklySales("STOPPED", historyStart, 0, stoppedUnits...)...)
+	// Продажа в неделю asOf не входит в анализ, так как неделя еще не завершена
+	sales = append(sales, testSale("STOPPED", 50, 10, 0, asOf))
+
+	products := []entities.Product{
+		testProduct("DECL_A", "dairy", 10),
+		testProduct("DECL_C", "dairy", 10),
+		testProduct("FRESH", "dairy", 10),
+		testProduct("GROW", "dairy", 10),
+		testProduct("IDLE", "dairy", 10),
+		testProduct("LAUNCH", "dairy", 10),
+		testProduct("RETIRED", "dairy", 10),
+		testProduct("STABLE", "dairy", 10),
+		testProduct("STOPPED", "dairy", 10),
+	}
+	products[2].CreatedAt = asOf.AddDate(0, 0, -7)
+	products[4].CreatedAt = asOf.AddDate(-1, 0, 0)
+	products[6].IsActive = false
+
+	segments := map[string]entities.Segment{
+		"DECL_A":  entities.SegmentA,
+		"DECL_C":  entities.SegmentC,
+		"IDLE":    entities.SegmentC,
+		"LAUNCH":  entities.SegmentC,
+		"STOPPED": entities.SegmentC,
+	}
+
+	repo := &memoryLifecycleRepository{}
+	service := services.NewLifecycleService(&memoryProductRepository{products: products}, &memorySalesRepository{sales: sales},
+		&memorySegmentRepository{segments: segments}, repo, logger.NewLogger("ERROR"))
+
+	lifecycles, err := service.ClassifyProducts(context.Background(), asOf, testLifecycleConfig())
+	if err != nil {
+		t.Fatalf("ClassifyProducts() error = %v", err)
+	}
+
+	tests := []struct {
+		productID     string
+		wantStage     entities.LifecycleStage
+		wantCandidate bool
+		wantWeeks     int
+		wantRecent    float64
+		wantPeak      float64
+	}{
+		{productID: "DECL_A", wantStage: entities.LifecycleDeclining, wantWeeks: 26},
+		// Товар категории C на спаде предлагается к выводу
+		{productID: "DECL_C", wantStage: entities.LifecycleDeclining, wantCandidate: true, wantWeeks: 26},
+		// Активный товар без продаж, заведенный неделю назад, считается новым
+		{productID: "FRESH", wantStage: entities.LifecycleNew},
+		{productID: "GROW", wantStage: entities.LifecycleGrowing, wantWeeks: 26},
+		{productID: "IDLE", wantStage: entities.LifecycleDormant, wantCandidate: true},
+		// Четыре недели продаж меньше порога новизны, поэтому тренд не влияет на стадию
+		{productID: "LAUNCH", wantStage: entities.LifecycleNew, wantWeeks: 4, wantRecent: 5, wantPeak: 5},
+		{productID: "STABLE", wantStage: entities.LifecycleMature, wantWeeks: 26, wantRecent: 20, wantPeak: 20},
+		{productID: "STOPPED", wantStage: entities.LifecycleDormant, wantCandidate: true, wantWeeks: 26, wantPeak: 10},
+	}
+
+	// Неактивный товар без продаж не классифицируется
+	if len(lifecycles) != len(tests) {
+		t.Fatalf("lifecycles = %d, want %d", len(lifecycles), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.productID, func(t *testing.T) {
+			got := lifecycles[i]
+			if got.ProductID != tt.productID || got.Stage != tt.wantStage {
+				t.Fatalf("lifecycle = %s %s, want %s %s", got.ProductID, got.Stage, tt.productID, tt.wantStage)
+			}
+			if got.DelistingCandidate != tt.wantCandidate || (got.DelistingReason != "") != tt.wantCandidate {
+				t.Errorf("delisting = %v (%q), want %v", got.DelistingCandidate, got.DelistingReason, tt.wantCandidate)
+			}
+			if got.WeeksSinceFirstSale != tt.wantWeeks {
+				t.Errorf("weeks since first sale = %d, want %d", got.WeeksSinceFirstSale, tt.wantWeeks)
+			}
+			if tt.wantPeak > 0 && (got.RecentWeeklyUnits != tt.wantRecent || got.PeakWeeklyUnits != tt.wantPeak) {
+				t.Errorf("recent/peak units = %.2f/%.2f, want %.2f/%.2f", got.RecentWeeklyUnits, got.PeakWeeklyUnits,
+					tt.wantRecent, tt.wantPeak)
+			}
+			if got.Seasonal {
+				t.Errorf("seasonal = true, want false for history shorter than a year")
+			}
+		})
+	}
+
+	if got := lifecycles[3].WeeklyGrowthRate; got < 0.02 || lifecycles[3].TrendTStat < 2 {
+		t.Errorf("growing trend = %.4f (t = %.2f), want significant growth", got, lifecycles[3].TrendTStat)
+	}
+	if got := lifecycles[0].WeeklyGrowthRate; got > -0.02 || lifecycles[0].TrendTStat > -2 {
+		t.Errorf("declining trend = %.4f (t = %.2f), want significant decline", got, lifecycles[0].TrendTStat)
+	}
+	// Окно истории начинается 1 января, продажи STOPPED идут по средам первых 16 недель
+	stopped := lifecycles[7]
+	if !stopped.FirstSaleDate.Equal(time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)) ||
+		!stopped.LastSaleDate.Equal(time.Date(2024, 4, 17, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("sales range = %s..%s, want 2024-01-03..2024-04-17", stopped.FirstSaleDate, stopped.LastSaleDate)
+	}
+
+	candidates, err := service.GetDelistingCandidates(context.Background())
+	if err != nil {
+		t.Fatalf("GetDelistingCandidates() error = %v", err)
+	}
+	if len(candidates) != 3 || candidates[0].ProductID != "DECL_C" || candidates[1].ProductID != "IDLE" ||
+		candidates[2].ProductID != "STOPPED" {
+		t.Errorf("candidates = %+v, want DECL_C, IDLE, STOPPED", candidates)
+	}
+
+	declining, err := service.GetLifecycles(context.Background(), entities.LifecycleDeclining)
+	if err != nil {
+		t.Fatalf("GetLifecycles() error = %v", err)
+	}
+	if len(declining) != 2 {
+		t.Errorf("declining = %d, want 2", len(declining))
+	}
+	all, err := service.GetLifecycles(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetLifecycles() error = %v", err)
+	}
+	if len(all) != len(tests) {
+		t.Errorf("all lifecycles = %d, want %d", len(all), len(tests))
+	}
+}
+
+func TestLifecycleServiceErrors(t *testing.T) {
+	asOf := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	service := services.NewLifecycleService(&memoryProductRepository{products: []entities.Product{testProduct("P1", "dairy", 10)}},
+		&memorySalesRepository{}, &memorySegmentRepository{}, &memoryLifecycleRepository{}, logger.NewLogger("ERROR"))
+
+	shortTrend := testLifecycleConfig()
+	shortTrend.TrendWeeks = 3
+	shortHistory := testLifecycleConfig()
+	shortHistory.HistoryWeeks = 6
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{name: "short trend window", call: func() error {
+			_, err := service.ClassifyProducts(context.Background(), asOf, shortTrend)
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "history shorter than trend", call: func() error {
+			_, err := service.ClassifyProducts(context.Background(), asOf, shortHistory)
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "no sales", call: func() error {
+			_, err := service.ClassifyProducts(context.Background(), asOf, testLifecycleConfig())
+			return err
+		}, want: services.ErrInsufficientData},
+		{name: "unknown stage", call: func() error {
+			_, err := service.GetLifecycles(context.Background(), "retired")
+			return err
+		}, want: services.ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+)
+
+// MarginService определяет интерфейс аналитики маржи по истории себестоимости и цен товаров
+type MarginService interface {
+	// RecordCosts сохраняет изменения себестоимости и цен товаров с датами начала действия
+	RecordCosts(ctx context.Context, costs []entities.ProductCost) error
+
+	// GetCostHistory возвращает историю себестоимости и цен товара
+	GetCostHistory(ctx context.Context, productID string) ([]entities.ProductCost, error)
+
+	// BuildErosionReport строит отчет о марже, потерянной на скидках и росте себестоимости, по категориям за период
+	BuildErosionReport(ctx context.Context, startDate, endDate time.Time) (*entities.MarginErosionReport, error)
+}
+
+// marginService реализует интерфейс MarginService
+type marginService struct {
+	costRepo    repositories.ProductCostRepository
+	productRepo repositories.ProductRepository
+	salesRepo   repositories.SalesRepository
+	transactor  repositories.Transactor
+	logger      logger.Logger
+}
+
+// productCosts определяет себестоимость товара на дату продажи по истории себестоимости,
+// а для товаров без истории — по текущей себестоимости из каталога
+type productCosts struct {
+	history entities.CostHistory
+	current map[string]float64
+}
+
+// marginErosionTotals накапливает выручку и себестоимость продаж категории
+// costed* и себестоимость учитывают только продажи товаров с известной себестоимостью,
+// list* — только продажи товаров с историей цен
+type marginErosionTotals struct {
+	units         int
+	gross         float64
+	net           float64
+	costedGross   float64
+	costedNet     float64
+	cost          float64
+	baseCost      float64
+	listPrice     float64
+	baseListPrice float64
+}
+
+// NewMarginService создает новый экземпляр сервиса аналитики маржи
+func NewMarginService(
+	costRepo repositories.ProductCostRepository,
+	productRepo repositories.ProductRepository,
+	salesRepo repositories.SalesRepository,
+	transactor repositories.Transactor,
+	logger logger.Logger,
+) MarginService {
+	return &marginService{
+		costRepo:    costRepo,
+		productRepo: productRepo,
+		salesRepo:   salesRepo,
+		transactor:  transactor,
+		logger:      logger,
+	}
+}
+
+// RecordCosts проверяет и сохраняет записи истории себестоимости в одной транзакции
+func (s *marginService) RecordCosts(ctx context.Context, costs []entities.ProductCost) error {
+	if len(costs) == 0 {
+		return fmt.Errorf("%w: at least one cost record is required", ErrInvalidParameter)
+	}
+	for i := range costs {
+		if err := costs[i].Validate(); err != nil {
+			return fmt.Errorf("%w: cost record %d: %v", ErrInvalidParameter, i, err)
+		}
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.costRepo.SaveCosts(ctx, costs)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save product costs: %w", err)
+	}
+
+	s.logger.Info(ctx, "История себестоимости обновлена", "records", len(costs))
+	return nil
+}
+
+// GetCostHistory возвращает историю себестоимости и цен товара
+func (s *marginService) GetCostHistory(ctx context.Context, productID string) ([]entities.ProductCost, error) {
+	if productID == "" {
+		return nil, fmt.Errorf("%w: product ID is required", ErrInvalidParameter)
+	}
+
+	costs, err := s.costRepo.GetProductCostHistory(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product cost history: %w", err)
+	}
+	return costs, nil
+}
+
+// BuildErosionReport строит отчет о размывании маржи по продажам периода
+// Рост себестоимости сравнивает себестоимость на дату продажи с себестоимостью на начало периода
+func (s *marginService) BuildErosionReport(ctx context.Context, startDate, endDate time.Time) (*entities.MarginErosionReport, error) {
+	if !startDate.Before(endDate) {
+		return nil, fmt.Errorf("%w: start date must be before end date", ErrInvalidParameter)
+	}
+
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+	if len(sales) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	products, err := s.productRepo.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+	costs, err := loadProductCosts(ctx, s.costRepo, products)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string]string, len(products))
+	for _, product := range products {
+		categories[product.ID] = product.Category
+	}
+
+	total := &marginErosionTotals{}
+	byCategory := make(map[string]*marginErosionTotals)
+	for _, sale := range sales {
+		category := categories[sale.ProductID]
+		if category == "" {
+			category = "unknown"
+		}
+		totals, ok := byCategory[category]
+		if !ok {
+			totals = &marginErosionTotals{}
+			byCategory[category] = totals
+		}
+
+		totals.add(sale, costs, startDate)
+		total.add(sale, costs, startDate)
+	}
+
+	report := &entities.MarginErosionReport{
+		AnalysisMetadata: entities.AnalysisMetadata{
+			AnalysisDate: time.Now(),
+			PeriodStart:  startDate,
+			PeriodEnd:    endDate,
+		},
+		Total:      total.erosion(""),
+		Categories: make([]entities.CategoryMarginErosion, 0, len(byCategory)),
+	}
+	for category, totals := range byCategory {
+		report.Categories = append(report.Categories, totals.erosion(category))
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		lostI := report.Categories[i].DiscountLoss + report.Categories[i].CostInflationLoss
+		lostJ := report.Categories[j].DiscountLoss + report.Categories[j].CostInflationLoss
+		if lostI != lostJ {
+			return lostI > lostJ
+		}
+		return report.Categories[i].Category < report.Categories[j].Category
+	})
+
+	s.logger.Info(ctx, "Построен отчет о размывании маржи", "from", startDate, "to", endDate,
+		"categories", len(report.Categories), "marginErosionPct", report.Total.MarginErosionPct)
+	return report, nil
+}
+
+// loadProductCosts загружает историю себестоимости товаров
+func loadProductCosts(ctx context.Context, costRepo repositories.ProductCostRepository, products []entities.Product) (*productCosts, error) {
+	history, err := costRepo.GetCostHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product cost history: %w", err)
+	}
+	return newProductCosts(products, history), nil
+}
+
+// newProductCosts создает справочник себестоимости по истории и текущей себестоимости товаров каталога
+func newProductCosts(products []entities.Product, history []entities.ProductCost) *productCosts {
+	costs := &productCosts{
+		history: entities.NewCostHistory(history),
+		current: make(map[string]float64, len(products)),
+	}
+	for _, product := range products {
+		if product.Cost > 0 {
+			costs.current[product.ID] = product.Cost
+		}
+	}
+	return costs
+}
+
+// at возвращает себестоимость товара на дату или false, если она неизвестна
+func (c *productCosts) at(productID string, date time.Time) (float64, bool) {
+	if record, ok := c.history.At(productID, date); ok {
+		return record.Cost, true
+	}
+	cost, ok := c.current[productID]
+	return cost, ok
+}
+
+// listPrice возвращает цену товара по прейскуранту на дату или false, если в истории нет цены
+func (c *productCosts) listPrice(productID string, date time.Time) (float64, bool) {
+	record, ok := c.history.At(productID, date)
+	return record.Price, ok && record.Price > 0
+}
+
+// add учитывает продажу; себестоимость на начало периода дает базу для оценки роста себестоимости
+func (t *marginErosionTotals) add(sale entities.Sale, costs *productCosts, periodStart time.Time) {
+	quantity := float64(sale.Quantity)
+	gross := sale.Price * quantity
+	net := saleRevenue(sale)
+
+	t.units += sale.Quantity
+	t.gross += gross
+	t.net += net
+
+	if cost, ok := costs.at(sale.ProductID, sale.PurchaseDate); ok {
+		baseCost, _ := costs.at(sale.ProductID, periodStart)
+		t.costedGross += gross
+		t.costedNet += net
+		t.cost += cost * quantity
+		t.baseCost += baseCost * quantity
+	}
+
+	if price, ok := costs.listPrice(sale.ProductID, sale.PurchaseDate); ok {
+		if basePrice, ok := costs.listPrice(sale.ProductID, periodStart); ok {
+			t.listPrice += price * quantity
+			t.baseListPrice += basePrice * quantity
+		}
+	}
+}
+
+// erosion рассчитывает показатели размывания маржи по накопленным итогам
+func (t *marginErosionTotals) erosion(category string) entities.CategoryMarginErosion {
+	e := entities.CategoryMarginErosion{
+		Category:          category,
+		Units:             t.units,
+		GrossRevenue:      roundTo(t.gross, 2),
+		NetRevenue:        roundTo(t.net, 2),
+		DiscountLoss:      roundTo(t.gross-t.net, 2),
+		Cost:              roundTo(t.cost, 2),
+		BaseCost:          roundTo(t.baseCost, 2),
+		CostInflationLoss: roundTo(t.cost-t.baseCost, 2),
+		Profit:            roundTo(t.costedNet-t.cost, 2),
+	}
+
+	if t.baseCost > 0 {
+		e.CostInflationPct = roundTo((t.cost/t.baseCost-1)*100, 2)
+	}
+	if t.baseListPrice > 0 {
+		e.PriceChangePct = roundTo((t.listPrice/t.baseListPrice-1)*100, 2)
+	}
+	if t.costedNet > 0 {
+		e.MarginPct = roundTo((t.costedNet-t.cost)/t.costedNet*100, 2)
+	}
+	if t.costedGross > 0 {
+		e.ListMarginPct = roundTo((t.costedGross-t.baseCost)/t.costedGross*100, 2)
+		e.MarginErosionPct = roundTo(e.ListMarginPct-e.MarginPct, 2)
+	}
+	if t.gross > 0 {
+		e.CostedRevenueShare = roundTo(t.costedGross/t.gross, 4)
+	}
+	return e
+}
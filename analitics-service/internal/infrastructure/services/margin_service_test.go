@@ -0,0 +1,160 @@
+// internal/infrastructure/services/margin_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+func TestBuildErosionReport(t *testing.T) {
+	start, end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// P1 — кофе с историей: с 15 марта себестоимость 40 -> 50, цена 100 -> 110;
+	// P2 — выпечка без истории с себестоимостью 20 в каталоге; P3 — выпечка с неизвестной себестоимостью
+	products := []entities.Product{
+		testProduct("P1", "coffee", 110),
+		testProduct("P2", "bakery", 50),
+		testProduct("P3", "bakery", 40),
+	}
+	products[1].Cost = 20
+	costs := &memoryCostRepository{costs: []entities.ProductCost{
+		{ProductID: "P1", EffectiveFrom: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), Cost: 50, Price: 110},
+		{ProductID: "P1", EffectiveFrom: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Cost: 40, Price: 100},
+	}}
+	sales := &memorySalesRepository{sales: []entities.Sale{
+		testSale("P1", 2, 100, 0, time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)),
+		testSale("P1", 1, 110, 10, time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)),
+		testSale("P2", 4, 50, 20, time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)),
+		testSale("P3", 1, 40, 0, time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)),
+		// Продажа вне периода не учитывается
+		testSale("P1", 5, 100, 50, time.Date(2024, 2, 20, 12, 0, 0, 0, time.UTC)),
+	}}
+
+	service := services.NewMarginService(costs, &memoryProductRepository{products: products}, sales, directTransactor{}, logger.NewLogger("ERROR"))
+	report, err := service.BuildErosionReport(context.Background(), start, end)
+	if err != nil {
+		t.Fatalf("BuildErosionReport() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  func() entities.CategoryMarginErosion
+		want entities.CategoryMarginErosion
+	}{
+		{
+			// Себестоимость P2 постоянна, поэтому маржа размывается только скидкой; P3 не входит в маржу
+			name: "bakery loses most to discounts",
+			got:  func() entities.CategoryMarginErosion { return report.Categories[0] },
+			want: entities.CategoryMarginErosion{Category: "bakery", Units: 5, GrossRevenue: 240, NetRevenue: 200,
+				DiscountLoss: 40, Cost: 80, BaseCost: 80, Profit: 80, MarginPct: 50, ListMarginPct: 60,
+				MarginErosionPct: 10, CostedRevenueShare: 0.8333},
+		},
+		{
+			// Продажа 20 марта несет себестоимость 50 против 40 на начало периода
+			name: "coffee loses to cost inflation",
+			got:  func() entities.CategoryMarginErosion { return report.Categories[1] },
+			want: entities.CategoryMarginErosion{Category: "coffee", Units: 3, GrossRevenue: 310, NetRevenue: 299,
+				DiscountLoss: 11, Cost: 130, BaseCost: 120, CostInflationLoss: 10, CostInflationPct: 8.33,
+				PriceChangePct: 3.33, Profit: 169, MarginPct: 56.52, ListMarginPct: 61.29, MarginErosionPct: 4.77,
+				CostedRevenueShare: 1},
+		},
+		{
+			name: "total",
+			got:  func() entities.CategoryMarginErosion { return report.Total },
+			want: entities.CategoryMarginErosion{Units: 8, GrossRevenue: 550, NetRevenue: 499, DiscountLoss: 51,
+				Cost: 210, BaseCost: 200, CostInflationLoss: 10, CostInflationPct: 5, PriceChangePct: 3.33,
+				Profit: 249, MarginPct: 54.25, ListMarginPct: 60.78, MarginErosionPct: 6.53, CostedRevenueShare: 0.9273},
+		},
+	}
+
+	if len(report.Categories) != 2 {
+		t.Fatalf("categories = %+v, want bakery and coffee", report.Categories)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("erosion = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	if !report.PeriodStart.Equal(start) || !report.PeriodEnd.Equal(end) {
+		t.Errorf("report period = %s..%s, want %s..%s", report.PeriodStart, report.PeriodEnd, start, end)
+	}
+}
+
+func TestRecordCosts(t *testing.T) {
+	effective := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		costs     []entities.ProductCost
+		wantErr   error
+		wantSaved int
+	}{
+		{name: "valid records", costs: []entities.ProductCost{
+			{ProductID: "P1", EffectiveFrom: effective, Cost: 40, Price: 100},
+			{ProductID: "P2", EffectiveFrom: effective, Cost: 20},
+		}, wantSaved: 2},
+		{name: "no records", wantErr: services.ErrInvalidParameter},
+		// Ни одна запись пачки не сохраняется, если хотя бы одна некорректна
+		{name: "negative cost", costs: []entities.ProductCost{
+			{ProductID: "P1", EffectiveFrom: effective, Cost: 40},
+			{ProductID: "P2", EffectiveFrom: effective, Cost: -1},
+		}, wantErr: services.ErrInvalidParameter},
+		{name: "missing effective date", costs: []entities.ProductCost{{ProductID: "P1", Cost: 40}}, wantErr: services.ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			costs := &memoryCostRepository{}
+			service := services.NewMarginService(costs, &memoryProductRepository{}, &memorySalesRepository{}, directTransactor{}, logger.NewLogger("ERROR"))
+
+			if err := service.RecordCosts(context.Background(), tt.costs); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RecordCosts() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(costs.costs) != tt.wantSaved {
+				t.Errorf("saved costs = %d, want %d", len(costs.costs), tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestMarginServiceErrors(t *testing.T) {
+	start, end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	service := services.NewMarginService(&memoryCostRepository{}, &memoryProductRepository{}, &memorySalesRepository{},
+		directTransactor{}, logger.NewLogger("ERROR"))
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{name: "reversed period", call: func() error {
+			_, err := service.BuildErosionReport(context.Background(), end, start)
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "no sales", call: func() error {
+			_, err := service.BuildErosionReport(context.Background(), start, end)
+			return err
+		}, want: services.ErrInsufficientData},
+		{name: "empty product ID", call: func() error {
+			_, err := service.GetCostHistory(context.Background(), "")
+			return err
+		}, want: services.ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,482 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+	"analitics-service/pkg/stats"
+)
+
+// Допустимый диапазон оцененной эластичности спроса по цене
+const (
+	minFittedElasticity = -6.0
+	maxFittedElasticity = -0.2
+)
+
+// ErrNoFeasiblePrice возвращается, если ни одна цена в допустимом диапазоне не обеспечивает минимальную маржу
+var ErrNoFeasiblePrice = errors.New("no price within the allowed range meets the minimum margin")
+
+// PricingService определяет интерфейс движка динамического ценообразования по временным слотам
+type PricingService interface {
+	// SuggestPrices рассчитывает цены товара на каждый временной слот указанного дня и сохраняет их в журнал
+	SuggestPrices(ctx context.Context, productID string, date time.Time, config entities.PricingConfig) ([]entities.PriceSuggestion, error)
+
+	// RunBatch рассчитывает цены всех активных товаров на указанный день
+	RunBatch(ctx context.Context, date time.Time, config entities.PricingConfig) (*entities.PricingRun, error)
+
+	// GetPriceHistory возвращает журнал предложенных цен товара за период
+	GetPriceHistory(ctx context.Context, productID string, startDate, endDate time.Time) ([]entities.PriceSuggestion, error)
+}
+
+// pricingService реализует интерфейс PricingService
+type pricingService struct {
+	productRepo repositories.ProductRepository
+	salesRepo   repositories.SalesRepository
+	segmentRepo repositories.ABCSegmentRepository
+	pricingRepo repositories.PricingRepository
+	logger      logger.Logger
+}
+
+// slotDemand содержит оценку спроса во временном слоте
+type slotDemand struct {
+	dailyUnits float64 // Средние продажи в слоте за день истории
+	refPrice   float64 // Средняя фактическая цена продаж в слоте
+	elasticity float64 // Эластичность слота, сжатая к общей эластичности товара
+}
+
+// demandEstimate содержит оценку спроса товара по слотам и общую эластичность по цене
+type demandEstimate struct {
+	slots      map[string]slotDemand
+	elasticity float64
+	fitted     bool
+}
+
+// priceCandidate содержит цену-кандидата и ожидаемые показатели
+type priceCandidate struct {
+	price  float64
+	units  float64
+	margin float64
+}
+
+// NewPricingService создает новый экземпляр движка ценообразования
+func NewPricingService(
+	productRepo repositories.ProductRepository,
+	salesRepo repositories.SalesRepository,
+	segmentRepo repositories.ABCSegmentRepository,
+	pricingRepo repositories.PricingRepository,
+	logger logger.Logger,
+) PricingService {
+	return &pricingService{
+		productRepo: productRepo,
+		salesRepo:   salesRepo,
+		segmentRepo: segmentRepo,
+		pricingRepo: pricingRepo,
+		logger:      logger,
+	}
+}
+
+// SuggestPrices рассчитывает цены товара на каждый временной слот указанного дня
+func (s *pricingService) SuggestPrices(ctx context.Context, productID string, date time.Time, config entities.PricingConfig) ([]entities.PriceSuggestion, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	product, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product %s: %w", productID, err)
+	}
+
+	segmentation, err := s.segmentRepo.GetProductSegmentation(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ABC segment for product %s: %w", productID, err)
+	}
+	// Как и в пакетном расчете, товар без ABC-сегмента не оценивается
+	if segmentation == nil {
+		return nil, fmt.Errorf("%w: product %s has no ABC segment", ErrInsufficientData, productID)
+	}
+
+	historyStart := date.AddDate(0, 0, -config.HistoryDays)
+	sales, err := s.salesRepo.GetSalesByProductID(ctx, productID, historyStart, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales for product %s: %w", productID, err)
+	}
+
+	suggestions, err := s.suggestForProduct(ctx, product, segmentation.Segment, sales, date, config, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.pricingRepo.SaveSuggestions(ctx, suggestions); err != nil {
+		return nil, fmt.Errorf("failed to save price suggestions: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// RunBatch рассчитывает цены всех активных товаров на указанный день
+func (s *pricingService) RunBatch(ctx context.Context, date time.Time, config entities.PricingConfig) (*entities.PricingRun, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	run := &entities.PricingRun{
+		EffectiveDate: date,
+		StartedAt:     time.Now(),
+	}
+	run.ID = fmt.Sprintf("pricing-%d", run.StartedAt.UnixNano())
+
+	products, err := s.productRepo.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+
+	segmentation, err := s.segmentRepo.GetFullSegmentation(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve ABC segmentation: %w", err)
+	}
+
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, date.AddDate(0, 0, -config.HistoryDays), date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	salesByProduct := make(map[string][]entities.Sale)
+	for _, sale := range sales {
+		salesByProduct[sale.ProductID] = append(salesByProduct[sale.ProductID], sale)
+	}
+
+	for _, product := range products {
+		if !product.IsActive {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		run.Products++
+
+		segment, ok := segmentation[product.ID]
+		if !ok {
+			s.logger.Warn(ctx, "Товар пропущен: нет ABC-сегмента", "productID", product.ID)
+			run.Skipped++
+			continue
+		}
+
+		suggestions, err := s.suggestForProduct(ctx, product, segment.FinalSegment, salesByProduct[product.ID], date, config, run.ID)
+		if err != nil {
+			s.logger.Warn(ctx, "Не удалось рассчитать цены товара", "productID", product.ID, "error", err)
+			run.Skipped++
+			continue
+		}
+
+		if err := s.pricingRepo.SaveSuggestions(ctx, suggestions); err != nil {
+			return nil, fmt.Errorf("failed to save price suggestions for product %s: %w", product.ID, err)
+		}
+
+		run.Suggestions += len(suggestions)
+		for _, suggestion := range suggestions {
+			if len(suggestion.AppliedGuardrails) > 0 {
+				run.Constrained++
+			}
+		}
+	}
+
+	run.FinishedAt = time.Now()
+	if err := s.pricingRepo.SavePricingRun(ctx, *run); err != nil {
+		return nil, fmt.Errorf("failed to save pricing run: %w", err)
+	}
+
+	s.logger.Info(ctx, "Пакетный расчет цен завершен", "runID", run.ID, "products", run.Products,
+		"suggestions", run.Suggestions, "skipped", run.Skipped, "constrained", run.Constrained)
+	return run, nil
+}
+
+// GetPriceHistory возвращает журнал предложенных цен товара за период
+func (s *pricingService) GetPriceHistory(ctx context.Context, productID string, startDate, endDate time.Time) ([]entities.PriceSuggestion, error) {
+	history, err := s.pricingRepo.GetSuggestionHistory(ctx, productID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve price history for product %s: %w", productID, err)
+	}
+	return history, nil
+}
+
+// suggestForProduct рассчитывает цены товара по слотам без сохранения
+func (s *pricingService) suggestForProduct(
+	ctx context.Context,
+	product entities.Product,
+	segment entities.Segment,
+	sales []entities.Sale,
+	date time.Time,
+	config entities.PricingConfig,
+	runID string,
+) ([]entities.PriceSuggestion, error) {
+	if product.Price <= 0 {
+		return nil, fmt.Errorf("%w: product %s has no base price", ErrInvalidParameter, product.ID)
+	}
+
+	location, _ := time.LoadLocation(config.Timezone)
+	demand := estimateSlotDemand(sales, config, location)
+	guardrails := config.Guardrails
+
+	maxDiscount := guardrails.MaxDiscountPct
+	segmentCapped := false
+	if limit, ok := segmentDiscountCap(segment); ok && limit < maxDiscount {
+		maxDiscount = limit
+		segmentCapped = true
+	}
+	// Границы округляются до копеек, иначе погрешность умножения исключает цену на самой границе
+	low := roundTo(product.Price*(1-maxDiscount/100), 2)
+	high := roundTo(product.Price*(1+guardrails.MaxMarkupPct/100), 2)
+
+	now := time.Now()
+	suggestions := make([]entities.PriceSuggestion, 0, len(config.TimeSlots))
+	for _, slot := range config.TimeSlots {
+		previousPrice := product.Price
+		previous, err := s.pricingRepo.GetLatestSuggestion(ctx, product.ID, slot.Name, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve previous price for slot %s: %w", slot.Name, err)
+		}
+		if previous != nil {
+			previousPrice = previous.SuggestedPrice
+		}
+
+		slotDemand, ok := demand.slots[slot.Name]
+		if !ok {
+			slotDemand.elasticity = demand.elasticity
+		}
+		evaluate := func(price float64) priceCandidate {
+			units := 0.0
+			if slotDemand.refPrice > 0 {
+				units = slotDemand.dailyUnits * math.Pow(price/slotDemand.refPrice, slotDemand.elasticity)
+			}
+			return priceCandidate{price: price, units: units, margin: (price - product.Cost) * units}
+		}
+
+		withinMargin := func(price float64) bool {
+			return (price-product.Cost)/price*100 >= guardrails.MinMarginPct
+		}
+		withinChange := func(price float64) bool {
+			return math.Abs(price-previousPrice)/previousPrice*100 <= guardrails.MaxDailyChangePct
+		}
+
+		// При эластичности больше -1 прибыль растет с ценой неограниченно, оптимум — верхняя граница
+		unconstrained := high
+		if slotDemand.elasticity < -1 {
+			unconstrained = product.Cost * slotDemand.elasticity / (1 + slotDemand.elasticity)
+		}
+
+		// nearest — ближайшая к предыдущей цена с минимальной маржой без учета дневного лимита
+		var best, chosen, nearest *priceCandidate
+		for _, price := range priceCandidates(low, high, guardrails.EndingStep, guardrails.Endings) {
+			candidate := evaluate(price)
+			if betterPriceCandidate(candidate, best, previousPrice) {
+				c := candidate
+				best = &c
+			}
+			if !withinMargin(price) {
+				continue
+			}
+			if withinChange(price) && betterPriceCandidate(candidate, chosen, previousPrice) {
+				c := candidate
+				chosen = &c
+			}
+			if nearest == nil || math.Abs(price-previousPrice) < math.Abs(nearest.price-previousPrice) {
+				c := candidate
+				nearest = &c
+			}
+		}
+
+		var applied []string
+		if unconstrained < low || unconstrained > high {
+			applied = append(applied, entities.GuardrailPriceRange)
+			if segmentCapped && unconstrained < low && unconstrained >= product.Price*(1-guardrails.MaxDiscountPct/100) {
+				applied = append(applied, entities.GuardrailSegmentCap)
+			}
+		}
+
+		// Минимальная маржа — жесткое ограничение: ради нее допускается превышение дневного лимита,
+		// а если ее не обеспечивает ни одна цена диапазона, предложение для слота не формируется
+		switch {
+		case chosen == nil && withinMargin(previousPrice):
+			fallback := evaluate(previousPrice)
+			chosen = &fallback
+			applied = append(applied, entities.GuardrailNoFeasible)
+		case chosen == nil && nearest != nil:
+			chosen = nearest
+			applied = append(applied, entities.GuardrailMarginFloor, entities.GuardrailDailyChangeExceeded)
+		case chosen == nil:
+			s.logger.Warn(ctx, "Нет цены с минимальной маржой, предложение для слота не сформировано",
+				"productID", product.ID, "slot", slot.Name, "cost", product.Cost, "maxPrice", high)
+			continue
+		case best != nil && best.price != chosen.price:
+			if !withinMargin(best.price) {
+				applied = append(applied, entities.GuardrailMarginFloor)
+			}
+			if !withinChange(best.price) {
+				applied = append(applied, entities.GuardrailDailyChange)
+			}
+		}
+
+		suggestions = append(suggestions, entities.PriceSuggestion{
+			ID:                 fmt.Sprintf("%s:%s:%s:%d", product.ID, slot.Name, date.Format("2006-01-02"), now.UnixNano()),
+			RunID:              runID,
+			ProductID:          product.ID,
+			TimeSlot:           slot.Name,
+			EffectiveDate:      date,
+			BasePrice:          product.Price,
+			Cost:               product.Cost,
+			PreviousPrice:      previousPrice,
+			UnconstrainedPrice: roundTo(unconstrained, 2),
+			SuggestedPrice:     chosen.price,
+			DiscountPct:        roundTo((product.Price-chosen.price)/product.Price*100, 2),
+			Elasticity:         slotDemand.elasticity,
+			ElasticityFitted:   demand.fitted,
+			ExpectedUnits:      roundTo(chosen.units, 2),
+			ExpectedMargin:     roundTo(chosen.margin, 2),
+			ABCCategory:        segment,
+			AppliedGuardrails:  applied,
+			CreatedAt:          now,
+		})
+	}
+
+	if len(suggestions) == 0 {
+		return nil, fmt.Errorf("%w: product %s, cost %.2f", ErrNoFeasiblePrice, product.ID, product.Cost)
+	}
+	return suggestions, nil
+}
+
+// estimateSlotDemand оценивает средний спрос по слотам и эластичность спроса по цене
+// Эластичность оценивается регрессией логарифма продаж на логарифм цены по дням внутри слотов,
+// что исключает различия уровня спроса между слотами
+func estimateSlotDemand(sales []entities.Sale, config entities.PricingConfig, location *time.Location) demandEstimate {
+	type slotDay struct {
+		slot string
+		day  string
+	}
+	type dayTotals struct {
+		units   float64
+		revenue float64
+	}
+
+	days := make(map[slotDay]*dayTotals)
+	for _, sale := range sales {
+		slot, ok := entities.DaypartAt(config.TimeSlots, sale.PurchaseDate.In(location).Hour())
+		if !ok || sale.Quantity <= 0 {
+			continue
+		}
+		key := slotDay{slot: slot, day: sale.PurchaseDate.In(location).Format("2006-01-02")}
+		totals, exists := days[key]
+		if !exists {
+			totals = &dayTotals{}
+			days[key] = totals
+		}
+		totals.units += float64(sale.Quantity)
+		totals.revenue += saleRevenue(sale)
+	}
+
+	estimate := demandEstimate{
+		slots:      make(map[string]slotDemand, len(config.TimeSlots)),
+		elasticity: config.DefaultElasticity,
+	}
+
+	slotUnits := make(map[string]float64)
+	slotRevenue := make(map[string]float64)
+	logPrices := make(map[string][]float64)
+	logUnits := make(map[string][]float64)
+	for key, totals := range days {
+		slotUnits[key.slot] += totals.units
+		slotRevenue[key.slot] += totals.revenue
+		if totals.revenue > 0 {
+			logPrices[key.slot] = append(logPrices[key.slot], math.Log(totals.revenue/totals.units))
+			logUnits[key.slot] = append(logUnits[key.slot], math.Log(totals.units))
+		}
+	}
+
+	// Сначала оценивается общая эластичность по всем слотам, затем эластичность каждого слота
+	// сжимается к общей с весом MinObservations, чтобы слоты с короткой историей не давали выбросов
+	type slotFit struct {
+		sxy, sxx     float64
+		observations int
+	}
+	fits := make(map[string]slotFit, len(slotUnits))
+	var pooled slotFit
+	for slot, units := range slotUnits {
+		demand := slotDemand{dailyUnits: units / float64(config.HistoryDays)}
+		if units > 0 {
+			demand.refPrice = slotRevenue[slot] / units
+		}
+		estimate.slots[slot] = demand
+
+		var fit slotFit
+		x, y := logPrices[slot], logUnits[slot]
+		meanX, meanY := stats.Mean(x), stats.Mean(y)
+		for i := range x {
+			fit.sxy += (x[i] - meanX) * (y[i] - meanY)
+			fit.sxx += (x[i] - meanX) * (x[i] - meanX)
+		}
+		fit.observations = len(x)
+		fits[slot] = fit
+
+		pooled.sxy += fit.sxy
+		pooled.sxx += fit.sxx
+		pooled.observations += fit.observations
+	}
+
+	if pooled.observations >= config.MinObservations && pooled.sxx > 1e-9 {
+		if slope := pooled.sxy / pooled.sxx; slope < 0 {
+			estimate.elasticity = clampElasticity(slope)
+			estimate.fitted = true
+		}
+	}
+
+	prior := float64(config.MinObservations)
+	for slot, demand := range estimate.slots {
+		demand.elasticity = estimate.elasticity
+		fit := fits[slot]
+		if estimate.fitted && fit.sxx > 1e-9 {
+			slope := clampElasticity(fit.sxy / fit.sxx)
+			weight := float64(fit.observations)
+			demand.elasticity = clampElasticity((weight*slope + prior*estimate.elasticity) / (weight + prior))
+		}
+		estimate.slots[slot] = demand
+	}
+
+	return estimate
+}
+
+// clampElasticity ограничивает эластичность допустимым диапазоном
+func clampElasticity(elasticity float64) float64 {
+	return math.Max(math.Min(elasticity, maxFittedElasticity), minFittedElasticity)
+}
+
+// priceCandidates перечисляет цены с допустимыми окончаниями внутри диапазона
+func priceCandidates(low, high, step float64, endings []float64) []float64 {
+	var prices []float64
+	for base := math.Floor(low/step) * step; base <= high; base += step {
+		for _, ending := range endings {
+			price := roundTo(base+ending, 2)
+			if price >= low && price <= high && price > 0 {
+				prices = append(prices, price)
+			}
+		}
+	}
+	sort.Float64s(prices)
+	return prices
+}
+
+// betterPriceCandidate сравнивает кандидатов по ожидаемой марже, при равенстве предпочитается цена ближе к предыдущей
+func betterPriceCandidate(candidate priceCandidate, current *priceCandidate, previousPrice float64) bool {
+	if current == nil {
+		return true
+	}
+	if math.Abs(candidate.margin-current.margin) > 1e-9 {
+		return candidate.margin > current.margin
+	}
+	return math.Abs(candidate.price-previousPrice) < math.Abs(current.price-previousPrice)
+}
@@ -0,0 +1,289 @@
+// internal/infrastructure/services/pricing_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryPricingRepository хранит журнал предложенных цен в памяти
+type memoryPricingRepository struct {
+	suggestions []entities.PriceSuggestion
+	runs        []entities.PricingRun
+}
+
+func (r *memoryPricingRepository) SaveSuggestions(_ context.Context, suggestions []entities.PriceSuggestion) error {
+	r.suggestions = append(r.suggestions, suggestions...)
+	return nil
+}
+
+func (r *memoryPricingRepository) GetLatestSuggestion(_ context.Context, productID, timeSlot string, before time.Time) (*entities.PriceSuggestion, error) {
+	var latest *entities.PriceSuggestion
+	for i := range r.suggestions {
+		suggestion := &r.suggestions[i]
+		if suggestion.ProductID == productID && suggestion.TimeSlot == timeSlot && suggestion.EffectiveDate.Before(before) {
+			latest = suggestion
+		}
+	}
+	return latest, nil
+}
+
+func (r *memoryPricingRepository) GetSuggestionHistory(_ context.Context, productID string, startDate, endDate time.Time) ([]entities.PriceSuggestion, error) {
+	var result []entities.PriceSuggestion
+	for _, suggestion := range r.suggestions {
+		if suggestion.ProductID == productID && !suggestion.EffectiveDate.Before(startDate) && !suggestion.EffectiveDate.After(endDate) {
+			result = append(result, suggestion)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryPricingRepository) SavePricingRun(_ context.Context, run entities.PricingRun) error {
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+// testPricingConfig возвращает конфигурацию с одним слотом на весь день и десятью днями истории
+func testPricingConfig(elasticity float64) entities.PricingConfig {
+	config := entities.DefaultPricingConfig()
+	config.TimeSlots = []entities.Daypart{{Name: "day", StartHour: 0, EndHour: 24}}
+	config.HistoryDays = 10
+	config.MinObservations = 3
+	config.DefaultElasticity = elasticity
+	return config
+}
+
+// testPricingSales возвращает продажи товара в полдень каждого дня истории по чередующимся ценам
+// Спрос задан как units = 100000 / price², то есть эластичность равна -2
+func testPricingSales(productID string, date time.Time, prices ...float64) []entities.Sale {
+	var sales []entities.Sale
+	for i := 1; i <= 10; i++ {
+		price := prices[i%len(prices)]
+		sales = append(sales, testSale(productID, int(100000/(price*price)), price, 0, date.AddDate(0, 0, -i).Add(12*time.Hour)))
+	}
+	return sales
+}
+
+func TestSuggestPricesGuardrails(t *testing.T) {
+	date := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+
+	// Базовая цена 100, диапазон 70..115 (для сегмента A — 80..115), дневной лимит 10%, минимальная маржа 20%.
+	// История с постоянной ценой 100 не позволяет оценить эластичность, поэтому используется значение по умолчанию,
+	// а оптимум без ограничений равен cost·e / (1 + e)
+	tests := []struct {
+		name          string
+		cost          float64
+		elasticity    float64
+		segment       entities.Segment
+		previous      float64
+		endings       []float64
+		wantPrice     float64
+		wantOptimum   float64
+		wantGuardrail []string
+	}{
+		{
+			name: "optimum above markup", cost: 40, elasticity: -1.5, segment: entities.SegmentC,
+			wantPrice: 110, wantOptimum: 120,
+			wantGuardrail: []string{entities.GuardrailPriceRange, entities.GuardrailDailyChange},
+		},
+		{
+			name: "optimum inside range", cost: 40, elasticity: -2.2, segment: entities.SegmentC,
+			wantPrice: 90, wantOptimum: 73.33,
+			wantGuardrail: []string{entities.GuardrailDailyChange},
+		},
+		{
+			// Без ограничения сегмента оптимум 73.33 был бы допустим, для сегмента A нижняя граница 80
+			name: "discount capped by segment", cost: 40, elasticity: -2.2, segment: entities.SegmentA,
+			wantPrice: 90, wantOptimum: 73.33,
+			wantGuardrail: []string{entities.GuardrailPriceRange, entities.GuardrailSegmentCap, entities.GuardrailDailyChange},
+		},
+		{
+			// Оптимум 102 дает маржу 15%, ближайшая цена с маржой 20% и допустимым окончанием — 109
+			name: "margin floor", cost: 85, elasticity: -6, segment: entities.SegmentC,
+			wantPrice: 109, wantOptimum: 102,
+			wantGuardrail: []string{entities.GuardrailMarginFloor},
+		},
+		{
+			name: "margin floor exceeds daily change", cost: 90, elasticity: -1.5, segment: entities.SegmentC,
+			wantPrice: 115, wantOptimum: 270,
+			wantGuardrail: []string{entities.GuardrailPriceRange, entities.GuardrailMarginFloor, entities.GuardrailDailyChangeExceeded},
+		},
+		{
+			// Цены кратны 10, ни одна из них не дает маржу 20%, но предыдущая цена 113 ее обеспечивает
+			name: "previous price kept", cost: 90, elasticity: -1.5, segment: entities.SegmentC, previous: 113, endings: []float64{0},
+			wantPrice: 113, wantOptimum: 270,
+			wantGuardrail: []string{entities.GuardrailPriceRange, entities.GuardrailNoFeasible},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := testProduct("P1", "coffee", 100)
+			product.Cost = tt.cost
+
+			pricing := &memoryPricingRepository{}
+			if tt.previous > 0 {
+				pricing.suggestions = append(pricing.suggestions, entities.PriceSuggestion{
+					ProductID: "P1", TimeSlot: "day", EffectiveDate: date.AddDate(0, 0, -1), SuggestedPrice: tt.previous,
+				})
+			}
+			config := testPricingConfig(tt.elasticity)
+			if tt.endings != nil {
+				config.Guardrails.Endings = tt.endings
+			}
+
+			service := services.NewPricingService(
+				&memoryProductRepository{products: []entities.Product{product}},
+				&memorySalesRepository{sales: testPricingSales("P1", date, 100)},
+				&memorySegmentRepository{segments: map[string]entities.Segment{"P1": tt.segment}},
+				pricing,
+				logger.NewLogger("ERROR"),
+			)
+
+			suggestions, err := service.SuggestPrices(context.Background(), "P1", date, config)
+			if err != nil {
+				t.Fatalf("SuggestPrices() error = %v", err)
+			}
+			if len(suggestions) != 1 {
+				t.Fatalf("suggestions = %d, want 1", len(suggestions))
+			}
+			got := suggestions[0]
+			if got.SuggestedPrice != tt.wantPrice {
+				t.Errorf("suggested price = %.2f, want %.2f", got.SuggestedPrice, tt.wantPrice)
+			}
+			if math.Abs(got.UnconstrainedPrice-tt.wantOptimum) > 1e-9 {
+				t.Errorf("unconstrained price = %.2f, want %.2f", got.UnconstrainedPrice, tt.wantOptimum)
+			}
+			if !reflect.DeepEqual(got.AppliedGuardrails, tt.wantGuardrail) {
+				t.Errorf("guardrails = %v, want %v", got.AppliedGuardrails, tt.wantGuardrail)
+			}
+			if got.ElasticityFitted || got.Elasticity != tt.elasticity {
+				t.Errorf("elasticity = %.2f (fitted %v), want default %.2f", got.Elasticity, got.ElasticityFitted, tt.elasticity)
+			}
+			// Каждое предложение добавляется в журнал к предыдущим
+			if last := pricing.suggestions[len(pricing.suggestions)-1]; last.ID != got.ID {
+				t.Errorf("audit trail last record = %s, want %s", last.ID, got.ID)
+			}
+		})
+	}
+}
+
+func TestSuggestPricesFitsElasticity(t *testing.T) {
+	date := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+	product := testProduct("P1", "coffee", 100)
+	product.Cost = 40
+
+	service := services.NewPricingService(
+		&memoryProductRepository{products: []entities.Product{product}},
+		&memorySalesRepository{sales: testPricingSales("P1", date, 50, 100)},
+		&memorySegmentRepository{segments: map[string]entities.Segment{"P1": entities.SegmentC}},
+		&memoryPricingRepository{},
+		logger.NewLogger("ERROR"),
+	)
+
+	suggestions, err := service.SuggestPrices(context.Background(), "P1", date, testPricingConfig(-1.5))
+	if err != nil {
+		t.Fatalf("SuggestPrices() error = %v", err)
+	}
+	// Продажи 40 единиц по 50 и 10 единиц по 100 точно соответствуют эластичности -2, оптимум 40·2 = 80
+	got := suggestions[0]
+	if !got.ElasticityFitted || math.Abs(got.Elasticity+2) > 1e-9 {
+		t.Errorf("elasticity = %.4f (fitted %v), want fitted -2", got.Elasticity, got.ElasticityFitted)
+	}
+	if math.Abs(got.UnconstrainedPrice-80) > 1e-9 {
+		t.Errorf("unconstrained price = %.2f, want 80", got.UnconstrainedPrice)
+	}
+}
+
+func TestSuggestPricesErrors(t *testing.T) {
+	date := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+
+	expensive := testProduct("P1", "coffee", 100)
+	expensive.Cost = 95
+	unpriced := testProduct("P2", "coffee", 0)
+	unsegmented := testProduct("P3", "coffee", 100)
+
+	service := services.NewPricingService(
+		&memoryProductRepository{products: []entities.Product{expensive, unpriced, unsegmented}},
+		&memorySalesRepository{},
+		&memorySegmentRepository{segments: map[string]entities.Segment{"P1": entities.SegmentC, "P2": entities.SegmentC}},
+		&memoryPricingRepository{},
+		logger.NewLogger("ERROR"),
+	)
+
+	invalid := testPricingConfig(-1.5)
+	invalid.Guardrails.Endings = []float64{10}
+
+	tests := []struct {
+		name      string
+		productID string
+		config    entities.PricingConfig
+		want      error
+	}{
+		{name: "ending outside step", productID: "P1", config: invalid, want: services.ErrInvalidParameter},
+		// Маржа 20% требует цены от 118.75 при максимальной 115
+		{name: "no price meets margin", productID: "P1", config: testPricingConfig(-1.5), want: services.ErrNoFeasiblePrice},
+		{name: "no base price", productID: "P2", config: testPricingConfig(-1.5), want: services.ErrInvalidParameter},
+		{name: "no ABC segment", productID: "P3", config: testPricingConfig(-1.5), want: services.ErrInsufficientData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.SuggestPrices(context.Background(), tt.productID, date, tt.config); !errors.Is(err, tt.want) {
+				t.Errorf("SuggestPrices() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPricingBatch(t *testing.T) {
+	date := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+
+	products := []entities.Product{
+		testProduct("P1", "coffee", 100),
+		testProduct("P2", "coffee", 100),
+		testProduct("P3", "coffee", 100),
+		testProduct("P4", "coffee", 100),
+	}
+	products[0].Cost = 40
+	products[1].Cost = 40
+	products[2].Cost = 95
+	products[3].IsActive = false
+
+	pricing := &memoryPricingRepository{}
+	service := services.NewPricingService(
+		&memoryProductRepository{products: products},
+		&memorySalesRepository{sales: testPricingSales("P1", date, 100)},
+		// P2 без ABC-сегмента, P3 без допустимой цены, P4 не участвует в продаже
+		&memorySegmentRepository{segments: map[string]entities.Segment{"P1": entities.SegmentC, "P3": entities.SegmentC, "P4": entities.SegmentC}},
+		pricing,
+		logger.NewLogger("ERROR"),
+	)
+
+	run, err := service.RunBatch(context.Background(), date, testPricingConfig(-1.5))
+	if err != nil {
+		t.Fatalf("RunBatch() error = %v", err)
+	}
+	if run.Products != 3 || run.Suggestions != 1 || run.Skipped != 2 || run.Constrained != 1 {
+		t.Errorf("run = %+v, want 3 products, 1 suggestion, 2 skipped, 1 constrained", *run)
+	}
+	if len(pricing.runs) != 1 {
+		t.Errorf("saved runs = %d, want 1", len(pricing.runs))
+	}
+
+	history, err := service.GetPriceHistory(context.Background(), "P1", date, date)
+	if err != nil {
+		t.Fatalf("GetPriceHistory() error = %v", err)
+	}
+	if len(history) != 1 || history[0].RunID != run.ID {
+		t.Errorf("history = %+v, want one suggestion of run %s", history, run.ID)
+	}
+}
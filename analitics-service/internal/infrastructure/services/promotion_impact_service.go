@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+)
+
+// ErrPromotionNotFound возвращается, если акции с указанным ID нет в промо-календаре
+var ErrPromotionNotFound = errors.New("promotion not found")
+
+// ErrNoControlProducts возвращается, если для акции не нашлось контрольных товаров;
+// в этом случае их нужно передать явно в ControlProductIDs
+var ErrNoControlProducts = fmt.Errorf("%w: no control products for promotion, pass control_product_ids", ErrInsufficientData)
+
+// Источники контрольной группы акции
+const (
+	controlGroupExplicit        = "explicit"
+	controlGroupSameCategory    = "same_category"
+	controlGroupOtherCategories = "other_categories"
+)
+
+// PromotionImpactService определяет интерфейс оценки эффекта акций промо-календаря
+type PromotionImpactService interface {
+	// CreatePromotion проверяет и сохраняет акцию в промо-календаре
+	CreatePromotion(ctx context.Context, promotion entities.Promotion) error
+
+	// MeasureImpact оценивает эффект акции методом разности разностей
+	// Контрольная группа — явно заданные товары, товары тех же категорий вне акции,
+	// а если акция охватывает категории целиком — товары других категорий
+	MeasureImpact(ctx context.Context, promotionID string, options entities.PromotionImpactOptions) (*entities.PromotionImpact, error)
+}
+
+// promotionImpactService реализует интерфейс PromotionImpactService
+type promotionImpactService struct {
+	promotionRepo repositories.PromotionRepository
+	salesRepo     repositories.SalesRepository
+	productRepo   repositories.ProductRepository
+	costRepo      repositories.ProductCostRepository
+	logger        logger.Logger
+}
+
+// groupDaily содержит средние дневные продажи на один товар группы
+type groupDaily struct {
+	units   float64
+	revenue float64
+	margin  float64
+}
+
+// NewPromotionImpactService создает новый экземпляр сервиса оценки эффекта акций
+func NewPromotionImpactService(
+	promotionRepo repositories.PromotionRepository,
+	salesRepo repositories.SalesRepository,
+	productRepo repositories.ProductRepository,
+	costRepo repositories.ProductCostRepository,
+	logger logger.Logger,
+) PromotionImpactService {
+	return &promotionImpactService{
+		promotionRepo: promotionRepo,
+		salesRepo:     salesRepo,
+		productRepo:   productRepo,
+		costRepo:      costRepo,
+		logger:        logger,
+	}
+}
+
+// CreatePromotion проверяет и сохраняет акцию в промо-календаре
+func (s *promotionImpactService) CreatePromotion(ctx context.Context, promotion entities.Promotion) error {
+	if err := promotion.Validate(); err != nil {
+		return fmt.Errorf("%w: invalid promotion: %v", ErrInvalidParameter, err)
+	}
+
+	now := time.Now()
+	if promotion.CreatedAt.IsZero() {
+		promotion.CreatedAt = now
+	}
+	promotion.UpdatedAt = now
+
+	if err := s.promotionRepo.SavePromotion(ctx, promotion); err != nil {
+		return fmt.Errorf("failed to save promotion: %w", err)
+	}
+
+	s.logger.Info(ctx, "Акция добавлена в промо-календарь", "promotionID", promotion.ID,
+		"mechanic", promotion.Mechanic, "start", promotion.StartDate, "end", promotion.EndDate)
+	return nil
+}
+
+// MeasureImpact оценивает эффект акции методом разности разностей
+func (s *promotionImpactService) MeasureImpact(ctx context.Context, promotionID string, options entities.PromotionImpactOptions) (*entities.PromotionImpact, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	promotion, err := s.promotionRepo.GetPromotionByID(ctx, promotionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve promotion %s: %w", promotionID, err)
+	}
+	if promotion.ID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrPromotionNotFound, promotionID)
+	}
+
+	products, err := s.productRepo.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+
+	treated, control, controlGroup := splitPromotionGroups(promotion, products, options.ControlProductIDs)
+	if len(treated) == 0 {
+		return nil, fmt.Errorf("%w: promotion %s covers no products", ErrInsufficientData, promotionID)
+	}
+	if len(control) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoControlProducts, promotionID)
+	}
+
+	promoDuration := promotion.EndDate.Sub(promotion.StartDate)
+	preDuration := promoDuration
+	if options.PrePeriodDays > 0 {
+		preDuration = time.Duration(options.PrePeriodDays) * 24 * time.Hour
+	}
+	postDuration := time.Duration(options.PostPeriodDays) * 24 * time.Hour
+
+	preStart := promotion.StartDate.Add(-preDuration)
+	postEnd := promotion.EndDate.Add(postDuration)
+
+	sales, err := s.salesRepo.GetSalesByPeriod(ctx, preStart, postEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sales: %w", err)
+	}
+
+	costs, err := loadProductCosts(ctx, s.costRepo, products)
+	if err != nil {
+		return nil, err
+	}
+
+	preDays := preDuration.Hours() / 24
+	promoDays := promoDuration.Hours() / 24
+	postDays := postDuration.Hours() / 24
+
+	// Итоги по группам и периодам: 0 — до акции, 1 — акция, 2 — после акции
+	var treatedTotals, controlTotals [3]groupDaily
+	for _, sale := range sales {
+		period := 1
+		switch {
+		case sale.PurchaseDate.Before(promotion.StartDate):
+			period = 0
+		case !sale.PurchaseDate.Before(promotion.EndDate):
+			period = 2
+		}
+
+		var totals *[3]groupDaily
+		if treated[sale.ProductID] {
+			totals = &treatedTotals
+		} else if control[sale.ProductID] {
+			totals = &controlTotals
+		} else {
+			continue
+		}
+
+		revenue := saleRevenue(sale)
+		totals[period].units += float64(sale.Quantity)
+		totals[period].revenue += revenue
+		cost, _ := costs.at(sale.ProductID, sale.PurchaseDate)
+		totals[period].margin += revenue - cost*float64(sale.Quantity)
+	}
+
+	// Средние дневные продажи на товар делают группы разного размера сопоставимыми
+	days := [3]float64{preDays, promoDays, postDays}
+	for period := range days {
+		treatedTotals[period] = perProductDaily(treatedTotals[period], len(treated), days[period])
+		controlTotals[period] = perProductDaily(controlTotals[period], len(control), days[period])
+	}
+
+	scale := float64(len(treated))
+	impact := &entities.PromotionImpact{
+		PromotionID:     promotionID,
+		AnalysisDate:    time.Now(),
+		PreStart:        preStart,
+		PromoStart:      promotion.StartDate,
+		PromoEnd:        promotion.EndDate,
+		PostEnd:         postEnd,
+		TreatedProducts: sortedKeys(treated),
+		ControlProducts: sortedKeys(control),
+		ControlGroup:    controlGroup,
+		Incremental:     diffInDiff(treatedTotals[0], treatedTotals[1], controlTotals[0], controlTotals[1], scale*promoDays),
+	}
+
+	if postDays > 0 {
+		impact.PostPromotionDip = diffInDiff(treatedTotals[0], treatedTotals[2], controlTotals[0], controlTotals[2], scale*postDays)
+	}
+
+	impact.NetIncremental = entities.ImpactMetrics{
+		Units:   impact.Incremental.Units + impact.PostPromotionDip.Units,
+		Revenue: impact.Incremental.Revenue + impact.PostPromotionDip.Revenue,
+		Margin:  impact.Incremental.Margin + impact.PostPromotionDip.Margin,
+	}
+
+	// Контрфактические продажи: уровень до акции плюс изменение в контрольной группе
+	counterfactual := (treatedTotals[0].units + controlTotals[1].units - controlTotals[0].units) * scale * promoDays
+	if counterfactual > 0 {
+		impact.IncrementalUnitsPct = impact.Incremental.Units / counterfactual * 100
+	}
+
+	if err := s.promotionRepo.SavePromotionImpact(ctx, *impact); err != nil {
+		s.logger.Warn(ctx, "Не удалось сохранить оценку эффекта акции", "promotionID", promotionID, "error", err)
+	}
+
+	s.logger.Info(ctx, "Оценен эффект акции", "promotionID", promotionID,
+		"incrementalUnits", impact.Incremental.Units, "incrementalMargin", impact.Incremental.Margin,
+		"postDipUnits", impact.PostPromotionDip.Units)
+	return impact, nil
+}
+
+// splitPromotionGroups разделяет товары на участвующие в акции и контрольные и возвращает источник контрольной группы
+// Без явного списка контрольными считаются товары тех же категорий вне акции, а если таких нет
+// (акция охватывает категории целиком) — товары остальных категорий
+func splitPromotionGroups(promotion entities.Promotion, products []entities.Product, controlIDs []string) (map[string]bool, map[string]bool, string) {
+	treated := make(map[string]bool)
+	categories := make(map[string]bool)
+	for _, product := range products {
+		if promotion.Covers(product) {
+			treated[product.ID] = true
+			categories[product.Category] = true
+		}
+	}
+
+	control := make(map[string]bool)
+	if len(controlIDs) > 0 {
+		for _, id := range controlIDs {
+			if !treated[id] {
+				control[id] = true
+			}
+		}
+		return treated, control, controlGroupExplicit
+	}
+
+	for _, product := range products {
+		if !treated[product.ID] && categories[product.Category] {
+			control[product.ID] = true
+		}
+	}
+	if len(control) > 0 {
+		return treated, control, controlGroupSameCategory
+	}
+
+	for _, product := range products {
+		if !treated[product.ID] {
+			control[product.ID] = true
+		}
+	}
+	return treated, control, controlGroupOtherCategories
+}
+
+// perProductDaily переводит итоги группы за период в средние дневные значения на товар
+func perProductDaily(totals groupDaily, products int, days float64) groupDaily {
+	if products == 0 || days <= 0 {
+		return groupDaily{}
+	}
+	divisor := float64(products) * days
+	return groupDaily{
+		units:   totals.units / divisor,
+		revenue: totals.revenue / divisor,
+		margin:  totals.margin / divisor,
+	}
+}
+
+// diffInDiff рассчитывает разность разностей и масштабирует её на число товаро-дней
+func diffInDiff(treatedBefore, treatedAfter, controlBefore, controlAfter groupDaily, scale float64) entities.ImpactMetrics {
+	return entities.ImpactMetrics{
+		Units:   ((treatedAfter.units - treatedBefore.units) - (controlAfter.units - controlBefore.units)) * scale,
+		Revenue: ((treatedAfter.revenue - treatedBefore.revenue) - (controlAfter.revenue - controlBefore.revenue)) * scale,
+		Margin:  ((treatedAfter.margin - treatedBefore.margin) - (controlAfter.margin - controlBefore.margin)) * scale,
+	}
+}
+
+// sortedKeys возвращает отсортированные ключи множества
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,265 @@
+// internal/infrastructure/services/promotion_impact_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryPromotionRepository хранит промо-календарь и оценки эффекта акций в памяти
+type memoryPromotionRepository struct {
+	promotions map[string]entities.Promotion
+	impacts    []entities.PromotionImpact
+}
+
+func newMemoryPromotionRepository() *memoryPromotionRepository {
+	return &memoryPromotionRepository{promotions: make(map[string]entities.Promotion)}
+}
+
+func (r *memoryPromotionRepository) SavePromotion(_ context.Context, promotion entities.Promotion) error {
+	r.promotions[promotion.ID] = promotion
+	return nil
+}
+
+func (r *memoryPromotionRepository) GetPromotionByID(_ context.Context, promotionID string) (entities.Promotion, error) {
+	return r.promotions[promotionID], nil
+}
+
+func (r *memoryPromotionRepository) GetPromotionsByPeriod(_ context.Context, startDate, endDate time.Time) ([]entities.Promotion, error) {
+	var result []entities.Promotion
+	for _, promotion := range r.promotions {
+		if promotion.StartDate.Before(endDate) && promotion.EndDate.After(startDate) {
+			result = append(result, promotion)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryPromotionRepository) GetPromotionsByProduct(_ context.Context, productID string) ([]entities.Promotion, error) {
+	var result []entities.Promotion
+	for _, promotion := range r.promotions {
+		for _, id := range promotion.ProductIDs {
+			if id == productID {
+				result = append(result, promotion)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryPromotionRepository) DeletePromotion(_ context.Context, promotionID string) error {
+	delete(r.promotions, promotionID)
+	return nil
+}
+
+func (r *memoryPromotionRepository) SavePromotionImpact(_ context.Context, impact entities.PromotionImpact) error {
+	r.impacts = append(r.impacts, impact)
+	return nil
+}
+
+// memoryCostRepository хранит историю себестоимости в памяти
+type memoryCostRepository struct {
+	costs []entities.ProductCost
+}
+
+func (r *memoryCostRepository) GetCostHistory(_ context.Context) ([]entities.ProductCost, error) {
+	return r.costs, nil
+}
+
+func (r *memoryCostRepository) GetProductCostHistory(_ context.Context, productID string) ([]entities.ProductCost, error) {
+	var result []entities.ProductCost
+	for _, cost := range r.costs {
+		if cost.ProductID == productID {
+			result = append(result, cost)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryCostRepository) SaveCosts(_ context.Context, costs []entities.ProductCost) error {
+	r.costs = append(r.costs, costs...)
+	return nil
+}
+
+// testPromotion возвращает недельную акцию со скидкой 10%
+func testPromotion(start time.Time, productIDs, categories []string) entities.Promotion {
+	promotion := entities.Promotion{
+		Name:          "Spring coffee",
+		ProductIDs:    productIDs,
+		Categories:    categories,
+		Mechanic:      entities.MechanicPercentOff,
+		DiscountValue: 10,
+		StartDate:     start,
+		EndDate:       start.AddDate(0, 0, 7),
+	}
+	promotion.ID = "promo-1"
+	return promotion
+}
+
+// testPromotionService возвращает сервис с продажами за неделю до акции, неделю акции и неделю после:
+// P1 (кофе, в акции) продает 10, 20 и 8 единиц в день, P2 (кофе) — 10, 11 и 10, P3 (чай) — 10 в каждый период
+func testPromotionService(promotion entities.Promotion) (services.PromotionImpactService, *memoryPromotionRepository) {
+	pre, promo, post := promotion.StartDate.AddDate(0, 0, -3), promotion.StartDate.AddDate(0, 0, 3), promotion.EndDate.AddDate(0, 0, 3)
+	sales := &memorySalesRepository{sales: []entities.Sale{
+		testSale("P1", 70, 10, 0, pre),
+		testSale("P1", 140, 10, 10, promo),
+		testSale("P1", 56, 10, 0, post),
+		testSale("P2", 70, 10, 0, pre),
+		testSale("P2", 77, 10, 0, promo),
+		testSale("P2", 70, 10, 0, post),
+		testSale("P3", 70, 5, 0, pre),
+		testSale("P3", 70, 5, 0, promo),
+		testSale("P3", 70, 5, 0, post),
+	}}
+
+	products := &memoryProductRepository{}
+	for _, product := range []entities.Product{testProduct("P1", "coffee", 10), testProduct("P2", "coffee", 10), testProduct("P3", "tea", 5)} {
+		product.Cost = product.Price * 0.4
+		products.products = append(products.products, product)
+	}
+
+	promotions := newMemoryPromotionRepository()
+	promotions.promotions[promotion.ID] = promotion
+	return services.NewPromotionImpactService(promotions, sales, products, &memoryCostRepository{}, logger.NewLogger("ERROR")), promotions
+}
+
+func TestMeasureImpactDiffInDiff(t *testing.T) {
+	promotion := testPromotion(time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), []string{"P1"}, nil)
+	service, repo := testPromotionService(promotion)
+
+	impact, err := service.MeasureImpact(context.Background(), promotion.ID, entities.PromotionImpactOptions{PostPeriodDays: 7})
+	if err != nil {
+		t.Fatalf("MeasureImpact() error = %v", err)
+	}
+
+	// Дневные значения на товар: выручка P1 100, 180 и 80, P2 100, 110 и 100; маржа при себестоимости 40% цены
+	// P1 60, 100 и 48, P2 60, 66 и 60. Разность разностей умножается на 7 дней
+	tests := []struct {
+		name string
+		got  entities.ImpactMetrics
+		want entities.ImpactMetrics
+	}{
+		{name: "incremental", got: impact.Incremental, want: entities.ImpactMetrics{Units: 63, Revenue: 490, Margin: 238}},
+		{name: "post-promotion dip", got: impact.PostPromotionDip, want: entities.ImpactMetrics{Units: -14, Revenue: -140, Margin: -84}},
+		{name: "net incremental", got: impact.NetIncremental, want: entities.ImpactMetrics{Units: 49, Revenue: 350, Margin: 154}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got.Units-tt.want.Units) > 1e-9 || math.Abs(tt.got.Revenue-tt.want.Revenue) > 1e-9 ||
+				math.Abs(tt.got.Margin-tt.want.Margin) > 1e-9 {
+				t.Errorf("%s = %+v, want %+v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+
+	// Контрфактические продажи: (10 + 11 - 10) * 7 = 77 единиц
+	if want := 63.0 / 77 * 100; math.Abs(impact.IncrementalUnitsPct-want) > 1e-9 {
+		t.Errorf("incremental units pct = %.4f, want %.4f", impact.IncrementalUnitsPct, want)
+	}
+	if !impact.PreStart.Equal(promotion.StartDate.AddDate(0, 0, -7)) || !impact.PostEnd.Equal(promotion.EndDate.AddDate(0, 0, 7)) {
+		t.Errorf("periods = %s..%s, want a week before and after the promotion", impact.PreStart, impact.PostEnd)
+	}
+	if len(repo.impacts) != 1 {
+		t.Errorf("saved impacts = %d, want 1", len(repo.impacts))
+	}
+}
+
+func TestMeasureImpactControlGroup(t *testing.T) {
+	start := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		promotion   entities.Promotion
+		controlIDs  []string
+		wantTreated []string
+		wantControl []string
+		wantGroup   string
+	}{
+		{
+			name:        "same category",
+			promotion:   testPromotion(start, []string{"P1"}, nil),
+			wantTreated: []string{"P1"},
+			wantControl: []string{"P2"},
+			wantGroup:   "same_category",
+		},
+		{
+			name:        "explicit control without treated products",
+			promotion:   testPromotion(start, []string{"P1"}, nil),
+			controlIDs:  []string{"P3", "P1"},
+			wantTreated: []string{"P1"},
+			wantControl: []string{"P3"},
+			wantGroup:   "explicit",
+		},
+		{
+			// Акция охватывает категорию целиком, поэтому контролем служат другие категории
+			name:        "whole category promoted",
+			promotion:   testPromotion(start, nil, []string{"coffee"}),
+			wantTreated: []string{"P1", "P2"},
+			wantControl: []string{"P3"},
+			wantGroup:   "other_categories",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := testPromotionService(tt.promotion)
+			impact, err := service.MeasureImpact(context.Background(), tt.promotion.ID, entities.PromotionImpactOptions{ControlProductIDs: tt.controlIDs})
+			if err != nil {
+				t.Fatalf("MeasureImpact() error = %v", err)
+			}
+			if !reflect.DeepEqual(impact.TreatedProducts, tt.wantTreated) {
+				t.Errorf("treated = %v, want %v", impact.TreatedProducts, tt.wantTreated)
+			}
+			if !reflect.DeepEqual(impact.ControlProducts, tt.wantControl) {
+				t.Errorf("control = %v, want %v", impact.ControlProducts, tt.wantControl)
+			}
+			if impact.ControlGroup != tt.wantGroup {
+				t.Errorf("control group = %s, want %s", impact.ControlGroup, tt.wantGroup)
+			}
+		})
+	}
+}
+
+func TestPromotionImpactErrors(t *testing.T) {
+	start := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	service, _ := testPromotionService(testPromotion(start, []string{"P1"}, nil))
+
+	invalid := testPromotion(start, []string{"P1"}, nil)
+	invalid.DiscountValue = 150
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{name: "invalid promotion", call: func() error { return service.CreatePromotion(context.Background(), invalid) }, want: services.ErrInvalidParameter},
+		{name: "unknown promotion", call: func() error {
+			_, err := service.MeasureImpact(context.Background(), "missing", entities.PromotionImpactOptions{})
+			return err
+		}, want: services.ErrPromotionNotFound},
+		{name: "negative post period", call: func() error {
+			_, err := service.MeasureImpact(context.Background(), "promo-1", entities.PromotionImpactOptions{PostPeriodDays: -1})
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "only treated products as control", call: func() error {
+			_, err := service.MeasureImpact(context.Background(), "promo-1", entities.PromotionImpactOptions{ControlProductIDs: []string{"P1"}})
+			return err
+		}, want: services.ErrNoControlProducts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+)
+
+var (
+	// ErrProductSegmentNotFound возвращается, если товар не попал в последнюю ABC-сегментацию
+	ErrProductSegmentNotFound = errors.New("product segment not found")
+
+	// ErrDiscountRecommendationNotFound возвращается, если для товара нет рекомендации по скидке
+	ErrDiscountRecommendationNotFound = errors.New("discount recommendation not found")
+)
+
+const (
+	// defaultRecommendationLimit количество рекомендаций к корзине, если ограничение не задано ни в запросе, ни в конфигурации
+	defaultRecommendationLimit = 10
+
+	// defaultLatestRecommendations количество последних рекомендаций по скидкам, если ограничение не задано
+	defaultLatestRecommendations = 1000
+)
+
+// RecommendationQueryService определяет интерфейс быстрых запросов к готовым результатам аналитики
+// для внешних сервисов: рекомендаций к корзине, ABC-сегментов и текущих скидок
+type RecommendationQueryService interface {
+	// GetProductRecommendations возвращает товары, рекомендованные к корзине по сохраненным ассоциативным правилам
+	// limit <= 0 означает ограничение по умолчанию
+	GetProductRecommendations(ctx context.Context, basketProductIDs []string, limit int) ([]entities.ProductRecommendation, error)
+
+	// GetProductSegment возвращает ABC-сегмент товара
+	GetProductSegment(ctx context.Context, productID string) (*entities.ProductSegmentation, error)
+
+	// GetDiscountRecommendation возвращает текущую рекомендацию по скидке для товара
+	GetDiscountRecommendation(ctx context.Context, productID string) (*entities.DiscountRecommendation, error)
+
+	// ListDiscountRecommendations возвращает рекомендации по скидкам категории или последние рекомендации,
+	// если категория не указана
+	ListDiscountRecommendations(ctx context.Context, category string, limit int) ([]entities.DiscountRecommendation, error)
+}
+
+// recommendationQueryService реализует интерфейс RecommendationQueryService
+type recommendationQueryService struct {
+	productRepo        repositories.ProductRepository
+	ruleRepo           repositories.AssociationRuleRepository
+	abcSegmentRepo     repositories.ABCSegmentRepository
+	recommendationRepo repositories.DiscountRecommendationRepository
+	aprioriService     AprioriService
+	maxRecommendations int
+	logger             logger.Logger
+}
+
+// NewRecommendationQueryService создает новый экземпляр сервиса запросов к результатам аналитики
+// maxRecommendations ограничивает количество рекомендаций к корзине; 0 — значение по умолчанию
+func NewRecommendationQueryService(
+	productRepo repositories.ProductRepository,
+	ruleRepo repositories.AssociationRuleRepository,
+	abcSegmentRepo repositories.ABCSegmentRepository,
+	recommendationRepo repositories.DiscountRecommendationRepository,
+	aprioriService AprioriService,
+	maxRecommendations int,
+	logger logger.Logger,
+) RecommendationQueryService {
+	if maxRecommendations <= 0 {
+		maxRecommendations = defaultRecommendationLimit
+	}
+	return &recommendationQueryService{
+		productRepo:        productRepo,
+		ruleRepo:           ruleRepo,
+		abcSegmentRepo:     abcSegmentRepo,
+		recommendationRepo: recommendationRepo,
+		aprioriService:     aprioriService,
+		maxRecommendations: maxRecommendations,
+		logger:             logger,
+	}
+}
+
+// GetProductRecommendations возвращает товары, рекомендованные к корзине по сохраненным ассоциативным правилам
+func (s *recommendationQueryService) GetProductRecommendations(ctx context.Context, basketProductIDs []string, limit int) ([]entities.ProductRecommendation, error) {
+	if len(basketProductIDs) == 0 {
+		return nil, fmt.Errorf("%w: basket must contain at least one product", ErrInvalidParameter)
+	}
+	if limit <= 0 || limit > s.maxRecommendations {
+		limit = s.maxRecommendations
+	}
+
+	basket := make([]entities.Product, 0, len(basketProductIDs))
+	var rules []entities.AssociationRule
+	seenRules := make(map[string]bool)
+
+	for _, productID := range basketProductIDs {
+		if productID == "" {
+			return nil, fmt.Errorf("%w: basket contains an empty product ID", ErrInvalidParameter)
+		}
+
+		product, err := s.productRepo.GetProductByID(ctx, productID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get product %s: %w", productID, err)
+		}
+		basket = append(basket, product)
+
+		// Правило может быть связано с несколькими товарами корзины, учитываем его один раз
+		productRules, err := s.ruleRepo.GetRulesByProduct(ctx, productID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get association rules for product %s: %w", productID, err)
+		}
+		for _, rule := range productRules {
+			key := joinItemIDs(rule.Antecedent) + "=>" + joinItemIDs(rule.Consequent)
+			if seenRules[key] {
+				continue
+			}
+			seenRules[key] = true
+			rules = append(rules, rule)
+		}
+	}
+
+	recommendations, err := s.aprioriService.GetProductRecommendations(ctx, basket, rules, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product recommendations: %w", err)
+	}
+
+	s.logger.Debug(ctx, "Рекомендации к корзине подобраны", "basket", len(basket), "rules", len(rules), "recommendations", len(recommendations))
+	return recommendations, nil
+}
+
+// GetProductSegment возвращает ABC-сегмент товара
+func (s *recommendationQueryService) GetProductSegment(ctx context.Context, productID string) (*entities.ProductSegmentation, error) {
+	if productID == "" {
+		return nil, fmt.Errorf("%w: product ID is required", ErrInvalidParameter)
+	}
+
+	segmentation, err := s.abcSegmentRepo.GetProductSegmentation(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product segmentation: %w", err)
+	}
+	if segmentation == nil {
+		return nil, ErrProductSegmentNotFound
+	}
+	return segmentation, nil
+}
+
+// GetDiscountRecommendation возвращает текущую рекомендацию по скидке для товара
+func (s *recommendationQueryService) GetDiscountRecommendation(ctx context.Context, productID string) (*entities.DiscountRecommendation, error) {
+	if productID == "" {
+		return nil, fmt.Errorf("%w: product ID is required", ErrInvalidParameter)
+	}
+
+	recommendation, err := s.recommendationRepo.GetRecommendationByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get discount recommendation: %w", err)
+	}
+	// Репозиторий возвращает пустую рекомендацию, если для товара ее нет
+	if recommendation.ProductID == "" {
+		return nil, ErrDiscountRecommendationNotFound
+	}
+	return &recommendation, nil
+}
+
+// ListDiscountRecommendations возвращает рекомендации по скидкам категории или последние рекомендации
+func (s *recommendationQueryService) ListDiscountRecommendations(ctx context.Context, category string, limit int) ([]entities.DiscountRecommendation, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: limit must not be negative", ErrInvalidParameter)
+	}
+
+	if category != "" {
+		recommendations, err := s.recommendationRepo.GetRecommendationsByCategory(ctx, category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get discount recommendations for category %s: %w", category, err)
+		}
+		return recommendations, nil
+	}
+
+	if limit == 0 {
+		limit = defaultLatestRecommendations
+	}
+	recommendations, err := s.recommendationRepo.GetLatestRecommendations(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest discount recommendations: %w", err)
+	}
+	return recommendations, nil
+}
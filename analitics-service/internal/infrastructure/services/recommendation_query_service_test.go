@@ -0,0 +1,233 @@
+// internal/infrastructure/services/recommendation_query_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryRecommendationRepository хранит рекомендации по скидкам в памяти в порядке сохранения
+type memoryRecommendationRepository struct {
+	recommendations []entities.DiscountRecommendation
+}
+
+func (r *memoryRecommendationRepository) SaveRecommendation(_ context.Context, recommendation entities.DiscountRecommendation) error {
+	r.recommendations = append(r.recommendations, recommendation)
+	return nil
+}
+
+func (r *memoryRecommendationRepository) GetRecommendationByProductID(_ context.Context, productID string) (entities.DiscountRecommendation, error) {
+	for i := len(r.recommendations) - 1; i >= 0; i-- {
+		if r.recommendations[i].ProductID == productID {
+			return r.recommendations[i], nil
+		}
+	}
+	return entities.DiscountRecommendation{}, nil
+}
+
+func (r *memoryRecommendationRepository) GetRecommendationsByCategory(_ context.Context, category string) ([]entities.DiscountRecommendation, error) {
+	var result []entities.DiscountRecommendation
+	for _, recommendation := range r.recommendations {
+		if recommendation.Category == category {
+			result = append(result, recommendation)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRecommendationRepository) GetRecommendationsBySegment(_ context.Context, segment entities.Segment) ([]entities.DiscountRecommendation, error) {
+	var result []entities.DiscountRecommendation
+	for _, recommendation := range r.recommendations {
+		if recommendation.ABCCategory == segment {
+			result = append(result, recommendation)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRecommendationRepository) GetLatestRecommendations(_ context.Context, limit int) ([]entities.DiscountRecommendation, error) {
+	var result []entities.DiscountRecommendation
+	for i := len(r.recommendations) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, r.recommendations[i])
+	}
+	return result, nil
+}
+
+func (r *memoryRecommendationRepository) GetRecommendationsByRunID(_ context.Context, runID string) ([]entities.DiscountRecommendation, error) {
+	var result []entities.DiscountRecommendation
+	for _, recommendation := range r.recommendations {
+		if recommendation.RunID == runID {
+			result = append(result, recommendation)
+		}
+	}
+	return result, nil
+}
+
+// testConfidenceRule возвращает правило антецедент -> консеквент с указанной достоверностью
+func testConfidenceRule(antecedent []string, consequent string, confidence float64) entities.AssociationRule {
+	rule := entities.AssociationRule{Consequent: []entities.Item{{ProductID: consequent}}, Confidence: confidence, Lift: 2}
+	for _, productID := range antecedent {
+		rule.Antecedent = append(rule.Antecedent, entities.Item{ProductID: productID})
+	}
+	return rule
+}
+
+// testRecommendationQueryService возвращает сервис запросов с каталогом A–F, правилами и рекомендациями по скидкам
+func testRecommendationQueryService(maxRecommendations int) services.RecommendationQueryService {
+	var products []entities.Product
+	for _, productID := range []string{"A", "B", "C", "D", "E", "F"} {
+		products = append(products, testProduct(productID, "bakery", 100))
+	}
+
+	rules := &memoryRuleRepository{rules: []entities.AssociationRule{
+		testConfidenceRule([]string{"A"}, "C", 0.8),
+		testConfidenceRule([]string{"B"}, "C", 0.6),
+		testConfidenceRule([]string{"A"}, "D", 0.5),
+		testConfidenceRule([]string{"A", "E"}, "F", 0.9),
+		testConfidenceRule([]string{"A"}, "B", 0.7),
+	}}
+
+	recommendations := &memoryRecommendationRepository{recommendations: []entities.DiscountRecommendation{
+		{ProductID: "A", Category: "bakery", OptimalDiscount: 10},
+		{Category: "bakery", OptimalDiscount: 15},
+		{ProductID: "A", Category: "bakery", OptimalDiscount: 12},
+		{Category: "coffee", OptimalDiscount: 5},
+	}}
+
+	segments := &memorySegmentRepository{segments: map[string]entities.Segment{"A": entities.SegmentA}}
+	log := logger.NewLogger("ERROR")
+	return services.NewRecommendationQueryService(&memoryProductRepository{products: products}, rules, segments,
+		recommendations, services.NewAprioriService(log), maxRecommendations, log)
+}
+
+func TestGetProductRecommendations(t *testing.T) {
+	tests := []struct {
+		name               string
+		maxRecommendations int
+		limit              int
+		want               []string
+		wantScores         []float64
+	}{
+		// C рекомендуется по двум правилам с лучшей достоверностью, B уже в корзине, для F в корзине нет E
+		{name: "default limit", want: []string{"C", "D"}, wantScores: []float64{0.8, 0.5}},
+		{name: "request limit", limit: 1, want: []string{"C"}, wantScores: []float64{0.8}},
+		{name: "limit above configured maximum", maxRecommendations: 1, limit: 5, want: []string{"C"}, wantScores: []float64{0.8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := testRecommendationQueryService(tt.maxRecommendations)
+			recommendations, err := service.GetProductRecommendations(context.Background(), []string{"A", "B"}, tt.limit)
+			if err != nil {
+				t.Fatalf("GetProductRecommendations() error = %v", err)
+			}
+
+			if len(recommendations) != len(tt.want) {
+				t.Fatalf("recommendations = %+v, want %v", recommendations, tt.want)
+			}
+			for i, recommendation := range recommendations {
+				if recommendation.Product.ID != tt.want[i] || recommendation.Score != tt.wantScores[i] {
+					t.Errorf("recommendation[%d] = %s (%.2f), want %s (%.2f)", i, recommendation.Product.ID,
+						recommendation.Score, tt.want[i], tt.wantScores[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecommendationLookups(t *testing.T) {
+	service := testRecommendationQueryService(0)
+
+	segment, err := service.GetProductSegment(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("GetProductSegment() error = %v", err)
+	}
+	if segment.ProductID != "A" || segment.Segment != entities.SegmentA {
+		t.Errorf("segment = %+v, want A in segment A", segment)
+	}
+
+	// Возвращается последняя сохраненная рекомендация товара
+	recommendation, err := service.GetDiscountRecommendation(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("GetDiscountRecommendation() error = %v", err)
+	}
+	if recommendation.OptimalDiscount != 12 {
+		t.Errorf("discount = %.2f, want 12", recommendation.OptimalDiscount)
+	}
+
+	tests := []struct {
+		name     string
+		category string
+		limit    int
+		want     []float64
+	}{
+		{name: "category", category: "bakery", want: []float64{10, 15, 12}},
+		{name: "latest with default limit", want: []float64{5, 12, 15, 10}},
+		{name: "latest with limit", limit: 2, want: []float64{5, 12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recommendations, err := service.ListDiscountRecommendations(context.Background(), tt.category, tt.limit)
+			if err != nil {
+				t.Fatalf("ListDiscountRecommendations() error = %v", err)
+			}
+			if len(recommendations) != len(tt.want) {
+				t.Fatalf("recommendations = %d, want %d", len(recommendations), len(tt.want))
+			}
+			for i, recommendation := range recommendations {
+				if recommendation.OptimalDiscount != tt.want[i] {
+					t.Errorf("recommendation[%d] discount = %.2f, want %.2f", i, recommendation.OptimalDiscount, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecommendationQueryErrors(t *testing.T) {
+	service := testRecommendationQueryService(0)
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{name: "empty basket", call: func() error {
+			_, err := service.GetProductRecommendations(context.Background(), nil, 5)
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "empty product in basket", call: func() error {
+			_, err := service.GetProductRecommendations(context.Background(), []string{"A", ""}, 5)
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "product without segment", call: func() error {
+			_, err := service.GetProductSegment(context.Background(), "B")
+			return err
+		}, want: services.ErrProductSegmentNotFound},
+		{name: "product without discount", call: func() error {
+			_, err := service.GetDiscountRecommendation(context.Background(), "B")
+			return err
+		}, want: services.ErrDiscountRecommendationNotFound},
+		{name: "empty product ID", call: func() error {
+			_, err := service.GetDiscountRecommendation(context.Background(), "")
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "negative limit", call: func() error {
+			_, err := service.ListDiscountRecommendations(context.Background(), "", -1)
+			return err
+		}, want: services.ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,14 @@
-// internal/domain/services/regression_service.go
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
 
 	"github.com/sajari/regression"
 )
@@ -18,52 +20,71 @@ var (
 	ErrRegressionFailed = errors.New("regression analysis failed")
 )
 
+// seasonalMarkdownDiscount уценка сезонного товара, продажи которого снижаются
+const seasonalMarkdownDiscount = 0.3
+
 // RegressionService определяет интерфейс для сервиса регрессионного анализа
 type RegressionService interface {
 	// AnalyzeDiscountEffect анализирует влияние скидок на продажи
-	AnalyzeDiscountEffect(productID string, period time.Duration) (*entities.DiscountEffect, error)
+	AnalyzeDiscountEffect(ctx context.Context, productID string, period time.Duration) (*entities.DiscountEffect, error)
 
 	// AnalyzeDiscountEffectByCategory анализирует влияние скидок на продажи по категории товаров
-	AnalyzeDiscountEffectByCategory(category string, period time.Duration) (*entities.DiscountEffect, error)
+	AnalyzeDiscountEffectByCategory(ctx context.Context, category string, period time.Duration) (*entities.DiscountEffect, error)
+
+	// GenerateDiscountRecommendations генерирует рекомендации по оптимальным скидкам на основе продаж за период
+	GenerateDiscountRecommendations(ctx context.Context, startDate, endDate time.Time) ([]*entities.DiscountRecommendation, error)
 
-	// GenerateDiscountRecommendations генерирует рекомендации по оптимальным скидкам
-	GenerateDiscountRecommendations() ([]*entities.DiscountRecommendation, error)
+	// GenerateDiscountRecommendationsForTransactions генерирует рекомендации по переданным транзакциям периода,
+	// например очищенным проверкой качества данных
+	GenerateDiscountRecommendationsForTransactions(ctx context.Context, startDate, endDate time.Time, transactions []entities.Transaction) ([]*entities.DiscountRecommendation, error)
 
 	// AnalyzeABTestResults анализирует результаты A/B тестов для оптимизации скидок
-	AnalyzeABTestResults(testIDs []string) (*entities.ABTestAnalysis, error)
+	AnalyzeABTestResults(ctx context.Context, testIDs []string) (*entities.ABTestAnalysis, error)
 }
 
 // regressionServiceImpl реализация сервиса регрессионного анализа
 type regressionServiceImpl struct {
-	transactionRepo entities.TransactionRepository
-	productRepo     entities.ProductRepository
-	abTestRepo      entities.ABTestRepository
+	transactionRepo repositories.TransactionRepository
+	productRepo     repositories.ProductRepository
+	abTestRepo      repositories.ABTestRepository
+	abcSegmentRepo  repositories.ABCSegmentRepository
+	lifecycleRepo   repositories.ProductLifecycleRepository
+
+	// Необязательный сервис каннибализации: при наличии рекомендации по товарам дополняются чистым эффектом
+	cannibalizationService CannibalizationService
 }
 
 // NewRegressionService создает новый экземпляр сервиса регрессионного анализа
 func NewRegressionService(
-	transactionRepo entities.TransactionRepository,
-	productRepo entities.ProductRepository,
-	abTestRepo entities.ABTestRepository,
+	transactionRepo repositories.TransactionRepository,
+	productRepo repositories.ProductRepository,
+	abTestRepo repositories.ABTestRepository,
+	abcSegmentRepo repositories.ABCSegmentRepository,
+	lifecycleRepo repositories.ProductLifecycleRepository,
+	cannibalizationService CannibalizationService,
 ) RegressionService {
 	return &regressionServiceImpl{
-		transactionRepo: transactionRepo,
-		productRepo:     productRepo,
-		abTestRepo:      abTestRepo,
+		transactionRepo:        transactionRepo,
+		productRepo:            productRepo,
+		abTestRepo:             abTestRepo,
+		abcSegmentRepo:         abcSegmentRepo,
+		lifecycleRepo:          lifecycleRepo,
+		cannibalizationService: cannibalizationService,
 	}
 }
 
 // AnalyzeDiscountEffect анализирует влияние скидок на продажи
-func (s *regressionServiceImpl) AnalyzeDiscountEffect(productID string, period time.Duration) (*entities.DiscountEffect, error) {
+func (s *regressionServiceImpl) AnalyzeDiscountEffect(ctx context.Context, productID string, period time.Duration) (*entities.DiscountEffect, error) {
 	// Получаем транзакции за указанный период
 	endDate := time.Now()
 	startDate := endDate.Add(-period)
 
 	// Получаем все транзакции, содержащие данный товар
-	transactions, err := s.transactionRepo.FindByProductAndDateRange(productID, startDate, endDate)
+	productTransactions, err := s.transactionRepo.GetTransactionsWithProduct(ctx, productID, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
 	}
+	transactions := transactionPointers(productTransactions)
 
 	if len(transactions) < 30 {
 		return nil, ErrInsufficientData
@@ -106,13 +127,13 @@ func (s *regressionServiceImpl) AnalyzeDiscountEffect(productID string, period t
 
 	// Оцениваем оптимальный уровень скидки на основе модели
 	// Максимизируем доход: Revenue = Price * (1 - Discount) * Sales, где Sales зависит от Discount
-	optimalDiscount := s.findOptimalDiscount(r, productID)
+	optimalDiscount := s.findOptimalDiscount(ctx, r, productID)
 
 	// Формируем результат анализа
 	result := &entities.DiscountEffect{
 		ProductID:         productID,
 		LiftFactor:        discountCoeff,
-		RSqaured:          r.R2,
+		RSquared:          r.R2,
 		OptimalDiscount:   optimalDiscount,
 		AnalysisTimestamp: time.Now(),
 		Coefficients: map[string]float64{
@@ -139,17 +160,28 @@ func boolToFloat(b bool) float64 {
 }
 
 // AnalyzeDiscountEffectByCategory анализирует влияние скидок на продажи по категории товаров
-func (s *regressionServiceImpl) AnalyzeDiscountEffectByCategory(category string, period time.Duration) (*entities.DiscountEffect, error) {
-	// Получаем транзакции за указанный период для категории
+func (s *regressionServiceImpl) AnalyzeDiscountEffectByCategory(ctx context.Context, category string, period time.Duration) (*entities.DiscountEffect, error) {
 	endDate := time.Now()
 	startDate := endDate.Add(-period)
+	transactions, err := s.categoryTransactions(ctx, category, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return s.analyzeCategoryEffect(category, transactions, startDate, endDate)
+}
 
-	// Получаем все транзакции, содержащие товары из этой категории
-	transactions, err := s.transactionRepo.FindByCategoryAndDateRange(category, startDate, endDate)
+// categoryTransactions возвращает из репозитория транзакции с товарами категории за период
+func (s *regressionServiceImpl) categoryTransactions(ctx context.Context, category string, startDate, endDate time.Time) ([]*entities.Transaction, error) {
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
 	}
+	return transactionsWithCategory(transactions, category), nil
+}
 
+// analyzeCategoryEffect анализирует влияние скидок на продажи категории по транзакциям
+// с товарами категории за фиксированный диапазон дат
+func (s *regressionServiceImpl) analyzeCategoryEffect(category string, transactions []*entities.Transaction, startDate, endDate time.Time) (*entities.DiscountEffect, error) {
 	if len(transactions) < 30 {
 		return nil, ErrInsufficientData
 	}
@@ -182,8 +214,7 @@ func (s *regressionServiceImpl) AnalyzeDiscountEffectByCategory(category string,
 	}
 
 	// Запускаем регрессионный анализ
-	err = r.Run()
-	if err != nil {
+	if err := r.Run(); err != nil {
 		return nil, fmt.Errorf("regression analysis failed: %w", err)
 	}
 
@@ -197,7 +228,7 @@ func (s *regressionServiceImpl) AnalyzeDiscountEffectByCategory(category string,
 	result := &entities.DiscountEffect{
 		Category:          category,
 		LiftFactor:        discountCoeff,
-		RSqaured:          r.R2,
+		RSquared:          r.R2,
 		OptimalDiscount:   optimalDiscount,
 		AnalysisTimestamp: time.Now(),
 		Coefficients: map[string]float64{
@@ -217,19 +248,41 @@ func (s *regressionServiceImpl) AnalyzeDiscountEffectByCategory(category string,
 }
 
 // GenerateDiscountRecommendations генерирует рекомендации по оптимальным скидкам
-func (s *regressionServiceImpl) GenerateDiscountRecommendations() ([]*entities.DiscountRecommendation, error) {
+func (s *regressionServiceImpl) GenerateDiscountRecommendations(ctx context.Context, startDate, endDate time.Time) ([]*entities.DiscountRecommendation, error) {
+	// Транзакции периода читаются один раз и фильтруются по категориям
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+	return s.GenerateDiscountRecommendationsForTransactions(ctx, startDate, endDate, transactions)
+}
+
+// GenerateDiscountRecommendationsForTransactions генерирует рекомендации по оптимальным скидкам по переданным транзакциям
+func (s *regressionServiceImpl) GenerateDiscountRecommendationsForTransactions(ctx context.Context, startDate, endDate time.Time, transactions []entities.Transaction) ([]*entities.DiscountRecommendation, error) {
+	if !startDate.Before(endDate) {
+		return nil, fmt.Errorf("%w: start date must be before end date", ErrInvalidParameter)
+	}
+
 	// Получаем все категории товаров
-	categories, err := s.productRepo.GetAllCategories()
+	products, err := s.productRepo.GetAllProducts(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve categories: %w", err)
 	}
+	categories := productCategories(products)
+
+	// Получаем стадии жизненного цикла товаров из последней классификации
+	lifecycles, err := s.lifecycleRepo.GetLatestLifecycles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product lifecycles: %w", err)
+	}
 
 	recommendations := make([]*entities.DiscountRecommendation, 0, len(categories))
 
 	// Для каждой категории проводим анализ и генерируем рекомендации
 	for _, category := range categories {
-		// Анализируем влияние скидок за последние 3 месяца
-		effect, err := s.AnalyzeDiscountEffectByCategory(category, 90*24*time.Hour)
+		// Анализируем влияние скидок за указанный период
+		categoryTransactions := transactionsWithCategory(transactions, category)
+		effect, err := s.analyzeCategoryEffect(category, categoryTransactions, startDate, endDate)
 		// Если недостаточно данных, пропускаем категорию
 		if errors.Is(err, ErrInsufficientData) {
 			continue
@@ -239,41 +292,121 @@ func (s *regressionServiceImpl) GenerateDiscountRecommendations() ([]*entities.D
 		}
 
 		// Получаем прибыльность категории
-		abcCategory, err := s.productRepo.GetCategoryABCClassification(category)
+		abcCategory, err := s.categoryABCClassification(ctx, category)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get ABC classification for category %s: %w", category, err)
 		}
 
 		// Формируем рекомендацию на основе анализа и категории ABC
 		recommendation := &entities.DiscountRecommendation{
+			AnalysisMetadata: entities.AnalysisMetadata{
+				AnalysisDate: time.Now(),
+				PeriodStart:  startDate,
+				PeriodEnd:    endDate,
+			},
 			Category:        category,
 			OptimalDiscount: effect.OptimalDiscount,
 			LiftFactor:      effect.LiftFactor,
 			ABCCategory:     abcCategory,
-			Confidence:      effect.RSqaured, // Используем R² как меру уверенности в рекомендации
-			GeneratedAt:     time.Now(),
+			Confidence:      effect.RSquared, // Используем R² как меру уверенности в рекомендации
 		}
 
 		// Корректируем рекомендацию в зависимости от категории ABC
 		s.adjustRecommendationByABCCategory(recommendation)
 
+		// Корректируем рекомендацию в зависимости от преобладающей стадии жизненного цикла категории
+		if stage, ok := dominantLifecycleStage(lifecycles, category); ok {
+			recommendation.LifecycleStage = stage
+			s.adjustRecommendationByLifecycle(recommendation)
+		}
+
 		recommendations = append(recommendations, recommendation)
+
+		// Для сезонных товаров категории на спаде продаж добавляем отдельные рекомендации по уценке
+		for _, lifecycle := range lifecycles {
+			if lifecycle.Category != category || lifecycle.Stage != entities.LifecycleDeclining || !lifecycle.Seasonal {
+				continue
+			}
+
+			markdown := &entities.DiscountRecommendation{
+				AnalysisMetadata: recommendation.AnalysisMetadata,
+				ProductID:        lifecycle.ProductID,
+				Category:         category,
+				OptimalDiscount:  seasonalMarkdownDiscount,
+				LiftFactor:       effect.LiftFactor,
+				ABCCategory:      lifecycle.ABCCategory,
+				Confidence:       effect.RSquared,
+				AdjustmentReason: "Уценка сезонного товара на спаде продаж",
+				LifecycleStage:   lifecycle.Stage,
+			}
+			s.adjustRecommendationByABCCategory(markdown)
+
+			// Уценка может каннибализировать продажи заменителей, поэтому учитываем чистый эффект
+			if err := s.applyNetEffect(ctx, markdown, transactions); err != nil {
+				return nil, err
+			}
+
+			recommendations = append(recommendations, markdown)
+		}
 	}
 
 	return recommendations, nil
 }
 
+// applyNetEffect дополняет рекомендацию по товару чистым эффектом с учетом заменителей и дополняющих товаров
+// Эффект оценивается по периоду продаж товара со скидкой; без таких продаж рекомендация не меняется
+func (s *regressionServiceImpl) applyNetEffect(ctx context.Context, recommendation *entities.DiscountRecommendation, transactions []entities.Transaction) error {
+	if s.cannibalizationService == nil {
+		return nil
+	}
+
+	promoStart, promoEnd, ok := discountedSalesWindow(transactions, recommendation.ProductID)
+	if !ok {
+		return nil
+	}
+	if err := s.cannibalizationService.ApplyNetEffect(ctx, recommendation, promoStart, promoEnd); err != nil {
+		return fmt.Errorf("failed to apply net effect for product %s: %w", recommendation.ProductID, err)
+	}
+	return nil
+}
+
+// discountedSalesWindow возвращает границы дней, в которые товар продавался со скидкой
+func discountedSalesWindow(transactions []entities.Transaction, productID string) (time.Time, time.Time, bool) {
+	var first, last time.Time
+	for _, transaction := range transactions {
+		for _, item := range transaction.Items {
+			if item.ProductID != productID || item.DiscountPct <= 0 {
+				continue
+			}
+			if first.IsZero() || transaction.Date.Before(first) {
+				first = transaction.Date
+			}
+			if transaction.Date.After(last) {
+				last = transaction.Date
+			}
+		}
+	}
+	if first.IsZero() {
+		return time.Time{}, time.Time{}, false
+	}
+	return first.Truncate(24 * time.Hour), last.Truncate(24 * time.Hour).Add(24 * time.Hour), true
+}
+
 // AnalyzeABTestResults анализирует результаты A/B тестов для оптимизации скидок
-func (s *regressionServiceImpl) AnalyzeABTestResults(testIDs []string) (*entities.ABTestAnalysis, error) {
+func (s *regressionServiceImpl) AnalyzeABTestResults(ctx context.Context, testIDs []string) (*entities.ABTestAnalysis, error) {
+	if s.abTestRepo == nil {
+		return nil, fmt.Errorf("%w: A/B test repository is not configured", ErrInvalidParameter)
+	}
+
 	tests := make([]*entities.ABTestResult, 0, len(testIDs))
 
 	// Получаем результаты всех указанных тестов
 	for _, testID := range testIDs {
-		test, err := s.abTestRepo.GetByID(testID)
+		test, err := s.abTestRepo.GetTestResultByID(ctx, testID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve test %s: %w", testID, err)
 		}
-		tests = append(tests, test)
+		tests = append(tests, &test)
 	}
 
 	if len(tests) < 3 {
@@ -322,7 +455,7 @@ func (s *regressionServiceImpl) AnalyzeABTestResults(testIDs []string) (*entitie
 
 	// Оцениваем оптимальный уровень скидки на основе модели
 	// Предполагаем среднюю цену и среднюю длительность теста
-	avgBasePrice, _ := s.getAverageBasePrice()
+	avgBasePrice, _ := s.getAverageBasePrice(tests)
 	optimalDiscount := s.findOptimalDiscountFromABTests(r, avgBasePrice, 14.0) // 14 дней стандартная длительность теста
 
 	// Формируем результат анализа
@@ -332,7 +465,7 @@ func (s *regressionServiceImpl) AnalyzeABTestResults(testIDs []string) (*entitie
 		BasePriceCoeff:    r.Coeff(1),
 		DurationCoeff:     r.Coeff(2),
 		InterceptCoeff:    r.Coeff(3),
-		RSqaured:          r.R2,
+		RSquared:          r.R2,
 		OptimalDiscount:   optimalDiscount,
 		AnalysisTimestamp: time.Now(),
 		Recommendations: []string{
@@ -392,7 +525,7 @@ func (s *regressionServiceImpl) aggregateTransactionsByDay(transactions []*entit
 
 		// Находим интересующий нас товар в транзакции
 		for _, item := range tx.Items {
-			if item.ID == productID {
+			if item.ProductID == productID {
 				daily.Sales += float64(item.Quantity)
 				daily.TotalPrice += item.Price * float64(item.Quantity)
 
@@ -422,6 +555,66 @@ func (s *regressionServiceImpl) aggregateTransactionsByDay(transactions []*entit
 	return result
 }
 
+// transactionPointers возвращает указатели на транзакции среза
+func transactionPointers(transactions []entities.Transaction) []*entities.Transaction {
+	result := make([]*entities.Transaction, len(transactions))
+	for i := range transactions {
+		result[i] = &transactions[i]
+	}
+	return result
+}
+
+// productCategories возвращает отсортированный список категорий товаров без повторов
+func productCategories(products []entities.Product) []string {
+	seen := make(map[string]bool)
+	var categories []string
+	for _, product := range products {
+		if product.Category == "" || seen[product.Category] {
+			continue
+		}
+		seen[product.Category] = true
+		categories = append(categories, product.Category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// categoryABCClassification возвращает преобладающий ABC-сегмент товаров категории по последней сегментации
+// или пустой сегмент, если товары категории еще не сегментированы; при равенстве выбирается более высокий сегмент
+func (s *regressionServiceImpl) categoryABCClassification(ctx context.Context, category string) (entities.Segment, error) {
+	segmentation, err := s.abcSegmentRepo.GetSegmentationByCategory(ctx, category)
+	if err != nil {
+		return "", err
+	}
+
+	counts := make(map[entities.Segment]int)
+	for _, product := range segmentation {
+		counts[product.Segment]++
+	}
+
+	var dominant entities.Segment
+	for _, segment := range []entities.Segment{entities.SegmentA, entities.SegmentB, entities.SegmentC} {
+		if counts[segment] > counts[dominant] {
+			dominant = segment
+		}
+	}
+	return dominant, nil
+}
+
+// transactionsWithCategory возвращает транзакции, содержащие товары категории
+func transactionsWithCategory(transactions []entities.Transaction, category string) []*entities.Transaction {
+	var result []*entities.Transaction
+	for i := range transactions {
+		for _, item := range transactions[i].Items {
+			if item.Category == category {
+				result = append(result, &transactions[i])
+				break
+			}
+		}
+	}
+	return result
+}
+
 // aggregateCategoryTransactionsByDay агрегирует транзакции по категории и дням
 func (s *regressionServiceImpl) aggregateCategoryTransactionsByDay(transactions []*entities.Transaction, category string) []*entities.DailyTransactionData {
 	// Реализация аналогична aggregateTransactionsByDay, но для категории
@@ -465,7 +658,7 @@ func (s *regressionServiceImpl) aggregateCategoryTransactionsByDay(transactions
 				}
 
 				daily.TotalTx++
-				daily.ProductIDs[item.ID] = struct{}{}
+				daily.ProductIDs[item.ProductID] = struct{}{}
 			}
 		}
 	}
@@ -490,10 +683,9 @@ func (s *regressionServiceImpl) aggregateCategoryTransactionsByDay(transactions
 }
 
 // findOptimalDiscount находит оптимальный уровень скидки для максимизации дохода
-func (s *regressionServiceImpl) findOptimalDiscount(r *regression.Regression, productID string) float64 {
-	// Находим базовую цену товара
-	product, err := s.productRepo.GetByID(productID)
-	if err != nil {
+func (s *regressionServiceImpl) findOptimalDiscount(ctx context.Context, r *regression.Regression, productID string) float64 {
+	// Проверяем, что товар известен
+	if _, err := s.productRepo.GetProductByID(ctx, productID); err != nil {
 		return 0.15 // Если не удалось получить товар, возвращаем стандартное значение
 	}
 
@@ -579,6 +771,24 @@ func (s *regressionServiceImpl) adjustRecommendationByABCCategory(rec *entities.
 	}
 }
 
+// adjustRecommendationByLifecycle корректирует рекомендацию на основе стадии жизненного цикла
+func (s *regressionServiceImpl) adjustRecommendationByLifecycle(rec *entities.DiscountRecommendation) {
+	switch rec.LifecycleStage {
+	case entities.LifecycleGrowing:
+		// Растущий спрос не требует стимулирования, скидка только снижает выручку
+		if rec.OptimalDiscount > 0.1 {
+			rec.OptimalDiscount = 0.1
+			rec.AdjustmentReason = "Скидка ограничена для категории с растущими продажами"
+		}
+	case entities.LifecycleDeclining:
+		// Категории на спаде уцениваем, кроме высокодоходной категории A
+		if rec.OptimalDiscount < 0.15 && rec.ABCCategory != entities.SegmentA {
+			rec.OptimalDiscount = 0.15
+			rec.AdjustmentReason = "Скидка увеличена для категории со снижающимися продажами"
+		}
+	}
+}
+
 // findOptimalDiscountFromABTests находит оптимальный уровень скидки на основе A/B тестов
 func (s *regressionServiceImpl) findOptimalDiscountFromABTests(r *regression.Regression, basePrice, duration float64) float64 {
 	// Функция для расчета прогнозируемого Lift при заданной скидке
@@ -637,53 +847,36 @@ func (s *regressionServiceImpl) getHolidayDates() map[string]struct{} {
 	return holidays
 }
 
-// getAverageBasePriceForTest возвращает среднюю базовую цену товаров в тесте
+// getAverageBasePriceForTest возвращает базовую цену теста — средний чек контрольной группы без скидки
+// Результат A/B теста не хранит список товаров, поэтому цена берется из статистики групп
 func (s *regressionServiceImpl) getAverageBasePriceForTest(test *entities.ABTestResult) (float64, error) {
 	if test == nil {
 		return 0, ErrInvalidParameter
 	}
 
-	var totalPrice float64
-	var totalProducts int
-
-	// Получаем все товары, участвовавшие в тесте
-	products, err := s.productRepo.GetProductsByTestID(test.ID)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get test products: %w", err)
-	}
-
-	if len(products) == 0 {
+	if test.ControlGroup.AvgPurchase <= 0 {
 		return 0, ErrInsufficientData
 	}
 
-	// Суммируем базовые цены всех товаров
-	for _, product := range products {
-		totalPrice += product.BasePrice
-		totalProducts++
-	}
-
-	// Вычисляем среднюю цену
-	avgPrice := totalPrice / float64(totalProducts)
-
-	return avgPrice, nil
+	return test.ControlGroup.AvgPurchase, nil
 }
 
-// getAverageBasePrice возвращает среднюю базовую цену всех товаров
-func (s *regressionServiceImpl) getAverageBasePrice() (float64, error) {
-	// Получаем все активные товары
-	products, err := s.productRepo.GetAllActive()
-	if err != nil {
-		return 0, fmt.Errorf("failed to get products: %w", err)
+// getAverageBasePrice возвращает среднюю базовую цену по тестам
+func (s *regressionServiceImpl) getAverageBasePrice(tests []*entities.ABTestResult) (float64, error) {
+	var totalPrice float64
+	var testsWithPrice int
+	for _, test := range tests {
+		basePrice, err := s.getAverageBasePriceForTest(test)
+		if err != nil {
+			continue
+		}
+		totalPrice += basePrice
+		testsWithPrice++
 	}
 
-	if len(products) == 0 {
+	if testsWithPrice == 0 {
 		return 0, ErrInsufficientData
 	}
 
-	var totalPrice float64
-	for _, product := range products {
-		totalPrice += product.BasePrice
-	}
-
-	return totalPrice / float64(len(products)), nil
+	return totalPrice / float64(testsWithPrice), nil
 }
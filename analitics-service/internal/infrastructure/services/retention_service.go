@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+)
+
+// retentionWarmupPeriods количество периодов истории перед началом анализа,
+// клиенты с покупками в этих периодах не считаются новыми
+const retentionWarmupPeriods = 12
+
+// RetentionService определяет интерфейс аналитики удержания клиентов
+type RetentionService interface {
+	// ComputeMetrics рассчитывает и сохраняет метрики удержания для каждого периода диапазона
+	ComputeMetrics(ctx context.Context, period entities.TimeRange, startDate, endDate time.Time) ([]entities.RetentionMetrics, error)
+
+	// BuildCohortTriangle строит треугольник удержания когорт, привлеченных в диапазоне
+	BuildCohortTriangle(ctx context.Context, period entities.TimeRange, startDate, endDate time.Time) (*entities.RetentionTriangle, error)
+
+	// GetTrend возвращает сохраненные метрики удержания за диапазон
+	GetTrend(ctx context.Context, period entities.TimeRange, startDate, endDate time.Time) ([]entities.RetentionMetrics, error)
+}
+
+// retentionService реализует интерфейс RetentionService
+type retentionService struct {
+	transactionRepo repositories.TransactionRepository
+	metricsRepo     repositories.RetentionMetricsRepository
+	logger          logger.Logger
+}
+
+// customerActivity содержит активность клиентов по периодам
+type customerActivity struct {
+	starts      []time.Time         // Начала периодов, включая периоды разогрева
+	firstPeriod map[string]int      // Индекс периода первой покупки клиента
+	active      []map[string]int    // Количество покупок клиента в каждом периоде
+	cumulative  map[string][]int    // Накопленное количество покупок клиента к концу периода
+	firstIndex  int                 // Индекс первого анализируемого периода
+	customers   map[string]struct{} // Все клиенты с покупками
+}
+
+// NewRetentionService создает новый экземпляр сервиса аналитики удержания
+func NewRetentionService(
+	transactionRepo repositories.TransactionRepository,
+	metricsRepo repositories.RetentionMetricsRepository,
+	logger logger.Logger,
+) RetentionService {
+	return &retentionService{
+		transactionRepo: transactionRepo,
+		metricsRepo:     metricsRepo,
+		logger:          logger,
+	}
+}
+
+// ComputeMetrics рассчитывает и сохраняет метрики удержания для каждого периода диапазона
+func (s *retentionService) ComputeMetrics(ctx context.Context, period entities.TimeRange, startDate, endDate time.Time) ([]entities.RetentionMetrics, error) {
+	activity, err := s.loadActivity(ctx, period, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make([]entities.RetentionMetrics, 0, len(activity.starts)-activity.firstIndex)
+	for i := activity.firstIndex; i < len(activity.starts); i++ {
+		current := activity.active[i]
+		previous := activity.active[i-1]
+
+		m := entities.RetentionMetrics{
+			Period:          period,
+			PeriodStart:     activity.starts[i],
+			PeriodEnd:       period.Advance(activity.starts[i], 1),
+			ActiveCustomers: len(current),
+		}
+
+		retained := 0
+		for customerID := range previous {
+			if _, ok := current[customerID]; ok {
+				retained++
+			}
+		}
+		m.LostCustomers = len(previous) - retained
+		if len(previous) > 0 {
+			m.RetentionRate = float64(retained) / float64(len(previous))
+			m.ChurnRate = float64(m.LostCustomers) / float64(len(previous))
+		}
+
+		// Повторными считаются клиенты периода, у которых к его концу не меньше двух покупок
+		repeat := 0
+		for customerID := range current {
+			if activity.firstPeriod[customerID] == i {
+				m.NewCustomers++
+			}
+			if activity.cumulative[customerID][i] >= 2 {
+				repeat++
+			}
+		}
+		if len(current) > 0 {
+			m.RepeatPurchaseRate = float64(repeat) / float64(len(current))
+		}
+
+		if err := s.metricsRepo.SaveMetrics(ctx, m); err != nil {
+			return nil, fmt.Errorf("failed to save retention metrics for %s: %w", m.PeriodStart.Format("2006-01-02"), err)
+		}
+		metrics = append(metrics, m)
+	}
+
+	s.logger.Info(ctx, "Рассчитаны метрики удержания", "period", period, "periods", len(metrics),
+		"customers", len(activity.customers))
+	return metrics, nil
+}
+
+// BuildCohortTriangle строит треугольник удержания когорт, привлеченных в диапазоне
+func (s *retentionService) BuildCohortTriangle(ctx context.Context, period entities.TimeRange, startDate, endDate time.Time) (*entities.RetentionTriangle, error) {
+	activity, err := s.loadActivity(ctx, period, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	cohorts := make(map[int][]string)
+	for customerID, first := range activity.firstPeriod {
+		if first >= activity.firstIndex {
+			cohorts[first] = append(cohorts[first], customerID)
+		}
+	}
+
+	last := len(activity.starts) - 1
+	triangle := &entities.RetentionTriangle{
+		Period:           period,
+		StartDate:        activity.starts[activity.firstIndex],
+		EndDate:          period.Advance(activity.starts[last], 1),
+		AverageRetention: make([]float64, last-activity.firstIndex+1),
+		GeneratedAt:      time.Now(),
+	}
+
+	retainedByOffset := make([]int, len(triangle.AverageRetention))
+	sizeByOffset := make([]int, len(triangle.AverageRetention))
+
+	for c := activity.firstIndex; c <= last; c++ {
+		members := cohorts[c]
+		row := entities.CohortRow{
+			CohortStart:    activity.starts[c],
+			Size:           len(members),
+			Retained:       make([]int, last-c+1),
+			RetentionRates: make([]float64, last-c+1),
+		}
+
+		for offset := range row.Retained {
+			for _, customerID := range members {
+				if _, ok := activity.active[c+offset][customerID]; ok {
+					row.Retained[offset]++
+				}
+			}
+			if row.Size > 0 {
+				row.RetentionRates[offset] = float64(row.Retained[offset]) / float64(row.Size)
+			}
+			retainedByOffset[offset] += row.Retained[offset]
+			sizeByOffset[offset] += row.Size
+		}
+
+		triangle.Cohorts = append(triangle.Cohorts, row)
+	}
+
+	for offset := range triangle.AverageRetention {
+		if sizeByOffset[offset] > 0 {
+			triangle.AverageRetention[offset] = float64(retainedByOffset[offset]) / float64(sizeByOffset[offset])
+		}
+	}
+
+	return triangle, nil
+}
+
+// GetTrend возвращает сохраненные метрики удержания за диапазон
+func (s *retentionService) GetTrend(ctx context.Context, period entities.TimeRange, startDate, endDate time.Time) ([]entities.RetentionMetrics, error) {
+	if !period.IsValid() {
+		return nil, fmt.Errorf("%w: invalid period %s", ErrInvalidParameter, period)
+	}
+
+	history, err := s.metricsRepo.GetMetricsHistory(ctx, period, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve retention metrics history: %w", err)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].PeriodStart.Before(history[j].PeriodStart)
+	})
+	return history, nil
+}
+
+// loadActivity загружает транзакции с периодами разогрева и раскладывает покупки клиентов по периодам
+func (s *retentionService) loadActivity(ctx context.Context, period entities.TimeRange, startDate, endDate time.Time) (*customerActivity, error) {
+	if !period.IsValid() {
+		return nil, fmt.Errorf("%w: invalid period %s", ErrInvalidParameter, period)
+	}
+	if !startDate.Before(endDate) {
+		return nil, fmt.Errorf("%w: start date must be before end date", ErrInvalidParameter)
+	}
+
+	rangeStart := period.Truncate(startDate)
+	warmupStart := period.Advance(rangeStart, -retentionWarmupPeriods)
+
+	activity := &customerActivity{
+		firstPeriod: make(map[string]int),
+		cumulative:  make(map[string][]int),
+		customers:   make(map[string]struct{}),
+		firstIndex:  retentionWarmupPeriods,
+	}
+	for start := warmupStart; start.Before(endDate); start = period.Advance(start, 1) {
+		activity.starts = append(activity.starts, start)
+		activity.active = append(activity.active, make(map[string]int))
+	}
+
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, warmupStart, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+	if len(transactions) == 0 {
+		return nil, ErrInsufficientData
+	}
+
+	for _, transaction := range transactions {
+		index := sort.Search(len(activity.starts), func(i int) bool {
+			return activity.starts[i].After(transaction.Date)
+		}) - 1
+		if index < 0 {
+			continue
+		}
+
+		customerID := transaction.CustomerID
+		activity.active[index][customerID]++
+		activity.customers[customerID] = struct{}{}
+		if first, ok := activity.firstPeriod[customerID]; !ok || index < first {
+			activity.firstPeriod[customerID] = index
+		}
+	}
+
+	for customerID := range activity.customers {
+		counts := make([]int, len(activity.starts))
+		total := 0
+		for i := range activity.starts {
+			total += activity.active[i][customerID]
+			counts[i] = total
+		}
+		activity.cumulative[customerID] = counts
+	}
+
+	return activity, nil
+}
@@ -0,0 +1,240 @@
+// internal/infrastructure/services/retention_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// memoryRetentionRepository хранит метрики удержания в памяти от новых к старым,
+// чтобы тренд проверялся на сортировку по началу периода
+type memoryRetentionRepository struct {
+	metrics []entities.RetentionMetrics
+}
+
+func (r *memoryRetentionRepository) SaveMetrics(_ context.Context, metrics entities.RetentionMetrics) error {
+	r.metrics = append([]entities.RetentionMetrics{metrics}, r.metrics...)
+	return nil
+}
+
+func (r *memoryRetentionRepository) GetMetricsByPeriod(_ context.Context, period entities.TimeRange, date time.Time) (entities.RetentionMetrics, error) {
+	for _, metrics := range r.metrics {
+		if metrics.Period == period && metrics.PeriodStart.Equal(period.Truncate(date)) {
+			return metrics, nil
+		}
+	}
+	return entities.RetentionMetrics{}, nil
+}
+
+func (r *memoryRetentionRepository) GetMetricsHistory(_ context.Context, period entities.TimeRange, startDate, endDate time.Time) ([]entities.RetentionMetrics, error) {
+	var result []entities.RetentionMetrics
+	for _, metrics := range r.metrics {
+		if metrics.Period == period && !metrics.PeriodStart.Before(startDate) && metrics.PeriodStart.Before(endDate) {
+			result = append(result, metrics)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRetentionRepository) GetLatestMetrics(_ context.Context, period entities.TimeRange) (entities.RetentionMetrics, error) {
+	for _, metrics := range r.metrics {
+		if metrics.Period == period {
+			return metrics, nil
+		}
+	}
+	return entities.RetentionMetrics{}, nil
+}
+
+// testRetentionService возвращает сервис с покупками клиентов по месяцам:
+// A — ноябрь 2023, январь и март; B — дважды в январе и в феврале; C — январь; D — дважды в феврале и в марте; E — март
+func testRetentionService() (services.RetentionService, *memoryRetentionRepository) {
+	purchase := func(id, customerID string, year int, month time.Month, day int) entities.Transaction {
+		transaction := testTransaction(id, time.Date(year, month, day, 12, 0, 0, 0, time.UTC), 100, "P1")
+		transaction.CustomerID = customerID
+		return transaction
+	}
+
+	transactions := &memoryTransactionRepository{transactions: []entities.Transaction{
+		purchase("T1", "A", 2023, time.November, 15),
+		purchase("T2", "A", 2024, time.January, 10),
+		purchase("T3", "A", 2024, time.March, 5),
+		purchase("T4", "B", 2024, time.January, 3),
+		purchase("T5", "B", 2024, time.January, 20),
+		purchase("T6", "B", 2024, time.February, 14),
+		purchase("T7", "C", 2024, time.January, 31),
+		purchase("T8", "D", 2024, time.February, 1),
+		purchase("T9", "D", 2024, time.February, 29),
+		purchase("T10", "D", 2024, time.March, 31),
+		purchase("T11", "E", 2024, time.March, 15),
+	}}
+
+	metrics := &memoryRetentionRepository{}
+	return services.NewRetentionService(transactions, metrics, logger.NewLogger("ERROR")), metrics
+}
+
+func TestComputeRetentionMetrics(t *testing.T) {
+	start, end := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	service, repo := testRetentionService()
+
+	metrics, err := service.ComputeMetrics(context.Background(), entities.Monthly, start, end)
+	if err != nil {
+		t.Fatalf("ComputeMetrics() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		want      entities.RetentionMetrics
+		wantStart time.Time
+	}{
+		{
+			// A покупал до начала анализа, поэтому новым не считается; в декабре покупок не было
+			name:      "january",
+			wantStart: start,
+			want:      entities.RetentionMetrics{ActiveCustomers: 3, NewCustomers: 2, RepeatPurchaseRate: 2.0 / 3},
+		},
+		{
+			name:      "february",
+			wantStart: start.AddDate(0, 1, 0),
+			want: entities.RetentionMetrics{ActiveCustomers: 2, NewCustomers: 1, LostCustomers: 2,
+				RetentionRate: 1.0 / 3, ChurnRate: 2.0 / 3, RepeatPurchaseRate: 1},
+		},
+		{
+			name:      "march",
+			wantStart: start.AddDate(0, 2, 0),
+			want: entities.RetentionMetrics{ActiveCustomers: 3, NewCustomers: 1, LostCustomers: 1,
+				RetentionRate: 0.5, ChurnRate: 0.5, RepeatPurchaseRate: 2.0 / 3},
+		},
+	}
+
+	if len(metrics) != len(tests) {
+		t.Fatalf("metrics = %d periods, want %d", len(metrics), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := metrics[i]
+			if !got.PeriodStart.Equal(tt.wantStart) || !got.PeriodEnd.Equal(tt.wantStart.AddDate(0, 1, 0)) {
+				t.Errorf("period = %s..%s, want month from %s", got.PeriodStart, got.PeriodEnd, tt.wantStart)
+			}
+			if got.ActiveCustomers != tt.want.ActiveCustomers || got.NewCustomers != tt.want.NewCustomers ||
+				got.LostCustomers != tt.want.LostCustomers {
+				t.Errorf("active/new/lost = %d/%d/%d, want %d/%d/%d", got.ActiveCustomers, got.NewCustomers,
+					got.LostCustomers, tt.want.ActiveCustomers, tt.want.NewCustomers, tt.want.LostCustomers)
+			}
+			rates := []struct {
+				name      string
+				got, want float64
+			}{
+				{name: "retention", got: got.RetentionRate, want: tt.want.RetentionRate},
+				{name: "churn", got: got.ChurnRate, want: tt.want.ChurnRate},
+				{name: "repeat purchase", got: got.RepeatPurchaseRate, want: tt.want.RepeatPurchaseRate},
+			}
+			for _, rate := range rates {
+				if math.Abs(rate.got-rate.want) > 1e-9 {
+					t.Errorf("%s rate = %.4f, want %.4f", rate.name, rate.got, rate.want)
+				}
+			}
+		})
+	}
+
+	trend, err := service.GetTrend(context.Background(), entities.Monthly, start, end)
+	if err != nil {
+		t.Fatalf("GetTrend() error = %v", err)
+	}
+	if len(repo.metrics) != 3 || !reflect.DeepEqual(trend, metrics) {
+		t.Errorf("trend = %+v, want saved metrics in period order", trend)
+	}
+}
+
+func TestBuildCohortTriangle(t *testing.T) {
+	start, end := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	service, _ := testRetentionService()
+
+	triangle, err := service.BuildCohortTriangle(context.Background(), entities.Monthly, start, end)
+	if err != nil {
+		t.Fatalf("BuildCohortTriangle() error = %v", err)
+	}
+
+	// Когорты: январь — B и C, февраль — D, март — E; A привлечен до начала анализа
+	tests := []struct {
+		name         string
+		wantStart    time.Time
+		wantSize     int
+		wantRetained []int
+		wantRates    []float64
+	}{
+		{name: "january", wantStart: start, wantSize: 2, wantRetained: []int{2, 1, 0}, wantRates: []float64{1, 0.5, 0}},
+		{name: "february", wantStart: start.AddDate(0, 1, 0), wantSize: 1, wantRetained: []int{1, 1}, wantRates: []float64{1, 1}},
+		{name: "march", wantStart: start.AddDate(0, 2, 0), wantSize: 1, wantRetained: []int{1}, wantRates: []float64{1}},
+	}
+
+	if len(triangle.Cohorts) != len(tests) {
+		t.Fatalf("cohorts = %d, want %d", len(triangle.Cohorts), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := triangle.Cohorts[i]
+			if !got.CohortStart.Equal(tt.wantStart) || got.Size != tt.wantSize {
+				t.Errorf("cohort = %s size %d, want %s size %d", got.CohortStart, got.Size, tt.wantStart, tt.wantSize)
+			}
+			if !reflect.DeepEqual(got.Retained, tt.wantRetained) || !reflect.DeepEqual(got.RetentionRates, tt.wantRates) {
+				t.Errorf("retained = %v (%v), want %v (%v)", got.Retained, got.RetentionRates, tt.wantRetained, tt.wantRates)
+			}
+		})
+	}
+
+	// Среднее удержание взвешено размером когорт: (2+1+1)/4, (1+1)/3, 0/2
+	wantAverage := []float64{1, 2.0 / 3, 0}
+	for offset, want := range wantAverage {
+		if math.Abs(triangle.AverageRetention[offset]-want) > 1e-9 {
+			t.Errorf("average retention[%d] = %.4f, want %.4f", offset, triangle.AverageRetention[offset], want)
+		}
+	}
+	if !triangle.StartDate.Equal(start) || !triangle.EndDate.Equal(end) {
+		t.Errorf("triangle range = %s..%s, want %s..%s", triangle.StartDate, triangle.EndDate, start, end)
+	}
+}
+
+func TestRetentionServiceErrors(t *testing.T) {
+	start, end := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	service, _ := testRetentionService()
+	empty := services.NewRetentionService(&memoryTransactionRepository{}, &memoryRetentionRepository{}, logger.NewLogger("ERROR"))
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{name: "invalid period", call: func() error {
+			_, err := service.ComputeMetrics(context.Background(), "quarterly", start, end)
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "reversed range", call: func() error {
+			_, err := service.BuildCohortTriangle(context.Background(), entities.Weekly, end, start)
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "invalid trend period", call: func() error {
+			_, err := service.GetTrend(context.Background(), "yearly", start, end)
+			return err
+		}, want: services.ErrInvalidParameter},
+		{name: "no transactions", call: func() error {
+			_, err := empty.ComputeMetrics(context.Background(), entities.Daily, start, end)
+			return err
+		}, want: services.ErrInsufficientData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,516 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/domain/repositories"
+	"analitics-service/pkg/logger"
+	"analitics-service/pkg/stats"
+)
+
+// ErrUpliftModelNotFound возвращается, если uplift-модель с указанным ID не обучена
+var ErrUpliftModelNotFound = errors.New("uplift model not found")
+
+// upliftFeatureNames — признаки клиента, рассчитываемые по истории транзакций до показа
+var upliftFeatureNames = []string{
+	"recency_days",
+	"frequency",
+	"monetary",
+	"avg_basket_value",
+	"avg_items",
+	"discount_share",
+	"tenure_days",
+}
+
+// UpliftService определяет интерфейс uplift-моделирования для таргетирования скидок
+type UpliftService interface {
+	// TrainModel обучает uplift-модель на показах экспериментов и исходах транзакций и сохраняет её
+	TrainModel(ctx context.Context, modelID string, config entities.UpliftModelConfig) (*entities.UpliftModel, error)
+
+	// ScoreCustomers рассчитывает прогнозный uplift клиентов на дату asOf и ранжирует их по убыванию
+	// Пустой список customerIDs означает всех клиентов с транзакциями в окне истории
+	ScoreCustomers(ctx context.Context, modelID string, customerIDs []string, asOf time.Time) ([]entities.UpliftScore, error)
+
+	// BuildTargetList формирует и сохраняет список клиентов с uplift не ниже minUplift
+	BuildTargetList(ctx context.Context, modelID string, asOf time.Time, minUplift float64, limit int) ([]entities.UpliftScore, error)
+}
+
+// upliftService реализует интерфейс UpliftService
+type upliftService struct {
+	upliftRepo      repositories.UpliftModelRepository
+	experimentRepo  repositories.ExperimentRepository
+	exposureRepo    repositories.ExposureRepository
+	transactionRepo repositories.TransactionRepository
+	logger          logger.Logger
+}
+
+// upliftRow представляет наблюдение обучающей выборки
+type upliftRow struct {
+	customerID string
+	features   []float64
+	treated    bool
+	outcome    float64
+}
+
+// NewUpliftService создает новый экземпляр сервиса uplift-моделирования
+func NewUpliftService(
+	upliftRepo repositories.UpliftModelRepository,
+	experimentRepo repositories.ExperimentRepository,
+	exposureRepo repositories.ExposureRepository,
+	transactionRepo repositories.TransactionRepository,
+	logger logger.Logger,
+) UpliftService {
+	return &upliftService{
+		upliftRepo:      upliftRepo,
+		experimentRepo:  experimentRepo,
+		exposureRepo:    exposureRepo,
+		transactionRepo: transactionRepo,
+		logger:          logger,
+	}
+}
+
+// TrainModel обучает uplift-модель и сохраняет её
+func (s *upliftService) TrainModel(ctx context.Context, modelID string, config entities.UpliftModelConfig) (*entities.UpliftModel, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: invalid uplift config: %v", ErrInvalidParameter, err)
+	}
+
+	rows, err := s.buildTrainingRows(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Делим клиентов на обучающую и отложенную выборки по хэшу, чтобы разбиение было стабильным
+	var train, holdout []upliftRow
+	holdoutBuckets := int(config.HoldoutShare * entities.ExperimentBuckets)
+	for _, row := range rows {
+		if hashBucket("uplift-holdout", row.customerID, entities.ExperimentBuckets) < holdoutBuckets {
+			holdout = append(holdout, row)
+		} else {
+			train = append(train, row)
+		}
+	}
+
+	treatedCount := 0
+	for _, row := range train {
+		if row.treated {
+			treatedCount++
+		}
+	}
+	if treatedCount < 30 || len(train)-treatedCount < 30 {
+		return nil, fmt.Errorf("%w: need at least 30 treated and 30 control rows, got %d and %d",
+			ErrInsufficientData, treatedCount, len(train)-treatedCount)
+	}
+
+	model := &entities.UpliftModel{
+		ID:             modelID,
+		Method:         config.Method,
+		Config:         config,
+		FeatureNames:   upliftFeatureNames,
+		TreatmentShare: float64(treatedCount) / float64(len(train)),
+		TrainingRows:   len(train),
+		TrainedAt:      time.Now(),
+	}
+
+	opts := stats.LogisticOptions{
+		Iterations:   config.Iterations,
+		LearningRate: config.LearningRate,
+		L2:           config.L2,
+	}
+
+	switch config.Method {
+	case entities.UpliftTwoModel:
+		if err := fitTwoModel(model, train, opts); err != nil {
+			return nil, err
+		}
+	case entities.UpliftClassTransformation:
+		if err := fitClassTransformation(model, train, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	model.Evaluation = evaluateUplift(model, holdout, config.CurveBins)
+
+	if err := s.upliftRepo.SaveModel(ctx, *model); err != nil {
+		return nil, fmt.Errorf("failed to save uplift model: %w", err)
+	}
+
+	s.logger.Info(ctx, "Uplift-модель обучена", "modelID", modelID, "method", config.Method,
+		"train", len(train), "holdout", len(holdout), "qini", model.Evaluation.QiniCoefficient)
+	return model, nil
+}
+
+// ScoreCustomers рассчитывает прогнозный uplift клиентов и ранжирует их по убыванию
+func (s *upliftService) ScoreCustomers(ctx context.Context, modelID string, customerIDs []string, asOf time.Time) ([]entities.UpliftScore, error) {
+	model, err := s.upliftRepo.GetModelByID(ctx, modelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve uplift model %s: %w", modelID, err)
+	}
+	if model.ID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUpliftModelNotFound, modelID)
+	}
+
+	startDate := asOf.AddDate(0, 0, -model.Config.LookbackDays)
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx, startDate, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+	history := groupTransactionsByCustomer(transactions)
+
+	if len(customerIDs) == 0 {
+		for customerID := range history {
+			customerIDs = append(customerIDs, customerID)
+		}
+	}
+
+	scores := make([]entities.UpliftScore, 0, len(customerIDs))
+	for _, customerID := range customerIDs {
+		features := upliftFeatures(history[customerID], asOf, model.Config.LookbackDays)
+		score := predictUplift(&model, features)
+		score.CustomerID = customerID
+		scores = append(scores, score)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Uplift == scores[j].Uplift {
+			return scores[i].CustomerID < scores[j].CustomerID
+		}
+		return scores[i].Uplift > scores[j].Uplift
+	})
+	for i := range scores {
+		scores[i].Rank = i + 1
+	}
+
+	return scores, nil
+}
+
+// BuildTargetList формирует и сохраняет список клиентов для таргетирования скидки
+func (s *upliftService) BuildTargetList(ctx context.Context, modelID string, asOf time.Time, minUplift float64, limit int) ([]entities.UpliftScore, error) {
+	scores, err := s.ScoreCustomers(ctx, modelID, nil, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]entities.UpliftScore, 0)
+	for _, score := range scores {
+		if score.Uplift < minUplift {
+			break
+		}
+		targets = append(targets, score)
+		if limit > 0 && len(targets) >= limit {
+			break
+		}
+	}
+
+	if err := s.upliftRepo.SaveTargetList(ctx, modelID, targets); err != nil {
+		return nil, fmt.Errorf("failed to save target list: %w", err)
+	}
+
+	s.logger.Info(ctx, "Сформирован целевой список", "modelID", modelID, "scored", len(scores), "targets", len(targets))
+	return targets, nil
+}
+
+// buildTrainingRows собирает обучающую выборку из показов экспериментов и транзакций клиентов
+func (s *upliftService) buildTrainingRows(ctx context.Context, config entities.UpliftModelConfig) ([]upliftRow, error) {
+	type exposureTreatment struct {
+		exposure entities.Exposure
+		treated  bool
+	}
+
+	var exposures []exposureTreatment
+	var minExposure, maxExposure time.Time
+	for _, experimentID := range config.ExperimentIDs {
+		experiment, err := s.experimentRepo.GetExperimentByID(ctx, experimentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve experiment %s: %w", experimentID, err)
+		}
+
+		// Воздействием считается вариант со скидкой или купоном, контролем — контрольный вариант
+		treatment := make(map[string]bool, len(experiment.Variants))
+		for _, variant := range experiment.Variants {
+			switch {
+			case variant.IsControl:
+				treatment[variant.ID] = false
+			case variant.DiscountPct > 0 || variant.CouponCode != "":
+				treatment[variant.ID] = true
+			}
+		}
+
+		experimentExposures, err := s.exposureRepo.GetFirstExposures(ctx, experimentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve exposures for %s: %w", experimentID, err)
+		}
+
+		for _, exposure := range experimentExposures {
+			treated, known := treatment[exposure.VariantID]
+			if !known {
+				continue
+			}
+			exposures = append(exposures, exposureTreatment{exposure: exposure, treated: treated})
+			if minExposure.IsZero() || exposure.ExposedAt.Before(minExposure) {
+				minExposure = exposure.ExposedAt
+			}
+			if exposure.ExposedAt.After(maxExposure) {
+				maxExposure = exposure.ExposedAt
+			}
+		}
+	}
+
+	if len(exposures) == 0 {
+		return nil, ErrNoExperimentExposure
+	}
+
+	transactions, err := s.transactionRepo.GetTransactionsByPeriod(ctx,
+		minExposure.AddDate(0, 0, -config.LookbackDays),
+		maxExposure.AddDate(0, 0, config.OutcomeWindowDays))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+	history := groupTransactionsByCustomer(transactions)
+
+	rows := make([]upliftRow, 0, len(exposures))
+	for _, item := range exposures {
+		exposedAt := item.exposure.ExposedAt
+		outcomeEnd := exposedAt.AddDate(0, 0, config.OutcomeWindowDays)
+
+		var before []entities.Transaction
+		outcome := 0.0
+		for _, tx := range history[item.exposure.CustomerID] {
+			switch {
+			case tx.Date.Before(exposedAt):
+				before = append(before, tx)
+			case tx.Date.Before(outcomeEnd):
+				outcome = 1
+			}
+		}
+
+		rows = append(rows, upliftRow{
+			customerID: item.exposure.CustomerID,
+			features:   upliftFeatures(before, exposedAt, config.LookbackDays),
+			treated:    item.treated,
+			outcome:    outcome,
+		})
+	}
+
+	return rows, nil
+}
+
+// fitTwoModel обучает отдельные модели для групп со скидкой и без нее
+func fitTwoModel(model *entities.UpliftModel, rows []upliftRow, opts stats.LogisticOptions) error {
+	var xt, xc [][]float64
+	var yt, yc []float64
+	for _, row := range rows {
+		if row.treated {
+			xt = append(xt, row.features)
+			yt = append(yt, row.outcome)
+		} else {
+			xc = append(xc, row.features)
+			yc = append(yc, row.outcome)
+		}
+	}
+
+	treatment, err := stats.FitLogistic(xt, yt, nil, opts)
+	if err != nil {
+		return fmt.Errorf("failed to fit treatment model: %w", err)
+	}
+	control, err := stats.FitLogistic(xc, yc, nil, opts)
+	if err != nil {
+		return fmt.Errorf("failed to fit control model: %w", err)
+	}
+
+	model.TreatmentModel = toLogisticParams(treatment)
+	model.ControlModel = toLogisticParams(control)
+	return nil
+}
+
+// fitClassTransformation обучает модель на преобразованной целевой переменной Z = Y*T + (1-Y)*(1-T)
+// Веса наблюдений выравнивают группы, поэтому uplift = 2*P(Z=1|x) - 1 при любой доле воздействия
+func fitClassTransformation(model *entities.UpliftModel, rows []upliftRow, opts stats.LogisticOptions) error {
+	x := make([][]float64, 0, len(rows))
+	z := make([]float64, 0, len(rows))
+	weights := make([]float64, 0, len(rows))
+	for _, row := range rows {
+		x = append(x, row.features)
+		if row.treated {
+			z = append(z, row.outcome)
+			weights = append(weights, 1/(2*model.TreatmentShare))
+		} else {
+			z = append(z, 1-row.outcome)
+			weights = append(weights, 1/(2*(1-model.TreatmentShare)))
+		}
+	}
+
+	transformed, err := stats.FitLogistic(x, z, weights, opts)
+	if err != nil {
+		return fmt.Errorf("failed to fit transformed model: %w", err)
+	}
+
+	model.TransformedModel = toLogisticParams(transformed)
+	return nil
+}
+
+// predictUplift рассчитывает прогнозный uplift по признакам клиента
+func predictUplift(model *entities.UpliftModel, features []float64) entities.UpliftScore {
+	if model.Method == entities.UpliftClassTransformation && model.TransformedModel != nil {
+		p := fromLogisticParams(model.TransformedModel).Predict(features)
+		return entities.UpliftScore{Uplift: 2*p - 1}
+	}
+
+	pt := fromLogisticParams(model.TreatmentModel).Predict(features)
+	pc := fromLogisticParams(model.ControlModel).Predict(features)
+	return entities.UpliftScore{
+		Uplift:             pt - pc,
+		ProbabilityTreated: pt,
+		ProbabilityControl: pc,
+	}
+}
+
+// evaluateUplift строит Qini и uplift кривые на отложенной выборке
+func evaluateUplift(model *entities.UpliftModel, rows []upliftRow, bins int) entities.UpliftEvaluation {
+	evaluation := entities.UpliftEvaluation{HoldoutRows: len(rows)}
+	if len(rows) == 0 {
+		return evaluation
+	}
+
+	type scored struct {
+		uplift float64
+		row    upliftRow
+	}
+	ranked := make([]scored, 0, len(rows))
+	for _, row := range rows {
+		ranked = append(ranked, scored{uplift: predictUplift(model, row.features).Uplift, row: row})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].uplift > ranked[j].uplift
+	})
+
+	// Накопленные суммы по группам для каждой позиции ранжирования
+	nt, nc := make([]float64, len(ranked)+1), make([]float64, len(ranked)+1)
+	yt, yc := make([]float64, len(ranked)+1), make([]float64, len(ranked)+1)
+	for i, item := range ranked {
+		nt[i+1], nc[i+1], yt[i+1], yc[i+1] = nt[i], nc[i], yt[i], yc[i]
+		if item.row.treated {
+			nt[i+1]++
+			yt[i+1] += item.row.outcome
+		} else {
+			nc[i+1]++
+			yc[i+1] += item.row.outcome
+		}
+	}
+
+	qiniAt := func(k int) float64 {
+		if nc[k] == 0 {
+			return yt[k]
+		}
+		return yt[k] - yc[k]*nt[k]/nc[k]
+	}
+	upliftAt := func(k int) float64 {
+		if nt[k] == 0 || nc[k] == 0 {
+			return 0
+		}
+		return yt[k]/nt[k] - yc[k]/nc[k]
+	}
+
+	totalQini := qiniAt(len(ranked))
+	evaluation.Curve = append(evaluation.Curve, entities.UpliftCurvePoint{})
+	for b := 1; b <= bins; b++ {
+		fraction := float64(b) / float64(bins)
+		k := int(math.Round(fraction * float64(len(ranked))))
+		evaluation.Curve = append(evaluation.Curve, entities.UpliftCurvePoint{
+			Fraction: fraction,
+			Qini:     qiniAt(k),
+			Uplift:   upliftAt(k),
+			Random:   fraction * totalQini,
+		})
+	}
+
+	// Площади считаем методом трапеций, Qini нормируем на размер выборки
+	for i := 1; i < len(evaluation.Curve); i++ {
+		prev, cur := evaluation.Curve[i-1], evaluation.Curve[i]
+		width := cur.Fraction - prev.Fraction
+		evaluation.QiniCoefficient += width * ((prev.Qini - prev.Random) + (cur.Qini - cur.Random)) / 2
+		evaluation.AUUC += width * (prev.Uplift + cur.Uplift) / 2
+	}
+	evaluation.QiniCoefficient /= float64(len(ranked))
+
+	return evaluation
+}
+
+// upliftFeatures рассчитывает признаки клиента по транзакциям до момента asOf
+func upliftFeatures(transactions []entities.Transaction, asOf time.Time, lookbackDays int) []float64 {
+	features := make([]float64, len(upliftFeatureNames))
+	features[0] = float64(lookbackDays)
+
+	var first, last time.Time
+	items, discounted := 0, 0
+	for _, tx := range transactions {
+		if !tx.Date.Before(asOf) || tx.Date.Before(asOf.AddDate(0, 0, -lookbackDays)) {
+			continue
+		}
+		features[1]++
+		features[2] += tx.TotalAmount
+		for _, item := range tx.Items {
+			items += item.Quantity
+		}
+		if tx.DiscountUsed {
+			discounted++
+		}
+		if first.IsZero() || tx.Date.Before(first) {
+			first = tx.Date
+		}
+		if tx.Date.After(last) {
+			last = tx.Date
+		}
+	}
+
+	if features[1] > 0 {
+		features[0] = asOf.Sub(last).Hours() / 24
+		features[3] = features[2] / features[1]
+		features[4] = float64(items) / features[1]
+		features[5] = float64(discounted) / features[1]
+		features[6] = asOf.Sub(first).Hours() / 24
+	}
+
+	return features
+}
+
+// groupTransactionsByCustomer группирует транзакции по клиентам в хронологическом порядке
+func groupTransactionsByCustomer(transactions []entities.Transaction) map[string][]entities.Transaction {
+	result := make(map[string][]entities.Transaction)
+	for _, tx := range transactions {
+		result[tx.CustomerID] = append(result[tx.CustomerID], tx)
+	}
+	for customerID := range result {
+		txs := result[customerID]
+		sort.Slice(txs, func(i, j int) bool {
+			return txs[i].Date.Before(txs[j].Date)
+		})
+	}
+	return result
+}
+
+// toLogisticParams преобразует модель из пакета stats в доменную сущность
+func toLogisticParams(model *stats.LogisticModel) *entities.LogisticModelParams {
+	return &entities.LogisticModelParams{
+		Weights:   model.Weights,
+		Intercept: model.Intercept,
+		Means:     model.Means,
+		Scales:    model.Scales,
+	}
+}
+
+// fromLogisticParams преобразует доменную сущность в модель пакета stats
+func fromLogisticParams(params *entities.LogisticModelParams) *stats.LogisticModel {
+	return &stats.LogisticModel{
+		Weights:   params.Weights,
+		Intercept: params.Intercept,
+		Means:     params.Means,
+		Scales:    params.Scales,
+	}
+}
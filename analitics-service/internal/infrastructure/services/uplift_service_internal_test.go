@@ -0,0 +1,99 @@
+// internal/infrastructure/services/uplift_service_internal_test.go
+package services
+
+import (
+	"math"
+	"testing"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// testUpliftRows возвращает отложенную выборку, где скидка влияет только на клиентов с признаком 1:
+// они покупают только при скидке, остальные не покупают вовсе
+func testUpliftRows() []upliftRow {
+	var rows []upliftRow
+	for i := 0; i < 25; i++ {
+		rows = append(rows,
+			upliftRow{features: []float64{0}, treated: true},
+			upliftRow{features: []float64{0}},
+			upliftRow{features: []float64{1}, treated: true, outcome: 1},
+			upliftRow{features: []float64{1}},
+		)
+	}
+	return rows
+}
+
+// testTransformedModel возвращает модель class transformation с uplift = 2*sigmoid(weight*x) - 1
+func testTransformedModel(weight float64) *entities.UpliftModel {
+	return &entities.UpliftModel{
+		Method: entities.UpliftClassTransformation,
+		TransformedModel: &entities.LogisticModelParams{
+			Weights: []float64{weight},
+			Means:   []float64{0},
+			Scales:  []float64{1},
+		},
+	}
+}
+
+func TestEvaluateUpliftQini(t *testing.T) {
+	tests := []struct {
+		name string
+		// Вес признака: положительный ранжирует отзывчивых клиентов первыми, отрицательный — последними
+		weight   float64
+		wantQini float64
+		wantAUUC float64
+	}{
+		// Отзывчивые клиенты занимают первую половину: разница со случайной кривой растет до 12.5 к середине
+		// и убывает до нуля, площадь треугольника 6.25 на 100 строк. Uplift равен 1 до середины, затем 25/(25+5j)
+		{name: "perfect ranking", weight: 1, wantQini: 0.0625, wantAUUC: 0.1 * (5 + 5.0/6 + 5.0/7 + 5.0/8 + 5.0/9 + 0.5/2)},
+		// Отзывчивые клиенты в конце: площадь того же треугольника под случайной кривой, uplift 5j/(25+5j) во второй половине
+		{name: "inverted ranking", weight: -1, wantQini: -0.0625, wantAUUC: 0.1 * (5.0/30 + 10.0/35 + 15.0/40 + 20.0/45 + 0.5/2)},
+	}
+
+	const bins = 10
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := testUpliftRows()
+			evaluation := evaluateUplift(testTransformedModel(tt.weight), rows, bins)
+
+			if evaluation.HoldoutRows != len(rows) {
+				t.Errorf("holdout rows = %d, want %d", evaluation.HoldoutRows, len(rows))
+			}
+			if len(evaluation.Curve) != bins+1 {
+				t.Fatalf("curve points = %d, want %d", len(evaluation.Curve), bins+1)
+			}
+			if last := evaluation.Curve[bins]; last.Qini != 25 || last.Random != 25 {
+				t.Errorf("curve end = %+v, want qini and random 25", last)
+			}
+			if math.Abs(evaluation.QiniCoefficient-tt.wantQini) > 1e-9 {
+				t.Errorf("qini coefficient = %.6f, want %.6f", evaluation.QiniCoefficient, tt.wantQini)
+			}
+			if math.Abs(evaluation.AUUC-tt.wantAUUC) > 1e-9 {
+				t.Errorf("AUUC = %.6f, want %.6f", evaluation.AUUC, tt.wantAUUC)
+			}
+		})
+	}
+}
+
+func TestEvaluateUpliftEmptyHoldout(t *testing.T) {
+	evaluation := evaluateUplift(testTransformedModel(1), nil, 10)
+	if evaluation.HoldoutRows != 0 || len(evaluation.Curve) != 0 || evaluation.QiniCoefficient != 0 {
+		t.Errorf("evaluateUplift() on empty holdout = %+v, want zero evaluation", evaluation)
+	}
+}
+
+func TestPredictUpliftTwoModel(t *testing.T) {
+	model := &entities.UpliftModel{
+		Method:         entities.UpliftTwoModel,
+		TreatmentModel: &entities.LogisticModelParams{Weights: []float64{0}, Intercept: math.Log(3), Means: []float64{0}, Scales: []float64{1}},
+		ControlModel:   &entities.LogisticModelParams{Weights: []float64{0}, Intercept: 0, Means: []float64{0}, Scales: []float64{1}},
+	}
+
+	score := predictUplift(model, []float64{5})
+	if math.Abs(score.ProbabilityTreated-0.75) > 1e-9 || math.Abs(score.ProbabilityControl-0.5) > 1e-9 {
+		t.Errorf("probabilities = %.4f / %.4f, want 0.75 / 0.5", score.ProbabilityTreated, score.ProbabilityControl)
+	}
+	if math.Abs(score.Uplift-0.25) > 1e-9 {
+		t.Errorf("uplift = %.4f, want 0.25", score.Uplift)
+	}
+}
@@ -0,0 +1,209 @@
+// internal/interfaces/grpc/analytics_server.go
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	analyticsv1 "analitics-service/api/analytics/v1"
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// analyticsServer реализует analyticsv1.AnalyticsServiceServer поверх сервиса запросов к результатам аналитики
+type analyticsServer struct {
+	analyticsv1.UnimplementedAnalyticsServiceServer
+	queryService services.RecommendationQueryService
+	maxBatchSize int
+	logger       logger.Logger
+}
+
+func newAnalyticsServer(queryService services.RecommendationQueryService, maxBatchSize int, logger logger.Logger) *analyticsServer {
+	return &analyticsServer{
+		queryService: queryService,
+		maxBatchSize: maxBatchSize,
+		logger:       logger,
+	}
+}
+
+// GetProductRecommendations возвращает товары, рекомендованные к корзине
+func (s *analyticsServer) GetProductRecommendations(ctx context.Context, req *analyticsv1.GetProductRecommendationsRequest) (*analyticsv1.GetProductRecommendationsResponse, error) {
+	recommendations, err := s.queryService.GetProductRecommendations(ctx, req.GetBasketProductIds(), int(req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &analyticsv1.GetProductRecommendationsResponse{
+		Recommendations: make([]*analyticsv1.ProductRecommendation, 0, len(recommendations)),
+	}
+	for _, recommendation := range recommendations {
+		resp.Recommendations = append(resp.Recommendations, &analyticsv1.ProductRecommendation{
+			ProductId: recommendation.Product.ID,
+			Name:      recommendation.Product.Name,
+			Category:  recommendation.Product.Category,
+			Price:     recommendation.Product.Price,
+			Score:     recommendation.Score,
+			Lift:      recommendation.Lift,
+			Support:   recommendation.Support,
+		})
+	}
+	return resp, nil
+}
+
+// GetProductSegment возвращает ABC-сегмент товара
+func (s *analyticsServer) GetProductSegment(ctx context.Context, req *analyticsv1.GetProductSegmentRequest) (*analyticsv1.ProductSegment, error) {
+	segmentation, err := s.queryService.GetProductSegment(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	return toProductSegment(segmentation), nil
+}
+
+// BatchGetProductSegments отвечает на каждый запрос потока сегментом товара или found=false
+func (s *analyticsServer) BatchGetProductSegments(stream analyticsv1.AnalyticsService_BatchGetProductSegmentsServer) error {
+	return s.serveBatch(stream.Context(), func() (string, error) {
+		req, err := stream.Recv()
+		return req.GetProductId(), err
+	}, func(ctx context.Context, productID string) error {
+		result := &analyticsv1.ProductSegmentResult{ProductId: productID}
+
+		segmentation, err := s.queryService.GetProductSegment(ctx, productID)
+		switch {
+		case errors.Is(err, services.ErrProductSegmentNotFound):
+			// Товар не сегментирован: отвечаем found=false, не прерывая поток
+		case err != nil:
+			return err
+		default:
+			result.Found = true
+			result.Segment = toProductSegment(segmentation)
+		}
+		return stream.Send(result)
+	})
+}
+
+// GetDiscountRecommendation возвращает текущую рекомендацию по скидке для товара
+func (s *analyticsServer) GetDiscountRecommendation(ctx context.Context, req *analyticsv1.GetDiscountRecommendationRequest) (*analyticsv1.DiscountRecommendation, error) {
+	recommendation, err := s.queryService.GetDiscountRecommendation(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	return toDiscountRecommendation(*recommendation), nil
+}
+
+// BatchGetDiscountRecommendations отвечает на каждый запрос потока рекомендацией по скидке или found=false
+func (s *analyticsServer) BatchGetDiscountRecommendations(stream analyticsv1.AnalyticsService_BatchGetDiscountRecommendationsServer) error {
+	return s.serveBatch(stream.Context(), func() (string, error) {
+		req, err := stream.Recv()
+		return req.GetProductId(), err
+	}, func(ctx context.Context, productID string) error {
+		result := &analyticsv1.DiscountRecommendationResult{ProductId: productID}
+
+		recommendation, err := s.queryService.GetDiscountRecommendation(ctx, productID)
+		switch {
+		case errors.Is(err, services.ErrDiscountRecommendationNotFound):
+			// Рекомендации нет: отвечаем found=false, не прерывая поток
+		case err != nil:
+			return err
+		default:
+			result.Found = true
+			result.Recommendation = toDiscountRecommendation(*recommendation)
+		}
+		return stream.Send(result)
+	})
+}
+
+// ListDiscountRecommendations передает потоком последние рекомендации по скидкам
+func (s *analyticsServer) ListDiscountRecommendations(req *analyticsv1.ListDiscountRecommendationsRequest, stream analyticsv1.AnalyticsService_ListDiscountRecommendationsServer) error {
+	recommendations, err := s.queryService.ListDiscountRecommendations(stream.Context(), req.GetCategory(), int(req.GetLimit()))
+	if err != nil {
+		return err
+	}
+
+	for _, recommendation := range recommendations {
+		if err := stream.Send(toDiscountRecommendation(recommendation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serveBatch читает ID товаров из потока до его закрытия клиентом и отвечает на каждый через handle
+func (s *analyticsServer) serveBatch(ctx context.Context, recv func() (string, error), handle func(ctx context.Context, productID string) error) error {
+	processed := 0
+	for {
+		productID, err := recv()
+		if err == io.EOF {
+			s.logger.Debug(ctx, "Пакетный запрос gRPC обработан", "requests", processed)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if s.maxBatchSize > 0 && processed >= s.maxBatchSize {
+			return status.Errorf(codes.ResourceExhausted, "batch is limited to %d requests", s.maxBatchSize)
+		}
+		if err := handle(ctx, productID); err != nil {
+			return err
+		}
+		processed++
+	}
+}
+
+// toProductSegment преобразует сегментацию товара в сообщение API
+func toProductSegment(segmentation *entities.ProductSegmentation) *analyticsv1.ProductSegment {
+	return &analyticsv1.ProductSegment{
+		ProductId:    segmentation.ProductID,
+		Segment:      toSegment(segmentation.Segment),
+		Score:        segmentation.Score,
+		AnalysisDate: toTimestamp(segmentation.AnalysisDate),
+	}
+}
+
+// toDiscountRecommendation преобразует рекомендацию по скидке в сообщение API
+func toDiscountRecommendation(recommendation entities.DiscountRecommendation) *analyticsv1.DiscountRecommendation {
+	return &analyticsv1.DiscountRecommendation{
+		ProductId:             recommendation.ProductID,
+		Category:              recommendation.Category,
+		Daypart:               recommendation.Daypart,
+		OptimalDiscount:       recommendation.OptimalDiscount,
+		LiftFactor:            recommendation.LiftFactor,
+		AbcSegment:            toSegment(recommendation.ABCCategory),
+		Confidence:            recommendation.Confidence,
+		LifecycleStage:        string(recommendation.LifecycleStage),
+		AdjustmentReason:      recommendation.AdjustmentReason,
+		NetIncrementalRevenue: recommendation.NetIncrementalRevenue,
+		AnalysisDate:          toTimestamp(recommendation.AnalysisDate),
+		PeriodStart:           toTimestamp(recommendation.PeriodStart),
+		PeriodEnd:             toTimestamp(recommendation.PeriodEnd),
+		RunId:                 recommendation.RunID,
+	}
+}
+
+// toSegment преобразует ABC-сегмент в перечисление API
+func toSegment(segment entities.Segment) analyticsv1.Segment {
+	switch segment {
+	case entities.SegmentA:
+		return analyticsv1.Segment_SEGMENT_A
+	case entities.SegmentB:
+		return analyticsv1.Segment_SEGMENT_B
+	case entities.SegmentC:
+		return analyticsv1.Segment_SEGMENT_C
+	}
+	return analyticsv1.Segment_SEGMENT_UNSPECIFIED
+}
+
+// toTimestamp преобразует время в сообщение API; нулевое время не передается
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,113 @@
+// internal/interfaces/grpc/interceptors.go
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// contextStream подменяет контекст потокового вызова
+type contextStream struct {
+	gogrpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// unaryDeadlineInterceptor ограничивает длительность одиночного вызова
+// Более короткий дедлайн клиента сохраняется, отсутствующий или более длинный заменяется на timeout
+func unaryDeadlineInterceptor(timeout time.Duration) gogrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// streamDeadlineInterceptor ограничивает длительность потокового вызова так же, как unaryDeadlineInterceptor
+func streamDeadlineInterceptor(timeout time.Duration) gogrpc.StreamServerInterceptor {
+	return func(srv interface{}, stream gogrpc.ServerStream, _ *gogrpc.StreamServerInfo, handler gogrpc.StreamHandler) error {
+		ctx, cancel := context.WithTimeout(stream.Context(), timeout)
+		defer cancel()
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// unaryErrorInterceptor переводит ошибки сервисов в коды gRPC
+func unaryErrorInterceptor(logger logger.Logger) gogrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, toStatus(ctx, logger, info.FullMethod, err)
+		}
+		return resp, nil
+	}
+}
+
+// streamErrorInterceptor переводит ошибки сервисов в коды gRPC для потоковых вызовов
+func streamErrorInterceptor(logger logger.Logger) gogrpc.StreamServerInterceptor {
+	return func(srv interface{}, stream gogrpc.ServerStream, info *gogrpc.StreamServerInfo, handler gogrpc.StreamHandler) error {
+		if err := handler(srv, stream); err != nil {
+			return toStatus(stream.Context(), logger, info.FullMethod, err)
+		}
+		return nil
+	}
+}
+
+// unaryRecoveryInterceptor перехватывает панику обработчика и возвращает INTERNAL вместо падения сервера
+func unaryRecoveryInterceptor(logger logger.Logger) gogrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error(ctx, "Паника в обработчике gRPC", "method", info.FullMethod, "panic", r)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// streamRecoveryInterceptor перехватывает панику потокового обработчика
+func streamRecoveryInterceptor(logger logger.Logger) gogrpc.StreamServerInterceptor {
+	return func(srv interface{}, stream gogrpc.ServerStream, info *gogrpc.StreamServerInfo, handler gogrpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error(stream.Context(), "Паника в обработчике gRPC", "method", info.FullMethod, "panic", r)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(srv, stream)
+	}
+}
+
+// toStatus возвращает ошибку gRPC с кодом, соответствующим ошибке сервиса
+// Внутренние ошибки логируются, а клиенту возвращается только общее сообщение
+func toStatus(ctx context.Context, logger logger.Logger, method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, services.ErrInvalidParameter):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, services.ErrProductSegmentNotFound), errors.Is(err, services.ErrDiscountRecommendationNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	logger.Error(ctx, "Ошибка обработки gRPC запроса", "method", method, "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,105 @@
+// internal/interfaces/grpc/server.go
+package grpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	analyticsv1 "analitics-service/api/analytics/v1"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// Таймауты по умолчанию: одиночные запросы сервиса меню должны укладываться в доли секунды,
+// потоки пакетного поиска живут дольше
+const (
+	defaultUnaryTimeout  = 500 * time.Millisecond
+	defaultStreamTimeout = time.Minute
+)
+
+// Options содержит настройки gRPC-сервера
+type Options struct {
+	UnaryTimeout  time.Duration // Предельная длительность одиночного вызова
+	StreamTimeout time.Duration // Предельная длительность потокового вызова
+	MaxBatchSize  int           // Максимальное количество запросов в одном потоке пакетного поиска; 0 — без ограничения
+}
+
+// Server gRPC-сервер аналитического сервиса со стандартной проверкой состояния grpc.health.v1
+type Server struct {
+	server *gogrpc.Server
+	health *health.Server
+	logger logger.Logger
+}
+
+// NewServer создает gRPC-сервер и регистрирует на нем AnalyticsService и сервис проверки состояния
+// До вызова Serve сервис отвечает на проверку состояния NOT_SERVING
+func NewServer(queryService services.RecommendationQueryService, options Options, logger logger.Logger) *Server {
+	if options.UnaryTimeout <= 0 {
+		options.UnaryTimeout = defaultUnaryTimeout
+	}
+	if options.StreamTimeout <= 0 {
+		options.StreamTimeout = defaultStreamTimeout
+	}
+
+	server := gogrpc.NewServer(
+		gogrpc.ChainUnaryInterceptor(
+			unaryRecoveryInterceptor(logger),
+			unaryDeadlineInterceptor(options.UnaryTimeout),
+			unaryErrorInterceptor(logger),
+		),
+		gogrpc.ChainStreamInterceptor(
+			streamRecoveryInterceptor(logger),
+			streamDeadlineInterceptor(options.StreamTimeout),
+			streamErrorInterceptor(logger),
+		),
+	)
+
+	s := &Server{
+		server: server,
+		health: health.NewServer(),
+		logger: logger,
+	}
+	s.setServing(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	analyticsv1.RegisterAnalyticsServiceServer(server, newAnalyticsServer(queryService, options.MaxBatchSize, logger))
+	healthpb.RegisterHealthServer(server, s.health)
+	return s
+}
+
+// Serve принимает соединения на listener до остановки сервера
+func (s *Server) Serve(listener net.Listener) error {
+	s.setServing(healthpb.HealthCheckResponse_SERVING)
+	s.logger.Info(context.Background(), "Запуск gRPC сервера", "address", listener.Addr().String())
+	return s.server.Serve(listener)
+}
+
+// Shutdown переводит сервис в NOT_SERVING и дожидается завершения текущих вызовов
+// Если ctx завершается раньше, оставшиеся вызовы прерываются
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.logger.Warn(ctx, "gRPC сервер остановлен принудительно", "error", ctx.Err())
+		s.server.Stop()
+		<-stopped
+	}
+}
+
+// setServing устанавливает состояние сервера и AnalyticsService для проверки состояния
+func (s *Server) setServing(status healthpb.HealthCheckResponse_ServingStatus) {
+	s.health.SetServingStatus("", status)
+	s.health.SetServingStatus(analyticsv1.AnalyticsService_ServiceDesc.ServiceName, status)
+}
@@ -0,0 +1,94 @@
+// internal/interfaces/http/handlers/ab_test_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// ABTestHandler обрабатывает запросы оценки A/B тестов
+type ABTestHandler struct {
+	evaluationService services.ABTestEvaluationService
+	options           entities.ABTestAnalysisOptions
+	logger            logger.Logger
+}
+
+// abTestEvaluateRequest представляет тело запроса оценки теста
+// ID теста передается в теле: ID тестов экспериментов с несколькими вариантами содержат "/"
+// Без options используются параметры оценки по умолчанию, без samples бутстреп-оценки не рассчитываются
+type abTestEvaluateRequest struct {
+	TestID  string                          `json:"test_id"`
+	Options *entities.ABTestAnalysisOptions `json:"options,omitempty"`
+	Samples *entities.ABTestSamples         `json:"samples,omitempty"`
+}
+
+// abTestSampleSizeRequest представляет тело запроса расчета размера групп
+type abTestSampleSizeRequest struct {
+	Baseline    entities.GroupStats   `json:"baseline"`
+	Metric      entities.ABTestMetric `json:"metric"`
+	RelativeMDE float64               `json:"relative_mde"`
+}
+
+// abTestSampleSizeResponse представляет необходимый размер каждой группы
+type abTestSampleSizeResponse struct {
+	GroupSize int `json:"group_size"`
+}
+
+// NewABTestHandler создает новый обработчик оценки A/B тестов
+func NewABTestHandler(evaluationService services.ABTestEvaluationService, options entities.ABTestAnalysisOptions, logger logger.Logger) *ABTestHandler {
+	return &ABTestHandler{
+		evaluationService: evaluationService,
+		options:           options,
+		logger:            logger,
+	}
+}
+
+// EvaluateTest оценивает сохраненный тест в его режиме анализа и сохраняет результат
+func (h *ABTestHandler) EvaluateTest(w http.ResponseWriter, r *http.Request) {
+	var request abTestEvaluateRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if request.TestID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "test_id is required"})
+		return
+	}
+
+	options := h.options
+	if request.Options != nil {
+		options = *request.Options
+	}
+
+	result, err := h.evaluationService.EvaluateTest(r.Context(), request.TestID, options, request.Samples)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось оценить A/B тест", "testID", request.TestID, "error", err)
+		writeError(w, "Failed to evaluate A/B test", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// RequiredSampleSize рассчитывает размер каждой группы для обнаружения относительного эффекта
+// с параметрами частотной оценки по умолчанию
+func (h *ABTestHandler) RequiredSampleSize(w http.ResponseWriter, r *http.Request) {
+	var request abTestSampleSizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	size, err := h.evaluationService.RequiredSampleSize(request.Baseline, request.Metric, request.RelativeMDE, h.options.Frequentist)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось рассчитать размер групп", "metric", request.Metric, "error", err)
+		writeError(w, "Failed to calculate required sample size", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, abTestSampleSizeResponse{GroupSize: size})
+}
@@ -0,0 +1,144 @@
+// internal/interfaces/http/handlers/analysis_run_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// AnalysisRunHandler обрабатывает запросы воспроизводимых запусков анализа
+type AnalysisRunHandler struct {
+	runService services.AnalysisRunService
+	logger     logger.Logger
+}
+
+// NewAnalysisRunHandler создает новый обработчик запусков анализа
+func NewAnalysisRunHandler(runService services.AnalysisRunService, logger logger.Logger) *AnalysisRunHandler {
+	return &AnalysisRunHandler{
+		runService: runService,
+		logger:     logger,
+	}
+}
+
+// RunABCAnalysis выполняет ABC-анализ с критериями из тела запроса
+func (h *AnalysisRunHandler) RunABCAnalysis(w http.ResponseWriter, r *http.Request) {
+	var criteria entities.ABCAnalysisCriteria
+	if err := json.NewDecoder(r.Body).Decode(&criteria); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	run, err := h.runService.RunABCAnalysis(r.Context(), criteria)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось выполнить ABC-анализ", "error", err)
+		writeError(w, "Failed to run ABC analysis", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// RunAssociationRules ищет ассоциативные правила с параметрами из тела запроса
+func (h *AnalysisRunHandler) RunAssociationRules(w http.ResponseWriter, r *http.Request) {
+	var params entities.AssociationRuleParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	run, err := h.runService.RunAssociationRules(r.Context(), params)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось найти ассоциативные правила", "error", err)
+		writeError(w, "Failed to run association rules analysis", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// RunDiscountRecommendations генерирует рекомендации по скидкам за период from-to
+func (h *AnalysisRunHandler) RunDiscountRecommendations(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := queryPeriod(w, r, 90)
+	if !ok {
+		return
+	}
+
+	params := entities.DiscountRecommendationParams{StartDate: from, EndDate: to}
+	run, err := h.runService.RunDiscountRecommendations(r.Context(), params)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось сгенерировать рекомендации по скидкам", "error", err)
+		writeError(w, "Failed to run discount recommendations", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// Rerun повторяет анализ с параметрами запуска из пути запроса
+func (h *AnalysisRunHandler) Rerun(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+
+	run, err := h.runService.Rerun(r.Context(), runID)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось повторить анализ", "runID", runID, "error", err)
+		writeError(w, "Failed to rerun analysis", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// GetRuns возвращает последние запуски анализа вида type, по умолчанию всех видов
+func (h *AnalysisRunHandler) GetRuns(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", 50)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	analysisType := entities.AnalysisType(r.URL.Query().Get("type"))
+	runs, err := h.runService.GetRuns(r.Context(), analysisType, limit)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить запуски анализа", "error", err)
+		writeError(w, "Failed to get analysis runs", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, runs)
+}
+
+// GetRun возвращает запуск анализа по ID
+func (h *AnalysisRunHandler) GetRun(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+
+	run, err := h.runService.GetRun(r.Context(), runID)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить запуск анализа", "runID", runID, "error", err)
+		writeError(w, "Failed to get analysis run", err)
+		return
+	}
+	if run == nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "Analysis run not found"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// GetQuarantined возвращает записи, исключенные проверкой качества данных при запуске анализа
+func (h *AnalysisRunHandler) GetQuarantined(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+
+	records, err := h.runService.GetQuarantined(r.Context(), runID)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить записи карантина", "runID", runID, "error", err)
+		writeError(w, "Failed to get quarantined records", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, records)
+}
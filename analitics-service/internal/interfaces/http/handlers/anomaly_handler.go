@@ -0,0 +1,92 @@
+// internal/interfaces/http/handlers/anomaly_handler.go
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// AnomalyHandler обрабатывает запросы аномалий продаж
+type AnomalyHandler struct {
+	anomalyService services.AnomalyService
+	config         entities.AnomalyDetectionConfig
+	logger         logger.Logger
+}
+
+// NewAnomalyHandler создает новый обработчик аномалий продаж
+func NewAnomalyHandler(anomalyService services.AnomalyService, config entities.AnomalyDetectionConfig, logger logger.Logger) *AnomalyHandler {
+	return &AnomalyHandler{
+		anomalyService: anomalyService,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+// GetAnomalies возвращает аномалии за период from-to с серьезностью не ниже severity
+func (h *AnomalyHandler) GetAnomalies(w http.ResponseWriter, r *http.Request) {
+	to, err := queryDate(r, "to", today().AddDate(0, 0, 1))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	from, err := queryDate(r, "from", to.AddDate(0, 0, -7))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	severity := entities.AnomalySeverity(r.URL.Query().Get("severity"))
+
+	anomalies, err := h.anomalyService.GetAnomalies(r.Context(), from, to, severity)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить аномалии", "error", err)
+		writeError(w, "Failed to get anomalies", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, anomalies)
+}
+
+// DetectAnomalies запускает поиск аномалий с гранулярностью daily или hourly
+// Параметр date задает день, до начала которого анализируются продажи
+func (h *AnomalyHandler) DetectAnomalies(w http.ResponseWriter, r *http.Request) {
+	granularity := entities.AnomalyGranularity(r.URL.Query().Get("granularity"))
+	if granularity == "" {
+		granularity = entities.GranularityDaily
+	}
+
+	asOf := time.Now().UTC()
+	if r.URL.Query().Get("date") != "" {
+		date, err := queryDate(r, "date", today())
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+		asOf = date
+	}
+
+	var (
+		anomalies []entities.SalesAnomaly
+		err       error
+	)
+	switch granularity {
+	case entities.GranularityDaily:
+		anomalies, err = h.anomalyService.DetectDaily(r.Context(), asOf, h.config)
+	case entities.GranularityHourly:
+		anomalies, err = h.anomalyService.DetectHourly(r.Context(), asOf, h.config)
+	default:
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Granularity must be daily or hourly"})
+		return
+	}
+
+	if err != nil {
+		h.logger.Error(r.Context(), "Поиск аномалий завершился ошибкой", "granularity", granularity, "error", err)
+		writeError(w, "Failed to detect anomalies", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, anomalies)
+}
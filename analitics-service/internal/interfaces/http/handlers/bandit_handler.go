@@ -0,0 +1,114 @@
+// internal/interfaces/http/handlers/bandit_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// BanditHandler обрабатывает запросы выбора уровня скидки многоруким бандитом
+type BanditHandler struct {
+	banditService services.BanditService
+	logger        logger.Logger
+}
+
+// banditOutcomeRequest представляет исход показа руки бандита
+type banditOutcomeRequest struct {
+	ArmID  string  `json:"arm_id"`
+	Reward float64 `json:"reward"`
+}
+
+// NewBanditHandler создает новый обработчик бандитов
+func NewBanditHandler(banditService services.BanditService, logger logger.Logger) *BanditHandler {
+	return &BanditHandler{
+		banditService: banditService,
+		logger:        logger,
+	}
+}
+
+// CreateBandit проверяет и сохраняет бандита из тела запроса
+func (h *BanditHandler) CreateBandit(w http.ResponseWriter, r *http.Request) {
+	var bandit entities.Bandit
+	if err := json.NewDecoder(r.Body).Decode(&bandit); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	if err := h.banditService.CreateBandit(r.Context(), bandit); err != nil {
+		h.logger.Error(r.Context(), "Не удалось создать бандита", "banditID", bandit.ID, "error", err)
+		writeError(w, "Failed to create bandit", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, bandit)
+}
+
+// SelectArm выбирает уровень скидки бандита из пути запроса
+func (h *BanditHandler) SelectArm(w http.ResponseWriter, r *http.Request) {
+	banditID := r.PathValue("id")
+
+	decision, err := h.banditService.SelectArm(r.Context(), banditID)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось выбрать руку бандита", "banditID", banditID, "error", err)
+		writeError(w, "Failed to select arm", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, decision)
+}
+
+// SelectArmFor выбирает уровень скидки активного бандита товара или категории из параметров запроса
+func (h *BanditHandler) SelectArmFor(w http.ResponseWriter, r *http.Request) {
+	productID := r.URL.Query().Get("product_id")
+	category := r.URL.Query().Get("category")
+	if productID == "" && category == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "product_id or category is required"})
+		return
+	}
+
+	decision, err := h.banditService.SelectArmFor(r.Context(), productID, category)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось выбрать руку бандита", "productID", productID, "category", category, "error", err)
+		writeError(w, "Failed to select arm", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, decision)
+}
+
+// RecordOutcome фиксирует исход показа руки бандита из пути запроса
+func (h *BanditHandler) RecordOutcome(w http.ResponseWriter, r *http.Request) {
+	banditID := r.PathValue("id")
+
+	var request banditOutcomeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	if err := h.banditService.RecordOutcome(r.Context(), banditID, request.ArmID, request.Reward); err != nil {
+		h.logger.Error(r.Context(), "Не удалось записать исход руки бандита", "banditID", banditID, "armID", request.ArmID, "error", err)
+		writeError(w, "Failed to record outcome", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "recorded"})
+}
+
+// GetReport возвращает статистику рук и накопленное сожаление бандита
+func (h *BanditHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	banditID := r.PathValue("id")
+
+	report, err := h.banditService.GetReport(r.Context(), banditID)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось построить отчет бандита", "banditID", banditID, "error", err)
+		writeError(w, "Failed to build bandit report", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
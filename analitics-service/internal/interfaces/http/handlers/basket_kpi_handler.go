@@ -0,0 +1,47 @@
+// internal/interfaces/http/handlers/basket_kpi_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// BasketKPIHandler обрабатывает запросы KPI корзины
+type BasketKPIHandler struct {
+	kpiService services.BasketKPIService
+	logger     logger.Logger
+}
+
+// NewBasketKPIHandler создает новый обработчик KPI корзины
+func NewBasketKPIHandler(kpiService services.BasketKPIService, logger logger.Logger) *BasketKPIHandler {
+	return &BasketKPIHandler{
+		kpiService: kpiService,
+		logger:     logger,
+	}
+}
+
+// GetKPIs возвращает KPI корзины по периодам from-to в разрезе by с изменением к предыдущему периоду
+func (h *BasketKPIHandler) GetKPIs(w http.ResponseWriter, r *http.Request) {
+	period, from, to, ok := periodParams(w, r)
+	if !ok {
+		return
+	}
+
+	dimension := entities.BasketKPIDimension(queryString(r, "by", string(entities.BasketKPITotal)))
+	if !dimension.IsValid() {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Dimension must be total, category, daypart or segment"})
+		return
+	}
+
+	report, err := h.kpiService.BuildReport(r.Context(), period, dimension, from, to)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось рассчитать KPI корзины", "period", period, "dimension", dimension, "error", err)
+		writeError(w, "Failed to build basket KPIs", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,96 @@
+// internal/interfaces/http/handlers/bundle_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// BundleHandler обрабатывает запросы наборов товаров
+type BundleHandler struct {
+	bundleService services.BundleService
+	options       entities.BundleOptions
+	logger        logger.Logger
+}
+
+// bundleReviewRequest представляет тело запроса с решением по набору
+type bundleReviewRequest struct {
+	Reviewer string `json:"reviewer"`
+}
+
+// NewBundleHandler создает новый обработчик наборов товаров
+func NewBundleHandler(bundleService services.BundleService, options entities.BundleOptions, logger logger.Logger) *BundleHandler {
+	return &BundleHandler{
+		bundleService: bundleService,
+		options:       options,
+		logger:        logger,
+	}
+}
+
+// RecommendBundles ищет наборы по чекам за период from-to и сохраняет предложения
+func (h *BundleHandler) RecommendBundles(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := queryPeriod(w, r, 90)
+	if !ok {
+		return
+	}
+
+	bundles, err := h.bundleService.RecommendBundles(r.Context(), from, to, h.options)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось подобрать наборы товаров", "error", err)
+		writeError(w, "Failed to recommend bundles", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bundles)
+}
+
+// GetBundles возвращает наборы со статусом status, по умолчанию ожидающие решения
+func (h *BundleHandler) GetBundles(w http.ResponseWriter, r *http.Request) {
+	status := entities.BundleStatus(r.URL.Query().Get("status"))
+	if status == "" {
+		status = entities.BundleProposed
+	}
+
+	bundles, err := h.bundleService.GetBundles(r.Context(), status)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить наборы товаров", "error", err)
+		writeError(w, "Failed to get bundles", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bundles)
+}
+
+// ApproveBundle одобряет предложенный набор
+func (h *BundleHandler) ApproveBundle(w http.ResponseWriter, r *http.Request) {
+	h.reviewBundle(w, r, true)
+}
+
+// RejectBundle отклоняет предложенный набор
+func (h *BundleHandler) RejectBundle(w http.ResponseWriter, r *http.Request) {
+	h.reviewBundle(w, r, false)
+}
+
+// reviewBundle фиксирует решение команды меню по набору из пути запроса
+func (h *BundleHandler) reviewBundle(w http.ResponseWriter, r *http.Request, approve bool) {
+	bundleID := r.PathValue("id")
+
+	var request bundleReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	bundle, err := h.bundleService.ReviewBundle(r.Context(), bundleID, approve, request.Reviewer)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось сохранить решение по набору", "bundleID", bundleID, "error", err)
+		writeError(w, "Failed to review bundle", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bundle)
+}
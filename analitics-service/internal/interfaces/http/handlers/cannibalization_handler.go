@@ -0,0 +1,42 @@
+// internal/interfaces/http/handlers/cannibalization_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// CannibalizationHandler обрабатывает запросы анализа каннибализации и эффекта ореола акций
+type CannibalizationHandler struct {
+	cannibalizationService services.CannibalizationService
+	logger                 logger.Logger
+}
+
+// NewCannibalizationHandler создает новый обработчик анализа перекрестных эффектов
+func NewCannibalizationHandler(cannibalizationService services.CannibalizationService, logger logger.Logger) *CannibalizationHandler {
+	return &CannibalizationHandler{
+		cannibalizationService: cannibalizationService,
+		logger:                 logger,
+	}
+}
+
+// GetCrossEffects оценивает влияние скидки на товар в период from-to (по умолчанию последние 14 дней)
+// на продажи заменителей и дополняющих товаров
+func (h *CannibalizationHandler) GetCrossEffects(w http.ResponseWriter, r *http.Request) {
+	productID := r.PathValue("id")
+	from, to, ok := queryPeriod(w, r, 14)
+	if !ok {
+		return
+	}
+
+	analysis, err := h.cannibalizationService.AnalyzeCrossEffects(r.Context(), productID, from, to)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось оценить перекрестные эффекты акции", "productID", productID, "error", err)
+		writeError(w, "Failed to analyze cross effects", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, analysis)
+}
@@ -0,0 +1,60 @@
+// internal/interfaces/http/handlers/coupon_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// CouponHandler обрабатывает запросы аналитики купонов
+type CouponHandler struct {
+	couponService services.CouponAnalyticsService
+	config        entities.CouponAnalyticsConfig
+	logger        logger.Logger
+}
+
+// NewCouponHandler создает новый обработчик аналитики купонов
+func NewCouponHandler(couponService services.CouponAnalyticsService, config entities.CouponAnalyticsConfig, logger logger.Logger) *CouponHandler {
+	return &CouponHandler{
+		couponService: couponService,
+		config:        config,
+		logger:        logger,
+	}
+}
+
+// GetReport возвращает отчет по купонам и купонным кампаниям за период from-to
+func (h *CouponHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := queryPeriod(w, r, 30)
+	if !ok {
+		return
+	}
+
+	report, err := h.couponService.BuildReport(r.Context(), from, to, h.config)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось построить отчет по купонам", "error", err)
+		writeError(w, "Failed to build coupon report", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// GetAbuseAlerts возвращает подозрительное использование купонов за период from-to
+func (h *CouponHandler) GetAbuseAlerts(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := queryPeriod(w, r, 30)
+	if !ok {
+		return
+	}
+
+	alerts, err := h.couponService.DetectAbuse(r.Context(), from, to, h.config)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось проверить использование купонов", "error", err)
+		writeError(w, "Failed to detect coupon abuse", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, alerts)
+}
@@ -0,0 +1,65 @@
+// internal/interfaces/http/handlers/daypart_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// DaypartHandler обрабатывает запросы анализа продаж по часам и частям дня
+type DaypartHandler struct {
+	daypartService services.DaypartService
+	options        entities.DaypartAnalysisOptions
+	logger         logger.Logger
+}
+
+// NewDaypartHandler создает новый обработчик анализа частей дня
+func NewDaypartHandler(daypartService services.DaypartService, options entities.DaypartAnalysisOptions, logger logger.Logger) *DaypartHandler {
+	return &DaypartHandler{
+		daypartService: daypartService,
+		options:        options,
+		logger:         logger,
+	}
+}
+
+// GetReport строит отчет по часам и частям дня за период from-to, по умолчанию последние 30 дней
+func (h *DaypartHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := queryPeriod(w, r, 30)
+	if !ok {
+		return
+	}
+
+	report, err := h.daypartService.BuildReport(r.Context(), from, to, h.options)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось построить отчет по частям дня", "error", err)
+		writeError(w, "Failed to build daypart report", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// RecommendDiscounts оценивает оптимальную скидку товара для каждой части дня по продажам
+// за период from-to, по умолчанию последние 90 дней
+func (h *DaypartHandler) RecommendDiscounts(w http.ResponseWriter, r *http.Request) {
+	productID := r.PathValue("id")
+	from, to, ok := queryPeriod(w, r, 90)
+	if !ok {
+		return
+	}
+
+	recommendations, err := h.daypartService.RecommendDiscountsByDaypart(r.Context(), productID, from, to, h.options)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось рассчитать скидки по частям дня", "productID", productID, "error", err)
+		writeError(w, "Failed to recommend daypart discounts", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":           len(recommendations),
+		"recommendations": recommendations,
+	})
+}
@@ -0,0 +1,38 @@
+// internal/interfaces/http/handlers/event_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// EventHandler обрабатывает запросы публикации событий аналитики
+type EventHandler struct {
+	eventService services.AnalyticsEventService
+	logger       logger.Logger
+}
+
+// NewEventHandler создает новый обработчик событий аналитики
+func NewEventHandler(eventService services.AnalyticsEventService, logger logger.Logger) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+		logger:       logger,
+	}
+}
+
+// PublishSnapshot ставит в очередь снимок текущего состояния потока из пути запроса
+func (h *EventHandler) PublishSnapshot(w http.ResponseWriter, r *http.Request) {
+	stream := entities.EventStream(r.PathValue("stream"))
+
+	event, err := h.eventService.PublishSnapshot(r.Context(), stream)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось опубликовать снимок потока событий", "stream", stream, "error", err)
+		writeError(w, "Failed to publish snapshot", err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, event)
+}
@@ -0,0 +1,144 @@
+// internal/interfaces/http/handlers/experiment_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// ExperimentHandler обрабатывает запросы реестра экспериментов, назначения клиентов и журнала показов
+type ExperimentHandler struct {
+	experimentService services.ExperimentService
+	logger            logger.Logger
+}
+
+// experimentCustomerRequest представляет клиента и его сегменты для назначения в эксперимент
+type experimentCustomerRequest struct {
+	CustomerID string   `json:"customer_id"`
+	Segments   []string `json:"segments,omitempty"`
+}
+
+// experimentExposureRequest представляет показ эксперимента клиенту
+// Вариант определяется назначением на сервере; без exposed_at используется время запроса
+type experimentExposureRequest struct {
+	experimentCustomerRequest
+	Channel   string    `json:"channel,omitempty"`
+	ExposedAt time.Time `json:"exposed_at"`
+}
+
+// experimentResultsResponse представляет результаты эксперимента и наблюдения групп для бутстрепа по ID теста
+type experimentResultsResponse struct {
+	Results []entities.ABTestResult            `json:"results"`
+	Samples map[string]*entities.ABTestSamples `json:"samples"`
+}
+
+// NewExperimentHandler создает новый обработчик экспериментов
+func NewExperimentHandler(experimentService services.ExperimentService, logger logger.Logger) *ExperimentHandler {
+	return &ExperimentHandler{
+		experimentService: experimentService,
+		logger:            logger,
+	}
+}
+
+// RegisterExperiment проверяет и сохраняет эксперимент из тела запроса
+func (h *ExperimentHandler) RegisterExperiment(w http.ResponseWriter, r *http.Request) {
+	var experiment entities.Experiment
+	if err := json.NewDecoder(r.Body).Decode(&experiment); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	if err := h.experimentService.RegisterExperiment(r.Context(), experiment); err != nil {
+		h.logger.Error(r.Context(), "Не удалось зарегистрировать эксперимент", "experimentID", experiment.ID, "error", err)
+		writeError(w, "Failed to register experiment", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, experiment)
+}
+
+// AssignCustomer назначает клиента в вариант эксперимента из пути запроса
+func (h *ExperimentHandler) AssignCustomer(w http.ResponseWriter, r *http.Request) {
+	experimentID := r.PathValue("id")
+
+	var request experimentCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	assignment, err := h.experimentService.Assign(r.Context(), experimentID, request.CustomerID, request.Segments)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось назначить клиента в эксперимент", "experimentID", experimentID, "error", err)
+		writeError(w, "Failed to assign customer", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, assignment)
+}
+
+// AssignCustomerToAll назначает клиента во все активные эксперименты, по одному на слой
+func (h *ExperimentHandler) AssignCustomerToAll(w http.ResponseWriter, r *http.Request) {
+	var request experimentCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	assignments, err := h.experimentService.AssignAll(r.Context(), request.CustomerID, request.Segments, time.Now())
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось назначить клиента в эксперименты", "error", err)
+		writeError(w, "Failed to assign customer", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, assignments)
+}
+
+// LogExposure назначает клиента в вариант эксперимента и фиксирует показ этого варианта
+func (h *ExperimentHandler) LogExposure(w http.ResponseWriter, r *http.Request) {
+	experimentID := r.PathValue("id")
+
+	var request experimentExposureRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if request.ExposedAt.IsZero() {
+		request.ExposedAt = time.Now()
+	}
+
+	assignment, err := h.experimentService.Assign(r.Context(), experimentID, request.CustomerID, request.Segments)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось назначить клиента в эксперимент", "experimentID", experimentID, "error", err)
+		writeError(w, "Failed to assign customer", err)
+		return
+	}
+
+	if err := h.experimentService.LogExposure(r.Context(), *assignment, request.ExposedAt, request.Channel); err != nil {
+		h.logger.Error(r.Context(), "Не удалось сохранить показ эксперимента", "experimentID", experimentID, "error", err)
+		writeError(w, "Failed to log exposure", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, assignment)
+}
+
+// ComputeResults рассчитывает и сохраняет результаты A/B тестов эксперимента по продажам после первого показа
+func (h *ExperimentHandler) ComputeResults(w http.ResponseWriter, r *http.Request) {
+	experimentID := r.PathValue("id")
+
+	results, samples, err := h.experimentService.ComputeTestResults(r.Context(), experimentID)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось рассчитать результаты эксперимента", "experimentID", experimentID, "error", err)
+		writeError(w, "Failed to compute experiment results", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, experimentResultsResponse{Results: results, Samples: samples})
+}
@@ -0,0 +1,81 @@
+// internal/interfaces/http/handlers/export_handler.go
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/export"
+	"analitics-service/pkg/logger"
+)
+
+// ExportHandler обрабатывает запросы выгрузки аналитических результатов в файлы
+type ExportHandler struct {
+	exportService services.ExportService
+	logger        logger.Logger
+}
+
+// responseStartWriter отмечает, что тело ответа уже начало записываться
+type responseStartWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *responseStartWriter) Write(p []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(p)
+}
+
+// NewExportHandler создает новый обработчик выгрузок
+func NewExportHandler(exportService services.ExportService, logger logger.Logger) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// Export отдает набор данных файлом в формате format (csv, xlsx, parquet)
+// Треугольник удержания строится за период from-to с гранулярностью period, прогнозы выгружаются
+// построенные после from на уровне level, рекомендации — последние limit штук
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format, err := export.ParseFormat(queryString(r, "format", string(export.FormatCSV)))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	from, to, ok := queryPeriod(w, r, 90)
+	if !ok {
+		return
+	}
+	limit, err := queryInt(r, "limit", 1000)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	request := entities.ExportRequest{
+		Dataset:   entities.ExportDataset(r.PathValue("dataset")),
+		StartDate: from,
+		EndDate:   to,
+		Period:    entities.TimeRange(queryString(r, "period", string(entities.Weekly))),
+		Level:     entities.ForecastLevel(queryString(r, "level", string(entities.ForecastLevelProduct))),
+		Limit:     limit,
+	}
+
+	filename := fmt.Sprintf("%s_%s%s", request.Dataset, today().Format("20060102"), format.Extension())
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	body := &responseStartWriter{ResponseWriter: w}
+	if err := h.exportService.Export(r.Context(), request, format, body); err != nil {
+		h.logger.Error(r.Context(), "Не удалось выгрузить данные", "dataset", request.Dataset, "format", format, "error", err)
+		// После начала передачи файла статус изменить нельзя, клиент получит оборванный файл
+		if !body.started {
+			w.Header().Del("Content-Disposition")
+			writeError(w, "Failed to export data", err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// internal/interfaces/http/handlers/forecast_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// ForecastHandler обрабатывает запросы прогнозов спроса
+type ForecastHandler struct {
+	forecastService services.ForecastService
+	logger          logger.Logger
+}
+
+// NewForecastHandler создает новый обработчик прогнозов спроса
+func NewForecastHandler(forecastService services.ForecastService, logger logger.Logger) *ForecastHandler {
+	return &ForecastHandler{
+		forecastService: forecastService,
+		logger:          logger,
+	}
+}
+
+// GetLatestForecast возвращает последний сохраненный прогноз товара или категории
+func (h *ForecastHandler) GetLatestForecast(w http.ResponseWriter, r *http.Request) {
+	level := entities.ForecastLevel(r.PathValue("level"))
+	targetID := r.PathValue("id")
+	if targetID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Target ID is required"})
+		return
+	}
+
+	forecast, err := h.forecastService.GetLatestForecast(r.Context(), level, targetID)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить прогноз", "level", level, "target", targetID, "error", err)
+		writeError(w, "Failed to get forecast", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, forecast)
+}
+
+// BuildForecast строит новый прогноз товара или категории
+// Параметры запроса horizon и history переопределяют значения по умолчанию
+func (h *ForecastHandler) BuildForecast(w http.ResponseWriter, r *http.Request) {
+	config, ok := h.forecastConfig(w, r)
+	if !ok {
+		return
+	}
+
+	level := entities.ForecastLevel(r.PathValue("level"))
+	targetID := r.PathValue("id")
+
+	var (
+		forecast *entities.DemandForecast
+		err      error
+	)
+	switch level {
+	case entities.ForecastLevelProduct:
+		forecast, err = h.forecastService.ForecastProduct(r.Context(), targetID, config)
+	case entities.ForecastLevelCategory:
+		forecast, err = h.forecastService.ForecastCategory(r.Context(), targetID, config)
+	default:
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Level must be product or category"})
+		return
+	}
+
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось построить прогноз", "level", level, "target", targetID, "error", err)
+		writeError(w, "Failed to build forecast", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, forecast)
+}
+
+// RunForecasts строит прогнозы для всех товаров и категорий
+func (h *ForecastHandler) RunForecasts(w http.ResponseWriter, r *http.Request) {
+	config, ok := h.forecastConfig(w, r)
+	if !ok {
+		return
+	}
+
+	forecasts, err := h.forecastService.RunForecasts(r.Context(), config)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось построить прогнозы", "error", err)
+		writeError(w, "Failed to run forecasts", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":     len(forecasts),
+		"forecasts": forecasts,
+	})
+}
+
+// forecastConfig собирает параметры прогноза из запроса
+func (h *ForecastHandler) forecastConfig(w http.ResponseWriter, r *http.Request) (entities.ForecastConfig, bool) {
+	config := entities.DefaultForecastConfig()
+
+	var err error
+	if config.HorizonDays, err = queryInt(r, "horizon", config.HorizonDays); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return config, false
+	}
+	if config.HistoryDays, err = queryInt(r, "history", config.HistoryDays); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return config, false
+	}
+
+	return config, true
+}
@@ -0,0 +1,107 @@
+// internal/interfaces/http/handlers/job_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"analitics-service/internal/infrastructure/scheduler"
+	"analitics-service/pkg/logger"
+)
+
+// JobHandler обрабатывает запросы фоновых аналитических задач
+type JobHandler struct {
+	scheduler *scheduler.Scheduler
+	logger    logger.Logger
+}
+
+// jobRunRequest представляет тело запроса ручного запуска задачи
+type jobRunRequest struct {
+	Params scheduler.Params `json:"params"`
+}
+
+// NewJobHandler создает новый обработчик фоновых задач
+func NewJobHandler(scheduler *scheduler.Scheduler, logger logger.Logger) *JobHandler {
+	return &JobHandler{
+		scheduler: scheduler,
+		logger:    logger,
+	}
+}
+
+// GetJobs возвращает зарегистрированные задачи с расписанием и временем следующего запуска
+func (h *JobHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.scheduler.Jobs())
+}
+
+// RunJob запускает задачу вручную; параметры из тела запроса переопределяют параметры конфигурации
+func (h *JobHandler) RunJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	var request jobRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	run, err := h.scheduler.RunNow(r.Context(), name, request.Params)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось запустить задачу", "job", name, "error", err)
+		writeJobError(w, "Failed to run job", err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+// GetRuns возвращает историю запусков задачи job, по умолчанию всех задач
+func (h *JobHandler) GetRuns(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", 50)
+	if err != nil || limit <= 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid limit parameter"})
+		return
+	}
+
+	runs, err := h.scheduler.GetRuns(r.Context(), r.URL.Query().Get("job"), limit)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить историю запусков", "error", err)
+		writeJobError(w, "Failed to get job runs", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, runs)
+}
+
+// GetRun возвращает запуск задачи по ID
+func (h *JobHandler) GetRun(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+
+	run, err := h.scheduler.GetRun(r.Context(), runID)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить запуск задачи", "runID", runID, "error", err)
+		writeJobError(w, "Failed to get job run", err)
+		return
+	}
+	if run == nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "Job run not found"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// writeJobError отправляет ошибку планировщика: 404 для неизвестной задачи, 409 для уже выполняющейся,
+// 503 при остановке планировщика
+func writeJobError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: message, Details: err.Error()})
+	case errors.Is(err, scheduler.ErrJobLocked):
+		writeJSON(w, http.StatusConflict, errorResponse{Error: message, Details: err.Error()})
+	case errors.Is(err, scheduler.ErrStopped):
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: message, Details: err.Error()})
+	default:
+		writeError(w, message, err)
+	}
+}
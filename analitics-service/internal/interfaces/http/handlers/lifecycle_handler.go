@@ -0,0 +1,70 @@
+// internal/interfaces/http/handlers/lifecycle_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// LifecycleHandler обрабатывает запросы жизненного цикла товаров
+type LifecycleHandler struct {
+	lifecycleService services.LifecycleService
+	config           entities.LifecycleConfig
+	logger           logger.Logger
+}
+
+// NewLifecycleHandler создает новый обработчик жизненного цикла товаров
+func NewLifecycleHandler(lifecycleService services.LifecycleService, config entities.LifecycleConfig, logger logger.Logger) *LifecycleHandler {
+	return &LifecycleHandler{
+		lifecycleService: lifecycleService,
+		config:           config,
+		logger:           logger,
+	}
+}
+
+// ClassifyProducts классифицирует товары по полным неделям до даты date
+func (h *LifecycleHandler) ClassifyProducts(w http.ResponseWriter, r *http.Request) {
+	date, err := queryDate(r, "date", today())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	lifecycles, err := h.lifecycleService.ClassifyProducts(r.Context(), date, h.config)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось классифицировать жизненный цикл товаров", "error", err)
+		writeError(w, "Failed to classify product lifecycles", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lifecycles)
+}
+
+// GetLifecycles возвращает результаты последней классификации, параметр stage фильтрует по стадии
+func (h *LifecycleHandler) GetLifecycles(w http.ResponseWriter, r *http.Request) {
+	stage := entities.LifecycleStage(r.URL.Query().Get("stage"))
+
+	lifecycles, err := h.lifecycleService.GetLifecycles(r.Context(), stage)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить жизненный цикл товаров", "error", err)
+		writeError(w, "Failed to get product lifecycles", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lifecycles)
+}
+
+// GetDelistingCandidates возвращает кандидатов на вывод из ассортимента
+func (h *LifecycleHandler) GetDelistingCandidates(w http.ResponseWriter, r *http.Request) {
+	candidates, err := h.lifecycleService.GetDelistingCandidates(r.Context())
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить кандидатов на вывод из ассортимента", "error", err)
+		writeError(w, "Failed to get delisting candidates", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, candidates)
+}
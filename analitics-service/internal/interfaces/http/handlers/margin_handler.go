@@ -0,0 +1,78 @@
+// internal/interfaces/http/handlers/margin_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// MarginHandler обрабатывает запросы истории себестоимости и аналитики маржи
+type MarginHandler struct {
+	marginService services.MarginService
+	logger        logger.Logger
+}
+
+// NewMarginHandler создает новый обработчик аналитики маржи
+func NewMarginHandler(marginService services.MarginService, logger logger.Logger) *MarginHandler {
+	return &MarginHandler{
+		marginService: marginService,
+		logger:        logger,
+	}
+}
+
+// GetErosionReport возвращает отчет о размывании маржи скидками и ростом себестоимости за период from-to
+func (h *MarginHandler) GetErosionReport(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := queryPeriod(w, r, 30)
+	if !ok {
+		return
+	}
+
+	report, err := h.marginService.BuildErosionReport(r.Context(), from, to)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось построить отчет о размывании маржи", "error", err)
+		writeError(w, "Failed to build margin erosion report", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// GetCostHistory возвращает историю себестоимости и цен товара из пути запроса
+func (h *MarginHandler) GetCostHistory(w http.ResponseWriter, r *http.Request) {
+	productID := r.PathValue("id")
+
+	costs, err := h.marginService.GetCostHistory(r.Context(), productID)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить историю себестоимости", "productID", productID, "error", err)
+		writeError(w, "Failed to get product cost history", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, costs)
+}
+
+// RecordCosts сохраняет записи истории себестоимости товара из тела запроса
+func (h *MarginHandler) RecordCosts(w http.ResponseWriter, r *http.Request) {
+	productID := r.PathValue("id")
+
+	var costs []entities.ProductCost
+	if err := json.NewDecoder(r.Body).Decode(&costs); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+	for i := range costs {
+		costs[i].ProductID = productID
+	}
+
+	if err := h.marginService.RecordCosts(r.Context(), costs); err != nil {
+		h.logger.Error(r.Context(), "Не удалось сохранить историю себестоимости", "productID", productID, "error", err)
+		writeError(w, "Failed to record product costs", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, costs)
+}
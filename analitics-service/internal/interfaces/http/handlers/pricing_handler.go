@@ -0,0 +1,87 @@
+// internal/interfaces/http/handlers/pricing_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// PricingHandler обрабатывает запросы движка динамического ценообразования
+type PricingHandler struct {
+	pricingService services.PricingService
+	config         entities.PricingConfig
+	logger         logger.Logger
+}
+
+// NewPricingHandler создает новый обработчик ценообразования
+func NewPricingHandler(pricingService services.PricingService, config entities.PricingConfig, logger logger.Logger) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricingService,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+// SuggestPrices рассчитывает цены товара по временным слотам на дату из параметра date
+func (h *PricingHandler) SuggestPrices(w http.ResponseWriter, r *http.Request) {
+	productID := r.PathValue("id")
+	date, err := queryDate(r, "date", today())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	suggestions, err := h.pricingService.SuggestPrices(r.Context(), productID, date, h.config)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось рассчитать цены", "productID", productID, "error", err)
+		writeError(w, "Failed to suggest prices", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, suggestions)
+}
+
+// RunBatch запускает пакетный расчет цен всех активных товаров
+func (h *PricingHandler) RunBatch(w http.ResponseWriter, r *http.Request) {
+	date, err := queryDate(r, "date", today())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	run, err := h.pricingService.RunBatch(r.Context(), date, h.config)
+	if err != nil {
+		h.logger.Error(r.Context(), "Пакетный расчет цен завершился ошибкой", "error", err)
+		writeError(w, "Failed to run pricing batch", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// GetPriceHistory возвращает журнал предложенных цен товара за период from-to
+func (h *PricingHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	productID := r.PathValue("id")
+	to, err := queryDate(r, "to", today().AddDate(0, 0, 1))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	from, err := queryDate(r, "from", to.AddDate(0, 0, -30))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	history, err := h.pricingService.GetPriceHistory(r.Context(), productID, from, to)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить журнал цен", "productID", productID, "error", err)
+		writeError(w, "Failed to get price history", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
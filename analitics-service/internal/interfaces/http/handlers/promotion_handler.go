@@ -0,0 +1,65 @@
+// internal/interfaces/http/handlers/promotion_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// PromotionHandler обрабатывает запросы промо-календаря и оценки эффекта акций
+type PromotionHandler struct {
+	impactService services.PromotionImpactService
+	logger        logger.Logger
+}
+
+// NewPromotionHandler создает новый обработчик промо-календаря
+func NewPromotionHandler(impactService services.PromotionImpactService, logger logger.Logger) *PromotionHandler {
+	return &PromotionHandler{
+		impactService: impactService,
+		logger:        logger,
+	}
+}
+
+// CreatePromotion проверяет и сохраняет акцию из тела запроса
+func (h *PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	var promotion entities.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	if err := h.impactService.CreatePromotion(r.Context(), promotion); err != nil {
+		h.logger.Error(r.Context(), "Не удалось сохранить акцию", "promotionID", promotion.ID, "error", err)
+		writeError(w, "Failed to create promotion", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, promotion)
+}
+
+// MeasureImpact оценивает эффект акции из пути запроса методом разности разностей
+// Без тела запроса пред-период равен длительности акции, а пост-период не оценивается
+func (h *PromotionHandler) MeasureImpact(w http.ResponseWriter, r *http.Request) {
+	promotionID := r.PathValue("id")
+
+	var options entities.PromotionImpactOptions
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	impact, err := h.impactService.MeasureImpact(r.Context(), promotionID, options)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось оценить эффект акции", "promotionID", promotionID, "error", err)
+		writeError(w, "Failed to measure promotion impact", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, impact)
+}
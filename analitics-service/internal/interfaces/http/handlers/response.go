@@ -0,0 +1,121 @@
+// internal/interfaces/http/handlers/response.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+)
+
+// errorResponse представляет тело ответа с ошибкой
+type errorResponse struct {
+	Error   string `json:"error"`
+	Details string `json:"details,omitempty"`
+}
+
+// writeJSON сериализует ответ в JSON с указанным статусом
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError отправляет ошибку, выбирая статус по типу ошибки сервиса
+func writeError(w http.ResponseWriter, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidParameter):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrInsufficientData), errors.Is(err, services.ErrNoFeasiblePrice),
+		errors.Is(err, services.ErrInvalidGroupStats), errors.Is(err, services.ErrCustomerNotEligible),
+		errors.Is(err, services.ErrNoExperimentExposure):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrAnalysisRunNotFound), errors.Is(err, services.ErrABTestNotFound),
+		errors.Is(err, services.ErrExperimentNotFound), errors.Is(err, services.ErrBanditNotFound),
+		errors.Is(err, services.ErrUnknownArm), errors.Is(err, services.ErrUpliftModelNotFound),
+		errors.Is(err, services.ErrPromotionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrExperimentOverlap), errors.Is(err, services.ErrExperimentNotActive),
+		errors.Is(err, services.ErrBanditExists), errors.Is(err, services.ErrBanditInactive):
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, errorResponse{Error: message, Details: err.Error()})
+}
+
+// queryString возвращает строковый параметр запроса или значение по умолчанию
+func queryString(r *http.Request, name, fallback string) string {
+	if value := r.URL.Query().Get(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// queryInt возвращает целочисленный параметр запроса или значение по умолчанию
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, errors.New("invalid " + name + " parameter")
+	}
+	return value, nil
+}
+
+// queryDate возвращает дату из параметра запроса в формате YYYY-MM-DD или значение по умолчанию
+func queryDate(r *http.Request, name string, fallback time.Time) (time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, errors.New("invalid " + name + " parameter, expected YYYY-MM-DD")
+	}
+	return value, nil
+}
+
+// today возвращает начало текущего дня в UTC
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// queryPeriod разбирает диапазон дат from-to, по умолчанию последние days дней, включая текущий
+// При ошибке отправляет ответ 400 и возвращает false
+func queryPeriod(w http.ResponseWriter, r *http.Request, days int) (time.Time, time.Time, bool) {
+	to, err := queryDate(r, "to", today().AddDate(0, 0, 1))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return time.Time{}, time.Time{}, false
+	}
+	from, err := queryDate(r, "from", to.AddDate(0, 0, -days))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
+
+// periodParams разбирает период из пути и диапазон дат from-to, по умолчанию последние 90 дней
+func periodParams(w http.ResponseWriter, r *http.Request) (entities.TimeRange, time.Time, time.Time, bool) {
+	period := entities.TimeRange(r.PathValue("period"))
+	if !period.IsValid() {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Period must be daily, weekly or monthly"})
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	from, to, ok := queryPeriod(w, r, 90)
+	if !ok {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	return period, from, to, true
+}
@@ -0,0 +1,74 @@
+// internal/interfaces/http/handlers/retention_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// RetentionHandler обрабатывает запросы аналитики удержания клиентов
+type RetentionHandler struct {
+	retentionService services.RetentionService
+	logger           logger.Logger
+}
+
+// NewRetentionHandler создает новый обработчик аналитики удержания
+func NewRetentionHandler(retentionService services.RetentionService, logger logger.Logger) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+		logger:           logger,
+	}
+}
+
+// ComputeMetrics рассчитывает и сохраняет метрики удержания за период from-to
+func (h *RetentionHandler) ComputeMetrics(w http.ResponseWriter, r *http.Request) {
+	period, from, to, ok := periodParams(w, r)
+	if !ok {
+		return
+	}
+
+	metrics, err := h.retentionService.ComputeMetrics(r.Context(), period, from, to)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось рассчитать метрики удержания", "period", period, "error", err)
+		writeError(w, "Failed to compute retention metrics", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, metrics)
+}
+
+// GetCohortTriangle возвращает треугольник удержания когорт, привлеченных в период from-to
+func (h *RetentionHandler) GetCohortTriangle(w http.ResponseWriter, r *http.Request) {
+	period, from, to, ok := periodParams(w, r)
+	if !ok {
+		return
+	}
+
+	triangle, err := h.retentionService.BuildCohortTriangle(r.Context(), period, from, to)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось построить треугольник удержания", "period", period, "error", err)
+		writeError(w, "Failed to build cohort triangle", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, triangle)
+}
+
+// GetTrend возвращает ряд сохраненных метрик удержания за период from-to
+func (h *RetentionHandler) GetTrend(w http.ResponseWriter, r *http.Request) {
+	period, from, to, ok := periodParams(w, r)
+	if !ok {
+		return
+	}
+
+	trend, err := h.retentionService.GetTrend(r.Context(), period, from, to)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось получить динамику удержания", "period", period, "error", err)
+		writeError(w, "Failed to get retention trend", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, trend)
+}
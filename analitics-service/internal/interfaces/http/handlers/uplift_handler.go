@@ -0,0 +1,138 @@
+// internal/interfaces/http/handlers/uplift_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/internal/infrastructure/services"
+	"analitics-service/pkg/logger"
+)
+
+// UpliftHandler обрабатывает запросы обучения uplift-моделей и таргетирования скидок
+type UpliftHandler struct {
+	upliftService services.UpliftService
+	config        entities.UpliftModelConfig
+	logger        logger.Logger
+}
+
+// upliftTrainRequest представляет тело запроса обучения модели
+// Без config используются параметры обучения по умолчанию с экспериментами из experiment_ids
+type upliftTrainRequest struct {
+	ModelID       string                      `json:"model_id"`
+	ExperimentIDs []string                    `json:"experiment_ids,omitempty"`
+	Config        *entities.UpliftModelConfig `json:"config,omitempty"`
+}
+
+// upliftScoreRequest представляет клиентов для оценки; пустой список означает всех клиентов с историей
+type upliftScoreRequest struct {
+	CustomerIDs []string `json:"customer_ids,omitempty"`
+}
+
+// upliftTargetRequest представляет параметры целевого списка; limit 0 снимает ограничение размера
+type upliftTargetRequest struct {
+	MinUplift float64 `json:"min_uplift"`
+	Limit     int     `json:"limit"`
+}
+
+// NewUpliftHandler создает новый обработчик uplift-моделей
+func NewUpliftHandler(upliftService services.UpliftService, config entities.UpliftModelConfig, logger logger.Logger) *UpliftHandler {
+	return &UpliftHandler{
+		upliftService: upliftService,
+		config:        config,
+		logger:        logger,
+	}
+}
+
+// TrainModel обучает и сохраняет uplift-модель на показах экспериментов
+func (h *UpliftHandler) TrainModel(w http.ResponseWriter, r *http.Request) {
+	var request upliftTrainRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if request.ModelID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "model_id is required"})
+		return
+	}
+
+	config := h.config
+	if request.Config != nil {
+		config = *request.Config
+	}
+	if len(request.ExperimentIDs) > 0 {
+		config.ExperimentIDs = request.ExperimentIDs
+	}
+
+	model, err := h.upliftService.TrainModel(r.Context(), request.ModelID, config)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось обучить uplift-модель", "modelID", request.ModelID, "error", err)
+		writeError(w, "Failed to train uplift model", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, model)
+}
+
+// ScoreCustomers рассчитывает uplift клиентов на дату as_of (по умолчанию сегодня) моделью из пути запроса
+func (h *UpliftHandler) ScoreCustomers(w http.ResponseWriter, r *http.Request) {
+	modelID := r.PathValue("id")
+	asOf, err := queryDate(r, "as_of", today())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	var request upliftScoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	scores, err := h.upliftService.ScoreCustomers(r.Context(), modelID, request.CustomerIDs, asOf)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось рассчитать uplift клиентов", "modelID", modelID, "error", err)
+		writeError(w, "Failed to score customers", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":  len(scores),
+		"scores": scores,
+	})
+}
+
+// BuildTargetList формирует и сохраняет целевой список клиентов на дату as_of (по умолчанию сегодня)
+func (h *UpliftHandler) BuildTargetList(w http.ResponseWriter, r *http.Request) {
+	modelID := r.PathValue("id")
+	asOf, err := queryDate(r, "as_of", today())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	var request upliftTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+	if request.Limit < 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "limit must not be negative"})
+		return
+	}
+
+	targets, err := h.upliftService.BuildTargetList(r.Context(), modelID, asOf, request.MinUplift, request.Limit)
+	if err != nil {
+		h.logger.Error(r.Context(), "Не удалось сформировать целевой список", "modelID", modelID, "error", err)
+		writeError(w, "Failed to build target list", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":   len(targets),
+		"targets": targets,
+	})
+}
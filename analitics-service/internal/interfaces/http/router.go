@@ -0,0 +1,231 @@
+// internal/interfaces/http/router.go
+package http
+
+import (
+	nethttp "net/http"
+
+	"analitics-service/internal/interfaces/http/handlers"
+)
+
+// SetupRouter настраивает все маршруты API аналитического сервиса
+// Принимает обработчики для различных доменных областей и возвращает настроенный роутер
+func SetupRouter(
+	forecastHandler *handlers.ForecastHandler,
+	pricingHandler *handlers.PricingHandler,
+	anomalyHandler *handlers.AnomalyHandler,
+	retentionHandler *handlers.RetentionHandler,
+	couponHandler *handlers.CouponHandler,
+	bundleHandler *handlers.BundleHandler,
+	lifecycleHandler *handlers.LifecycleHandler,
+	jobHandler *handlers.JobHandler,
+	analysisRunHandler *handlers.AnalysisRunHandler,
+	exportHandler *handlers.ExportHandler,
+	eventHandler *handlers.EventHandler,
+	marginHandler *handlers.MarginHandler,
+	basketKPIHandler *handlers.BasketKPIHandler,
+	abTestHandler *handlers.ABTestHandler,
+	experimentHandler *handlers.ExperimentHandler,
+	banditHandler *handlers.BanditHandler,
+	upliftHandler *handlers.UpliftHandler,
+	cannibalizationHandler *handlers.CannibalizationHandler,
+	promotionHandler *handlers.PromotionHandler,
+	daypartHandler *handlers.DaypartHandler,
+) *nethttp.ServeMux {
+	router := nethttp.NewServeMux()
+
+	// --- Прогнозы спроса ---
+	// POST /api/v1/forecasts/run - Построение прогнозов для всех товаров и категорий
+	router.HandleFunc("POST /api/v1/forecasts/run", forecastHandler.RunForecasts)
+
+	// GET /api/v1/forecasts/{level}/{id} - Последний прогноз товара (product) или категории (category)
+	router.HandleFunc("GET /api/v1/forecasts/{level}/{id}", forecastHandler.GetLatestForecast)
+
+	// POST /api/v1/forecasts/{level}/{id}?horizon=14&history=365 - Построение прогноза
+	router.HandleFunc("POST /api/v1/forecasts/{level}/{id}", forecastHandler.BuildForecast)
+
+	// --- Динамическое ценообразование ---
+	// POST /api/v1/pricing/run?date=YYYY-MM-DD - Пакетный расчет цен всех активных товаров
+	router.HandleFunc("POST /api/v1/pricing/run", pricingHandler.RunBatch)
+
+	// POST /api/v1/pricing/products/{id}?date=YYYY-MM-DD - Расчет цен товара по временным слотам
+	router.HandleFunc("POST /api/v1/pricing/products/{id}", pricingHandler.SuggestPrices)
+
+	// GET /api/v1/pricing/products/{id}/history?from=&to= - Журнал предложенных цен товара
+	router.HandleFunc("GET /api/v1/pricing/products/{id}/history", pricingHandler.GetPriceHistory)
+
+	// --- Аномалии продаж ---
+	// GET /api/v1/anomalies?from=&to=&severity= - Найденные аномалии за период
+	router.HandleFunc("GET /api/v1/anomalies", anomalyHandler.GetAnomalies)
+
+	// POST /api/v1/anomalies/detect?granularity=daily|hourly&date= - Поиск аномалий
+	router.HandleFunc("POST /api/v1/anomalies/detect", anomalyHandler.DetectAnomalies)
+
+	// --- Удержание клиентов ---
+	// POST /api/v1/retention/{period}/compute?from=&to= - Расчет и сохранение метрик удержания
+	router.HandleFunc("POST /api/v1/retention/{period}/compute", retentionHandler.ComputeMetrics)
+
+	// GET /api/v1/retention/{period}/cohorts?from=&to= - Треугольник удержания когорт
+	router.HandleFunc("GET /api/v1/retention/{period}/cohorts", retentionHandler.GetCohortTriangle)
+
+	// GET /api/v1/retention/{period}/trend?from=&to= - Динамика метрик удержания
+	router.HandleFunc("GET /api/v1/retention/{period}/trend", retentionHandler.GetTrend)
+
+	// --- Купоны ---
+	// GET /api/v1/coupons/report?from=&to= - Эффективность купонов и купонных кампаний
+	router.HandleFunc("GET /api/v1/coupons/report", couponHandler.GetReport)
+
+	// GET /api/v1/coupons/abuse?from=&to= - Подозрительное использование купонов
+	router.HandleFunc("GET /api/v1/coupons/abuse", couponHandler.GetAbuseAlerts)
+
+	// --- Наборы товаров ---
+	// POST /api/v1/bundles/recommend?from=&to= - Поиск и сохранение предложенных наборов
+	router.HandleFunc("POST /api/v1/bundles/recommend", bundleHandler.RecommendBundles)
+
+	// GET /api/v1/bundles?status= - Наборы по статусу согласования
+	router.HandleFunc("GET /api/v1/bundles", bundleHandler.GetBundles)
+
+	// POST /api/v1/bundles/{id}/approve - Одобрение набора командой меню
+	router.HandleFunc("POST /api/v1/bundles/{id}/approve", bundleHandler.ApproveBundle)
+
+	// POST /api/v1/bundles/{id}/reject - Отклонение набора командой меню
+	router.HandleFunc("POST /api/v1/bundles/{id}/reject", bundleHandler.RejectBundle)
+
+	// --- Жизненный цикл товаров ---
+	// POST /api/v1/lifecycle/classify?date= - Классификация товаров по стадиям жизненного цикла
+	router.HandleFunc("POST /api/v1/lifecycle/classify", lifecycleHandler.ClassifyProducts)
+
+	// GET /api/v1/lifecycle?stage= - Стадии жизненного цикла по последней классификации
+	router.HandleFunc("GET /api/v1/lifecycle", lifecycleHandler.GetLifecycles)
+
+	// GET /api/v1/lifecycle/delisting - Кандидаты на вывод из ассортимента
+	router.HandleFunc("GET /api/v1/lifecycle/delisting", lifecycleHandler.GetDelistingCandidates)
+
+	// --- Фоновые задачи ---
+	// GET /api/v1/jobs - Зарегистрированные задачи и их расписание
+	router.HandleFunc("GET /api/v1/jobs", jobHandler.GetJobs)
+
+	// POST /api/v1/jobs/{name}/run - Ручной запуск задачи с переопределением параметров
+	router.HandleFunc("POST /api/v1/jobs/{name}/run", jobHandler.RunJob)
+
+	// GET /api/v1/jobs/runs?job=&limit= - История запусков задач
+	router.HandleFunc("GET /api/v1/jobs/runs", jobHandler.GetRuns)
+
+	// GET /api/v1/jobs/runs/{id} - Запуск задачи по ID
+	router.HandleFunc("GET /api/v1/jobs/runs/{id}", jobHandler.GetRun)
+
+	// --- Воспроизводимые запуски анализа ---
+	// POST /api/v1/analyses/abc - ABC-анализ с критериями из тела запроса
+	router.HandleFunc("POST /api/v1/analyses/abc", analysisRunHandler.RunABCAnalysis)
+
+	// POST /api/v1/analyses/rules - Поиск ассоциативных правил с параметрами из тела запроса
+	router.HandleFunc("POST /api/v1/analyses/rules", analysisRunHandler.RunAssociationRules)
+
+	// POST /api/v1/analyses/discounts?from=&to= - Рекомендации по скидкам за период
+	router.HandleFunc("POST /api/v1/analyses/discounts", analysisRunHandler.RunDiscountRecommendations)
+
+	// GET /api/v1/analyses/runs?type=&limit= - Последние запуски анализа
+	router.HandleFunc("GET /api/v1/analyses/runs", analysisRunHandler.GetRuns)
+
+	// GET /api/v1/analyses/runs/{id} - Параметры, версия кода и отпечаток входных данных запуска
+	router.HandleFunc("GET /api/v1/analyses/runs/{id}", analysisRunHandler.GetRun)
+
+	// POST /api/v1/analyses/runs/{id}/rerun - Повтор анализа с параметрами запуска
+	router.HandleFunc("POST /api/v1/analyses/runs/{id}/rerun", analysisRunHandler.Rerun)
+
+	// GET /api/v1/analyses/runs/{id}/quarantine - Записи, исключенные проверкой качества входных данных
+	router.HandleFunc("GET /api/v1/analyses/runs/{id}/quarantine", analysisRunHandler.GetQuarantined)
+
+	// --- Маржа и себестоимость ---
+	// GET /api/v1/margins/erosion?from=&to= - Маржа, потерянная на скидках и росте себестоимости, по категориям
+	router.HandleFunc("GET /api/v1/margins/erosion", marginHandler.GetErosionReport)
+
+	// GET /api/v1/products/{id}/costs - История себестоимости и цен товара
+	router.HandleFunc("GET /api/v1/products/{id}/costs", marginHandler.GetCostHistory)
+
+	// POST /api/v1/products/{id}/costs - Запись изменений себестоимости и цен товара с датами начала действия
+	router.HandleFunc("POST /api/v1/products/{id}/costs", marginHandler.RecordCosts)
+
+	// --- KPI корзины ---
+	// GET /api/v1/kpis/basket/{period}?by=total|category|daypart|segment&from=&to= - Средний чек, размер корзины
+	// и доля чеков со скидкой по периодам с изменением к предыдущему периоду
+	router.HandleFunc("GET /api/v1/kpis/basket/{period}", basketKPIHandler.GetKPIs)
+
+	// --- A/B тесты ---
+	// POST /api/v1/ab-tests/evaluate - Оценка сохраненного теста в его режиме анализа (frequentist, bayesian)
+	router.HandleFunc("POST /api/v1/ab-tests/evaluate", abTestHandler.EvaluateTest)
+
+	// POST /api/v1/ab-tests/sample-size - Размер каждой группы для обнаружения относительного эффекта
+	router.HandleFunc("POST /api/v1/ab-tests/sample-size", abTestHandler.RequiredSampleSize)
+
+	// --- Эксперименты ---
+	// POST /api/v1/experiments - Регистрация эксперимента в реестре
+	router.HandleFunc("POST /api/v1/experiments", experimentHandler.RegisterExperiment)
+
+	// POST /api/v1/experiments/assignments - Назначение клиента во все активные эксперименты, по одному на слой
+	router.HandleFunc("POST /api/v1/experiments/assignments", experimentHandler.AssignCustomerToAll)
+
+	// POST /api/v1/experiments/{id}/assign - Назначение клиента в вариант эксперимента
+	router.HandleFunc("POST /api/v1/experiments/{id}/assign", experimentHandler.AssignCustomer)
+
+	// POST /api/v1/experiments/{id}/exposures - Показ назначенного варианта клиенту
+	router.HandleFunc("POST /api/v1/experiments/{id}/exposures", experimentHandler.LogExposure)
+
+	// POST /api/v1/experiments/{id}/results - Расчет результатов A/B тестов эксперимента по продажам
+	router.HandleFunc("POST /api/v1/experiments/{id}/results", experimentHandler.ComputeResults)
+
+	// --- Бандиты скидок ---
+	// POST /api/v1/bandits - Создание бандита с руками для всех уровней скидки
+	router.HandleFunc("POST /api/v1/bandits", banditHandler.CreateBandit)
+
+	// POST /api/v1/bandits/select?product_id=&category= - Выбор скидки активным бандитом товара или категории
+	router.HandleFunc("POST /api/v1/bandits/select", banditHandler.SelectArmFor)
+
+	// POST /api/v1/bandits/{id}/select - Выбор скидки бандитом
+	router.HandleFunc("POST /api/v1/bandits/{id}/select", banditHandler.SelectArm)
+
+	// POST /api/v1/bandits/{id}/outcomes - Исход показа руки (reward 0/1 для bernoulli)
+	router.HandleFunc("POST /api/v1/bandits/{id}/outcomes", banditHandler.RecordOutcome)
+
+	// GET /api/v1/bandits/{id}/report - Статистика рук и накопленное сожаление
+	router.HandleFunc("GET /api/v1/bandits/{id}/report", banditHandler.GetReport)
+
+	// --- Uplift-модели ---
+	// POST /api/v1/uplift/models - Обучение uplift-модели на показах экспериментов
+	router.HandleFunc("POST /api/v1/uplift/models", upliftHandler.TrainModel)
+
+	// POST /api/v1/uplift/models/{id}/scores?as_of= - Прогнозный uplift клиентов по убыванию
+	router.HandleFunc("POST /api/v1/uplift/models/{id}/scores", upliftHandler.ScoreCustomers)
+
+	// POST /api/v1/uplift/models/{id}/targets?as_of= - Целевой список клиентов для скидки
+	router.HandleFunc("POST /api/v1/uplift/models/{id}/targets", upliftHandler.BuildTargetList)
+
+	// --- Каннибализация и эффект ореола ---
+	// GET /api/v1/products/{id}/cross-effects?from=&to= - Влияние скидки на товар на заменители и дополняющие товары
+	router.HandleFunc("GET /api/v1/products/{id}/cross-effects", cannibalizationHandler.GetCrossEffects)
+
+	// --- Промо-календарь ---
+	// POST /api/v1/promotions - Добавление акции в промо-календарь
+	router.HandleFunc("POST /api/v1/promotions", promotionHandler.CreatePromotion)
+
+	// POST /api/v1/promotions/{id}/impact - Эффект акции методом разности разностей с контрольными товарами
+	router.HandleFunc("POST /api/v1/promotions/{id}/impact", promotionHandler.MeasureImpact)
+
+	// --- Части дня ---
+	// GET /api/v1/dayparts/report?from=&to= - Выручка, чек, лидеры продаж и правила по часам и частям дня
+	router.HandleFunc("GET /api/v1/dayparts/report", daypartHandler.GetReport)
+
+	// GET /api/v1/products/{id}/daypart-discounts?from=&to= - Оптимальная скидка товара для каждой части дня
+	router.HandleFunc("GET /api/v1/products/{id}/daypart-discounts", daypartHandler.RecommendDiscounts)
+
+	// --- Выгрузки ---
+	// GET /api/v1/exports/{dataset}?format=csv|xlsx|parquet&from=&to=&period=&level=&limit= - Файл с набором данных
+	// (abc, rules, recommendations, retention, forecasts)
+	router.HandleFunc("GET /api/v1/exports/{dataset}", exportHandler.Export)
+
+	// --- События аналитики ---
+	// POST /api/v1/events/snapshots/{stream} - Снимок текущего состояния потока событий
+	// (discount_recommendations, abc_segmentation, association_rules)
+	router.HandleFunc("POST /api/v1/events/snapshots/{stream}", eventHandler.PublishSnapshot)
+
+	return router
+}
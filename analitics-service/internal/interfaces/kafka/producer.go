@@ -0,0 +1,73 @@
+// internal/interfaces/kafka/producer.go
+
+//go:build kafka
+// +build kafka
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Producer представляет Kafka продюсера событий аналитики
+type Producer struct {
+	writer *kafka.Writer
+}
+
+// NewProducer создает новый экземпляр Producer
+// Сообщения распределяются по партициям по ключу, поэтому события одного потока сохраняют порядок
+func NewProducer(brokers []string, topic string) MessageProducer {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// Синхронная отправка: событие отмечается опубликованным только после подтверждения брокером
+		Async:        false,
+		BatchSize:    1,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		// Снимки и пересчитанные наборы данных могут превышать размер сообщения по умолчанию
+		BatchBytes: 16 << 20,
+		Transport: &kafka.Transport{
+			Dial: (&kafka.Dialer{
+				Timeout:   10 * time.Second,
+				DualStack: true,
+			}).DialFunc,
+		},
+	}
+
+	return &Producer{
+		writer: writer,
+	}
+}
+
+// SendMessage отправляет сообщение в Kafka
+// Значения json.RawMessage передаются без повторной сериализации
+func (p *Producer) SendMessage(ctx context.Context, key string, value interface{}) error {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message value: %w", err)
+	}
+
+	message := kafka.Message{
+		Key:   []byte(key),
+		Value: jsonValue,
+		Time:  time.Now(),
+	}
+
+	if err := p.writer.WriteMessages(ctx, message); err != nil {
+		return fmt.Errorf("failed to write message to Kafka: %w", err)
+	}
+	return nil
+}
+
+// Close закрывает соединение с Kafka
+func (p *Producer) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,41 @@
+// internal/interfaces/notifier/log_notifier.go
+package notifier
+
+import (
+	"context"
+
+	"analitics-service/internal/domain/entities"
+	"analitics-service/pkg/logger"
+)
+
+// LogNotifier записывает уведомления об аномалиях в лог
+type LogNotifier struct {
+	logger logger.Logger
+}
+
+// NewLogNotifier создает новый экземпляр LogNotifier
+func NewLogNotifier(logger logger.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// Notify записывает каждую аномалию в лог с уровнем, зависящим от серьезности
+func (n *LogNotifier) Notify(ctx context.Context, anomalies []entities.SalesAnomaly) error {
+	for _, anomaly := range anomalies {
+		args := []interface{}{
+			"id", anomaly.ID,
+			"productID", anomaly.ProductID,
+			"metric", anomaly.Metric,
+			"period", anomaly.PeriodStart,
+			"observed", anomaly.Observed,
+			"expected", anomaly.Expected,
+			"score", anomaly.Score,
+			"severity", anomaly.Severity,
+		}
+		if anomaly.Severity == entities.SeverityHigh {
+			n.logger.Error(ctx, "Обнаружена аномалия продаж", args...)
+		} else {
+			n.logger.Warn(ctx, "Обнаружена аномалия продаж", args...)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// internal/interfaces/notifier/webhook_notifier.go
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"analitics-service/internal/domain/entities"
+)
+
+// WebhookNotifier отправляет уведомления об аномалиях POST-запросом на внешний адрес
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// webhookPayload представляет тело запроса вебхука
+type webhookPayload struct {
+	Source    string                  `json:"source"`
+	SentAt    time.Time               `json:"sent_at"`
+	Anomalies []entities.SalesAnomaly `json:"anomalies"`
+}
+
+// NewWebhookNotifier создает новый экземпляр WebhookNotifier
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Notify отправляет аномалии одним запросом, ответ со статусом вне 2xx считается ошибкой
+func (n *WebhookNotifier) Notify(ctx context.Context, anomalies []entities.SalesAnomaly) error {
+	if len(anomalies) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Source:    "analitics-service",
+		SentAt:    time.Now(),
+		Anomalies: anomalies,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package buildinfo
+
+import "runtime/debug"
+
+// Version задается при сборке: go build -ldflags "-X analitics-service/pkg/buildinfo.Version=v1.2.3"
+var Version = ""
+
+// CodeVersion возвращает версию кода сервиса
+// Если версия не задана при сборке, используется ревизия git, встроенная компилятором, с пометкой -dirty для незакоммиченных изменений
+func CodeVersion() string {
+	if Version != "" {
+		return Version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+
+	if revision == "" {
+		return "unknown"
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	return revision
+}
@@ -0,0 +1,204 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears ограничение поиска следующего срабатывания для невыполнимых расписаний, например 30 февраля
+const maxSearchYears = 5
+
+// Schedule представляет разобранное cron-расписание из пяти полей: минута, час, день месяца, месяц, день недели
+type Schedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// Если ограничены и день месяца, и день недели, достаточно совпадения любого из них, как в стандартном cron
+	dayOfMonthAny bool
+	dayOfWeekAny  bool
+}
+
+// field описывает допустимый диапазон поля расписания
+type field struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12}
+	dayOfWeekField  = field{name: "day of week", min: 0, max: 7} // 0 и 7 — воскресенье
+)
+
+// descriptors содержит поддерживаемые сокращения расписаний
+var descriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// Parse разбирает cron-выражение из пяти полей или сокращение вида @daily
+// Поля поддерживают *, списки через запятую, диапазоны a-b и шаг /n
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := descriptors[spec]; ok {
+		spec = expanded
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in cron spec %q, got %d", spec, len(parts))
+	}
+
+	schedule := &Schedule{
+		dayOfMonthAny: parts[2] == "*" || parts[2] == "?",
+		dayOfWeekAny:  parts[4] == "*" || parts[4] == "?",
+	}
+
+	var err error
+	if schedule.minute, err = parseField(parts[0], minuteField); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseField(parts[1], hourField); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfMonth, err = parseField(parts[2], dayOfMonthField); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseField(parts[3], monthField); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek, err = parseField(parts[4], dayOfWeekField); err != nil {
+		return nil, err
+	}
+
+	// Воскресенье может быть задано как 7
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+
+	return schedule, nil
+}
+
+// Next возвращает первый момент срабатывания строго после t в часовом поясе t
+// Для невыполнимого расписания возвращается нулевое время
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchesDay проверяет совпадение дня месяца и дня недели
+func (s *Schedule) matchesDay(t time.Time) bool {
+	domMatch := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dowMatch := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if s.dayOfMonthAny || s.dayOfWeekAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// parseField разбирает поле расписания в битовую маску допустимых значений
+func parseField(expr string, f field) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(expr, ",") {
+		bits, err := parseRange(part, f)
+		if err != nil {
+			return 0, err
+		}
+		mask |= bits
+	}
+	return mask, nil
+}
+
+// parseRange разбирает элемент списка: *, значение, диапазон a-b, каждый с необязательным шагом /n
+func parseRange(expr string, f field) (uint64, error) {
+	rangeExpr, stepExpr, hasStep := strings.Cut(expr, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepExpr)
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step %q in %s field", stepExpr, f.name)
+		}
+	}
+
+	start, end := f.min, f.max
+	switch {
+	case rangeExpr == "*" || rangeExpr == "?":
+	case strings.Contains(rangeExpr, "-"):
+		lowExpr, highExpr, _ := strings.Cut(rangeExpr, "-")
+		low, err := parseValue(lowExpr, f)
+		if err != nil {
+			return 0, err
+		}
+		high, err := parseValue(highExpr, f)
+		if err != nil {
+			return 0, err
+		}
+		if low > high {
+			return 0, fmt.Errorf("invalid range %q in %s field", rangeExpr, f.name)
+		}
+		start, end = low, high
+	default:
+		value, err := parseValue(rangeExpr, f)
+		if err != nil {
+			return 0, err
+		}
+		start = value
+		if !hasStep {
+			end = value
+		}
+	}
+
+	var mask uint64
+	for value := start; value <= end; value += step {
+		mask |= 1 << uint(value)
+	}
+	return mask, nil
+}
+
+// parseValue разбирает числовое значение поля и проверяет диапазон
+func parseValue(expr string, f field) (int, error) {
+	value, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", expr, f.name)
+	}
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("%s value %d out of range [%d, %d]", f.name, value, f.min, f.max)
+	}
+	return value, nil
+}
@@ -0,0 +1,94 @@
+// pkg/cron/schedule_test.go
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"analitics-service/pkg/cron"
+)
+
+func TestParseInvalidSpecs(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "empty", spec: ""},
+		{name: "too few fields", spec: "0 3 * *"},
+		{name: "too many fields", spec: "0 0 3 * * *"},
+		{name: "minute out of range", spec: "60 * * * *"},
+		{name: "hour out of range", spec: "0 24 * * *"},
+		{name: "zero day of month", spec: "0 0 0 * *"},
+		{name: "month out of range", spec: "0 0 1 13 *"},
+		{name: "day of week out of range", spec: "0 0 * * 8"},
+		{name: "reversed range", spec: "0 10-5 * * *"},
+		{name: "zero step", spec: "*/0 * * * *"},
+		{name: "non-numeric value", spec: "0 noon * * *"},
+		{name: "unknown descriptor", spec: "@sometimes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := cron.Parse(tt.spec); err == nil {
+				t.Errorf("Parse(%q) error = nil, want error", tt.spec)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// 2024-01-15 — понедельник
+	from := time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{name: "every minute", spec: "* * * * *", from: from, want: time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC)},
+		{name: "strictly after exact match", spec: "30 10 * * *", from: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), want: time.Date(2024, 1, 16, 10, 30, 0, 0, time.UTC)},
+		{name: "daily later today", spec: "0 18 * * *", from: from, want: time.Date(2024, 1, 15, 18, 0, 0, 0, time.UTC)},
+		{name: "daily tomorrow", spec: "15 3 * * *", from: from, want: time.Date(2024, 1, 16, 3, 15, 0, 0, time.UTC)},
+		{name: "step", spec: "*/20 * * * *", from: from, want: time.Date(2024, 1, 15, 10, 40, 0, 0, time.UTC)},
+		{name: "list", spec: "0 9,12,21 * * *", from: from, want: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{name: "range with step", spec: "0 8-20/4 * * *", from: from, want: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{name: "value with step", spec: "0 22/1 * * *", from: from, want: time.Date(2024, 1, 15, 22, 0, 0, 0, time.UTC)},
+		{name: "weekdays only", spec: "0 6 * * 1-5", from: time.Date(2024, 1, 19, 7, 0, 0, 0, time.UTC), want: time.Date(2024, 1, 22, 6, 0, 0, 0, time.UTC)},
+		{name: "sunday as seven", spec: "0 0 * * 7", from: from, want: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{name: "weekly descriptor", spec: "@weekly", from: from, want: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{name: "monthly descriptor", spec: "@monthly", from: from, want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "yearly across year", spec: "@yearly", from: from, want: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "leap day", spec: "0 0 29 2 *", from: from, want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "leap day after leap year", spec: "0 0 29 2 *", from: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Заданы и день месяца, и день недели: срабатывает 20-го числа или в ближайшую пятницу
+		{name: "day of month or day of week", spec: "0 0 20 * 5", from: from, want: time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{name: "impossible date", spec: "0 0 30 2 *", from: from},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := cron.Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.spec, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from.Format(time.RFC3339), got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestScheduleNextKeepsLocation(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	schedule, err := cron.Parse("0 2 * * *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got := schedule.Next(time.Date(2024, 1, 15, 23, 0, 0, 0, moscow))
+	want := time.Date(2024, 1, 16, 2, 0, 0, 0, moscow)
+	if !got.Equal(want) || got.Location() != moscow {
+		t.Errorf("Next() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// csvWriter записывает выгрузку в CSV с заголовком из имен колонок
+type csvWriter struct {
+	writer  *csv.Writer
+	columns []Column
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &csvWriter{
+		writer:  writer,
+		columns: columns,
+		record:  make([]string, len(columns)),
+	}, nil
+}
+
+func (c *csvWriter) WriteRow(values []interface{}) error {
+	if err := checkRow(c.columns, values); err != nil {
+		return err
+	}
+
+	for i, column := range c.columns {
+		value, present, err := normalizeValue(column, values[i])
+		if err != nil {
+			return err
+		}
+		c.record[i] = ""
+		if present {
+			c.record[i] = formatText(value)
+		}
+	}
+	return c.writer.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
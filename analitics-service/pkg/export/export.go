@@ -0,0 +1,157 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Format определяет формат файла выгрузки
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatXLSX    Format = "xlsx"
+	FormatParquet Format = "parquet"
+)
+
+// ParseFormat проверяет и возвращает формат выгрузки
+func ParseFormat(raw string) (Format, error) {
+	switch format := Format(raw); format {
+	case FormatCSV, FormatXLSX, FormatParquet:
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q, expected csv, xlsx or parquet", raw)
+}
+
+// ContentType возвращает MIME-тип файла выгрузки
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension возвращает расширение файла выгрузки
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// ColumnType определяет тип значений колонки
+type ColumnType int
+
+const (
+	String ColumnType = iota
+	Int
+	Float
+	Bool
+	Time
+)
+
+// Column описывает колонку выгрузки
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Writer построчно записывает набор данных в файл выгрузки
+// Значения строки передаются в порядке колонок: string, int, int64, float64, bool или time.Time;
+// nil и нулевое время записываются как пустое значение
+type Writer interface {
+	// WriteRow записывает строку данных
+	WriteRow(values []interface{}) error
+
+	// Close дописывает служебные структуры формата; базовый io.Writer не закрывается
+	Close() error
+}
+
+// NewWriter создает писателя выгрузки в указанном формате
+func NewWriter(format Format, w io.Writer, columns []Column) (Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("export requires at least one column")
+	}
+
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	case FormatParquet:
+		return newParquetWriter(w, columns)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// normalizeValue приводит значение к типу колонки; второй результат false означает пустое значение
+func normalizeValue(column Column, value interface{}) (interface{}, bool, error) {
+	if value == nil {
+		return nil, false, nil
+	}
+
+	switch column.Type {
+	case String:
+		if v, ok := value.(string); ok {
+			return v, true, nil
+		}
+		if v, ok := value.(fmt.Stringer); ok {
+			return v.String(), true, nil
+		}
+	case Int:
+		switch v := value.(type) {
+		case int:
+			return int64(v), true, nil
+		case int64:
+			return v, true, nil
+		}
+	case Float:
+		if v, ok := value.(float64); ok {
+			// NaN и бесконечности не представимы в таблицах, записываем их как пустые значения
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, false, nil
+			}
+			return v, true, nil
+		}
+	case Bool:
+		if v, ok := value.(bool); ok {
+			return v, true, nil
+		}
+	case Time:
+		if v, ok := value.(time.Time); ok {
+			if v.IsZero() {
+				return nil, false, nil
+			}
+			return v.UTC(), true, nil
+		}
+	}
+	return nil, false, fmt.Errorf("column %s: unexpected value of type %T", column.Name, value)
+}
+
+// formatText возвращает текстовое представление нормализованного значения
+func formatText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return ""
+}
+
+// checkRow проверяет количество значений в строке
+func checkRow(columns []Column, values []interface{}) error {
+	if len(values) != len(columns) {
+		return fmt.Errorf("row has %d values, expected %d", len(values), len(columns))
+	}
+	return nil
+}
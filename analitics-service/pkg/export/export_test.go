@@ -0,0 +1,367 @@
+// pkg/export/export_test.go
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"analitics-service/pkg/export"
+)
+
+// testColumns охватывает все типы колонок выгрузки
+var testColumns = []export.Column{
+	{Name: "product_id", Type: export.String},
+	{Name: "quantity", Type: export.Int},
+	{Name: "revenue", Type: export.Float},
+	{Name: "discount", Type: export.Bool},
+	{Name: "sold_at", Type: export.Time},
+}
+
+// testSoldAt — 2024-03-01 12:00 UTC, серийный номер Excel 45352.5
+var testSoldAt = time.Date(2024, 3, 1, 15, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+// writeTestExport записывает строки в выгрузку указанного формата
+func writeTestExport(t *testing.T, format export.Format, columns []export.Column, rows [][]interface{}) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer, err := export.NewWriter(format, &buf, columns)
+	if err != nil {
+		t.Fatalf("NewWriter(%s) error = %v", format, err)
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow(%v) error = %v", row, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		raw       string
+		want      export.Format
+		wantError bool
+	}{
+		{raw: "csv", want: export.FormatCSV},
+		{raw: "xlsx", want: export.FormatXLSX},
+		{raw: "parquet", want: export.FormatParquet},
+		{raw: "CSV", wantError: true},
+		{raw: "json", wantError: true},
+		{raw: "", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := export.ParseFormat(tt.raw)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseFormat(%q) error = %v, want error %v", tt.raw, err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	data := writeTestExport(t, export.FormatCSV, testColumns, [][]interface{}{
+		{"P1", 3, 299.5, true, testSoldAt},
+		{"P2, large", int64(-1), math.NaN(), false, time.Time{}},
+		{nil, nil, math.Inf(1), nil, nil},
+	})
+
+	want := "product_id,quantity,revenue,discount,sold_at\n" +
+		"P1,3,299.5,true,2024-03-01T12:00:00Z\n" +
+		"\"P2, large\",-1,,false,\n" +
+		",,,,\n"
+	if string(data) != want {
+		t.Errorf("csv = %q, want %q", data, want)
+	}
+}
+
+func TestWriterRejectsInvalidRows(t *testing.T) {
+	tests := []struct {
+		name string
+		row  []interface{}
+	}{
+		{name: "too few values", row: []interface{}{"P1", 3, 1.5, true}},
+		{name: "string for int", row: []interface{}{"P1", "3", 1.5, true, testSoldAt}},
+		{name: "int for float", row: []interface{}{"P1", 3, 2, true, testSoldAt}},
+		{name: "string for time", row: []interface{}{"P1", 3, 1.5, true, "2024-03-01"}},
+	}
+
+	for _, format := range []export.Format{export.FormatCSV, export.FormatXLSX, export.FormatParquet} {
+		for _, tt := range tests {
+			t.Run(string(format)+"/"+tt.name, func(t *testing.T) {
+				writer, err := export.NewWriter(format, io.Discard, testColumns)
+				if err != nil {
+					t.Fatalf("NewWriter() error = %v", err)
+				}
+				if err := writer.WriteRow(tt.row); err == nil {
+					t.Errorf("WriteRow(%v) error = nil, want error", tt.row)
+				}
+			})
+		}
+	}
+
+	if _, err := export.NewWriter(export.FormatCSV, io.Discard, nil); err == nil {
+		t.Errorf("NewWriter() without columns error = nil, want error")
+	}
+}
+
+// xlsxSheet соответствует листу книги в части, нужной для проверки ячеек
+type xlsxSheet struct {
+	Rows []struct {
+		Ref   string `xml:"r,attr"`
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Style  string `xml:"s,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSXWriter(t *testing.T) {
+	data := writeTestExport(t, export.FormatXLSX, testColumns, [][]interface{}{
+		{"<P1 & co>", 3, 299.5, true, testSoldAt},
+		{"P2", nil, math.NaN(), false, time.Time{}},
+	})
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	parts := make(map[string]*zip.File)
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		if parts[name] == nil {
+			t.Errorf("workbook part %s is missing", name)
+		}
+	}
+
+	file, ok := parts["xl/worksheets/sheet1.xml"]
+	if !ok {
+		t.Fatalf("worksheet is missing")
+	}
+	reader, err := file.Open()
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer reader.Close()
+
+	var sheet xlsxSheet
+	if err := xml.NewDecoder(reader).Decode(&sheet); err != nil {
+		t.Fatalf("decode worksheet: %v", err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("sheet rows = %d, want 3", len(sheet.Rows))
+	}
+
+	type cell struct{ ref, typ, style, value string }
+	tests := []struct {
+		name string
+		row  int
+		want []cell
+	}{
+		{
+			name: "header",
+			row:  0,
+			want: []cell{
+				{ref: "A1", typ: "inlineStr", value: "product_id"},
+				{ref: "B1", typ: "inlineStr", value: "quantity"},
+				{ref: "C1", typ: "inlineStr", value: "revenue"},
+				{ref: "D1", typ: "inlineStr", value: "discount"},
+				{ref: "E1", typ: "inlineStr", value: "sold_at"},
+			},
+		},
+		{
+			name: "values",
+			row:  1,
+			want: []cell{
+				{ref: "A2", typ: "inlineStr", value: "<P1 & co>"},
+				{ref: "B2", value: "3"},
+				{ref: "C2", value: "299.5"},
+				{ref: "D2", typ: "b", value: "1"},
+				{ref: "E2", style: "1", value: "45352.5"},
+			},
+		},
+		{
+			// Пустые значения не записываются в лист
+			name: "empty values skipped",
+			row:  2,
+			want: []cell{
+				{ref: "A3", typ: "inlineStr", value: "P2"},
+				{ref: "D3", typ: "b", value: "0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := sheet.Rows[tt.row]
+			if len(row.Cells) != len(tt.want) {
+				t.Fatalf("cells = %d, want %d", len(row.Cells), len(tt.want))
+			}
+			for i, c := range row.Cells {
+				got := cell{ref: c.Ref, typ: c.Type, style: c.Style, value: c.Value + c.Inline}
+				if got != tt.want[i] {
+					t.Errorf("cell %d = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestXLSXColumnReferences(t *testing.T) {
+	columns := make([]export.Column, 28)
+	row := make([]interface{}, len(columns))
+	for i := range columns {
+		columns[i] = export.Column{Name: "c", Type: export.Int}
+		row[i] = i
+	}
+	data := writeTestExport(t, export.FormatXLSX, columns, [][]interface{}{row})
+
+	for _, ref := range []string{`r="Z2"`, `r="AA2"`, `r="AB2"`} {
+		if !bytes.Contains(readZipPart(t, data, "xl/worksheets/sheet1.xml"), []byte(ref)) {
+			t.Errorf("worksheet has no cell %s", ref)
+		}
+	}
+}
+
+// readZipPart возвращает содержимое части архива
+func readZipPart(t *testing.T, data []byte, name string) []byte {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	reader, err := archive.Open(name)
+	if err != nil {
+		t.Fatalf("Open(%s) error = %v", name, err)
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("ReadAll(%s) error = %v", name, err)
+	}
+	return content
+}
+
+func TestParquetWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		column export.Column
+		values []interface{}
+		// Тело страницы данных: длина уровней определения, bit-packed уровни и непустые значения в PLAIN
+		wantPage []byte
+	}{
+		{
+			name:   "int64 with null",
+			column: export.Column{Name: "quantity", Type: export.Int},
+			values: []interface{}{1, nil, int64(3)},
+			wantPage: concatBytes(
+				[]byte{2, 0, 0, 0, 0x03, 0x05},
+				littleEndian64(1), littleEndian64(3),
+			),
+		},
+		{
+			name:   "strings",
+			column: export.Column{Name: "product_id", Type: export.String},
+			values: []interface{}{"ab", "c"},
+			wantPage: concatBytes(
+				[]byte{2, 0, 0, 0, 0x03, 0x03},
+				[]byte{2, 0, 0, 0, 'a', 'b', 1, 0, 0, 0, 'c'},
+			),
+		},
+		{
+			name:     "booleans packed",
+			column:   export.Column{Name: "discount", Type: export.Bool},
+			values:   []interface{}{true, false, nil, true},
+			wantPage: []byte{2, 0, 0, 0, 0x03, 0x0b, 0x05},
+		},
+		{
+			name:   "timestamps in milliseconds",
+			column: export.Column{Name: "sold_at", Type: export.Time},
+			values: []interface{}{testSoldAt, time.Time{}},
+			wantPage: concatBytes(
+				[]byte{2, 0, 0, 0, 0x03, 0x01},
+				littleEndian64(uint64(testSoldAt.UnixMilli())),
+			),
+		},
+		{
+			name:   "doubles without nan",
+			column: export.Column{Name: "revenue", Type: export.Float},
+			values: []interface{}{1.5, math.NaN()},
+			wantPage: concatBytes(
+				[]byte{2, 0, 0, 0, 0x03, 0x01},
+				littleEndian64(math.Float64bits(1.5)),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := make([][]interface{}, len(tt.values))
+			for i, value := range tt.values {
+				rows[i] = []interface{}{value}
+			}
+			data := writeTestExport(t, export.FormatParquet, []export.Column{tt.column}, rows)
+
+			if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+				t.Fatalf("file is not framed by PAR1 magic")
+			}
+			footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8 : len(data)-4]))
+			footerStart := len(data) - 8 - footerLength
+			if footerStart <= 4+len(tt.wantPage) {
+				t.Fatalf("footer length %d does not fit into file of %d bytes", footerLength, len(data))
+			}
+
+			footer := data[footerStart : len(data)-8]
+			if !bytes.Contains(footer, []byte(tt.column.Name)) || !bytes.Contains(footer, []byte("analitics-service")) {
+				t.Errorf("footer does not describe column %s", tt.column.Name)
+			}
+
+			// Единственная страница данных заканчивается непосредственно перед метаданными файла
+			if page := data[footerStart-len(tt.wantPage) : footerStart]; !bytes.Equal(page, tt.wantPage) {
+				t.Errorf("data page = % x, want % x", page, tt.wantPage)
+			}
+		})
+	}
+}
+
+func TestParquetWriterEmpty(t *testing.T) {
+	data := writeTestExport(t, export.FormatParquet, testColumns, nil)
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatalf("file is not framed by PAR1 magic")
+	}
+	footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8 : len(data)-4]))
+	if footerLength != len(data)-12 {
+		t.Errorf("footer length = %d, want %d for a file without row groups", footerLength, len(data)-12)
+	}
+}
+
+func littleEndian64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b
+}
+
+func concatBytes(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
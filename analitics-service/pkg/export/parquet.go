@@ -0,0 +1,316 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// parquetRowGroupSize количество строк в группе строк; память писателя ограничена одной группой
+const parquetRowGroupSize = 10000
+
+const parquetMagic = "PAR1"
+
+// Значения перечислений из спецификации формата Parquet (parquet.thrift)
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetOptional = 1
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedTimestampMillis = 9
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetUncompressed = 0
+	parquetDataPage     = 0
+)
+
+// parquetColumn накапливает значения колонки текущей группы строк
+type parquetColumn struct {
+	Column
+	present []bool       // Уровни определения: false — пустое значение
+	values  bytes.Buffer // Непустые значения в кодировке PLAIN
+	bools   []bool       // Непустые значения булевой колонки, упаковываются при записи страницы
+}
+
+// parquetColumnChunk описывает записанный фрагмент колонки для метаданных файла
+type parquetColumnChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+// parquetRowGroup описывает записанную группу строк
+type parquetRowGroup struct {
+	chunks  []parquetColumnChunk
+	size    int64
+	numRows int64
+}
+
+// parquetWriter записывает выгрузку в формате Parquet: все колонки необязательные,
+// без сжатия, одна страница данных на колонку в каждой группе строк
+type parquetWriter struct {
+	w         io.Writer
+	offset    int64
+	schema    []Column
+	columns   []*parquetColumn
+	rows      int
+	totalRows int64
+	groups    []parquetRowGroup
+}
+
+func newParquetWriter(w io.Writer, columns []Column) (*parquetWriter, error) {
+	p := &parquetWriter{w: w, schema: columns}
+	for _, column := range columns {
+		p.columns = append(p.columns, &parquetColumn{Column: column})
+	}
+
+	if err := p.write([]byte(parquetMagic)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *parquetWriter) WriteRow(values []interface{}) error {
+	if err := checkRow(p.schema, values); err != nil {
+		return err
+	}
+
+	// Сначала проверяем все значения, чтобы не записать строку частично
+	normalized := make([]interface{}, len(values))
+	present := make([]bool, len(values))
+	for i, column := range p.columns {
+		value, ok, err := normalizeValue(column.Column, values[i])
+		if err != nil {
+			return err
+		}
+		normalized[i], present[i] = value, ok
+	}
+
+	for i, column := range p.columns {
+		column.present = append(column.present, present[i])
+		if present[i] {
+			column.appendValue(normalized[i])
+		}
+	}
+
+	p.rows++
+	if p.rows >= parquetRowGroupSize {
+		return p.flushRowGroup()
+	}
+	return nil
+}
+
+func (p *parquetWriter) Close() error {
+	if p.rows > 0 {
+		if err := p.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	footer := p.encodeFileMetadata()
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+
+	for _, part := range [][]byte{footer, length[:], []byte(parquetMagic)} {
+		if err := p.write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendValue добавляет непустое значение в кодировке PLAIN
+func (c *parquetColumn) appendValue(value interface{}) {
+	var scratch [8]byte
+	switch v := value.(type) {
+	case string:
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(len(v)))
+		c.values.Write(scratch[:4])
+		c.values.WriteString(v)
+	case int64:
+		binary.LittleEndian.PutUint64(scratch[:], uint64(v))
+		c.values.Write(scratch[:])
+	case float64:
+		binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(v))
+		c.values.Write(scratch[:])
+	case bool:
+		c.bools = append(c.bools, v)
+	case time.Time:
+		binary.LittleEndian.PutUint64(scratch[:], uint64(v.UnixMilli()))
+		c.values.Write(scratch[:])
+	}
+}
+
+// flushRowGroup записывает накопленные строки группой строк и освобождает буферы колонок
+func (p *parquetWriter) flushRowGroup() error {
+	group := parquetRowGroup{numRows: int64(p.rows)}
+
+	for _, column := range p.columns {
+		page := column.encodePage()
+		header := encodePageHeader(len(column.present), len(page))
+
+		chunk := parquetColumnChunk{
+			offset:    p.offset,
+			size:      int64(len(header) + len(page)),
+			numValues: int64(len(column.present)),
+		}
+		if err := p.write(header); err != nil {
+			return err
+		}
+		if err := p.write(page); err != nil {
+			return err
+		}
+
+		group.chunks = append(group.chunks, chunk)
+		group.size += chunk.size
+
+		column.present = column.present[:0]
+		column.values.Reset()
+		column.bools = column.bools[:0]
+	}
+
+	p.groups = append(p.groups, group)
+	p.totalRows += int64(p.rows)
+	p.rows = 0
+	return nil
+}
+
+// encodePage кодирует тело страницы данных: уровни определения, затем непустые значения
+func (c *parquetColumn) encodePage() []byte {
+	levels := encodeBitPacked(c.present)
+
+	var page bytes.Buffer
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(levels)))
+	page.Write(length[:])
+	page.Write(levels)
+
+	if c.Type == Bool {
+		page.Write(packBits(c.bools))
+	} else {
+		page.Write(c.values.Bytes())
+	}
+	return page.Bytes()
+}
+
+// encodeBitPacked кодирует уровни определения разрядности 1 одним bit-packed блоком гибридной RLE-кодировки
+func encodeBitPacked(values []bool) []byte {
+	groups := (len(values) + 7) / 8
+
+	var header [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(header[:], uint64(groups)<<1|1)
+	return append(header[:n], packBits(values)...)
+}
+
+// packBits упаковывает булевы значения по восемь в байт, начиная с младшего бита
+func packBits(values []bool) []byte {
+	packed := make([]byte, (len(values)+7)/8)
+	for i, value := range values {
+		if value {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return packed
+}
+
+// encodePageHeader кодирует заголовок страницы данных
+func encodePageHeader(numValues, pageSize int) []byte {
+	e := newThriftEncoder()
+	e.i32(1, parquetDataPage)
+	e.i32(2, int32(pageSize))
+	e.i32(3, int32(pageSize))
+	e.beginStruct(5)
+	e.i32(1, int32(numValues))
+	e.i32(2, parquetEncodingPlain)
+	e.i32(3, parquetEncodingRLE)
+	e.i32(4, parquetEncodingRLE)
+	e.endStruct()
+	e.endStruct()
+	return e.bytes()
+}
+
+// encodeFileMetadata кодирует метаданные файла: схему и расположение групп строк
+func (p *parquetWriter) encodeFileMetadata() []byte {
+	e := newThriftEncoder()
+	e.i32(1, 1)
+
+	e.list(2, thriftStruct, len(p.columns)+1)
+	e.beginElement()
+	e.binary(4, "schema")
+	e.i32(5, int32(len(p.columns)))
+	e.endStruct()
+	for _, column := range p.columns {
+		physical, converted, hasConverted := parquetTypes(column.Type)
+		e.beginElement()
+		e.i32(1, physical)
+		e.i32(3, parquetOptional)
+		e.binary(4, column.Name)
+		if hasConverted {
+			e.i32(6, converted)
+		}
+		e.endStruct()
+	}
+
+	e.i64(3, p.totalRows)
+
+	e.list(4, thriftStruct, len(p.groups))
+	for _, group := range p.groups {
+		e.beginElement()
+		e.list(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			physical, _, _ := parquetTypes(p.columns[i].Type)
+			e.beginElement()
+			e.i64(2, chunk.offset)
+			e.beginStruct(3)
+			e.i32(1, physical)
+			e.list(2, thriftI32, 2)
+			e.elementI32(parquetEncodingPlain)
+			e.elementI32(parquetEncodingRLE)
+			e.list(3, thriftBinary, 1)
+			e.elementBinary(p.columns[i].Name)
+			e.i32(4, parquetUncompressed)
+			e.i64(5, chunk.numValues)
+			e.i64(6, chunk.size)
+			e.i64(7, chunk.size)
+			e.i64(9, chunk.offset)
+			e.endStruct()
+			e.endStruct()
+		}
+		e.i64(2, group.size)
+		e.i64(3, group.numRows)
+		e.endStruct()
+	}
+
+	e.binary(6, "analitics-service")
+	e.endStruct()
+	return e.bytes()
+}
+
+// parquetTypes возвращает физический и логический (converted) тип Parquet для колонки
+func parquetTypes(columnType ColumnType) (int32, int32, bool) {
+	switch columnType {
+	case Int:
+		return parquetInt64, 0, false
+	case Float:
+		return parquetDouble, 0, false
+	case Bool:
+		return parquetBoolean, 0, false
+	case Time:
+		return parquetInt64, parquetConvertedTimestampMillis, true
+	}
+	return parquetByteArray, parquetConvertedUTF8, true
+}
+
+func (p *parquetWriter) write(data []byte) error {
+	n, err := p.w.Write(data)
+	p.offset += int64(n)
+	return err
+}
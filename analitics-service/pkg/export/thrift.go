@@ -0,0 +1,108 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Типы полей компактного протокола Thrift, которым кодируются метаданные Parquet
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftEncoder кодирует структуры в компактном протоколе Thrift
+// Поля каждой структуры должны записываться в порядке возрастания идентификаторов
+type thriftEncoder struct {
+	buf       bytes.Buffer
+	lastField []int16 // Последний записанный идентификатор поля для каждой вложенной структуры
+}
+
+func newThriftEncoder() *thriftEncoder {
+	return &thriftEncoder{lastField: []int16{0}}
+}
+
+func (e *thriftEncoder) bytes() []byte {
+	return e.buf.Bytes()
+}
+
+func (e *thriftEncoder) fieldHeader(id int16, fieldType byte) {
+	last := &e.lastField[len(e.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		e.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		e.buf.WriteByte(fieldType)
+		e.writeVarint(int64(id))
+	}
+	*last = id
+}
+
+func (e *thriftEncoder) writeUvarint(v uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	e.buf.Write(scratch[:n])
+}
+
+// writeVarint записывает целое со знаком в zigzag-кодировке
+func (e *thriftEncoder) writeVarint(v int64) {
+	e.writeUvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (e *thriftEncoder) writeBinary(v []byte) {
+	e.writeUvarint(uint64(len(v)))
+	e.buf.Write(v)
+}
+
+func (e *thriftEncoder) i32(id int16, v int32) {
+	e.fieldHeader(id, thriftI32)
+	e.writeVarint(int64(v))
+}
+
+func (e *thriftEncoder) i64(id int16, v int64) {
+	e.fieldHeader(id, thriftI64)
+	e.writeVarint(v)
+}
+
+func (e *thriftEncoder) binary(id int16, v string) {
+	e.fieldHeader(id, thriftBinary)
+	e.writeBinary([]byte(v))
+}
+
+// list записывает заголовок списка; элементы записываются следом методами element*
+func (e *thriftEncoder) list(id int16, elementType byte, size int) {
+	e.fieldHeader(id, thriftList)
+	if size < 15 {
+		e.buf.WriteByte(byte(size)<<4 | elementType)
+		return
+	}
+	e.buf.WriteByte(0xF0 | elementType)
+	e.writeUvarint(uint64(size))
+}
+
+func (e *thriftEncoder) elementI32(v int32) {
+	e.writeVarint(int64(v))
+}
+
+func (e *thriftEncoder) elementBinary(v string) {
+	e.writeBinary([]byte(v))
+}
+
+// beginStruct начинает вложенную структуру-поле
+func (e *thriftEncoder) beginStruct(id int16) {
+	e.fieldHeader(id, thriftStruct)
+	e.lastField = append(e.lastField, 0)
+}
+
+// beginElement начинает структуру-элемент списка
+func (e *thriftEncoder) beginElement() {
+	e.lastField = append(e.lastField, 0)
+}
+
+// endStruct завершает текущую структуру
+func (e *thriftEncoder) endStruct() {
+	e.buf.WriteByte(0)
+	e.lastField = e.lastField[:len(e.lastField)-1]
+}
@@ -0,0 +1,107 @@
+// pkg/export/thrift_internal_test.go
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestThriftEncoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		encode func(e *thriftEncoder)
+		want   []byte
+	}{
+		{
+			// Короткая форма заголовка: смещение идентификатора в старших битах, zigzag(1) = 2
+			name:   "i32 short header",
+			encode: func(e *thriftEncoder) { e.i32(1, 1) },
+			want:   []byte{0x15, 0x02},
+		},
+		{
+			name:   "negative i64",
+			encode: func(e *thriftEncoder) { e.i64(2, -1) },
+			want:   []byte{0x26, 0x01},
+		},
+		{
+			// Смещение больше 15: тип отдельным байтом, затем идентификатор в zigzag
+			name:   "long field header",
+			encode: func(e *thriftEncoder) { e.i32(1, 0); e.i32(20, 300) },
+			want:   []byte{0x15, 0x00, 0x05, 0x28, 0xd8, 0x04},
+		},
+		{
+			name:   "binary",
+			encode: func(e *thriftEncoder) { e.binary(4, "ab") },
+			want:   []byte{0x48, 0x02, 'a', 'b'},
+		},
+		{
+			name: "short list",
+			encode: func(e *thriftEncoder) {
+				e.list(2, thriftI32, 2)
+				e.elementI32(0)
+				e.elementI32(3)
+			},
+			want: []byte{0x29, 0x25, 0x00, 0x06},
+		},
+		{
+			name:   "long list header",
+			encode: func(e *thriftEncoder) { e.list(1, thriftBinary, 20) },
+			want:   []byte{0x19, 0xf8, 0x14},
+		},
+		{
+			// Идентификаторы полей во вложенной структуре отсчитываются заново
+			name: "nested struct",
+			encode: func(e *thriftEncoder) {
+				e.i32(3, 1)
+				e.beginStruct(5)
+				e.i32(1, 7)
+				e.endStruct()
+				e.i32(6, 1)
+			},
+			want: []byte{0x35, 0x02, 0x2c, 0x15, 0x0e, 0x00, 0x15, 0x02},
+		},
+		{
+			name: "struct list elements",
+			encode: func(e *thriftEncoder) {
+				e.list(1, thriftStruct, 2)
+				for _, v := range []int32{1, 2} {
+					e.beginElement()
+					e.i32(1, v)
+					e.endStruct()
+				}
+			},
+			want: []byte{0x19, 0x2c, 0x15, 0x02, 0x00, 0x15, 0x04, 0x00},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newThriftEncoder()
+			tt.encode(e)
+			if got := e.bytes(); !bytes.Equal(got, tt.want) {
+				t.Errorf("encoded = % x, want % x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeBitPacked(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []bool
+		want   []byte
+	}{
+		// Заголовок блока — число групп по восемь значений, сдвинутое на бит, с признаком bit-packed
+		{name: "single group", values: []bool{true, false, true}, want: []byte{0x03, 0x05}},
+		{name: "two groups", values: []bool{true, true, true, true, true, true, true, true, false, true}, want: []byte{0x05, 0xff, 0x02}},
+		{name: "empty", want: []byte{0x01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encodeBitPacked(tt.values); !bytes.Equal(got, tt.want) {
+				t.Errorf("encodeBitPacked() = % x, want % x", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// xlsxMaxRows максимальное количество строк листа Excel, включая заголовок
+const xlsxMaxRows = 1048576
+
+// xlsxDateStyle индекс стиля ячейки с форматом даты и времени в styles.xml
+const xlsxDateStyle = 1
+
+// xlsxEpoch начало отсчета дат Excel с учетом ошибки 1900 года
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// Служебные части книги; строки хранятся прямо в ячейках (inlineStr), без таблицы общих строк,
+// поэтому лист записывается потоково и память не растет с числом строк
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="data" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+
+	// Стиль 1 — встроенный формат даты и времени 22 (m/d/yy h:mm)
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="22" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+		`</styleSheet>`
+
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+// xlsxWriter записывает выгрузку в книгу Excel с одним листом
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	columns []Column
+	refs    []string // Буквенные обозначения колонок
+	row     int
+}
+
+func newXLSXWriter(w io.Writer, columns []Column) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		writer, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(writer, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// Лист создается последним: zip пишет содержимое потоково, пока не создана следующая часть
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	x := &xlsxWriter{
+		archive: archive,
+		sheet:   bufio.NewWriter(sheet),
+		columns: columns,
+		refs:    make([]string, len(columns)),
+	}
+	for i := range columns {
+		x.refs[i] = xlsxColumnName(i)
+	}
+
+	if _, err := x.sheet.WriteString(xlsxSheetHeader); err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, len(columns))
+	headerColumns := make([]Column, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+		headerColumns[i] = Column{Name: column.Name, Type: String}
+	}
+	if err := x.writeRow(headerColumns, header); err != nil {
+		return nil, err
+	}
+
+	return x, nil
+}
+
+func (x *xlsxWriter) WriteRow(values []interface{}) error {
+	if err := checkRow(x.columns, values); err != nil {
+		return err
+	}
+	return x.writeRow(x.columns, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := x.sheet.WriteString(xlsxSheetFooter); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.archive.Close()
+}
+
+// writeRow записывает строку листа; пустые значения пропускаются
+// Строка собирается целиком до записи в лист, поэтому ошибка в значении не оставляет в листе неполную строку
+func (x *xlsxWriter) writeRow(columns []Column, values []interface{}) error {
+	if x.row >= xlsxMaxRows {
+		return fmt.Errorf("xlsx sheet is limited to %d rows", xlsxMaxRows)
+	}
+
+	normalized := make([]interface{}, len(columns))
+	present := make([]bool, len(columns))
+	for i, column := range columns {
+		value, ok, err := normalizeValue(column, values[i])
+		if err != nil {
+			return err
+		}
+		normalized[i], present[i] = value, ok
+	}
+
+	rowNumber := strconv.Itoa(x.row + 1)
+	var row strings.Builder
+	row.WriteString(`<row r="` + rowNumber + `">`)
+	for i := range columns {
+		if !present[i] {
+			continue
+		}
+
+		ref := x.refs[i] + rowNumber
+		switch v := normalized[i].(type) {
+		case string:
+			row.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(&row, []byte(v)); err != nil {
+				return err
+			}
+			row.WriteString(`</t></is></c>`)
+		case bool:
+			flag := "0"
+			if v {
+				flag = "1"
+			}
+			row.WriteString(`<c r="` + ref + `" t="b"><v>` + flag + `</v></c>`)
+		case time.Time:
+			serial := v.Sub(xlsxEpoch).Hours() / 24
+			row.WriteString(`<c r="` + ref + `" s="` + strconv.Itoa(xlsxDateStyle) + `"><v>` +
+				strconv.FormatFloat(serial, 'f', -1, 64) + `</v></c>`)
+		default:
+			row.WriteString(`<c r="` + ref + `"><v>` + formatText(v) + `</v></c>`)
+		}
+	}
+	row.WriteString(`</row>`)
+
+	if _, err := x.sheet.WriteString(row.String()); err != nil {
+		return err
+	}
+	x.row++
+	return nil
+}
+
+// xlsxColumnName возвращает буквенное обозначение колонки: 0 — A, 25 — Z, 26 — AA
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Mean вычисляет среднее арифметическое массива чисел
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+// Variance вычисляет несмещенную выборочную дисперсию
+func Variance(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	mean := Mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+
+	return sum / float64(len(values)-1)
+}
+
+// StdDev вычисляет выборочное стандартное отклонение
+func StdDev(values []float64) float64 {
+	return math.Sqrt(Variance(values))
+}
+
+// Quantile вычисляет квантиль уровня q (от 0 до 1) с линейной интерполяцией
+// Исходный массив не изменяется
+func Quantile(values []float64, q float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	return QuantileSorted(sorted, q)
+}
+
+// QuantileSorted вычисляет квантиль уровня q для уже отсортированного массива
+func QuantileSorted(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return sorted[0]
+	}
+	if q >= 1 {
+		return sorted[len(sorted)-1]
+	}
+
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return sorted[lower]
+	}
+
+	frac := pos - float64(lower)
+	return sorted[lower]*(1-frac) + sorted[upper]*frac
+}
@@ -0,0 +1,62 @@
+// pkg/stats/descriptive_test.go
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	"analitics-service/pkg/stats"
+)
+
+func TestDescriptive(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		mean     float64
+		variance float64
+	}{
+		{name: "empty", values: nil, mean: 0, variance: 0},
+		{name: "single", values: []float64{5}, mean: 5, variance: 0},
+		{name: "sequence", values: []float64{1, 2, 3, 4}, mean: 2.5, variance: 5.0 / 3},
+		{name: "constant", values: []float64{7, 7, 7}, mean: 7, variance: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stats.Mean(tt.values); math.Abs(got-tt.mean) > 1e-12 {
+				t.Errorf("Mean() = %v, want %v", got, tt.mean)
+			}
+			if got := stats.Variance(tt.values); math.Abs(got-tt.variance) > 1e-12 {
+				t.Errorf("Variance() = %v, want %v", got, tt.variance)
+			}
+			if got := stats.StdDev(tt.values); math.Abs(got-math.Sqrt(tt.variance)) > 1e-12 {
+				t.Errorf("StdDev() = %v, want %v", got, math.Sqrt(tt.variance))
+			}
+		})
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	values := []float64{4, 1, 3, 2}
+
+	tests := []struct {
+		q    float64
+		want float64
+	}{
+		{q: 0, want: 1},
+		{q: 0.25, want: 1.75},
+		{q: 0.5, want: 2.5},
+		{q: 1, want: 4},
+		{q: 1.5, want: 4},
+	}
+
+	for _, tt := range tests {
+		if got := stats.Quantile(values, tt.q); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("Quantile(%v) = %v, want %v", tt.q, got, tt.want)
+		}
+	}
+
+	if values[0] != 4 {
+		t.Errorf("Quantile() sorted the input in place: %v", values)
+	}
+}
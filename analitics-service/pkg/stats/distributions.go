@@ -0,0 +1,209 @@
+package stats
+
+import (
+	"math"
+)
+
+// NormalCDF возвращает значение функции распределения стандартного нормального закона
+func NormalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// NormalQuantile возвращает квантиль стандартного нормального распределения для вероятности p
+func NormalQuantile(p float64) float64 {
+	if p <= 0 {
+		return math.Inf(-1)
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+	return -math.Sqrt2 * math.Erfcinv(2*p)
+}
+
+// StudentTCDF возвращает значение функции распределения Стьюдента с df степенями свободы
+func StudentTCDF(t, df float64) float64 {
+	if df <= 0 || math.IsNaN(t) {
+		return math.NaN()
+	}
+	if math.IsInf(t, 1) {
+		return 1
+	}
+	if math.IsInf(t, -1) {
+		return 0
+	}
+
+	x := df / (df + t*t)
+	tail := 0.5 * RegularizedIncompleteBeta(x, df/2, 0.5)
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// StudentTQuantile возвращает квантиль распределения Стьюдента с df степенями свободы
+// Квантиль находится бисекцией по StudentTCDF
+func StudentTQuantile(p, df float64) float64 {
+	if p <= 0 {
+		return math.Inf(-1)
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+
+	// Расширяем интервал поиска, пока он не накроет искомую вероятность
+	lo, hi := -10.0, 10.0
+	for StudentTCDF(lo, df) > p {
+		lo *= 2
+	}
+	for StudentTCDF(hi, df) < p {
+		hi *= 2
+	}
+
+	for i := 0; i < 200; i++ {
+		mid := (lo + hi) / 2
+		if StudentTCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+		if hi-lo < 1e-10 {
+			break
+		}
+	}
+
+	return (lo + hi) / 2
+}
+
+// ChiSquareSurvival возвращает P(X > x) для распределения хи-квадрат с df степенями свободы
+func ChiSquareSurvival(x, df float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return 1 - RegularizedLowerGamma(df/2, x/2)
+}
+
+// RegularizedIncompleteBeta вычисляет регуляризованную неполную бета-функцию I_x(a, b)
+// Используется разложение в цепную дробь (алгоритм Ленца)
+func RegularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lbeta := lgamma(a+b) - lgamma(a) - lgamma(b)
+	front := math.Exp(math.Log(x)*a + math.Log(1-x)*b + lbeta)
+
+	// Для сходимости цепной дроби используем симметрию I_x(a, b) = 1 - I_{1-x}(b, a)
+	if x > (a+1)/(a+b+2) {
+		return 1 - front*betaContinuedFraction(1-x, b, a)/b
+	}
+	return front * betaContinuedFraction(x, a, b) / a
+}
+
+// RegularizedLowerGamma вычисляет регуляризованную нижнюю неполную гамма-функцию P(a, x)
+func RegularizedLowerGamma(a, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	// При x < a+1 быстрее сходится ряд, иначе — цепная дробь для верхней функции
+	if x < a+1 {
+		sum := 1 / a
+		term := sum
+		for n := 1; n < 500; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return sum * math.Exp(-x+a*math.Log(x)-lgamma(a))
+	}
+
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 500; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return 1 - math.Exp(-x+a*math.Log(x)-lgamma(a))*h
+}
+
+// betaContinuedFraction вычисляет цепную дробь для неполной бета-функции
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 500
+		epsilon       = 1e-15
+		tiny          = 1e-300
+	)
+
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+
+		// Четный шаг
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		// Нечетный шаг
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+
+	return h
+}
+
+// lgamma возвращает натуральный логарифм гамма-функции
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
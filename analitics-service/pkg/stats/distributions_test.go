@@ -0,0 +1,130 @@
+// pkg/stats/distributions_test.go
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	"analitics-service/pkg/stats"
+)
+
+// Допустимое абсолютное расхождение со справочными значениями таблиц распределений
+const testDistributionTolerance = 1e-5
+
+func TestNormalCDF(t *testing.T) {
+	tests := []struct {
+		x    float64
+		want float64
+	}{
+		{x: 0, want: 0.5},
+		{x: 1.959964, want: 0.975},
+		{x: -1, want: 0.158655},
+		{x: 3, want: 0.998650},
+	}
+
+	for _, tt := range tests {
+		if got := stats.NormalCDF(tt.x); math.Abs(got-tt.want) > testDistributionTolerance {
+			t.Errorf("NormalCDF(%v) = %.6f, want %.6f", tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestNormalQuantile(t *testing.T) {
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0.5, want: 0},
+		{p: 0.975, want: 1.959964},
+		{p: 0.8, want: 0.841621},
+		{p: 0.05, want: -1.644854},
+		{p: 0, want: math.Inf(-1)},
+		{p: 1, want: math.Inf(1)},
+	}
+
+	for _, tt := range tests {
+		got := stats.NormalQuantile(tt.p)
+		if math.IsInf(tt.want, 0) {
+			if got != tt.want {
+				t.Errorf("NormalQuantile(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+			continue
+		}
+		if math.Abs(got-tt.want) > testDistributionTolerance {
+			t.Errorf("NormalQuantile(%v) = %.6f, want %.6f", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestStudentT(t *testing.T) {
+	tests := []struct {
+		name string
+		t    float64
+		df   float64
+		cdf  float64
+	}{
+		{name: "cauchy", t: 1, df: 1, cdf: 0.75},
+		{name: "symmetry", t: 0, df: 7, cdf: 0.5},
+		{name: "df10", t: 2.228139, df: 10, cdf: 0.975},
+		{name: "df30", t: 2.042272, df: 30, cdf: 0.975},
+		{name: "left tail", t: -1.812461, df: 10, cdf: 0.05},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stats.StudentTCDF(tt.t, tt.df); math.Abs(got-tt.cdf) > testDistributionTolerance {
+				t.Errorf("StudentTCDF(%v, %v) = %.6f, want %.6f", tt.t, tt.df, got, tt.cdf)
+			}
+			if got := stats.StudentTQuantile(tt.cdf, tt.df); math.Abs(got-tt.t) > testDistributionTolerance {
+				t.Errorf("StudentTQuantile(%v, %v) = %.6f, want %.6f", tt.cdf, tt.df, got, tt.t)
+			}
+		})
+	}
+}
+
+func TestStudentTCDFApproachesNormal(t *testing.T) {
+	for _, x := range []float64{-2, -0.5, 1, 1.96} {
+		if got, want := stats.StudentTCDF(x, 1e6), stats.NormalCDF(x); math.Abs(got-want) > testDistributionTolerance {
+			t.Errorf("StudentTCDF(%v, 1e6) = %.6f, want %.6f", x, got, want)
+		}
+	}
+}
+
+func TestChiSquareSurvival(t *testing.T) {
+	tests := []struct {
+		x    float64
+		df   float64
+		want float64
+	}{
+		{x: 3.841459, df: 1, want: 0.05},
+		{x: 5.991465, df: 2, want: 0.05},
+		{x: 6.634897, df: 1, want: 0.01},
+		{x: 0, df: 3, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := stats.ChiSquareSurvival(tt.x, tt.df); math.Abs(got-tt.want) > testDistributionTolerance {
+			t.Errorf("ChiSquareSurvival(%v, %v) = %.6f, want %.6f", tt.x, tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestRegularizedIncompleteBeta(t *testing.T) {
+	tests := []struct {
+		x, a, b float64
+		want    float64
+	}{
+		{x: 0.5, a: 2, b: 2, want: 0.5},
+		// Для целых a и b функция равна биномиальной вероятности P(Bin(a+b-1, x) >= a)
+		{x: 0.3, a: 2, b: 3, want: 0.3483},
+		{x: 0.9, a: 1, b: 1, want: 0.9},
+		{x: 0, a: 3, b: 4, want: 0},
+		{x: 1, a: 3, b: 4, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := stats.RegularizedIncompleteBeta(tt.x, tt.a, tt.b); math.Abs(got-tt.want) > testDistributionTolerance {
+			t.Errorf("RegularizedIncompleteBeta(%v, %v, %v) = %.6f, want %.6f", tt.x, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
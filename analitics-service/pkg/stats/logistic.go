@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrEmptyDataset возвращается при попытке обучить модель на пустой выборке
+var ErrEmptyDataset = errors.New("empty dataset")
+
+// LogisticOptions содержит параметры обучения логистической регрессии
+type LogisticOptions struct {
+	Iterations   int     // Количество итераций градиентного спуска
+	LearningRate float64 // Шаг градиентного спуска
+	L2           float64 // Коэффициент L2-регуляризации (свободный член не регуляризуется)
+}
+
+// LogisticModel представляет обученную логистическую регрессию
+// Признаки стандартизуются по средним и масштабам обучающей выборки
+type LogisticModel struct {
+	Weights   []float64 `json:"weights"`
+	Intercept float64   `json:"intercept"`
+	Means     []float64 `json:"means"`
+	Scales    []float64 `json:"scales"`
+}
+
+// FitLogistic обучает логистическую регрессию пакетным градиентным спуском
+// sampleWeights могут быть nil, тогда все наблюдения имеют единичный вес
+func FitLogistic(x [][]float64, y []float64, sampleWeights []float64, opts LogisticOptions) (*LogisticModel, error) {
+	if len(x) == 0 || len(x) != len(y) {
+		return nil, ErrEmptyDataset
+	}
+	if sampleWeights != nil && len(sampleWeights) != len(y) {
+		return nil, errors.New("sample weights length does not match observations")
+	}
+
+	features := len(x[0])
+	model := &LogisticModel{
+		Weights: make([]float64, features),
+		Means:   make([]float64, features),
+		Scales:  make([]float64, features),
+	}
+
+	// Стандартизация признаков
+	for j := 0; j < features; j++ {
+		column := make([]float64, len(x))
+		for i := range x {
+			column[i] = x[i][j]
+		}
+		model.Means[j] = Mean(column)
+		model.Scales[j] = StdDev(column)
+		if model.Scales[j] == 0 {
+			model.Scales[j] = 1
+		}
+	}
+
+	standardized := make([][]float64, len(x))
+	for i := range x {
+		standardized[i] = model.standardize(x[i])
+	}
+
+	totalWeight := 0.0
+	for i := range y {
+		totalWeight += weightAt(sampleWeights, i)
+	}
+
+	gradient := make([]float64, features)
+	for iter := 0; iter < opts.Iterations; iter++ {
+		for j := range gradient {
+			gradient[j] = 0
+		}
+		gradIntercept := 0.0
+
+		for i, row := range standardized {
+			w := weightAt(sampleWeights, i)
+			residual := w * (model.linear(row) - y[i])
+			for j, v := range row {
+				gradient[j] += residual * v
+			}
+			gradIntercept += residual
+		}
+
+		for j := range model.Weights {
+			model.Weights[j] -= opts.LearningRate * (gradient[j]/totalWeight + opts.L2*model.Weights[j])
+		}
+		model.Intercept -= opts.LearningRate * gradIntercept / totalWeight
+	}
+
+	return model, nil
+}
+
+// Predict возвращает вероятность положительного исхода для вектора признаков
+func (m *LogisticModel) Predict(features []float64) float64 {
+	return m.linear(m.standardize(features))
+}
+
+// linear вычисляет сигмоиду линейной комбинации стандартизованных признаков
+func (m *LogisticModel) linear(standardized []float64) float64 {
+	z := m.Intercept
+	for j, v := range standardized {
+		z += m.Weights[j] * v
+	}
+	return 1 / (1 + math.Exp(-z))
+}
+
+// standardize приводит признаки к нулевому среднему и единичному масштабу
+func (m *LogisticModel) standardize(features []float64) []float64 {
+	result := make([]float64, len(features))
+	for j, v := range features {
+		result[j] = (v - m.Means[j]) / m.Scales[j]
+	}
+	return result
+}
+
+// weightAt возвращает вес наблюдения или 1, если веса не заданы
+func weightAt(weights []float64, i int) float64 {
+	if weights == nil {
+		return 1
+	}
+	return weights[i]
+}
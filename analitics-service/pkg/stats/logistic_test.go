@@ -0,0 +1,103 @@
+// pkg/stats/logistic_test.go
+package stats_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"analitics-service/pkg/stats"
+)
+
+// Параметры обучения, при которых градиентный спуск сходится на небольших выборках тестов
+var testLogisticOptions = stats.LogisticOptions{Iterations: 3000, LearningRate: 0.5}
+
+func TestFitLogisticSeparatesClasses(t *testing.T) {
+	// Покупка вероятна при большом первом признаке, второй признак — шум
+	x := [][]float64{
+		{1, 5}, {2, 3}, {3, 4}, {4, 5}, {5, 3},
+		{10, 4}, {11, 5}, {12, 3}, {13, 4}, {14, 5},
+	}
+	y := []float64{0, 0, 0, 0, 0, 1, 1, 1, 1, 1}
+
+	model, err := stats.FitLogistic(x, y, nil, testLogisticOptions)
+	if err != nil {
+		t.Fatalf("FitLogistic() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		features []float64
+		wantLo   float64
+		wantHi   float64
+	}{
+		{name: "low class", features: []float64{2, 4}, wantLo: 0, wantHi: 0.1},
+		{name: "high class", features: []float64{13, 4}, wantLo: 0.9, wantHi: 1},
+		{name: "boundary", features: []float64{7.5, 4}, wantLo: 0.2, wantHi: 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p := model.Predict(tt.features); p < tt.wantLo || p > tt.wantHi {
+				t.Errorf("Predict(%v) = %.4f, want between %.2f and %.2f", tt.features, p, tt.wantLo, tt.wantHi)
+			}
+		})
+	}
+
+	if model.Weights[0] <= 0 {
+		t.Errorf("weight of informative feature = %.4f, want positive", model.Weights[0])
+	}
+}
+
+func TestFitLogisticSampleWeights(t *testing.T) {
+	// При постоянном признаке модель сходится к взвешенной доле положительных исходов
+	x := [][]float64{{1}, {1}, {1}, {1}}
+	y := []float64{1, 1, 0, 0}
+
+	tests := []struct {
+		name    string
+		weights []float64
+		want    float64
+	}{
+		{name: "unweighted", want: 0.5},
+		{name: "positive outcomes weighted", weights: []float64{3, 3, 1, 1}, want: 0.75},
+		{name: "negative outcomes weighted", weights: []float64{1, 1, 4, 4}, want: 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := stats.FitLogistic(x, y, tt.weights, testLogisticOptions)
+			if err != nil {
+				t.Fatalf("FitLogistic() error = %v", err)
+			}
+			if p := model.Predict([]float64{1}); math.Abs(p-tt.want) > 1e-3 {
+				t.Errorf("Predict() = %.4f, want %.4f", p, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitLogisticInvalidInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		x         [][]float64
+		y         []float64
+		weights   []float64
+		wantEmpty bool
+	}{
+		{name: "no observations", wantEmpty: true},
+		{name: "length mismatch", x: [][]float64{{1}, {2}}, y: []float64{1}, wantEmpty: true},
+		{name: "weights mismatch", x: [][]float64{{1}, {2}}, y: []float64{0, 1}, weights: []float64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := stats.FitLogistic(tt.x, tt.y, tt.weights, testLogisticOptions)
+			if err == nil {
+				t.Fatalf("FitLogistic() error = nil, want error")
+			}
+			if errors.Is(err, stats.ErrEmptyDataset) != tt.wantEmpty {
+				t.Errorf("FitLogistic() error = %v, want ErrEmptyDataset: %v", err, tt.wantEmpty)
+			}
+		})
+	}
+}
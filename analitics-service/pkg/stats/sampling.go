@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+)
+
+// SampleGamma генерирует случайную величину из гамма-распределения с параметром формы shape и масштабом 1
+// Используется метод Марсальи–Цанга
+func SampleGamma(rng *rand.Rand, shape float64) float64 {
+	if shape <= 0 {
+		return 0
+	}
+
+	// Для shape < 1 используем преобразование Gamma(a) = Gamma(a+1) * U^(1/a)
+	if shape < 1 {
+		return SampleGamma(rng, shape+1) * math.Pow(rng.Float64(), 1/shape)
+	}
+
+	d := shape - 1.0/3.0
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if u < 1-0.0331*x*x*x*x {
+			return d * v
+		}
+		if math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// SampleBeta генерирует случайную величину из бета-распределения Beta(a, b)
+func SampleBeta(rng *rand.Rand, a, b float64) float64 {
+	x := SampleGamma(rng, a)
+	y := SampleGamma(rng, b)
+	if x+y == 0 {
+		return 0
+	}
+	return x / (x + y)
+}
+
+// DirichletWeights генерирует веса из симметричного распределения Дирихле Dir(1, ..., 1)
+// Используется в байесовском бутстрепе
+func DirichletWeights(rng *rand.Rand, n int) []float64 {
+	weights := make([]float64, n)
+	sum := 0.0
+	for i := range weights {
+		weights[i] = rng.ExpFloat64()
+		sum += weights[i]
+	}
+	for i := range weights {
+		weights[i] /= sum
+	}
+	return weights
+}
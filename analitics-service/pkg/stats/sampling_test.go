@@ -0,0 +1,84 @@
+// pkg/stats/sampling_test.go
+package stats_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"analitics-service/pkg/stats"
+)
+
+// Количество выборок, при котором выборочные моменты отличаются от теоретических меньше чем на 2%
+const testSampleDraws = 200000
+
+func TestSampleGammaMoments(t *testing.T) {
+	// Для Gamma(k, 1) среднее и дисперсия равны k; shape < 1 проверяет ветку с преобразованием
+	for _, shape := range []float64{0.5, 1, 2.5, 30} {
+		rng := rand.New(rand.NewSource(1))
+		draws := make([]float64, testSampleDraws)
+		for i := range draws {
+			draws[i] = stats.SampleGamma(rng, shape)
+		}
+
+		if got := stats.Mean(draws); math.Abs(got-shape) > 0.02*shape {
+			t.Errorf("SampleGamma(%v) mean = %.4f, want %.4f", shape, got, shape)
+		}
+		if got := stats.Variance(draws); math.Abs(got-shape) > 0.03*shape {
+			t.Errorf("SampleGamma(%v) variance = %.4f, want %.4f", shape, got, shape)
+		}
+	}
+}
+
+func TestSampleBetaMoments(t *testing.T) {
+	tests := []struct {
+		a, b float64
+	}{
+		{a: 1, b: 1},
+		{a: 0.5, b: 0.5},
+		{a: 101, b: 901},
+		{a: 3, b: 7},
+	}
+
+	for _, tt := range tests {
+		rng := rand.New(rand.NewSource(1))
+		draws := make([]float64, testSampleDraws)
+		for i := range draws {
+			draws[i] = stats.SampleBeta(rng, tt.a, tt.b)
+			if draws[i] < 0 || draws[i] > 1 {
+				t.Fatalf("SampleBeta(%v, %v) = %v, want value in [0, 1]", tt.a, tt.b, draws[i])
+			}
+		}
+
+		sum := tt.a + tt.b
+		mean := tt.a / sum
+		variance := tt.a * tt.b / (sum * sum * (sum + 1))
+		if got := stats.Mean(draws); math.Abs(got-mean) > 0.02*mean {
+			t.Errorf("SampleBeta(%v, %v) mean = %.5f, want %.5f", tt.a, tt.b, got, mean)
+		}
+		if got := stats.Variance(draws); math.Abs(got-variance) > 0.03*variance {
+			t.Errorf("SampleBeta(%v, %v) variance = %.6f, want %.6f", tt.a, tt.b, got, variance)
+		}
+	}
+}
+
+func TestDirichletWeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 50} {
+		weights := stats.DirichletWeights(rng, n)
+		if len(weights) != n {
+			t.Fatalf("DirichletWeights(%d) returned %d weights", n, len(weights))
+		}
+
+		sum := 0.0
+		for _, w := range weights {
+			if w < 0 {
+				t.Errorf("DirichletWeights(%d) weight = %v, want non-negative", n, w)
+			}
+			sum += w
+		}
+		if math.Abs(sum-1) > 1e-12 {
+			t.Errorf("DirichletWeights(%d) sum = %v, want 1", n, sum)
+		}
+	}
+}